@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	apiclient "github.com/alexandru-savinov/BalancedNewsGo/internal/api/wrapper"
+)
+
+// Thresholds are the limits checked after a report run. A value is breached when the
+// measured rate is above it; a negative value turns the check off.
+type Thresholds struct {
+	// MaxUncertaintyRatio is the highest allowed daily share of low-confidence scores
+	MaxUncertaintyRatio float64 `json:"max_uncertainty_ratio"`
+	// UncertaintyDays is how many of the most recent days the uncertainty check covers,
+	// so one bad day stops alerting once it leaves the window. Less than 1 means 1.
+	UncertaintyDays int `json:"uncertainty_days"`
+	// MaxDisagreementRate is the highest allowed number of articles with conflicting
+	// feedback per feedback item received
+	MaxDisagreementRate float64 `json:"max_disagreement_rate"`
+	// MaxErrorRate is the highest allowed share of metrics endpoints that failed to load
+	MaxErrorRate float64 `json:"max_error_rate"`
+}
+
+// defaultThresholds keeps the uncertainty limit the report always alerted on and fails
+// on any endpoint error
+var defaultThresholds = Thresholds{
+	MaxUncertaintyRatio: 0.3,
+	UncertaintyDays:     1,
+	MaxDisagreementRate: 0.2,
+	MaxErrorRate:        0,
+}
+
+// loadThresholds reads thresholds from a JSON file. Fields missing from the file keep
+// the defaults.
+func loadThresholds(path string) (Thresholds, error) {
+	thresholds := defaultThresholds
+	data, err := os.ReadFile(path) // #nosec G304 - path is from command line flag, controlled input
+	if err != nil {
+		return thresholds, fmt.Errorf("failed to read thresholds file: %w", err)
+	}
+	if err := json.Unmarshal(data, &thresholds); err != nil {
+		return thresholds, fmt.Errorf("failed to parse thresholds file: %w", err)
+	}
+	return thresholds, nil
+}
+
+// Alert is one breached threshold
+type Alert struct {
+	Check string `json:"check"`
+	// Subject is what the value was measured over, such as a day; empty for the whole run
+	Subject   string  `json:"subject,omitempty"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Message   string  `json:"message"`
+}
+
+// Alert check names
+const (
+	checkUncertainty  = "uncertainty_ratio"
+	checkDisagreement = "disagreement_rate"
+	checkErrorRate    = "error_rate"
+)
+
+// snapshot holds the metrics fetched during one report run
+type snapshot struct {
+	uncertainty   []apiclient.UncertaintyRate
+	feedback      []apiclient.FeedbackSummary
+	disagreements []apiclient.Disagreement
+	// feedbackLoaded and disagreementsLoaded are false when the endpoint failed, so the
+	// disagreement rate is not computed from missing data
+	feedbackLoaded      bool
+	disagreementsLoaded bool
+	endpoints           int
+	failed              []string
+}
+
+// evaluate returns the alerts for every threshold the snapshot breaches
+func evaluate(s snapshot, t Thresholds) []Alert {
+	var alerts []Alert
+	if t.MaxUncertaintyRatio >= 0 {
+		for _, rate := range recentDays(s.uncertainty, t.UncertaintyDays) {
+			if rate.LowConfidenceRate > t.MaxUncertaintyRatio {
+				alerts = append(alerts, Alert{
+					Check:     checkUncertainty,
+					Subject:   rate.Day,
+					Value:     rate.LowConfidenceRate,
+					Threshold: t.MaxUncertaintyRatio,
+					Message: fmt.Sprintf("High uncertainty (%.2f) on %s, above %.2f",
+						rate.LowConfidenceRate, rate.Day, t.MaxUncertaintyRatio),
+				})
+			}
+		}
+	}
+
+	if t.MaxDisagreementRate >= 0 && s.feedbackLoaded && s.disagreementsLoaded {
+		total := 0
+		for _, f := range s.feedback {
+			total += f.FeedbackCount
+		}
+		if total > 0 {
+			rate := float64(len(s.disagreements)) / float64(total)
+			if rate > t.MaxDisagreementRate {
+				alerts = append(alerts, Alert{
+					Check:     checkDisagreement,
+					Value:     rate,
+					Threshold: t.MaxDisagreementRate,
+					Message: fmt.Sprintf("High disagreement rate (%.2f): %d disputed articles for %d feedback items, above %.2f",
+						rate, len(s.disagreements), total, t.MaxDisagreementRate),
+				})
+			}
+		}
+	}
+
+	if t.MaxErrorRate >= 0 && s.endpoints > 0 {
+		rate := float64(len(s.failed)) / float64(s.endpoints)
+		if rate > t.MaxErrorRate {
+			alerts = append(alerts, Alert{
+				Check:     checkErrorRate,
+				Value:     rate,
+				Threshold: t.MaxErrorRate,
+				Message: fmt.Sprintf("%d of %d metrics endpoints failed (%v), above an error rate of %.2f",
+					len(s.failed), s.endpoints, s.failed, t.MaxErrorRate),
+			})
+		}
+	}
+	return alerts
+}
+
+// recentDays returns the rates of the latest days, newest first
+func recentDays(rates []apiclient.UncertaintyRate, days int) []apiclient.UncertaintyRate {
+	if days < 1 {
+		days = 1
+	}
+	sorted := append([]apiclient.UncertaintyRate(nil), rates...)
+	// Days are YYYY-MM-DD, so they sort by date as strings
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Day > sorted[j].Day })
+	if len(sorted) > days {
+		sorted = sorted[:days]
+	}
+	return sorted
+}
+
+// alertReport is the structured alert output written with -alerts-file
+type alertReport struct {
+	GeneratedAt string     `json:"generated_at"`
+	Thresholds  Thresholds `json:"thresholds"`
+	Alerts      []Alert    `json:"alerts"`
+}
+
+// writeAlerts writes the alerts as JSON to path
+func writeAlerts(path string, report alertReport) error {
+	if report.Alerts == nil {
+		report.Alerts = []Alert{}
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	apiclient "github.com/alexandru-savinov/BalancedNewsGo/internal/api/wrapper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	snap := snapshot{
+		uncertainty: []apiclient.UncertaintyRate{
+			{Day: "2026-10-01", LowConfidenceRate: 0.1},
+			{Day: "2026-10-02", LowConfidenceRate: 0.45},
+		},
+		feedback:            []apiclient.FeedbackSummary{{Day: "2026-10-02", Category: "agree", FeedbackCount: 10}},
+		disagreements:       make([]apiclient.Disagreement, 3),
+		feedbackLoaded:      true,
+		disagreementsLoaded: true,
+		endpoints:           5,
+		failed:              []string{"outliers"},
+	}
+
+	alerts := evaluate(snap, defaultThresholds)
+	require.Len(t, alerts, 3)
+	assert.Equal(t, checkUncertainty, alerts[0].Check)
+	assert.Equal(t, "2026-10-02", alerts[0].Subject)
+	assert.Equal(t, checkDisagreement, alerts[1].Check)
+	assert.InDelta(t, 0.3, alerts[1].Value, 1e-9)
+	assert.Equal(t, checkErrorRate, alerts[2].Check)
+	assert.InDelta(t, 0.2, alerts[2].Value, 1e-9)
+
+	assert.Empty(t, evaluate(snap, Thresholds{MaxUncertaintyRatio: 0.5, MaxDisagreementRate: -1, MaxErrorRate: 0.5}),
+		"raised or disabled thresholds are not breached")
+
+	// Only the latest days are checked, so an old bad day stops alerting
+	snap.uncertainty = append(snap.uncertainty, apiclient.UncertaintyRate{Day: "2026-10-03", LowConfidenceRate: 0.1})
+	for _, a := range evaluate(snap, defaultThresholds) {
+		assert.NotEqual(t, checkUncertainty, a.Check)
+	}
+	window := defaultThresholds
+	window.UncertaintyDays = 2
+	alerts = evaluate(snap, window)
+	require.NotEmpty(t, alerts)
+	assert.Equal(t, "2026-10-02", alerts[0].Subject)
+
+	snap.feedbackLoaded = false
+	for _, a := range evaluate(snap, defaultThresholds) {
+		assert.NotEqual(t, checkDisagreement, a.Check, "the disagreement rate needs the feedback counts")
+	}
+}
+
+func TestLoadThresholds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "thresholds.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"max_uncertainty_ratio": 0.5, "max_error_rate": -1}`), 0o600))
+
+	thresholds, err := loadThresholds(path)
+	require.NoError(t, err)
+	assert.Equal(t, Thresholds{MaxUncertaintyRatio: 0.5, UncertaintyDays: 1, MaxDisagreementRate: defaultThresholds.MaxDisagreementRate, MaxErrorRate: -1}, thresholds)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o600))
+	_, err = loadThresholds(path)
+	assert.Error(t, err)
+}
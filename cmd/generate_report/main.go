@@ -1,37 +1,93 @@
 package main
 
 import (
+	"context"
 	"encoding/csv"
-	"encoding/json"
+	"flag"
 	"fmt"
-	"net/http"
 	"os"
+	"strconv"
 	"time"
+
+	apiclient "github.com/alexandru-savinov/BalancedNewsGo/internal/api/wrapper"
 )
 
-var baseURL = "http://localhost:8080"
+// Exit codes: exitAlert when a threshold is breached, exitUsage for bad flags or config
+const (
+	exitAlert = 1
+	exitUsage = 2
+)
 
-func fetchAndSave(endpoint, filename string) error {
-	resp, err := http.Get(baseURL + endpoint)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Failed to close response body: %v\n", err)
-		}
-	}()
+// report is one metrics endpoint written to a CSV file
+type report struct {
+	name     string
+	filename string
+	// fetch returns the CSV header and rows
+	fetch func(ctx context.Context) ([]string, [][]string, error)
+}
 
-	var data []map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return err
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// reports lists the endpoints to fetch. The metrics the alerts need are kept in snap.
+func reports(client *apiclient.APIClient, timestamp string, snap *snapshot) []report {
+	return []report{
+		{"validation metrics", fmt.Sprintf("validation_metrics_%s.csv", timestamp), func(ctx context.Context) ([]string, [][]string, error) {
+			metrics, err := client.GetValidationMetrics(ctx)
+			rows := make([][]string, 0, len(metrics))
+			for _, m := range metrics {
+				rows = append(rows, []string{m.Day, m.Label, strconv.Itoa(m.LabelCount), formatFloat(m.AvgConfidence)})
+			}
+			return []string{"day", "label", "label_count", "avg_confidence"}, rows, err
+		}},
+		{"feedback summary", fmt.Sprintf("feedback_summary_%s.csv", timestamp), func(ctx context.Context) ([]string, [][]string, error) {
+			summaries, err := client.GetFeedbackSummary(ctx)
+			snap.feedback, snap.feedbackLoaded = summaries, err == nil
+			rows := make([][]string, 0, len(summaries))
+			for _, s := range summaries {
+				rows = append(rows, []string{s.Day, s.Category, strconv.Itoa(s.FeedbackCount)})
+			}
+			return []string{"day", "category", "feedback_count"}, rows, err
+		}},
+		{"uncertainty rates", fmt.Sprintf("uncertainty_rates_%s.csv", timestamp), func(ctx context.Context) ([]string, [][]string, error) {
+			rates, err := client.GetUncertaintyRates(ctx)
+			snap.uncertainty = rates
+			rows := make([][]string, 0, len(rates))
+			for _, r := range rates {
+				rows = append(rows, []string{r.Day, formatFloat(r.LowConfidenceRate)})
+			}
+			return []string{"day", "low_confidence_ratio"}, rows, err
+		}},
+		{"disagreements", fmt.Sprintf("disagreements_%s.csv", timestamp), func(ctx context.Context) ([]string, [][]string, error) {
+			disagreements, err := client.GetDisagreements(ctx)
+			snap.disagreements, snap.disagreementsLoaded = disagreements, err == nil
+			rows := make([][]string, 0, len(disagreements))
+			for _, d := range disagreements {
+				rows = append(rows, []string{strconv.FormatInt(d.ArticleID, 10), strconv.Itoa(d.DistinctCategories),
+					d.LastFeedbackTime.Format(time.RFC3339)})
+			}
+			return []string{"article_id", "distinct_categories", "last_feedback_time"}, rows, err
+		}},
+		{"outliers", fmt.Sprintf("outliers_%s.csv", timestamp), func(ctx context.Context) ([]string, [][]string, error) {
+			outliers, err := client.GetOutliers(ctx)
+			rows := make([][]string, 0, len(outliers))
+			for _, o := range outliers {
+				rows = append(rows, []string{strconv.FormatInt(o.ArticleID, 10), formatFloat(o.MaxScore), formatFloat(o.MinScore),
+					formatFloat(o.ScoreRange), strconv.Itoa(o.ScoreCount)})
+			}
+			return []string{"article_id", "max_score", "min_score", "score_range", "score_count"}, rows, err
+		}},
 	}
+}
 
-	if len(data) == 0 {
+// writeCSV writes the header and rows to filename. Nothing is written when there are
+// no rows.
+func writeCSV(filename string, header []string, rows [][]string) error {
+	if len(rows) == 0 {
 		return nil
 	}
-
-	file, err := os.Create(filename) // #nosec G304 - filename is from command line argument, controlled input
+	file, err := os.Create(filename) // #nosec G304 - filename is built from a timestamp, controlled input
 	if err != nil {
 		return err
 	}
@@ -42,79 +98,86 @@ func fetchAndSave(endpoint, filename string) error {
 	}()
 
 	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// Write header
-	var header = make([]string, 0, len(data[0]))
-	for k := range data[0] {
-		header = append(header, k)
-	}
 	if err := writer.Write(header); err != nil {
 		return err
 	}
-
-	// Write rows
-	for _, row := range data {
-		var record = make([]string, 0, len(header))
-		for _, k := range header {
-			val := fmt.Sprintf("%v", row[k])
-			record = append(record, val)
-		}
-		if err := writer.Write(record); err != nil {
-			return err
-		}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
 	}
-
-	return nil
+	return writer.Error()
 }
 
 func main() {
-	timestamp := time.Now().Format("20060102_150405")
-
-	endpoints := map[string]string{
-		"/metrics/validation":    fmt.Sprintf("validation_metrics_%s.csv", timestamp),
-		"/metrics/feedback":      fmt.Sprintf("feedback_summary_%s.csv", timestamp),
-		"/metrics/uncertainty":   fmt.Sprintf("uncertainty_rates_%s.csv", timestamp),
-		"/metrics/disagreements": fmt.Sprintf("disagreements_%s.csv", timestamp),
-		"/metrics/outliers":      fmt.Sprintf("outliers_%s.csv", timestamp),
+	baseURL := flag.String("base-url", "http://localhost:8080", "Base URL of the NewsBalancer server")
+	configPath := flag.String("thresholds", "", "JSON file with alert thresholds; flags given explicitly override it")
+	maxUncertainty := flag.Float64("max-uncertainty-ratio", defaultThresholds.MaxUncertaintyRatio,
+		"Alert when a day's share of low-confidence scores is above this (negative disables)")
+	uncertaintyDays := flag.Int("uncertainty-days", defaultThresholds.UncertaintyDays,
+		"How many of the most recent days the uncertainty check covers")
+	maxDisagreement := flag.Float64("max-disagreement-rate", defaultThresholds.MaxDisagreementRate,
+		"Alert when articles with conflicting feedback per feedback item is above this (negative disables)")
+	maxErrorRate := flag.Float64("max-error-rate", defaultThresholds.MaxErrorRate,
+		"Alert when the share of metrics endpoints that failed is above this (negative disables)")
+	alertsFile := flag.String("alerts-file", "", "Write the alerts as JSON to this file")
+	flag.Parse()
+
+	thresholds := defaultThresholds
+	if *configPath != "" {
+		var err error
+		if thresholds, err = loadThresholds(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
 	}
-
-	for endpoint, filename := range endpoints {
-		fmt.Printf("Fetching %s...\n", endpoint)
-		if err := fetchAndSave(endpoint, filename); err != nil {
-			fmt.Printf("Error fetching %s: %v\n", endpoint, err)
-		} else {
-			fmt.Printf("Saved report to %s\n", filename)
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "max-uncertainty-ratio":
+			thresholds.MaxUncertaintyRatio = *maxUncertainty
+		case "uncertainty-days":
+			thresholds.UncertaintyDays = *uncertaintyDays
+		case "max-disagreement-rate":
+			thresholds.MaxDisagreementRate = *maxDisagreement
+		case "max-error-rate":
+			thresholds.MaxErrorRate = *maxErrorRate
+		}
+	})
+
+	client := apiclient.NewAPIClient(*baseURL)
+	ctx := context.Background()
+	now := time.Now()
+	timestamp := now.Format("20060102_150405")
+
+	var snap snapshot
+	for _, r := range reports(client, timestamp, &snap) {
+		snap.endpoints++
+		fmt.Printf("Fetching %s...\n", r.name)
+		header, rows, err := r.fetch(ctx)
+		if err != nil {
+			snap.failed = append(snap.failed, r.name)
+			fmt.Printf("Error fetching %s: %v\n", r.name, err)
+			continue
+		}
+		if err := writeCSV(r.filename, header, rows); err != nil {
+			fmt.Printf("Error writing %s: %v\n", r.filename, err)
+		} else if len(rows) > 0 {
+			fmt.Printf("Saved report to %s\n", r.filename)
 		}
 	}
 
-	// Basic alerting example: check uncertainty rates
-	resp, err := http.Get(baseURL + "/metrics/uncertainty")
-	if err != nil {
-		fmt.Printf("Error fetching uncertainty rates: %v\n", err)
-		return
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Failed to close response body: %v\n", err)
+	alerts := evaluate(snap, thresholds)
+	if *alertsFile != "" {
+		report := alertReport{GeneratedAt: now.UTC().Format(time.RFC3339), Thresholds: thresholds, Alerts: alerts}
+		if err := writeAlerts(*alertsFile, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing alerts: %v\n", err)
+			os.Exit(exitUsage)
 		}
-	}()
-
-	var rates []map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&rates); err != nil {
-		fmt.Printf("Error decoding uncertainty rates: %v\n", err)
+	}
+	if len(alerts) == 0 {
+		fmt.Println("No thresholds breached")
 		return
 	}
-
-	for _, rate := range rates {
-		day := rate["day"]
-		val, ok := rate["low_confidence_ratio"].(float64)
-		if !ok {
-			fmt.Printf("Warning: Could not parse low_confidence_ratio for day %v\n", day)
-			continue
-		}
-		if val > 0.3 { // example threshold
-			fmt.Printf("ALERT: High uncertainty (%.2f) on %s\n", val, day)
-		}
+	for _, a := range alerts {
+		fmt.Printf("ALERT: %s\n", a.Message)
 	}
+	os.Exit(exitAlert)
 }
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "modernc.org/sqlite"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/importfile"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+)
+
+// requiredColumns are the fields every input row must have
+var requiredColumns = []string{"title", "content", "url", "source", "published"}
+
+// columnAliases maps alternative column names to the required ones
+var columnAliases = map[string]string{
+	"published_at": "published",
+	"pub_date":     "published",
+	"link":         "url",
+}
+
+// publishedLayouts are the accepted formats of the published column, tried in order
+var publishedLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// articleRow is one article read from the input file, with its row number for reporting
+type articleRow struct {
+	row       int
+	title     string
+	content   string
+	url       string
+	source    string
+	published string
+}
+
+// importOptions controls how rows are written
+type importOptions struct {
+	// score collects the inserted article IDs so they can be scored after the import
+	score bool
+	// dryRun validates and counts without writing
+	dryRun bool
+}
+
+// importSummary counts what happened to each row
+type importSummary struct {
+	Inserted   int
+	Duplicates int
+	Skipped    int
+}
+
+func main() {
+	dbPath := flag.String("db", "news.db", "Path to SQLite database")
+	filePath := flag.String("file", "", "Path to the article file (CSV, JSON array or JSONL)")
+	format := flag.String("format", "csv", "File format: csv or json (JSON arrays and JSONL are detected automatically)")
+	score := flag.Bool("score", false, "Score the imported articles with the configured LLM models after the import")
+	dryRun := flag.Bool("dry-run", false, "Validate the file and report counts without writing")
+	flag.Parse()
+
+	if *filePath == "" {
+		log.Printf("ERROR: Please provide --file path to the article file")
+		os.Exit(1)
+	}
+
+	database, err := db.InitDB(*dbPath)
+	if err != nil {
+		log.Printf("ERROR: Failed to open DB: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if closeErr := database.Close(); closeErr != nil {
+			log.Printf("Warning: Failed to close database: %v", closeErr)
+		}
+	}()
+
+	f, err := os.Open(*filePath)
+	if err != nil {
+		log.Printf("ERROR: Failed to open file: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil {
+			log.Printf("Warning: Failed to close file: %v", closeErr)
+		}
+	}()
+
+	importer := newArticleImporter(database, importOptions{score: *score, dryRun: *dryRun})
+
+	var skipped int
+	switch *format {
+	case "csv":
+		skipped, err = readCSV(f, importer.add)
+	case "json", "jsonl":
+		skipped, err = readJSON(f, importer.add)
+	default:
+		log.Printf("ERROR: Unsupported format: %s", *format)
+		os.Exit(1)
+	}
+	summary := importer.summary
+	summary.Skipped += skipped
+	if err != nil {
+		log.Printf("ERROR: Import stopped early: %v", err)
+	}
+
+	prefix := ""
+	if *dryRun {
+		prefix = "Dry run: would have "
+	}
+	fmt.Printf("%sinserted %d, skipped %d duplicate and %d invalid articles from %s\n",
+		prefix, summary.Inserted, summary.Duplicates, summary.Skipped, *filePath)
+	if err != nil {
+		os.Exit(1)
+	}
+	if *dryRun || summary.Inserted == 0 {
+		return
+	}
+	if !*score {
+		fmt.Println("Imported articles are unscored; pass --score, run cmd/score_articles or enable the " +
+			"server's score backfill (SCORE_BACKFILL_ENABLED) to score them")
+		return
+	}
+	scored, failed := scoreArticles(database, importer.inserted)
+	fmt.Printf("Scored %d imported articles, %d failed\n", scored, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// scoreArticles reanalyses the imported articles one by one with the configured LLM
+// models, returning how many were scored and how many failed
+func scoreArticles(database *sqlx.DB, articleIDs []int64) (int, int) {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found or error loading .env file (this is okay if env vars are set elsewhere)")
+	}
+	llmClient, err := llm.NewLLMClient(database)
+	if err != nil {
+		log.Printf("ERROR: Failed to initialize LLM client: %v", err)
+		return 0, len(articleIDs)
+	}
+	scoreManager := llm.NewScoreManager(database, llm.NewCache(), llm.ScoreCalculatorFromEnv(),
+		llm.NewProgressManager(10*time.Minute))
+
+	scored, failed := 0, 0
+	for _, id := range articleIDs {
+		if err := llmClient.ReanalyzeArticle(context.Background(), id, scoreManager); err != nil {
+			log.Printf("Failed to score article %d: %v", id, err)
+			failed++
+			continue
+		}
+		scored++
+	}
+	return scored, failed
+}
+
+// canonicalColumn returns the required column a header names, or "" if it names none
+func canonicalColumn(header string) string {
+	name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+	if alias, ok := columnAliases[name]; ok {
+		return alias
+	}
+	for _, col := range requiredColumns {
+		if name == col {
+			return col
+		}
+	}
+	return ""
+}
+
+// readCSV streams rows from a CSV file with the required columns, in any order,
+// returning how many short rows were skipped
+func readCSV(r io.Reader, add func(articleRow)) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return 0, fmt.Errorf("empty CSV file")
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	idx := make(map[string]int)
+	for i, h := range header {
+		if col := canonicalColumn(h); col != "" {
+			if _, dup := idx[col]; !dup {
+				idx[col] = i
+			}
+		}
+	}
+	var missing []string
+	for _, col := range requiredColumns {
+		if _, ok := idx[col]; !ok {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		return 0, fmt.Errorf("CSV is missing required columns: %s", strings.Join(missing, ", "))
+	}
+
+	skipped := 0
+	for row := 2; ; row++ { // 1-based, after the header
+		rec, err := reader.Read()
+		if err == io.EOF {
+			return skipped, nil
+		}
+		if err != nil {
+			return skipped, err
+		}
+		field := func(col string) (string, bool) {
+			if idx[col] >= len(rec) {
+				return "", false
+			}
+			return rec[idx[col]], true
+		}
+		var values [5]string
+		ok := true
+		for i, col := range requiredColumns {
+			if values[i], ok = field(col); !ok {
+				break
+			}
+		}
+		if !ok {
+			log.Printf("Skipping row %d: missing columns", row)
+			skipped++
+			continue
+		}
+		add(articleRow{row: row, title: values[0], content: values[1], url: values[2],
+			source: values[3], published: values[4]})
+	}
+}
+
+// readJSON streams objects with the required string fields from either a JSON array
+// or JSONL, one object per line, detected from the first character. It returns how
+// many objects were skipped.
+func readJSON(r io.Reader, add func(articleRow)) (int, error) {
+	return importfile.ReadJSON(r, func(n int, item importfile.Item) bool {
+		row, ok := rowFromItem(n, item)
+		if ok {
+			add(row)
+		}
+		return ok
+	})
+}
+
+// rowFromItem reads the required fields of a decoded object, accepting the same
+// aliases as CSV headers
+func rowFromItem(n int, item importfile.Item) (articleRow, bool) {
+	values := make(map[string]string)
+	for key, value := range item {
+		col := canonicalColumn(key)
+		if col == "" {
+			continue
+		}
+		s, ok := value.(string)
+		if !ok {
+			log.Printf("Skipping item %d: %q must be a string", n, key)
+			return articleRow{}, false
+		}
+		if _, dup := values[col]; !dup {
+			values[col] = s
+		}
+	}
+	for _, col := range requiredColumns {
+		if _, ok := values[col]; !ok {
+			log.Printf("Skipping item %d: missing %q", n, col)
+			return articleRow{}, false
+		}
+	}
+	return articleRow{row: n, title: values["title"], content: values["content"], url: values["url"],
+		source: values["source"], published: values["published"]}, true
+}
+
+// parsePublished parses the published column in any of publishedLayouts. Values
+// without a zone are taken as UTC.
+func parsePublished(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range publishedLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid published date %q (want RFC3339 or YYYY-MM-DD)", s)
+}
+
+// articleImporter validates rows as they are read and inserts them one by one,
+// skipping URLs already in the database or earlier in the file. Invalid rows are
+// skipped and reported.
+type articleImporter struct {
+	database *sqlx.DB
+	opts     importOptions
+	summary  importSummary
+	// seen holds the URLs imported so far, so a dry run also catches repeats
+	seen map[string]bool
+	// inserted holds the IDs of the inserted articles; only kept with score
+	inserted []int64
+}
+
+func newArticleImporter(database *sqlx.DB, opts importOptions) *articleImporter {
+	return &articleImporter{database: database, opts: opts, seen: make(map[string]bool)}
+}
+
+// add validates a row and writes it
+func (im *articleImporter) add(r articleRow) {
+	article, err := im.article(r)
+	if err != nil {
+		log.Printf("Skipping row %d: %v", r.row, err)
+		im.summary.Skipped++
+		return
+	}
+
+	if im.seen[article.URL] {
+		im.summary.Duplicates++
+		return
+	}
+	exists, err := db.ArticleExistsByURL(im.database, article.URL)
+	if err != nil {
+		log.Printf("Failed to check row %d for an existing article: %v", r.row, err)
+		im.summary.Skipped++
+		return
+	}
+	if exists {
+		im.seen[article.URL] = true
+		im.summary.Duplicates++
+		return
+	}
+
+	if !im.opts.dryRun {
+		id, err := db.InsertArticle(im.database, article)
+		if err != nil {
+			if errors.Is(err, db.ErrDuplicateURL) {
+				im.seen[article.URL] = true
+				im.summary.Duplicates++
+				return
+			}
+			log.Printf("Failed to insert article from row %d: %v", r.row, err)
+			im.summary.Skipped++
+			return
+		}
+		if im.opts.score {
+			im.inserted = append(im.inserted, id)
+		}
+	}
+	im.seen[article.URL] = true
+	im.summary.Inserted++
+}
+
+// article validates a row and builds the article to insert
+func (im *articleImporter) article(r articleRow) (*db.Article, error) {
+	row := articleRow{
+		title:     strings.TrimSpace(r.title),
+		content:   strings.TrimSpace(r.content),
+		url:       strings.TrimSpace(r.url),
+		source:    strings.TrimSpace(r.source),
+		published: r.published,
+	}
+	var missing []string
+	for _, field := range []struct{ col, value string }{
+		{"title", row.title}, {"content", row.content}, {"url", row.url}, {"source", row.source},
+	} {
+		if field.value == "" {
+			missing = append(missing, field.col)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("empty %s", strings.Join(missing, ", "))
+	}
+	if !strings.HasPrefix(row.url, "http://") && !strings.HasPrefix(row.url, "https://") {
+		return nil, fmt.Errorf("invalid URL %q (must start with http:// or https://)", row.url)
+	}
+	published, err := parsePublished(row.published)
+	if err != nil {
+		return nil, err
+	}
+
+	article := &db.Article{
+		Source:    row.source,
+		PubDate:   published,
+		URL:       row.url,
+		Title:     row.title,
+		Content:   row.content,
+		CreatedAt: time.Now(),
+	}
+	// Score columns stay NULL so the article reads as unscored until it is analysed
+	return article, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+)
+
+func fetchByURL(database *sqlx.DB, url string) (*db.Article, error) {
+	var article db.Article
+	err := database.Get(&article, "SELECT * FROM articles WHERE url = ?", url)
+	return &article, err
+}
+
+func TestImportArticles(t *testing.T) {
+	database, err := db.InitDB(":memory:")
+	require.NoError(t, err)
+	defer func() { _ = database.Close() }()
+	_, err = db.InsertArticle(database, &db.Article{Source: "bbc", URL: "https://example.com/existing",
+		Title: "Existing", Content: "Already stored", PubDate: time.Now()})
+	require.NoError(t, err)
+
+	importer := newArticleImporter(database, importOptions{score: true})
+	skipped, err := readCSV(strings.NewReader("\ufeffTitle,Link,Content,Source,Published_At,Extra\n"+
+		"First,https://example.com/1,Body one,bbc,2024-03-01T10:00:00Z,x\n"+
+		"Second,https://example.com/2,Body two,cnn,2024-03-02,x\n"+
+		"Repeat,https://example.com/1,Body again,bbc,2024-03-03,x\n"+
+		"Stored,https://example.com/existing,Body,bbc,2024-03-04,x\n"+
+		"No content,https://example.com/3,,bbc,2024-03-05,x\n"+
+		"Bad URL,example.com/4,Body,bbc,2024-03-05,x\n"+
+		"Bad date,https://example.com/5,Body,bbc,March 5th,x\n"+
+		"Short row,https://example.com/6\n"), importer.add)
+	require.NoError(t, err)
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, importSummary{Inserted: 2, Duplicates: 2, Skipped: 3}, importer.summary)
+
+	article, err := fetchByURL(database, "https://example.com/2")
+	require.NoError(t, err)
+	assert.Equal(t, "cnn", article.Source)
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), article.PubDate.UTC())
+	assert.Nil(t, article.CompositeScore, "imported articles are left unscored")
+	assert.Len(t, importer.inserted, 2, "with -score the inserted articles are kept for scoring")
+	assert.Contains(t, importer.inserted, article.ID)
+
+	importer = newArticleImporter(database, importOptions{})
+	skipped, err = readJSON(strings.NewReader(`[
+		{"title": "Third", "url": "https://example.com/7", "content": "Body", "source": "bbc", "published": "2024-03-06"},
+		{"title": "Fourth", "url": "https://example.com/8", "content": "Body", "source": "bbc"},
+		{"title": 5, "url": "https://example.com/9", "content": "Body", "source": "bbc", "published": "2024-03-06"}
+	]`), importer.add)
+	require.NoError(t, err)
+	assert.Equal(t, 2, skipped)
+	assert.Equal(t, importSummary{Inserted: 1}, importer.summary)
+	article, err = fetchByURL(database, "https://example.com/7")
+	require.NoError(t, err)
+	assert.Nil(t, article.CompositeScore)
+	assert.Nil(t, article.Confidence)
+	assert.Nil(t, article.ScoreSource, "no placeholder score marks the article as scored")
+	assert.Empty(t, importer.inserted)
+
+	importer = newArticleImporter(database, importOptions{dryRun: true})
+	skipped, err = readJSON(strings.NewReader(
+		`{"title": "Fifth", "url": "https://example.com/10", "content": "Body", "source": "bbc", "pub_date": "2024-03-07"}`+"\n"+
+			`{"title": "Fifth", "url": "https://example.com/10", "content": "Body", "source": "bbc", "pub_date": "2024-03-07"}`+"\n"), importer.add)
+	require.NoError(t, err)
+	assert.Zero(t, skipped)
+	assert.Equal(t, importSummary{Inserted: 1, Duplicates: 1}, importer.summary, "a dry run still catches repeats")
+	exists, err := db.ArticleExistsByURL(database, "https://example.com/10")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = readCSV(strings.NewReader("title,url,content\n"), importer.add)
+	assert.EqualError(t, err, "CSV is missing required columns: source, published")
+}
@@ -2,44 +2,92 @@ package main
 
 import (
 	"encoding/csv"
-	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "modernc.org/sqlite"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/importfile"
 )
 
 const LabelUnknown = "unknown"
 
+// defaultBatchSize is how many labels are inserted per transaction
+const defaultBatchSize = 500
+
+// labelRow is one labelled item read from the input file, with its row number for reporting
+type labelRow struct {
+	row   int
+	data  string
+	label string
+}
+
+// importOptions controls how rows are written
+type importOptions struct {
+	source     string
+	labeler    string
+	confidence float64
+	// upsert updates an existing label with the same data hash and labeler instead of
+	// inserting a duplicate
+	upsert bool
+	// dryRun validates and counts without writing
+	dryRun bool
+	// batchSize is how many labels are inserted per transaction
+	batchSize int
+}
+
+// importSummary counts what happened to each row
+type importSummary struct {
+	Inserted int
+	Updated  int
+	Skipped  int
+}
+
 func main() {
 	dbPath := flag.String("db", "news.db", "Path to SQLite database")
-	filePath := flag.String("file", "", "Path to labeled dataset file (CSV or JSON)")
-	format := flag.String("format", "csv", "File format: csv or json")
+	filePath := flag.String("file", "", "Path to labeled dataset file (CSV, JSON array or JSONL)")
+	format := flag.String("format", "csv", "File format: csv or json (JSON arrays and JSONL are detected automatically)")
 	source := flag.String("source", LabelUnknown, "Data source name")
 	labeler := flag.String("labeler", LabelUnknown, "Labeler name")
 	confidence := flag.Float64("confidence", 1.0, "Default confidence score")
+	upsert := flag.Bool("upsert", false, "Update labels with the same data and labeler instead of inserting duplicates")
+	dryRun := flag.Bool("dry-run", false, "Validate the file and report counts without writing")
+	batchSize := flag.Int("batch-size", defaultBatchSize, "Number of labels inserted per transaction")
 	flag.Parse()
 
 	if *filePath == "" {
-		log.Fatal("Please provide --file path to labeled dataset")
+		log.Printf("ERROR: Please provide --file path to labeled dataset")
+		os.Exit(1)
 	}
 
-	database, err := db.InitDB(*dbPath)
+	// A dry run writes nothing, so it only opens an existing database read-only, to
+	// find the labels an upsert would update
+	var database *sqlx.DB
+	var err error
+	switch {
+	case !*dryRun:
+		database, err = db.InitDB(*dbPath)
+	case *upsert:
+		database, err = db.OpenReadOnly(*dbPath)
+	}
 	if err != nil {
 		log.Printf("ERROR: Failed to open DB: %v", err)
 		os.Exit(1)
 	}
-	defer func() {
-		if closeErr := database.Close(); closeErr != nil {
-			log.Printf("Warning: Failed to close database: %v", closeErr)
-		}
-	}()
+	if database != nil {
+		defer func() {
+			if closeErr := database.Close(); closeErr != nil {
+				log.Printf("Warning: Failed to close database: %v", closeErr)
+			}
+		}()
+	}
 
 	f, err := os.Open(*filePath)
 	if err != nil {
@@ -52,38 +100,61 @@ func main() {
 		}
 	}()
 
-	var count int
+	importer := newLabelImporter(database, importOptions{
+		source:     *source,
+		labeler:    *labeler,
+		confidence: *confidence,
+		upsert:     *upsert,
+		dryRun:     *dryRun,
+		batchSize:  *batchSize,
+	})
+
+	var skipped int
 	switch *format {
 	case "csv":
-		count, err = importCSV(database, f, *source, *labeler, *confidence)
-	case "json":
-		count, err = importJSON(database, f, *source, *labeler, *confidence)
+		skipped, err = readCSV(f, importer.add)
+	case "json", "jsonl":
+		skipped, err = readJSON(f, importer.add)
 	default:
 		log.Printf("ERROR: Unsupported format: %s", *format)
 		os.Exit(1)
 	}
+	// Rows read before an error are still written
+	importer.flush()
+	summary := importer.summary
+	summary.Skipped += skipped
 	if err != nil {
-		log.Printf("ERROR: Import failed: %v", err)
-		os.Exit(1)
+		log.Printf("ERROR: Import stopped early: %v", err)
 	}
 
-	fmt.Printf("Imported %d labels from %s\n", count, *filePath)
+	prefix := ""
+	if *dryRun {
+		prefix = "Dry run: would have "
+	}
+	fmt.Printf("%sinserted %d, updated %d, skipped %d labels from %s\n",
+		prefix, summary.Inserted, summary.Updated, summary.Skipped, *filePath)
+	if err != nil {
+		os.Exit(1)
+	}
 }
 
-func importCSV(database *sqlx.DB, f *os.File, source, labeler string, confidence float64) (int, error) {
-	reader := csv.NewReader(f)
-	records, err := reader.ReadAll()
+// readCSV streams rows from a CSV file with 'data' and 'label' columns, returning how
+// many short rows were skipped
+func readCSV(r io.Reader, add func(labelRow)) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return 0, fmt.Errorf("empty CSV file")
+	}
 	if err != nil {
 		return 0, err
 	}
-	if len(records) < 1 {
-		return 0, fmt.Errorf("empty CSV file")
-	}
 
-	header := records[0]
 	dataIdx, labelIdx := -1, -1
 	for i, h := range header {
-		switch h {
+		switch strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")) {
 		case "data":
 			dataIdx = i
 		case "label":
@@ -94,59 +165,195 @@ func importCSV(database *sqlx.DB, f *os.File, source, labeler string, confidence
 		return 0, fmt.Errorf("CSV must have 'data' and 'label' columns")
 	}
 
-	count := 0
-	for _, rec := range records[1:] {
-		label := db.Label{
-			Data:        rec[dataIdx],
-			Label:       rec[labelIdx],
-			Source:      source,
-			DateLabeled: time.Now(),
-			Labeler:     labeler,
-			Confidence:  confidence,
-			CreatedAt:   time.Now(),
+	skipped := 0
+	for row := 2; ; row++ { // 1-based, after the header
+		rec, err := reader.Read()
+		if err == io.EOF {
+			return skipped, nil
 		}
-		// Insert label
-		err := db.InsertLabel(database, &label)
 		if err != nil {
-			log.Printf("Failed to insert label: %v", err)
+			return skipped, err
+		}
+		if dataIdx >= len(rec) || labelIdx >= len(rec) {
+			log.Printf("Skipping row %d: missing columns", row)
+			skipped++
 			continue
 		}
-		count++
+		add(labelRow{row: row, data: rec[dataIdx], label: rec[labelIdx]})
 	}
-	return count, nil
 }
 
-func importJSON(database *sqlx.DB, f *os.File, source, labeler string, confidence float64) (int, error) {
-	var items []map[string]interface{}
-	decoder := json.NewDecoder(f)
-	if err := decoder.Decode(&items); err != nil {
-		return 0, err
+// readJSON streams objects with string 'data' and 'label' fields from either a JSON
+// array or JSONL, one object per line, detected from the first character. It returns
+// how many objects were skipped.
+func readJSON(r io.Reader, add func(labelRow)) (int, error) {
+	return importfile.ReadJSON(r, func(n int, item importfile.Item) bool {
+		row, ok := rowFromItem(n, item)
+		if ok {
+			add(row)
+		}
+		return ok
+	})
+}
+
+// rowFromItem reads the 'data' and 'label' fields of a decoded object
+func rowFromItem(n int, item importfile.Item) (labelRow, bool) {
+	dataVal, ok1 := item["data"].(string)
+	labelVal, ok2 := item["label"].(string)
+	if !ok1 || !ok2 {
+		log.Printf("Skipping item %d: 'data' and 'label' must be strings: %v", n, item)
+		return labelRow{}, false
 	}
+	return labelRow{row: n, data: dataVal, label: labelVal}, true
+}
 
-	count := 0
-	for _, item := range items {
-		dataVal, ok1 := item["data"].(string)
-		labelVal, ok2 := item["label"].(string)
-		if !ok1 || !ok2 {
-			log.Printf("Skipping invalid item: %v", item)
-			continue
+// labelImporter validates rows as they are read and writes them in batches. With
+// upsert, each batch first looks up the labels it already has for the same data and
+// labeler and updates them instead of inserting duplicates. Invalid rows are skipped
+// and reported.
+type labelImporter struct {
+	// database is nil in a dry run without upsert, which needs no lookups
+	database *sqlx.DB
+	opts     importOptions
+	summary  importSummary
+	pending  []*db.Label
+	// pendingRows are the input rows of pending, for reporting
+	pendingRows []int
+	// pendingByHash indexes pending by data hash; only used with upsert
+	pendingByHash map[string]int
+	// dryRunInserted holds the data hashes a dry run with upsert would have inserted,
+	// since later batches cannot find them in the database
+	dryRunInserted map[string]bool
+}
+
+func newLabelImporter(database *sqlx.DB, opts importOptions) *labelImporter {
+	if opts.batchSize <= 0 {
+		opts.batchSize = defaultBatchSize
+	}
+	im := &labelImporter{database: database, opts: opts}
+	if opts.upsert {
+		im.pendingByHash = make(map[string]int)
+		if opts.dryRun {
+			im.dryRunInserted = make(map[string]bool)
 		}
-		label := db.Label{
-			Data:        dataVal,
-			Label:       labelVal,
-			Source:      source,
-			DateLabeled: time.Now(),
-			Labeler:     labeler,
-			Confidence:  confidence,
-			CreatedAt:   time.Now(),
+	}
+	return im
+}
+
+// add validates a row and queues it for writing
+func (im *labelImporter) add(r labelRow) {
+	label, ok := db.NormalizeLabel(r.label)
+	if !ok {
+		log.Printf("Skipping row %d: invalid label %q (want left, right or neutral)", r.row, r.label)
+		im.summary.Skipped++
+		return
+	}
+	if strings.TrimSpace(r.data) == "" {
+		log.Printf("Skipping row %d: empty data", r.row)
+		im.summary.Skipped++
+		return
+	}
+
+	now := time.Now()
+	record := &db.Label{
+		Data:        r.data,
+		Label:       label,
+		Source:      im.opts.source,
+		DateLabeled: now,
+		Labeler:     im.opts.labeler,
+		Confidence:  im.opts.confidence,
+		CreatedAt:   now,
+	}
+
+	if im.opts.upsert {
+		hash := db.LabelDataHash(r.data)
+		// A repeated row replaces the queued one rather than duplicating it
+		if i, queued := im.pendingByHash[hash]; queued {
+			im.pending[i] = record
+			im.pendingRows[i] = r.row
+			im.summary.Updated++
+			return
 		}
-		// Insert label
-		err := db.InsertLabel(database, &label)
-		if err != nil {
-			log.Printf("Failed to insert label: %v", err)
+		im.pendingByHash[hash] = len(im.pending)
+	}
+
+	im.pending = append(im.pending, record)
+	im.pendingRows = append(im.pendingRows, r.row)
+	if len(im.pending) >= im.opts.batchSize {
+		im.flush()
+	}
+}
+
+// flush writes the queued labels
+func (im *labelImporter) flush() {
+	if len(im.pending) == 0 {
+		return
+	}
+	defer im.resetPending()
+	first, last := im.pendingRows[0], im.pendingRows[len(im.pendingRows)-1]
+
+	inserts := im.pending
+	if im.opts.upsert {
+		var err error
+		if inserts, err = im.updateExisting(); err != nil {
+			log.Printf("Failed to look up existing labels for rows %d-%d: %v", first, last, err)
+			im.summary.Skipped += len(im.pending)
+			return
+		}
+	}
+	if len(inserts) > 0 && !im.opts.dryRun {
+		if err := db.InsertLabels(im.database, inserts); err != nil {
+			log.Printf("Failed to insert labels from rows %d-%d: %v", first, last, err)
+			im.summary.Skipped += len(inserts)
+			return
+		}
+	}
+	im.summary.Inserted += len(inserts)
+}
+
+// updateExisting updates the stored labels the queued ones match and returns the
+// rest, which are new
+func (im *labelImporter) updateExisting() ([]*db.Label, error) {
+	data := make([]string, len(im.pending))
+	for i, record := range im.pending {
+		data[i] = record.Data
+	}
+	existing, err := db.FetchLabelIDsByData(im.database, im.opts.labeler, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var inserts []*db.Label
+	for i, record := range im.pending {
+		hash := db.LabelDataHash(record.Data)
+		if id, found := existing[hash]; found {
+			record.ID = id
+			if !im.opts.dryRun {
+				if err := db.UpdateLabel(im.database, record); err != nil {
+					log.Printf("Failed to update label from row %d: %v", im.pendingRows[i], err)
+					im.summary.Skipped++
+					continue
+				}
+			}
+			im.summary.Updated++
 			continue
 		}
-		count++
+		if im.dryRunInserted != nil {
+			if im.dryRunInserted[hash] {
+				im.summary.Updated++
+				continue
+			}
+			im.dryRunInserted[hash] = true
+		}
+		inserts = append(inserts, record)
+	}
+	return inserts, nil
+}
+
+func (im *labelImporter) resetPending() {
+	im.pending = im.pending[:0]
+	im.pendingRows = im.pendingRows[:0]
+	if im.opts.upsert {
+		im.pendingByHash = make(map[string]int)
 	}
-	return count, nil
 }
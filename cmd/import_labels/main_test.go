@@ -0,0 +1,65 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+)
+
+func TestImportLabelsUpsert(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "labels.db")
+	database, err := db.InitDB(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = database.Close() }()
+
+	opts := importOptions{source: "src", labeler: "alice", confidence: 1, upsert: true, batchSize: 2}
+	importer := newLabelImporter(database, opts)
+	skipped, err := readCSV(strings.NewReader("data,label\n"+
+		"first,left\n"+
+		"second,Right\n"+
+		"third,center-left\n"+
+		"  first ,neutral\n"+
+		"fourth,0\n"+
+		"fourth,1\n"), importer.add)
+	require.NoError(t, err)
+	importer.flush()
+	assert.Zero(t, skipped)
+	assert.Equal(t, importSummary{Inserted: 3, Updated: 2, Skipped: 1}, importer.summary,
+		"a row matching an earlier batch updates it, a repeat within a batch replaces it")
+
+	var labels []db.Label
+	require.NoError(t, database.Select(&labels, "SELECT * FROM labels ORDER BY id"))
+	require.Len(t, labels, 3)
+	assert.Equal(t, "neutral", labels[0].Label)
+	assert.Equal(t, "right", labels[1].Label)
+	assert.Equal(t, "right", labels[2].Label)
+
+	// A dry run finds the stored labels through a read-only connection
+	readOnly, err := db.OpenReadOnly(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = readOnly.Close() }()
+	opts.dryRun = true
+	importer = newLabelImporter(readOnly, opts)
+	_, err = readJSON(strings.NewReader(`{"data": "first", "label": "left"}`+"\n"+
+		`{"data": "fifth", "label": "left"}`+"\n"+
+		`{"data": "sixth", "label": "left"}`+"\n"+
+		`{"data": "fifth", "label": "right"}`+"\n"), importer.add)
+	require.NoError(t, err)
+	importer.flush()
+	assert.Equal(t, importSummary{Inserted: 2, Updated: 2}, importer.summary,
+		"a dry run counts a row repeated across batches once")
+
+	var count int
+	require.NoError(t, database.Get(&count, "SELECT COUNT(*) FROM labels"))
+	assert.Equal(t, 3, count)
+	_, err = readOnly.Exec("DELETE FROM labels")
+	assert.Error(t, err, "the dry-run connection cannot write")
+
+	_, err = db.OpenReadOnly(filepath.Join(t.TempDir(), "missing.db"))
+	assert.Error(t, err, "a dry run does not create a database")
+}
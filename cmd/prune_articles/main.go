@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+)
+
+// parseMaxAge reads a Go duration or a number of days such as "90d"
+func parseMaxAge(v string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid --max-age %q", v)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(v)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid --max-age %q", v)
+	}
+	return age, nil
+}
+
+func run() error {
+	dbPath := flag.String("db", "news.db", "Path to SQLite database")
+	maxAge := flag.String("max-age", "", "Delete articles ingested longer ago than this, e.g. 90d or 2160h")
+	maxCount := flag.Int("max-count", 0, "Keep only this many of the newest articles (0 = no limit)")
+	batchSize := flag.Int("batch-size", 500, "Number of articles deleted per transaction")
+	dryRun := flag.Bool("dry-run", false, "Report what would be deleted without deleting anything")
+	flag.Parse()
+
+	var policy db.RetentionPolicy
+	if *maxAge != "" {
+		age, err := parseMaxAge(*maxAge)
+		if err != nil {
+			return err
+		}
+		policy.MaxAge = age
+	}
+	if *maxCount < 0 {
+		return fmt.Errorf("--max-count must not be negative")
+	}
+	policy.MaxCount = *maxCount
+	if *batchSize < 1 {
+		return fmt.Errorf("--batch-size must be at least 1")
+	}
+	if !policy.Enabled() {
+		log.Println("No --max-age or --max-count given; only orphaned scores and feedback are removed")
+	}
+
+	database, err := db.InitDB(*dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open DB: %w", err)
+	}
+	defer func() {
+		if closeErr := database.Close(); closeErr != nil {
+			log.Printf("Warning: Failed to close database: %v", closeErr)
+		}
+	}()
+
+	result, err := db.CleanupArticles(database, policy, time.Now(), *batchSize, *dryRun)
+	prefix := "Deleted"
+	if *dryRun {
+		prefix = "Dry run: would delete"
+	}
+	fmt.Printf("%s %d articles, %d model scores, %d score history entries, %d feedback items and %d tag assignments\n",
+		prefix, result.Articles, result.LLMScores, result.ScoreHistory, result.Feedback, result.ArticleTags)
+	return err
+}
+
+func main() {
+	if err := run(); err != nil {
+		log.Printf("ERROR: %v", err)
+		os.Exit(1)
+	}
+}
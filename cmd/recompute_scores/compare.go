@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+)
+
+// profileStats accumulates one profile's composites across articles
+type profileStats struct {
+	scored     int
+	failed     int
+	sumScore   float64
+	compared   int // scored articles that also have a stored composite
+	sumAbsDiff float64
+}
+
+// profileComparer writes one CSV row per article with each profile's composite side
+// by side and keeps per-profile totals for the summary
+type profileComparer struct {
+	profiles []llm.AggregationProfile
+	writer   *csv.Writer
+	stats    []profileStats
+	articles int
+	skipped  int
+}
+
+func newProfileComparer(profiles []llm.AggregationProfile, w io.Writer) (*profileComparer, error) {
+	pc := &profileComparer{profiles: profiles, writer: csv.NewWriter(w), stats: make([]profileStats, len(profiles))}
+	header := []string{"article_id", "stored_score", "models"}
+	for _, p := range profiles {
+		header = append(header, p.Name+"_score", p.Name+"_confidence")
+	}
+	return pc, pc.writer.Write(header)
+}
+
+// Add records one article's comparison; failed profiles leave their cells empty
+func (pc *profileComparer) Add(comparison *llm.ProfileComparison) error {
+	pc.articles++
+	row := []string{fmt.Sprint(comparison.ArticleID), "", fmt.Sprint(comparison.Models)}
+	if comparison.PreviousScore != nil {
+		row[1] = formatScore(*comparison.PreviousScore)
+	}
+	for i, score := range comparison.Scores {
+		stats := &pc.stats[i]
+		if score.Err != nil {
+			stats.failed++
+			row = append(row, "", "")
+			continue
+		}
+		stats.scored++
+		stats.sumScore += score.Score
+		if comparison.PreviousScore != nil {
+			stats.compared++
+			stats.sumAbsDiff += math.Abs(score.Score - *comparison.PreviousScore)
+		}
+		row = append(row, formatScore(score.Score), formatScore(score.Confidence))
+	}
+	return pc.writer.Write(row)
+}
+
+// Flush writes any buffered rows
+func (pc *profileComparer) Flush() error {
+	pc.writer.Flush()
+	return pc.writer.Error()
+}
+
+// Print writes the per-profile summary to w
+func (pc *profileComparer) Print(w io.Writer) {
+	fmt.Fprintf(w, "\n--- Profile Comparison (%d articles, %d without usable scores) ---\n", pc.articles, pc.skipped)
+	fmt.Fprintf(w, "%-20s %8s %8s %12s %18s\n", "profile", "scored", "failed", "mean score", "mean |vs stored|")
+	for i, p := range pc.profiles {
+		s := pc.stats[i]
+		mean, diff := math.NaN(), math.NaN()
+		if s.scored > 0 {
+			mean = s.sumScore / float64(s.scored)
+		}
+		if s.compared > 0 {
+			diff = s.sumAbsDiff / float64(s.compared)
+		}
+		fmt.Fprintf(w, "%-20s %8d %8d %12.4f %18.4f\n", p.Name, s.scored, s.failed, mean, diff)
+	}
+}
+
+func formatScore(v float64) string {
+	return fmt.Sprintf("%.4f", v)
+}
+
+// compareProfiles computes every scored article's composite under each profile and
+// writes them side by side as CSV to output, or stdout when empty, without storing
+// anything. Rows keep article ID order.
+func compareProfiles(conn *sqlx.DB, scoreManager *llm.ScoreManager, profiles []llm.AggregationProfile,
+	output string, batchSize, workers, maxArticles int) (err error) {
+	out := io.Writer(os.Stdout)
+	if output != "" {
+		f, createErr := os.Create(output) // #nosec G304 - output is an operator-supplied CLI argument
+		if createErr != nil {
+			return createErr
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		out = f
+	}
+	comparer, err := newProfileComparer(profiles, out)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Comparing %d aggregation profiles with batch size %d, %d workers, max articles %d",
+		len(profiles), batchSize, workers, maxArticles)
+	var writeErr error
+	err = forEachScoredBatch(conn, batchSize, maxArticles, func(ids []int64) {
+		results := make([]*llm.ProfileComparison, len(ids))
+		var wg sync.WaitGroup
+		idx := make(chan int, len(ids))
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// The calculator may annotate configs while scoring, so each worker gets copies
+				workerProfiles := make([]llm.AggregationProfile, len(profiles))
+				for i, p := range profiles {
+					cfg := *p.Config
+					workerProfiles[i] = llm.AggregationProfile{Name: p.Name, Config: &cfg}
+				}
+				for i := range idx {
+					comparison, compareErr := scoreManager.CompareProfiles(ids[i], workerProfiles)
+					if compareErr != nil {
+						log.Printf("Skipping article %d: %v", ids[i], compareErr)
+						continue
+					}
+					results[i] = comparison
+				}
+			}()
+		}
+		for i := range ids {
+			idx <- i
+		}
+		close(idx)
+		wg.Wait()
+
+		for _, comparison := range results {
+			if comparison == nil {
+				comparer.skipped++
+				continue
+			}
+			if writeErr == nil {
+				writeErr = comparer.Add(comparison)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+	if err := comparer.Flush(); err != nil {
+		return err
+	}
+	comparer.Print(os.Stderr)
+	return nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	_ "modernc.org/sqlite"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+)
+
+// changeEpsilon is the smallest composite difference reported as a change
+const changeEpsilon = 1e-6
+
+// recomputeStats accumulates how the recomputed composites differ from the stored ones
+type recomputeStats struct {
+	mu             sync.Mutex
+	processed      int
+	changed        int
+	unchanged      int
+	newlyScored    int
+	failed         int
+	totalAbsChange float64
+	maxAbsChange   float64
+	maxChangeID    int64
+}
+
+// Add records the outcome for one article
+func (s *recomputeStats) Add(result *llm.RecomputeResult, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processed++
+	switch {
+	case err != nil:
+		s.failed++
+	case result.PreviousScore == nil:
+		s.newlyScored++
+	default:
+		delta := math.Abs(result.Score - *result.PreviousScore)
+		if delta < changeEpsilon {
+			s.unchanged++
+			return
+		}
+		s.changed++
+		s.totalAbsChange += delta
+		if delta > s.maxAbsChange {
+			s.maxAbsChange = delta
+			s.maxChangeID = result.ArticleID
+		}
+	}
+}
+
+// Print writes the summary to stdout
+func (s *recomputeStats) Print(dryRun bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	verb := "changed"
+	if dryRun {
+		verb = "would change"
+	}
+	avg := 0.0
+	if s.changed > 0 {
+		avg = s.totalAbsChange / float64(s.changed)
+	}
+	fmt.Printf("\n--- Recompute Job Complete (dry run: %t) ---\n", dryRun)
+	fmt.Printf("Articles processed: %d\n", s.processed)
+	fmt.Printf("Composites that %s: %d (mean |change| %.4f, max |change| %.4f", verb, s.changed, avg, s.maxAbsChange)
+	if s.changed > 0 {
+		fmt.Printf(" for article %d", s.maxChangeID)
+	}
+	fmt.Printf(")\n")
+	fmt.Printf("Composites unchanged: %d\n", s.unchanged)
+	fmt.Printf("Articles without a previous composite: %d\n", s.newlyScored)
+	fmt.Printf("Failed: %d\n", s.failed)
+}
+
+// validateFlags checks the batch tuning flags
+func validateFlags(batchSize, workerCount, maxArticles int) error {
+	if batchSize < 1 {
+		return errors.New("--batch-size must be at least 1")
+	}
+	if workerCount < 1 {
+		return errors.New("--workers must be at least 1")
+	}
+	if maxArticles < 0 {
+		return errors.New("--max-articles must not be negative")
+	}
+	return nil
+}
+
+func main() {
+	dbPathFlag := flag.String("db", "news.db", "Path to SQLite database")
+	batchSizeFlag := flag.Int("batch-size", 100, "Number of articles fetched and recomputed per batch")
+	workersFlag := flag.Int("workers", 4, "Number of concurrent recompute workers per batch")
+	maxArticlesFlag := flag.Int("max-articles", 0, "Maximum number of articles to recompute (0 = no limit)")
+	dryRun := flag.Bool("dry-run", false, "Report how many composites would change, and by how much, without storing anything")
+	profilesFlag := flag.String("profiles", "", "JSON file of named aggregation profiles to compare side by side instead of recomputing; nothing is stored")
+	outputFlag := flag.String("output", "", "CSV file for the --profiles comparison (default: stdout)")
+	verbose := flag.Bool("verbose", false, "Log per-article details")
+	flag.Parse()
+
+	if err := validateFlags(*batchSizeFlag, *workersFlag, *maxArticlesFlag); err != nil {
+		log.Fatalf("Invalid flags: %v", err)
+	}
+	// vlogf logs per-article details only when --verbose is set
+	vlogf := func(format string, args ...interface{}) {
+		if *verbose {
+			log.Printf(format, args...)
+		}
+	}
+	batchSize := *batchSizeFlag
+	maxArticles := *maxArticlesFlag
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found or error loading .env file (this is okay if env vars are set elsewhere)")
+	}
+
+	conn, err := db.InitDB(*dbPathFlag)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Printf("Error closing database connection: %v", err)
+		}
+	}()
+
+	config, err := llm.LoadCompositeScoreConfig()
+	if err != nil {
+		log.Fatalf("Failed to load composite score config: %v", err)
+	}
+
+	// No LLM client is needed: composites are recomputed from stored scores only
+	progressMgr := llm.NewProgressManager(10 * time.Minute)
+	defer progressMgr.Stop()
+	scoreManager := llm.NewScoreManager(conn, llm.NewCache(), llm.ScoreCalculatorFromEnv(), progressMgr)
+
+	if *profilesFlag != "" {
+		profiles, err := llm.LoadAggregationProfiles(*profilesFlag, config)
+		if err != nil {
+			log.Fatalf("Failed to load aggregation profiles: %v", err)
+		}
+		if err := compareProfiles(conn, scoreManager, profiles, *outputFlag, batchSize, *workersFlag, maxArticles); err != nil {
+			log.Fatalf("Profile comparison failed: %v", err)
+		}
+		return
+	}
+
+	log.Printf("Recomputing composites with batch size %d, %d workers, max articles %d, dry run %t",
+		batchSize, *workersFlag, maxArticles, *dryRun)
+
+	stats := &recomputeStats{}
+	err = forEachScoredBatch(conn, batchSize, maxArticles, func(ids []int64) {
+		vlogf("Processing batch of %d articles. IDs: %v", len(ids), ids)
+
+		var wg sync.WaitGroup
+		idCh := make(chan int64, len(ids))
+		for i := 0; i < *workersFlag; i++ {
+			wg.Add(1)
+			go func(workerID int) {
+				defer wg.Done()
+				// UpdateArticleScore annotates the config while scoring, so each worker gets a copy
+				workerConfig := *config
+				for id := range idCh {
+					var result *llm.RecomputeResult
+					var recomputeErr error
+					if *dryRun {
+						result, recomputeErr = scoreManager.PreviewRecompute(id, &workerConfig)
+					} else {
+						result, recomputeErr = scoreManager.RecomputeArticleScore(id, &workerConfig)
+					}
+					stats.Add(result, recomputeErr)
+					if recomputeErr != nil {
+						log.Printf("[Worker %d] Failed to recompute article %d: %v", workerID, id, recomputeErr)
+						continue
+					}
+					if result.PreviousScore != nil {
+						vlogf("[Worker %d] Article %d: %.4f -> %.4f", workerID, id, *result.PreviousScore, result.Score)
+					} else {
+						vlogf("[Worker %d] Article %d: none -> %.4f", workerID, id, result.Score)
+					}
+				}
+			}(i)
+		}
+		for _, id := range ids {
+			idCh <- id
+		}
+		close(idCh)
+		wg.Wait()
+	})
+	if err != nil {
+		log.Fatalf("Failed to fetch scored articles: %v", err)
+	}
+
+	stats.Print(*dryRun)
+}
+
+// forEachScoredBatch calls fn with the IDs of articles that have per-model scores, in
+// batches of batchSize in ID order, stopping after maxArticles when it is positive
+func forEachScoredBatch(conn *sqlx.DB, batchSize, maxArticles int, fn func(ids []int64)) error {
+	var afterID int64
+	total := 0
+	for {
+		limit := batchSize
+		if maxArticles > 0 {
+			remaining := maxArticles - total
+			if remaining <= 0 {
+				log.Printf("Reached --max-articles cap of %d.", maxArticles)
+				return nil
+			}
+			if remaining < limit {
+				limit = remaining
+			}
+		}
+
+		ids, err := db.FetchModelScoredArticleIDs(conn, afterID, limit)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		fn(ids)
+
+		total += len(ids)
+		afterID = ids[len(ids)-1]
+		log.Printf("[PROGRESS] %d articles processed", total)
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"sync"
@@ -30,6 +32,78 @@ func (s *APIUsageStats) AddCall(duration time.Duration, err error) {
 	}
 }
 
+// progressReporter periodically logs overall progress, throughput and ETA
+type progressReporter struct {
+	total     int
+	processed int
+	start     time.Time
+	mu        sync.Mutex
+	stopChan  chan struct{}
+	done      chan struct{}
+}
+
+func newProgressReporter(total int) *progressReporter {
+	return &progressReporter{
+		total:    total,
+		start:    time.Now(),
+		stopChan: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Add records n more processed articles
+func (p *progressReporter) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.processed += n
+}
+
+// Report logs processed/total, the average rate and the estimated time remaining
+func (p *progressReporter) Report() {
+	p.mu.Lock()
+	processed, total := p.processed, p.total
+	p.mu.Unlock()
+
+	elapsed := time.Since(p.start)
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(processed) / elapsed.Seconds()
+	}
+	eta := "unknown"
+	if rate > 0 && total >= processed {
+		eta = (time.Duration(float64(total-processed)/rate) * time.Second).Round(time.Second).String()
+	}
+	percent := 0.0
+	if total > 0 {
+		percent = float64(processed) / float64(total) * 100
+	}
+	log.Printf("[PROGRESS] %d/%d articles (%.1f%%), %.2f articles/s, elapsed %v, ETA %s",
+		processed, total, percent, rate, elapsed.Round(time.Second), eta)
+}
+
+// Start reports progress every interval until Stop is called
+func (p *progressReporter) Start(interval time.Duration) {
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.Report()
+			case <-p.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends periodic reporting
+func (p *progressReporter) Stop() {
+	close(p.stopChan)
+	<-p.done
+}
+
 func (s *APIUsageStats) Print(prefix string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -40,8 +114,99 @@ func (s *APIUsageStats) Print(prefix string) {
 	log.Printf("%s API usage: calls=%d, errors=%d, total_time=%v, avg_time=%v", prefix, s.CallCount, s.ErrorCount, s.TotalDuration, avg)
 }
 
+// validateFlags checks the batch tuning flags
+func validateFlags(batchSize, workerCount, startOffset, maxArticles int) error {
+	if batchSize < 1 {
+		return errors.New("--batch-size must be at least 1")
+	}
+	if workerCount < 1 {
+		return errors.New("--workers must be at least 1")
+	}
+	if startOffset < 0 {
+		return errors.New("--start-offset must not be negative")
+	}
+	if maxArticles < 0 {
+		return errors.New("--max-articles must not be negative")
+	}
+	return nil
+}
+
+// parseDateFlag reads an optional date flag given as YYYY-MM-DD (UTC midnight) or as an
+// RFC 3339 timestamp
+func parseDateFlag(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("--%s must be a date as YYYY-MM-DD or an RFC 3339 timestamp, got %q", name, value)
+}
+
+// articleFilterFromFlags builds the filter that selects the articles to score
+func articleFilterFromFlags(source, publishedAfter, publishedBefore string) (db.ArticleFilter, error) {
+	filter := db.ArticleFilter{Source: source}
+	var err error
+	if filter.PublishedAfter, err = parseDateFlag("published-after", publishedAfter); err != nil {
+		return filter, err
+	}
+	if filter.PublishedBefore, err = parseDateFlag("published-before", publishedBefore); err != nil {
+		return filter, err
+	}
+	if filter.PublishedAfter != nil && filter.PublishedBefore != nil && !filter.PublishedAfter.Before(*filter.PublishedBefore) {
+		return filter, errors.New("--published-after must be before --published-before")
+	}
+	return filter, nil
+}
+
+// waitForProvider blocks while the LLM circuit breaker is open, so a provider outage
+// pauses scoring instead of failing every article
+func waitForProvider(llmClient *llm.LLMClient) {
+	for {
+		until, unavailable := llmClient.ProviderUnavailableUntil()
+		if !unavailable {
+			return
+		}
+		log.Printf("LLM provider unavailable (circuit breaker open), pausing until %s", until.Format(time.RFC3339))
+		time.Sleep(time.Until(until))
+	}
+}
+
 func main() {
-	err := godotenv.Load()
+	batchSizeFlag := flag.Int("batch-size", 10, "Number of articles fetched and scored per batch")
+	workersFlag := flag.Int("workers", 4, "Number of concurrent scoring workers per batch")
+	startOffsetFlag := flag.Int("start-offset", 0, "Number of matching articles (newest first) to skip before scoring")
+	maxArticlesFlag := flag.Int("max-articles", 0, "Maximum number of articles to score (0 = no limit)")
+	progressIntervalFlag := flag.Duration("progress-interval", 30*time.Second, "How often to log overall progress and ETA")
+	sourceFlag := flag.String("source", "", "Only score articles from this source")
+	publishedAfterFlag := flag.String("published-after", "", "Only score articles published on or after this date (YYYY-MM-DD or RFC 3339)")
+	publishedBeforeFlag := flag.String("published-before", "", "Only score articles published before this date (YYYY-MM-DD or RFC 3339)")
+	verbose := flag.Bool("verbose", false, "Log per-article details")
+	flag.Parse()
+
+	if err := validateFlags(*batchSizeFlag, *workersFlag, *startOffsetFlag, *maxArticlesFlag); err != nil {
+		log.Fatalf("Invalid flags: %v", err)
+	}
+	filter, err := articleFilterFromFlags(*sourceFlag, *publishedAfterFlag, *publishedBeforeFlag)
+	if err != nil {
+		log.Fatalf("Invalid flags: %v", err)
+	}
+	if *progressIntervalFlag <= 0 {
+		log.Fatalf("Invalid flags: --progress-interval must be positive")
+	}
+	// vlogf logs per-article details only when --verbose is set
+	vlogf := func(format string, args ...interface{}) {
+		if *verbose {
+			log.Printf(format, args...)
+		}
+	}
+	batchSize := *batchSizeFlag
+	workerCount := *workersFlag
+	maxArticles := *maxArticlesFlag
+
+	err = godotenv.Load()
 	if err != nil {
 		log.Println("No .env file found or error loading .env file (this is okay if env vars are set elsewhere)")
 	}
@@ -73,20 +238,54 @@ func main() {
 
 	// Instantiate ScoreManager and its dependencies
 	cache := llm.NewCache()
-	calculator := &llm.DefaultScoreCalculator{}
+	calculator := llm.ScoreCalculatorFromEnv()
 	// Using a longer cleanup interval as this is a batch job
 	progressMgr := llm.NewProgressManager(10 * time.Minute)
 	scoreManager := llm.NewScoreManager(conn, cache, calculator, progressMgr)
 
-	const batchSize = 10 // Reduced batch size for potentially more logging/granularity during testing
-	const workerCount = 4
+	log.Printf("Scoring with batch size %d, %d workers, start offset %d, max articles %d",
+		batchSize, workerCount, *startOffsetFlag, maxArticles)
 
 	var totalArticlesProcessed, totalLLMScoresGenerated, totalCompositeScoresUpdated int
 	apiStats := &APIUsageStats{}
 
-	offset := 0
+	articleCount, err := db.CountArticlesFiltered(conn, filter)
+	if err != nil {
+		log.Fatalf("Failed to count articles: %v", err)
+	}
+	if filter.Source != "" || filter.PublishedAfter != nil || filter.PublishedBefore != nil {
+		log.Printf("%d articles match the filters (source %q, published after %q, before %q)",
+			articleCount, filter.Source, *publishedAfterFlag, *publishedBeforeFlag)
+	}
+	totalToProcess := articleCount - *startOffsetFlag
+	if totalToProcess < 0 {
+		totalToProcess = 0
+	}
+	if maxArticles > 0 && maxArticles < totalToProcess {
+		totalToProcess = maxArticles
+	}
+	log.Printf("%d articles to score", totalToProcess)
+
+	progress := newProgressReporter(totalToProcess)
+	progress.Start(*progressIntervalFlag)
+
+	offset := *startOffsetFlag
 	for {
-		articlesToProcess, fetchErr := db.FetchArticles(conn, "", "", batchSize, offset)
+		limit := batchSize
+		if maxArticles > 0 {
+			remaining := maxArticles - totalArticlesProcessed
+			if remaining <= 0 {
+				log.Printf("Reached --max-articles cap of %d.", maxArticles)
+				break
+			}
+			if remaining < limit {
+				limit = remaining
+			}
+		}
+
+		waitForProvider(llmClient)
+		filter.Limit, filter.Offset = limit, offset
+		articlesToProcess, fetchErr := db.FetchArticlesFiltered(conn, filter)
 		if fetchErr != nil {
 			log.Fatalf("Failed to fetch articles: %v", fetchErr)
 		}
@@ -99,10 +298,10 @@ func main() {
 		for _, article := range articlesToProcess {
 			currentBatchArticleIDs = append(currentBatchArticleIDs, article.ID)
 		}
-		log.Printf("Processing batch of %d articles. IDs: %v", len(articlesToProcess), currentBatchArticleIDs)
+		vlogf("Processing batch of %d articles. IDs: %v", len(articlesToProcess), currentBatchArticleIDs)
 
 		// Stage 1: Collect individual LLM scores for all articles in the batch
-		log.Printf("Stage 1: Collecting individual LLM scores for %d articles...", len(articlesToProcess))
+		vlogf("Stage 1: Collecting individual LLM scores for %d articles...", len(articlesToProcess))
 		var wg sync.WaitGroup
 		articleCh := make(chan db.Article, len(articlesToProcess)) // Buffered channel
 
@@ -110,9 +309,10 @@ func main() {
 			wg.Add(1)
 			go func(workerID int) {
 				defer wg.Done()
-				log.Printf("[Worker %d] Started", workerID)
+				vlogf("[Worker %d] Started", workerID)
 				for article := range articleCh {
-					log.Printf("[Worker %d] Analyzing article ID %d (%s) for individual LLM scores...", workerID, article.ID, article.Title)
+					waitForProvider(llmClient)
+					vlogf("[Worker %d] Analyzing article ID %d (%s) for individual LLM scores...", workerID, article.ID, article.Title)
 					var scoresGeneratedForThisArticle int
 
 					// Placeholder IDs: const idForInvalidTest = 7; const idForZeroConfTest = 8;
@@ -140,14 +340,14 @@ func main() {
 						}
 					} // End models loop
 
-					log.Printf("[Worker %d] Finished LLM analysis for article ID %d. "+
+					vlogf("[Worker %d] Finished LLM analysis for article ID %d. "+
 						"Generated %d individual scores (skipped if test ID).", workerID, article.ID, scoresGeneratedForThisArticle)
 					// Safely update global counter (though could be done after wg.Wait for simplicity if only counting total LLM scores)
 					apiStats.mu.Lock() // Assuming APIUsageStats has a mutex for its counters if updated concurrently here
 					totalLLMScoresGenerated += scoresGeneratedForThisArticle
 					apiStats.mu.Unlock()
 				} // End article channel loop
-				log.Printf("[Worker %d] Finished", workerID)
+				vlogf("[Worker %d] Finished", workerID)
 			}(i)
 		}
 
@@ -156,12 +356,12 @@ func main() {
 		}
 		close(articleCh)
 		wg.Wait()
-		log.Printf("Stage 1: Collection of individual LLM scores for batch complete.")
+		vlogf("Stage 1: Collection of individual LLM scores for batch complete.")
 
 		// Stage 2: Calculate and store composite scores for the processed batch
-		log.Printf("Stage 2: Calculating and storing composite scores for %d articles...", len(articlesToProcess))
+		vlogf("Stage 2: Calculating and storing composite scores for %d articles...", len(articlesToProcess))
 		for _, article := range articlesToProcess {
-			log.Printf("Fetching LLM scores for article ID %d to compute composite score...", article.ID)
+			vlogf("Fetching LLM scores for article ID %d to compute composite score...", article.ID)
 			fetchedLLMScores, fetchLLMErr := db.FetchLLMScores(conn, article.ID)
 			if fetchLLMErr != nil {
 				log.Printf("[ERROR] Failed to fetch LLM scores for article ID %d: %v. Skipping composite score calculation.", article.ID, fetchLLMErr)
@@ -175,7 +375,7 @@ func main() {
 				// Calling UpdateArticleScore with empty scores should trigger ErrAllPerspectivesInvalid if appropriate.
 			}
 
-			log.Printf("Calculating composite score for article ID %d (%s) "+
+			vlogf("Calculating composite score for article ID %d (%s) "+
 				"using %d fetched LLM scores...", article.ID, article.Title, len(fetchedLLMScores))
 			_, _, compErr := scoreManager.UpdateArticleScore(article.ID, fetchedLLMScores, config)
 			if compErr != nil {
@@ -183,19 +383,22 @@ func main() {
 				log.Printf("[ERROR] Failed to compute or store composite score for article ID %d: %v", article.ID, compErr)
 				// The status is updated by ScoreManager, so no explicit status update here on error is needed
 			} else {
-				log.Printf("[INFO] Successfully computed and stored composite score for article ID %d.", article.ID)
+				vlogf("[INFO] Successfully computed and stored composite score for article ID %d.", article.ID)
 				totalCompositeScoresUpdated++
 			}
 		} // End loop for composite scoring for the batch
-		log.Printf("Stage 2: Composite score processing for batch complete.")
+		vlogf("Stage 2: Composite score processing for batch complete.")
 
 		totalArticlesProcessed += len(articlesToProcess)
 		offset += len(articlesToProcess)
-		log.Printf("Batch processed. Total articles processed so far: %d. Moving to next offset: %d", totalArticlesProcessed, offset)
+		progress.Add(len(articlesToProcess))
+		vlogf("Batch processed. Total articles processed so far: %d. Moving to next offset: %d", totalArticlesProcessed, offset)
 
 		// Optional: Add a small delay between batches if needed for API rate limits or DB load
 		// time.Sleep(1 * time.Second)
 	} // End main processing loop (batches)
+	progress.Stop()
+	progress.Report()
 
 	fmt.Printf("\n--- Scoring Job Complete ---\n")
 	fmt.Printf("Total articles processed (fetched in batches): %d\n", totalArticlesProcessed)
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleFilterFromFlags(t *testing.T) {
+	filter, err := articleFilterFromFlags("bbc", "2026-03-01", "2026-03-08T12:00:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, "bbc", filter.Source)
+	require.NotNil(t, filter.PublishedAfter)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), *filter.PublishedAfter)
+	require.NotNil(t, filter.PublishedBefore)
+	assert.Equal(t, time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC), filter.PublishedBefore.UTC())
+
+	filter, err = articleFilterFromFlags("", "", "")
+	require.NoError(t, err)
+	assert.Nil(t, filter.PublishedAfter)
+	assert.Nil(t, filter.PublishedBefore)
+
+	_, err = articleFilterFromFlags("", "March 1", "")
+	assert.ErrorContains(t, err, "--published-after")
+	_, err = articleFilterFromFlags("", "2026-03-08", "2026-03-01")
+	assert.Error(t, err, "an empty window is rejected")
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -9,9 +11,55 @@ import (
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/testing"
+	"github.com/jmoiron/sqlx"
 )
 
+const defaultSeedFile = "testdata/seed/sources.sql"
+
+// testArticle is an article seeded for the accessibility and E2E tests
+type testArticle struct {
+	title   string
+	content string
+}
+
+var testArticles = []testArticle{
+	{
+		title:   "Test Article for Accessibility Testing",
+		content: "This is test content for accessibility testing. It ensures that H1 elements have proper content and are visible to screen readers.",
+	},
+	{
+		title:   "Test Article 4 for Additional Testing",
+		content: "Additional test content for comprehensive testing.",
+	},
+}
+
+// seedReport records what a seeding run did. Warnings are problems tolerated in
+// best-effort mode; failures make the run exit with status 1.
+type seedReport struct {
+	SeedLoaded  bool
+	InsertedIDs []int64
+	Verified    int
+	Failures    []string
+	Warnings    []string
+}
+
+func (r *seedReport) fail(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	log.Printf("ERROR: %s", msg)
+	r.Failures = append(r.Failures, msg)
+}
+
+func (r *seedReport) warn(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	log.Printf("Warning: %s", msg)
+	r.Warnings = append(r.Warnings, msg)
+}
+
 func main() {
+	seedFile := flag.String("seed-file", defaultSeedFile, "Path to the SQL file with the seed sources")
+	bestEffort := flag.Bool("best-effort", false, "Report verification problems as warnings instead of failing")
+	flag.Parse()
+
 	// Get database path from environment or use default
 	dbPath := os.Getenv("DATABASE_PATH")
 	if dbPath == "" {
@@ -26,80 +74,115 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
-	defer func() {
-		if err := database.Close(); err != nil {
-			log.Printf("Warning: Failed to close database: %v", err)
-		}
-	}()
 
 	fmt.Println("Database initialized successfully")
 
-	// Load seed data from SQL file
-	fmt.Println("Loading seed data from SQL file...")
-	seedSQL, err := os.ReadFile("testdata/seed/sources.sql")
-	if err != nil {
-		log.Fatalf("Failed to read seed data file: %v", err)
+	report := seed(database, *seedFile, testArticles, *bestEffort)
+	if err := database.Close(); err != nil {
+		log.Printf("Warning: Failed to close database: %v", err)
 	}
 
-	// Execute the seed SQL
-	_, err = database.Exec(string(seedSQL))
-	if err != nil {
-		log.Fatalf("Failed to execute seed data: %v", err)
+	printSummary(report)
+	if len(report.Failures) > 0 {
+		os.Exit(1)
 	}
-	fmt.Println("✓ Seed data loaded successfully")
+}
 
-	// Seed additional test articles for accessibility tests
-	testArticles := []struct {
-		title   string
-		content string
-	}{
-		{
-			title:   "Test Article for Accessibility Testing",
-			content: "This is test content for accessibility testing. It ensures that H1 elements have proper content and are visible to screen readers.",
-		},
-		{
-			title:   "Test Article 4 for Additional Testing",
-			content: "Additional test content for comprehensive testing.",
-		},
+// seed loads the seed SQL, inserts the test articles and verifies them, carrying on
+// past individual failures so that one bad step does not leave the database empty
+func seed(database *sqlx.DB, seedFile string, articles []testArticle, bestEffort bool) seedReport {
+	var report seedReport
+
+	fmt.Printf("Loading seed data from %s...\n", seedFile)
+	if err := loadSeedFile(database, seedFile); err != nil {
+		report.fail("%v", err)
+	} else {
+		report.SeedLoaded = true
+		fmt.Println("✓ Seed data loaded successfully")
 	}
 
-	fmt.Printf("Inserting %d additional test articles...\n", len(testArticles))
-
-	var insertedIDs []int64
-	for i, article := range testArticles {
+	fmt.Printf("Inserting %d additional test articles...\n", len(articles))
+	for i, article := range articles {
 		articleID, err := testing.InsertTestArticle(database, article.title, article.content)
 		if err != nil {
-			log.Fatalf("Failed to insert test article %d: %v", i+1, err)
+			report.warn("failed to insert test article %d (%q): %v", i+1, article.title, err)
+			continue
 		}
-		insertedIDs = append(insertedIDs, articleID)
+		report.InsertedIDs = append(report.InsertedIDs, articleID)
 		fmt.Printf("✓ Inserted article %d with ID: %d, Title: %s\n", i+1, articleID, article.title)
 	}
+	if failed := len(articles) - len(report.InsertedIDs); failed > 0 {
+		report.fail("%d of %d test articles could not be inserted", failed, len(articles))
+	}
 
-	// Verify articles were inserted by counting total articles
+	// Verification problems fail the run unless running in best-effort mode
+	verifyFailed := report.fail
+	if bestEffort {
+		verifyFailed = report.warn
+	}
 	var count int
-	err = database.Get(&count, "SELECT COUNT(*) FROM articles")
-	if err != nil {
-		log.Fatalf("Failed to count articles: %v", err)
+	if err := database.Get(&count, "SELECT COUNT(*) FROM articles"); err != nil {
+		verifyFailed("failed to count articles: %v", err)
+	} else {
+		fmt.Printf("✓ Database now contains %d total articles\n", count)
+	}
+	for _, id := range report.InsertedIDs {
+		if err := verifyArticle(database, id); err != nil {
+			verifyFailed("%v", err)
+			continue
+		}
+		report.Verified++
 	}
+	return report
+}
 
-	fmt.Printf("✓ Database now contains %d total articles\n", count)
+// loadSeedFile executes the seed SQL file, explaining where it was looked for when it
+// is missing
+func loadSeedFile(database *sqlx.DB, path string) error {
+	seedSQL, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		wd, _ := os.Getwd()
+		return fmt.Errorf("seed file %s not found (working directory %s); run from the repository root or pass -seed-file", path, wd)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read seed file %s: %w", path, err)
+	}
+	if _, err := database.Exec(string(seedSQL)); err != nil {
+		return fmt.Errorf("failed to execute seed file %s: %w", path, err)
+	}
+	return nil
+}
 
-	// Verify specific articles exist and have content
-	for _, id := range insertedIDs {
-		var article db.Article
-		err = database.Get(&article, "SELECT id, title, content FROM articles WHERE id = ?", id)
-		if err != nil {
-			log.Fatalf("Failed to verify article %d: %v", id, err)
-		}
-		if article.Title == "" {
-			log.Fatalf("Article %d has empty title", id)
-		}
-		if article.Content == "" {
-			log.Fatalf("Article %d has empty content", id)
-		}
-		fmt.Printf("✓ Verified article %d: title='%s', content_length=%d\n", id, article.Title, len(article.Content))
+// verifyArticle checks that a seeded article can be read back with a title and content
+func verifyArticle(database *sqlx.DB, id int64) error {
+	var article db.Article
+	if err := database.Get(&article, "SELECT id, title, content FROM articles WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to verify article %d: %w", id, err)
+	}
+	if article.Title == "" {
+		return fmt.Errorf("article %d has empty title", id)
+	}
+	if article.Content == "" {
+		return fmt.Errorf("article %d has empty content", id)
 	}
+	fmt.Printf("✓ Verified article %d: title='%s', content_length=%d\n", id, article.Title, len(article.Content))
+	return nil
+}
 
+func printSummary(report seedReport) {
+	fmt.Println("Seeding summary:")
+	fmt.Printf("  seed file loaded: %t\n", report.SeedLoaded)
+	fmt.Printf("  articles inserted: %d, verified: %d\n", len(report.InsertedIDs), report.Verified)
+	fmt.Printf("  inserted article IDs: %v\n", report.InsertedIDs)
+	for _, w := range report.Warnings {
+		fmt.Printf("  warning: %s\n", w)
+	}
+	for _, f := range report.Failures {
+		fmt.Printf("  failed: %s\n", f)
+	}
+	if len(report.Failures) > 0 {
+		fmt.Printf("❌ Test data seeding finished with %d failure(s)\n", len(report.Failures))
+		return
+	}
 	fmt.Println("✅ Test data seeding completed successfully!")
-	fmt.Printf("Inserted article IDs: %v\n", insertedIDs)
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeed(t *testing.T) {
+	dir := t.TempDir()
+	database, err := db.InitDB(filepath.Join(dir, "seed.db"))
+	require.NoError(t, err)
+	defer func() { _ = database.Close() }()
+
+	t.Run("MissingSeedFile", func(t *testing.T) {
+		report := seed(database, filepath.Join(dir, "missing.sql"), testArticles, false)
+		assert.False(t, report.SeedLoaded)
+		assert.Len(t, report.InsertedIDs, len(testArticles), "articles are still inserted")
+		assert.Equal(t, len(testArticles), report.Verified)
+		require.Len(t, report.Failures, 1)
+		assert.Contains(t, report.Failures[0], "not found")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		seedFile := filepath.Join(dir, "sources.sql")
+		require.NoError(t, os.WriteFile(seedFile, []byte("SELECT 1;"), 0o600))
+		report := seed(database, seedFile, testArticles, false)
+		assert.True(t, report.SeedLoaded)
+		assert.Empty(t, report.Failures)
+	})
+
+	t.Run("BestEffortVerification", func(t *testing.T) {
+		articles := []testArticle{{title: "Empty body", content: ""}}
+		report := seed(database, filepath.Join(dir, "missing.sql"), articles, true)
+		assert.Len(t, report.InsertedIDs, 1)
+		assert.Zero(t, report.Verified)
+		assert.Len(t, report.Warnings, 1, "empty content is only a warning")
+		assert.Len(t, report.Failures, 1, "the missing seed file still fails")
+
+		report = seed(database, filepath.Join(dir, "sources.sql"), articles, false)
+		assert.Len(t, report.Failures, 1)
+	})
+}
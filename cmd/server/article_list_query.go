@@ -0,0 +1,138 @@
+package main
+
+import (
+	"html/template"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/api"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+)
+
+// articleListDateLayout is the format of the from and to date filters, as sent by
+// <input type="date">
+const articleListDateLayout = "2006-01-02"
+
+// articleSortOption is one entry of the sort control
+type articleSortOption struct {
+	Value string
+	Label string
+}
+
+// articleSortOptions lists the sorts offered on the article list, default first
+var articleSortOptions = []articleSortOption{
+	{"", "Newest first"},
+	{db.ArticleSortScoreAsc, "Score: left to right"},
+	{db.ArticleSortScoreDesc, "Score: right to left"},
+	{db.ArticleSortConfidence, "Highest confidence"},
+}
+
+// articleListQuery holds the filters and sort of the article list. The listing page,
+// its HTMX fragment and load-more all read it the same way, and every link they render
+// carries it so a new page keeps the active filters.
+type articleListQuery struct {
+	Source string
+	Bias   string
+	// Sort is a db.ArticleSort value; empty means newest first
+	Sort string
+	// From and To are inclusive publication dates in articleListDateLayout
+	From string
+	To   string
+	Page int
+}
+
+// parseArticleListQuery reads the list query parameters. Unknown sorts, malformed
+// dates and bad page numbers are dropped rather than rejected, as the controls only
+// produce valid values.
+func parseArticleListQuery(c *gin.Context) articleListQuery {
+	q := articleListQuery{
+		Source: c.Query("source"),
+		Bias:   c.Query("bias"),
+		Sort:   c.Query("sort"),
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+	}
+	if q.Bias == "" {
+		q.Bias = c.Query("leaning") // Support both parameter names for backward compatibility
+	}
+	if q.Source == "all" {
+		q.Source = ""
+	}
+	if q.Bias == "all" {
+		q.Bias = ""
+	}
+	if q.Sort == db.ArticleSortNewest || !db.IsArticleSort(q.Sort) {
+		q.Sort = ""
+	}
+	if _, err := time.Parse(articleListDateLayout, q.From); err != nil {
+		q.From = ""
+	}
+	if _, err := time.Parse(articleListDateLayout, q.To); err != nil {
+		q.To = ""
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	q.Page = page
+	return q
+}
+
+// params converts the query into internal API parameters for a page of limit articles
+func (q articleListQuery) params(limit int) api.InternalArticlesParams {
+	params := api.InternalArticlesParams{
+		Source:  q.Source,
+		Leaning: q.Bias,
+		Sort:    q.Sort,
+		Limit:   limit,
+		Offset:  (q.Page - 1) * limit,
+	}
+	if from, err := time.Parse(articleListDateLayout, q.From); err == nil {
+		params.PublishedAfter = &from
+	}
+	if to, err := time.Parse(articleListDateLayout, q.To); err == nil {
+		// The end date is inclusive
+		before := to.AddDate(0, 0, 1)
+		params.PublishedBefore = &before
+	}
+	return params
+}
+
+// Encode returns the filters and sort as a query string without the page, for links
+// to other pages of the same list
+func (q articleListQuery) Encode() string {
+	v := url.Values{}
+	for key, value := range map[string]string{
+		"source": q.Source, "bias": q.Bias, "sort": q.Sort, "from": q.From, "to": q.To,
+	} {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	return v.Encode()
+}
+
+// templateData returns the values the list templates use to render the controls and
+// links, to be merged into the page data
+func (q articleListQuery) templateData() gin.H {
+	return gin.H{
+		"SelectedSource": q.Source,
+		"SelectedBias":   q.Bias,
+		"SelectedSort":   q.Sort,
+		"SelectedFrom":   q.From,
+		"SelectedTo":     q.To,
+		"SortOptions":    articleSortOptions,
+		"FilterQuery":    template.URL(q.Encode()), // #nosec G203 - url.Values encoding, safe in a query string
+		"Filtered":       q.Source != "" || q.Bias != "" || q.From != "" || q.To != "",
+	}
+}
+
+// withArticleListQuery merges the query's template data into data
+func withArticleListQuery(data gin.H, q articleListQuery) gin.H {
+	for key, value := range q.templateData() {
+		data[key] = value
+	}
+	return data
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleListQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	parse := func(rawQuery string) articleListQuery {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/htmx/articles?"+rawQuery, nil)
+		return parseArticleListQuery(c)
+	}
+
+	q := parse("source=BBC+News&leaning=left&sort=score_desc&from=2026-10-01&to=2026-10-07&page=3")
+	assert.Equal(t, articleListQuery{Source: "BBC News", Bias: "left", Sort: db.ArticleSortScoreDesc,
+		From: "2026-10-01", To: "2026-10-07", Page: 3}, q)
+
+	params := q.params(20)
+	assert.Equal(t, 40, params.Offset)
+	assert.Equal(t, db.ArticleSortScoreDesc, params.Sort)
+	require.NotNil(t, params.PublishedAfter)
+	require.NotNil(t, params.PublishedBefore)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), *params.PublishedAfter)
+	assert.Equal(t, time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC), *params.PublishedBefore, "the end date is inclusive")
+	assert.Equal(t, "bias=left&from=2026-10-01&sort=score_desc&source=BBC+News&to=2026-10-07", q.Encode())
+
+	assert.Equal(t, articleListQuery{Page: 1}, parse("source=all&bias=all&sort=newest&from=yesterday&to=2026-13-01&page=-2"),
+		"defaults and malformed values are dropped")
+	assert.Equal(t, articleListQuery{Page: 1}, parse("sort=bogus"))
+	assert.Empty(t, parse("").Encode())
+
+	// Load more keeps the filters and sort for the next page
+	tmpl := template.Must(template.New("").Funcs(templateFuncs()).ParseFiles("../../templates/fragments/article-items.html"))
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.ExecuteTemplate(&buf, "load-more-button", withArticleListQuery(gin.H{"HasMore": true, "NextPage": 4}, q)))
+	assert.Contains(t, buf.String(),
+		`hx-get="/htmx/articles/load-more?page=4&bias=left&amp;from=2026-10-01&amp;sort=score_desc&amp;source=BBC&#43;News&amp;to=2026-10-07"`)
+}
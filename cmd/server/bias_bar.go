@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+)
+
+// Bias bar geometry, in viewBox units: the track spans x 0-200 for scores -1 to 1
+const (
+	biasBarWidth  = 200
+	biasBarHeight = 20
+	biasBarTrackY = 7
+	biasBarTrackH = 6
+)
+
+// Bias bar colours, matching the .bias-left, .bias-center and .bias-right badges
+const (
+	biasBarLeftColor    = "#0a58ca"
+	biasBarCenterColor  = "#6c757d"
+	biasBarRightColor   = "#b02a37"
+	biasBarUnscoredFill = "#dee2e6"
+)
+
+// biasBarX maps a score in -1..1 to its x position on the track
+func biasBarX(score float64) float64 {
+	return (math.Max(-1, math.Min(1, score)) + 1) / 2 * biasBarWidth
+}
+
+// biasBar renders an inline SVG bar for the template function of the same name. The
+// track runs from left (-1) to right (1) with the center zone shaded, and a marker
+// sits at the score, fainter the lower the confidence. Articles without a score,
+// which the templates see as a zero score and zero confidence, get an empty dashed
+// track instead.
+func biasBar(score, confidence float64) template.HTML {
+	var b strings.Builder
+	unscored := math.IsNaN(score) || math.IsNaN(confidence) || (score == 0 && confidence == 0)
+
+	label := "Not scored yet"
+	if !unscored {
+		label = fmt.Sprintf("Bias score %.2f (%s), confidence %.0f%%",
+			score, models.BiasLabel(score), math.Max(0, math.Min(1, confidence))*100)
+	}
+	fmt.Fprintf(&b, `<svg class="bias-bar" viewBox="0 0 %d %d" width="%d" height="%d" role="img" aria-label="%s">`,
+		biasBarWidth, biasBarHeight, biasBarWidth/2, biasBarHeight, label)
+	fmt.Fprintf(&b, `<title>%s</title>`, label)
+
+	if unscored {
+		fmt.Fprintf(&b, `<rect x="0.5" y="%d" width="%d" height="%d" rx="3" fill="%s" stroke="%s" stroke-dasharray="4 3"/>`,
+			biasBarTrackY, biasBarWidth-1, biasBarTrackH, biasBarUnscoredFill, biasBarCenterColor)
+		b.WriteString(`</svg>`)
+		return template.HTML(b.String()) // #nosec G203 - built from numbers and fixed strings only
+	}
+
+	centerStart := biasBarX(-models.BiasLabelThreshold)
+	centerEnd := biasBarX(models.BiasLabelThreshold)
+	fmt.Fprintf(&b, `<rect x="0" y="%d" width="%.1f" height="%d" fill="%s" fill-opacity="0.35"/>`,
+		biasBarTrackY, centerStart, biasBarTrackH, biasBarLeftColor)
+	fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s" fill-opacity="0.35"/>`,
+		centerStart, biasBarTrackY, centerEnd-centerStart, biasBarTrackH, biasBarCenterColor)
+	fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s" fill-opacity="0.35"/>`,
+		centerEnd, biasBarTrackY, biasBarWidth-centerEnd, biasBarTrackH, biasBarRightColor)
+	fmt.Fprintf(&b, `<line x1="%d" y1="3" x2="%d" y2="17" stroke="%s" stroke-width="1"/>`,
+		biasBarWidth/2, biasBarWidth/2, biasBarCenterColor)
+
+	color := biasBarCenterColor
+	switch models.BiasLabel(score) {
+	case models.BiasLabelLeft:
+		color = biasBarLeftColor
+	case models.BiasLabelRight:
+		color = biasBarRightColor
+	}
+	// Low-confidence scores stay visible but faded
+	opacity := 0.3 + 0.7*math.Max(0, math.Min(1, confidence))
+	fmt.Fprintf(&b, `<circle cx="%.1f" cy="10" r="6" fill="%s" fill-opacity="%.2f" stroke="#fff" stroke-width="1.5"/>`,
+		math.Max(6, math.Min(biasBarWidth-6, biasBarX(score))), color, opacity)
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String()) // #nosec G203 - built from numbers and fixed strings only
+}
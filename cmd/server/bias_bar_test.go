@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBiasBar(t *testing.T) {
+	left := string(biasBar(-1, 1))
+	assert.Contains(t, left, `aria-label="Bias score -1.00 (left), confidence 100%"`)
+	assert.Contains(t, left, `<circle cx="6.0"`, "the marker stays inside the track at the ends")
+	assert.Contains(t, left, `fill="#0a58ca" fill-opacity="1.00"`)
+
+	right := string(biasBar(0.42, 0.5))
+	assert.Contains(t, right, `<circle cx="142.0"`)
+	assert.Contains(t, right, `fill="#b02a37" fill-opacity="0.65"`, "lower confidence fades the marker")
+
+	center := string(biasBar(0.05, 0.9))
+	assert.Contains(t, center, `(center)`)
+	assert.Contains(t, center, `<rect x="90.0" y="7" width="20.0"`, "the neutral zone is shaded")
+
+	assert.Contains(t, string(biasBar(3, 2)), `<circle cx="194.0"`, "out of range values are clamped")
+
+	for _, unscored := range []template.HTML{biasBar(0, 0), biasBar(math.NaN(), 0.5)} {
+		assert.Contains(t, string(unscored), `aria-label="Not scored yet"`)
+		assert.Contains(t, string(unscored), `stroke-dasharray`)
+		assert.NotContains(t, string(unscored), "<circle")
+	}
+
+	tmpl := template.Must(template.New("t").Funcs(templateFuncs()).Parse(`{{biasBar .CompositeScore .Confidence}}`))
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, struct{ CompositeScore, Confidence float64 }{-0.3, 0.8}))
+	assert.Contains(t, buf.String(), `<svg class="bias-bar"`, "the SVG is not escaped")
+}
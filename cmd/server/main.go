@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	_ "github.com/alexandru-savinov/BalancedNewsGo/docs" // This will import the generated docs
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/api"
@@ -26,6 +28,7 @@ import (
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/metrics"
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/rss"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/tracing"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -63,6 +66,10 @@ import (
 // @tag.name Analysis
 // @tag.description Operations related to article analysis and summaries
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+
 func main() {
 	// Check for health check flag
 	if len(os.Args) > 1 && os.Args[1] == "--health-check" {
@@ -115,19 +122,28 @@ func main() {
 	if err != nil {
 		log.Println("No .env file found or error loading .env file:", err)
 	}
+	// Tracing is a no-op unless OTEL_EXPORTER_OTLP_ENDPOINT is set
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		log.Printf("Warning: Failed to initialize tracing, continuing without it: %v", err)
+		shutdownTracing = func(context.Context) error { return nil }
+	}
+
 	// Initialize services
-	dbConn, llmClient, rssCollector, scoreManager, progressManager, simpleCache := initServices()
+	dbConn, llmClient, rssCollector, scoreManager, progressManager, apiCache := initServices()
 	defer func() { _ = dbConn.Close() }() // Initialize Gin
 	router := gin.Default()
+	router.Use(otelgin.Middleware(tracing.ServiceName))
+	// Gzip large responses (GZIP_ENABLED, GZIP_MIN_SIZE). Registered before the request
+	// logger so the logger sees uncompressed bodies.
+	router.Use(api.NewCompressorFromEnv().Middleware())
+	// Optional sampled request/response logging with redacted bodies (REQUEST_LOG_ENABLED)
+	router.Use(api.NewRequestLoggerFromEnv().Middleware())
+	// Cancel slow requests and answer 504 (REQUEST_TIMEOUT); SSE streams are exempt
+	router.Use(api.NewRequestTimeoutFromEnv().Middleware())
 
 	// Configure template function map
-	router.SetFuncMap(template.FuncMap{
-		"add":   func(a, b int) int { return a + b },
-		"sub":   func(a, b int) int { return a - b },
-		"mul":   func(a, b float64) float64 { return a * b },
-		"split": func(s, sep string) []string { return strings.Split(s, sep) },
-		"date":  func(t time.Time, layout string) string { return t.Format(layout) },
-	}) // Load HTML templates (skip in test mode if templates don't exist)
+	router.SetFuncMap(templateFuncs()) // Load HTML templates (skip in test mode if templates don't exist)
 	if os.Getenv("TEST_MODE") != "true" {
 		// router.LoadHTMLGlob("templates/*.html") // Load top-level html files
 		// router.LoadHTMLGlob("templates/fragments/*.html") // Load fragment html files
@@ -143,6 +159,7 @@ func main() {
 			"templates/fragments/error.html",
 			"templates/fragments/summary.html",
 			"templates/fragments/sources.html",
+			"templates/fragments/reanalysis-progress.html",
 		) // Load specific files
 	} else {
 		log.Println("TEST_MODE: Skipping template loading")
@@ -181,7 +198,7 @@ func main() {
 	router.GET("/", func(c *gin.Context) {
 		c.Redirect(302, "/articles")
 	}) // Initialize TemplateHandlers with database connection
-	templateHandlers := NewTemplateHandlers(dbConn)
+	templateHandlers := NewTemplateHandlers(dbConn, scoreManager)
 	router.GET("/articles", templateHandlers.TemplateIndexHandler())
 	router.GET("/article/:id", templateHandlers.TemplateArticleHandler())
 	router.GET("/admin", templateHandlers.TemplateAdminHandler())
@@ -189,26 +206,43 @@ func main() {
 	router.GET("/htmx/articles", templateHandlers.TemplateArticlesFragmentHandler())
 	router.GET("/htmx/articles/load-more", templateHandlers.TemplateArticlesLoadMoreHandler())
 	router.GET("/htmx/article/:id", templateHandlers.TemplateArticleFragmentHandler())
+	// Reanalysis progress for the article detail page, rendered as HTML for hx-sse
+	router.GET("/htmx/article/:id/reanalysis", templateHandlers.TemplateReanalysisPanelHandler())
+	router.GET("/htmx/article/:id/reanalysis/stream", templateHandlers.TemplateReanalysisStreamHandler())
+
+	// Articles stored by the collector and POST /api/articles are scored according to
+	// INGEST_SCORING_POLICY
+	ingestScorer := api.NewIngestScorerFromEnv(llmClient, dbConn, scoreManager)
+	rssCollector.SetIngestHook(ingestScorer)
+	// Articles left unscored are caught up in the background when SCORE_BACKFILL_ENABLED
+	// is set; the admin API starts and stops it at runtime
+	scoreBackfiller := api.NewScoreBackfillerFromEnv(llmClient, dbConn, scoreManager)
+	// Articles whose scoring the last shutdown interrupted are requeued either way
+	api.RequeuePendingRetries(llmClient, dbConn, scoreManager)
+	// Old articles are deleted in the background when RETENTION_MAX_AGE or
+	// RETENTION_MAX_ARTICLES is set
+	articleRetention := api.NewArticleRetentionFromEnv(dbConn)
 
 	// Register API routes on the router instance
 	// The ProgressManager handles progress tracking for LLM scoring jobs.
-	// The SimpleCache provides in-memory caching for API responses.
-	api.RegisterRoutes(router, dbConn, rssCollector, llmClient, scoreManager, progressManager, simpleCache)
+	// The API cache holds responses shared by the handlers.
+	api.RegisterRoutes(router, dbConn, rssCollector, llmClient, scoreManager, progressManager, apiCache, ingestScorer, scoreBackfiller)
 
 	// Metrics endpoints
 	router.GET("/metrics/validation", func(c *gin.Context) {
 		metrics, err := metrics.GetValidationMetrics(dbConn)
 		if err != nil {
-			c.JSON(500, gin.H{"error": err.Error()})
+			api.RespondError(c, err)
 			return
 		}
 		c.JSON(200, metrics)
 	})
 
 	router.GET("/metrics/feedback", func(c *gin.Context) {
-		summary, err := metrics.GetFeedbackSummary(dbConn)
+		excludeSpam, _ := strconv.ParseBool(c.Query("exclude_spam"))
+		summary, err := metrics.GetFeedbackSummary(dbConn, excludeSpam)
 		if err != nil {
-			c.JSON(500, gin.H{"error": err.Error()})
+			api.RespondError(c, err)
 			return
 		}
 		c.JSON(200, summary)
@@ -217,7 +251,7 @@ func main() {
 	router.GET("/metrics/uncertainty", func(c *gin.Context) {
 		rates, err := metrics.GetUncertaintyRates(dbConn)
 		if err != nil {
-			c.JSON(500, gin.H{"error": err.Error()})
+			api.RespondError(c, err)
 			return
 		}
 		c.JSON(200, rates)
@@ -226,7 +260,7 @@ func main() {
 	router.GET("/metrics/disagreements", func(c *gin.Context) {
 		disagreements, err := metrics.GetDisagreements(dbConn)
 		if err != nil {
-			c.JSON(500, gin.H{"error": err.Error()})
+			api.RespondError(c, err)
 			return
 		}
 		c.JSON(200, disagreements)
@@ -234,11 +268,84 @@ func main() {
 	router.GET("/metrics/outliers", func(c *gin.Context) {
 		outliers, err := metrics.GetOutlierScores(dbConn)
 		if err != nil {
-			c.JSON(500, gin.H{"error": err.Error()})
+			api.RespondError(c, err)
 			return
 		}
 		c.JSON(200, outliers)
 	})
+	// Variance of composite scores across reanalysis versions; ?threshold= overrides the
+	// variance above which an article is unstable, ?unstable_only=true drops stable ones
+	router.GET("/metrics/score-stability", func(c *gin.Context) {
+		threshold := metrics.DefaultStabilityThreshold
+		if v := c.Query("threshold"); v != "" {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil || parsed < 0 {
+				api.RespondError(c, api.NewAppError(api.ErrValidation, "threshold must be a non-negative number"))
+				return
+			}
+			threshold = parsed
+		}
+		unstableOnly, _ := strconv.ParseBool(c.Query("unstable_only"))
+		report, err := metrics.GetScoreStability(dbConn, threshold, unstableOnly)
+		if err != nil {
+			api.RespondError(c, err)
+			return
+		}
+		c.JSON(200, report)
+	})
+
+	router.GET("/metrics/outlier-models", func(c *gin.Context) {
+		models, err := metrics.GetOutlierModels(dbConn)
+		if err != nil {
+			api.RespondError(c, err)
+			return
+		}
+		c.JSON(200, models)
+	})
+
+	// Token usage and estimated spend of LLM calls; ?from= and ?to= are
+	// inclusive YYYY-MM-DD dates and prices come from model_prices in the config
+	router.GET("/metrics/llm-cost", func(c *gin.Context) {
+		var bounds [2]*time.Time
+		for i, param := range []string{"from", "to"} {
+			v := c.Query(param)
+			if v == "" {
+				continue
+			}
+			day, err := time.Parse(articleListDateLayout, v)
+			if err != nil {
+				api.RespondError(c, api.NewAppError(api.ErrValidation, param+" must be a date as YYYY-MM-DD"))
+				return
+			}
+			if param == "to" {
+				day = day.AddDate(0, 0, 1) // The end date is inclusive
+			}
+			bounds[i] = &day
+		}
+		if bounds[0] != nil && bounds[1] != nil && !bounds[0].Before(*bounds[1]) {
+			api.RespondError(c, api.NewAppError(api.ErrValidation, "from must not be after to"))
+			return
+		}
+		report, err := metrics.GetLLMCost(dbConn, bounds[0], bounds[1], llmClient.GetConfig().EstimateCost)
+		if err != nil {
+			api.RespondError(c, err)
+			return
+		}
+		c.JSON(200, report)
+	})
+
+	router.GET("/metrics/validation/latest", func(c *gin.Context) {
+		run, err := metrics.GetLatestValidationRun(dbConn)
+		if err != nil {
+			api.RespondError(c, err)
+			return
+		}
+		if run == nil {
+			api.RespondError(c, api.NewAppError(api.ErrNotFound, "No validation run recorded"))
+			return
+		}
+		c.JSON(200, run)
+	})
 
 	// Add Swagger route
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -277,10 +384,39 @@ func main() {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
 
+	// Stop queuing scoring work, then give the jobs in flight time to finish. Jobs
+	// still running after SCORING_SHUTDOWN_TIMEOUT are left pending_retry and
+	// requeued at the next startup.
+	ingestScorer.Stop()
+	scoreBackfiller.Stop()
+	articleRetention.Stop()
+	scoringCtx, scoringCancel := context.WithTimeout(context.Background(), llm.ShutdownTimeoutFromEnv())
+	if interrupted := scoreManager.Shutdown(scoringCtx); len(interrupted) > 0 {
+		log.Printf("Interrupted scoring of %d articles, left pending retry: %v", len(interrupted), interrupted)
+	}
+	scoringCancel()
+	progressManager.Stop()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
+
 	log.Println("Server exited")
 }
 
-func initServices() (*sqlx.DB, *llm.LLMClient, *rss.Collector, *llm.ScoreManager, *llm.ProgressManager, *api.SimpleCache) {
+// templateFuncs are the functions available to the HTML templates
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"add":   func(a, b int) int { return a + b },
+		"sub":   func(a, b int) int { return a - b },
+		"mul":   func(a, b float64) float64 { return a * b },
+		"split": func(s, sep string) []string { return strings.Split(s, sep) },
+		"date":  func(t time.Time, layout string) string { return t.Format(layout) },
+		// biasBar renders an inline SVG bias bar for a score and confidence
+		"biasBar": biasBar,
+	}
+}
+
+func initServices() (*sqlx.DB, *llm.LLMClient, *rss.Collector, *llm.ScoreManager, *llm.ProgressManager, api.Cache) {
 	// Load environment variables from .env file if present
 	err := godotenv.Load()
 	if err != nil {
@@ -308,6 +444,9 @@ func initServices() (*sqlx.DB, *llm.LLMClient, *rss.Collector, *llm.ScoreManager
 		log.Printf("ERROR: Failed to initialize LLM Client: %v", err)
 		os.Exit(1)
 	}
+	if mode := llm.EndpointCheckModeFromEnv(); mode != llm.EndpointCheckOff {
+		checkModelEndpoints(llmClient, mode)
+	}
 
 	// Initialize RSS collector with database sources
 	log.Println("Initializing RSS collector...")
@@ -378,24 +517,54 @@ func initServices() (*sqlx.DB, *llm.LLMClient, *rss.Collector, *llm.ScoreManager
 
 	// Initialize ScoreManager
 	llmAPICache := llm.NewCache() // This is the cache for the LLM service, distinct from the API cache.
-	calculator := &llm.DefaultScoreCalculator{}
+	calculator := llm.ScoreCalculatorFromEnv()
 	// ProgressManager handles progress tracking and cleanup for LLM scoring jobs.
 	// Use shorter cleanup interval in test environments for faster cleanup
 	cleanupInterval := time.Minute
 	if os.Getenv("TEST_MODE") == "true" || os.Getenv("NO_AUTO_ANALYZE") == "true" {
 		cleanupInterval = time.Second * 5 // Much shorter for tests
 	}
+	// main stops it once scoring jobs have drained on shutdown
 	progressManager := llm.NewProgressManager(cleanupInterval)
 
-	// Ensure ProgressManager is stopped when server shuts down
-	defer progressManager.Stop()
-
 	scoreManager := llm.NewScoreManager(dbConn, llmAPICache, calculator, progressManager)
+	scoreManager.SetMaxConcurrentJobs(llm.MaxConcurrentJobsFromEnv())
+
+	// The API cache holds responses (articles, summaries, etc). It is in-memory unless
+	// CACHE_BACKEND=redis selects the Redis cache shared between instances.
+	apiCache, err := api.NewCacheFromEnv()
+	if err != nil {
+		log.Printf("ERROR: Failed to initialize API cache: %v", err)
+		os.Exit(1)
+	}
 
-	// SimpleCache provides in-memory caching for API responses (articles, summaries, etc).
-	simpleAPICache := api.NewSimpleCache()
+	return dbConn, llmClient, collector, scoreManager, progressManager, apiCache
+}
 
-	return dbConn, llmClient, collector, scoreManager, progressManager, simpleAPICache
+// checkModelEndpoints probes every configured model endpoint and logs the ones that
+// did not answer, exiting when mode is fail so a misconfigured URL is caught before
+// any article is scored
+func checkModelEndpoints(llmClient *llm.LLMClient, mode string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	results, err := llmClient.CheckModelEndpoints(ctx)
+	if err != nil {
+		log.Printf("WARNING: Skipping model endpoint check: %v", err)
+		return
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Reachable {
+			log.Printf("Model endpoint OK: %s at %s (%s)", r.Model, r.URL, r.Reason)
+			continue
+		}
+		failed++
+		log.Printf("ERROR: Model endpoint check failed: %s at %s (HTTP %d): %s", r.Model, r.URL, r.StatusCode, r.Reason)
+	}
+	if failed > 0 && mode == llm.EndpointCheckFail {
+		log.Printf("ERROR: %d of %d model endpoints are unreachable (MODEL_ENDPOINT_CHECK=fail)", failed, len(results))
+		os.Exit(1)
+	}
 }
 
 // loadFeedSourcesConfig loads the feed sources configuration from multiple possible locations
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/api"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// reanalysisTemplatesPath holds the reanalysis-* fragments, which the progress stream
+// renders itself because gin can only render templates into the response
+const reanalysisTemplatesPath = "templates/fragments/reanalysis-progress.html"
+
+// reanalysisPollInterval is how often the stream checks the job's progress
+const reanalysisPollInterval = 250 * time.Millisecond
+
+// reanalysisView is the data for the reanalysis-* templates
+type reanalysisView struct {
+	ArticleID int64
+	Progress  *models.ProgressState
+	// Message is shown in the idle panel, e.g. how the last reanalysis ended
+	Message string
+	// Article carries the new score once a reanalysis completed
+	Article *api.InternalArticle
+}
+
+// loadReanalysisTemplates parses the reanalysis fragments
+func loadReanalysisTemplates(path string) (*template.Template, error) {
+	return template.New(filepath.Base(path)).Funcs(templateFuncs()).ParseFiles(path) // #nosec G304 - path is a constant, controlled input
+}
+
+// reanalysisProgress returns the article's current reanalysis state, or nil when there
+// is no job
+func (h *TemplateHandlers) reanalysisProgress(articleID int64) (*models.ProgressState, int64) {
+	if h.scoreManager == nil {
+		return nil, 0
+	}
+	return h.scoreManager.GetProgressWithEventID(articleID)
+}
+
+// TemplateReanalysisPanelHandler returns the live progress panel for the article detail
+// page. It is loaded when the reanalyze button's HX-Trigger arrives and subscribes to
+// TemplateReanalysisStreamHandler with hx-sse.
+func (h *TemplateHandlers) TemplateReanalysisPanelHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || id < 1 {
+			c.String(http.StatusBadRequest, "Invalid article ID")
+			return
+		}
+		if h.reanalysisTemplates == nil {
+			c.String(http.StatusServiceUnavailable, "Reanalysis progress is unavailable")
+			return
+		}
+
+		view := reanalysisView{ArticleID: id}
+		name := "reanalysis-panel"
+		progress, _ := h.reanalysisProgress(id)
+		switch {
+		case progress == nil:
+			name = "reanalysis-idle"
+			view.Message = "No reanalysis in progress."
+		case llm.IsTerminalProgressStatus(progress.Status):
+			// The job finished before the panel was requested
+			name = "reanalysis-done"
+			view = h.finishedView(c.Request.Context(), id, progress)
+		default:
+			view.Progress = progress
+		}
+
+		var buf bytes.Buffer
+		if err := h.reanalysisTemplates.ExecuteTemplate(&buf, name, view); err != nil {
+			log.Printf("[HTMX reanalysis %d] Error rendering %s: %v", id, name, err)
+			c.String(http.StatusInternalServerError, "Error rendering reanalysis progress")
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+	}
+}
+
+// TemplateReanalysisStreamHandler streams an article's reanalysis progress as HTML
+// fragments for hx-sse. Each change is sent as a "progress" event; when the job ends,
+// or there is none, a single "done" event carries the idle panel and the new score and
+// the stream closes. Swapping the panel out stops htmx from reconnecting.
+func (h *TemplateHandlers) TemplateReanalysisStreamHandler() gin.HandlerFunc {
+	heartbeatInterval := api.SSEHeartbeatInterval()
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || id < 1 {
+			c.String(http.StatusBadRequest, "Invalid article ID")
+			return
+		}
+		if h.reanalysisTemplates == nil {
+			c.String(http.StatusServiceUnavailable, "Reanalysis progress is unavailable")
+			return
+		}
+
+		c.Writer.Header().Set("Content-Type", "text/event-stream")
+		c.Writer.Header().Set("Cache-Control", "no-cache")
+		c.Writer.Header().Set("Connection", "keep-alive")
+		c.Writer.Flush()
+
+		ticker := time.NewTicker(reanalysisPollInterval)
+		defer ticker.Stop()
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		var lastEventID int64 = -1
+		// send reports the current state and returns false once the stream is finished
+		send := func() bool {
+			progress, eventID := h.reanalysisProgress(id)
+			if progress == nil {
+				h.writeReanalysisEvent(c, id, "done", "reanalysis-done",
+					reanalysisView{ArticleID: id, Message: "No reanalysis in progress."})
+				return false
+			}
+			if llm.IsTerminalProgressStatus(progress.Status) {
+				h.scoreManager.MarkProgressObserved(id)
+				h.writeReanalysisEvent(c, id, "done", "reanalysis-done", h.finishedView(c.Request.Context(), id, progress))
+				return false
+			}
+			if eventID == lastEventID {
+				return true
+			}
+			lastEventID = eventID
+			return h.writeReanalysisEvent(c, id, "progress", "reanalysis-progress", progress)
+		}
+
+		if !send() {
+			return
+		}
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(c.Writer, ":keepalive\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			case <-ticker.C:
+				if !send() {
+					return
+				}
+			}
+		}
+	}
+}
+
+// finishedView describes how a reanalysis ended, with the stored score on success
+func (h *TemplateHandlers) finishedView(ctx context.Context, articleID int64, progress *models.ProgressState) reanalysisView {
+	view := reanalysisView{ArticleID: articleID}
+	switch progress.Status {
+	case llm.ProgressStatusError:
+		view.Message = "Reanalysis failed: " + progress.Message
+	case llm.ProgressStatusCancelled:
+		view.Message = "Reanalysis cancelled."
+	case llm.ProgressStatusInterrupted:
+		view.Message = "Reanalysis interrupted by a server restart; it will be retried."
+	default:
+		view.Message = "Reanalysis complete."
+		if progress.Status == "Skipped" && progress.Message != "" {
+			view.Message = progress.Message
+		}
+		article, err := h.client.GetArticle(ctx, articleID)
+		if err != nil {
+			log.Printf("[HTMX reanalysis %d] Error loading the new score: %v", articleID, err)
+		} else {
+			view.Article = article
+		}
+	}
+	return view
+}
+
+// writeReanalysisEvent renders a fragment as an SSE event, prefixing every line with
+// "data:", and reports whether the write succeeded
+func (h *TemplateHandlers) writeReanalysisEvent(c *gin.Context, articleID int64, event, name string, data interface{}) bool {
+	var buf bytes.Buffer
+	if err := h.reanalysisTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("[HTMX reanalysis %d] Error rendering %s: %v", articleID, name, err)
+		return false
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "event: %s\n", event)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		fmt.Fprintf(&out, "data: %s\n", strings.TrimRight(line, "\r"))
+	}
+	out.WriteString("\n")
+	if _, err := c.Writer.WriteString(out.String()); err != nil {
+		log.Printf("[HTMX reanalysis %d] Error writing %s event: %v", articleID, event, err)
+		return false
+	}
+	c.Writer.Flush()
+	return true
+}
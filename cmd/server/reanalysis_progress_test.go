@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/api"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReanalysisProgressFragments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "reanalysis.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score, confidence)
+		VALUES ('bbc', CURRENT_TIMESTAMP, 'https://example.com/r', 'Title', 'Body', 0.42, 0.9)`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+
+	tmpl, err := loadReanalysisTemplates(filepath.Join("..", "..", reanalysisTemplatesPath))
+	require.NoError(t, err)
+	sm := llm.NewScoreManager(dbConn, llm.NewCache(), &llm.DefaultScoreCalculator{}, llm.NewProgressManager(time.Minute))
+	h := &TemplateHandlers{client: api.NewInternalAPIClient(dbConn), scoreManager: sm, reanalysisTemplates: tmpl}
+
+	router := gin.New()
+	router.GET("/htmx/article/:id/reanalysis", h.TemplateReanalysisPanelHandler())
+	router.GET("/htmx/article/:id/reanalysis/stream", h.TemplateReanalysisStreamHandler())
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	panelPath := "/htmx/article/" + strconv.FormatInt(articleID, 10) + "/reanalysis"
+
+	w := get(panelPath)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "No reanalysis in progress")
+	assert.NotContains(t, w.Body.String(), "hx-sse")
+
+	sm.SetProgress(articleID, &models.ProgressState{Status: llm.ProgressStatusInProgress, Step: "Scoring", Percent: 40})
+	w = get(panelPath)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `hx-sse="connect:/htmx/article/`+strconv.FormatInt(articleID, 10)+`/reanalysis/stream"`)
+	assert.Contains(t, w.Body.String(), `value="40"`)
+
+	// The job finishes while the stream is open
+	time.AfterFunc(2*reanalysisPollInterval, func() {
+		sm.SetProgress(articleID, &models.ProgressState{Status: llm.ProgressStatusSuccess, Step: "Complete", Percent: 100})
+	})
+	w = get(panelPath + "/stream")
+	body := w.Body.String()
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	require.Contains(t, body, "event: progress\ndata: <p><strong>Scoring</strong></p>\n")
+	require.Contains(t, body, "event: done\n")
+	done := body[strings.Index(body, "event: done"):]
+	assert.Contains(t, done, "Reanalysis complete.")
+	assert.Contains(t, done, `hx-swap-oob="true"`)
+	assert.Contains(t, done, "Right Leaning (0.42)")
+	for _, line := range strings.Split(strings.TrimSpace(done), "\n")[1:] {
+		assert.True(t, strings.HasPrefix(line, "data: "), "every line of a fragment is a data line: %q", line)
+	}
+
+	w = get("/htmx/article/abc/reanalysis/stream")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
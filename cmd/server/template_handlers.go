@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"html/template"
 	"net/http"
 	"strconv"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"log"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/api"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 )
@@ -19,13 +21,26 @@ import (
 // while avoiding circular dependencies
 type TemplateHandlers struct {
 	client *api.InternalAPIClient
+	// scoreManager reports reanalysis progress; may be nil
+	scoreManager *llm.ScoreManager
+	// reanalysisTemplates renders the reanalysis progress fragments; nil when they
+	// could not be loaded
+	reanalysisTemplates *template.Template
 }
 
 // NewTemplateHandlers creates a new handler instance with the internal API client
-func NewTemplateHandlers(dbConn *sqlx.DB) *TemplateHandlers {
-	return &TemplateHandlers{
-		client: api.NewInternalAPIClient(dbConn),
+func NewTemplateHandlers(dbConn *sqlx.DB, scoreManager *llm.ScoreManager) *TemplateHandlers {
+	h := &TemplateHandlers{
+		client:       api.NewInternalAPIClient(dbConn),
+		scoreManager: scoreManager,
 	}
+	tmpl, err := loadReanalysisTemplates(reanalysisTemplatesPath)
+	if err != nil {
+		log.Printf("[WARN] Reanalysis progress fragments unavailable: %v", err)
+	} else {
+		h.reanalysisTemplates = tmpl
+	}
+	return h
 }
 
 // TemplateIndexHandler handles the articles listing page using internal API client
@@ -34,66 +49,39 @@ func (h *TemplateHandlers) TemplateIndexHandler() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		// Get query parameters for filtering
-		source := c.Query("source")
-		bias := c.Query("bias")
-		if bias == "" {
-			bias = c.Query("leaning") // Support both parameter names for backward compatibility
-		}
+		// Get query parameters for filtering and sorting
+		listQuery := parseArticleListQuery(c)
+		source := listQuery.Source
 		query := c.Query("query")
-		pageStr := c.DefaultQuery("page", "1")
-
-		page, err := strconv.Atoi(pageStr)
-		if err != nil || page < 1 {
-			page = 1
-		}
+		page := listQuery.Page
 
 		limit := 20 // Articles per page
-		offset := (page - 1) * limit
 		// Build API parameters
-		params := api.InternalArticlesParams{
-			Limit:  limit,
-			Offset: offset,
-		}
-
-		if source != "" {
-			params.Source = source
-		}
-
-		if bias != "" {
-			params.Leaning = bias // Use Leaning field instead of Bias
-		}
+		params := listQuery.params(limit)
 
 		// Note: Query parameter is not supported in current internal API client
 		// This would need to be added to the API later
 
 		// Get articles from internal API client
-		articles, err := h.client.GetArticles(ctx, params)
+		articles, pagination, err := h.client.GetArticlesPage(ctx, params)
 		if err != nil {
 			log.Printf("[DEBUG] TemplateIndexHandler ERROR path - Error fetching articles: %v", err)
-			c.HTML(http.StatusInternalServerError, "articles.html", gin.H{
-				"Error":          "Error fetching articles: " + err.Error(),
-				"Articles":       []api.InternalArticle{}, // Pass empty slice
-				"Sources":        []string{},
-				"SearchQuery":    query,
-				"SelectedSource": source,
-				"SelectedBias":   bias,
-				"CurrentPage":    1,        // Default value
-				"TotalPages":     1,        // Default value
-				"Pages":          []int{1}, // Default value
-				"PrevPage":       0,        // Default value
-				"NextPage":       0,        // Default value
-			})
+			c.HTML(http.StatusInternalServerError, "articles.html", withArticleListQuery(gin.H{
+				"Error":       "Error fetching articles: " + err.Error(),
+				"Articles":    []api.InternalArticle{}, // Pass empty slice
+				"Sources":     []string{},
+				"SearchQuery": query,
+				"CurrentPage": 1,        // Default value
+				"TotalPages":  1,        // Default value
+				"Pages":       []int{1}, // Default value
+				"PrevPage":    0,        // Default value
+				"NextPage":    0,        // Default value
+			}, listQuery))
 			log.Printf("[DEBUG] TemplateIndexHandler ERROR path - Error fetching articles: %v. CurrentPage type: %T, value: %v", err, 1, 1) // DEBUG
 			return
 		}
 
-		// Simplified pagination since we don't have a count endpoint yet
-		// Estimate based on whether we got a full page
-		totalCount := offset + len(articles)
-		if len(articles) == limit {
-			totalCount += 1 // Assume there's at least one more page
-		}
+		totalCount := pagination.Total
 
 		totalPages := (totalCount + limit - 1) / limit
 
@@ -115,18 +103,23 @@ func (h *TemplateHandlers) TemplateIndexHandler() gin.HandlerFunc {
 			sources = append(sources, s)
 		}
 
-		c.HTML(http.StatusOK, "articles.html", gin.H{
-			"Articles":       articles,
-			"Sources":        sources,
-			"SearchQuery":    query,
-			"SelectedSource": source,
-			"SelectedBias":   bias,
-			"CurrentPage":    page,
-			"TotalPages":     totalPages,
-			"Pages":          pages,
-			"PrevPage":       page - 1,
-			"NextPage":       page + 1,
-		})
+		// Keep the selected source available after filtering down to it
+		if source != "" && !sourceSet[source] {
+			sources = append(sources, source)
+		}
+
+		c.HTML(http.StatusOK, "articles.html", withArticleListQuery(gin.H{
+			"Articles":     articles,
+			"Sources":      sources,
+			"SearchQuery":  query,
+			"CurrentPage":  page,
+			"TotalPages":   totalPages,
+			"TotalResults": totalCount,
+			"Pages":        pages,
+			"PrevPage":     page - 1,
+			"NextPage":     page + 1,
+			"HasMore":      pagination.HasMore,
+		}, listQuery))
 		log.Printf("[DEBUG] TemplateIndexHandler SUCCESS path - CurrentPage type: %T, value: %v", page, page) // DEBUG
 	}
 }
@@ -225,38 +218,15 @@ func (h *TemplateHandlers) TemplateArticlesFragmentHandler() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		// Get query parameters for filtering (same as main handler)
-		source := c.Query("source")
-		bias := c.Query("bias")
-		if bias == "" {
-			bias = c.Query("leaning")
-		}
+		// Get query parameters for filtering and sorting (same as main handler)
+		listQuery := parseArticleListQuery(c)
 		query := c.Query("query")
-		pageStr := c.DefaultQuery("page", "1")
-
-		page, err := strconv.Atoi(pageStr)
-		if err != nil || page < 1 {
-			page = 1
-		}
+		page := listQuery.Page
 
 		limit := 20
-		offset := (page - 1) * limit
 
-		// Build API parameters
-		params := api.InternalArticlesParams{
-			Limit:  limit,
-			Offset: offset,
-		}
-
-		if source != "" {
-			params.Source = source
-		}
-
-		if bias != "" {
-			params.Leaning = bias
-		}
 		// Get articles from API
-		articles, err := h.client.GetArticles(ctx, params)
+		articles, pagination, err := h.client.GetArticlesPage(ctx, listQuery.params(limit))
 		if err != nil {
 			c.HTML(http.StatusInternalServerError, "article-list-fragment", gin.H{
 				"Error": "Error fetching articles: " + err.Error(),
@@ -264,11 +234,7 @@ func (h *TemplateHandlers) TemplateArticlesFragmentHandler() gin.HandlerFunc {
 			return
 		}
 
-		// Calculate pagination (simplified)
-		totalCount := offset + len(articles)
-		if len(articles) == limit {
-			totalCount += 1
-		}
+		totalCount := pagination.Total
 		totalPages := (totalCount + limit - 1) / limit
 
 		var pages []int
@@ -278,17 +244,16 @@ func (h *TemplateHandlers) TemplateArticlesFragmentHandler() gin.HandlerFunc {
 			pages = append(pages, i)
 		}
 		// Return just the fragment
-		c.HTML(http.StatusOK, "article-list-fragment", gin.H{
-			"Articles":       articles,
-			"SearchQuery":    query,
-			"SelectedSource": source,
-			"SelectedBias":   bias,
-			"CurrentPage":    page,
-			"TotalPages":     totalPages,
-			"Pages":          pages,
-			"PrevPage":       page - 1,
-			"NextPage":       page + 1,
-		})
+		c.HTML(http.StatusOK, "article-list-fragment", withArticleListQuery(gin.H{
+			"Articles":    articles,
+			"SearchQuery": query,
+			"CurrentPage": page,
+			"TotalPages":  totalPages,
+			"Pages":       pages,
+			"PrevPage":    page - 1,
+			"NextPage":    page + 1,
+			"HasMore":     pagination.HasMore,
+		}, listQuery))
 	}
 }
 
@@ -336,10 +301,16 @@ func (h *TemplateHandlers) TemplateArticleFragmentHandler() gin.HandlerFunc {
 			stats = make(map[string]interface{})
 		}
 
+		// A reanalysis already running is followed straight away
+		progress, _ := h.reanalysisProgress(int64(id))
+		reanalyzing := progress != nil && !llm.IsTerminalProgressStatus(progress.Status)
+
 		c.HTML(http.StatusOK, "article_htmx.html", gin.H{
 			"Article":        article,
 			"RecentArticles": filteredRecent,
 			"Stats":          stats,
+			"Reanalyzing":    reanalyzing,
+			"Reanalysis":     reanalysisView{ArticleID: int64(id), Progress: progress},
 		})
 	}
 }
@@ -628,38 +599,12 @@ func (h *TemplateHandlers) TemplateArticlesLoadMoreHandler() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		// Get query parameters for filtering (same as main handler)
-		source := c.Query("source")
-		bias := c.Query("bias")
-		if bias == "" {
-			bias = c.Query("leaning")
-		}
-		pageStr := c.DefaultQuery("page", "1")
-
-		page, err := strconv.Atoi(pageStr)
-		if err != nil || page < 1 {
-			page = 1
-		}
-
+		// Get query parameters for filtering and sorting (same as main handler)
+		listQuery := parseArticleListQuery(c)
 		limit := 20
-		offset := (page - 1) * limit
-
-		// Build API parameters
-		params := api.InternalArticlesParams{
-			Limit:  limit,
-			Offset: offset,
-		}
-
-		if source != "" {
-			params.Source = source
-		}
-
-		if bias != "" {
-			params.Leaning = bias
-		}
 
 		// Get articles from API
-		articles, err := h.client.GetArticles(ctx, params)
+		articles, pagination, err := h.client.GetArticlesPage(ctx, listQuery.params(limit))
 		if err != nil {
 			c.HTML(http.StatusInternalServerError, "article-items-fragment", gin.H{
 				"Error": "Error fetching articles: " + err.Error(),
@@ -667,10 +612,14 @@ func (h *TemplateHandlers) TemplateArticlesLoadMoreHandler() gin.HandlerFunc {
 			return
 		}
 
-		// Return just the article items for appending
-		c.HTML(http.StatusOK, "article-items-fragment", gin.H{
+		// Return just the article items for appending, with the load more button
+		// refreshed for the next page or removed after the last one
+		c.HTML(http.StatusOK, "article-items-fragment", withArticleListQuery(gin.H{
 			"Articles": articles,
-		})
+			"HasMore":  pagination.HasMore,
+			"NextPage": listQuery.Page + 1,
+			"LoadMore": true,
+		}, listQuery))
 	}
 }
 
@@ -712,7 +661,7 @@ func (h *TemplateHandlers) TemplateIndexHTMXHandler() gin.HandlerFunc {
 		}
 
 		// Get articles from internal API client
-		articles, err := h.client.GetArticles(ctx, params)
+		articles, pagination, err := h.client.GetArticlesPage(ctx, params)
 		if err != nil {
 			log.Printf("[DEBUG] TemplateIndexHTMXHandler ERROR path - Error fetching articles: %v", err)
 			c.HTML(http.StatusInternalServerError, "articles_htmx.html", gin.H{
@@ -731,12 +680,7 @@ func (h *TemplateHandlers) TemplateIndexHTMXHandler() gin.HandlerFunc {
 			return
 		}
 
-		// Simplified pagination since we don't have a count endpoint yet
-		// Estimate based on whether we got a full page
-		totalCount := offset + len(articles)
-		if len(articles) == limit {
-			totalCount += 1 // Assume there's at least one more page
-		}
+		totalCount := pagination.Total
 
 		totalPages := (totalCount + limit - 1) / limit
 
@@ -770,6 +714,7 @@ func (h *TemplateHandlers) TemplateIndexHTMXHandler() gin.HandlerFunc {
 			"Pages":          pages,
 			"PrevPage":       page - 1,
 			"NextPage":       page + 1,
+			"HasMore":        pagination.HasMore,
 		})
 		log.Printf("[DEBUG] TemplateIndexHTMXHandler SUCCESS path - CurrentPage type: %T, value: %v", page, page)
 	}
@@ -46,6 +46,8 @@ func main() {
 	testArticle := &db.Article{
 		ID:      1,
 		Title:   "Test Article",
+		Source:  "test_llm",
+		PubDate: time.Now(),
 		Content: "This is a test article that should be fairly neutral in its political bias.",
 	}
 
@@ -53,9 +55,10 @@ func main() {
 	promptVariant := llm.PromptVariant{
 		ID:    "test",
 		Model: "mistralai/mistral-small-3.1-24b-instruct", // Switch to Mistral model
-		Template: "Please analyze the political bias of the following article on a scale from -1.0 (strongly left) " +
+		SystemPrompt: "You are a media bias analyst. Rate political bias on a scale from -1.0 (strongly left) " +
 			"to 1.0 (strongly right). Respond ONLY with a valid JSON object containing 'score', 'explanation', " +
-			"and 'confidence'. Article: {{content}}",
+			"and 'confidence'.",
+		Template: "Title: {{title}}\nSource: {{source}}\nPublished: {{published}}",
 		Examples: []string{
 			`{"score": 0.0, "explanation": "This article appears neutral in its political bias", "confidence": 0.9}`,
 		},
@@ -6,8 +6,24 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 )
 
+// templateFuncs stands in for the functions the server registers, so templates that
+// call them parse
+var templateFuncs = template.FuncMap{
+	"add":     func(a, b int) int { return a + b },
+	"sub":     func(a, b int) int { return a - b },
+	"mul":     func(a, b float64) float64 { return a * b },
+	"split":   func(s, sep string) []string { return nil },
+	"date":    func(t time.Time, layout string) string { return "" },
+	"biasBar": func(score, confidence float64) template.HTML { return "" },
+}
+
+func parseTemplate(path string) (*template.Template, error) {
+	return template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+}
+
 func main() {
 	// Test template parsing to ensure our changes don't break Go template compilation
 	fmt.Println("🧪 Testing Template Compilation...")
@@ -27,7 +43,7 @@ func main() {
 			continue
 		}
 
-		_, err := template.ParseFiles(tmplPath)
+		_, err := parseTemplate(tmplPath)
 		if err != nil {
 			fmt.Printf("❌ Template compilation failed: %s\n", tmplPath)
 			fmt.Printf("   Error: %v\n", err)
@@ -45,7 +61,7 @@ func main() {
 		}
 
 		if filepath.Ext(path) == ".html" {
-			_, parseErr := template.ParseFiles(path)
+			_, parseErr := parseTemplate(path)
 			if parseErr != nil {
 				fmt.Printf("❌ Fragment template compilation failed: %s\n", path)
 				fmt.Printf("   Error: %v\n", parseErr)
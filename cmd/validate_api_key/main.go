@@ -1,7 +1,10 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"time"
@@ -10,7 +13,28 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// keyCheckResult is the outcome of a key validation, emitted as-is in --json mode
+type keyCheckResult struct {
+	Valid      bool   `json:"valid"`
+	StatusCode int    `json:"status_code"`
+	Reason     string `json:"reason"`
+	body       string
+}
+
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdout))
+}
+
+// run checks the key and reports the result on stdout, returning the exit code: 0 when
+// the key is valid, 1 otherwise
+func run(args []string, stdout io.Writer) int {
+	flags := flag.NewFlagSet("validate_api_key", flag.ContinueOnError)
+	jsonOutput := flags.Bool("json", false, "Print the result as JSON instead of human-readable output")
+	keyFlag := flags.String("key", "", "API key to check (defaults to LLM_API_KEY)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
 	// Load .env file
 	err := godotenv.Load()
 	if err != nil {
@@ -18,17 +42,24 @@ func main() {
 	}
 
 	// Get API key
-	apiKey := os.Getenv("LLM_API_KEY")
+	apiKey := *keyFlag
 	if apiKey == "" {
-		fmt.Println("❌ ERROR: LLM_API_KEY not set")
-		fmt.Println("Please set your OpenRouter API key in the .env file")
-		fmt.Println("Get a new API key from: https://openrouter.ai/")
-		os.Exit(1)
+		apiKey = os.Getenv("LLM_API_KEY")
+	}
+	if apiKey == "" {
+		if *jsonOutput {
+			return writeJSON(stdout, keyCheckResult{Reason: "LLM_API_KEY not set"})
+		}
+		fmt.Fprintln(stdout, "❌ ERROR: LLM_API_KEY not set")
+		fmt.Fprintln(stdout, "Please set your OpenRouter API key in the .env file or pass --key")
+		fmt.Fprintln(stdout, "Get a new API key from: https://openrouter.ai/")
+		return 1
 	}
 
-	// Mask the API key for display
-	maskedKey := maskKey(apiKey)
-	fmt.Printf("🔑 Testing API key: %s\n", maskedKey)
+	if !*jsonOutput {
+		// Mask the API key for display
+		fmt.Fprintf(stdout, "🔑 Testing API key: %s\n", maskKey(apiKey))
+	}
 
 	// Test the API key
 	baseURL := os.Getenv("LLM_BASE_URL")
@@ -39,7 +70,19 @@ func main() {
 	client := resty.New()
 	client.SetTimeout(30 * time.Second)
 
-	// Make a simple test request
+	result := checkKey(client, baseURL, apiKey)
+	if *jsonOutput {
+		return writeJSON(stdout, result)
+	}
+	printResult(stdout, result)
+	if !result.Valid {
+		return 1
+	}
+	return 0
+}
+
+// checkKey sends a minimal completion request and classifies the response
+func checkKey(client *resty.Client, baseURL, apiKey string) keyCheckResult {
 	resp, err := client.R().
 		SetAuthToken(apiKey).
 		SetHeader("Content-Type", "application/json").
@@ -53,39 +96,66 @@ func main() {
 			"max_tokens": 1,
 		}).
 		Post(baseURL + "/chat/completions")
-
 	if err != nil {
-		fmt.Printf("❌ ERROR: Network error: %v\n", err)
-		fmt.Println("Check your internet connection and try again")
-		os.Exit(1)
+		return keyCheckResult{Reason: fmt.Sprintf("network error: %v", err)}
 	}
 
-	// Check response status
+	result := keyCheckResult{StatusCode: resp.StatusCode(), body: resp.String()}
 	switch resp.StatusCode() {
 	case 200, 201:
-		fmt.Println("✅ SUCCESS: API key is valid and working!")
-		fmt.Println("Your reanalysis functionality should work properly.")
+		result.Valid = true
+		result.Reason = "ok"
+	case 401:
+		result.Reason = "invalid api key"
+	case 402:
+		result.Reason = "payment required"
+	case 429:
+		result.Reason = "rate limited"
+	default:
+		result.Reason = "unexpected response"
+	}
+	return result
+}
+
+// printResult prints the human-readable report for a check
+func printResult(w io.Writer, result keyCheckResult) {
+	switch result.StatusCode {
+	case 0:
+		fmt.Fprintf(w, "❌ ERROR: %s\n", result.Reason)
+		fmt.Fprintln(w, "Check your internet connection and try again")
+	case 200, 201:
+		fmt.Fprintln(w, "✅ SUCCESS: API key is valid and working!")
+		fmt.Fprintln(w, "Your reanalysis functionality should work properly.")
 	case 401:
-		fmt.Println("❌ ERROR: Invalid API key (HTTP 401)")
-		fmt.Println("Your API key is invalid or has been disabled.")
-		fmt.Println("Please get a new API key from: https://openrouter.ai/")
-		fmt.Println("Update the LLM_API_KEY in your .env file")
+		fmt.Fprintln(w, "❌ ERROR: Invalid API key (HTTP 401)")
+		fmt.Fprintln(w, "Your API key is invalid or has been disabled.")
+		fmt.Fprintln(w, "Please get a new API key from: https://openrouter.ai/")
+		fmt.Fprintln(w, "Update the LLM_API_KEY in your .env file")
 	case 402:
-		fmt.Println("❌ ERROR: Payment required (HTTP 402)")
-		fmt.Println("Your OpenRouter account has insufficient credits.")
-		fmt.Println("Please add credits to your account at: https://openrouter.ai/")
+		fmt.Fprintln(w, "❌ ERROR: Payment required (HTTP 402)")
+		fmt.Fprintln(w, "Your OpenRouter account has insufficient credits.")
+		fmt.Fprintln(w, "Please add credits to your account at: https://openrouter.ai/")
 	case 429:
-		fmt.Println("⚠️  WARNING: Rate limited (HTTP 429)")
-		fmt.Println("You're making too many requests. Try again in a few minutes.")
+		fmt.Fprintln(w, "⚠️  WARNING: Rate limited (HTTP 429)")
+		fmt.Fprintln(w, "You're making too many requests. Try again in a few minutes.")
 	default:
-		fmt.Printf("❌ ERROR: Unexpected response (HTTP %d)\n", resp.StatusCode())
-		fmt.Printf("Response: %s\n", resp.String())
-		fmt.Println("Please check the OpenRouter service status")
+		fmt.Fprintf(w, "❌ ERROR: Unexpected response (HTTP %d)\n", result.StatusCode)
+		fmt.Fprintf(w, "Response: %s\n", result.body)
+		fmt.Fprintln(w, "Please check the OpenRouter service status")
 	}
+}
 
-	if resp.StatusCode() != 200 && resp.StatusCode() != 201 {
-		os.Exit(1)
+// writeJSON prints the result as JSON and returns the exit code, non-zero unless the
+// key is valid
+func writeJSON(w io.Writer, result keyCheckResult) int {
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("Failed to encode result: %v", err)
+		return 1
+	}
+	if !result.Valid {
+		return 1
 	}
+	return 0
 }
 
 // maskKey masks an API key for logging purposes
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keyServer answers chat completions with status for every key but "good"
+func keyServer(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer good" {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"choices":[]}`))
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"error":"nope"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckKey(t *testing.T) {
+	tests := []struct {
+		status int
+		reason string
+	}{
+		{http.StatusUnauthorized, "invalid api key"},
+		{http.StatusPaymentRequired, "payment required"},
+		{http.StatusTooManyRequests, "rate limited"},
+		{http.StatusInternalServerError, "unexpected response"},
+	}
+	for _, tt := range tests {
+		server := keyServer(t, tt.status)
+		result := checkKey(resty.New(), server.URL, "bad")
+		assert.False(t, result.Valid)
+		assert.Equal(t, tt.status, result.StatusCode)
+		assert.Equal(t, tt.reason, result.Reason)
+	}
+
+	server := keyServer(t, http.StatusUnauthorized)
+	result := checkKey(resty.New(), server.URL, "good")
+	assert.True(t, result.Valid)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+
+	server.Close()
+	result = checkKey(resty.New(), server.URL, "good")
+	assert.False(t, result.Valid)
+	assert.Zero(t, result.StatusCode)
+	assert.Contains(t, result.Reason, "network error")
+}
+
+func TestRunJSON(t *testing.T) {
+	server := keyServer(t, http.StatusUnauthorized)
+	t.Setenv("LLM_BASE_URL", server.URL)
+	t.Setenv("LLM_API_KEY", "bad")
+
+	var out bytes.Buffer
+	assert.Equal(t, 0, run([]string{"--json", "--key", "good"}, &out), "--key takes precedence over LLM_API_KEY")
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	assert.Equal(t, map[string]any{"valid": true, "status_code": float64(200), "reason": "ok"}, result)
+
+	out.Reset()
+	assert.Equal(t, 1, run([]string{"--json"}, &out))
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	assert.Equal(t, false, result["valid"])
+	assert.Equal(t, float64(http.StatusUnauthorized), result["status_code"])
+
+	t.Setenv("LLM_API_KEY", "")
+	out.Reset()
+	assert.Equal(t, 1, run([]string{"--json"}, &out))
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	assert.Equal(t, "LLM_API_KEY not set", result["reason"])
+}
+
+func TestRunPrettyOutput(t *testing.T) {
+	server := keyServer(t, http.StatusPaymentRequired)
+	t.Setenv("LLM_BASE_URL", server.URL)
+
+	var out bytes.Buffer
+	assert.Equal(t, 0, run([]string{"--key", "good"}, &out))
+	assert.Contains(t, out.String(), "SUCCESS")
+	assert.NotContains(t, out.String(), "good", "the key is masked")
+
+	out.Reset()
+	assert.Equal(t, 1, run([]string{"--key", "bad-key-123456"}, &out))
+	assert.Contains(t, out.String(), "HTTP 402")
+	assert.Contains(t, out.String(), "bad-****3456")
+}
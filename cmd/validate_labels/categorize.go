@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+)
+
+// Where a flagged case's error category came from
+const (
+	CategorySourceAuto   = "auto"
+	CategorySourceManual = "manual"
+)
+
+// categorizer fills in FlaggedCase.ErrorCategory. Manual overrides, keyed by label ID,
+// win over the heuristics, which only run when auto is set.
+type categorizer struct {
+	auto      bool
+	overrides map[int64]string
+}
+
+// loadCategoryOverrides reads a JSON object mapping label IDs to error categories,
+// e.g. {"12": "data_noise"}
+func loadCategoryOverrides(path string) (map[int64]string, error) {
+	b, err := os.ReadFile(path) // #nosec G304 - path is from command line argument, controlled input
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	overrides := make(map[int64]string, len(raw))
+	for key, category := range raw {
+		id, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label ID %q in %s", key, path)
+		}
+		if category == "" || !db.ValidErrorCategory(category) {
+			return nil, fmt.Errorf("invalid error category %q for label %d in %s", category, id, path)
+		}
+		overrides[id] = category
+	}
+	return overrides, nil
+}
+
+// categorize sets the case's error category from an override or, failing that, the
+// heuristics. analysisErr is the scoring error and metadata the ensemble metadata of a
+// successful score.
+func (cz categorizer) categorize(fc *FlaggedCase, analysisErr error, metadata string) {
+	if category, ok := cz.overrides[fc.ID]; ok {
+		fc.ErrorCategory, fc.CategorySource, fc.CategoryReason = category, CategorySourceManual, "override"
+		return
+	}
+	if !cz.auto {
+		return
+	}
+	if category, reason := autoCategory(fc, analysisErr, metadata); category != "" {
+		fc.ErrorCategory, fc.CategorySource, fc.CategoryReason = category, CategorySourceAuto, reason
+	}
+}
+
+// autoCategory guesses why a case was flagged. In order:
+//   - analysis failed: unusable responses are a prompt_issue, anything else such as an
+//     unavailable or rate limited service a model_failure
+//   - true and predicted labels differ only between neutral and a side: data_noise,
+//     since such borderline labels are often inconsistent
+//   - the ensemble flagged itself uncertain because models disagreed or varied between
+//     attempts: model_failure; because confidence was low: prompt_issue
+//
+// Anything else, such as a confident prediction of the opposite side, is left for a
+// reviewer.
+func autoCategory(fc *FlaggedCase, analysisErr error, metadata string) (string, string) {
+	if analysisErr != nil {
+		msg := strings.ToLower(analysisErr.Error())
+		if errors.Is(analysisErr, llm.ErrInvalidLLMResponse) || strings.Contains(msg, "parse") ||
+			strings.Contains(msg, "no valid high-confidence") || errors.Is(analysisErr, llm.ErrAllScoresZeroConfidence) {
+			return db.ErrorCategoryPromptIssue, "model responses could not be used"
+		}
+		return db.ErrorCategoryModelFailure, "analysis failed"
+	}
+
+	if fc.Disagreement && (fc.TrueLabel == LabelNeutral) != (fc.PredictedLabel == LabelNeutral) {
+		return db.ErrorCategoryDataNoise, "neutral versus " + sideOf(fc.TrueLabel, fc.PredictedLabel)
+	}
+
+	for _, reason := range uncertaintyReasons(metadata) {
+		switch reason {
+		case llm.UncertaintyModelDisagreement, llm.UncertaintyHighVariance:
+			return db.ErrorCategoryModelFailure, "uncertain: " + reason
+		case llm.UncertaintyLowConfidence:
+			return db.ErrorCategoryPromptIssue, "uncertain: " + reason
+		}
+	}
+	return "", ""
+}
+
+// sideOf returns whichever of two labels is not neutral
+func sideOf(a, b string) string {
+	if a == LabelNeutral {
+		return b
+	}
+	return a
+}
+
+// uncertaintyReasons extracts final_aggregation.uncertainty_reasons from ensemble metadata
+func uncertaintyReasons(metadata string) []string {
+	if metadata == "" {
+		return nil
+	}
+	var meta struct {
+		FinalAggregation struct {
+			UncertaintyReasons []string `json:"uncertainty_reasons"`
+		} `json:"final_aggregation"`
+	}
+	if err := json.Unmarshal([]byte(metadata), &meta); err != nil {
+		return nil
+	}
+	return meta.FinalAggregation.UncertaintyReasons
+}
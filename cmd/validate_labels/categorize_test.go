@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoCategory(t *testing.T) {
+	cz := categorizer{auto: true}
+	categorize := func(fc FlaggedCase, err error, metadata string) FlaggedCase {
+		cz.categorize(&fc, err, metadata)
+		return fc
+	}
+
+	fc := categorize(FlaggedCase{ID: 1}, fmt.Errorf("ensemble: %w", llm.ErrInvalidLLMResponse), "")
+	assert.Equal(t, db.ErrorCategoryPromptIssue, fc.ErrorCategory)
+	assert.Equal(t, CategorySourceAuto, fc.CategorySource)
+
+	fc = categorize(FlaggedCase{ID: 1}, errors.New("no valid high-confidence LLM responses from any model"), "")
+	assert.Equal(t, db.ErrorCategoryPromptIssue, fc.ErrorCategory)
+
+	fc = categorize(FlaggedCase{ID: 1}, llm.ErrLLMServiceUnavailable, "")
+	assert.Equal(t, db.ErrorCategoryModelFailure, fc.ErrorCategory)
+
+	fc = categorize(FlaggedCase{ID: 2, TrueLabel: LabelNeutral, PredictedLabel: LabelLeft, Disagreement: true}, nil, "{}")
+	assert.Equal(t, db.ErrorCategoryDataNoise, fc.ErrorCategory)
+	assert.Equal(t, "neutral versus left", fc.CategoryReason)
+
+	disagreeing := `{"final_aggregation":{"uncertainty_flag":true,"uncertainty_reasons":["model_disagreement"]}}`
+	fc = categorize(FlaggedCase{ID: 3, TrueLabel: LabelLeft, PredictedLabel: LabelLeft, Uncertain: true}, nil, disagreeing)
+	assert.Equal(t, db.ErrorCategoryModelFailure, fc.ErrorCategory)
+
+	lowConfidence := `{"final_aggregation":{"uncertainty_flag":true,"uncertainty_reasons":["low_confidence"]}}`
+	fc = categorize(FlaggedCase{ID: 4, TrueLabel: LabelLeft, PredictedLabel: LabelLeft, Uncertain: true}, nil, lowConfidence)
+	assert.Equal(t, db.ErrorCategoryPromptIssue, fc.ErrorCategory)
+
+	fc = categorize(FlaggedCase{ID: 5, TrueLabel: LabelLeft, PredictedLabel: LabelRight, Disagreement: true}, nil, "{}")
+	assert.Empty(t, fc.ErrorCategory, "opposite sides are left for a reviewer")
+	assert.Empty(t, fc.CategorySource)
+
+	off := categorizer{}
+	fc = FlaggedCase{ID: 2, TrueLabel: LabelNeutral, PredictedLabel: LabelLeft, Disagreement: true}
+	off.categorize(&fc, nil, "{}")
+	assert.Empty(t, fc.ErrorCategory)
+}
+
+func TestCategoryOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"7": "model_failure"}`), 0o600))
+	overrides, err := loadCategoryOverrides(path)
+	require.NoError(t, err)
+
+	cz := categorizer{auto: true, overrides: overrides}
+	fc := FlaggedCase{ID: 7, TrueLabel: LabelNeutral, PredictedLabel: LabelLeft, Disagreement: true}
+	cz.categorize(&fc, nil, "{}")
+	assert.Equal(t, db.ErrorCategoryModelFailure, fc.ErrorCategory)
+	assert.Equal(t, CategorySourceManual, fc.CategorySource)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"7": "typo"}`), 0o600))
+	_, err = loadCategoryOverrides(path)
+	assert.Error(t, err)
+}
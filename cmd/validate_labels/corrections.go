@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+)
+
+// readReviewedCases loads a flagged cases file that reviewers have annotated
+func readReviewedCases(path string) ([]FlaggedCase, error) {
+	b, err := os.ReadFile(path) // #nosec G304 - path is from command line argument, controlled input
+	if err != nil {
+		return nil, err
+	}
+	var cases []FlaggedCase
+	if err := json.Unmarshal(b, &cases); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cases, nil
+}
+
+// buildCorrections turns reviewed cases into label corrections, checking that every
+// label exists and every correction and error category is allowed. Cases without a
+// corrected_label or a manually set error_category were not reviewed and are left out;
+// to confirm a guessed category, set category_source to "manual" or clear it.
+func buildCorrections(database *sqlx.DB, cases []FlaggedCase) ([]db.LabelCorrection, []string, error) {
+	ids := make([]int64, 0, len(cases))
+	for _, c := range cases {
+		ids = append(ids, c.ID)
+	}
+	existing, err := db.FetchLabelsByIDs(database, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var corrections []db.LabelCorrection
+	var problems []string
+	for i, c := range cases {
+		if c.CorrectedLabel == "" && (c.ErrorCategory == "" || c.CategorySource == CategorySourceAuto) {
+			continue
+		}
+		correction := db.LabelCorrection{LabelID: c.ID, ErrorCategory: c.ErrorCategory}
+		valid := true
+		if _, ok := existing[c.ID]; !ok {
+			problems = append(problems, fmt.Sprintf("case %d: label %d does not exist", i+1, c.ID))
+			valid = false
+		}
+		if c.CorrectedLabel != "" {
+			label, ok := db.NormalizeLabel(c.CorrectedLabel)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("case %d: corrected_label %q is not left, right or neutral", i+1, c.CorrectedLabel))
+				valid = false
+			}
+			correction.CorrectedLabel = label
+		}
+		if !db.ValidErrorCategory(c.ErrorCategory) {
+			problems = append(problems, fmt.Sprintf("case %d: error_category %q is not one of %s, %s or %s",
+				i+1, c.ErrorCategory, db.ErrorCategoryPromptIssue, db.ErrorCategoryModelFailure, db.ErrorCategoryDataNoise))
+			valid = false
+		}
+		if valid {
+			corrections = append(corrections, correction)
+		}
+	}
+	return corrections, problems, nil
+}
+
+// applyCorrections validates a reviewed flagged cases file and, unless dryRun is set,
+// writes the corrections back to the labels table. Nothing is applied if any reviewed
+// case is invalid.
+func applyCorrections(database *sqlx.DB, path string, dryRun bool) error {
+	cases, err := readReviewedCases(path)
+	if err != nil {
+		return err
+	}
+	corrections, problems, err := buildCorrections(database, cases)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		return fmt.Errorf("%d problems in %s, nothing applied", len(problems), path)
+	}
+
+	if dryRun {
+		fmt.Printf("Dry run: %d of %d cases reviewed, all valid\n", len(corrections), len(cases))
+		return nil
+	}
+	changed, err := db.ApplyLabelCorrections(database, corrections)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Applied %d reviews from %s: %d labels changed\n", len(corrections), path, changed)
+	return nil
+}
@@ -1,12 +1,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"math"
-	"math/rand/v2"
 	"os"
 	"time"
 
@@ -14,6 +14,7 @@ import (
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	appmetrics "github.com/alexandru-savinov/BalancedNewsGo/internal/metrics"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -47,26 +48,66 @@ type FlaggedCase struct {
 	Uncertain      bool    `json:"uncertain"`
 	Disagreement   bool    `json:"disagreement"`
 	ErrorCategory  string  `json:"error_category"` // prompt_issue, model_failure, data_noise, or empty
+	// CategorySource is "auto" for a heuristic category and "manual" for an override
+	CategorySource string `json:"category_source,omitempty"`
+	CategoryReason string `json:"category_reason,omitempty"`
+	// AnalysisError is set when the sample could not be scored
+	AnalysisError string `json:"analysis_error,omitempty"`
+	// CorrectedLabel is filled in by a reviewer and applied with -apply-corrections
+	CorrectedLabel string `json:"corrected_label,omitempty"`
 }
 
 func main() {
 	dbPath := flag.String("db", "news.db", "Path to SQLite database")
+	correctionsPath := flag.String("apply-corrections", "", "Apply corrected_label and error_category from a reviewed flagged cases file instead of validating")
+	dryRun := flag.Bool("dry-run", false, "With -apply-corrections, check the file without writing")
+	autoCategorize := flag.Bool("auto-categorize", true, "Guess the error category of flagged cases from the score and metadata")
+	overridesPath := flag.String("category-overrides", "", "JSON file mapping label IDs to error categories, which win over the guessed ones")
+	seed := flag.Int64("seed", 0, "Seed for sampling flagged cases; the same seed over the same labels gives the same sample (default: time-based)")
+	sampleFraction := flag.Float64("sample-fraction", defaultSampleFraction, "Share of flagged cases sampled for review, in (0, 1]")
 	flag.Parse()
 
+	if *sampleFraction <= 0 || *sampleFraction > 1 {
+		log.Fatalf("Invalid -sample-fraction %v: must be in (0, 1]", *sampleFraction)
+	}
+	seeded := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			seeded = true
+		}
+	})
+
+	cz := categorizer{auto: *autoCategorize}
+	if *overridesPath != "" {
+		overrides, err := loadCategoryOverrides(*overridesPath)
+		if err != nil {
+			log.Fatalf("Failed to load category overrides: %v", err)
+		}
+		cz.overrides = overrides
+	}
+
 	database, client := initDBAndClient(*dbPath)
+	if *correctionsPath != "" {
+		if err := applyCorrections(database, *correctionsPath, *dryRun); err != nil {
+			log.Fatalf("Failed to apply corrections: %v", err)
+		}
+		return
+	}
 	labels := fetchLabels(database)
 
 	log.Printf("Processing %d labeled samples...", len(labels))
 
-	metrics, flaggedCases := processLabels(database, client, labels)
+	metrics, flaggedCases := processLabels(database, client, labels, cz)
 
 	computeMetrics(&metrics)
 
 	saveAndPrintResults(metrics)
 
+	recordValidationRun(database, metrics)
+
 	saveAllFlaggedCases(flaggedCases)
 
-	sampleAndSaveFlaggedCases(flaggedCases)
+	sampleAndSaveFlaggedCases(flaggedCases, *sampleFraction, *seed, seeded)
 }
 
 func initDBAndClient(dbPath string) (*sqlx.DB, *llm.LLMClient) {
@@ -80,14 +121,14 @@ func initDBAndClient(dbPath string) (*sqlx.DB, *llm.LLMClient) {
 
 func fetchLabels(database *sqlx.DB) []db.Label {
 	var labels []db.Label
-	err := database.Select(&labels, "SELECT * FROM labels")
+	err := database.Select(&labels, "SELECT * FROM labels ORDER BY id")
 	if err != nil {
 		log.Fatalf("Failed to fetch labels: %v", err)
 	}
 	return labels
 }
 
-func processLabels(database *sqlx.DB, client *llm.LLMClient, labels []db.Label) (Metrics, []FlaggedCase) {
+func processLabels(database *sqlx.DB, client *llm.LLMClient, labels []db.Label, cz categorizer) (Metrics, []FlaggedCase) {
 	metrics := Metrics{
 		ConfusionMatrix: map[string]map[string]int{
 			LabelLeft:    {LabelLeft: 0, LabelRight: 0, LabelNeutral: 0},
@@ -102,6 +143,11 @@ func processLabels(database *sqlx.DB, client *llm.LLMClient, labels []db.Label)
 		scoreObj, err := analyzeLabel(client, label)
 		if err != nil {
 			log.Printf("Error scoring label ID %d: %v", label.ID, err)
+			// Failed samples stay out of the metrics but are flagged for review
+			fc := createFlaggedCase(label, "", 0, false, false)
+			fc.AnalysisError = err.Error()
+			cz.categorize(&fc, err, "")
+			flaggedCases = append(flaggedCases, fc)
 			continue
 		}
 
@@ -117,7 +163,9 @@ func processLabels(database *sqlx.DB, client *llm.LLMClient, labels []db.Label)
 		disagreement := compareLabels(predLabel, trueLabel, &metrics)
 
 		if isUncertain || disagreement {
-			flaggedCases = append(flaggedCases, createFlaggedCase(label, predLabel, scoreObj.Score, isUncertain, disagreement))
+			fc := createFlaggedCase(label, predLabel, scoreObj.Score, isUncertain, disagreement)
+			cz.categorize(&fc, nil, scoreObj.Metadata)
+			flaggedCases = append(flaggedCases, fc)
 		}
 
 		updateConfusionMatrix(&metrics, trueLabel, predLabel)
@@ -130,7 +178,7 @@ func processLabels(database *sqlx.DB, client *llm.LLMClient, labels []db.Label)
 
 // analyzeLabel calls the LLM client and prepares the score object
 func analyzeLabel(client *llm.LLMClient, label db.Label) (*db.LLMScore, error) {
-	scoreObj, err := client.EnsembleAnalyze(label.ID, label.Data)
+	scoreObj, err := client.EnsembleAnalyze(context.Background(), label.ID, label.Data)
 	if err != nil {
 		return nil, err
 	}
@@ -149,16 +197,18 @@ func insertScore(database *sqlx.DB, scoreObj *db.LLMScore) {
 
 // parseUncertaintyFlag extracts the uncertainty flag from metadata JSON
 func parseUncertaintyFlag(metadata string) bool {
+	type aggregation struct {
+		UncertaintyFlag bool `json:"uncertainty_flag"`
+	}
 	var meta struct {
-		Aggregation struct {
-			UncertaintyFlag bool `json:"uncertainty_flag"`
-		} `json:"aggregation"`
+		FinalAggregation aggregation `json:"final_aggregation"`
+		Aggregation      aggregation `json:"aggregation"` // older metadata
 	}
 	if err := json.Unmarshal([]byte(metadata), &meta); err != nil {
 		log.Printf("Failed to parse score metadata: %v", err)
 		return false
 	}
-	return meta.Aggregation.UncertaintyFlag
+	return meta.FinalAggregation.UncertaintyFlag || meta.Aggregation.UncertaintyFlag
 }
 
 // compareLabels updates metrics and returns true if there is disagreement
@@ -249,19 +299,36 @@ func saveAndPrintResults(metrics Metrics) {
 	fmt.Printf("Confusion Matrix: %+v\n", metrics.ConfusionMatrix)
 }
 
+// recordValidationRun stores the run's metrics so the server can report the latest one
+func recordValidationRun(database *sqlx.DB, m Metrics) {
+	run := &appmetrics.ValidationRun{
+		Total:           m.Total,
+		Correct:         m.Correct,
+		Incorrect:       m.Incorrect,
+		Uncertain:       m.Uncertain,
+		Disagreements:   m.Disagreements,
+		Accuracy:        m.Accuracy,
+		Precision:       m.Precision,
+		Recall:          m.Recall,
+		F1:              m.F1,
+		ConfusionMatrix: m.ConfusionMatrix,
+	}
+	if err := appmetrics.SaveValidationRun(database, run); err != nil {
+		log.Printf("Failed to record validation run: %v", err)
+		return
+	}
+	log.Printf("Recorded validation run %d", run.ID)
+}
+
 func saveAllFlaggedCases(flaggedCases []FlaggedCase) {
 	saveFlaggedCases(flaggedCases, "flagged_cases")
 }
 
-func sampleAndSaveFlaggedCases(flaggedCases []FlaggedCase) {
-	sampleSize := int(math.Max(1, math.Round(0.1*float64(len(flaggedCases)))))
-	if sampleSize > len(flaggedCases) {
-		sampleSize = len(flaggedCases)
-	}
-	sampled := make([]FlaggedCase, 0, sampleSize)
-	perm := rand.Perm(len(flaggedCases))
-	for i := 0; i < sampleSize; i++ {
-		sampled = append(sampled, flaggedCases[perm[i]])
+func sampleAndSaveFlaggedCases(flaggedCases []FlaggedCase, fraction float64, seed int64, seeded bool) {
+	rng, seed := newSampler(seed, seeded)
+	sampled := sampleFlaggedCases(flaggedCases, fraction, rng)
+	if len(sampled) > 0 {
+		log.Printf("Sampled %d of %d flagged cases with -seed %d", len(sampled), len(flaggedCases), seed)
 	}
 	saveFlaggedCases(sampled, "sampled_flagged_cases")
 }
@@ -299,17 +366,12 @@ func scoreToLabel(score float64) string {
 	return LabelNeutral
 }
 
+// normalizeLabel maps a label to left, right or neutral, treating unknown labels as neutral
 func normalizeLabel(label string) string {
-	switch label {
-	case "Left", "left", "-1", "-1.0":
-		return LabelLeft
-	case "Right", "right", "1", "1.0":
-		return LabelRight
-	case "Neutral", "neutral", "0", "0.0":
-		return LabelNeutral
-	default:
-		return "neutral"
+	if normalized, ok := db.NormalizeLabel(label); ok {
+		return normalized
 	}
+	return LabelNeutral
 }
 
 func saveMetrics(metrics Metrics) {
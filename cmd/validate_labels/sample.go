@@ -0,0 +1,39 @@
+package main
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// defaultSampleFraction is the share of flagged cases sampled for manual review
+const defaultSampleFraction = 0.1
+
+// newSampler returns the random source for sampling. The same seed over the same
+// labels picks the same cases, so two validation runs, say before and after a prompt
+// change, can be compared on identical samples. Without a seed the current time is
+// used, and it is logged so the sample can still be reproduced.
+func newSampler(seed int64, seeded bool) (*rand.Rand, int64) {
+	if !seeded {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewPCG(uint64(seed), uint64(seed))), seed // #nosec G404 - sampling, not security
+}
+
+// sampleFlaggedCases picks round(fraction * len(cases)) cases, at least one, in random
+// order
+func sampleFlaggedCases(cases []FlaggedCase, fraction float64, rng *rand.Rand) []FlaggedCase {
+	if len(cases) == 0 {
+		return nil
+	}
+	sampleSize := int(math.Max(1, math.Round(fraction*float64(len(cases)))))
+	if sampleSize > len(cases) {
+		sampleSize = len(cases)
+	}
+	sampled := make([]FlaggedCase, 0, sampleSize)
+	perm := rng.Perm(len(cases))
+	for i := 0; i < sampleSize; i++ {
+		sampled = append(sampled, cases[perm[i]])
+	}
+	return sampled
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleFlaggedCases(t *testing.T) {
+	cases := make([]FlaggedCase, 50)
+	for i := range cases {
+		cases[i].ID = int64(i + 1)
+	}
+	ids := func(sampled []FlaggedCase) []int64 {
+		out := make([]int64, len(sampled))
+		for i, fc := range sampled {
+			out[i] = fc.ID
+		}
+		return out
+	}
+
+	rng, seed := newSampler(42, true)
+	assert.Equal(t, int64(42), seed)
+	first := sampleFlaggedCases(cases, defaultSampleFraction, rng)
+	assert.Len(t, first, 5)
+
+	rng, _ = newSampler(42, true)
+	assert.Equal(t, ids(first), ids(sampleFlaggedCases(cases, defaultSampleFraction, rng)), "the same seed gives the same sample")
+
+	rng, _ = newSampler(7, true)
+	assert.NotEqual(t, ids(first), ids(sampleFlaggedCases(cases, defaultSampleFraction, rng)))
+
+	rng, _ = newSampler(42, true)
+	assert.Len(t, sampleFlaggedCases(cases, 0.5, rng), 25)
+	assert.Len(t, sampleFlaggedCases(cases, 1, rng), 50)
+	assert.Len(t, sampleFlaggedCases(cases[:3], defaultSampleFraction, rng), 1, "at least one case is sampled")
+	assert.Empty(t, sampleFlaggedCases(nil, defaultSampleFraction, rng))
+
+	_, timeSeed := newSampler(0, false)
+	assert.NotZero(t, timeSeed, "an unseeded run reports the seed it used")
+}
@@ -531,12 +531,13 @@ const docTemplate = `{
         },
         "/api/manual-score/{id}": {
             "post": {
-                "description": "Updates an article's bias score manually",
+                "description": "Deprecated: pins an article's bias score like PUT /api/articles/{id}/manual-score, without a reason",
                 "tags": [
                     "Analysis"
                 ],
                 "summary": "Manually set article score",
                 "operationId": "addManualScore",
+                "deprecated": true,
                 "parameters": [
                     {
                         "minimum": 1,
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminConfigEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("LLM_MODELS", "")
+
+	// The config file is found relative to the working directory
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "configs"), 0o750))
+	configPath := filepath.Join(dir, "configs", "composite_score_config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"formula": "average", "models": [
+		{"modelName": "old-model", "perspective": "center", "weight": 1.0, "url": "https://router.example/v1"}
+	]}`), 0o600))
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+	dbConn, err := db.InitDB(filepath.Join(dir, "config.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	client := llm.NewLLMClientWithService(nil, nil, nil)
+	router := gin.New()
+	router.GET("/api/admin/config", AdminAuthMiddleware("secret"), adminGetConfigHandler())
+	router.PUT("/api/admin/config", AdminAuthMiddleware("secret"), NewBodyLimit(1024).Middleware(), AuditMiddleware(dbConn, "config.update"),
+		adminUpdateConfigHandler(client))
+
+	do := func(method, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, "/api/admin/config", bytes.NewBufferString(body))
+		req.Header.Set(AdminAPIKeyHeader, "secret")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "old-model")
+
+	t.Run("RejectsInvalid", func(t *testing.T) {
+		for _, body := range []string{
+			`{"formula": "average", "models": []}`,
+			`{"formula": "weighted", "models": [{"modelName": "m", "perspective": "left", "weight": -1}]}`,
+			`{"formula": "average", "models": [{"modelName": "m", "perspective": "left", "weight": 1}], "weights": {"left": -2}}`,
+			`{"formula": "average", "models": [{"modelName": "m", "perspective": "left", "weight": 1}], "typo": 1}`,
+			`not json`,
+		} {
+			assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, body).Code, body)
+		}
+		assert.Equal(t, http.StatusRequestEntityTooLarge, do(http.MethodPut, `{"formula": "`+strings.Repeat("x", 2048)+`"}`).Code)
+		assert.Nil(t, client.GetConfig(), "rejected configs are not applied")
+	})
+
+	w = do(http.MethodPut, `{"formula": "weighted", "models": [
+		{"modelName": "new-model", "perspective": "left", "weight": 2.0, "url": "https://router.example/v1"}
+	], "weights": {"left": 2}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	require.NotNil(t, client.GetConfig())
+	assert.Equal(t, "new-model", client.GetConfig().Models[0].ModelName)
+	stored, err := llm.ReadCompositeScoreConfigFile()
+	require.NoError(t, err)
+	assert.Equal(t, "weighted", stored.Formula)
+	assert.Contains(t, do(http.MethodGet, "").Body.String(), "new-model")
+
+	entries, total, err := db.FetchAuditEntries(dbConn, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, "config.update", entries[0].Action)
+	var details map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(entries[0].Details), &details))
+	assert.Equal(t, []interface{}{"new-model"}, details["models"])
+}
@@ -2,9 +2,11 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
@@ -125,7 +127,7 @@ func reanalyzeArticlesBatch(ctx context.Context, llmClient *llm.LLMClient, score
 	log.Printf("[ADMIN] Starting reanalysis of %d recent articles", len(articleIDs))
 
 	for _, articleID := range articleIDs {
-		if err := llmClient.ReanalyzeArticle(ctx, articleID, scoreManager); err != nil {
+		if err := reanalyzeLocked(ctx, llmClient, scoreManager, articleID); err != nil {
 			log.Printf("[ADMIN] Failed to reanalyze article %d: %v", articleID, err)
 			continue
 		}
@@ -135,6 +137,26 @@ func reanalyzeArticlesBatch(ctx context.Context, llmClient *llm.LLMClient, score
 	log.Printf("[ADMIN] Completed reanalysis of recent articles")
 }
 
+// reanalyzeLocked reanalyses an article under its job lock, skipping it with
+// llm.ErrJobInProgress when another reanalysis of it is underway. It waits for a job
+// slot like any other reanalysis, so batches respect REANALYSIS_MAX_CONCURRENT.
+func reanalyzeLocked(ctx context.Context, llmClient *llm.LLMClient, scoreManager *llm.ScoreManager, articleID int64) error {
+	if scoreManager == nil {
+		return llmClient.ReanalyzeArticle(ctx, articleID, scoreManager)
+	}
+	jobCtx, jobDone, err := scoreManager.TryStartJob(ctx, articleID)
+	if err != nil {
+		return err
+	}
+	defer jobDone()
+	releaseSlot, err := scoreManager.AcquireJobSlot(jobCtx, articleID)
+	if err != nil {
+		return err
+	}
+	defer releaseSlot()
+	return llmClient.ReanalyzeArticle(jobCtx, articleID, scoreManager)
+}
+
 // performAsyncReanalysis handles the async reanalysis workflow
 func performAsyncReanalysis(llmClient *llm.LLMClient, scoreManager *llm.ScoreManager, dbConn *sqlx.DB) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
@@ -536,6 +558,79 @@ func adminGetLogsHandler() gin.HandlerFunc {
 	}
 }
 
+// adminLLMKeyStatsHandler handles GET /api/admin/llm/key-stats
+func adminLLMKeyStatsHandler(llmClient *llm.LLMClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keys := []llm.KeyStats{}
+		var breaker *llm.BreakerStats
+		if llmClient != nil {
+			if stats := llmClient.KeyStats(); stats != nil {
+				keys = stats
+			}
+			breaker = llmClient.BreakerStats()
+		}
+
+		healthy := 0
+		for _, k := range keys {
+			if k.Healthy {
+				healthy++
+			}
+		}
+
+		RespondSuccess(c, map[string]interface{}{
+			"keys":            keys,
+			"total_keys":      len(keys),
+			"healthy_keys":    healthy,
+			"circuit_breaker": breaker,
+		})
+	}
+}
+
+// adminScoreBackfillStatusHandler handles GET /api/admin/score-backfill
+func adminScoreBackfillStatusHandler(backfiller *ScoreBackfiller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		RespondSuccess(c, backfiller.Status())
+	}
+}
+
+// adminScoreBackfillStartHandler handles POST /api/admin/score-backfill/start
+func adminScoreBackfillStartHandler(backfiller *ScoreBackfiller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := backfiller.Start(); err != nil {
+			RespondError(c, WrapError(err, ErrLLMService, "Score backfill cannot start: "+err.Error()))
+			return
+		}
+		RespondSuccess(c, backfiller.Status())
+	}
+}
+
+// adminScoreBackfillStopHandler handles POST /api/admin/score-backfill/stop
+func adminScoreBackfillStopHandler(backfiller *ScoreBackfiller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		backfiller.Stop()
+		RespondSuccess(c, backfiller.Status())
+	}
+}
+
+// adminCacheStatsHandler handles GET /api/admin/cache-stats
+func adminCacheStatsHandler(llmClient *llm.LLMClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		articlesCacheLock.RLock()
+		apiStats := articlesCache.Stats()
+		articlesCacheLock.RUnlock()
+
+		var llmStats interface{}
+		if llmClient != nil {
+			llmStats = llmClient.CacheStats()
+		}
+
+		RespondSuccess(c, map[string]interface{}{
+			"api": apiStats,
+			"llm": llmStats,
+		})
+	}
+}
+
 // adminRunHealthCheckHandler handles POST /api/admin/health-check
 func adminRunHealthCheckHandler(dbConn *sqlx.DB, llmClient *llm.LLMClient, rssCollector rss.CollectorInterface) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -566,6 +661,81 @@ func adminRunHealthCheckHandler(dbConn *sqlx.DB, llmClient *llm.LLMClient, rssCo
 	}
 }
 
+// adminSelfCheckHandler handles POST /api/admin/self-check
+func adminSelfCheckHandler(llmClient *llm.LLMClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if llmClient == nil {
+			RespondError(c, NewAppError(ErrLLMService, "LLM client is not configured"))
+			return
+		}
+
+		result, err := llmClient.SelfCheck(c.Request.Context())
+		if err != nil {
+			RespondError(c, NewAppError(ErrLLMService, err.Error()))
+			return
+		}
+		RespondSuccess(c, result)
+	}
+}
+
+// adminGetConfigHandler handles GET /api/admin/config
+func adminGetConfigHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg, err := llm.ReadCompositeScoreConfigFile()
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to read composite score config"))
+			return
+		}
+		RespondSuccess(c, cfg)
+	}
+}
+
+// adminUpdateConfigHandler handles PUT /api/admin/config. The new config is validated,
+// written to the config file and reloaded into the LLM client; recomputes and other
+// callers loading the config read the file and pick it up on their next run.
+func adminUpdateConfigHandler(llmClient *llm.LLMClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var cfg llm.CompositeScoreConfig
+		decoder := json.NewDecoder(c.Request.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&cfg); err != nil {
+			if strings.Contains(err.Error(), "unknown field") {
+				RespondError(c, NewAppError(ErrValidation, "Config contains unknown fields: "+err.Error()))
+				return
+			}
+			RespondError(c, ErrInvalidPayload)
+			return
+		}
+		if err := cfg.Validate(); err != nil {
+			RespondError(c, NewAppError(ErrValidation, "Invalid config: "+err.Error()))
+			return
+		}
+		if err := llm.SaveCompositeScoreConfig(&cfg); err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to save composite score config"))
+			return
+		}
+
+		models := make([]string, len(cfg.Models))
+		for i, m := range cfg.Models {
+			models[i] = m.ModelName
+		}
+		setAuditDetails(c, "models", models)
+		setAuditDetails(c, "formula", cfg.Formula)
+
+		// Reload from the file so the client also gets the LLM_MODELS override
+		loaded, err := llm.LoadCompositeScoreConfig()
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Config saved but failed to reload"))
+			return
+		}
+		if llmClient != nil {
+			llmClient.SetConfig(loaded)
+		}
+		log.Printf("[INFO] Composite score config updated by %s: models %s", auditActor(c), strings.Join(models, ", "))
+		RespondSuccess(c, cfg)
+	}
+}
+
 // Source Management Admin Handlers for HTMX
 
 // adminSourcesListHandler handles GET /htmx/sources
@@ -756,6 +926,9 @@ func adminCreateSourceHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			})
 			return
 		}
+		setAuditDetails(c, "source_id", id)
+		setAuditDetails(c, "source_name", source.Name)
+
 		source.ID = id
 
 		// Return updated source list HTML
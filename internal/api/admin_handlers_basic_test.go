@@ -370,6 +370,22 @@ func TestAdminReanalyzeRecentHandler(t *testing.T) {
 	}
 }
 
+func TestReanalyzeLockedWaitsForJobSlot(t *testing.T) {
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	scoreManager := llm.NewScoreManager(nil, llm.NewCache(), &llm.DefaultScoreCalculator{}, pm)
+	scoreManager.SetMaxConcurrentJobs(1)
+	release, err := scoreManager.AcquireJobSlot(context.Background(), 1)
+	assert.NoError(t, err)
+	defer release()
+
+	// With the only slot taken the batch article queues instead of scoring
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = reanalyzeLocked(ctx, nil, scoreManager, 2)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 // TestAdminClearAnalysisErrorsHandler tests the analysis error clearing handler
 func TestAdminClearAnalysisErrorsHandler(t *testing.T) {
 	ginTestModeOnceBasic.Do(func() {
@@ -17,6 +17,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/apperrors"
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
@@ -34,7 +35,7 @@ const (
 )
 
 var (
-	articlesCache     = NewSimpleCache()
+	articlesCache     Cache = NewSimpleCache()
 	articlesCacheLock sync.RWMutex
 )
 
@@ -49,8 +50,35 @@ func RegisterRoutes(
 	llmClient *llm.LLMClient,
 	scoreManager *llm.ScoreManager,
 	progressManager *llm.ProgressManager,
-	cache *SimpleCache,
+	cache Cache,
+	ingestScorer *IngestScorer,
+	scoreBackfiller *ScoreBackfiller,
 ) {
+	// Handlers share the configured cache backend; the in-memory cache is the default
+	if cache != nil {
+		articlesCacheLock.Lock()
+		articlesCache = cache
+		articlesCacheLock.Unlock()
+	}
+
+	// Without a configured scorer pushed articles are only scored on request
+	if ingestScorer == nil {
+		ingestScorer = NewIngestScorer(IngestScoringOff, llmClient, dbConn, scoreManager)
+	}
+	// Without a configured backfiller the admin endpoints control a stopped one
+	if scoreBackfiller == nil {
+		scoreBackfiller = NewScoreBackfiller(llmClient, dbConn, scoreManager, 0, 0)
+	}
+
+	// Admin and mutating endpoints require the admin API key when one is configured
+	adminAuth := AdminAuthMiddleware(adminAPIKeyFromEnv())
+	// Successful admin actions are recorded in the audit log
+	audit := func(action string) gin.HandlerFunc { return AuditMiddleware(dbConn, action) }
+	// Public article endpoints are rate limited per client IP
+	articlesRateLimit := newRateLimiterFromEnv().Middleware()
+	// Ingestion, scoring and batch endpoints refuse oversized bodies with 413
+	bodyLimit := newBodyLimitFromEnv().Middleware()
+
 	// Articles endpoints
 	// @Summary Get all articles
 	// @Description Get a list of all articles with optional filtering
@@ -63,7 +91,7 @@ func RegisterRoutes(
 	// @Success 200 {array} api.Article
 	// @Failure 500 {object} ErrorResponse
 	// @Router /api/articles [get]
-	router.GET("/api/articles", SafeHandler(getArticlesHandler(dbConn)))
+	router.GET("/api/articles", articlesRateLimit, SafeHandler(getArticlesHandler(dbConn)))
 
 	// @Summary Get article by ID
 	// @Description Get detailed information about a specific article
@@ -74,18 +102,22 @@ func RegisterRoutes(
 	// @Success 200 {object} api.Article
 	// @Failure 404 {object} ErrorResponse
 	// @Router /api/articles/{id} [get]
-	router.GET("/api/articles/:id", SafeHandler(getArticleByIDHandler(dbConn)))
+	router.GET("/api/articles/:id", articlesRateLimit, SafeHandler(getArticleByIDHandler(dbConn)))
 
 	// @Summary Create article
-	// @Description Create a new article
+	// @Description Create a new article, optionally scoring it straight away
 	// @Tags Articles
 	// @Accept json
 	// @Produce json
-	// @Param article body models.CreateArticleRequest true "Article object"
-	// @Success 200 {object} StandardResponse{data=api.Article}
+	// @Param article body CreateArticleRequest true "Article object"
+	// @Success 201 {object} StandardResponse{data=CreatedArticleResponse}
 	// @Failure 400 {object} ErrorResponse
+	// @Failure 401 {object} ErrorResponse
+	// @Failure 409 {object} ErrorResponse
+	// @Security ApiKeyAuth
 	// @Router /api/articles [post]
-	router.POST("/api/articles", SafeHandler(createArticleHandler(dbConn)))
+	router.POST("/api/articles", articlesRateLimit, adminAuth, bodyLimit, audit("article.create"),
+		SafeHandler(createArticleHandler(dbConn, ingestScorer)))
 
 	// Feed management
 	// @Summary Refresh feeds
@@ -94,10 +126,12 @@ func RegisterRoutes(
 	// @Accept json
 	// @Produce json
 	// @Success 200 {object} StandardResponse
+	// @Failure 401 {object} ErrorResponse
 	// @Failure 500 {object} ErrorResponse
+	// @Security ApiKeyAuth
 	// @Router /api/refresh [post]
 	// @ID triggerRssRefresh
-	router.POST("/api/refresh", SafeHandler(refreshHandler(rssCollector)))
+	router.POST("/api/refresh", adminAuth, audit("feeds.refresh"), SafeHandler(refreshHandler(rssCollector)))
 
 	// LLM Analysis
 	// @Summary      Re-analyze article via LLM
@@ -111,11 +145,24 @@ func RegisterRoutes(
 	// @Failure      503   {object} StandardResponse
 	// @Router       /api/llm/reanalyze/{id} [post]
 	// @ID reanalyzeArticle
-	router.POST("/api/llm/reanalyze/:id", SafeHandler(reanalyzeHandler(llmClient, dbConn, scoreManager)))
+	router.POST("/api/llm/reanalyze/:id", adminAuth, audit("article.reanalyze"), SafeHandler(reanalyzeHandler(llmClient, dbConn, scoreManager)))
+
+	// @Summary      Cancel an in-flight reanalysis
+	// @Param        id    path     int  true  "Article ID"
+	// @Success      200   {object} StandardResponse  "Cancellation requested"
+	// @Failure      404   {object} StandardResponse  "No reanalysis in progress"
+	// @Router       /api/llm/reanalyze/{id} [delete]
+	// @ID cancelReanalysis
+	router.DELETE("/api/llm/reanalyze/:id", adminAuth, audit("article.reanalyze.cancel"), SafeHandler(cancelReanalysisHandler(scoreManager)))
+
+	// @Summary      Score pasted text without storing it
+	// @Router       /api/score-text [post]
+	// @ID scoreText
+	router.POST("/api/score-text", adminAuth, bodyLimit, SafeHandler(scoreTextHandler(llmClient)))
 
 	// Scoring
 	// @Summary Add manual score
-	// @Description Add a manual bias score for an article
+	// @Description Deprecated: pins a manual bias score for an article. Use PUT /api/articles/{id}/manual-score instead.
 	// @Tags Scoring
 	// @Accept json
 	// @Produce json
@@ -125,7 +172,57 @@ func RegisterRoutes(
 	// @Failure 400 {object} ErrorResponse
 	// @Router /api/manual-score/{id} [post]
 	// @ID addManualScore
-	router.POST("/api/manual-score/:id", SafeHandler(manualScoreHandler(dbConn)))
+	router.POST("/api/manual-score/:id", adminAuth, audit("article.manual_score"), SafeHandler(manualScoreHandler(dbConn)))
+
+	// @Summary Pin manual score
+	// @Description Overrides an article's composite score with an editor-provided value. Reanalysis keeps updating model scores but does not replace the override until it is cleared.
+	// @Tags Scoring
+	// @Accept json
+	// @Produce json
+	// @Param id path integer true "Article ID"
+	// @Param request body ManualScoreOverrideRequest true "Score and reason"
+	// @Success 200 {object} StandardResponse
+	// @Failure 400 {object} ErrorResponse
+	// @Failure 401 {object} ErrorResponse
+	// @Failure 404 {object} ErrorResponse
+	// @Security ApiKeyAuth
+	// @Router /api/articles/{id}/manual-score [put]
+	// @ID setManualScoreOverride
+	router.PUT("/api/articles/:id/manual-score", adminAuth, audit("article.manual_score.set"), SafeHandler(setManualScoreOverrideHandler(dbConn)))
+
+	// @Summary Clear manual score
+	// @Description Removes an article's manual score override and restores the model composite score
+	// @Tags Scoring
+	// @Produce json
+	// @Param id path integer true "Article ID"
+	// @Success 200 {object} StandardResponse
+	// @Failure 401 {object} ErrorResponse
+	// @Failure 404 {object} ErrorResponse
+	// @Security ApiKeyAuth
+	// @Router /api/articles/{id}/manual-score [delete]
+	// @ID clearManualScoreOverride
+	router.DELETE("/api/articles/:id/manual-score", adminAuth, audit("article.manual_score.clear"), SafeHandler(clearManualScoreOverrideHandler(dbConn)))
+
+	router.POST("/api/articles/:id/rescore-failed", adminAuth, audit("article.rescore_failed"),
+		SafeHandler(rescoreFailedHandler(llmClient, dbConn, scoreManager)))
+	router.POST("/api/articles/:id/recompute", adminAuth, audit("article.recompute"),
+		SafeHandler(recomputeHandler(llmClient, dbConn, scoreManager)))
+
+	// @Summary Preview rendered prompt
+	// @Description Returns the fully rendered prompt (template, examples and content) that would be sent to a model for an article, without calling the LLM
+	// @Tags LLM
+	// @Produce json
+	// @Param id path integer true "Article ID"
+	// @Param model query string false "Configured model name (defaults to the first configured model)"
+	// @Param variant query string false "Prompt variant ID (defaults to the single-pass \"default\" prompt)"
+	// @Success 200 {object} StandardResponse{data=llm.PromptPreview}
+	// @Failure 400 {object} ErrorResponse
+	// @Failure 401 {object} ErrorResponse
+	// @Failure 404 {object} ErrorResponse
+	// @Security ApiKeyAuth
+	// @Router /api/articles/{id}/prompt-preview [get]
+	// @ID previewArticlePrompt
+	router.GET("/api/articles/:id/prompt-preview", adminAuth, SafeHandler(promptPreviewHandler(llmClient, dbConn)))
 
 	// Article analysis
 	// @Summary      Get article summary
@@ -137,7 +234,7 @@ func RegisterRoutes(
 	// @Router       /api/articles/{id}/summary [get]
 	// @ID getArticleSummary
 	handler := NewSummaryHandler(&db.DBInstance{DB: dbConn})
-	router.GET("/api/articles/:id/summary", SafeHandler(handler.Handle))
+	router.GET("/api/articles/:id/summary", articlesRateLimit, SafeHandler(handler.Handle))
 
 	// @Summary Get bias analysis
 	// @Description Get the bias analysis for an article
@@ -148,7 +245,31 @@ func RegisterRoutes(
 	// @Success 200 {object} api.ScoreResponse
 	// @Failure 404 {object} ErrorResponse
 	// @Router /api/articles/{id}/bias [get]
-	router.GET("/api/articles/:id/bias", SafeHandler(biasHandler(dbConn)))
+	// The aggregation debug output is verbose and only for admins
+	router.GET("/api/articles/:id/bias", articlesRateLimit, adminAuthWhen(adminAuth, wantsAggregationDebug),
+		SafeHandler(biasHandler(dbConn)))
+
+	// @Summary Get related articles
+	// @Description Get recent articles with similar content and similar or contrasting bias
+	// @Tags Articles
+	// @Param id path integer true "Article ID"
+	// @Success 200 {object} api.StandardResponse
+	// @Failure 404 {object} ErrorResponse
+	// @Router /api/articles/{id}/related [get]
+	router.GET("/api/articles/:id/related", articlesRateLimit, SafeHandler(relatedArticlesHandler(dbConn)))
+
+	// @Summary Get per-model score breakdown
+	// @Description Get each model's latest score, confidence and label with pairwise agreement flags
+	// @Tags Analysis
+	// @Param id path integer true "Article ID"
+	// @Success 200 {object} api.StandardResponse
+	// @Failure 404 {object} ErrorResponse
+	// @Router /api/articles/{id}/model-breakdown [get]
+	router.GET("/api/articles/:id/model-breakdown", articlesRateLimit, SafeHandler(modelBreakdownHandler(dbConn)))
+
+	// Stored score rows and their metadata, for debugging without access to the database
+	router.GET("/api/articles/:id/scores", articlesRateLimit, SafeHandler(listArticleScoresHandler(dbConn)))
+	router.GET("/api/articles/:id/scores/:scoreID/metadata", adminAuth, SafeHandler(scoreMetadataHandler(dbConn)))
 
 	// @Summary Get ensemble details
 	// @Description Get detailed ensemble analysis results for an article
@@ -158,10 +279,16 @@ func RegisterRoutes(
 	// @Failure 404 {object} ErrorResponse
 	// @Router /api/articles/{id}/ensemble [get]
 	// @ID getArticleEnsemble
-	router.GET("/api/articles/:id/ensemble", SafeHandler(ensembleDetailsHandler(dbConn)))
+	router.GET("/api/articles/:id/ensemble", articlesRateLimit, SafeHandler(ensembleDetailsHandler(dbConn)))
+
+	// Editorial tags
+	router.GET("/api/tags", articlesRateLimit, SafeHandler(listTagsHandler(dbConn)))
+	router.GET("/api/articles/:id/tags", articlesRateLimit, SafeHandler(getArticleTagsHandler(dbConn)))
+	router.POST("/api/articles/:id/tags", adminAuth, audit("article.tags.add"), SafeHandler(addArticleTagsHandler(dbConn)))
+	router.DELETE("/api/articles/:id/tags/:tag", adminAuth, audit("article.tags.remove"), SafeHandler(removeArticleTagHandler(dbConn)))
 
 	// For backward compatibility with the frontend
-	router.GET("/api/articles/:id/ensemble-details", SafeHandler(ensembleDetailsHandler(dbConn)))
+	router.GET("/api/articles/:id/ensemble-details", articlesRateLimit, SafeHandler(ensembleDetailsHandler(dbConn)))
 
 	// Feedback
 	// @Summary Submit feedback
@@ -172,9 +299,13 @@ func RegisterRoutes(
 	// @Param feedback body models.FeedbackRequest true "Feedback information"
 	// @Success 200 {object} StandardResponse
 	// @Failure 400 {object} ErrorResponse
+	// @Failure 413 {object} ErrorResponse "Request body too large"
 	// @Router /api/feedback [post]
 	// @ID submitFeedback
-	router.POST("/api/feedback", SafeHandler(feedbackHandler(dbConn, llmClient)))
+	router.POST("/api/feedback", bodyLimit, SafeHandler(feedbackHandler(dbConn, llmClient)))
+	router.POST("/api/feedback/batch", adminAuth, bodyLimit, audit("feedback.batch"), SafeHandler(feedbackBatchHandler(dbConn)))
+	router.GET("/api/admin/feedback", adminAuth, SafeHandler(listFeedbackHandler(dbConn)))
+	router.PUT("/api/admin/feedback/:id/status", adminAuth, audit("feedback.status"), SafeHandler(updateFeedbackStatusHandler(dbConn, llmClient)))
 
 	// Health checks
 	// @Summary Get RSS feed health status
@@ -242,7 +373,7 @@ func RegisterRoutes(
 	// @Failure 409 {object} ErrorResponse
 	// @Failure 500 {object} ErrorResponse
 	// @Router /api/sources [post]
-	router.POST("/api/sources", SafeHandler(createSourceHandler(dbConn)))
+	router.POST("/api/sources", adminAuth, audit("source.create"), SafeHandler(createSourceHandler(dbConn)))
 
 	// @Summary Get source by ID
 	// @Description Get a specific source by its ID
@@ -271,7 +402,7 @@ func RegisterRoutes(
 	// @Failure 409 {object} ErrorResponse
 	// @Failure 500 {object} ErrorResponse
 	// @Router /api/sources/{id} [put]
-	router.PUT("/api/sources/:id", SafeHandler(updateSourceHandler(dbConn)))
+	router.PUT("/api/sources/:id", adminAuth, audit("source.update"), SafeHandler(updateSourceHandler(dbConn)))
 
 	// @Summary Delete source (soft delete)
 	// @Description Disable a source (soft delete)
@@ -284,7 +415,7 @@ func RegisterRoutes(
 	// @Failure 404 {object} ErrorResponse
 	// @Failure 500 {object} ErrorResponse
 	// @Router /api/sources/{id} [delete]
-	router.DELETE("/api/sources/:id", SafeHandler(deleteSourceHandler(dbConn)))
+	router.DELETE("/api/sources/:id", adminAuth, audit("source.delete"), SafeHandler(deleteSourceHandler(dbConn)))
 
 	// @Summary Get source statistics
 	// @Description Get detailed statistics for a specific source
@@ -299,6 +430,9 @@ func RegisterRoutes(
 	// @Router /api/sources/{id}/stats [get]
 	router.GET("/api/sources/:id/stats", SafeHandler(getSourceStatsHandler(dbConn)))
 
+	// Stats endpoints
+	router.GET("/api/stats/bias-trend", SafeHandler(biasTrendHandler(dbConn)))
+
 	// Admin endpoints
 	// @Summary Refresh all RSS feeds
 	// @Description Triggers a manual refresh of all configured RSS feeds
@@ -306,9 +440,11 @@ func RegisterRoutes(
 	// @Accept json
 	// @Produce json
 	// @Success 200 {object} StandardResponse
+	// @Failure 401 {object} ErrorResponse
 	// @Failure 500 {object} ErrorResponse
+	// @Security ApiKeyAuth
 	// @Router /api/admin/refresh-feeds [post]
-	router.POST("/api/admin/refresh-feeds", SafeHandler(adminRefreshFeedsHandler(rssCollector)))
+	router.POST("/api/admin/refresh-feeds", adminAuth, audit("feeds.refresh"), SafeHandler(adminRefreshFeedsHandler(rssCollector)))
 
 	// @Summary Reset feed errors
 	// @Description Resets error states for RSS feeds
@@ -317,7 +453,7 @@ func RegisterRoutes(
 	// @Produce json
 	// @Success 200 {object} StandardResponse
 	// @Router /api/admin/reset-feed-errors [post]
-	router.POST("/api/admin/reset-feed-errors", SafeHandler(adminResetFeedErrorsHandler(rssCollector)))
+	router.POST("/api/admin/reset-feed-errors", adminAuth, audit("feeds.reset_errors"), SafeHandler(adminResetFeedErrorsHandler(rssCollector)))
 
 	// @Summary Get sources status
 	// @Description Returns health status of all RSS feed sources
@@ -326,7 +462,7 @@ func RegisterRoutes(
 	// @Produce json
 	// @Success 200 {object} StandardResponse
 	// @Router /api/admin/sources [get]
-	router.GET("/api/admin/sources", SafeHandler(adminGetSourcesStatusHandler(rssCollector)))
+	router.GET("/api/admin/sources", adminAuth, SafeHandler(adminGetSourcesStatusHandler(rssCollector)))
 
 	// @Summary Reanalyze recent articles
 	// @Description Triggers reanalysis of recent articles using LLM
@@ -336,7 +472,7 @@ func RegisterRoutes(
 	// @Success 200 {object} StandardResponse
 	// @Failure 503 {object} ErrorResponse
 	// @Router /api/admin/reanalyze-recent [post]
-	router.POST("/api/admin/reanalyze-recent", SafeHandler(adminReanalyzeRecentHandler(llmClient, scoreManager, dbConn)))
+	router.POST("/api/admin/reanalyze-recent", adminAuth, audit("articles.reanalyze_recent"), SafeHandler(adminReanalyzeRecentHandler(llmClient, scoreManager, dbConn)))
 
 	// @Summary Clear analysis errors
 	// @Description Clears error states for articles with failed analysis
@@ -345,7 +481,7 @@ func RegisterRoutes(
 	// @Produce json
 	// @Success 200 {object} StandardResponse
 	// @Router /api/admin/clear-analysis-errors [post]
-	router.POST("/api/admin/clear-analysis-errors", SafeHandler(adminClearAnalysisErrorsHandler(dbConn)))
+	router.POST("/api/admin/clear-analysis-errors", adminAuth, audit("articles.clear_analysis_errors"), SafeHandler(adminClearAnalysisErrorsHandler(dbConn)))
 
 	// @Summary Validate bias scores
 	// @Description Validates consistency and validity of bias scores
@@ -354,7 +490,7 @@ func RegisterRoutes(
 	// @Produce json
 	// @Success 200 {object} StandardResponse
 	// @Router /api/admin/validate-scores [post]
-	router.POST("/api/admin/validate-scores", SafeHandler(adminValidateBiasScoresHandler(llmClient, scoreManager, dbConn)))
+	router.POST("/api/admin/validate-scores", adminAuth, audit("articles.validate_scores"), SafeHandler(adminValidateBiasScoresHandler(llmClient, scoreManager, dbConn)))
 
 	// @Summary Optimize database
 	// @Description Runs database optimization (VACUUM and ANALYZE)
@@ -364,7 +500,7 @@ func RegisterRoutes(
 	// @Success 200 {object} StandardResponse
 	// @Failure 500 {object} ErrorResponse
 	// @Router /api/admin/optimize-db [post]
-	router.POST("/api/admin/optimize-db", SafeHandler(adminOptimizeDatabaseHandler(dbConn)))
+	router.POST("/api/admin/optimize-db", adminAuth, audit("database.optimize"), SafeHandler(adminOptimizeDatabaseHandler(dbConn)))
 
 	// @Summary Export data
 	// @Description Exports articles and scores as CSV
@@ -372,7 +508,7 @@ func RegisterRoutes(
 	// @Produce text/csv
 	// @Success 200 {string} string "CSV file download"
 	// @Router /api/admin/export [get]
-	router.GET("/api/admin/export", SafeHandler(adminExportDataHandler(dbConn)))
+	router.GET("/api/admin/export", adminAuth, SafeHandler(adminExportDataHandler(dbConn)))
 
 	// @Summary Cleanup old articles
 	// @Description Deletes articles older than 30 days
@@ -382,7 +518,7 @@ func RegisterRoutes(
 	// @Success 200 {object} StandardResponse
 	// @Failure 500 {object} ErrorResponse
 	// @Router /api/admin/cleanup-old [delete]
-	router.DELETE("/api/admin/cleanup-old", SafeHandler(adminCleanupOldArticlesHandler(dbConn)))
+	router.DELETE("/api/admin/cleanup-old", adminAuth, audit("articles.cleanup_old"), SafeHandler(adminCleanupOldArticlesHandler(dbConn)))
 
 	// @Summary Get system metrics
 	// @Description Returns system statistics and metrics
@@ -391,7 +527,7 @@ func RegisterRoutes(
 	// @Produce json
 	// @Success 200 {object} SystemStatsResponse
 	// @Router /api/admin/metrics [get]
-	router.GET("/api/admin/metrics", SafeHandler(adminGetMetricsHandler(dbConn)))
+	router.GET("/api/admin/metrics", adminAuth, SafeHandler(adminGetMetricsHandler(dbConn)))
 
 	// @Summary Get system logs
 	// @Description Returns recent system log entries
@@ -400,7 +536,7 @@ func RegisterRoutes(
 	// @Produce json
 	// @Success 200 {object} StandardResponse
 	// @Router /api/admin/logs [get]
-	router.GET("/api/admin/logs", SafeHandler(adminGetLogsHandler()))
+	router.GET("/api/admin/logs", adminAuth, SafeHandler(adminGetLogsHandler()))
 
 	// @Summary Run health check
 	// @Description Performs comprehensive system health check
@@ -409,7 +545,101 @@ func RegisterRoutes(
 	// @Produce json
 	// @Success 200 {object} SystemHealthResponse
 	// @Router /api/admin/health-check [post]
-	router.POST("/api/admin/health-check", SafeHandler(adminRunHealthCheckHandler(dbConn, llmClient, rssCollector)))
+	router.POST("/api/admin/health-check", adminAuth, SafeHandler(adminRunHealthCheckHandler(dbConn, llmClient, rssCollector)))
+
+	// @Summary Run a scoring self-check
+	// @Description Scores a built-in sample article with every configured model and returns per-model status, the composite score, timings and whether the API key was accepted. Nothing is persisted.
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {object} StandardResponse{data=llm.SelfCheckResult}
+	// @Failure 503 {object} ErrorResponse
+	// @Router /api/admin/self-check [post]
+	router.POST("/api/admin/self-check", adminAuth, audit("llm.self_check"), SafeHandler(adminSelfCheckHandler(llmClient)))
+
+	router.GET("/api/admin/audit", adminAuth, SafeHandler(auditLogHandler(dbConn)))
+
+	// @Summary Get composite score config
+	// @Description Returns the composite score config file as stored, without the LLM_MODELS override
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {object} StandardResponse{data=llm.CompositeScoreConfig}
+	// @Failure 401 {object} ErrorResponse
+	// @Security ApiKeyAuth
+	// @Router /api/admin/config [get]
+	router.GET("/api/admin/config", adminAuth, SafeHandler(adminGetConfigHandler()))
+
+	// @Summary Replace composite score config
+	// @Description Validates the config, writes it to the config file and reloads it. Configs without
+	// @Description models, with negative weights or with an unknown formula are rejected.
+	// @Tags Admin
+	// @Accept json
+	// @Produce json
+	// @Param config body llm.CompositeScoreConfig true "New composite score config"
+	// @Success 200 {object} StandardResponse{data=llm.CompositeScoreConfig}
+	// @Failure 400 {object} ErrorResponse
+	// @Failure 401 {object} ErrorResponse
+	// @Security ApiKeyAuth
+	// @Router /api/admin/config [put]
+	router.PUT("/api/admin/config", adminAuth, bodyLimit, audit("config.update"), SafeHandler(adminUpdateConfigHandler(llmClient)))
+
+	// @Summary Get LLM API key health
+	// @Description Returns per-key request counts, failures and cooldown state for the configured LLM API keys (keys are masked)
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {object} StandardResponse
+	// @Failure 401 {object} ErrorResponse
+	// @Security ApiKeyAuth
+	// @Router /api/admin/llm/key-stats [get]
+	router.GET("/api/admin/llm/key-stats", adminAuth, SafeHandler(adminLLMKeyStatsHandler(llmClient)))
+
+	// @Summary Get cache statistics
+	// @Description Returns hit, miss and eviction counts and hit rates for the API response cache (per key prefix) and the LLM result cache (per model)
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {object} StandardResponse
+	// @Failure 401 {object} ErrorResponse
+	// @Security ApiKeyAuth
+	// @Router /api/admin/cache-stats [get]
+	router.GET("/api/admin/cache-stats", adminAuth, SafeHandler(adminCacheStatsHandler(llmClient)))
+
+	// @Summary Get score backfill status
+	// @Description Returns whether the background backfill of unscored articles is running, its
+	// @Description tuning, and counts of queued, scored and failed jobs since the server started
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {object} StandardResponse{data=ScoreBackfillStatus}
+	// @Failure 401 {object} ErrorResponse
+	// @Security ApiKeyAuth
+	// @Router /api/admin/score-backfill [get]
+	router.GET("/api/admin/score-backfill", adminAuth, SafeHandler(adminScoreBackfillStatusHandler(scoreBackfiller)))
+
+	// @Summary Start score backfill
+	// @Description Starts periodically queueing articles without a composite score for scoring,
+	// @Description respecting the reanalysis concurrency cap. Starting a running backfill does nothing.
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {object} StandardResponse{data=ScoreBackfillStatus}
+	// @Failure 401 {object} ErrorResponse
+	// @Failure 503 {object} ErrorResponse "Scoring is unavailable"
+	// @Security ApiKeyAuth
+	// @Router /api/admin/score-backfill/start [post]
+	router.POST("/api/admin/score-backfill/start", adminAuth, audit("score_backfill.start"),
+		SafeHandler(adminScoreBackfillStartHandler(scoreBackfiller)))
+
+	// @Summary Stop score backfill
+	// @Description Stops queueing unscored articles. Jobs already queued carry on.
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {object} StandardResponse{data=ScoreBackfillStatus}
+	// @Failure 401 {object} ErrorResponse
+	// @Security ApiKeyAuth
+	// @Router /api/admin/score-backfill/stop [post]
+	router.POST("/api/admin/score-backfill/stop", adminAuth, audit("score_backfill.stop"),
+		SafeHandler(adminScoreBackfillStopHandler(scoreBackfiller)))
+
+	router.POST("/api/admin/recompute", adminAuth, bodyLimit, audit("article.recompute_batch"),
+		SafeHandler(recomputeBatchHandler(llmClient, dbConn, scoreManager)))
+	router.POST("/api/admin/config/preview", adminAuth, SafeHandler(configPreviewHandler(llmClient, dbConn, scoreManager)))
 
 	// HTMX Admin Source Management Routes
 	router.GET("/htmx/sources", SafeHandler(adminSourcesListHandler(dbConn)))
@@ -417,8 +647,8 @@ func RegisterRoutes(
 	router.GET("/htmx/sources/:id/edit", SafeHandler(adminSourceFormHandler(dbConn)))
 	router.GET("/htmx/sources/:id/stats", SafeHandler(adminSourceStatsHandler(dbConn)))
 	// HTMX endpoints for source CRUD operations that return HTML
-	router.POST("/htmx/sources", SafeHandler(adminCreateSourceHandler(dbConn)))
-	router.PUT("/htmx/sources/:id", SafeHandler(adminUpdateSourceHandler(dbConn)))
+	router.POST("/htmx/sources", adminAuth, audit("source.create"), SafeHandler(adminCreateSourceHandler(dbConn)))
+	router.PUT("/htmx/sources/:id", adminAuth, audit("source.update"), SafeHandler(adminUpdateSourceHandler(dbConn)))
 }
 
 // SafeHandler wraps a handler function with panic recovery to prevent server crashes
@@ -452,6 +682,11 @@ func toArticleResponse(a *db.Article) ArticleResponse {
 		scoreSource = *a.ScoreSource
 	}
 
+	imageURL := ""
+	if a.ImageURL != nil {
+		imageURL = *a.ImageURL
+	}
+
 	return ArticleResponse{
 		ArticleID:   a.ID,
 		Source:      a.Source,
@@ -462,30 +697,40 @@ func toArticleResponse(a *db.Article) ArticleResponse {
 		Composite:   composite,
 		Confidence:  confidence,
 		ScoreSource: scoreSource,
+		ImageURL:    imageURL,
+		Authors:     a.Authors,
+		Categories:  a.Categories,
 	}
 }
 
 // Handler for POST /api/articles
 // @Summary Create article
-// @Description Creates a new article with the provided information
+// @Description Creates a new article with the provided information. "score": true queues it for
+// @Description scoring and "score": false skips scoring; when omitted the ingestion scoring policy
+// @Description decides. When scoring starts the response carries the job ID to follow on
+// @Description /api/llm/score-progress/{job_id}.
 // @Tags Articles
 // @Accept json
 // @Produce json
 // @Param request body CreateArticleRequest true "Article information"
-// @Success 200 {object} StandardResponse{data=CreateArticleResponse} "Article created successfully"
+// @Success 201 {object} StandardResponse{data=CreatedArticleResponse} "Article created successfully"
 // @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key"
 // @Failure 409 {object} ErrorResponse "Article URL already exists"
+// @Failure 413 {object} ErrorResponse "Request body too large"
 // @Failure 500 {object} ErrorResponse "Server error"
 // @Router /api/articles [post]
 // @ID createArticle
-func createArticleHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+func createArticleHandler(dbConn *sqlx.DB, ingestScorer *IngestScorer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req struct {
-			Source  string `json:"source"`
-			PubDate string `json:"pub_date"`
-			URL     string `json:"url"`
-			Title   string `json:"title"`
-			Content string `json:"content"`
+			Source      string `json:"source"`
+			PubDate     string `json:"pub_date"`
+			PublishedAt string `json:"published_at"`
+			URL         string `json:"url"`
+			Title       string `json:"title"`
+			Content     string `json:"content"`
+			Score       *bool  `json:"score"`
 		}
 		decoder := json.NewDecoder(c.Request.Body)
 		decoder.DisallowUnknownFields()
@@ -498,6 +743,11 @@ func createArticleHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			return
 		}
 
+		// published_at is the preferred name; pub_date is still accepted
+		if req.PubDate == "" {
+			req.PubDate = req.PublishedAt
+		}
+
 		// Validate required fields
 		var missingFields []string
 		if req.Source == "" {
@@ -513,7 +763,7 @@ func createArticleHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			missingFields = append(missingFields, "content")
 		}
 		if req.PubDate == "" {
-			missingFields = append(missingFields, "pub_date")
+			missingFields = append(missingFields, "published_at")
 		}
 
 		if len(missingFields) > 0 {
@@ -534,20 +784,14 @@ func createArticleHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			return
 		}
 		if exists {
-			c.JSON(http.StatusConflict, gin.H{
-				"success": false,
-				"error": gin.H{
-					"code":    "duplicate_url",
-					"message": "Article with this URL already exists",
-				},
-			})
+			RespondError(c, ErrDuplicateURL)
 			return
 		}
 
 		// Parse pub_date
 		pubDate, err := time.Parse(time.RFC3339, req.PubDate)
 		if err != nil {
-			RespondError(c, NewAppError(ErrValidation, "Invalid pub_date format (expected RFC3339)"))
+			RespondError(c, NewAppError(ErrValidation, "Invalid published_at format (expected RFC3339)"))
 			return
 		}
 		zero := 0.0
@@ -568,13 +812,7 @@ func createArticleHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 		id, err := db.InsertArticle(dbConn, article)
 		if err != nil {
 			if errors.Is(err, db.ErrDuplicateURL) {
-				c.JSON(http.StatusConflict, gin.H{
-					"success": false,
-					"error": gin.H{
-						"code":    "duplicate_url",
-						"message": "Article with this URL already exists",
-					},
-				})
+				RespondError(c, ErrDuplicateURL)
 				return
 			}
 			RespondError(c, WrapError(err, ErrInternal, "Failed to create article"))
@@ -582,13 +820,28 @@ func createArticleHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 		}
 
 		// Fetch the full article object after creation
-		createdArticle, err := db.FetchArticleByID(dbConn, id)
+		createdArticle, err := db.FetchArticleByIDContext(c.Request.Context(), dbConn, id)
 		if err != nil {
 			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch created article"))
 			return
 		}
 
-		resp := toArticleResponse(createdArticle)
+		resp := CreatedArticleResponse{ArticleResponse: toArticleResponse(createdArticle)}
+		switch {
+		case req.Score != nil && *req.Score:
+			resp.Scoring = ingestScorer.ScoreNow(id)
+		case req.Score == nil:
+			autoScore := false
+			if ingestScorer.Policy() == IngestScoringConditional {
+				if source, err := db.FetchSourceByName(dbConn, req.Source); err == nil {
+					autoScore = source.AutoScore()
+				}
+			}
+			resp.Scoring = ingestScorer.ArticleIngested(id, autoScore)
+		}
+		if resp.Scoring == IngestScoringImmediate || resp.Scoring == IngestScoringInProgress {
+			resp.JobID = id
+		}
 		c.JSON(http.StatusCreated, gin.H{
 			"success": true,
 			"data":    resp,
@@ -602,14 +855,32 @@ func createArticleHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 // Handler for GET /api/articles
 // @Summary Get articles
 // @Description Fetches a list of articles with optional filtering by source, leaning, and pagination
+// @Description Responds with JSON, CSV or XML according to the Accept header.
 // @Tags Articles
 // @Accept json
-// @Produce json
+// @Produce json,text/csv,application/xml
 // @Param source query string false "Filter by news source"
 // @Param leaning query string false "Filter by political leaning (left/center/right)"
+// @Param author query string false "Filter by author name (case-insensitive exact match)"
+// @Param category query string false "Filter by feed category (case-insensitive exact match)"
+// @Param tag query string false "Filter by editorial tag"
 // @Param offset query integer false "Pagination offset" default(0) minimum(0)
 // @Param limit query integer false "Number of items per page" default(20) minimum(1) maximum(100)
+// @Param min_score query number false "Minimum composite score" minimum(-1) maximum(1)
+// @Param max_score query number false "Maximum composite score" minimum(-1) maximum(1)
+// @Param min_confidence query number false "Minimum score confidence" minimum(0) maximum(1)
+// @Param published_after query string false "Published at or after this RFC3339 time"
+// @Param published_before query string false "Published before this RFC3339 time"
+// @Param ingested_after query string false "Ingested at or after this RFC3339 time"
+// @Param ingested_before query string false "Ingested before this RFC3339 time"
+// @Param fallback_to_ingested query boolean false "Apply the published bounds to the ingestion date of articles without a publication date"
+// @Param envelope query boolean false "Wrap the list with pagination metadata (default: ARTICLES_ENVELOPE_DEFAULT, false)"
+// @Param fields query string false "Comma-separated article fields to return in JSON and CSV (e.g. id,title,composite_score)"
 // @Success 200 {object} StandardResponse{data=[]ArticleResponse} "List of articles"
+// @Success 200 {object} PaginatedResponse{data=[]ArticleResponse} "List of articles with pagination metadata (envelope=true)"
+// @Header 200 {integer} X-Total-Count "Number of articles matching the filters"
+// @Failure 400 {object} ErrorResponse "Invalid parameters"
+// @Failure 406 {object} ErrorResponse "Unsupported Accept type"
 // @Failure 500 {object} ErrorResponse "Server error"
 // @Router /api/articles [get]
 // @ID getArticlesList
@@ -617,6 +888,15 @@ func getArticlesHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		safeLogf("[DEBUG] getArticlesHandler: Entered handler. Request: %s", c.Request.URL.String())
 
+		encoder, ok := negotiateArticleEncoder(c)
+		if !ok {
+			return
+		}
+		fields, ok := parseArticleFields(c)
+		if !ok {
+			return
+		}
+
 		source := c.Query("source")
 		leaning := c.Query("leaning")
 		limitStr := c.DefaultQuery("limit", "20")
@@ -637,11 +917,34 @@ func getArticlesHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			return
 		}
 
+		filter := db.ArticleFilter{
+			Source:   source,
+			Leaning:  leaning,
+			Author:   c.Query("author"),
+			Category: c.Query("category"),
+			Limit:    limit,
+			Offset:   offset,
+			// Article bodies are the bulk of a row; skip reading them when not requested
+			OmitContent: !fields.Has("content"),
+		}
+		if !parseArticleScoreFilters(c, &filter) || !parseArticleDateFilters(c, &filter) {
+			return
+		}
+		if v := c.Query("tag"); v != "" {
+			if filter.Tag, ok = normalizeTagParam(c, v); !ok {
+				return
+			}
+		}
+
+		envelope, ok := wantsEnvelope(c)
+		if !ok {
+			return
+		}
+
 		safeLogf("[INFO] getArticlesHandler: Fetching articles (source=%s, leaning=%s, limit=%d, offset=%d)", source, leaning, limit, offset)
 		// Corrected parameters for db.FetchArticles
 		safeLogf("[DEBUG] getArticlesHandler: Calling db.FetchArticles with source: '%s', leaning: '%s', limit: %d, offset: %d", source, leaning, limit, offset)
-		articles, err := db.FetchArticles(dbConn, source, leaning, limit, offset)
-		// totalCount is not returned by FetchArticles, so its usage is removed for now.
+		articles, err := db.FetchArticlesFiltered(dbConn, filter)
 		log.Printf("[DEBUG] getArticlesHandler: After db.FetchArticles. Error: %v. Articles count: %d", err, len(articles))
 
 		if err != nil {
@@ -650,54 +953,59 @@ func getArticlesHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			return
 		}
 
-		// Estimate totalCount for now. This should be replaced with a proper count query later.
-		totalCount := offset + len(articles)
-		if len(articles) == limit {
-			// If we fetched a full page, there might be more records.
-			// This is a rough estimation and might not be accurate.
-			// A separate COUNT(*) query would be more reliable.
-			totalCount += 1 // Placeholder to indicate more might exist
+		totalCount, err := db.CountArticlesFiltered(dbConn, filter)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to count articles"))
+			return
+		}
+		page := NewPagination(totalCount, limit, offset, len(articles))
+		writeArticles := func(out []ArticleResponse) {
+			c.Header("X-Total-Count", strconv.Itoa(totalCount))
+			if envelope {
+				encoder.WritePage(c, out, page, fields)
+			} else {
+				encoder.WriteList(c, out, fields)
+			}
 		}
 
 		if len(articles) == 0 {
-			c.Header("X-Total-Count", strconv.Itoa(totalCount))
-			c.JSON(http.StatusOK, gin.H{
-				"success": true,
-				"data":    []ArticleResponse{},
-			})
+			writeArticles([]ArticleResponse{})
 			return
 		}
 
-		// Enhance articles with composite scores and confidence (simplified error handling for now)
-		for i := range articles {
-			scores, fetchErr := db.FetchLLMScores(dbConn, articles[i].ID)
-			if fetchErr != nil {
-				log.Printf("WARNING: getArticlesHandler - Error fetching LLM scores for article ID %d: %v", articles[i].ID, fetchErr)
-			} else if len(scores) > 0 {
-				var weightedSum, sumWeights float64
-				validScoresCount := 0
-				for _, s := range scores {
-					var meta struct {
-						Confidence float64 `json:"confidence"`
-					}
-					if s.Metadata != "" {
-						if metaErr := json.Unmarshal([]byte(s.Metadata), &meta); metaErr != nil {
-							log.Printf("WARNING: getArticlesHandler - Error unmarshalling metadata for score ID %d (article ID %d): %v", s.ID, articles[i].ID, metaErr)
-							continue // Skip this score if metadata is malformed
+		// Enhance articles with composite scores and confidence (simplified error handling for now),
+		// unless the fieldset leaves both out
+		if fields.Has("composite_score") || fields.Has("confidence") {
+			for i := range articles {
+				scores, fetchErr := db.FetchLLMScoresContext(c.Request.Context(), dbConn, articles[i].ID)
+				if fetchErr != nil {
+					log.Printf("WARNING: getArticlesHandler - Error fetching LLM scores for article ID %d: %v", articles[i].ID, fetchErr)
+				} else if len(scores) > 0 {
+					var weightedSum, sumWeights float64
+					validScoresCount := 0
+					for _, s := range scores {
+						var meta struct {
+							Confidence float64 `json:"confidence"`
 						}
-					} else {
-						log.Printf("WARNING: getArticlesHandler - Empty metadata for score ID %d (article ID %d)", s.ID, articles[i].ID)
-						continue // Skip this score if metadata is empty
+						if s.Metadata != "" {
+							if metaErr := json.Unmarshal([]byte(s.Metadata), &meta); metaErr != nil {
+								log.Printf("WARNING: getArticlesHandler - Error unmarshalling metadata for score ID %d (article ID %d): %v", s.ID, articles[i].ID, metaErr)
+								continue // Skip this score if metadata is malformed
+							}
+						} else {
+							log.Printf("WARNING: getArticlesHandler - Empty metadata for score ID %d (article ID %d)", s.ID, articles[i].ID)
+							continue // Skip this score if metadata is empty
+						}
+						weightedSum += s.Score * meta.Confidence
+						sumWeights += meta.Confidence
+						validScoresCount++
+					}
+					if sumWeights > 0 && validScoresCount > 0 {
+						compositeScore := weightedSum / sumWeights
+						avgConfidence := sumWeights / float64(validScoresCount)
+						articles[i].CompositeScore = &compositeScore
+						articles[i].Confidence = &avgConfidence
 					}
-					weightedSum += s.Score * meta.Confidence
-					sumWeights += meta.Confidence
-					validScoresCount++
-				}
-				if sumWeights > 0 && validScoresCount > 0 {
-					compositeScore := weightedSum / sumWeights
-					avgConfidence := sumWeights / float64(validScoresCount)
-					articles[i].CompositeScore = &compositeScore
-					articles[i].Confidence = &avgConfidence
 				}
 			}
 		}
@@ -707,16 +1015,127 @@ func getArticlesHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			out = append(out, toArticleResponse(&articles[i]))
 		}
 
-		c.Header("X-Total-Count", strconv.Itoa(totalCount))
 		log.Printf("[DEBUG] getArticlesHandler: Preparing to send response. Number of articles: %d", len(out))
-		c.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"data":    out,
-		})
+		writeArticles(out)
 		log.Printf("[DEBUG] getArticlesHandler: Response sent successfully.")
 	}
 }
 
+// parseArticleScoreFilters reads the optional min_score, max_score and min_confidence
+// parameters into filter. Invalid or inverted bounds are answered with 400 and false.
+func parseArticleScoreFilters(c *gin.Context, filter *db.ArticleFilter) bool {
+	parse := func(name string, lo, hi float64) (*float64, bool) {
+		v, set := c.GetQuery(name)
+		if !set {
+			return nil, true
+		}
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(parsed) || parsed < lo || parsed > hi {
+			RespondError(c, NewAppError(ErrValidation, fmt.Sprintf("Invalid '%s' parameter, must be between %g and %g", name, lo, hi)))
+			return nil, false
+		}
+		return &parsed, true
+	}
+
+	var ok bool
+	if filter.MinScore, ok = parse("min_score", -1, 1); !ok {
+		return false
+	}
+	if filter.MaxScore, ok = parse("max_score", -1, 1); !ok {
+		return false
+	}
+	if filter.MinConfidence, ok = parse("min_confidence", 0, 1); !ok {
+		return false
+	}
+	if filter.MinScore != nil && filter.MaxScore != nil && *filter.MinScore > *filter.MaxScore {
+		RespondError(c, NewAppError(ErrValidation, "'min_score' must not be greater than 'max_score'"))
+		return false
+	}
+	return true
+}
+
+// parseArticleDateFilters reads the optional RFC3339 published_after, published_before,
+// ingested_after and ingested_before parameters and the fallback_to_ingested flag into
+// filter. Invalid or inverted bounds are answered with 400 and false.
+func parseArticleDateFilters(c *gin.Context, filter *db.ArticleFilter) bool {
+	parse := func(name string) (*time.Time, bool) {
+		v, set := c.GetQuery(name)
+		if !set {
+			return nil, true
+		}
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			RespondError(c, NewAppError(ErrValidation, fmt.Sprintf("Invalid '%s' parameter, must be an RFC3339 time", name)))
+			return nil, false
+		}
+		return &parsed, true
+	}
+	inverted := func(after, before *time.Time) bool {
+		return after != nil && before != nil && !after.Before(*before)
+	}
+
+	var ok bool
+	if filter.PublishedAfter, ok = parse("published_after"); !ok {
+		return false
+	}
+	if filter.PublishedBefore, ok = parse("published_before"); !ok {
+		return false
+	}
+	if filter.IngestedAfter, ok = parse("ingested_after"); !ok {
+		return false
+	}
+	if filter.IngestedBefore, ok = parse("ingested_before"); !ok {
+		return false
+	}
+	if inverted(filter.PublishedAfter, filter.PublishedBefore) {
+		RespondError(c, NewAppError(ErrValidation, "'published_after' must be before 'published_before'"))
+		return false
+	}
+	if inverted(filter.IngestedAfter, filter.IngestedBefore) {
+		RespondError(c, NewAppError(ErrValidation, "'ingested_after' must be before 'ingested_before'"))
+		return false
+	}
+	if v, set := c.GetQuery("fallback_to_ingested"); set {
+		fallback, err := strconv.ParseBool(v)
+		if err != nil {
+			RespondError(c, NewAppError(ErrValidation, "Invalid 'fallback_to_ingested' parameter, must be true or false"))
+			return false
+		}
+		filter.PublishedFallbackToIngested = fallback
+	}
+	return true
+}
+
+// wantsEnvelope reports whether the list response should carry pagination metadata,
+// from the envelope query parameter or else ARTICLES_ENVELOPE_DEFAULT. An invalid
+// parameter is answered with 400 and false for ok.
+func wantsEnvelope(c *gin.Context) (envelope bool, ok bool) {
+	if v, set := c.GetQuery("envelope"); set {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			RespondError(c, NewAppError(ErrValidation, "Invalid 'envelope' parameter, must be true or false"))
+			return false, false
+		}
+		return parsed, true
+	}
+	return envelopeByDefault(), true
+}
+
+// envelopeByDefault reports whether ARTICLES_ENVELOPE_DEFAULT makes the paginated
+// envelope the default list shape
+func envelopeByDefault() bool {
+	v := os.Getenv("ARTICLES_ENVELOPE_DEFAULT")
+	if v == "" {
+		return false
+	}
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("[WARN] Invalid ARTICLES_ENVELOPE_DEFAULT %q, using default false", v)
+		return false
+	}
+	return parsed
+}
+
 // Helper: Validate article ID from path param
 func getValidArticleID(c *gin.Context) (int64, bool) {
 	idStr := c.Param("id")
@@ -734,14 +1153,17 @@ func getValidArticleID(c *gin.Context) (int64, bool) {
 
 // getArticleByIDHandler handles GET /articles/:id
 // @Summary Get article by ID
-// @Description Fetches a specific article by its ID with scores and metadata
+// @Description Fetches a specific article by its ID with scores and metadata.
+// @Description Responds with JSON, CSV or XML according to the Accept header.
 // @Tags Articles
 // @Accept json
-// @Produce json
+// @Produce json,text/csv,application/xml
 // @Param id path int true "Article ID" minimum(1)
+// @Param fields query string false "Comma-separated article fields to return in JSON and CSV (e.g. id,title,composite_score)"
 // @Success 200 {object} StandardResponse "Success with article details"
 // @Failure 400 {object} ErrorResponse "Invalid article ID"
 // @Failure 404 {object} ErrorResponse "Article not found"
+// @Failure 406 {object} ErrorResponse "Unsupported Accept type"
 // @Failure 500 {object} ErrorResponse "Server error"
 // @Router /api/articles/{id} [get]
 // @ID getArticleById
@@ -752,6 +1174,15 @@ func getArticleByIDHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 		if !ok {
 			return
 		}
+		encoder, ok := negotiateArticleEncoder(c)
+		if !ok {
+			return
+		}
+		// The full article is fetched and cached either way; the fieldset only trims the response
+		fields, ok := parseArticleFields(c)
+		if !ok {
+			return
+		}
 
 		// Check for cache busting parameter
 		_, skipCache := c.GetQuery("_t")
@@ -760,9 +1191,10 @@ func getArticleByIDHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 		cacheKey := "article:" + strconv.FormatInt(id, 10)
 		if !skipCache {
 			articlesCacheLock.RLock()
-			if cached, found := articlesCache.Get(cacheKey); found {
+			var cached ArticleResponse
+			if cacheGetJSON(articlesCache, cacheKey, &cached) {
 				articlesCacheLock.RUnlock()
-				RespondSuccess(c, cached)
+				encoder.WriteOne(c, cached, fields)
 				LogPerformance("getArticleByIDHandler (cache hit)", start)
 				return
 			}
@@ -771,7 +1203,7 @@ func getArticleByIDHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			log.Printf("[getArticleByIDHandler] Cache busting requested for article %d", id)
 		}
 
-		article, err := db.FetchArticleByID(dbConn, id)
+		article, err := db.FetchArticleByIDContext(c.Request.Context(), dbConn, id)
 		if err != nil {
 			if errors.Is(err, db.ErrArticleNotFound) {
 				RespondError(c, ErrArticleNotFound)
@@ -786,13 +1218,10 @@ func getArticleByIDHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 
 		// Cache the result for 30 seconds
 		articlesCacheLock.Lock()
-		articlesCache.Set(cacheKey, resp, 30*time.Second)
+		cacheSetJSON(articlesCache, cacheKey, resp, 30*time.Second)
 		articlesCacheLock.Unlock()
 
-		c.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"data":    resp,
-		})
+		encoder.WriteOne(c, resp, fields)
 		LogPerformance("getArticleByIDHandler", start)
 	}
 }
@@ -853,15 +1282,19 @@ func translateReanalysisError(err error) (userMessage string, step string) {
 // Refactored reanalyzeHandler to use ScoreManager for scoring, storage, and progress
 // @Summary Reanalyze article
 // @Description Trigger a new LLM analysis for a specific article and update its scores.
+// @Description An article whose content is unchanged since its last analysis keeps its score
+// @Description without calling the LLM, and its progress reports skipped_unchanged, unless force is set.
 // @Tags LLM
 // @Accept json
 // @Produce json
 // @Param id path integer true "Article ID"
+// @Param force query boolean false "Reanalyze even when the content is unchanged (also accepted as \"force\": true in the body)"
 // @Success 202 {object} StandardResponse "Reanalysis started"
 // @Failure 400 {object} ErrorResponse "Invalid article ID"
 // @Failure 401 {object} ErrorResponse "LLM authentication failed"
 // @Failure 402 {object} ErrorResponse "LLM payment required or credits exhausted"
 // @Failure 404 {object} ErrorResponse "Article not found"
+// @Failure 409 {object} ErrorResponse "A reanalysis of the article is already queued or running"
 // @Failure 429 {object} ErrorResponse "LLM rate limit exceeded"
 // @Failure 500 {object} ErrorResponse "Server error"
 // @Failure 503 {object} ErrorResponse "LLM service unavailable or streaming error"
@@ -877,7 +1310,7 @@ func reanalyzeHandler(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *l
 		log.Printf("[POST /api/llm/reanalyze] ArticleID=%d", articleID)
 
 		// Verify article exists
-		_, err := db.FetchArticleByID(dbConn, articleID)
+		_, err := db.FetchArticleByIDContext(c.Request.Context(), dbConn, articleID)
 		if err != nil {
 			if errors.Is(err, db.ErrArticleNotFound) {
 				RespondError(c, ErrArticleNotFound)
@@ -931,7 +1364,7 @@ func reanalyzeHandler(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *l
 			}
 
 			confidence := 1.0 // Use maximum confidence for direct score updates
-			err = db.UpdateArticleScoreLLM(dbConn, articleID, scoreFloat, confidence)
+			err = db.UpdateArticleScoreLLMContext(c.Request.Context(), dbConn, articleID, scoreFloat, confidence)
 			if err != nil {
 				RespondError(c, NewAppError(ErrInternal, "Failed to update article score"))
 				LogError(c, err, "reanalyzeHandler: failed to update article score")
@@ -951,6 +1384,12 @@ func reanalyzeHandler(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *l
 			return
 		}
 
+		// force rescores even when the content is unchanged since the last analysis
+		force, _ := strconv.ParseBool(c.Query("force"))
+		if forceRaw, ok := raw["force"].(bool); ok {
+			force = force || forceRaw
+		}
+
 		// Load composite score config to get the models
 		cfg, cfgErr := llm.LoadCompositeScoreConfig()
 		if cfgErr != nil || len(cfg.Models) == 0 {
@@ -965,106 +1404,241 @@ func reanalyzeHandler(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *l
 			return
 		}
 
+		if scoreManager != nil && scoreManager.ShuttingDown() {
+			RespondError(c, ErrShuttingDown)
+			return
+		}
+
 		log.Printf("[reanalyzeHandler %d] Proceeding with reanalysis - ReanalyzeArticle will handle model fallbacks", articleID)
 
-		// Start the reanalysis process
-		if scoreManager != nil {
-			// Set initial progress BEFORE responding to the client
-			initialProgress := &models.ProgressState{
-				Status:  "Queued",
-				Step:    "Pending",
-				Message: "Reanalysis queued for all configured models",
+		// Start the reanalysis process; a reanalysis already underway is left to finish
+		if err := startReanalysisJob(llmClient, dbConn, scoreManager, articleID, force); errors.Is(err, llm.ErrJobInProgress) {
+			RespondError(c, reanalysisInProgressError(articleID))
+			return
+		}
+
+		// HTMX pages open their progress panel when they see this event
+		if c.GetHeader("HX-Request") == "true" {
+			c.Header("HX-Trigger", ReanalysisStartedEvent)
+		}
+		RespondSuccess(c, map[string]interface{}{
+			"status":     "reanalyze queued",
+			"article_id": articleID,
+		})
+	}
+}
+
+// reanalysisInProgressError is the 409 for a second reanalysis of an article, pointing
+// the client at the running job's progress so it can follow that instead
+func reanalysisInProgressError(articleID int64) *apperrors.AppError {
+	return WithDetails(ErrReanalysisInProgress, map[string]interface{}{
+		"article_id":   articleID,
+		"progress_url": fmt.Sprintf("/api/llm/score-progress/%d", articleID),
+	})
+}
+
+// ReanalysisStartedEvent is the HX-Trigger event sent to HTMX requests that queue a
+// reanalysis
+const ReanalysisStartedEvent = "reanalysis-started"
+
+// startReanalysisJob queues a background reanalysis of articleID. Progress is reported
+// through scoreManager under the article ID, so /api/llm/score-progress/{id} follows it
+// and DELETE /api/llm/reanalyze/{id} cancels it. Unless force is set, an article whose
+// content is unchanged since its last analysis keeps its score. It fails with
+// llm.ErrJobInProgress, leaving the running job alone, when the article is already
+// being reanalysed.
+func startReanalysisJob(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *llm.ScoreManager, articleID int64, force bool) error {
+	return startReanalysisJobThen(llmClient, dbConn, scoreManager, articleID, force, nil)
+}
+
+// errAutoAnalyzeDisabled is reported to job callbacks when NO_AUTO_ANALYZE skips the job
+var errAutoAnalyzeDisabled = errors.New("automatic analysis disabled by NO_AUTO_ANALYZE")
+
+// startReanalysisJobThen is startReanalysisJob, calling onDone, when not nil, with the
+// job's outcome once it has finished: nil on success, context.Canceled when cancelled.
+// A job refused with llm.ErrJobInProgress is reported to onDone straight away.
+func startReanalysisJobThen(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *llm.ScoreManager, articleID int64, force bool, onDone func(error)) error {
+	finish := func(err error) {
+		if onDone != nil {
+			onDone(err)
+		}
+	}
+	if scoreManager != nil {
+		// Check for an environment variable to skip auto-analysis during tests
+		autoAnalyze := os.Getenv("NO_AUTO_ANALYZE") != "true"
+		var jobCtx context.Context
+		var jobDone func()
+		if autoAnalyze {
+			// Take the article's job lock before touching its progress, so a second
+			// request cannot overwrite the progress of the job already running.
+			// DELETE /api/llm/reanalyze/:id cancels the registered job.
+			var err error
+			jobCtx, jobDone, err = scoreManager.TryStartJob(context.Background(), articleID)
+			if err != nil {
+				log.Printf("[reanalysis %d] Not starting reanalysis: %v", articleID, err)
+				finish(err)
+				return err
 			}
-			log.Printf("[reanalyzeHandler %d] Setting initial progress: %+v", articleID, initialProgress)
-			scoreManager.SetProgress(articleID, initialProgress)
-
-			// Check for an environment variable to skip auto-analysis during tests
-			if os.Getenv("NO_AUTO_ANALYZE") != "true" {
-				go func() {
-					// Pass scoreManager to ReanalyzeArticle
-					err := llmClient.ReanalyzeArticle(context.Background(), articleID, scoreManager)
-					if err != nil {
-						log.Printf("[reanalyzeHandler %d] Error during reanalysis: %v", articleID, err)
-						// Ensure scoreManager is not nil before using
-						if scoreManager != nil {
-							userMessage, step := translateReanalysisError(err)
-							scoreManager.SetProgress(articleID, &models.ProgressState{
-								Status:  "Error",
-								Step:    step,
-								Message: userMessage,
-								Error:   err.Error(), // Keep technical error for debugging
-							})
-						}
-						return
-					}
+		}
+
+		// Set initial progress BEFORE responding to the client
+		initialProgress := &models.ProgressState{
+			Status:  "Queued",
+			Step:    "Pending",
+			Message: "Reanalysis queued for all configured models",
+		}
+		log.Printf("[reanalysis %d] Setting initial progress: %+v", articleID, initialProgress)
+		scoreManager.SetProgress(articleID, initialProgress)
+
+		if autoAnalyze {
+			go func() {
+				defer jobDone()
+				// Wait for a free slot; the progress stream shows the queue position meanwhile
+				releaseSlot, err := scoreManager.AcquireJobSlot(jobCtx, articleID)
+				if err != nil {
+					log.Printf("[reanalysis %d] Reanalysis cancelled while queued", articleID)
+					scoreManager.MarkCancelled(articleID)
+					finish(context.Canceled)
+					return
+				}
+				defer releaseSlot()
+				// Pass scoreManager to ReanalyzeArticle
+				err = llmClient.ReanalyzeArticleWithOptions(jobCtx, articleID, scoreManager, llm.ReanalyzeOptions{Force: force})
+				if errors.Is(err, context.Canceled) {
+					log.Printf("[reanalysis %d] Reanalysis cancelled", articleID)
+					scoreManager.MarkCancelled(articleID)
+					finish(err)
+					return
+				}
+				if err != nil {
+					defer finish(err)
+					log.Printf("[reanalysis %d] Error during reanalysis: %v", articleID, err)
 					// Ensure scoreManager is not nil before using
 					if scoreManager != nil {
-						// Fetch the final score to include in the progress update
-						finalProgressState := scoreManager.GetProgress(articleID)
-						var finalScore *float64
-						article, fetchErr := db.FetchArticleByID(dbConn, articleID)
-						if fetchErr == nil && article.CompositeScore != nil {
-							finalScore = article.CompositeScore
-						} else if fetchErr != nil {
-							log.Printf("[reanalyzeHandler %d] Could not fetch article to get final score for progress: %v", articleID, fetchErr)
-						} else {
-							log.Printf("[reanalyzeHandler %d] Article fetched but composite score is nil for progress update.", articleID)
-						}
+						userMessage, step := translateReanalysisError(err)
+						scoreManager.SetProgress(articleID, &models.ProgressState{
+							Status:  "Error",
+							Step:    step,
+							Message: userMessage,
+							Error:   err.Error(), // Keep technical error for debugging
+						})
+					}
+					return
+				}
+				// Ensure scoreManager is not nil before using
+				if scoreManager != nil {
+					// Fetch the final score to include in the progress update
+					finalProgressState := scoreManager.GetProgress(articleID)
+					var finalScore *float64
+					article, fetchErr := db.FetchArticleByID(dbConn, articleID)
+					if fetchErr == nil && article.CompositeScore != nil {
+						finalScore = article.CompositeScore
+					} else if fetchErr != nil {
+						log.Printf("[reanalysis %d] Could not fetch article to get final score for progress: %v", articleID, fetchErr)
+					} else {
+						log.Printf("[reanalysis %d] Article fetched but composite score is nil for progress update.", articleID)
+					}
 
-						// If the ReanalyzeArticle function set a near-complete state, update it to full "Complete"
-						// Otherwise, create a new one.
-						log.Printf("[reanalyzeHandler %d] Current progress state: %+v", articleID, finalProgressState)
-						if finalProgressState != nil && finalProgressState.Status == "InProgress" && finalProgressState.Percent == 99 {
-							log.Printf("[reanalyzeHandler %d] Updating existing progress state to Complete", articleID)
-							finalProgressState.Status = "Complete"
-							finalProgressState.Step = "Done"
-							finalProgressState.Message = "Analysis complete"
-							finalProgressState.Percent = 100
-							finalProgressState.FinalScore = finalScore
-							scoreManager.SetProgress(articleID, finalProgressState)
-						} else {
-							log.Printf("[reanalyzeHandler %d] Setting new Complete progress state", articleID)
-							scoreManager.SetProgress(articleID, &models.ProgressState{
-								Status:     "Complete",
-								Step:       "Done",
-								Message:    "Analysis complete",
-								Percent:    100,
-								FinalScore: finalScore, // Include final score if available
-							})
+					// If the ReanalyzeArticle function set a near-complete state, update it to full "Complete"
+					// Otherwise, create a new one.
+					log.Printf("[reanalysis %d] Current progress state: %+v", articleID, finalProgressState)
+					if finalProgressState != nil && finalProgressState.Status == "InProgress" && finalProgressState.Percent == 99 {
+						log.Printf("[reanalysis %d] Updating existing progress state to Complete", articleID)
+						finalProgressState.Status = "Complete"
+						finalProgressState.Step = "Done"
+						finalProgressState.Message = "Analysis complete"
+						if finalProgressState.SkippedUnchanged {
+							finalProgressState.Message = "Content unchanged since the last analysis; kept the existing score"
 						}
+						finalProgressState.Percent = 100
+						finalProgressState.FinalScore = finalScore
+						scoreManager.SetProgress(articleID, finalProgressState)
+					} else {
+						log.Printf("[reanalysis %d] Setting new Complete progress state", articleID)
+						scoreManager.SetProgress(articleID, &models.ProgressState{
+							Status:     "Complete",
+							Step:       "Done",
+							Message:    "Analysis complete",
+							Percent:    100,
+							FinalScore: finalScore, // Include final score if available
+						})
 					}
-				}()
-			} else {
-				log.Printf("[reanalyzeHandler %d] NO_AUTO_ANALYZE is set, skipping background reanalysis.", articleID)
-				// Optionally, set progress to complete or a specific "skipped" state
-				scoreManager.SetProgress(articleID, &models.ProgressState{
-					Status:      "Skipped", // Ensure this status is handled by SSE or test
-					Step:        "Skipped",
-					Message:     "Automatic reanalysis skipped by test configuration.",
-					Percent:     100,
-					LastUpdated: time.Now().Unix(),
-				})
-			}
+				}
+				finish(nil)
+			}()
 		} else {
-			log.Printf("[reanalyzeHandler %d] ScoreManager is nil, cannot set progress.", articleID)
+			log.Printf("[reanalysis %d] NO_AUTO_ANALYZE is set, skipping background reanalysis.", articleID)
+			// Optionally, set progress to complete or a specific "skipped" state
+			scoreManager.SetProgress(articleID, &models.ProgressState{
+				Status:      "Skipped", // Ensure this status is handled by SSE or test
+				Step:        "Skipped",
+				Message:     "Automatic reanalysis skipped by test configuration.",
+				Percent:     100,
+				LastUpdated: time.Now().Unix(),
+			})
+			finish(errAutoAnalyzeDisabled)
+		}
+	} else {
+		log.Printf("[reanalysis %d] ScoreManager is nil, cannot set progress.", articleID)
+		finish(errors.New("score manager unavailable"))
+	}
+	return nil
+}
+
+// @Summary Cancel reanalysis
+// @Description Cancel the in-flight reanalysis of an article. Scores computed so far are discarded
+// @Description and progress subscribers receive a final "Cancelled" status.
+// @Tags LLM
+// @Produce json
+// @Param id path integer true "Article ID"
+// @Success 200 {object} StandardResponse "Cancellation requested"
+// @Failure 400 {object} ErrorResponse "Invalid article ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "No reanalysis in progress"
+// @Security ApiKeyAuth
+// @Router /api/llm/reanalyze/{id} [delete]
+// @ID cancelReanalysis
+func cancelReanalysisHandler(scoreManager *llm.ScoreManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		articleID, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+
+		if scoreManager == nil || !scoreManager.CancelJob(articleID) {
+			RespondError(c, NewAppError(ErrNotFound, "No reanalysis in progress for this article"))
+			return
 		}
 
+		log.Printf("[DELETE /api/llm/reanalyze] ArticleID=%d cancellation requested", articleID)
 		RespondSuccess(c, map[string]interface{}{
-			"status":     "reanalyze queued",
+			"status":     "cancelling",
 			"article_id": articleID,
 		})
 	}
 }
 
 // @Summary   Stream LLM scoring progress
+// @Description Streams progress as server-sent events. Clients that cannot use SSE can poll
+// @Description instead: with mode=poll, or an Accept header asking for application/json
+// @Description but not text/event-stream, the current progress is returned once as JSON.
 // @Produce   text/event-stream
+// @Produce   json
 // @Param     id  path  int  true  "Article ID"
-// @Success   200  {object} models.ProgressState  "SSE stream of progress updates"
+// @Param     mode  query  string  false  "poll for a JSON snapshot instead of the stream"
+// @Param     Last-Event-ID  header  string  false  "ID of the last event received, to resume a dropped stream"
+// @Success   200  {object} ProgressSnapshot  "SSE stream of progress updates, or a snapshot when polling"
 // @Failure   400  {object} StandardResponse
+// @Failure   404  {object} ErrorResponse  "No progress for the article when polling"
 // @Router    /api/llm/score-progress/{id} [get]
 // @ID getScoreProgress
 func scoreProgressSSEHandler(scoreManager *llm.ScoreManager) gin.HandlerFunc {
+	heartbeatInterval := SSEHeartbeatInterval()
 	return func(c *gin.Context) {
+		if wantsProgressPolling(c) {
+			scoreProgressSnapshot(c, scoreManager)
+			return
+		}
 		id, ok := getValidArticleID(c)
 		if !ok {
 			// It's important to set headers before writing the body for SSE
@@ -1087,55 +1661,85 @@ func scoreProgressSSEHandler(scoreManager *llm.ScoreManager) gin.HandlerFunc {
 		c.Writer.Flush()
 
 		lastProgressJSON := ""
+		var lastEventID int64
+		// A reconnecting EventSource sends the ID of the last event it received
+		if header := c.GetHeader("Last-Event-ID"); header != "" {
+			if parsed, parseErr := strconv.ParseInt(header, 10, 64); parseErr == nil && parsed > 0 {
+				lastEventID = parsed
+			}
+		}
 		ticker := time.NewTicker(250 * time.Millisecond) // Reduced ticker for faster updates during debugging
+		// Keepalive comments stop proxies from dropping idle streams during long jobs
+		heartbeat := time.NewTicker(heartbeatInterval)
 		defer func() {
 			ticker.Stop()
+			heartbeat.Stop()
 			log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Connection closed.", articleID)
 		}()
 
-		// Send an initial "connected" or "pending" event immediately
-		// This helps confirm the connection is working before the first tick
+		// Send the current state immediately. Jobs unknown to the progress manager were
+		// never started or have already been cleaned up, so tell the client right away
+		// instead of leaving the stream open with nothing to report.
 		initialState := &models.ProgressState{
 			Status:  "Connected",
 			Step:    "Initializing",
 			Message: "SSE connection established, awaiting progress.",
 			Percent: 0,
 		}
+		var initialEventID int64
 		if scoreManager != nil {
-			// Check if there's already an initial state (e.g. "Queued" from reanalyzeHandler)
-			// If so, send that instead of a generic "Connected"
-			existingState := scoreManager.GetProgress(articleID)
-			if existingState != nil {
-				initialState = existingState
-				log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Found existing initial state: %+v", articleID, initialState)
-			} else {
-				log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: No existing state, sending generic 'Connected' state.", articleID)
+			existingState, eventID := scoreManager.GetProgressWithEventID(articleID)
+			if existingState == nil {
+				log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: No progress state, job not found or expired.", articleID)
+				writeSSEJobExpired(c)
+				return
 			}
+			initialState = existingState
+			initialEventID = eventID
+			log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Found existing initial state: %+v", articleID, initialState)
 		} else {
 			log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: ScoreManager is nil, cannot fetch initial state.", articleID)
 		}
 
 		initialData, err := json.Marshal(initialState)
-		if err == nil {
+		if err == nil && lastEventID > 0 && initialEventID > 0 && lastEventID >= initialEventID {
+			// The client already received the current state before reconnecting
+			log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Resuming after event %d, client is up to date.", articleID, lastEventID)
+			lastProgressJSON = string(initialData)
+		} else if err == nil {
 			log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Sending initial event: event: progress, data: %s", articleID, string(initialData))
-			if _, writeErr := fmt.Fprintf(c.Writer, "event: progress\ndata: %s\n\n", initialData); writeErr != nil {
+			if writeErr := writeSSEProgress(c, initialEventID, initialData); writeErr != nil {
 				log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Error writing initial SSE event: %v", articleID, writeErr)
 				// Don't return here, try to continue with the ticker
 			}
-			c.Writer.Flush() // Ensure the initial event is sent immediately
+			lastProgressJSON = string(initialData)
+			lastEventID = initialEventID
 		} else {
 			log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Error marshalling initial state: %v", articleID, err)
 		}
 
+		if scoreManager != nil && llm.IsTerminalProgressStatus(initialState.Status) {
+			log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Job already finished with status '%s'. Closing SSE stream.", articleID, initialState.Status)
+			scoreManager.MarkProgressObserved(articleID)
+			return
+		}
+
 		for {
 			select {
 			case <-c.Request.Context().Done():
 				log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Client disconnected.", articleID)
 				return
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(c.Writer, ":keepalive\n\n"); err != nil {
+					log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Error writing keepalive: %v", articleID, err)
+					return
+				}
+				c.Writer.Flush()
 			case <-ticker.C:
 				var progress *models.ProgressState
+				var eventID int64
 				if scoreManager != nil {
-					progress = scoreManager.GetProgress(articleID)
+					progress, eventID = scoreManager.GetProgressWithEventID(articleID)
 				} else {
 					log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: ScoreManager is nil in ticker loop.", articleID)
 					// Optionally send an error event or just continue
@@ -1143,12 +1747,12 @@ func scoreProgressSSEHandler(scoreManager *llm.ScoreManager) gin.HandlerFunc {
 				}
 
 				if progress == nil {
-					// log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: No progress update found.", articleID)
-					continue // No progress update yet
+					// The job was cleaned up while we were streaming it
+					log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Progress state disappeared, job expired.", articleID)
+					writeSSEJobExpired(c)
+					return
 				}
 
-				// log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Fetched progress: %+v", articleID, progress)
-
 				data, err := json.Marshal(progress)
 				if err != nil {
 					log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Error marshalling progress: %v", articleID, err)
@@ -1156,28 +1760,25 @@ func scoreProgressSSEHandler(scoreManager *llm.ScoreManager) gin.HandlerFunc {
 				}
 
 				currentProgressJSON := string(data)
-				if currentProgressJSON != lastProgressJSON {
+				if currentProgressJSON != lastProgressJSON || eventID != lastEventID {
 					log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Sending progress update: %s", articleID, currentProgressJSON)
-					if _, err := fmt.Fprintf(c.Writer, "event: progress\ndata: %s\n\n", data); err != nil {
+					if err := writeSSEProgress(c, eventID, data); err != nil {
 						log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Error writing to client: %v", articleID, err)
 						return // Stop if we can't write to client
 					}
-					c.Writer.Flush() // Ensure data is sent immediately
 					lastProgressJSON = currentProgressJSON
+					lastEventID = eventID
 
 					// Check for terminal states
-					if progress.Status == "Complete" || progress.Status == "Error" || progress.Status == "Success" {
+					if llm.IsTerminalProgressStatus(progress.Status) {
 						log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Terminal progress status '%s' received. Closing SSE stream.", articleID, progress.Status)
+						scoreManager.MarkProgressObserved(articleID)
 
 						// For "Complete" status, delay closure to allow frontend to process and display completion
 						if progress.Status == "Complete" {
 							log.Printf("[SSE HANDLER /api/llm/score-progress] ArticleID=%d: Delaying SSE closure for 3 seconds to allow frontend completion processing.", articleID)
 							time.Sleep(3 * time.Second)
 						}
-
-						// Send one last time to be sure, then close.
-						// It's possible the client might miss this if we return immediately.
-						// However, the test client should see the event and then the connection close.
 						return
 					}
 				} else {
@@ -1189,6 +1790,76 @@ func scoreProgressSSEHandler(scoreManager *llm.ScoreManager) gin.HandlerFunc {
 	}
 }
 
+// writeSSEProgress sends a progress event, tagged with its ID when known so that
+// clients can resume with Last-Event-ID after a dropped connection
+func writeSSEProgress(c *gin.Context, eventID int64, data []byte) error {
+	var err error
+	if eventID > 0 {
+		_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: progress\ndata: %s\n\n", eventID, data)
+	} else {
+		_, err = fmt.Fprintf(c.Writer, "event: progress\ndata: %s\n\n", data)
+	}
+	c.Writer.Flush() // Ensure data is sent immediately
+	return err
+}
+
+// defaultSSEHeartbeatInterval is used when SSE_HEARTBEAT_INTERVAL is unset or invalid
+const defaultSSEHeartbeatInterval = 15 * time.Second
+
+// SSEHeartbeatInterval returns the keepalive interval for progress streams, set by
+// SSE_HEARTBEAT_INTERVAL
+func SSEHeartbeatInterval() time.Duration {
+	if v := os.Getenv("SSE_HEARTBEAT_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err == nil && d > 0 {
+			return d
+		}
+		log.Printf("[WARN] Invalid SSE_HEARTBEAT_INTERVAL %q, using default %v", v, defaultSSEHeartbeatInterval)
+	}
+	return defaultSSEHeartbeatInterval
+}
+
+// sseJobExpiredMessage is sent when a progress stream has no job to report on
+const sseJobExpiredMessage = "job not found or expired"
+
+// writeSSEJobExpired sends a terminal error event for an unknown or cleaned up job
+func writeSSEJobExpired(c *gin.Context) {
+	if _, err := fmt.Fprintf(c.Writer, "event: error\ndata: {\"error\":%q}\n\n", sseJobExpiredMessage); err != nil {
+		log.Printf("[SSE HANDLER] Error writing job expired event: %v", err)
+	}
+	c.Writer.Flush()
+}
+
+// wantsProgressPolling reports whether a score progress request asks for a JSON snapshot
+// rather than the event stream: mode=poll, or an Accept header preferring JSON
+func wantsProgressPolling(c *gin.Context) bool {
+	if c.Query("mode") == "poll" {
+		return true
+	}
+	accept := c.GetHeader("Accept")
+	return strings.Contains(accept, "application/json") && !strings.Contains(accept, "text/event-stream")
+}
+
+// scoreProgressSnapshot responds with the article's current progress, for clients that
+// poll instead of streaming. The event ID lets them skip snapshots they have seen.
+func scoreProgressSnapshot(c *gin.Context, scoreManager *llm.ScoreManager) {
+	articleID, ok := getValidArticleID(c)
+	if !ok {
+		return
+	}
+	var state *models.ProgressState
+	var eventID int64
+	if scoreManager != nil {
+		state, eventID = scoreManager.GetProgressWithEventID(articleID)
+	}
+	if state == nil {
+		RespondError(c, NewAppError(ErrNotFound, "Scoring "+sseJobExpiredMessage))
+		return
+	}
+	c.Header("Cache-Control", "no-cache")
+	RespondSuccess(c, ProgressSnapshot{ProgressState: *state, EventID: eventID})
+}
+
 // @Summary Get RSS feed health status
 // @Description Returns the health status of all configured RSS feeds
 // @Tags Feeds
@@ -1232,6 +1903,7 @@ func llmHealthHandler(llmClient *llm.LLMClient) gin.HandlerFunc {
 			// Determine appropriate HTTP status code based on error
 			var statusCode int
 			var errorType string
+			code := ErrLLMService
 
 			errStr := strings.ToLower(err.Error())
 			switch {
@@ -1244,6 +1916,7 @@ func llmHealthHandler(llmClient *llm.LLMClient) gin.HandlerFunc {
 			case strings.Contains(errStr, "rate limited"):
 				statusCode = 429
 				errorType = "rate_limited"
+				code = ErrRateLimit
 			case strings.Contains(errStr, "timeout") || strings.Contains(errStr, "unavailable"):
 				statusCode = 503
 				errorType = "service_unavailable"
@@ -1252,11 +1925,13 @@ func llmHealthHandler(llmClient *llm.LLMClient) gin.HandlerFunc {
 				errorType = "unknown_error"
 			}
 
-			c.JSON(statusCode, gin.H{
-				"success": false,
-				"error": gin.H{
-					"type":    errorType,
-					"message": err.Error(),
+			// The status depends on the provider's answer rather than the code alone
+			c.JSON(statusCode, ErrorResponse{
+				Success: false,
+				Error: ErrorDetail{
+					Code:    code,
+					Message: err.Error(),
+					Details: map[string]interface{}{"type": errorType},
 				},
 			})
 			return
@@ -1300,7 +1975,7 @@ func (h *SummaryHandler) Handle(c *gin.Context) {
 	articlesCacheLock.RLock()
 	if cached, found := articlesCache.Get(cacheKey); found {
 		articlesCacheLock.RUnlock()
-		RespondSuccess(c, cached)
+		RespondSuccess(c, json.RawMessage(cached))
 		LogPerformance("summaryHandler (cache hit)", start)
 		return
 	}
@@ -1338,7 +2013,7 @@ func (h *SummaryHandler) Handle(c *gin.Context) {
 				"created_at": score.CreatedAt,
 			}
 			articlesCacheLock.Lock()
-			articlesCache.Set(cacheKey, result, 30*time.Second)
+			cacheSetJSON(articlesCache, cacheKey, result, 30*time.Second)
 			articlesCacheLock.Unlock()
 
 			RespondSuccess(c, result)
@@ -1351,6 +2026,13 @@ func (h *SummaryHandler) Handle(c *gin.Context) {
 	LogPerformance("summaryHandler", start)
 }
 
+// wantsAggregationDebug reports whether the request asks for the intermediate
+// aggregation math with include_debug=true
+func wantsAggregationDebug(c *gin.Context) bool {
+	debug, _ := strconv.ParseBool(c.Query("include_debug"))
+	return debug
+}
+
 // biasHandler returns article bias scores and composite score.
 // @Summary Get article bias analysis
 // @Description Retrieves the political bias score and individual model results for an article
@@ -1361,8 +2043,10 @@ func (h *SummaryHandler) Handle(c *gin.Context) {
 // @Param min_score query number false "Minimum score filter" default(-1) minimum(-1) maximum(1)
 // @Param max_score query number false "Maximum score filter" default(1) minimum(-1) maximum(1)
 // @Param sort query string false "Sort order (asc or desc)" Enums(asc, desc) default(desc)
+// @Param include_debug query boolean false "Add the intermediate aggregation math as debug (admin key required)"
 // @Success 200 {object} StandardResponse{data=ScoreResponse} "Success"
 // @Failure 400 {object} ErrorResponse "Invalid parameters"
+// @Failure 401 {object} ErrorResponse "include_debug without the admin key"
 // @Failure 404 {object} ErrorResponse "Article not found"
 // @Failure 500 {object} ErrorResponse "Server error"
 // @Router /api/articles/{id}/bias [get]
@@ -1400,20 +2084,23 @@ func biasHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			return
 		}
 
-		// Caching
+		// Caching; debug responses are always computed afresh
+		debug := wantsAggregationDebug(c)
 		cacheKey := "bias:" + strconv.FormatInt(id, 10) + ":" +
 			c.DefaultQuery("min_score", "-1") + ":" +
 			c.DefaultQuery("max_score", "1") + ":" + sortOrder
-		articlesCacheLock.RLock()
-		if cached, found := articlesCache.Get(cacheKey); found {
+		if !debug {
+			articlesCacheLock.RLock()
+			if cached, found := articlesCache.Get(cacheKey); found {
+				articlesCacheLock.RUnlock()
+				RespondSuccess(c, json.RawMessage(cached))
+				LogPerformance("biasHandler (cache hit)", start)
+				return
+			}
 			articlesCacheLock.RUnlock()
-			RespondSuccess(c, cached)
-			LogPerformance("biasHandler (cache hit)", start)
-			return
 		}
-		articlesCacheLock.RUnlock()
 
-		scores, err := db.FetchLLMScores(dbConn, id)
+		scores, err := db.FetchLLMScoresContext(c.Request.Context(), dbConn, id)
 		if err != nil {
 			RespondError(c, NewAppError(ErrInternal, "Failed to fetch bias data"))
 			LogError(c, err, "biasHandler: fetch scores")
@@ -1421,13 +2108,16 @@ func biasHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 		}
 
 		var latestEnsembleScore *db.LLMScore
+		var manualScore *db.LLMScore
 		individualResults := make([]map[string]interface{}, 0)
 
 		// Find the latest ensemble score and gather individual scores
 		for i := range scores {
 			score := scores[i] // Create a copy to avoid loop variable issues if needed later
 
-			if score.Model == ModelEnsemble {
+			if score.Model == db.ManualScoreModel {
+				manualScore = &score
+			} else if score.Model == ModelEnsemble {
 				if latestEnsembleScore == nil || score.CreatedAt.After(latestEnsembleScore.CreatedAt) {
 					latestEnsembleScore = &score // Store pointer to the score
 				}
@@ -1506,17 +2196,53 @@ func biasHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 		resp := map[string]interface{}{
 			"composite_score": compositeScoreValue,
 			"results":         individualResults,
+			"neutral_zone":    models.NeutralZone(),
 		}
 		// Add status only if it's set (i.e., no ensemble score found)
 		if status != "" {
 			resp["status"] = status
 		}
+		// The interval reflects how far the individual models disagree
+		if latestEnsembleScore != nil {
+			interval := llm.CompositeScoreInterval(latestEnsembleScore.Score, scores)
+			resp["score_low"] = interval.Low
+			resp["score_high"] = interval.High
+			resp["interval_models"] = interval.Models
+			resp["interval_reliable"] = interval.Reliable
+		}
+
+		// A manual override takes precedence; the model score is still reported
+		if manualScore != nil {
+			var meta db.ManualScoreMetadata
+			if err := json.Unmarshal([]byte(manualScore.Metadata), &meta); err != nil {
+				log.Printf("WARN: biasHandler: Failed to unmarshal manual score metadata for article %d: %v", id, err)
+			}
+			resp["composite_score"] = manualScore.Score
+			resp["model_composite_score"] = compositeScoreValue
+			resp["score_source"] = db.ScoreSourceManual
+			resp["manual_override"] = map[string]interface{}{
+				"score":      manualScore.Score,
+				"reason":     meta.Reason,
+				"created_at": manualScore.CreatedAt,
+			}
+			delete(resp, "status")
+		}
 
 		// Cache the result for 30 seconds
 		articlesCacheLock.Lock()
-		articlesCache.Set(cacheKey, resp, 30*time.Second)
+		cacheSetJSON(articlesCache, cacheKey, resp, 30*time.Second)
 		articlesCacheLock.Unlock()
 
+		// Added after caching so the cached response stays without it
+		if debug {
+			cfg, err := llm.LoadCompositeScoreConfig()
+			if err != nil {
+				RespondError(c, WrapError(err, ErrLLMService, "Failed to load LLM configuration"))
+				return
+			}
+			resp["debug"] = llm.TraceArticleComposite(dbConn, id, scores, cfg)
+		}
+
 		// DEBUG: Log the response being sent, especially for article 1646
 		if id == 1646 {
 			log.Printf("[biasHandler DEBUG 1646] Sending response: %+v", resp)
@@ -1546,7 +2272,7 @@ func ensembleDetailsHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 		// Skip cache if _t query param exists (cache busting)
 		if _, skipCache := c.GetQuery("_t"); skipCache {
 			log.Printf("[ensembleDetailsHandler] Cache busting requested for article %d", id)
-			scores, err := db.FetchLLMScores(dbConn, int64(id))
+			scores, err := db.FetchLLMScoresContext(c.Request.Context(), dbConn, int64(id))
 			if err != nil {
 				RespondError(c, NewAppError(ErrInternal, "Failed to fetch ensemble data"))
 				LogError(c, err, "ensembleDetailsHandler: fetch scores")
@@ -1569,14 +2295,14 @@ func ensembleDetailsHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			articlesCacheLock.RUnlock()
 			c.JSON(http.StatusOK, gin.H{
 				"success": true,
-				"scores":  cachedRaw,
+				"scores":  json.RawMessage(cachedRaw),
 			})
 			LogPerformance("ensembleDetailsHandler (cache hit)", start)
 			return
 		}
 		articlesCacheLock.RUnlock()
 
-		scores, err := db.FetchLLMScores(dbConn, int64(id))
+		scores, err := db.FetchLLMScoresContext(c.Request.Context(), dbConn, int64(id))
 		if err != nil {
 			RespondError(c, NewAppError(ErrInternal, "Failed to fetch ensemble data"))
 			LogError(c, err, "ensembleDetailsHandler: fetch scores")
@@ -1606,7 +2332,7 @@ func ensembleDetailsHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 		}
 
 		articlesCacheLock.Lock()
-		articlesCache.Set(cacheKey, details, 30*time.Second)
+		cacheSetJSON(articlesCache, cacheKey, details, 30*time.Second)
 		articlesCacheLock.Unlock()
 
 		c.JSON(http.StatusOK, gin.H{
@@ -1702,6 +2428,7 @@ func processEnsembleScores(scores []db.LLMScore) []map[string]interface{} {
 // @Param request body FeedbackRequest true "Feedback information"
 // @Success 200 {object} StandardResponse "Feedback received"
 // @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 413 {object} ErrorResponse "Request body too large"
 // @Failure 500 {object} ErrorResponse "Server error"
 // @Router /api/feedback [post]
 // @ID submitFeedback
@@ -1722,24 +2449,8 @@ func feedbackHandler(dbConn *sqlx.DB, llmClient *llm.LLMClient) gin.HandlerFunc
 			return
 		}
 
-		var missingFields []string
-		if req.ArticleID == 0 {
-			missingFields = append(missingFields, "article_id")
-		}
-		if req.FeedbackText == "" {
-			missingFields = append(missingFields, "feedback_text")
-		}
-		if req.UserID == "" {
-			missingFields = append(missingFields, "user_id")
-		}
-		if len(missingFields) > 0 {
-			RespondError(c, NewAppError(ErrValidation, "Missing required fields: "+strings.Join(missingFields, ", ")))
-			return
-		}
-
-		validCategories := map[string]bool{"agree": true, "disagree": true, "unclear": true, "other": true, "": true}
-		if req.Category != "" && !validCategories[req.Category] {
-			RespondError(c, ErrInvalidCategory)
+		if appErr := validateFeedback(req.ArticleID, req.UserID, req.FeedbackText, req.Category); appErr != nil {
+			RespondError(c, appErr)
 			return
 		}
 
@@ -1761,8 +2472,7 @@ func feedbackHandler(dbConn *sqlx.DB, llmClient *llm.LLMClient) gin.HandlerFunc
 		}
 
 		// Update article confidence based on feedback
-		scores, err := db.FetchLLMScores(dbConn, req.ArticleID)
-		if err == nil {
+		if scores, err := db.FetchLLMScoresContext(c.Request.Context(), dbConn, req.ArticleID); err == nil {
 			// Get config from the LLMClient associated with the handler
 			config := llmClient.GetConfig()
 			if config == nil {
@@ -1770,24 +2480,9 @@ func feedbackHandler(dbConn *sqlx.DB, llmClient *llm.LLMClient) gin.HandlerFunc
 				RespondError(c, NewAppError(ErrInternal, "Internal processing error [config]"))
 				return
 			}
-
-			score, confidence, err := llm.ComputeCompositeScoreWithConfidence(scores, config)
-			if err != nil {
-				log.Printf("[API DEBUG] Error computing composite score for article %d: %v", req.ArticleID, err)
-			} else {
-				// Adjust confidence based on feedback category
-				if req.Category == "agree" {
-					confidence = math.Min(1.0, confidence+0.1) // Increase confidence on agreement
-				} else if req.Category == "disagree" {
-					confidence = math.Max(0.0, confidence-0.1) // Decrease confidence on disagreement
-				}
-
-				// Update article with new confidence
-				err = db.UpdateArticleScore(dbConn, req.ArticleID, score, confidence)
-				if err != nil {
-					// Log error but don't fail the request since feedback was saved
-					LogError(c, err, "feedbackHandler: update article confidence")
-				}
+			if err := adjustFeedbackConfidence(dbConn, config, req.ArticleID, scores); err != nil {
+				// Log error but don't fail the request since feedback was saved
+				LogError(c, err, "feedbackHandler: update article confidence")
 			}
 		}
 
@@ -1796,8 +2491,12 @@ func feedbackHandler(dbConn *sqlx.DB, llmClient *llm.LLMClient) gin.HandlerFunc
 	}
 }
 
+// legacyManualScoreReason is recorded for overrides pinned through the deprecated
+// POST /api/manual-score/:id, which takes no reason
+const legacyManualScoreReason = "set via deprecated POST /api/manual-score"
+
 // @Summary Manually set article score
-// @Description Updates an article's bias score manually
+// @Description Deprecated: pins an article's bias score like PUT /api/articles/{id}/manual-score, without a reason
 // @Tags Analysis
 // @Param id path int true "Article ID" minimum(1)
 // @Param request body ManualScoreRequest true "Score value between -1.0 and 1.0"
@@ -1805,6 +2504,7 @@ func feedbackHandler(dbConn *sqlx.DB, llmClient *llm.LLMClient) gin.HandlerFunc
 // @Failure 400 {object} ErrorResponse
 // @Router /api/manual-score/{id} [post]
 // @ID addManualScore
+// @Deprecated
 func manualScoreHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, ok := getValidArticleID(c)
@@ -1847,7 +2547,7 @@ func manualScoreHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			return
 		}
 
-		_, err = db.FetchArticleByID(dbConn, articleID)
+		_, err = db.FetchArticleByIDContext(c.Request.Context(), dbConn, articleID)
 		if err != nil {
 			if errors.Is(err, db.ErrArticleNotFound) {
 				RespondError(c, NewAppError(ErrNotFound, "Article not found"))
@@ -1859,36 +2559,152 @@ func manualScoreHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			return
 		}
 
-		// Update score in DB
-		err = db.UpdateArticleScore(dbConn, articleID, scoreVal, 1.0) // Set confidence to 1.0 for manual scores
-		if err != nil {
-			errMsg := err.Error()
-			if errMsg != "" && (strings.Contains(errMsg, "constraint failed") ||
-				strings.Contains(errMsg, "UNIQUE constraint failed") ||
-				strings.Contains(errMsg, "CHECK constraint failed") ||
-				strings.Contains(errMsg, "NOT NULL constraint failed") ||
-				strings.Contains(errMsg, "FOREIGN KEY constraint failed") ||
-				strings.Contains(errMsg, "invalid") ||
-				strings.Contains(errMsg, "out of range") ||
-				strings.Contains(errMsg, "data type mismatch")) {
-				log.Printf("[manualScoreHandler] Constraint/validation error updating article score: %v", err)
-				RespondError(c, NewAppError(ErrValidation, "Failed to update score due to invalid data or constraint violation"))
-				return
-			}
-			log.Printf("[manualScoreHandler] Unexpected DB error updating article score: %v", err)
-			RespondError(c, NewAppError(ErrInternal, "Failed to update article score"))
+		// The legacy endpoint pins the score like PUT /api/articles/:id/manual-score,
+		// so later reanalysis cannot silently replace it
+		c.Header("Deprecation", "true")
+		c.Header("Link", fmt.Sprintf("</api/articles/%d/manual-score>; rel=\"successor-version\"", articleID))
+		setAuditDetails(c, "score", scoreVal)
+		if err = db.SetManualScore(dbConn, articleID, scoreVal, legacyManualScoreReason); err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to update article score"))
 			LogError(c, err, "manualScoreHandler: failed to update article score")
 			return
 		}
+		invalidateArticleScoreCache(articleID)
 		safeLogf("[manualScoreHandler] Article score updated successfully: articleID=%d, score=%f", articleID, scoreVal)
 		RespondSuccess(c, map[string]interface{}{
-			"status":     "score updated",
+			"status":       "score updated",
+			"article_id":   articleID,
+			"score":        scoreVal,
+			"score_source": db.ScoreSourceManual,
+			"deprecated":   "use PUT /api/articles/{id}/manual-score with a reason",
+		})
+	}
+}
+
+// invalidateArticleScoreCache drops cached article and bias responses for an article
+func invalidateArticleScoreCache(articleID int64) {
+	id := strconv.FormatInt(articleID, 10)
+	articlesCacheLock.Lock()
+	defer articlesCacheLock.Unlock()
+	articlesCache.Invalidate("article:" + id)
+	articlesCache.InvalidatePrefix("bias:" + id + ":")
+}
+
+// setManualScoreOverrideHandler handles PUT /api/articles/:id/manual-score
+func setManualScoreOverrideHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		articleID, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+
+		var req ManualScoreOverrideRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondError(c, NewAppError(ErrValidation, "Invalid JSON body"))
+			return
+		}
+		if req.Score == nil {
+			RespondError(c, NewAppError(ErrValidation, "'score' is required"))
+			return
+		}
+		if *req.Score < -1.0 || *req.Score > 1.0 {
+			RespondError(c, NewAppError(ErrValidation, "Score must be between -1.0 and 1.0"))
+			return
+		}
+		reason := strings.TrimSpace(req.Reason)
+		if reason == "" {
+			RespondError(c, NewAppError(ErrValidation, "'reason' is required"))
+			return
+		}
+
+		if _, err := db.FetchArticleByIDContext(c.Request.Context(), dbConn, articleID); err != nil {
+			if errors.Is(err, db.ErrArticleNotFound) {
+				RespondError(c, ErrArticleNotFound)
+				return
+			}
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch article"))
+			return
+		}
+
+		setAuditDetails(c, "score", *req.Score)
+		setAuditDetails(c, "reason", reason)
+		if err := db.SetManualScore(dbConn, articleID, *req.Score, reason); err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to set manual score"))
+			LogError(c, err, "setManualScoreOverrideHandler: failed to set manual score")
+			return
+		}
+		invalidateArticleScoreCache(articleID)
+
+		safeLogf("[setManualScoreOverrideHandler] Manual score pinned: articleID=%d, score=%f, reason=%s",
+			articleID, *req.Score, sanitizeForLog(reason))
+		RespondSuccess(c, map[string]interface{}{
+			"article_id":   articleID,
+			"score":        *req.Score,
+			"reason":       reason,
+			"score_source": db.ScoreSourceManual,
+		})
+	}
+}
+
+// clearManualScoreOverrideHandler handles DELETE /api/articles/:id/manual-score
+func clearManualScoreOverrideHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		articleID, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+
+		cleared, err := db.ClearManualScore(dbConn, articleID)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to clear manual score"))
+			LogError(c, err, "clearManualScoreOverrideHandler: failed to clear manual score")
+			return
+		}
+		if !cleared {
+			RespondError(c, NewAppError(ErrNotFound, "No manual score override for this article"))
+			return
+		}
+		invalidateArticleScoreCache(articleID)
+
+		RespondSuccess(c, map[string]interface{}{
 			"article_id": articleID,
-			"score":      scoreVal,
+			"status":     "manual score cleared",
 		})
 	}
 }
 
+// promptPreviewHandler handles GET /api/articles/:id/prompt-preview
+func promptPreviewHandler(llmClient *llm.LLMClient, dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		articleID, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+
+		article, err := db.FetchArticleByIDContext(c.Request.Context(), dbConn, articleID)
+		if err != nil {
+			if errors.Is(err, db.ErrArticleNotFound) {
+				RespondError(c, ErrArticleNotFound)
+				return
+			}
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch article"))
+			return
+		}
+
+		preview, err := llmClient.PreviewPrompt(article, c.Query("model"), c.Query("variant"))
+		if err != nil {
+			if errors.Is(err, llm.ErrPromptModelNotConfigured) || errors.Is(err, llm.ErrUnknownPromptVariant) {
+				RespondError(c, NewAppError(ErrValidation, err.Error()))
+				return
+			}
+			RespondError(c, WrapError(err, ErrLLMService, "Failed to render prompt"))
+			return
+		}
+
+		RespondSuccess(c, preview)
+	}
+}
+
 // sanitizeForLog sanitizes user input to prevent log injection attacks
 // It removes or escapes potentially dangerous characters that could be used for log injection
 func sanitizeForLog(input string) string {
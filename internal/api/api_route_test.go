@@ -60,7 +60,7 @@ func TestRegisterRoutes(t *testing.T) {
 	mockScoreManager := new(llm.ScoreManager)
 
 	// Register routes
-	RegisterRoutes(router, dbConn, mockRSS, mockLLM, mockScoreManager, nil, nil)
+	RegisterRoutes(router, dbConn, mockRSS, mockLLM, mockScoreManager, nil, nil, nil, nil)
 
 	// Test that key routes exist
 	routes := []struct {
@@ -96,6 +96,25 @@ func TestRegisterRoutes(t *testing.T) {
 	}
 }
 
+// TestRefreshRoutesRequireAdminKey checks that both feed refresh routes reject
+// requests without the admin API key
+func TestRefreshRoutesRequireAdminKey(t *testing.T) {
+	ginTestModeOnceRoute.Do(func() {
+		gin.SetMode(gin.TestMode)
+	})
+	t.Setenv(AdminAPIKeyEnv, "secret")
+	router := gin.New()
+	RegisterRoutes(router, &sqlx.DB{}, new(rss.Collector), new(llm.LLMClient), new(llm.ScoreManager), nil, nil, nil, nil)
+
+	for _, path := range []string{"/api/refresh", "/api/admin/refresh-feeds"} {
+		t.Run(path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		})
+	}
+}
+
 // TestSafeHandler tests that the SafeHandler correctly recovers from panics
 func TestSafeHandler(t *testing.T) {
 	ginTestModeOnceRoute.Do(func() {
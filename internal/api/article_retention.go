@@ -0,0 +1,163 @@
+package api
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// defaultRetentionInterval is how often the retention cleanup runs
+	defaultRetentionInterval = 24 * time.Hour
+	// defaultRetentionBatchSize is how many articles are deleted per transaction
+	defaultRetentionBatchSize = 500
+)
+
+// ArticleRetention periodically deletes the articles outside a retention policy along
+// with their scores, score history and feedback. In dry-run mode it only logs what it
+// would delete.
+type ArticleRetention struct {
+	dbConn    *sqlx.DB
+	policy    db.RetentionPolicy
+	interval  time.Duration
+	batchSize int
+	dryRun    bool
+
+	mu       sync.Mutex
+	stopChan chan struct{} // nil while stopped
+}
+
+// NewArticleRetention creates a stopped cleanup job. A non-positive interval or batch
+// size uses the default.
+func NewArticleRetention(dbConn *sqlx.DB, policy db.RetentionPolicy, interval time.Duration, batchSize int, dryRun bool) *ArticleRetention {
+	if interval <= 0 {
+		interval = defaultRetentionInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultRetentionBatchSize
+	}
+	return &ArticleRetention{
+		dbConn:    dbConn,
+		policy:    policy,
+		interval:  interval,
+		batchSize: batchSize,
+		dryRun:    dryRun,
+	}
+}
+
+// NewArticleRetentionFromEnv creates the cleanup job for RETENTION_MAX_AGE and
+// RETENTION_MAX_ARTICLES, tuned by RETENTION_INTERVAL, RETENTION_BATCH_SIZE and
+// RETENTION_DRY_RUN, and starts it when either limit is set
+func NewArticleRetentionFromEnv(dbConn *sqlx.DB) *ArticleRetention {
+	policy := db.RetentionPolicy{
+		MaxAge:   retentionMaxAgeFromEnv(),
+		MaxCount: positiveIntFromEnv("RETENTION_MAX_ARTICLES", 0),
+	}
+	interval := defaultRetentionInterval
+	if v := os.Getenv("RETENTION_INTERVAL"); v != "" {
+		if parsed, err := time.ParseDuration(v); err == nil && parsed > 0 {
+			interval = parsed
+		} else {
+			log.Printf("[WARN] Invalid RETENTION_INTERVAL %q, using default %v", v, defaultRetentionInterval)
+		}
+	}
+	dryRun, _ := strconv.ParseBool(os.Getenv("RETENTION_DRY_RUN"))
+
+	r := NewArticleRetention(dbConn, policy, interval,
+		positiveIntFromEnv("RETENTION_BATCH_SIZE", defaultRetentionBatchSize), dryRun)
+	if policy.Enabled() {
+		r.Start()
+		log.Printf("Article retention started (max age %v, max articles %d, every %v, dry run %t)",
+			policy.MaxAge, policy.MaxCount, interval, dryRun)
+	}
+	return r
+}
+
+// retentionMaxAgeFromEnv reads RETENTION_MAX_AGE as a Go duration or a number of days
+// such as "90d"; unset or invalid values disable the age limit
+func retentionMaxAgeFromEnv() time.Duration {
+	v := os.Getenv("RETENTION_MAX_AGE")
+	if v == "" {
+		return 0
+	}
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour
+		}
+	} else if age, err := time.ParseDuration(v); err == nil && age > 0 {
+		return age
+	}
+	log.Printf("[WARN] Invalid RETENTION_MAX_AGE %q, not limiting article age", v)
+	return 0
+}
+
+// positiveIntFromEnv reads a positive integer, falling back to def when unset or invalid
+func positiveIntFromEnv(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		log.Printf("[WARN] Invalid %s %q, using default %d", name, v, def)
+		return def
+	}
+	return n
+}
+
+// Start runs the cleanup straight away and then every interval until Stop is called.
+// Starting a running job does nothing.
+func (r *ArticleRetention) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopChan != nil {
+		return
+	}
+	stop := make(chan struct{})
+	r.stopChan = stop
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			r.RunOnce()
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the periodic runs; a batch already being deleted finishes first
+func (r *ArticleRetention) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopChan != nil {
+		close(r.stopChan)
+		r.stopChan = nil
+	}
+}
+
+// RunOnce applies the policy once and logs what was removed, or would be in dry-run mode
+func (r *ArticleRetention) RunOnce() (db.ArticleCleanup, error) {
+	result, err := db.CleanupArticles(r.dbConn, r.policy, time.Now(), r.batchSize, r.dryRun)
+	prefix := "Deleted"
+	if r.dryRun {
+		prefix = "Dry run: would delete"
+	}
+	if result != (db.ArticleCleanup{}) {
+		log.Printf("[Retention] %s %d articles, %d model scores, %d score history entries, %d feedback items and %d tag assignments",
+			prefix, result.Articles, result.LLMScores, result.ScoreHistory, result.Feedback, result.ArticleTags)
+	}
+	if err != nil {
+		log.Printf("[Retention] Cleanup failed: %v", err)
+	}
+	return result, err
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionMaxAgeFromEnv(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"":      0,
+		"90d":   90 * 24 * time.Hour,
+		"36h":   36 * time.Hour,
+		"-1h":   0,
+		"soon":  0,
+		"0d":    0,
+		"1.5d":  0,
+		"720h0": 0,
+	} {
+		t.Setenv("RETENTION_MAX_AGE", value)
+		assert.Equal(t, want, retentionMaxAgeFromEnv(), "RETENTION_MAX_AGE=%q", value)
+	}
+}
+
+func TestArticleRetentionRunOnce(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "retention.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	old := time.Now().AddDate(0, 0, -100).UTC()
+	for _, createdAt := range []time.Time{old, time.Now().UTC()} {
+		_, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, created_at)
+			VALUES ('bbc', ?, ?, 'Title', 'Body', ?)`, createdAt, "https://example.com/"+createdAt.Format(time.RFC3339Nano), createdAt)
+		require.NoError(t, err)
+	}
+	policy := db.RetentionPolicy{MaxAge: 90 * 24 * time.Hour}
+
+	result, err := NewArticleRetention(dbConn, policy, 0, 0, true).RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Articles)
+
+	result, err = NewArticleRetention(dbConn, policy, 0, 0, false).RunOnce()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Articles)
+	var remaining int
+	require.NoError(t, dbConn.Get(&remaining, `SELECT COUNT(*) FROM articles`))
+	assert.Equal(t, 1, remaining)
+}
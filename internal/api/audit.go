@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// Context keys used to pass audit information between middleware and handlers
+const (
+	auditActorKey   = "audit_actor"
+	auditDetailsKey = "audit_details"
+)
+
+// Audit actors recorded depending on how the admin request was authorized
+const (
+	AuditActorAPIKey    = "api-key"
+	AuditActorAnonymous = "anonymous"
+)
+
+// Audit log pagination bounds
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// AuditMiddleware records the wrapped admin action in the audit log once the
+// handler has run. Only successful requests are recorded; rejected requests
+// change nothing and are already visible in the access logs.
+func AuditMiddleware(dbConn *sqlx.DB, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusBadRequest || dbConn == nil {
+			return
+		}
+
+		details := map[string]interface{}{
+			"method":    c.Request.Method,
+			"path":      c.Request.URL.Path,
+			"status":    status,
+			"client_ip": c.ClientIP(),
+		}
+		if q := c.Request.URL.RawQuery; q != "" {
+			details["query"] = q
+		}
+		if extra, ok := c.Get(auditDetailsKey); ok {
+			for k, v := range extra.(map[string]interface{}) {
+				details[k] = v
+			}
+		}
+		detailsJSON, err := json.Marshal(details)
+		if err != nil {
+			log.Printf("[WARN] Failed to encode audit details for %s: %v", action, err)
+			detailsJSON = []byte("{}")
+		}
+
+		entry := &db.AuditEntry{
+			Actor:   auditActor(c),
+			Action:  action,
+			Target:  c.Param("id"),
+			Details: string(detailsJSON),
+		}
+		if _, err := db.InsertAuditEntry(dbConn, entry); err != nil {
+			log.Printf("[ERROR] Failed to record audit entry for %s: %v", action, err)
+		}
+	}
+}
+
+// setAuditDetails attaches an extra key/value to the audit entry for this request
+func setAuditDetails(c *gin.Context, key string, value interface{}) {
+	details, ok := c.Get(auditDetailsKey)
+	if !ok {
+		details = map[string]interface{}{}
+		c.Set(auditDetailsKey, details)
+	}
+	details.(map[string]interface{})[key] = value
+}
+
+// auditActor returns the actor set by the admin auth middleware
+func auditActor(c *gin.Context) string {
+	if actor := c.GetString(auditActorKey); actor != "" {
+		return actor
+	}
+	return AuditActorAnonymous
+}
+
+// @Summary Get audit log
+// @Description Returns recorded administrative actions, newest first
+// @Tags Admin
+// @Produce json
+// @Param limit query int false "Maximum entries to return (default 50, max 500)"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {object} StandardResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/admin/audit [get]
+// @ID getAuditLog
+func auditLogHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultAuditLimit)))
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			RespondError(c, NewAppError(ErrValidation, "limit must be between 1 and 500"))
+			return
+		}
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			RespondError(c, NewAppError(ErrValidation, "offset must be a non-negative integer"))
+			return
+		}
+
+		entries, total, err := db.FetchAuditEntries(dbConn, limit, offset)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch audit log"))
+			return
+		}
+
+		RespondSuccess(c, map[string]interface{}{
+			"entries": entries,
+			"total":   total,
+			"limit":   limit,
+			"offset":  offset,
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "audit.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	router := gin.New()
+	adminAuth := AdminAuthMiddleware("secret")
+	router.POST("/api/things/:id", adminAuth, AuditMiddleware(dbConn, "thing.poke"), func(c *gin.Context) {
+		setAuditDetails(c, "note", "poked")
+		RespondSuccess(c, "ok")
+	})
+	router.DELETE("/api/things/:id", adminAuth, AuditMiddleware(dbConn, "thing.delete"), func(c *gin.Context) {
+		RespondError(c, NewAppError(ErrNotFound, "no such thing"))
+	})
+	router.GET("/api/admin/audit", adminAuth, auditLogHandler(dbConn))
+
+	do := func(method, path string, withKey bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		if withKey {
+			req.Header.Set(AdminAPIKeyHeader, "secret")
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/api/things/42", true).Code)
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodPost, "/api/things/42", false).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/api/things/42", true).Code)
+
+	entries, total, err := db.FetchAuditEntries(dbConn, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, total, "only the successful, authorized request is recorded")
+	assert.Equal(t, AuditActorAPIKey, entries[0].Actor)
+	assert.Equal(t, "thing.poke", entries[0].Action)
+	assert.Equal(t, "42", entries[0].Target)
+
+	var details map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(entries[0].Details), &details))
+	assert.Equal(t, "poked", details["note"])
+	assert.Equal(t, "/api/things/42", details["path"])
+
+	t.Run("ListEndpoint", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/admin/audit?limit=5", true)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Data struct {
+				Entries []db.AuditEntry `json:"entries"`
+				Total   int             `json:"total"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.Data.Total)
+		assert.Len(t, resp.Data.Entries, 1)
+
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/api/admin/audit?limit=0", true).Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/api/admin/audit?offset=-1", true).Code)
+		assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/api/admin/audit", false).Code)
+	})
+}
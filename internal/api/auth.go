@@ -0,0 +1,71 @@
+package api
+
+import (
+	"crypto/subtle"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAPIKeyHeader is the header checked for the admin API key.
+// A bearer token in the Authorization header is accepted as well.
+const AdminAPIKeyHeader = "X-API-Key"
+
+// AdminAPIKeyEnv names the environment variable holding the admin API key.
+const AdminAPIKeyEnv = "ADMIN_API_KEY"
+
+// AdminAuthMiddleware rejects requests that do not present the configured API key
+// with 401. An empty key disables the check so that local setups keep working.
+func AdminAuthMiddleware(apiKey string) gin.HandlerFunc {
+	if apiKey == "" {
+		log.Printf("[WARN] %s not set, admin endpoints are unauthenticated", AdminAPIKeyEnv)
+		return func(c *gin.Context) {
+			c.Set(auditActorKey, AuditActorAnonymous)
+			c.Next()
+		}
+	}
+
+	expected := []byte(apiKey)
+	return func(c *gin.Context) {
+		provided := extractAPIKey(c)
+		if provided == "" || subtle.ConstantTimeCompare([]byte(provided), expected) != 1 {
+			RespondError(c, ErrUnauthorized)
+			c.Abort()
+			return
+		}
+		c.Set(auditActorKey, AuditActorAPIKey)
+		c.Next()
+	}
+}
+
+// adminAuthWhen applies auth only to requests for which required returns true, for
+// public endpoints with admin-only options
+func adminAuthWhen(auth gin.HandlerFunc, required func(*gin.Context) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if required(c) {
+			auth(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// extractAPIKey returns the key from X-API-Key or an "Authorization: Bearer" header
+func extractAPIKey(c *gin.Context) string {
+	if key := c.GetHeader(AdminAPIKeyHeader); key != "" {
+		return key
+	}
+	auth := c.GetHeader("Authorization")
+	const prefix = "bearer "
+	if len(auth) > len(prefix) && strings.EqualFold(auth[:len(prefix)], prefix) {
+		return strings.TrimSpace(auth[len(prefix):])
+	}
+	return ""
+}
+
+// adminAPIKeyFromEnv reads the admin API key from the environment
+func adminAPIKeyFromEnv() string {
+	return strings.TrimSpace(os.Getenv(AdminAPIKeyEnv))
+}
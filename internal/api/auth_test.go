@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(key string) *gin.Engine {
+		r := gin.New()
+		r.POST("/api/admin/op", AdminAuthMiddleware(key), func(c *gin.Context) {
+			RespondSuccess(c, "ok")
+		})
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{"missing key", "secret", nil, http.StatusUnauthorized},
+		{"wrong header key", "secret", map[string]string{AdminAPIKeyHeader: "nope"}, http.StatusUnauthorized},
+		{"valid header key", "secret", map[string]string{AdminAPIKeyHeader: "secret"}, http.StatusOK},
+		{"valid bearer token", "secret", map[string]string{"Authorization": "Bearer secret"}, http.StatusOK},
+		{"wrong bearer token", "secret", map[string]string{"Authorization": "Bearer secre"}, http.StatusUnauthorized},
+		{"basic auth rejected", "secret", map[string]string{"Authorization": "Basic secret"}, http.StatusUnauthorized},
+		{"auth disabled", "", nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/op", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			newRouter(tt.key).ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Contains(t, w.Body.String(), ErrAuth)
+			}
+		})
+	}
+}
+
+func TestAdminAuthWhenDebugRequested(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/api/articles/:id/bias", adminAuthWhen(AdminAuthMiddleware("secret"), wantsAggregationDebug),
+		func(c *gin.Context) {
+			RespondSuccess(c, "ok")
+		})
+
+	tests := []struct {
+		name       string
+		query      string
+		key        string
+		wantStatus int
+	}{
+		{"no debug is public", "", "", http.StatusOK},
+		{"debug off is public", "?include_debug=false", "", http.StatusOK},
+		{"debug without key", "?include_debug=true", "", http.StatusUnauthorized},
+		{"debug with wrong key", "?include_debug=1", "nope", http.StatusUnauthorized},
+		{"debug with key", "?include_debug=true", "secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/articles/1/bias"+tt.query, nil)
+			if tt.key != "" {
+				req.Header.Set(AdminAPIKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
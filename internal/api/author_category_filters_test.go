@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticlesAuthorAndCategoryFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "authors.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	seed := []struct {
+		title      string
+		authors    db.StringList
+		categories db.StringList
+	}{
+		{"tagged", db.StringList{"Jane Doe"}, db.StringList{"Politics", "Economy"}},
+		{"other author", db.StringList{"John Roe"}, db.StringList{"Politics"}},
+		{"untagged", nil, nil},
+	}
+	for i, a := range seed {
+		_, err := db.InsertArticle(dbConn, &db.Article{
+			Source: "bbc", URL: fmt.Sprintf("https://example.com/a/%d", i), Title: a.title, Content: "Body",
+			Authors: a.authors, Categories: a.categories,
+		})
+		require.NoError(t, err)
+	}
+
+	router := gin.New()
+	router.GET("/api/articles", getArticlesHandler(dbConn))
+	list := func(query string) []ArticleResponse {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles?"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data []ArticleResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	byAuthor := list("author=" + url.QueryEscape("jane doe"))
+	require.Len(t, byAuthor, 1)
+	assert.Equal(t, "tagged", byAuthor[0].Title)
+	assert.Equal(t, []string{"Jane Doe"}, byAuthor[0].Authors)
+	assert.Equal(t, []string{"Politics", "Economy"}, byAuthor[0].Categories)
+
+	assert.Len(t, list("category=politics"), 2)
+	assert.Len(t, list("category=Economy&author="+url.QueryEscape("John Roe")), 0)
+	assert.Len(t, list("author=nobody"), 0)
+
+	for _, a := range list("") {
+		if a.Title == "untagged" {
+			assert.Nil(t, a.Authors)
+			assert.Nil(t, a.Categories)
+		}
+	}
+}
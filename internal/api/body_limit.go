@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultMaxBodyBytes is the largest request body the ingestion, scoring and batch
+// endpoints accept. It is generous on purpose; MAX_REQUEST_BODY_BYTES can only lower it.
+const defaultMaxBodyBytes int64 = 10 << 20
+
+// BodyLimit rejects request bodies larger than a limit with a 413, so a huge upload is
+// never held in memory or forwarded to the LLM
+type BodyLimit struct {
+	maxBytes int64
+}
+
+// NewBodyLimit accepts bodies of up to maxBytes bytes
+func NewBodyLimit(maxBytes int64) *BodyLimit {
+	return &BodyLimit{maxBytes: maxBytes}
+}
+
+// newBodyLimitFromEnv builds the limit from MAX_REQUEST_BODY_BYTES. Values above the
+// default are capped at it.
+func newBodyLimitFromEnv() *BodyLimit {
+	maxBytes := defaultMaxBodyBytes
+	if v := os.Getenv("MAX_REQUEST_BODY_BYTES"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		switch {
+		case err != nil || parsed < 1:
+			log.Printf("[WARN] Invalid MAX_REQUEST_BODY_BYTES %q, using default %d", v, defaultMaxBodyBytes)
+		case parsed > defaultMaxBodyBytes:
+			log.Printf("[WARN] MAX_REQUEST_BODY_BYTES %d is above the maximum, using %d", parsed, defaultMaxBodyBytes)
+		default:
+			maxBytes = parsed
+		}
+	}
+	return NewBodyLimit(maxBytes)
+}
+
+// Middleware reads the body through http.MaxBytesReader before the handler runs, so an
+// oversized body is answered with 413 whether or not it declares its length. The body
+// is handed on to the handler as read. A nil limit is a no-op.
+func (bl *BodyLimit) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if bl == nil || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > bl.maxBytes {
+			bl.reject(c)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, bl.maxBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				bl.reject(c)
+				return
+			}
+			RespondError(c, NewAppError(ErrValidation, "Failed to read request body"))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func (bl *BodyLimit) reject(c *gin.Context) {
+	log.Printf("[WARN] %s %s rejected: body larger than %d bytes", c.Request.Method, c.Request.URL.Path, bl.maxBytes)
+	RespondError(c, NewAppError(ErrTooLarge, fmt.Sprintf("Request body exceeds the limit of %d bytes", bl.maxBytes)))
+	c.Abort()
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/rss"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBodyLimitRouter(limit *BodyLimit) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/ingest", limit.Middleware(), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return router
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	router := newBodyLimitRouter(NewBodyLimit(16))
+
+	tests := []struct {
+		name     string
+		body     string
+		chunked  bool
+		wantCode int
+	}{
+		{"within limit", `{"title":"ok"}`, false, http.StatusOK},
+		{"declared length over limit", strings.Repeat("x", 17), false, http.StatusRequestEntityTooLarge},
+		{"undeclared length over limit", strings.Repeat("x", 64), true, http.StatusRequestEntityTooLarge},
+		{"undeclared length within limit", "small", true, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/ingest", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, tt.body, w.Body.String(), "handler sees the full body")
+			} else {
+				assert.Contains(t, w.Body.String(), `"code":"payload_too_large"`)
+			}
+		})
+	}
+}
+
+func TestNewBodyLimitFromEnv(t *testing.T) {
+	t.Setenv("MAX_REQUEST_BODY_BYTES", "1024")
+	assert.Equal(t, int64(1024), newBodyLimitFromEnv().maxBytes)
+
+	t.Setenv("MAX_REQUEST_BODY_BYTES", "999999999999")
+	assert.Equal(t, defaultMaxBodyBytes, newBodyLimitFromEnv().maxBytes, "can only be lowered")
+
+	t.Setenv("MAX_REQUEST_BODY_BYTES", "lots")
+	assert.Equal(t, defaultMaxBodyBytes, newBodyLimitFromEnv().maxBytes)
+}
+
+func TestFeedbackBodyLimit(t *testing.T) {
+	t.Setenv("MAX_REQUEST_BODY_BYTES", "64")
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router, &sqlx.DB{}, new(rss.Collector), new(llm.LLMClient), new(llm.ScoreManager), nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/feedback", strings.NewReader(`{"feedback_text":"`+strings.Repeat("x", 128)+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), ErrTooLarge)
+}
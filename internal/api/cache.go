@@ -1,18 +1,174 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
 	"sync"
 	"time"
 )
 
-// Cache implementation
+// Cache backends selectable with CACHE_BACKEND
+const (
+	CacheBackendMemory = "memory"
+	CacheBackendRedis  = "redis"
+)
+
+// Cache stores API responses shared by the handlers. Values are opaque bytes so every
+// backend stores exactly the same encoding; handlers serialize with cacheSetJSON and
+// read back with cacheGetJSON or as raw JSON.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	// Invalidate removes a single key
+	Invalidate(key string)
+	// InvalidatePrefix removes every key starting with prefix
+	InvalidatePrefix(prefix string)
+	Stats() CacheStats
+}
+
+// CacheStats reports cache usage since the cache was created
+type CacheStats struct {
+	Backend   string  `json:"backend"`
+	Entries   int64   `json:"entries"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	HitRate   float64 `json:"hit_rate"`
+	// Prefixes breaks the counts down by key prefix, the part of the key before the first ':'
+	Prefixes map[string]CacheCounts `json:"prefixes"`
+}
+
+// CacheCounts are the hit, miss and eviction counts for one key prefix
+type CacheCounts struct {
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	HitRate   float64 `json:"hit_rate"`
+}
+
+// cacheCounters tallies cache outcomes overall and per key prefix. It is safe for
+// concurrent use.
+type cacheCounters struct {
+	mu       sync.Mutex
+	total    CacheCounts
+	prefixes map[string]*CacheCounts
+}
+
+func (cc *cacheCounters) hit(key string) {
+	cc.record(key, func(counts *CacheCounts) { counts.Hits++ })
+}
+
+func (cc *cacheCounters) miss(key string) {
+	cc.record(key, func(counts *CacheCounts) { counts.Misses++ })
+}
+
+func (cc *cacheCounters) evict(key string) {
+	cc.record(key, func(counts *CacheCounts) { counts.Evictions++ })
+}
+
+func (cc *cacheCounters) record(key string, inc func(*CacheCounts)) {
+	prefix := cacheKeyPrefix(key)
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.prefixes == nil {
+		cc.prefixes = make(map[string]*CacheCounts)
+	}
+	counts, ok := cc.prefixes[prefix]
+	if !ok {
+		counts = &CacheCounts{}
+		cc.prefixes[prefix] = counts
+	}
+	inc(counts)
+	inc(&cc.total)
+}
+
+// fill copies the counters into stats
+func (cc *cacheCounters) fill(stats *CacheStats) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	stats.Hits = cc.total.Hits
+	stats.Misses = cc.total.Misses
+	stats.Evictions = cc.total.Evictions
+	stats.HitRate = hitRate(cc.total.Hits, cc.total.Misses)
+	stats.Prefixes = make(map[string]CacheCounts, len(cc.prefixes))
+	for prefix, counts := range cc.prefixes {
+		snapshot := *counts
+		snapshot.HitRate = hitRate(counts.Hits, counts.Misses)
+		stats.Prefixes[prefix] = snapshot
+	}
+}
+
+// cacheKeyPrefix returns the part of key before the first ':', e.g. "article" for "article:42"
+func cacheKeyPrefix(key string) string {
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// hitRate is the fraction of lookups that were hits, 0 when there were none
+func hitRate(hits, misses uint64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// NewCacheFromEnv returns the cache selected by CACHE_BACKEND: the in-memory cache by
+// default, or Redis at REDIS_URL when set to "redis"
+func NewCacheFromEnv() (Cache, error) {
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv("CACHE_BACKEND")))
+	switch backend {
+	case "", CacheBackendMemory:
+		return NewSimpleCache(), nil
+	case CacheBackendRedis:
+		redisURL := os.Getenv("REDIS_URL")
+		if redisURL == "" {
+			return nil, fmt.Errorf("CACHE_BACKEND is %q but REDIS_URL is not set", backend)
+		}
+		return NewRedisCache(redisURL)
+	default:
+		log.Printf("[WARN] Invalid CACHE_BACKEND %q, using default %s", backend, CacheBackendMemory)
+		return NewSimpleCache(), nil
+	}
+}
+
+// cacheSetJSON stores the JSON encoding of value under key
+func cacheSetJSON(cache Cache, key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("[WARN] Not caching %s: %v", key, err)
+		return
+	}
+	cache.Set(key, data, ttl)
+}
+
+// cacheGetJSON decodes the value cached under key into dst, reporting whether it was found
+func cacheGetJSON(cache Cache, key string, dst interface{}) bool {
+	data, found := cache.Get(key)
+	if !found {
+		return false
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		log.Printf("[WARN] Discarding undecodable cache entry %s: %v", key, err)
+		cache.Invalidate(key)
+		return false
+	}
+	return true
+}
+
+// SimpleCache is the in-memory Cache implementation
 type SimpleCache struct {
-	cache map[string]cacheEntry
-	mu    sync.RWMutex
+	cache    map[string]cacheEntry
+	mu       sync.RWMutex
+	counters cacheCounters
 }
 
 type cacheEntry struct {
-	value      interface{}
+	value      []byte
 	expiration time.Time
 }
 
@@ -22,24 +178,36 @@ func NewSimpleCache() *SimpleCache {
 	}
 }
 
-func (c *SimpleCache) Get(key string) (interface{}, bool) {
+func (c *SimpleCache) Get(key string) ([]byte, bool) {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
-
 	entry, exists := c.cache[key]
+	c.mu.RUnlock()
+
 	if !exists {
+		c.counters.miss(key)
 		return nil, false
 	}
 
 	if time.Now().After(entry.expiration) {
-		delete(c.cache, key)
+		c.mu.Lock()
+		current, ok := c.cache[key]
+		expired := ok && time.Now().After(current.expiration)
+		if expired {
+			delete(c.cache, key)
+		}
+		c.mu.Unlock()
+		if expired {
+			c.counters.evict(key)
+		}
+		c.counters.miss(key)
 		return nil, false
 	}
 
+	c.counters.hit(key)
 	return entry.value, true
 }
 
-func (c *SimpleCache) Set(key string, value interface{}, ttl time.Duration) {
+func (c *SimpleCache) Set(key string, value []byte, ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -49,8 +217,38 @@ func (c *SimpleCache) Set(key string, value interface{}, ttl time.Duration) {
 	}
 }
 
-func (c *SimpleCache) Delete(key string) {
+// Invalidate removes a single entry
+func (c *SimpleCache) Invalidate(key string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	delete(c.cache, key)
 }
+
+// InvalidatePrefix removes every entry whose key starts with prefix
+func (c *SimpleCache) InvalidatePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.cache {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.cache, key)
+		}
+	}
+}
+
+// Stats reports the number of unexpired entries and the hit, miss and eviction
+// counts. Evictions are expired entries dropped when they were next looked up.
+func (c *SimpleCache) Stats() CacheStats {
+	c.mu.RLock()
+	now := time.Now()
+	var entries int64
+	for _, entry := range c.cache {
+		if now.Before(entry.expiration) {
+			entries++
+		}
+	}
+	c.mu.RUnlock()
+
+	stats := CacheStats{Backend: CacheBackendMemory, Entries: entries}
+	c.counters.fill(&stats)
+	return stats
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// redisCacheKeyPrefix namespaces cache keys so the Redis database can be shared
+	redisCacheKeyPrefix = "newsbalancer:cache:"
+	// redisCacheTimeout bounds each Redis call so a slow Redis degrades to cache misses
+	redisCacheTimeout = 500 * time.Millisecond
+	// redisScanCount is the SCAN batch size used for prefix invalidation and stats
+	redisScanCount = 500
+)
+
+// RedisCache is a Cache backed by Redis, shared by every server instance using the
+// same Redis database. Redis errors are logged and treated as cache misses.
+type RedisCache struct {
+	client   *redis.Client
+	counters cacheCounters
+}
+
+// NewRedisCache connects to the Redis server at redisURL (redis://[:password@]host:port/db)
+func NewRedisCache(redisURL string) (*RedisCache, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis at %s: %w", opts.Addr, err)
+	}
+	return &RedisCache{client: client}, nil
+}
+
+// Close closes the Redis connection pool
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
+
+func (c *RedisCache) Get(key string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisCacheTimeout)
+	defer cancel()
+
+	value, err := c.client.Get(ctx, redisCacheKeyPrefix+key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("[WARN] Redis cache get %s: %v", key, err)
+		}
+		c.counters.miss(key)
+		return nil, false
+	}
+	c.counters.hit(key)
+	return value, true
+}
+
+func (c *RedisCache) Set(key string, value []byte, ttl time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisCacheTimeout)
+	defer cancel()
+
+	if err := c.client.Set(ctx, redisCacheKeyPrefix+key, value, ttl).Err(); err != nil {
+		log.Printf("[WARN] Redis cache set %s: %v", key, err)
+	}
+}
+
+// Invalidate removes a single key
+func (c *RedisCache) Invalidate(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisCacheTimeout)
+	defer cancel()
+
+	if err := c.client.Del(ctx, redisCacheKeyPrefix+key).Err(); err != nil {
+		log.Printf("[WARN] Redis cache invalidate %s: %v", key, err)
+	}
+}
+
+// InvalidatePrefix removes every key starting with prefix
+func (c *RedisCache) InvalidatePrefix(prefix string) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisCacheTimeout)
+	defer cancel()
+
+	keys, err := c.scan(ctx, prefix)
+	if err == nil && len(keys) > 0 {
+		err = c.client.Del(ctx, keys...).Err()
+	}
+	if err != nil {
+		log.Printf("[WARN] Redis cache invalidate prefix %s: %v", prefix, err)
+	}
+}
+
+// Stats counts the cached keys; hits and misses are those of this instance. Redis
+// expires keys itself, so evictions are always zero.
+func (c *RedisCache) Stats() CacheStats {
+	ctx, cancel := context.WithTimeout(context.Background(), redisCacheTimeout)
+	defer cancel()
+
+	entries := int64(-1)
+	if keys, err := c.scan(ctx, ""); err == nil {
+		entries = int64(len(keys))
+	} else {
+		log.Printf("[WARN] Redis cache stats: %v", err)
+	}
+	stats := CacheStats{Backend: CacheBackendRedis, Entries: entries}
+	c.counters.fill(&stats)
+	return stats
+}
+
+// scan returns the full Redis keys of the cache entries starting with prefix
+func (c *RedisCache) scan(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	iter := c.client.Scan(ctx, 0, redisCacheKeyPrefix+escapeRedisPattern(prefix)+"*", redisScanCount).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// escapeRedisPattern escapes glob metacharacters so a prefix matches literally
+func escapeRedisPattern(s string) string {
+	var escaped []rune
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, r)
+	}
+	return string(escaped)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exerciseCache checks the behaviour every Cache backend must share
+func exerciseCache(t *testing.T, cache Cache) {
+	t.Helper()
+
+	_, found := cache.Get("article:1")
+	assert.False(t, found)
+
+	cacheSetJSON(cache, "article:1", ArticleResponse{ArticleID: 1, Title: "Cached"}, time.Minute)
+	var article ArticleResponse
+	require.True(t, cacheGetJSON(cache, "article:1", &article))
+	assert.Equal(t, "Cached", article.Title)
+
+	raw, found := cache.Get("article:1")
+	require.True(t, found)
+	assert.JSONEq(t, `{"article_id":1,"source":"","url":"","title":"Cached","content":"","published_at":"","composite_score":0,"confidence":0,"score_source":""}`, string(raw))
+
+	cache.Set("bias:1:-1:1:asc", []byte(`{}`), time.Minute)
+	cache.Set("bias:1:-1:1:desc", []byte(`{}`), time.Minute)
+	cache.Set("bias:12:-1:1:asc", []byte(`{}`), time.Minute)
+	cache.InvalidatePrefix("bias:1:")
+	_, found = cache.Get("bias:1:-1:1:asc")
+	assert.False(t, found)
+	_, found = cache.Get("bias:12:-1:1:asc")
+	assert.True(t, found, "prefix invalidation leaves other articles alone")
+
+	cache.Invalidate("article:1")
+	_, found = cache.Get("article:1")
+	assert.False(t, found)
+
+	stats := cache.Stats()
+	assert.Equal(t, int64(1), stats.Entries)
+	assert.Equal(t, uint64(3), stats.Hits)
+	assert.Equal(t, uint64(3), stats.Misses)
+}
+
+func TestSimpleCache(t *testing.T) {
+	cache := NewSimpleCache()
+	exerciseCache(t, cache)
+	assert.Equal(t, CacheBackendMemory, cache.Stats().Backend)
+
+	cache.Set("short", []byte("x"), -time.Second)
+	_, found := cache.Get("short")
+	assert.False(t, found, "expired entries are misses")
+}
+
+func TestRedisCache(t *testing.T) {
+	server := miniredis.RunT(t)
+	cache, err := NewRedisCache("redis://" + server.Addr())
+	require.NoError(t, err)
+	defer cache.Close()
+
+	exerciseCache(t, cache)
+	assert.Equal(t, CacheBackendRedis, cache.Stats().Backend)
+
+	cache.Set("short", []byte("x"), time.Second)
+	server.FastForward(2 * time.Second)
+	_, found := cache.Get("short")
+	assert.False(t, found, "entries expire with their TTL")
+
+	server.Close()
+	_, found = cache.Get("bias:12:-1:1:asc")
+	assert.False(t, found, "an unreachable Redis degrades to misses")
+}
+
+func TestNewCacheFromEnv(t *testing.T) {
+	t.Setenv("CACHE_BACKEND", "")
+	cache, err := NewCacheFromEnv()
+	require.NoError(t, err)
+	assert.IsType(t, &SimpleCache{}, cache)
+
+	t.Setenv("CACHE_BACKEND", "redis")
+	t.Setenv("REDIS_URL", "")
+	_, err = NewCacheFromEnv()
+	assert.Error(t, err, "redis needs REDIS_URL")
+
+	server := miniredis.RunT(t)
+	t.Setenv("REDIS_URL", "redis://"+server.Addr())
+	cache, err = NewCacheFromEnv()
+	require.NoError(t, err)
+	assert.IsType(t, &RedisCache{}, cache)
+}
+
+func TestCacheStatsByPrefix(t *testing.T) {
+	cache := NewSimpleCache()
+	cache.Set("article:1", []byte("a"), time.Minute)
+	cache.Set("summary:1", []byte("s"), -time.Second)
+	cache.Get("article:1")
+	cache.Get("article:2")
+	cache.Get("summary:1")
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.InDelta(t, 1.0/3, stats.HitRate, 1e-9)
+	assert.Equal(t, CacheCounts{Hits: 1, Misses: 1, HitRate: 0.5}, stats.Prefixes["article"])
+	assert.Equal(t, CacheCounts{Misses: 1, Evictions: 1}, stats.Prefixes["summary"])
+}
+
+func TestCacheStatsConcurrent(t *testing.T) {
+	cache := NewSimpleCache()
+	cache.Set("article:1", []byte("a"), time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				cache.Get("article:1")
+				cache.Get("bias:1")
+				_ = cache.Stats()
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(800), stats.Hits)
+	assert.Equal(t, uint64(800), stats.Misses)
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 // ArticlesApiService handles article-related API calls
@@ -15,6 +16,17 @@ type ArticlesApiService service
 
 // GetArticles fetches a list of articles with optional filtering
 func (a *ArticlesApiService) GetArticles(ctx context.Context, params ArticlesParams) ([]Article, error) {
+	articles, _, err := a.getArticles(ctx, params, false)
+	return articles, err
+}
+
+// GetArticlesPage fetches a page of articles together with its pagination metadata.
+// The pagination is nil when the server does not support the paginated envelope.
+func (a *ArticlesApiService) GetArticlesPage(ctx context.Context, params ArticlesParams) ([]Article, *Pagination, error) {
+	return a.getArticles(ctx, params, true)
+}
+
+func (a *ArticlesApiService) getArticles(ctx context.Context, params ArticlesParams, envelope bool) ([]Article, *Pagination, error) {
 	path := "/articles"
 
 	// Build query parameters
@@ -25,12 +37,31 @@ func (a *ArticlesApiService) GetArticles(ctx context.Context, params ArticlesPar
 	if params.Leaning != "" {
 		query.Set("leaning", params.Leaning)
 	}
+	if params.Author != "" {
+		query.Set("author", params.Author)
+	}
+	if params.Category != "" {
+		query.Set("category", params.Category)
+	}
 	if params.Limit > 0 {
 		query.Set("limit", strconv.Itoa(params.Limit))
 	}
 	if params.Offset > 0 {
 		query.Set("offset", strconv.Itoa(params.Offset))
 	}
+	setFloatParam(query, "min_score", params.MinScore)
+	setFloatParam(query, "max_score", params.MaxScore)
+	setFloatParam(query, "min_confidence", params.MinConfidence)
+	setTimeParam(query, "published_after", params.PublishedAfter)
+	setTimeParam(query, "published_before", params.PublishedBefore)
+	setTimeParam(query, "ingested_after", params.IngestedAfter)
+	setTimeParam(query, "ingested_before", params.IngestedBefore)
+	if params.FallbackToIngested {
+		query.Set("fallback_to_ingested", "true")
+	}
+	if envelope {
+		query.Set("envelope", "true")
+	}
 
 	if len(query) > 0 {
 		path += "?" + query.Encode()
@@ -38,7 +69,7 @@ func (a *ArticlesApiService) GetArticles(ctx context.Context, params ArticlesPar
 
 	resp, err := a.client.makeRequest(ctx, "GET", path, nil, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -47,35 +78,52 @@ func (a *ArticlesApiService) GetArticles(ctx context.Context, params ArticlesPar
 	}()
 
 	if err := checkResponse(resp); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var response StandardResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, err
+	return decodeArticleList(body)
+}
+
+// setFloatParam sets an optional numeric query parameter
+func setFloatParam(query url.Values, name string, value *float64) {
+	if value != nil {
+		query.Set(name, strconv.FormatFloat(*value, 'f', -1, 64))
 	}
+}
 
-	// Convert interface{} to []Article
-	if response.Data == nil {
-		return []Article{}, nil
+// setTimeParam sets an optional RFC3339 time query parameter
+func setTimeParam(query url.Values, name string, value *time.Time) {
+	if value != nil {
+		query.Set(name, value.Format(time.RFC3339))
 	}
+}
 
-	articlesData, err := json.Marshal(response.Data)
-	if err != nil {
-		return nil, err
+// decodeArticleList decodes a list response in either shape: the bare list
+// {"data": [...]} or the paginated envelope {"data": [...], "pagination": {...}}
+func decodeArticleList(body []byte) ([]Article, *Pagination, error) {
+	var response struct {
+		Data       json.RawMessage `json:"data"`
+		Pagination *Pagination     `json:"pagination"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, nil, err
+	}
+
+	if len(response.Data) == 0 || string(response.Data) == "null" {
+		return []Article{}, response.Pagination, nil
 	}
 
 	var articles []Article
-	if err := json.Unmarshal(articlesData, &articles); err != nil {
-		return nil, err
+	if err := json.Unmarshal(response.Data, &articles); err != nil {
+		return nil, nil, err
 	}
 
-	return articles, nil
+	return articles, response.Pagination, nil
 }
 
 // GetArticle fetches a single article by ID
@@ -20,8 +20,14 @@ type Configuration struct {
 	DefaultHeader map[string]string `json:"defaultHeader,omitempty"`
 	UserAgent     string            `json:"userAgent,omitempty"`
 	HTTPClient    *http.Client
+	// Transport, when set, replaces HTTPClient's transport for every request, e.g. a
+	// tuned TransportConfig.NewTransport() or a test round-tripper
+	Transport http.RoundTripper `json:"-"`
 }
 
+// defaultTimeout is the overall request timeout of the default HTTP client
+const defaultTimeout = 30 * time.Second
+
 // NewConfiguration creates a new Configuration with default values
 func NewConfiguration() *Configuration {
 	cfg := &Configuration{
@@ -31,7 +37,7 @@ func NewConfiguration() *Configuration {
 		DefaultHeader: make(map[string]string),
 		UserAgent:     "NewsBalancer-Go-Client/1.0.0",
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: defaultTimeout,
 		},
 	}
 	return cfg
@@ -46,6 +52,7 @@ type APIClient struct {
 	ArticlesAPI *ArticlesApiService
 	LLMApi      *LLMApiService
 	FeedsApi    *FeedsApiService
+	MetricsApi  *MetricsApiService
 }
 
 type service struct {
@@ -66,6 +73,7 @@ func NewAPIClient(cfg *Configuration) *APIClient {
 	c.ArticlesAPI = (*ArticlesApiService)(&c.common)
 	c.LLMApi = (*LLMApiService)(&c.common)
 	c.FeedsApi = (*FeedsApiService)(&c.common)
+	c.MetricsApi = (*MetricsApiService)(&c.common)
 
 	return c
 }
@@ -75,10 +83,45 @@ func (c *APIClient) GetConfig() *Configuration {
 	return c.cfg
 }
 
-// makeRequest performs the HTTP request
+// makeRequest performs the HTTP request for a path under the base path
 func (c *APIClient) makeRequest(ctx context.Context, method, path string, body interface{}, headers map[string]string) (*http.Response, error) {
+	return c.doRequest(ctx, c.httpClient(), method, c.cfg.BasePath+path, body, headers)
+}
+
+// makeRootRequest performs the HTTP request for a path outside the base path, such as
+// the /metrics endpoints
+func (c *APIClient) makeRootRequest(ctx context.Context, method, path string) (*http.Response, error) {
+	return c.doRequest(ctx, c.httpClient(), method, path, nil, nil)
+}
+
+// httpClient returns the configured HTTP client, falling back to the default one, with
+// the configured Transport applied
+func (c *APIClient) httpClient() *http.Client {
+	httpClient := c.cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if c.cfg.Transport == nil {
+		return httpClient
+	}
+	withTransport := *httpClient
+	withTransport.Transport = c.cfg.Transport
+	return &withTransport
+}
+
+// streamingHTTPClient returns a copy of the configured HTTP client without the overall
+// request timeout, which would cut off long-lived streams; the context bounds them instead
+func (c *APIClient) streamingHTTPClient() *http.Client {
+	streamClient := *c.httpClient()
+	streamClient.Timeout = 0
+	return &streamClient
+}
+
+// doRequest performs the HTTP request with the given HTTP client. The path is
+// relative to the host, so callers add the base path.
+func (c *APIClient) doRequest(ctx context.Context, httpClient *http.Client, method, path string, body interface{}, headers map[string]string) (*http.Response, error) {
 	// Build URL
-	u, err := url.Parse(c.cfg.Scheme + "://" + c.cfg.Host + c.cfg.BasePath + path)
+	u, err := url.Parse(c.cfg.Scheme + "://" + c.cfg.Host + path)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +158,7 @@ func (c *APIClient) makeRequest(ctx context.Context, method, path string, body i
 	}
 
 	// Make request
-	resp, err := c.cfg.HTTPClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -123,11 +166,13 @@ func (c *APIClient) makeRequest(ctx context.Context, method, path string, body i
 	return resp, nil
 }
 
-// Error represents an API error
+// APIError is the error envelope the server sends: {"error": {code, message, details?}}.
+// StatusCode is the HTTP status of the response it came in.
 type APIError struct {
-	Code    string      `json:"code,omitempty"`
-	Message string      `json:"message,omitempty"`
-	Details interface{} `json:"details,omitempty"`
+	StatusCode int         `json:"-"`
+	Code       string      `json:"code,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	Details    interface{} `json:"details,omitempty"`
 }
 
 func (e APIError) Error() string {
@@ -162,12 +207,10 @@ func checkResponse(resp *http.Response) error {
 		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
 	}
 
-	if apiResp.Error != nil {
-		return APIError{
-			Code:    apiResp.Error.Code,
-			Message: apiResp.Error.Message,
-			Details: apiResp.Error.Details,
-		}
+	if apiResp.Error != nil && apiResp.Error.Code != "" {
+		apiErr := *apiResp.Error
+		apiErr.StatusCode = resp.StatusCode
+		return apiErr
 	}
 
 	return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"strconv"
 )
 
 // LLMApiService handles LLM-related API calls
@@ -55,14 +57,75 @@ func (l *LLMApiService) ReanalyzeArticle(ctx context.Context, id int64, req *Man
 	return fmt.Sprintf("%v", response.Data), nil
 }
 
-// GetScoreProgress gets the progress of a scoring operation via SSE
-// Note: This is a simplified implementation. In a real implementation,
-// you would want to handle Server-Sent Events properly.
-func (l *LLMApiService) GetScoreProgress(ctx context.Context, id int64) (*ProgressState, error) {
+// StartReanalysis queues reanalysis of an article and returns the queued job
+func (l *LLMApiService) StartReanalysis(ctx context.Context, id int64) (*ReanalyzeResponse, error) {
+	path := fmt.Sprintf("/llm/reanalyze/%d", id)
+
+	resp, err := l.client.makeRequest(ctx, "POST", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Warning: failed to close response body: %v", closeErr)
+		}
+	}()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data *ReanalyzeResponse `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, err
+	}
+	if response.Data == nil {
+		return nil, fmt.Errorf("no reanalysis response data")
+	}
+
+	return response.Data, nil
+}
+
+// StreamScoreProgress opens the server-sent event stream of scoring progress for an
+// article. lastEventID resumes a dropped stream when positive. The caller must close
+// the response body.
+func (l *LLMApiService) StreamScoreProgress(ctx context.Context, id int64, lastEventID int64) (*http.Response, error) {
 	path := fmt.Sprintf("/llm/score-progress/%d", id)
 
+	headers := map[string]string{
+		"Accept":        "text/event-stream",
+		"Cache-Control": "no-cache",
+	}
+	if lastEventID > 0 {
+		headers["Last-Event-ID"] = strconv.FormatInt(lastEventID, 10)
+	}
+
+	resp, err := l.client.doRequest(ctx, l.client.streamingHTTPClient(), "GET", l.client.cfg.BasePath+path, nil, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// PollScoreProgress fetches the current scoring progress of an article once, as JSON,
+// for clients that cannot use the event stream. It returns the response's data object.
+func (l *LLMApiService) PollScoreProgress(ctx context.Context, id int64) (json.RawMessage, error) {
+	path := fmt.Sprintf("/llm/score-progress/%d?mode=poll", id)
+
 	resp, err := l.client.makeRequest(ctx, "GET", path, nil, map[string]string{
-		"Accept": "application/json", // Override SSE for now
+		"Cache-Control": "no-cache",
 	})
 	if err != nil {
 		return nil, err
@@ -82,22 +145,28 @@ func (l *LLMApiService) GetScoreProgress(ctx context.Context, id int64) (*Progre
 		return nil, err
 	}
 
-	var response StandardResponse
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
 	if err := json.Unmarshal(body, &response); err != nil {
 		return nil, err
 	}
-
-	if response.Data == nil {
+	if len(response.Data) == 0 || string(response.Data) == "null" {
 		return nil, fmt.Errorf("no progress data returned")
 	}
+	return response.Data, nil
+}
 
-	progressData, err := json.Marshal(response.Data)
+// GetScoreProgress gets the current progress of a scoring operation; see
+// PollScoreProgress
+func (l *LLMApiService) GetScoreProgress(ctx context.Context, id int64) (*ProgressState, error) {
+	data, err := l.PollScoreProgress(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	var progress ProgressState
-	if err := json.Unmarshal(progressData, &progress); err != nil {
+	if err := json.Unmarshal(data, &progress); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+)
+
+// MetricsApiService handles the /metrics endpoints. They sit outside the /api base
+// path and return bare JSON lists rather than the standard response envelope.
+type MetricsApiService service
+
+// GetValidationMetrics gets daily label counts and confidence
+func (m *MetricsApiService) GetValidationMetrics(ctx context.Context) ([]ValidationMetric, error) {
+	var out []ValidationMetric
+	return out, m.getList(ctx, "/metrics/validation", &out)
+}
+
+// GetFeedbackSummary gets daily feedback counts per category
+func (m *MetricsApiService) GetFeedbackSummary(ctx context.Context) ([]FeedbackSummary, error) {
+	var out []FeedbackSummary
+	return out, m.getList(ctx, "/metrics/feedback", &out)
+}
+
+// GetUncertaintyRates gets the daily share of low-confidence scores
+func (m *MetricsApiService) GetUncertaintyRates(ctx context.Context) ([]UncertaintyRate, error) {
+	var out []UncertaintyRate
+	return out, m.getList(ctx, "/metrics/uncertainty", &out)
+}
+
+// GetDisagreements gets articles whose feedback disagrees
+func (m *MetricsApiService) GetDisagreements(ctx context.Context) ([]Disagreement, error) {
+	var out []Disagreement
+	return out, m.getList(ctx, "/metrics/disagreements", &out)
+}
+
+// GetOutliers gets articles whose model scores spread widely
+func (m *MetricsApiService) GetOutliers(ctx context.Context) ([]OutlierScore, error) {
+	var out []OutlierScore
+	return out, m.getList(ctx, "/metrics/outliers", &out)
+}
+
+// getList decodes the JSON list at path into out. A null body leaves out empty.
+func (m *MetricsApiService) getList(ctx context.Context, path string, out interface{}) error {
+	resp, err := m.client.makeRootRequest(ctx, "GET", path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Warning: failed to close response body: %v", closeErr)
+		}
+	}()
+
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -14,6 +14,9 @@ type Article struct {
 	CompositeScore float64   `json:"CompositeScore,omitempty"`
 	Confidence     float64   `json:"Confidence,omitempty"`
 	ScoreSource    string    `json:"ScoreSource,omitempty"`
+	ImageURL       string    `json:"image_url,omitempty"`
+	Authors        []string  `json:"authors,omitempty"`
+	Categories     []string  `json:"categories,omitempty"`
 	// BiasLabel field removed - not present in database
 }
 
@@ -32,12 +35,31 @@ type CreateArticleResponse struct {
 	Status    string `json:"status,omitempty"`
 }
 
+// Pagination describes the page returned by a list endpoint
+type Pagination struct {
+	Total   int  `json:"total"`
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"has_more"`
+}
+
 // ArticlesParams represents parameters for fetching articles
 type ArticlesParams struct {
-	Source  string `json:"source,omitempty"`
-	Leaning string `json:"leaning,omitempty"`
-	Limit   int    `json:"limit,omitempty"`
-	Offset  int    `json:"offset,omitempty"`
+	Source        string   `json:"source,omitempty"`
+	Leaning       string   `json:"leaning,omitempty"`
+	Author        string   `json:"author,omitempty"`
+	Category      string   `json:"category,omitempty"`
+	Limit         int      `json:"limit,omitempty"`
+	Offset        int      `json:"offset,omitempty"`
+	MinScore      *float64 `json:"min_score,omitempty"`
+	MaxScore      *float64 `json:"max_score,omitempty"`
+	MinConfidence *float64 `json:"min_confidence,omitempty"`
+
+	PublishedAfter     *time.Time `json:"published_after,omitempty"`
+	PublishedBefore    *time.Time `json:"published_before,omitempty"`
+	IngestedAfter      *time.Time `json:"ingested_after,omitempty"`
+	IngestedBefore     *time.Time `json:"ingested_before,omitempty"`
+	FallbackToIngested bool       `json:"fallback_to_ingested,omitempty"`
 }
 
 // ScoreResponse represents a bias score response
@@ -82,5 +104,50 @@ type ProgressState struct {
 	LastUpdated  int64   `json:"last_updated,omitempty"`
 }
 
+// ReanalyzeResponse is returned when a reanalysis job is queued
+type ReanalyzeResponse struct {
+	Status    string `json:"status,omitempty"`
+	ArticleID int64  `json:"article_id,omitempty"`
+}
+
 // FeedHealth represents feed health status
 type FeedHealth map[string]bool
+
+// ValidationMetric is one day's count and mean confidence of labels with one label,
+// from GET /metrics/validation
+type ValidationMetric struct {
+	Day           string  `json:"day"`
+	Label         string  `json:"label"`
+	LabelCount    int     `json:"label_count"`
+	AvgConfidence float64 `json:"avg_confidence"`
+}
+
+// FeedbackSummary is one day's feedback count in one category, from GET /metrics/feedback
+type FeedbackSummary struct {
+	Day           string `json:"day"`
+	Category      string `json:"category"`
+	FeedbackCount int    `json:"feedback_count"`
+}
+
+// UncertaintyRate is one day's share of low-confidence scores, from GET /metrics/uncertainty
+type UncertaintyRate struct {
+	Day               string  `json:"day"`
+	LowConfidenceRate float64 `json:"low_confidence_ratio"`
+}
+
+// Disagreement is an article whose feedback spans several categories, from
+// GET /metrics/disagreements
+type Disagreement struct {
+	ArticleID          int64     `json:"article_id"`
+	DistinctCategories int       `json:"distinct_categories"`
+	LastFeedbackTime   time.Time `json:"last_feedback_time"`
+}
+
+// OutlierScore is an article whose model scores spread widely, from GET /metrics/outliers
+type OutlierScore struct {
+	ArticleID  int64   `json:"article_id"`
+	MaxScore   float64 `json:"max_score"`
+	MinScore   float64 `json:"min_score"`
+	ScoreRange float64 `json:"score_range"`
+	ScoreCount int     `json:"score_count"`
+}
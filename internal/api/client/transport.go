@@ -0,0 +1,66 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig tunes connection pooling, timeouts and keep-alives. Zero values keep
+// the settings of http.DefaultTransport.
+type TransportConfig struct {
+	// MaxIdleConns caps idle connections across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps idle connections kept per host; Go's default is 2, which
+	// is too low for many concurrent requests to the same server
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps all connections per host, including active ones
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection stays in the pool
+	IdleConnTimeout time.Duration
+	// DialTimeout bounds establishing a TCP connection
+	DialTimeout time.Duration
+	// KeepAlive is the TCP keep-alive probe interval
+	KeepAlive time.Duration
+	// TLSHandshakeTimeout bounds the TLS handshake
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout bounds the wait for response headers after the request is sent
+	ResponseHeaderTimeout time.Duration
+	// DisableKeepAlives opens a new connection for every request
+	DisableKeepAlives bool
+}
+
+// NewTransport returns a copy of http.DefaultTransport with the tuning applied
+func (t TransportConfig) NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if t.MaxIdleConns > 0 {
+		transport.MaxIdleConns = t.MaxIdleConns
+	}
+	if t.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+	}
+	if t.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = t.MaxConnsPerHost
+	}
+	if t.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = t.IdleConnTimeout
+	}
+	if t.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = t.TLSHandshakeTimeout
+	}
+	if t.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = t.ResponseHeaderTimeout
+	}
+	if t.DialTimeout > 0 || t.KeepAlive > 0 {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		if t.DialTimeout > 0 {
+			dialer.Timeout = t.DialTimeout
+		}
+		if t.KeepAlive > 0 {
+			dialer.KeepAlive = t.KeepAlive
+		}
+		transport.DialContext = dialer.DialContext
+	}
+	transport.DisableKeepAlives = t.DisableKeepAlives
+	return transport
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultGzipMinSize is the smallest response body, in bytes, that is compressed.
+// Smaller bodies gain little and cost a gzip header.
+const defaultGzipMinSize = 1024
+
+// compressibleTypes are the media types worth compressing
+var compressibleTypes = []string{
+	"application/json", "application/xml", "application/javascript", "application/problem+json",
+	"text/html", "text/csv", "text/plain", "text/xml", "text/css", "text/javascript",
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} { return gzip.NewWriter(nil) },
+}
+
+// Compressor gzips responses for clients that accept it. Bodies are buffered until
+// they reach minSize, so small responses and streams go out unchanged.
+type Compressor struct {
+	minSize int
+}
+
+// NewCompressor compresses responses of at least minSize bytes
+func NewCompressor(minSize int) *Compressor {
+	if minSize < 0 {
+		minSize = 0
+	}
+	return &Compressor{minSize: minSize}
+}
+
+// NewCompressorFromEnv builds the compressor, returning nil when GZIP_ENABLED is false.
+// GZIP_MIN_SIZE sets the threshold in bytes.
+func NewCompressorFromEnv() *Compressor {
+	if v := os.Getenv("GZIP_ENABLED"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Printf("[WARN] Invalid GZIP_ENABLED %q, using default true", v)
+		} else if !enabled {
+			return nil
+		}
+	}
+
+	minSize := defaultGzipMinSize
+	if v := os.Getenv("GZIP_MIN_SIZE"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Printf("[WARN] Invalid GZIP_MIN_SIZE %q, using default %d", v, defaultGzipMinSize)
+		} else {
+			minSize = parsed
+		}
+	}
+	return NewCompressor(minSize)
+}
+
+// Middleware compresses eligible responses. It must run before middleware that reads
+// response bodies, such as the request logger, so they see the uncompressed body.
+// Server-sent event streams are never compressed. A nil compressor is a no-op.
+func (cp *Compressor) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cp == nil || c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			c.Next()
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: c.Writer, minSize: cp.minSize}
+		c.Writer = writer
+		defer writer.finish()
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isCompressible reports whether a Content-Type is worth compressing
+func isCompressible(contentType string) bool {
+	mediaType := strings.ToLower(mediaTypeOrUnknown(contentType))
+	for _, t := range compressibleTypes {
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter holds back the body until it knows whether to compress it: once
+// minSize bytes are buffered it compresses, and a flush or the end of the handler
+// sends whatever is buffered as is
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minSize int
+	buf     bytes.Buffer
+	gz      *gzip.Writer
+	decided bool
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf.Write(b)
+	if w.buf.Len() >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports whether the handler has written a body, including buffered bytes
+func (w *gzipResponseWriter) Written() bool {
+	return w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Flush sends the buffered body uncompressed when nothing was decided yet, since a
+// handler that flushes is streaming
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	return w.ResponseWriter.Hijack()
+}
+
+// decide picks compression or passthrough and writes out the buffered body
+func (w *gzipResponseWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.Header()
+	status := w.Status()
+	compress = compress &&
+		header.Get("Content-Encoding") == "" &&
+		status != http.StatusNoContent && status != http.StatusNotModified &&
+		isCompressible(header.Get("Content-Type"))
+
+	if isCompressible(header.Get("Content-Type")) {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	if compress {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		// The compressed bytes differ from the representation a strong ETag names, so
+		// the tag is weakened; it still matches the uncompressed response weakly
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		w.gz = gzipWriterPool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if w.gz != nil {
+		_, err = w.gz.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+	return err
+}
+
+// finish sends a body that stayed below minSize and closes the gzip stream
+func (w *gzipResponseWriter) finish() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.gz == nil {
+		return
+	}
+	if err := w.gz.Close(); err != nil {
+		log.Printf("[WARN] Failed to finish gzip response: %v", err)
+	}
+	w.gz.Reset(nil)
+	gzipWriterPool.Put(w.gz)
+	w.gz = nil
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressorMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	large := strings.Repeat("balanced ", 200)
+
+	router := gin.New()
+	router.Use(NewCompressor(1024).Middleware())
+	router.GET("/large", func(c *gin.Context) {
+		c.Header("ETag", `"v1"`)
+		c.JSON(http.StatusOK, gin.H{"text": large})
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"text": "short"})
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Writer.Flush()
+		_, _ = c.Writer.Write([]byte("data: " + large + "\n\n"))
+		c.Writer.Flush()
+	})
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	plain := get("/large", "")
+	assert.Empty(t, plain.Header().Get("Content-Encoding"))
+	assert.Equal(t, `"v1"`, plain.Header().Get("ETag"))
+
+	w := get("/large", "br, gzip")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, `W/"v1"`, w.Header().Get("ETag"), "a compressed body carries the weak form of the same tag")
+	assert.Less(t, w.Body.Len(), plain.Body.Len())
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, plain.Body.String(), string(body))
+
+	w = get("/large", "gzip;q=0")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+
+	w = get("/small", "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"), "bodies below the threshold are sent as is")
+	assert.JSONEq(t, `{"text":"short"}`, w.Body.String())
+
+	w = get("/stream", "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"), "event streams are never compressed")
+	assert.Contains(t, w.Body.String(), "data: balanced")
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	assert.True(t, acceptsGzip("gzip"))
+	assert.True(t, acceptsGzip("deflate, GZIP;q=0.5"))
+	assert.True(t, acceptsGzip("*"))
+	assert.False(t, acceptsGzip(""))
+	assert.False(t, acceptsGzip("br"))
+	assert.False(t, acceptsGzip("gzip; q=0"))
+}
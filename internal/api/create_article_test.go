@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateArticleHandlerIngestion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// Scoring jobs record a skipped state instead of calling the LLM
+	t.Setenv("NO_AUTO_ANALYZE", "true")
+
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "ingest.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	scoreManager := llm.NewScoreManager(dbConn, llm.NewCache(), nil, pm)
+	client := llm.NewLLMClientWithService(dbConn, llm.NewFixtureLLMService(llm.Fixture{}), &llm.CompositeScoreConfig{})
+
+	router := gin.New()
+	router.POST("/api/articles", createArticleHandler(dbConn, NewIngestScorer(IngestScoringOff, client, dbConn, scoreManager)))
+	post := func(body string) (*httptest.ResponseRecorder, CreatedArticleResponse) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		var resp struct {
+			Data CreatedArticleResponse `json:"data"`
+		}
+		if w.Code == http.StatusCreated {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w, resp.Data
+	}
+
+	w, created := post(`{"title":"Pushed","content":"Body","url":"https://partner.example/1","source":"partner","published_at":"2024-05-01T10:00:00Z"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.NotZero(t, created.ArticleID)
+	assert.Zero(t, created.JobID, "no job unless scoring is requested")
+	assert.Equal(t, "2024-05-01T10:00:00Z", created.PublishedAt)
+
+	w, _ = post(`{"title":"Again","content":"Body","url":"https://partner.example/1","source":"partner","published_at":"2024-05-01T10:00:00Z"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w, created = post(`{"title":"Scored","content":"Body","url":"https://partner.example/2","source":"partner","published_at":"2024-05-02T10:00:00Z","score":true}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, created.ArticleID, created.JobID)
+	assert.Equal(t, IngestScoringImmediate, created.Scoring)
+	progress := scoreManager.GetProgress(created.JobID)
+	require.NotNil(t, progress)
+	assert.Equal(t, "Skipped", progress.Status)
+
+	w, _ = post(`{"title":"Legacy","content":"Body","url":"https://partner.example/3","source":"partner","pub_date":"2024-05-03T10:00:00Z"}`)
+	assert.Equal(t, http.StatusCreated, w.Code, "pub_date is still accepted")
+
+	w, created = post(`{"title":"Opt out","content":"Body","url":"https://partner.example/5","source":"partner","published_at":"2024-05-03T10:00:00Z","score":false}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Empty(t, created.Scoring)
+	assert.Nil(t, scoreManager.GetProgress(created.ArticleID))
+
+	w, _ = post(`{"title":"No date","content":"Body","url":"https://partner.example/4","source":"partner"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "published_at")
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticlesDateFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "dates.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	day := func(d int) time.Time { return time.Date(2025, time.March, d, 12, 0, 0, 0, time.UTC) }
+	seed := []struct {
+		title    string
+		pubDate  time.Time
+		ingested time.Time
+	}{
+		{"early", day(1), day(2)},
+		{"middle", day(10), day(10)},
+		{"late", day(20), day(21)},
+		{"undated", time.Time{}, day(11)},
+	}
+	for i, a := range seed {
+		_, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, created_at, url, title, content)
+			VALUES ('bbc', ?, ?, ?, ?, 'Body')`, a.pubDate, a.ingested, fmt.Sprintf("https://example.com/d/%d", i), a.title)
+		require.NoError(t, err)
+	}
+
+	router := gin.New()
+	router.GET("/api/articles", getArticlesHandler(dbConn))
+	titles := func(query string) []string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles?envelope=true&"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data       []ArticleResponse `json:"data"`
+			Pagination Pagination        `json:"pagination"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, len(resp.Data), resp.Pagination.Total, "the total honours the filters")
+		var out []string
+		for _, a := range resp.Data {
+			out = append(out, a.Title)
+		}
+		return out
+	}
+
+	assert.ElementsMatch(t, []string{"middle", "late"}, titles("published_after=2025-03-05T00:00:00Z"))
+	assert.ElementsMatch(t, []string{"early"}, titles("published_before=2025-03-10T12:00:00Z"), "the upper bound is exclusive")
+	assert.ElementsMatch(t, []string{"middle", "undated"}, titles("ingested_after=2025-03-05T00:00:00Z&ingested_before=2025-03-15T00:00:00Z"))
+	assert.ElementsMatch(t, []string{"middle"}, titles("published_after=2025-03-05T00:00:00Z&published_before=2025-03-15T00:00:00Z"))
+	assert.ElementsMatch(t, []string{"middle", "undated"},
+		titles("published_after=2025-03-05T00:00:00Z&published_before=2025-03-15T00:00:00Z&fallback_to_ingested=true"))
+	assert.ElementsMatch(t, []string{"middle", "late"}, titles("published_after=2025-03-10T14:00:00%2B02:00"), "offsets are honoured")
+
+	// 01:00 at +05:00 is 20:00 UTC the day before; as text it would sort after 22:00 UTC
+	_, err = db.InsertArticle(dbConn, &db.Article{Source: "bbc", URL: "https://example.com/d/offset", Title: "offset",
+		Content: "Body", PubDate: time.Date(2025, time.March, 25, 1, 0, 0, 0, time.FixedZone("", 5*3600)), CreatedAt: day(25)})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"offset"},
+		titles("published_after=2025-03-24T00:00:00Z&published_before=2025-03-24T22:00:00Z"), "stored offsets are honoured")
+	assert.Empty(t, titles("published_after=2025-03-24T21:00:00Z&published_before=2025-03-26T00:00:00Z"))
+
+	for _, query := range []string{
+		"published_after=yesterday",
+		"ingested_before=2025-03-01",
+		"published_after=2025-03-10T00:00:00Z&published_before=2025-03-01T00:00:00Z",
+		"fallback_to_ingested=maybe",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -94,9 +94,7 @@ func scoreProgressHandler(pm *llm.ProgressManager) gin.HandlerFunc {
 		articleIDStr := c.Param("id")
 		articleID, err := strconv.ParseInt(articleIDStr, 10, 64)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid article ID",
-			})
+			RespondError(c, ErrInvalidArticleID)
 			return
 		}
 		// Set SSE headers
@@ -109,9 +107,7 @@ func scoreProgressHandler(pm *llm.ProgressManager) gin.HandlerFunc {
 		// Get flusher for immediate sending
 		flusher, ok := c.Writer.(http.Flusher)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Streaming unsupported",
-			})
+			RespondError(c, NewAppError(ErrInternal, "Streaming unsupported"))
 			return
 		}
 
@@ -1,17 +1,31 @@
 package api
 
 import (
+	"context"
+	"errors"
+
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/apperrors"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
 )
 
 // Pre-defined error codes
 const (
-	ErrValidation = "validation_error"
-	ErrNotFound   = "not_found"
-	ErrInternal   = "internal_error"
-	ErrRateLimit  = "rate_limit"
-	ErrLLMService = "llm_service_error"
-	ErrConflict   = "conflict_error"
+	ErrValidation    = "validation_error"
+	ErrNotFound      = "not_found"
+	ErrInternal      = "internal_error"
+	ErrRateLimit     = "rate_limit"
+	ErrLLMService    = "llm_service_error"
+	ErrConflict      = "conflict_error"
+	ErrAuth          = "unauthorized"
+	ErrNotAcceptable = "not_acceptable"
+	ErrTimeout       = "timeout"
+	ErrDuplicate     = "duplicate_url"
+	ErrTooLarge      = "payload_too_large"
+	// Scoring outcomes that leave an article without a usable composite
+	ErrNoValidScores     = "no_valid_scores"
+	ErrZeroConfidence    = "zero_confidence"
+	ErrInsufficientScore = "insufficient_models"
 )
 
 // Error constants for consistent error messages
@@ -58,15 +72,40 @@ var (
 		Message: "LLM service unavailable",
 	}
 
-	ErrDuplicateURL = &apperrors.AppError{
+	ErrShuttingDown = &apperrors.AppError{
+		Code:    ErrLLMService,
+		Message: "Server is shutting down; try again later",
+	}
+
+	ErrRequestTimeout = &apperrors.AppError{
+		Code:    ErrTimeout,
+		Message: "Request timed out; try again later",
+	}
+
+	ErrReanalysisInProgress = &apperrors.AppError{
 		Code:    ErrConflict,
+		Message: "A reanalysis of this article is already queued or running",
+	}
+
+	ErrDuplicateURL = &apperrors.AppError{
+		Code:    ErrDuplicate,
 		Message: "Article with this URL already exists",
 	}
 
+	ErrUnauthorized = &apperrors.AppError{
+		Code:    ErrAuth,
+		Message: "Missing or invalid API key",
+	}
+
 	ErrInvalidScore = &apperrors.AppError{
 		Code:    ErrValidation,
 		Message: "Score must be between -1.0 and 1.0",
 	}
+
+	ErrNotAcceptableFormat = &apperrors.AppError{
+		Code:    ErrNotAcceptable,
+		Message: "Unsupported Accept type; use application/json, text/csv or application/xml",
+	}
 )
 
 // Feedback-specific errors
@@ -139,3 +178,42 @@ func WrapError(err error, code string, context string) *apperrors.AppError {
 	}
 	return NewAppError(code, message)
 }
+
+// WithDetails returns a copy of err carrying details in the response envelope, leaving
+// the shared predefined errors untouched
+func WithDetails(err *apperrors.AppError, details interface{}) *apperrors.AppError {
+	if err == nil {
+		return nil
+	}
+	withDetails := *err
+	withDetails.Details = details
+	return &withDetails
+}
+
+// domainErrors maps errors raised below the API layer to the API errors clients see.
+// Order matters: a zero-confidence failure is also reported as having no valid scores.
+var domainErrors = []struct {
+	target error
+	apiErr *apperrors.AppError
+}{
+	{llm.ErrAllScoresZeroConfidence, NewAppError(ErrZeroConfidence, "All models returned zero confidence")},
+	{llm.ErrInsufficientModels, NewAppError(ErrInsufficientScore, "Too few models produced a valid score")},
+	{llm.ErrAllPerspectivesInvalid, NewAppError(ErrNoValidScores, "No valid model scores to build a composite from")},
+	{llm.ErrNoModelScores, NewAppError(ErrNoValidScores, "Article has no per-model scores")},
+	{llm.ErrShuttingDown, ErrShuttingDown},
+	{llm.ErrJobInProgress, ErrReanalysisInProgress},
+	{db.ErrArticleNotFound, ErrArticleNotFound},
+	{db.ErrDuplicateURL, ErrDuplicateURL},
+	{context.DeadlineExceeded, ErrRequestTimeout},
+}
+
+// domainError returns the API error for a known domain error, with the original error
+// text as details, or nil when err is not one
+func domainError(err error) *apperrors.AppError {
+	for _, d := range domainErrors {
+		if errors.Is(err, d.target) {
+			return WithDetails(d.apiErr, map[string]interface{}{"reason": err.Error()})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/apperrors"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxFeedbackBatch caps how many items one feedback batch may hold
+const maxFeedbackBatch = 500
+
+// Outcomes of a feedback batch item
+const (
+	feedbackInserted  = "inserted"
+	feedbackDuplicate = "duplicate"
+	feedbackFailed    = "failed"
+)
+
+// validFeedbackCategories are the accepted feedback categories; empty means none given
+var validFeedbackCategories = map[string]bool{"agree": true, "disagree": true, "unclear": true, "other": true, "": true}
+
+// validateFeedback checks the fields every feedback submission needs
+func validateFeedback(articleID int64, userID, text, category string) *apperrors.AppError {
+	var missingFields []string
+	if articleID == 0 {
+		missingFields = append(missingFields, "article_id")
+	}
+	if text == "" {
+		missingFields = append(missingFields, "feedback_text")
+	}
+	if userID == "" {
+		missingFields = append(missingFields, "user_id")
+	}
+	if len(missingFields) > 0 {
+		return NewAppError(ErrValidation, "Missing required fields: "+strings.Join(missingFields, ", "))
+	}
+	if !validFeedbackCategories[category] {
+		return ErrInvalidCategory
+	}
+	return nil
+}
+
+// FeedbackBatchItemRequest is one feedback item of a batch. An item sent again with the
+// same idempotency key is not stored twice.
+type FeedbackBatchItemRequest struct {
+	FeedbackRequest
+	IdempotencyKey string `json:"idempotency_key,omitempty" example:"form-export-2024-05-01-17"`
+}
+
+// FeedbackBatchRequest is the body of POST /api/feedback/batch
+type FeedbackBatchRequest struct {
+	Items []FeedbackBatchItemRequest `json:"items" binding:"required"`
+}
+
+// FeedbackBatchItem is the outcome for one item of a feedback batch
+type FeedbackBatchItem struct {
+	Index          int    `json:"index"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	Status         string `json:"status" example:"inserted"` // inserted, duplicate or failed
+	FeedbackID     int64  `json:"feedback_id,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// FeedbackBatchResponse summarises a feedback batch
+type FeedbackBatchResponse struct {
+	Inserted   int                 `json:"inserted"`
+	Duplicates int                 `json:"duplicates"`
+	Failed     int                 `json:"failed"`
+	Results    []FeedbackBatchItem `json:"results"`
+}
+
+// feedbackBatchHandler handles POST /api/feedback/batch
+// @Summary Submit feedback in bulk
+// @Description Stores up to 500 feedback items in one transaction. Each item is validated
+// @Description and stored on its own, so invalid or failing items are reported in the
+// @Description results without rejecting the rest. Items whose idempotency key is already
+// @Description stored are reported as duplicates. Unlike single submissions, a batch does
+// @Description not adjust article confidence.
+// @Tags Feedback
+// @Accept json
+// @Produce json
+// @Param request body FeedbackBatchRequest true "Feedback items"
+// @Success 200 {object} StandardResponse{data=FeedbackBatchResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 500 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/feedback/batch [post]
+// @ID submitFeedbackBatch
+func feedbackBatchHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req FeedbackBatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondError(c, NewAppError(ErrValidation, "Invalid request body: 'items' is required"))
+			return
+		}
+		if len(req.Items) == 0 || len(req.Items) > maxFeedbackBatch {
+			RespondError(c, NewAppError(ErrValidation, fmt.Sprintf("'items' must hold between 1 and %d feedback items", maxFeedbackBatch)))
+			return
+		}
+
+		resp := FeedbackBatchResponse{Results: make([]FeedbackBatchItem, len(req.Items))}
+		feedback := make([]*db.Feedback, 0, len(req.Items))
+		positions := make([]int, 0, len(req.Items)) // index in the request of each stored item
+		now := time.Now()
+		for i, item := range req.Items {
+			key := strings.TrimSpace(item.IdempotencyKey)
+			resp.Results[i] = FeedbackBatchItem{Index: i, IdempotencyKey: key}
+			if appErr := validateFeedback(item.ArticleID, item.UserID, item.FeedbackText, item.Category); appErr != nil {
+				resp.Results[i].Status = feedbackFailed
+				resp.Results[i].Error = appErr.Message
+				continue
+			}
+			f := &db.Feedback{
+				ArticleID:        item.ArticleID,
+				UserID:           item.UserID,
+				FeedbackText:     item.FeedbackText,
+				Category:         item.Category,
+				EnsembleOutputID: item.EnsembleOutputID,
+				Source:           item.Source,
+				CreatedAt:        now,
+			}
+			if key != "" {
+				f.IdempotencyKey = &key
+			}
+			feedback = append(feedback, f)
+			positions = append(positions, i)
+		}
+
+		if len(feedback) > 0 {
+			stored, err := db.InsertFeedbackBatch(dbConn, feedback)
+			if err != nil {
+				RespondError(c, WrapError(err, ErrInternal, "Failed to store feedback batch"))
+				return
+			}
+			for j, result := range stored {
+				item := &resp.Results[positions[j]]
+				item.FeedbackID = result.ID
+				switch {
+				case result.Err != nil:
+					item.Status = feedbackFailed
+					item.Error = result.Err.Error()
+				case result.Duplicate:
+					item.Status = feedbackDuplicate
+				default:
+					item.Status = feedbackInserted
+				}
+			}
+		}
+
+		for _, item := range resp.Results {
+			switch item.Status {
+			case feedbackInserted:
+				resp.Inserted++
+			case feedbackDuplicate:
+				resp.Duplicates++
+			default:
+				resp.Failed++
+			}
+		}
+		RespondSuccess(c, resp)
+	}
+}
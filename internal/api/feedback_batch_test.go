@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedbackBatchHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "feedback.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	router := gin.New()
+	router.POST("/api/feedback/batch", feedbackBatchHandler(dbConn))
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/feedback/batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	body := `{"items": [
+		{"article_id": 1, "user_id": "u1", "feedback_text": "fair", "category": "agree", "idempotency_key": "form-1"},
+		{"article_id": 1, "user_id": "u2", "feedback_text": "slanted", "category": "angry", "idempotency_key": "form-2"},
+		{"article_id": 2, "feedback_text": "no user"},
+		{"article_id": 2, "user_id": "u3", "feedback_text": "unsure", "category": "unclear", "source": "form"}
+	]}`
+	w := post(body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data FeedbackBatchResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Data.Inserted)
+	assert.Equal(t, 2, resp.Data.Failed)
+	require.Len(t, resp.Data.Results, 4)
+	assert.Equal(t, feedbackInserted, resp.Data.Results[0].Status)
+	assert.NotZero(t, resp.Data.Results[0].FeedbackID)
+	assert.Equal(t, feedbackFailed, resp.Data.Results[1].Status)
+	assert.Equal(t, ErrInvalidCategory.Message, resp.Data.Results[1].Error)
+	assert.Equal(t, "Missing required fields: user_id", resp.Data.Results[2].Error)
+	assert.Equal(t, 3, resp.Data.Results[3].Index)
+	assert.Equal(t, feedbackInserted, resp.Data.Results[3].Status)
+
+	// Resending the export stores the keyed item only once
+	w = post(body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Data.Duplicates)
+	assert.Equal(t, feedbackDuplicate, resp.Data.Results[0].Status)
+	assert.Equal(t, "form-1", resp.Data.Results[0].IdempotencyKey)
+
+	var count int
+	require.NoError(t, dbConn.Get(&count, `SELECT COUNT(*) FROM feedback`))
+	assert.Equal(t, 3, count)
+
+	for _, invalid := range []string{`{}`, `{"items": []}`, `not json`} {
+		assert.Equal(t, http.StatusBadRequest, post(invalid).Code, invalid)
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// Page sizes for the feedback moderation list
+const (
+	defaultFeedbackLimit = 50
+	maxFeedbackLimit     = 500
+)
+
+// FeedbackStatusRequest is the body of PUT /api/admin/feedback/:id/status
+type FeedbackStatusRequest struct {
+	Status string `json:"status" binding:"required" example:"spam"` // new, reviewed or spam
+}
+
+// adjustFeedbackConfidence stores the article's composite with its confidence nudged by
+// the latest feedback that is not spam: up 0.1 for agree, down 0.1 for disagree
+func adjustFeedbackConfidence(dbConn *sqlx.DB, config *llm.CompositeScoreConfig, articleID int64, scores []db.LLMScore) error {
+	category, err := db.FetchLatestFeedbackCategory(dbConn, articleID)
+	if err != nil {
+		return err
+	}
+	score, confidence, err := llm.ComputeCompositeScoreWithConfidence(scores, config)
+	if err != nil {
+		log.Printf("[API DEBUG] Error computing composite score for article %d: %v", articleID, err)
+		return nil
+	}
+	switch category {
+	case "agree":
+		confidence = math.Min(1.0, confidence+0.1) // Increase confidence on agreement
+	case "disagree":
+		confidence = math.Max(0.0, confidence-0.1) // Decrease confidence on disagreement
+	}
+	return db.UpdateArticleScore(dbConn, articleID, score, confidence)
+}
+
+// listFeedbackHandler handles GET /api/admin/feedback
+// @Summary List feedback for moderation
+// @Description Lists feedback newest first, optionally only that with one moderation status
+// @Tags Feedback
+// @Produce json
+// @Param status query string false "Moderation status: new, reviewed or spam"
+// @Param limit query int false "Page size (1-500, default 50)"
+// @Param offset query int false "Items to skip"
+// @Success 200 {object} StandardResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/admin/feedback [get]
+// @ID listFeedback
+func listFeedbackHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := c.Query("status")
+		if status != "" && !db.ValidFeedbackStatus(status) {
+			RespondError(c, NewAppError(ErrValidation, "status must be one of: new, reviewed, spam"))
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultFeedbackLimit)))
+		if err != nil || limit < 1 || limit > maxFeedbackLimit {
+			RespondError(c, NewAppError(ErrValidation, fmt.Sprintf("limit must be between 1 and %d", maxFeedbackLimit)))
+			return
+		}
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			RespondError(c, NewAppError(ErrValidation, "offset must be a non-negative integer"))
+			return
+		}
+
+		feedback, total, err := db.FetchFeedback(dbConn, status, limit, offset)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch feedback"))
+			return
+		}
+		RespondSuccess(c, map[string]interface{}{
+			"feedback": feedback,
+			"total":    total,
+			"limit":    limit,
+			"offset":   offset,
+		})
+	}
+}
+
+// updateFeedbackStatusHandler handles PUT /api/admin/feedback/:id/status
+// @Summary Moderate feedback
+// @Description Sets a feedback item's moderation status. The article's confidence is then
+// @Description adjusted again from its latest feedback that is not spam.
+// @Tags Feedback
+// @Accept json
+// @Produce json
+// @Param id path int true "Feedback ID" minimum(1)
+// @Param request body FeedbackStatusRequest true "New status"
+// @Success 200 {object} StandardResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/admin/feedback/{id}/status [put]
+// @ID updateFeedbackStatus
+func updateFeedbackStatusHandler(dbConn *sqlx.DB, llmClient *llm.LLMClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || id < 1 {
+			RespondError(c, NewAppError(ErrValidation, "Invalid feedback ID (must be a positive integer)"))
+			return
+		}
+		var req FeedbackStatusRequest
+		if err := c.ShouldBindJSON(&req); err != nil || !db.ValidFeedbackStatus(req.Status) {
+			RespondError(c, NewAppError(ErrValidation, "status must be one of: new, reviewed, spam"))
+			return
+		}
+
+		if err := db.UpdateFeedbackStatus(dbConn, id, req.Status); err != nil {
+			if errors.Is(err, db.ErrFeedbackNotFound) {
+				RespondError(c, NewAppError(ErrNotFound, "Feedback not found"))
+				return
+			}
+			RespondError(c, WrapError(err, ErrInternal, "Failed to update feedback status"))
+			return
+		}
+		feedback, err := db.FetchFeedbackByID(dbConn, id)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch feedback"))
+			return
+		}
+
+		// Marking feedback as spam, or restoring it, changes which feedback counts
+		if llmClient != nil && llmClient.GetConfig() != nil {
+			if scores, err := db.FetchLLMScoresContext(c.Request.Context(), dbConn, feedback.ArticleID); err == nil && len(scores) > 0 {
+				if err := adjustFeedbackConfidence(dbConn, llmClient.GetConfig(), feedback.ArticleID, scores); err != nil {
+					LogError(c, err, "updateFeedbackStatusHandler: update article confidence")
+				}
+			}
+		}
+		RespondSuccess(c, feedback)
+	}
+}
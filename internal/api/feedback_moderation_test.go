@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedbackModerationHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "moderation.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	cfg := &llm.CompositeScoreConfig{
+		Formula:  "average",
+		MinScore: -1,
+		MaxScore: 1,
+		Models:   []llm.ModelConfig{{ModelName: "left-model", Perspective: "left"}, {ModelName: "right-model", Perspective: "right"}},
+	}
+	client := llm.NewLLMClientWithService(dbConn, llm.NewFixtureLLMService(llm.Fixture{}), cfg)
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('src', CURRENT_TIMESTAMP, 'https://example.com/moderation', 'title', 'content')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	for model, score := range map[string]float64{"left-model": -0.4, "right-model": 0.2} {
+		_, err := db.InsertLLMScore(dbConn, &db.LLMScore{
+			ArticleID: articleID, Model: model, Score: score, Metadata: `{"confidence": 0.5}`, Version: 1, CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	scores, err := db.FetchLLMScores(dbConn, articleID)
+	require.NoError(t, err)
+	_, baseConfidence, err := llm.ComputeCompositeScoreWithConfidence(scores, cfg)
+	require.NoError(t, err)
+
+	older := &db.Feedback{ArticleID: articleID, UserID: "u1", FeedbackText: "biased", Category: "disagree", CreatedAt: time.Now().Add(-time.Hour)}
+	spam := &db.Feedback{ArticleID: articleID, UserID: "bot", FeedbackText: "buy now", Category: "agree", CreatedAt: time.Now()}
+	require.NoError(t, db.InsertFeedback(dbConn, older))
+	require.NoError(t, db.InsertFeedback(dbConn, spam))
+
+	router := gin.New()
+	router.GET("/api/admin/feedback", listFeedbackHandler(dbConn))
+	router.PUT("/api/admin/feedback/:id/status", updateFeedbackStatusHandler(dbConn, client))
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	articleConfidence := func() float64 {
+		article, err := db.FetchArticleByID(dbConn, articleID)
+		require.NoError(t, err)
+		require.NotNil(t, article.Confidence)
+		return *article.Confidence
+	}
+
+	w := request(http.MethodPut, fmt.Sprintf("/api/admin/feedback/%d/status", spam.ID), `{"status": "spam"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var updated struct {
+		Data db.Feedback `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, db.FeedbackStatusSpam, updated.Data.Status)
+	assert.InDelta(t, baseConfidence-0.1, articleConfidence(), 1e-9, "only the disagreement still counts")
+
+	w = request(http.MethodPut, fmt.Sprintf("/api/admin/feedback/%d/status", spam.ID), `{"status": "reviewed"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.InDelta(t, baseConfidence+0.1, articleConfidence(), 1e-9)
+
+	w = request(http.MethodGet, "/api/admin/feedback?status=reviewed", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		Data struct {
+			Feedback []db.Feedback `json:"feedback"`
+			Total    int           `json:"total"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Data.Total)
+	require.Len(t, list.Data.Feedback, 1)
+	assert.Equal(t, spam.ID, list.Data.Feedback[0].ID)
+
+	assert.Equal(t, http.StatusNotFound, request(http.MethodPut, "/api/admin/feedback/999/status", `{"status": "spam"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPut, fmt.Sprintf("/api/admin/feedback/%d/status", older.ID), `{"status": "deleted"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPut, "/api/admin/feedback/x/status", `{"status": "spam"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodGet, "/api/admin/feedback?status=hidden", "").Code)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// articleFieldAliases are extra names accepted by ?fields= for article fields
+var articleFieldAliases = map[string]string{"id": "article_id"}
+
+// articleFieldValues gives each selectable article field's JSON value. The keys are
+// the allowlist for ?fields= and match the JSON names and CSV columns.
+var articleFieldValues = map[string]func(a ArticleResponse) interface{}{
+	"article_id":      func(a ArticleResponse) interface{} { return a.ArticleID },
+	"source":          func(a ArticleResponse) interface{} { return a.Source },
+	"url":             func(a ArticleResponse) interface{} { return a.URL },
+	"title":           func(a ArticleResponse) interface{} { return a.Title },
+	"content":         func(a ArticleResponse) interface{} { return a.Content },
+	"published_at":    func(a ArticleResponse) interface{} { return a.PublishedAt },
+	"composite_score": func(a ArticleResponse) interface{} { return a.Composite },
+	"confidence":      func(a ArticleResponse) interface{} { return a.Confidence },
+	"score_source":    func(a ArticleResponse) interface{} { return a.ScoreSource },
+	"image_url":       func(a ArticleResponse) interface{} { return a.ImageURL },
+	"authors":         func(a ArticleResponse) interface{} { return a.Authors },
+	"categories":      func(a ArticleResponse) interface{} { return a.Categories },
+}
+
+// articleFields is a sparse fieldset from ?fields=. A nil set selects every field.
+type articleFields map[string]bool
+
+// Has reports whether a field is selected
+func (f articleFields) Has(name string) bool {
+	return f == nil || f[name]
+}
+
+// Project returns the selected fields of an article, leaving out empty optional fields
+// just as the full JSON response does
+func (f articleFields) Project(a ArticleResponse) map[string]interface{} {
+	out := make(map[string]interface{}, len(f))
+	for name := range f {
+		value := articleFieldValues[name](a)
+		switch v := value.(type) {
+		case string:
+			if v == "" && name == "image_url" {
+				continue
+			}
+		case []string:
+			if len(v) == 0 {
+				continue
+			}
+		}
+		out[name] = value
+	}
+	return out
+}
+
+// parseArticleFields reads the optional comma-separated ?fields= parameter. Unknown
+// field names are answered with 400 and false; an absent or empty parameter selects
+// every field.
+func parseArticleFields(c *gin.Context) (articleFields, bool) {
+	v := strings.TrimSpace(c.Query("fields"))
+	if v == "" {
+		return nil, true
+	}
+	fields := articleFields{}
+	for _, name := range strings.Split(v, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if canonical, ok := articleFieldAliases[name]; ok {
+			name = canonical
+		}
+		if _, ok := articleFieldValues[name]; !ok {
+			RespondError(c, WithDetails(NewAppError(ErrValidation, fmt.Sprintf("Unknown field %q", name)),
+				map[string]interface{}{"allowed": articleCSVHeader}))
+			return nil, false
+		}
+		fields[name] = true
+	}
+	if len(fields) == 0 {
+		return nil, true
+	}
+	return fields, true
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleSparseFieldsets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "fields.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score, confidence)
+		VALUES ('cnn', CURRENT_TIMESTAMP, 'https://example.com/fields', 'Sparse', 'A long body', 0.25, 0.8)`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	invalidateArticleScoreCache(articleID)
+
+	router := gin.New()
+	router.GET("/api/articles", getArticlesHandler(dbConn))
+	router.GET("/api/articles/:id", getArticleByIDHandler(dbConn))
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/articles?fields=id,title,composite_score", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, map[string]interface{}{
+		"article_id": float64(articleID), "title": "Sparse", "composite_score": 0.25,
+	}, list.Data[0])
+
+	w = get("/api/articles?envelope=true&fields=title", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"title":"Sparse"}]`, mustJSONField(t, w.Body.Bytes(), "data"))
+	assert.Contains(t, w.Body.String(), `"pagination"`)
+
+	w = get(fmt.Sprintf("/api/articles/%d?fields=url,%%20content", articleID), "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"url":"https://example.com/fields","content":"A long body"}`, mustJSONField(t, w.Body.Bytes(), "data"))
+
+	w = get("/api/articles?fields=title,source", "text/csv")
+	require.Equal(t, http.StatusOK, w.Code)
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"source", "title"}, {"cnn", "Sparse"}}, records, "CSV keeps the usual column order")
+
+	w = get("/api/articles", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"content":"A long body"`, "without fields the full object is returned")
+
+	for _, path := range []string{"/api/articles?fields=title,password", fmt.Sprintf("/api/articles/%d?fields=bias_label", articleID)} {
+		w = get(path, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		var resp ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, ErrValidation, resp.Error.Code)
+		assert.NotNil(t, resp.Error.Details)
+	}
+}
+
+func mustJSONField(t *testing.T, body []byte, field string) string {
+	var resp map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(body, &resp))
+	return string(resp[field])
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Media types supported by the article endpoints
+const (
+	MediaTypeJSON = "application/json"
+	MediaTypeCSV  = "text/csv"
+	MediaTypeXML  = "application/xml"
+)
+
+// articleEncoder writes article responses in one output format, limited to the
+// selected fields where the format supports sparse fieldsets
+type articleEncoder interface {
+	WriteList(c *gin.Context, articles []ArticleResponse, fields articleFields)
+	WriteOne(c *gin.Context, article ArticleResponse, fields articleFields)
+	// WritePage writes a list together with its pagination metadata
+	WritePage(c *gin.Context, articles []ArticleResponse, page Pagination, fields articleFields)
+}
+
+// articleFormat pairs a media type (and its aliases) with its encoder. Add an entry to
+// articleFormats to support a new format; the first entry is the default.
+type articleFormat struct {
+	mediaTypes []string
+	encoder    articleEncoder
+}
+
+var articleFormats = []articleFormat{
+	{mediaTypes: []string{MediaTypeJSON}, encoder: jsonArticleEncoder{}},
+	{mediaTypes: []string{MediaTypeCSV}, encoder: csvArticleEncoder{}},
+	{mediaTypes: []string{MediaTypeXML, "text/xml"}, encoder: xmlArticleEncoder{}},
+}
+
+// acceptRange is one media range from an Accept header
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept returns the media ranges of an Accept header, most preferred first
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if mediaType == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	return ranges
+}
+
+// matchesMediaRange reports whether a media type falls within a media range such as
+// text/csv, text/* or */*
+func matchesMediaRange(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(mediaRange, "/*"); ok {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+	return false
+}
+
+// negotiateArticleEncoder picks the encoder for the request's Accept header, defaulting
+// to JSON. When no supported format is acceptable it responds 406 and returns false.
+func negotiateArticleEncoder(c *gin.Context) (articleEncoder, bool) {
+	header := c.GetHeader("Accept")
+	if strings.TrimSpace(header) == "" {
+		return articleFormats[0].encoder, true
+	}
+	for _, r := range parseAccept(header) {
+		if r.q <= 0 {
+			continue
+		}
+		for _, format := range articleFormats {
+			for _, mediaType := range format.mediaTypes {
+				if matchesMediaRange(r.mediaType, mediaType) {
+					return format.encoder, true
+				}
+			}
+		}
+	}
+	RespondError(c, ErrNotAcceptableFormat)
+	return nil, false
+}
+
+// jsonArticleEncoder writes the standard JSON envelope
+type jsonArticleEncoder struct{}
+
+func (e jsonArticleEncoder) WriteList(c *gin.Context, articles []ArticleResponse, fields articleFields) {
+	RespondSuccess(c, e.data(articles, fields))
+}
+
+func (jsonArticleEncoder) WriteOne(c *gin.Context, article ArticleResponse, fields articleFields) {
+	if fields != nil {
+		RespondSuccess(c, fields.Project(article))
+		return
+	}
+	RespondSuccess(c, article)
+}
+
+func (e jsonArticleEncoder) WritePage(c *gin.Context, articles []ArticleResponse, page Pagination, fields articleFields) {
+	c.JSON(http.StatusOK, PaginatedResponse{Success: true, Data: e.data(articles, fields), Pagination: page})
+}
+
+// data returns the list as sent: never null, and projected to a sparse fieldset
+func (jsonArticleEncoder) data(articles []ArticleResponse, fields articleFields) interface{} {
+	if fields == nil {
+		if articles == nil {
+			return []ArticleResponse{}
+		}
+		return articles
+	}
+	projected := make([]map[string]interface{}, 0, len(articles))
+	for _, a := range articles {
+		projected = append(projected, fields.Project(a))
+	}
+	return projected
+}
+
+// articleCSVHeader lists the CSV columns, matching the JSON field names
+var articleCSVHeader = []string{
+	"article_id", "source", "url", "title", "content",
+	"published_at", "composite_score", "confidence", "score_source", "image_url",
+	"authors", "categories",
+}
+
+// articleCSVListSeparator joins list fields such as authors into one CSV cell
+const articleCSVListSeparator = "; "
+
+// csvArticleEncoder writes one row per article after a header row, with only the
+// selected columns for a sparse fieldset
+type csvArticleEncoder struct{}
+
+func (e csvArticleEncoder) WriteList(c *gin.Context, articles []ArticleResponse, fields articleFields) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", MediaTypeCSV+"; charset=utf-8")
+	var columns []int
+	for i, name := range articleCSVHeader {
+		if fields.Has(name) {
+			columns = append(columns, i)
+		}
+	}
+	selected := func(row []string) []string {
+		out := make([]string, 0, len(columns))
+		for _, i := range columns {
+			out = append(out, row[i])
+		}
+		return out
+	}
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(selected(articleCSVHeader))
+	for _, a := range articles {
+		_ = w.Write(selected([]string{
+			strconv.FormatInt(a.ArticleID, 10),
+			a.Source,
+			a.URL,
+			a.Title,
+			a.Content,
+			a.PublishedAt,
+			strconv.FormatFloat(a.Composite, 'f', -1, 64),
+			strconv.FormatFloat(a.Confidence, 'f', -1, 64),
+			a.ScoreSource,
+			a.ImageURL,
+			strings.Join(a.Authors, articleCSVListSeparator),
+			strings.Join(a.Categories, articleCSVListSeparator),
+		}))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		LogError(c, err, "csvArticleEncoder: write")
+	}
+}
+
+func (e csvArticleEncoder) WriteOne(c *gin.Context, article ArticleResponse, fields articleFields) {
+	e.WriteList(c, []ArticleResponse{article}, fields)
+}
+
+// WritePage writes the rows only; the X-Total-Count header carries the total
+func (e csvArticleEncoder) WritePage(c *gin.Context, articles []ArticleResponse, _ Pagination, fields articleFields) {
+	e.WriteList(c, articles, fields)
+}
+
+// articleXML names a single article element
+type articleXML struct {
+	XMLName xml.Name `xml:"article"`
+	ArticleResponse
+}
+
+// articleListXML wraps a list of articles in an <articles> element
+type articleListXML struct {
+	XMLName    xml.Name          `xml:"articles"`
+	Pagination *Pagination       `xml:"pagination,omitempty"`
+	Articles   []ArticleResponse `xml:"article"`
+}
+
+// xmlArticleEncoder writes <articles> or <article> documents. Its schema is fixed, so
+// sparse fieldsets are ignored and every field is written.
+type xmlArticleEncoder struct{}
+
+func (xmlArticleEncoder) WriteList(c *gin.Context, articles []ArticleResponse, _ articleFields) {
+	c.XML(http.StatusOK, articleListXML{Articles: articles})
+}
+
+func (xmlArticleEncoder) WriteOne(c *gin.Context, article ArticleResponse, _ articleFields) {
+	c.XML(http.StatusOK, articleXML{ArticleResponse: article})
+}
+
+func (xmlArticleEncoder) WritePage(c *gin.Context, articles []ArticleResponse, page Pagination, _ articleFields) {
+	c.XML(http.StatusOK, articleListXML{Pagination: &page, Articles: articles})
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAccept(t *testing.T) {
+	ranges := parseAccept("text/html;q=0.5, text/csv, application/*;q=0.8")
+	require.Len(t, ranges, 3)
+	assert.Equal(t, "text/csv", ranges[0].mediaType)
+	assert.Equal(t, "application/*", ranges[1].mediaType)
+	assert.Equal(t, 0.5, ranges[2].q)
+
+	assert.True(t, matchesMediaRange("*/*", MediaTypeCSV))
+	assert.True(t, matchesMediaRange("text/*", MediaTypeCSV))
+	assert.False(t, matchesMediaRange("text/*", MediaTypeJSON))
+}
+
+func TestArticleContentNegotiation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "format.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score, confidence, image_url)
+		VALUES ('cnn', CURRENT_TIMESTAMP, 'https://example.com/format', 'Title, with comma', 'Body "quoted"', 0.25, 0.8,
+			'https://example.com/format.jpg')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	invalidateArticleScoreCache(articleID)
+
+	router := gin.New()
+	router.GET("/api/articles", getArticlesHandler(dbConn))
+	router.GET("/api/articles/:id", getArticleByIDHandler(dbConn))
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+	articlePath := fmt.Sprintf("/api/articles/%d", articleID)
+
+	for _, path := range []string{"/api/articles", articlePath} {
+		t.Run("JSON default"+path, func(t *testing.T) {
+			for _, accept := range []string{"", "*/*", "application/json"} {
+				w := get(path, accept)
+				require.Equal(t, http.StatusOK, w.Code)
+				assert.Contains(t, w.Header().Get("Content-Type"), MediaTypeJSON)
+				var resp StandardResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.True(t, resp.Success)
+				assert.Contains(t, w.Body.String(), `"image_url":"https://example.com/format.jpg"`)
+			}
+		})
+
+		t.Run("CSV"+path, func(t *testing.T) {
+			w := get(path, "text/csv")
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), MediaTypeCSV)
+			records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+			require.NoError(t, err)
+			require.Len(t, records, 2)
+			assert.Equal(t, articleCSVHeader, records[0])
+			assert.Equal(t, "Title, with comma", records[1][3])
+			assert.Equal(t, `Body "quoted"`, records[1][4])
+			assert.Equal(t, "0.25", records[1][6])
+			assert.Equal(t, "https://example.com/format.jpg", records[1][9])
+		})
+
+		t.Run("XML"+path, func(t *testing.T) {
+			w := get(path, "application/xml;q=0.9, application/json;q=0.1")
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), MediaTypeXML)
+			assert.Contains(t, w.Body.String(), "<title>Title, with comma</title>")
+		})
+
+		t.Run("NotAcceptable"+path, func(t *testing.T) {
+			for _, accept := range []string{"text/html", "image/*", "application/json;q=0"} {
+				w := get(path, accept)
+				assert.Equal(t, http.StatusNotAcceptable, w.Code, accept)
+			}
+		})
+	}
+
+	var list articleListXML
+	require.NoError(t, xml.Unmarshal(get("/api/articles", "application/xml").Body.Bytes(), &list))
+	require.Len(t, list.Articles, 1)
+	assert.Equal(t, articleID, list.Articles[0].ArticleID)
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/jmoiron/sqlx"
+)
+
+// Ingestion scoring policies, selected with INGEST_SCORING_POLICY
+const (
+	// IngestScoringOff leaves new articles unscored (the default)
+	IngestScoringOff = "off"
+	// IngestScoringImmediate queues every new article for scoring straight away
+	IngestScoringImmediate = "immediate"
+	// IngestScoringDeferred collects new articles and queues them as a batch every
+	// INGEST_SCORING_BATCH_INTERVAL
+	IngestScoringDeferred = "deferred"
+	// IngestScoringConditional scores straight away only articles from sources flagged
+	// with {"auto_score": true} in their metadata
+	IngestScoringConditional = "conditional"
+)
+
+// IngestScoringInProgress is reported instead of IngestScoringImmediate when the article
+// already had a scoring job queued or running, which keeps running in place of a new one
+const IngestScoringInProgress = "in_progress"
+
+// defaultIngestBatchInterval is how often deferred articles are queued for scoring
+const defaultIngestBatchInterval = 15 * time.Minute
+
+// IngestScorer applies the ingestion scoring policy to articles stored by the RSS
+// collector and POST /api/articles. Scoring runs as a regular reanalysis job, so it
+// waits for a free job slot like any other reanalysis.
+type IngestScorer struct {
+	policy       string
+	llmClient    *llm.LLMClient
+	dbConn       *sqlx.DB
+	scoreManager *llm.ScoreManager
+
+	mu       sync.Mutex
+	deferred []int64 // article IDs waiting for the next batch
+	stopChan chan struct{}
+	stopped  bool
+}
+
+// NewIngestScorer creates a scorer for policy. Unknown policies fall back to off.
+func NewIngestScorer(policy string, llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *llm.ScoreManager) *IngestScorer {
+	switch policy {
+	case IngestScoringOff, IngestScoringImmediate, IngestScoringDeferred, IngestScoringConditional:
+	default:
+		log.Printf("[WARN] Invalid ingestion scoring policy %q, using %s", policy, IngestScoringOff)
+		policy = IngestScoringOff
+	}
+	return &IngestScorer{
+		policy:       policy,
+		llmClient:    llmClient,
+		dbConn:       dbConn,
+		scoreManager: scoreManager,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// NewIngestScorerFromEnv creates the scorer for INGEST_SCORING_POLICY and starts its
+// deferred batch routine
+func NewIngestScorerFromEnv(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *llm.ScoreManager) *IngestScorer {
+	scorer := NewIngestScorer(IngestScoringPolicyFromEnv(), llmClient, dbConn, scoreManager)
+	scorer.Start(ingestBatchIntervalFromEnv())
+	log.Printf("Ingestion scoring policy: %s", scorer.Policy())
+	return scorer
+}
+
+// IngestScoringPolicyFromEnv reads INGEST_SCORING_POLICY. NO_AUTO_ANALYZE=true
+// forces the policy off.
+func IngestScoringPolicyFromEnv() string {
+	if os.Getenv("NO_AUTO_ANALYZE") == "true" {
+		return IngestScoringOff
+	}
+	policy := strings.ToLower(strings.TrimSpace(os.Getenv("INGEST_SCORING_POLICY")))
+	if policy == "" {
+		return IngestScoringOff
+	}
+	return policy
+}
+
+// ingestBatchIntervalFromEnv reads INGEST_SCORING_BATCH_INTERVAL, falling back to the default
+func ingestBatchIntervalFromEnv() time.Duration {
+	v := os.Getenv("INGEST_SCORING_BATCH_INTERVAL")
+	if v == "" {
+		return defaultIngestBatchInterval
+	}
+	interval, err := time.ParseDuration(v)
+	if err != nil || interval <= 0 {
+		log.Printf("[WARN] Invalid INGEST_SCORING_BATCH_INTERVAL %q, using default %v", v, defaultIngestBatchInterval)
+		return defaultIngestBatchInterval
+	}
+	return interval
+}
+
+// Policy returns the active ingestion scoring policy
+func (s *IngestScorer) Policy() string {
+	return s.policy
+}
+
+// ArticleIngested applies the policy to a newly stored article and returns what was
+// done with it: IngestScoringImmediate, IngestScoringInProgress, IngestScoringDeferred
+// or "" when it is left unscored. sourceAutoScore reports whether the article's source is flagged for
+// auto-scoring.
+func (s *IngestScorer) ArticleIngested(articleID int64, sourceAutoScore bool) string {
+	if s == nil {
+		return ""
+	}
+	switch s.policy {
+	case IngestScoringImmediate:
+		return s.ScoreNow(articleID)
+	case IngestScoringConditional:
+		if sourceAutoScore {
+			return s.ScoreNow(articleID)
+		}
+	case IngestScoringDeferred:
+		s.mu.Lock()
+		s.deferred = append(s.deferred, articleID)
+		s.mu.Unlock()
+		return IngestScoringDeferred
+	}
+	return ""
+}
+
+// ScoreNow queues articleID for scoring regardless of the policy, returning
+// IngestScoringImmediate, IngestScoringInProgress when the article is already being
+// scored, or "" when scoring is unavailable
+func (s *IngestScorer) ScoreNow(articleID int64) string {
+	if s == nil || s.llmClient == nil || s.scoreManager == nil {
+		return ""
+	}
+	// An article already being scored keeps its running job
+	if err := startReanalysisJob(s.llmClient, s.dbConn, s.scoreManager, articleID, false); err != nil {
+		log.Printf("[INFO] Article %d not queued for scoring: %v", articleID, err)
+		if errors.Is(err, llm.ErrJobInProgress) {
+			return IngestScoringInProgress
+		}
+		return ""
+	}
+	return IngestScoringImmediate
+}
+
+// FlushDeferred queues every deferred article for scoring and returns how many there were
+func (s *IngestScorer) FlushDeferred() int {
+	s.mu.Lock()
+	batch := s.deferred
+	s.deferred = nil
+	s.mu.Unlock()
+
+	for _, articleID := range batch {
+		s.ScoreNow(articleID)
+	}
+	if len(batch) > 0 {
+		log.Printf("[IngestScorer] Queued %d deferred articles for scoring", len(batch))
+	}
+	return len(batch)
+}
+
+// Start flushes the deferred batch every interval until Stop is called. It does
+// nothing unless the policy is deferred.
+func (s *IngestScorer) Start(interval time.Duration) {
+	if s.policy != IngestScoringDeferred {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.FlushDeferred()
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the batch routine started by Start
+func (s *IngestScorer) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.stopChan)
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestScorerPolicies(t *testing.T) {
+	// Scoring jobs record a skipped state instead of calling the LLM
+	t.Setenv("NO_AUTO_ANALYZE", "true")
+
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "ingest_policy.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	scoreManager := llm.NewScoreManager(dbConn, llm.NewCache(), nil, pm)
+	client := llm.NewLLMClientWithService(dbConn, llm.NewFixtureLLMService(llm.Fixture{}), &llm.CompositeScoreConfig{})
+	newScorer := func(policy string) *IngestScorer {
+		return NewIngestScorer(policy, client, dbConn, scoreManager)
+	}
+
+	assert.Equal(t, "", newScorer(IngestScoringOff).ArticleIngested(1, true))
+	assert.Nil(t, scoreManager.GetProgress(1))
+
+	assert.Equal(t, IngestScoringImmediate, newScorer(IngestScoringImmediate).ArticleIngested(2, false))
+	require.NotNil(t, scoreManager.GetProgress(2), "immediate scoring starts a job")
+
+	conditional := newScorer(IngestScoringConditional)
+	assert.Equal(t, "", conditional.ArticleIngested(3, false))
+	assert.Equal(t, IngestScoringImmediate, conditional.ArticleIngested(4, true))
+
+	deferred := newScorer(IngestScoringDeferred)
+	assert.Equal(t, IngestScoringDeferred, deferred.ArticleIngested(5, false))
+	assert.Equal(t, IngestScoringDeferred, deferred.ArticleIngested(6, false))
+	assert.Nil(t, scoreManager.GetProgress(5), "deferred articles wait for the batch")
+	assert.Equal(t, 2, deferred.FlushDeferred())
+	assert.NotNil(t, scoreManager.GetProgress(5))
+	assert.NotNil(t, scoreManager.GetProgress(6))
+	assert.Equal(t, 0, deferred.FlushDeferred())
+
+	assert.Equal(t, IngestScoringOff, newScorer("sometimes").Policy())
+}
+
+func TestScoreNowReportsRunningJob(t *testing.T) {
+	t.Setenv("NO_AUTO_ANALYZE", "")
+
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	scoreManager := llm.NewScoreManager(nil, llm.NewCache(), nil, pm)
+	client := llm.NewLLMClientWithService(nil, llm.NewFixtureLLMService(llm.Fixture{}), &llm.CompositeScoreConfig{})
+	scorer := NewIngestScorer(IngestScoringImmediate, client, nil, scoreManager)
+
+	_, done, err := scoreManager.TryStartJob(context.Background(), 7)
+	require.NoError(t, err)
+	defer done()
+	assert.Equal(t, IngestScoringInProgress, scorer.ScoreNow(7), "the running job is kept, not replaced")
+	assert.Equal(t, IngestScoringInProgress, scorer.ArticleIngested(7, false))
+}
+
+func TestIngestScoringPolicyFromEnv(t *testing.T) {
+	t.Setenv("NO_AUTO_ANALYZE", "")
+	t.Setenv("INGEST_SCORING_POLICY", "")
+	assert.Equal(t, IngestScoringOff, IngestScoringPolicyFromEnv())
+	t.Setenv("INGEST_SCORING_POLICY", " Deferred ")
+	assert.Equal(t, IngestScoringDeferred, IngestScoringPolicyFromEnv())
+	t.Setenv("NO_AUTO_ANALYZE", "true")
+	assert.Equal(t, IngestScoringOff, IngestScoringPolicyFromEnv())
+}
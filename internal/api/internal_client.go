@@ -28,6 +28,12 @@ type InternalArticlesParams struct {
 	Leaning string
 	Limit   int
 	Offset  int
+	// Sort is a db.ArticleSort value; empty sorts newest first
+	Sort string
+	// Optional publication date bounds, After inclusive and Before exclusive. Articles
+	// without a publication date are bounded by their ingestion date.
+	PublishedAfter  *time.Time
+	PublishedBefore *time.Time
 }
 
 // InternalArticle represents an article in the internal API
@@ -43,10 +49,24 @@ type InternalArticle struct {
 	ScoreSource    string    `json:"score_source"`
 	Bias           string    `json:"bias"`
 	Summary        string    `json:"summary"`
+	ImageURL       string    `json:"image_url,omitempty"`
+	Authors        []string  `json:"authors,omitempty"`
+	Categories     []string  `json:"categories,omitempty"`
 }
 
 // GetArticles fetches articles using the same logic as the HTTP API handler
 func (c *InternalAPIClient) GetArticles(ctx context.Context, params InternalArticlesParams) ([]InternalArticle, error) {
+	articles, _, err := c.getArticles(params, false)
+	return articles, err
+}
+
+// GetArticlesPage fetches a page of articles with the same pagination metadata as
+// the HTTP API's envelope
+func (c *InternalAPIClient) GetArticlesPage(ctx context.Context, params InternalArticlesParams) ([]InternalArticle, Pagination, error) {
+	return c.getArticles(params, true)
+}
+
+func (c *InternalAPIClient) getArticles(params InternalArticlesParams, withCount bool) ([]InternalArticle, Pagination, error) {
 	// Use the same logic as getArticlesHandler but return data directly
 	source := params.Source
 	if source == "all" || source == "" {
@@ -67,9 +87,28 @@ func (c *InternalAPIClient) GetArticles(ctx context.Context, params InternalArti
 	if offset < 0 {
 		offset = 0
 	} // Fetch articles from database using the same method as the HTTP handler
-	dbArticles, err := db.FetchArticles(c.dbConn, source, leaning, limit, offset)
+	filter := db.ArticleFilter{
+		Source:                      source,
+		Leaning:                     leaning,
+		Limit:                       limit,
+		Offset:                      offset,
+		Sort:                        params.Sort,
+		PublishedAfter:              params.PublishedAfter,
+		PublishedBefore:             params.PublishedBefore,
+		PublishedFallbackToIngested: true,
+	}
+	dbArticles, err := db.FetchArticlesFiltered(c.dbConn, filter)
 	if err != nil {
-		return nil, err
+		return nil, Pagination{}, err
+	}
+
+	var page Pagination
+	if withCount {
+		total, err := db.CountArticlesFiltered(c.dbConn, filter)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		page = NewPagination(total, limit, offset, len(dbArticles))
 	}
 
 	// Convert to internal format
@@ -102,6 +141,11 @@ func (c *InternalAPIClient) GetArticles(ctx context.Context, params InternalArti
 			Confidence:     confidence,
 			ScoreSource:    scoreSource,
 			Summary:        "", // No summary field in the database model
+			Authors:        dbArticle.Authors,
+			Categories:     dbArticle.Categories,
+		}
+		if dbArticle.ImageURL != nil {
+			articles[i].ImageURL = *dbArticle.ImageURL
 		}
 		// Determine bias label based on composite score
 		if dbArticle.CompositeScore != nil {
@@ -117,7 +161,7 @@ func (c *InternalAPIClient) GetArticles(ctx context.Context, params InternalArti
 		}
 	}
 
-	return articles, nil
+	return articles, page, nil
 }
 
 // GetArticle fetches a single article by ID
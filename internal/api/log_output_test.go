@@ -0,0 +1,13 @@
+package api
+
+import (
+	"log"
+	"os"
+)
+
+// restoreLogOutput gives the standard logger its stderr output back. Some older tests
+// in this package finish by setting it to nil, after which any logging code under test
+// would panic.
+func restoreLogOutput() {
+	log.SetOutput(os.Stderr)
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManualScoreOverrideEndpoints(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "override.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score, confidence, score_source)
+		VALUES ('cnn', CURRENT_TIMESTAMP, 'https://example.com/a', 'title', 'content', 0.6, 0.8, 'llm')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	_, err = dbConn.Exec(`INSERT INTO llm_scores (article_id, model, score, metadata) VALUES (?, 'ensemble', 0.6, '{}')`, articleID)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.PUT("/api/articles/:id/manual-score", setManualScoreOverrideHandler(dbConn))
+	router.DELETE("/api/articles/:id/manual-score", clearManualScoreOverrideHandler(dbConn))
+	router.POST("/api/manual-score/:id", manualScoreHandler(dbConn))
+	router.GET("/api/articles/:id/bias", biasHandler(dbConn))
+
+	path := fmt.Sprintf("/api/articles/%d/manual-score", articleID)
+	biasPath := fmt.Sprintf("/api/articles/%d/bias", articleID)
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	biasData := func() map[string]interface{} {
+		w := do(http.MethodGet, biasPath, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp StandardResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.(map[string]interface{})
+	}
+
+	t.Run("Validation", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, path, `{"reason":"x"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, path, `{"score":1.5,"reason":"x"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, path, `{"score":0.1,"reason":"  "}`).Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodPut, "/api/articles/999/manual-score", `{"score":0.1,"reason":"x"}`).Code)
+	})
+
+	t.Run("ClearWithoutOverride", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, path, "").Code)
+	})
+
+	t.Run("PinAndClear", func(t *testing.T) {
+		// Other tests share the package-level cache; start clean, then prime it
+		// so the override has to invalidate it
+		invalidateArticleScoreCache(articleID)
+		assert.InDelta(t, 0.6, biasData()["composite_score"], 1e-9)
+
+		w := do(http.MethodPut, path, `{"score":-0.2,"reason":"satire misread"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		data := biasData()
+		assert.InDelta(t, -0.2, data["composite_score"], 1e-9)
+		assert.InDelta(t, 0.6, data["model_composite_score"], 1e-9)
+		assert.Equal(t, db.ScoreSourceManual, data["score_source"])
+		override := data["manual_override"].(map[string]interface{})
+		assert.Equal(t, "satire misread", override["reason"])
+		for _, r := range data["results"].([]interface{}) {
+			assert.NotEqual(t, db.ManualScoreModel, r.(map[string]interface{})["model"])
+		}
+
+		w = do(http.MethodDelete, path, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		data = biasData()
+		assert.InDelta(t, 0.6, data["composite_score"], 1e-9)
+		assert.NotContains(t, data, "manual_override")
+	})
+
+	t.Run("LegacyPostPinsScore", func(t *testing.T) {
+		invalidateArticleScoreCache(articleID)
+		assert.InDelta(t, 0.6, biasData()["composite_score"], 1e-9)
+
+		legacyPath := fmt.Sprintf("/api/manual-score/%d", articleID)
+		w := do(http.MethodPost, legacyPath, `{"score":0.3}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "true", w.Header().Get("Deprecation"))
+		assert.Contains(t, w.Header().Get("Link"), path)
+
+		// A second legacy call on the now-pinned article must still take effect
+		w = do(http.MethodPost, legacyPath, `{"score":0.4}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		data := biasData()
+		assert.InDelta(t, 0.4, data["composite_score"], 1e-9)
+		assert.Equal(t, db.ScoreSourceManual, data["score_source"])
+		assert.Equal(t, legacyManualScoreReason, data["manual_override"].(map[string]interface{})["reason"])
+
+		require.Equal(t, http.StatusOK, do(http.MethodDelete, path, "").Code)
+		article, err := db.FetchArticleByID(dbConn, articleID)
+		require.NoError(t, err)
+		require.NotNil(t, article.Confidence)
+		assert.InDelta(t, 0.8, *article.Confidence, 1e-9, "clearing restores the confidence from before the override")
+	})
+}
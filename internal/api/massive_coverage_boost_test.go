@@ -79,7 +79,7 @@ func TestAdditionalHandlerCoverage(t *testing.T) {
 		assert.Equal(t, 400, w.Code)
 
 		// Test createArticleHandler with invalid JSON
-		createHandler := createArticleHandler(db)
+		createHandler := createArticleHandler(db, NewIngestScorer(IngestScoringOff, nil, db, nil))
 		router2 := gin.New()
 		router2.POST("/articles", createHandler)
 
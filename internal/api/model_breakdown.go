@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// modelAgreementMaxDiff is the largest score difference at which two models agree
+const modelAgreementMaxDiff = 0.25
+
+// ModelScoreBreakdown is the latest score recorded for one model
+type ModelScoreBreakdown struct {
+	Model       string    `json:"model"`
+	Perspective string    `json:"perspective,omitempty"`
+	Score       float64   `json:"score"`
+	Confidence  *float64  `json:"confidence,omitempty"`
+	Label       string    `json:"label"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ModelAgreement compares the scores of two models. Models agree when their scores
+// are within 0.25 of each other and are opposed when one leans left and the other right.
+type ModelAgreement struct {
+	ModelA     string  `json:"model_a"`
+	ModelB     string  `json:"model_b"`
+	Difference float64 `json:"difference"`
+	Agree      bool    `json:"agree"`
+	Opposed    bool    `json:"opposed"`
+}
+
+// ModelBreakdownResponse explains an article's composite score by model
+type ModelBreakdownResponse struct {
+	ArticleID      int64                 `json:"article_id"`
+	CompositeScore *float64              `json:"composite_score,omitempty"`
+	Models         []ModelScoreBreakdown `json:"models"`
+	Pairs          []ModelAgreement      `json:"pairs"`
+	// AgreementRate is the share of model pairs that agree; absent with fewer than two models
+	AgreementRate *float64 `json:"agreement_rate,omitempty"`
+}
+
+// modelBreakdownHandler handles GET /api/articles/:id/model-breakdown
+// @Summary Get per-model score breakdown
+// @Description Returns the latest score, confidence and label recorded for each model that scored the article,
+// @Description with pairwise agreement flags. Models are reported as recorded, even if no longer configured.
+// @Tags Analysis
+// @Produce json
+// @Param id path integer true "Article ID"
+// @Success 200 {object} StandardResponse{data=ModelBreakdownResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/articles/{id}/model-breakdown [get]
+// @ID getArticleModelBreakdown
+func modelBreakdownHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+
+		article, err := db.FetchArticleByIDContext(c.Request.Context(), dbConn, id)
+		if err != nil {
+			if errors.Is(err, db.ErrArticleNotFound) {
+				RespondError(c, ErrArticleNotFound)
+				return
+			}
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch article"))
+			return
+		}
+
+		scores, err := db.FetchLLMScoresContext(c.Request.Context(), dbConn, id)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch scores"))
+			return
+		}
+
+		resp := buildModelBreakdown(scores)
+		resp.ArticleID = id
+		resp.CompositeScore = article.CompositeScore
+		RespondSuccess(c, resp)
+	}
+}
+
+// buildModelBreakdown keeps the latest score of each model, ignoring ensemble and
+// manual scores, and compares every pair of models. Databases created before the
+// unique (article_id, model) index may hold several scores per model.
+func buildModelBreakdown(scores []db.LLMScore) ModelBreakdownResponse {
+	latest := make(map[string]db.LLMScore)
+	for _, s := range scores {
+		if strings.EqualFold(s.Model, ModelEnsemble) || s.Model == db.ManualScoreModel {
+			continue
+		}
+		if prev, ok := latest[s.Model]; !ok || s.CreatedAt.After(prev.CreatedAt) {
+			latest[s.Model] = s
+		}
+	}
+
+	resp := ModelBreakdownResponse{Models: []ModelScoreBreakdown{}, Pairs: []ModelAgreement{}}
+	for _, s := range latest {
+		var meta struct {
+			Confidence  *float64 `json:"confidence"`
+			Perspective string   `json:"perspective"`
+		}
+		if s.Metadata != "" {
+			_ = json.Unmarshal([]byte(s.Metadata), &meta) // metadata is optional
+		}
+		resp.Models = append(resp.Models, ModelScoreBreakdown{
+			Model:       s.Model,
+			Perspective: meta.Perspective,
+			Score:       s.Score,
+			Confidence:  meta.Confidence,
+			Label:       models.BiasLabel(s.Score),
+			CreatedAt:   s.CreatedAt,
+		})
+	}
+	sort.Slice(resp.Models, func(i, j int) bool { return resp.Models[i].Model < resp.Models[j].Model })
+
+	agreeing := 0
+	for i := 0; i < len(resp.Models); i++ {
+		for j := i + 1; j < len(resp.Models); j++ {
+			a, b := resp.Models[i], resp.Models[j]
+			diff := math.Abs(a.Score - b.Score)
+			pair := ModelAgreement{
+				ModelA:     a.Model,
+				ModelB:     b.Model,
+				Difference: diff,
+				Agree:      diff <= modelAgreementMaxDiff,
+				Opposed:    a.Label != b.Label && a.Label != models.BiasLabelCenter && b.Label != models.BiasLabelCenter,
+			}
+			if pair.Agree {
+				agreeing++
+			}
+			resp.Pairs = append(resp.Pairs, pair)
+		}
+	}
+	if len(resp.Pairs) > 0 {
+		rate := float64(agreeing) / float64(len(resp.Pairs))
+		resp.AgreementRate = &rate
+	}
+	return resp
+}
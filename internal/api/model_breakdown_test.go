@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelBreakdownHandler(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "breakdown.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score, confidence)
+		VALUES ('bbc', CURRENT_TIMESTAMP, 'https://example.com/b', 't', 'c', 0.1, 0.8)`)
+	require.NoError(t, err)
+	id, err := res.LastInsertId()
+	require.NoError(t, err)
+	for _, s := range []struct {
+		model, meta string
+		score       float64
+		age         string
+	}{
+		{"left-model", `{"confidence": 0.7, "perspective": "left"}`, -0.2, "0 seconds"},
+		{"center-model", `{"confidence": 0.8}`, 0.0, "0 seconds"},
+		{"retired-model", `not json`, 0.6, "-30 days"}, // from an older model set
+		{"ensemble", `{}`, 0.1, "0 seconds"},
+		{db.ManualScoreModel, `{}`, 1.0, "0 seconds"},
+	} {
+		_, err := dbConn.Exec(`INSERT INTO llm_scores (article_id, model, score, metadata, created_at) VALUES (?, ?, ?, ?, datetime('now', ?))`,
+			id, s.model, s.score, s.meta, s.age)
+		require.NoError(t, err)
+	}
+
+	router := gin.New()
+	router.GET("/api/articles/:id/model-breakdown", modelBreakdownHandler(dbConn))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/articles/%d/model-breakdown", id), nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data ModelBreakdownResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	got := resp.Data
+	assert.InDelta(t, 0.1, *got.CompositeScore, 1e-9)
+
+	require.Len(t, got.Models, 3)
+	assert.Equal(t, "center-model", got.Models[0].Model)
+	assert.Equal(t, "center", got.Models[0].Label)
+	assert.Equal(t, "left-model", got.Models[1].Model)
+	assert.Equal(t, -0.2, got.Models[1].Score)
+	assert.Equal(t, "left", got.Models[1].Label)
+	assert.Equal(t, "left", got.Models[1].Perspective)
+	assert.InDelta(t, 0.7, *got.Models[1].Confidence, 1e-9)
+	assert.Equal(t, "retired-model", got.Models[2].Model)
+	assert.Nil(t, got.Models[2].Confidence)
+
+	require.Len(t, got.Pairs, 3)
+	pairs := make(map[string]ModelAgreement)
+	for _, p := range got.Pairs {
+		pairs[p.ModelA+"|"+p.ModelB] = p
+	}
+	assert.True(t, pairs["center-model|left-model"].Agree)
+	assert.False(t, pairs["center-model|left-model"].Opposed)
+	assert.False(t, pairs["left-model|retired-model"].Agree)
+	assert.True(t, pairs["left-model|retired-model"].Opposed)
+	assert.InDelta(t, 0.8, pairs["left-model|retired-model"].Difference, 1e-9)
+	assert.InDelta(t, 1.0/3.0, *got.AgreementRate, 1e-9)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles/9999/model-breakdown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
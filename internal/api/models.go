@@ -2,6 +2,8 @@ package api
 
 import (
 	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
 )
 
 // Article represents a news article with bias analysis
@@ -22,11 +24,13 @@ type Article struct {
 // CreateArticleRequest represents the request payload for creating an article
 // @Description Request body for creating a new article
 type CreateArticleRequest struct {
-	Source  string `json:"source" example:"CNN" binding:"required"`                      // News source name
-	PubDate string `json:"pub_date" example:"2023-01-01T12:00:00Z" binding:"required"`   // Publication date in RFC3339 format
-	URL     string `json:"url" example:"https://example.com/article" binding:"required"` // Article URL
-	Title   string `json:"title" example:"Breaking News" binding:"required"`             // Article title
-	Content string `json:"content" example:"Article content..." binding:"required"`      // Article content
+	Source      string `json:"source" example:"CNN" binding:"required"`                      // News source name
+	PublishedAt string `json:"published_at" example:"2023-01-01T12:00:00Z"`                  // Publication date in RFC3339 format
+	PubDate     string `json:"pub_date,omitempty" example:"2023-01-01T12:00:00Z"`            // Deprecated alias of published_at
+	URL         string `json:"url" example:"https://example.com/article" binding:"required"` // Article URL
+	Title       string `json:"title" example:"Breaking News" binding:"required"`             // Article title
+	Content     string `json:"content" example:"Article content..." binding:"required"`      // Article content
+	Score       *bool  `json:"score,omitempty" example:"true"`                               // Score right away (true), never (false), or per the ingestion policy (omitted)
 }
 
 // CreateArticleResponse represents the response for creating an article
@@ -36,12 +40,41 @@ type CreateArticleResponse struct {
 	ArticleID int64  `json:"article_id" example:"42"`  // ID of the created article
 }
 
+// CreatedArticleResponse is the created article, plus the scoring job when scoring was requested
+// @Description Created article and optional scoring job
+type CreatedArticleResponse struct {
+	ArticleResponse
+	// Scoring is "immediate" when scoring was queued, "in_progress" when the article was
+	// already being scored, "deferred" when the article waits for the next ingestion
+	// batch, and empty when it is left unscored
+	Scoring string `json:"scoring,omitempty" example:"immediate"`
+	// JobID is set when scoring was queued or already in progress; follow it on /api/llm/score-progress/{job_id}.
+	// Scoring progress is tracked per article, so it equals the article ID.
+	JobID int64 `json:"job_id,omitempty" example:"42"`
+}
+
+// ProgressSnapshot is the current scoring progress of an article, returned when
+// /api/llm/score-progress/{id} is polled instead of streamed
+// @Description Scoring progress snapshot
+type ProgressSnapshot struct {
+	models.ProgressState
+	EventID int64 `json:"event_id" example:"12"` // Increases with every progress update
+}
+
 // ScoreResponse represents the bias analysis result
 // @Description Political bias score analysis result
 type ScoreResponse struct {
 	CompositeScore *float64                `json:"composite_score,omitempty" example:"0.25"`       // Overall bias score
 	Results        []IndividualScoreResult `json:"results"`                                        // Individual model scores
 	Status         string                  `json:"status,omitempty" example:"scoring_unavailable"` // Status message if applicable
+	// 95% interval for the composite score from the spread of per-model scores;
+	// interval_reliable is false when fewer than two models contributed
+	ScoreLow         *float64 `json:"score_low,omitempty" example:"0.1"`
+	ScoreHigh        *float64 `json:"score_high,omitempty" example:"0.4"`
+	IntervalModels   int      `json:"interval_models,omitempty" example:"3"`
+	IntervalReliable bool     `json:"interval_reliable,omitempty" example:"true"`
+	// Scores inside the neutral zone are labelled center
+	NeutralZone models.BiasNeutralZone `json:"neutral_zone"`
 }
 
 // IndividualScoreResult represents an individual model's bias score
@@ -75,8 +108,9 @@ type ErrorResponse struct {
 // ErrorDetail contains details about an error
 // @Description Detailed error information
 type ErrorDetail struct {
-	Code    string `json:"code" example:"validation_error"`            // Error code
-	Message string `json:"message" example:"Invalid input parameters"` // Human-readable error message
+	Code    string      `json:"code" example:"validation_error"`            // Stable error code
+	Message string      `json:"message" example:"Invalid input parameters"` // Human-readable error message
+	Details interface{} `json:"details,omitempty"`                          // Optional structured context
 }
 
 // StandardResponse represents a standard API success response
@@ -86,22 +120,56 @@ type StandardResponse struct {
 	Data    interface{} `json:"data"`                   // Response data payload
 }
 
+// Pagination describes the page returned by a list endpoint
+// @Description Pagination metadata for list responses
+type Pagination struct {
+	Total   int  `json:"total" xml:"total,attr" example:"135"`        // Items matching the filters
+	Limit   int  `json:"limit" xml:"limit,attr" example:"20"`         // Page size requested
+	Offset  int  `json:"offset" xml:"offset,attr" example:"40"`       // Offset of the first item
+	HasMore bool `json:"has_more" xml:"has_more,attr" example:"true"` // Whether items exist past this page
+}
+
+// NewPagination computes the pagination metadata for a page of returned items
+func NewPagination(total, limit, offset, returned int) Pagination {
+	return Pagination{Total: total, Limit: limit, Offset: offset, HasMore: offset+returned < total}
+}
+
+// PaginatedResponse is a success response carrying a page of a list
+// @Description API success response for a page of a list
+type PaginatedResponse struct {
+	Success    bool        `json:"success" example:"true"` // Always true for success
+	Data       interface{} `json:"data"`                   // Items on this page
+	Pagination Pagination  `json:"pagination"`             // Pagination metadata
+}
+
 // ManualScoreRequest represents a request to manually set an article score
 // @Description Request body for manually setting an article's bias score
 type ManualScoreRequest struct {
 	Score float64 `json:"score" example:"0.5" binding:"required"` // Score value between -1.0 and 1.0
 }
 
+// ManualScoreOverrideRequest represents a request to pin an article's composite score
+// @Description Request body for pinning an article's composite score to an editor-provided value
+type ManualScoreOverrideRequest struct {
+	Score  *float64 `json:"score" example:"-0.2"`                      // Score value between -1.0 and 1.0
+	Reason string   `json:"reason" example:"Model misread the satire"` // Why the model score is being overridden
+}
+
 // ArticleResponse represents the JSON returned for an article.
 // @Name    ArticleResponse
 type ArticleResponse struct {
-	ArticleID   int64   `json:"article_id"`
-	Source      string  `json:"source"`
-	URL         string  `json:"url"`
-	Title       string  `json:"title"`
-	Content     string  `json:"content"`
-	PublishedAt string  `json:"published_at"`
-	Composite   float64 `json:"composite_score"`
-	Confidence  float64 `json:"confidence"`
-	ScoreSource string  `json:"score_source"`
+	ArticleID   int64   `json:"article_id" xml:"article_id"`
+	Source      string  `json:"source" xml:"source"`
+	URL         string  `json:"url" xml:"url"`
+	Title       string  `json:"title" xml:"title"`
+	Content     string  `json:"content" xml:"content"`
+	PublishedAt string  `json:"published_at" xml:"published_at"`
+	Composite   float64 `json:"composite_score" xml:"composite_score"`
+	Confidence  float64 `json:"confidence" xml:"confidence"`
+	ScoreSource string  `json:"score_source" xml:"score_source"`
+	// ImageURL is the feed's image for the article, if it had one
+	ImageURL string `json:"image_url,omitempty" xml:"image_url,omitempty"`
+	// Authors and Categories come from the feed and are omitted when it gave none
+	Authors    []string `json:"authors,omitempty" xml:"authors>author,omitempty"`
+	Categories []string `json:"categories,omitempty" xml:"categories>category,omitempty"`
 }
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticlesPaginationEnvelope(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "pagination.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+			VALUES ('bbc', CURRENT_TIMESTAMP, ?, ?, 'Body')`, fmt.Sprintf("https://example.com/page/%d", i), fmt.Sprintf("Article %d", i))
+		require.NoError(t, err)
+	}
+	_, err = dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('cnn', CURRENT_TIMESTAMP, 'https://example.com/other', 'Other', 'Body')`)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/api/articles", getArticlesHandler(dbConn))
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("bare list by default", func(t *testing.T) {
+		w := get("/api/articles?source=bbc&limit=2")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "5", w.Header().Get("X-Total-Count"))
+		var resp map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.NotContains(t, resp, "pagination")
+	})
+
+	t.Run("envelope", func(t *testing.T) {
+		for _, tc := range []struct {
+			offset  int
+			count   int
+			hasMore bool
+		}{{0, 2, true}, {2, 2, true}, {4, 1, false}} {
+			w := get(fmt.Sprintf("/api/articles?source=bbc&limit=2&offset=%d&envelope=true", tc.offset))
+			require.Equal(t, http.StatusOK, w.Code)
+			var resp struct {
+				Success    bool              `json:"success"`
+				Data       []ArticleResponse `json:"data"`
+				Pagination Pagination        `json:"pagination"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.True(t, resp.Success)
+			assert.Len(t, resp.Data, tc.count)
+			assert.Equal(t, Pagination{Total: 5, Limit: 2, Offset: tc.offset, HasMore: tc.hasMore}, resp.Pagination)
+		}
+	})
+
+	t.Run("default from env", func(t *testing.T) {
+		t.Setenv("ARTICLES_ENVELOPE_DEFAULT", "true")
+		var resp map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(get("/api/articles").Body.Bytes(), &resp))
+		assert.JSONEq(t, `{"total":6,"limit":20,"offset":0,"has_more":false}`, string(resp["pagination"]))
+
+		var bare map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(get("/api/articles?envelope=false").Body.Bytes(), &bare))
+		assert.NotContains(t, bare, "pagination", "clients can still opt out")
+	})
+
+	t.Run("invalid flag", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("/api/articles?envelope=maybe").Code)
+	})
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rate limit defaults, overridable with RATE_LIMIT_RPS and RATE_LIMIT_BURST.
+// Setting RATE_LIMIT_RPS to 0 disables rate limiting.
+const (
+	defaultRateLimitRPS     = 10.0
+	defaultRateLimitBurst   = 20
+	rateLimitCleanupEvery   = time.Minute
+	rateLimitBucketIdleTime = 10 * time.Minute
+)
+
+// tokenBucket holds the token count for a single client
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// RateLimiter is an in-memory per-client token bucket limiter. Idle buckets are
+// removed as requests arrive, so the limiter runs no goroutine and needs no stopping.
+type RateLimiter struct {
+	rate            float64
+	burst           float64
+	buckets         map[string]*tokenBucket
+	mu              sync.Mutex
+	cleanupInterval time.Duration
+	idleTTL         time.Duration
+	lastCleanup     time.Time
+	now             func() time.Time
+}
+
+// NewRateLimiter creates a limiter allowing ratePerSec requests per second per client
+// with bursts of up to burst requests, removing idle buckets every cleanupInterval
+func NewRateLimiter(ratePerSec float64, burst int, cleanupInterval time.Duration) *RateLimiter {
+	return &RateLimiter{
+		rate:            ratePerSec,
+		burst:           float64(burst),
+		buckets:         make(map[string]*tokenBucket),
+		cleanupInterval: cleanupInterval,
+		idleTTL:         rateLimitBucketIdleTime,
+		lastCleanup:     time.Now(),
+		now:             time.Now,
+	}
+}
+
+// newRateLimiterFromEnv builds the public API rate limiter, returning nil when disabled
+func newRateLimiterFromEnv() *RateLimiter {
+	rps := defaultRateLimitRPS
+	if v := os.Getenv("RATE_LIMIT_RPS"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 {
+			log.Printf("[WARN] Invalid RATE_LIMIT_RPS %q, using default %.1f", v, defaultRateLimitRPS)
+		} else {
+			rps = parsed
+		}
+	}
+	if rps == 0 {
+		return nil
+	}
+
+	burst := defaultRateLimitBurst
+	if v := os.Getenv("RATE_LIMIT_BURST"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			log.Printf("[WARN] Invalid RATE_LIMIT_BURST %q, using default %d", v, defaultRateLimitBurst)
+		} else {
+			burst = parsed
+		}
+	}
+
+	return NewRateLimiter(rps, burst, rateLimitCleanupEvery)
+}
+
+// Allow consumes a token for key. When no token is available it returns false
+// and the time until the next token becomes available.
+func (rl *RateLimiter) Allow(key string) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	if now.Sub(rl.lastCleanup) >= rl.cleanupInterval {
+		rl.cleanupLocked(now)
+	}
+	b, exists := rl.buckets[key]
+	if !exists {
+		b = &tokenBucket{tokens: rl.burst, lastSeen: now}
+		rl.buckets[key] = b
+	} else {
+		elapsed := now.Sub(b.lastSeen).Seconds()
+		b.tokens = math.Min(rl.burst, b.tokens+elapsed*rl.rate)
+		b.lastSeen = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / rl.rate * float64(time.Second))
+	return false, wait
+}
+
+// Middleware returns a gin middleware enforcing the limit per client IP.
+// A nil limiter lets every request through.
+func (rl *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rl == nil {
+			c.Next()
+			return
+		}
+		allowed, wait := rl.Allow(c.ClientIP())
+		if !allowed {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			RespondError(c, ErrRateLimited)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// cleanup removes buckets that have been idle longer than idleTTL
+func (rl *RateLimiter) cleanup() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.cleanupLocked(rl.now())
+}
+
+// cleanupLocked is cleanup with rl.mu held
+func (rl *RateLimiter) cleanupLocked(now time.Time) {
+	rl.lastCleanup = now
+	for key, b := range rl.buckets {
+		if now.Sub(b.lastSeen) > rl.idleTTL {
+			delete(rl.buckets, key)
+		}
+	}
+}
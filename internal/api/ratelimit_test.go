@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	rl := NewRateLimiter(1, 2, time.Hour)
+
+	now := time.Unix(1_700_000_000, 0)
+	rl.now = func() time.Time { return now }
+
+	ok, _ := rl.Allow("1.2.3.4")
+	assert.True(t, ok)
+	ok, _ = rl.Allow("1.2.3.4")
+	assert.True(t, ok)
+	ok, wait := rl.Allow("1.2.3.4")
+	assert.False(t, ok, "burst exhausted")
+	assert.Equal(t, time.Second, wait)
+
+	// Other clients have their own bucket
+	ok, _ = rl.Allow("5.6.7.8")
+	assert.True(t, ok)
+
+	// Tokens refill over time
+	now = now.Add(1500 * time.Millisecond)
+	ok, _ = rl.Allow("1.2.3.4")
+	assert.True(t, ok)
+}
+
+func TestRateLimiterCleanup(t *testing.T) {
+	rl := NewRateLimiter(1, 1, time.Hour)
+
+	now := time.Unix(1_700_000_000, 0)
+	rl.now = func() time.Time { return now }
+
+	rl.Allow("idle")
+	now = now.Add(rateLimitBucketIdleTime / 2)
+	rl.Allow("active")
+	now = now.Add(rateLimitBucketIdleTime/2 + time.Second)
+	rl.cleanup()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	assert.NotContains(t, rl.buckets, "idle")
+	assert.Contains(t, rl.buckets, "active")
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rl := NewRateLimiter(0.5, 1, time.Hour)
+
+	router := gin.New()
+	router.GET("/api/articles", rl.Middleware(), func(c *gin.Context) {
+		RespondSuccess(c, []string{})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+}
+
+func TestNilRateLimiterMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var rl *RateLimiter
+
+	router := gin.New()
+	router.GET("/api/articles", rl.Middleware(), func(c *gin.Context) {
+		RespondSuccess(c, []string{})
+	})
+
+	for i := 0; i < 50; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestRateLimiterCleansUpWhileServing(t *testing.T) {
+	rl := NewRateLimiter(1, 1, time.Minute)
+	now := time.Unix(1_700_000_000, 0)
+	rl.now = func() time.Time { return now }
+	rl.lastCleanup = now
+
+	rl.Allow("idle")
+	now = now.Add(rateLimitBucketIdleTime + time.Minute)
+	rl.Allow("active")
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	assert.NotContains(t, rl.buckets, "idle", "a request after the cleanup interval removes idle buckets")
+	assert.Contains(t, rl.buckets, "active")
+}
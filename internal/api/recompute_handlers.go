@@ -0,0 +1,283 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/apperrors"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxRecomputeBatch caps how many articles one batch recompute request may name
+const maxRecomputeBatch = 500
+
+// Sample sizes for config previews
+const (
+	defaultConfigPreviewSample = 100
+	maxConfigPreviewSample     = 1000
+)
+
+// RecomputeResponse reports a composite recomputed from stored per-model scores
+type RecomputeResponse struct {
+	ArticleID     int64    `json:"article_id" example:"42"`
+	PreviousScore *float64 `json:"previous_score,omitempty" example:"0.12"`
+	Score         float64  `json:"score" example:"0.18"`
+	Confidence    float64  `json:"confidence" example:"0.8"`
+	Version       int      `json:"version" example:"2"`
+	Models        int      `json:"models" example:"3"`
+	// Debug is the intermediate aggregation math, only with include_debug=true
+	Debug *llm.AggregationTrace `json:"debug,omitempty"`
+}
+
+// RecomputeBatchRequest names the articles to recompute
+type RecomputeBatchRequest struct {
+	ArticleIDs []int64 `json:"article_ids" binding:"required"`
+}
+
+// RecomputeBatchItem is the outcome for one article of a batch recompute
+type RecomputeBatchItem struct {
+	ArticleID int64              `json:"article_id"`
+	Result    *RecomputeResponse `json:"result,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// RecomputeBatchResponse summarises a batch recompute
+type RecomputeBatchResponse struct {
+	Recomputed int                  `json:"recomputed"`
+	Failed     int                  `json:"failed"`
+	Results    []RecomputeBatchItem `json:"results"`
+}
+
+// recomputeHandler handles POST /api/articles/:id/recompute
+// @Summary Recompute composite score
+// @Description Recomputes the composite from the stored per-model scores and the current
+// @Description aggregation config without calling any LLM. The ensemble score is stored as a
+// @Description new version marked as a recompute.
+// @Tags Scoring
+// @Produce json
+// @Param id path integer true "Article ID"
+// @Param include_debug query boolean false "Add the intermediate aggregation math as debug"
+// @Success 200 {object} StandardResponse{data=RecomputeResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/articles/{id}/recompute [post]
+// @ID recomputeArticleScore
+func recomputeHandler(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *llm.ScoreManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		articleID, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+		cfg, ok := recomputeConfig(c, llmClient, scoreManager)
+		if !ok {
+			return
+		}
+
+		if _, err := db.FetchArticleByIDContext(c.Request.Context(), dbConn, articleID); err != nil {
+			if errors.Is(err, db.ErrArticleNotFound) {
+				RespondError(c, ErrArticleNotFound)
+				return
+			}
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch article"))
+			return
+		}
+
+		result, err := scoreManager.RecomputeArticleScoreContext(c.Request.Context(), articleID, cfg)
+		if err != nil {
+			RespondError(c, recomputeError(err))
+			return
+		}
+		resp := newRecomputeResponse(result)
+		if wantsAggregationDebug(c) {
+			resp.Debug = recomputeTrace(dbConn, articleID, cfg)
+		}
+		RespondSuccess(c, resp)
+	}
+}
+
+// ConfigPreviewRequest is a proposed config and how many recent articles to try it on
+type ConfigPreviewRequest struct {
+	Config     llm.CompositeScoreConfig `json:"config"`
+	SampleSize int                      `json:"sample_size,omitempty" example:"100"`
+}
+
+// ConfigPreviewResponse is the effect a proposed config would have on recent composites
+type ConfigPreviewResponse struct {
+	SampleSize int `json:"sample_size"`
+	llm.ConfigDiff
+}
+
+// recomputeBatchHandler handles POST /api/admin/recompute
+// @Summary Recompute composite scores in bulk
+// @Description Recomputes the composites of up to 500 articles from their stored per-model scores
+// @Description without calling any LLM. Failures are reported per article.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body RecomputeBatchRequest true "Articles to recompute"
+// @Param include_debug query boolean false "Add the intermediate aggregation math to each result as debug"
+// @Success 200 {object} StandardResponse{data=RecomputeBatchResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 503 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/admin/recompute [post]
+// @ID recomputeArticleScores
+func recomputeBatchHandler(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *llm.ScoreManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RecomputeBatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondError(c, NewAppError(ErrValidation, "Invalid request body: 'article_ids' is required"))
+			return
+		}
+		if len(req.ArticleIDs) == 0 || len(req.ArticleIDs) > maxRecomputeBatch {
+			RespondError(c, NewAppError(ErrValidation, fmt.Sprintf("'article_ids' must name between 1 and %d articles", maxRecomputeBatch)))
+			return
+		}
+		cfg, ok := recomputeConfig(c, llmClient, scoreManager)
+		if !ok {
+			return
+		}
+
+		debug := wantsAggregationDebug(c)
+		resp := RecomputeBatchResponse{Results: make([]RecomputeBatchItem, 0, len(req.ArticleIDs))}
+		for _, id := range req.ArticleIDs {
+			item := RecomputeBatchItem{ArticleID: id}
+			if result, err := scoreManager.RecomputeArticleScoreContext(c.Request.Context(), id, cfg); err != nil {
+				item.Error = err.Error()
+				resp.Failed++
+			} else {
+				recomputed := newRecomputeResponse(result)
+				if debug {
+					recomputed.Debug = recomputeTrace(dbConn, id, cfg)
+				}
+				item.Result = &recomputed
+				resp.Recomputed++
+			}
+			resp.Results = append(resp.Results, item)
+		}
+		RespondSuccess(c, resp)
+	}
+}
+
+// recomputeConfig returns the current aggregation config, responding with an error
+// when scoring is unavailable
+func recomputeConfig(c *gin.Context, llmClient *llm.LLMClient, scoreManager *llm.ScoreManager) (*llm.CompositeScoreConfig, bool) {
+	if scoreManager == nil {
+		RespondError(c, NewAppError(ErrLLMService, "Scoring is not available"))
+		return nil, false
+	}
+	var cfg *llm.CompositeScoreConfig
+	if llmClient != nil {
+		cfg = llmClient.GetConfig()
+	}
+	if cfg == nil {
+		var err error
+		if cfg, err = llm.LoadCompositeScoreConfig(); err != nil {
+			RespondError(c, WrapError(err, ErrLLMService, "Failed to load LLM configuration"))
+			return nil, false
+		}
+	}
+	return cfg, true
+}
+
+// recomputeError maps a RecomputeArticleScore failure to an API error
+func recomputeError(err error) *apperrors.AppError {
+	switch {
+	case errors.Is(err, llm.ErrNoModelScores):
+		return NewAppError(ErrValidation, "Article has no per-model scores to recompute from")
+	default:
+		if apiErr := domainError(err); apiErr != nil {
+			return apiErr
+		}
+		return WrapError(err, ErrInternal, "Failed to recompute score")
+	}
+}
+
+// recomputeTrace traces the composite just recomputed from the article's stored scores.
+// The trace is left out when the scores cannot be read.
+func recomputeTrace(dbConn *sqlx.DB, articleID int64, cfg *llm.CompositeScoreConfig) *llm.AggregationTrace {
+	scores, err := db.FetchLLMScores(dbConn, articleID)
+	if err != nil {
+		log.Printf("[WARN] Failed to fetch scores to trace article %d: %v", articleID, err)
+		return nil
+	}
+	return llm.TraceArticleComposite(dbConn, articleID, scores, cfg)
+}
+
+func newRecomputeResponse(r *llm.RecomputeResult) RecomputeResponse {
+	return RecomputeResponse{
+		ArticleID:     r.ArticleID,
+		PreviousScore: r.PreviousScore,
+		Score:         r.Score,
+		Confidence:    r.Confidence,
+		Version:       r.Version,
+		Models:        r.Models,
+	}
+}
+
+// configPreviewHandler handles POST /api/admin/config/preview
+// @Summary Preview a proposed composite score config
+// @Description Recomputes the composites of the most recent scored articles from their stored
+// @Description per-model scores under both the current and the proposed config, and reports how
+// @Description many change, the mean and largest absolute change and which bias labels flip.
+// @Description No LLM is called and nothing is stored. sample_size defaults to 100, at most 1000.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body ConfigPreviewRequest true "Proposed config and sample size"
+// @Success 200 {object} StandardResponse{data=ConfigPreviewResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/admin/config/preview [post]
+// @ID previewCompositeScoreConfig
+func configPreviewHandler(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *llm.ScoreManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ConfigPreviewRequest
+		decoder := json.NewDecoder(c.Request.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			if strings.Contains(err.Error(), "unknown field") {
+				RespondError(c, NewAppError(ErrValidation, "Request contains unknown fields: "+err.Error()))
+				return
+			}
+			RespondError(c, ErrInvalidPayload)
+			return
+		}
+		if req.SampleSize == 0 {
+			req.SampleSize = defaultConfigPreviewSample
+		}
+		if req.SampleSize < 1 || req.SampleSize > maxConfigPreviewSample {
+			RespondError(c, NewAppError(ErrValidation, fmt.Sprintf("'sample_size' must be between 1 and %d", maxConfigPreviewSample)))
+			return
+		}
+		if err := req.Config.Validate(); err != nil {
+			RespondError(c, NewAppError(ErrValidation, "Invalid config: "+err.Error()))
+			return
+		}
+		current, ok := recomputeConfig(c, llmClient, scoreManager)
+		if !ok {
+			return
+		}
+
+		ids, err := db.FetchRecentModelScoredArticleIDs(dbConn, req.SampleSize)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch scored articles"))
+			return
+		}
+		diff := scoreManager.CompareConfigs(ids, current, &req.Config)
+		RespondSuccess(c, ConfigPreviewResponse{SampleSize: req.SampleSize, ConfigDiff: *diff})
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecomputeHandlers(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "recompute.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	cfg := &llm.CompositeScoreConfig{
+		Formula:  "average",
+		MinScore: -1,
+		MaxScore: 1,
+		Models:   []llm.ModelConfig{{ModelName: "left-model", Perspective: "left"}, {ModelName: "right-model", Perspective: "right"}},
+	}
+	svc := llm.NewFixtureLLMService(llm.Fixture{})
+	client := llm.NewLLMClientWithService(dbConn, svc, cfg)
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	scoreManager := llm.NewScoreManager(dbConn, llm.NewCache(), &llm.DefaultScoreCalculator{}, pm)
+
+	insertArticle := func(url string) int64 {
+		res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+			VALUES ('src', CURRENT_TIMESTAMP, ?, 'title', 'content')`, url)
+		require.NoError(t, err)
+		id, err := res.LastInsertId()
+		require.NoError(t, err)
+		return id
+	}
+	scored := insertArticle("https://example.com/recompute-1")
+	unscored := insertArticle("https://example.com/recompute-2")
+	for model, score := range map[string]float64{"left-model": -0.4, "right-model": 0.2} {
+		_, err := db.InsertLLMScore(dbConn, &db.LLMScore{
+			ArticleID: scored, Model: model, Score: score, Metadata: `{"confidence": 0.8}`, Version: 1, CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	router := gin.New()
+	router.POST("/api/articles/:id/recompute", recomputeHandler(client, dbConn, scoreManager))
+	router.POST("/api/admin/recompute", recomputeBatchHandler(client, dbConn, scoreManager))
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(fmt.Sprintf("/api/articles/%d/recompute", scored), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var single struct {
+		Data RecomputeResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &single))
+	assert.InDelta(t, -0.1, single.Data.Score, 1e-6)
+	assert.Equal(t, 1, single.Data.Version)
+	assert.Equal(t, 0, svc.Calls(), "recompute never calls the LLM")
+	assert.Nil(t, single.Data.Debug, "debug output is opt-in")
+
+	w = post(fmt.Sprintf("/api/articles/%d/recompute", unscored), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = post("/api/articles/999999/recompute", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = post("/api/admin/recompute", fmt.Sprintf(`{"article_ids":[%d,%d]}`, scored, unscored))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var batch struct {
+		Data RecomputeBatchResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &batch))
+	assert.Equal(t, 1, batch.Data.Recomputed)
+	assert.Equal(t, 1, batch.Data.Failed)
+	require.Len(t, batch.Data.Results, 2)
+	require.NotNil(t, batch.Data.Results[0].Result)
+	assert.Equal(t, 2, batch.Data.Results[0].Result.Version)
+	assert.NotEmpty(t, batch.Data.Results[1].Error)
+
+	w = post("/api/admin/recompute", `{"article_ids":[]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = post(fmt.Sprintf("/api/articles/%d/recompute?include_debug=true", scored), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &single))
+	trace := single.Data.Debug
+	require.NotNil(t, trace)
+	require.Len(t, trace.Models, 2)
+	assert.Equal(t, llm.CalculatorDefault, trace.Calculator, "the calculator that recomputed the score is traced")
+	assert.InDelta(t, 2.0, trace.WeightTotal, 1e-9)
+	assert.InDelta(t, -0.2, trace.WeightedSum, 1e-9)
+	assert.InDelta(t, -0.1, trace.PreClampScore, 1e-9)
+	assert.InDelta(t, single.Data.Score, trace.Score, 1e-9)
+	assert.InDelta(t, single.Data.Confidence, trace.Confidence, 1e-9)
+
+	w = post("/api/admin/recompute?include_debug=true", fmt.Sprintf(`{"article_ids":[%d]}`, scored))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &batch))
+	require.NotNil(t, batch.Data.Results[0].Result)
+	assert.NotNil(t, batch.Data.Results[0].Result.Debug)
+}
+
+func TestConfigPreviewHandler(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "preview.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	cfg := &llm.CompositeScoreConfig{
+		Formula:  "average",
+		MinScore: -1,
+		MaxScore: 1,
+		Models:   []llm.ModelConfig{{ModelName: "model-a", Perspective: "left"}, {ModelName: "model-b", Perspective: "right"}},
+	}
+	svc := llm.NewFixtureLLMService(llm.Fixture{})
+	client := llm.NewLLMClientWithService(dbConn, svc, cfg)
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	scoreManager := llm.NewScoreManager(dbConn, llm.NewCache(), &llm.DefaultScoreCalculator{}, pm)
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('src', CURRENT_TIMESTAMP, 'https://example.com/preview', 'title', 'content')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	for model, score := range map[string]float64{"model-a": -0.4, "model-b": 0.2} {
+		_, err := db.InsertLLMScore(dbConn, &db.LLMScore{
+			ArticleID: articleID, Model: model, Score: score, Metadata: `{"confidence": 0.8}`, Version: 1, CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	router := gin.New()
+	router.POST("/api/admin/config/preview", configPreviewHandler(client, dbConn, scoreManager))
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/config/preview", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Dropping model-b moves the composite from -0.1 (center) to -0.4 (left)
+	w := post(`{"config": {"formula": "average", "min_score": -1, "max_score": 1,
+		"models": [{"modelName": "model-a", "perspective": "left", "weight": 1}]}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data ConfigPreviewResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, defaultConfigPreviewSample, resp.Data.SampleSize)
+	assert.Equal(t, 1, resp.Data.Articles)
+	assert.Equal(t, 1, resp.Data.Changed)
+	assert.InDelta(t, 0.3, resp.Data.MeanAbsChange, 1e-6)
+	assert.Equal(t, 1, resp.Data.LabelFlips)
+	require.Len(t, resp.Data.Flips, 1)
+	assert.Equal(t, "center", resp.Data.Flips[0].CurrentLabel)
+	assert.Equal(t, "left", resp.Data.Flips[0].ProposedLabel)
+	assert.Equal(t, 0, svc.Calls(), "previews never call the LLM")
+
+	var versions int
+	require.NoError(t, dbConn.Get(&versions, `SELECT COUNT(*) FROM llm_scores`))
+	assert.Equal(t, 2, versions, "previews store nothing")
+
+	for _, body := range []string{
+		`{"config": {"models": []}}`,
+		`{"config": {"models": [{"modelName": "m", "perspective": "left", "weight": 1}]}, "sample_size": 5000}`,
+		`{"config": {"models": [{"modelName": "m", "perspective": "left", "weight": 1}]}, "extra": true}`,
+	} {
+		assert.Equal(t, http.StatusBadRequest, post(body).Code, body)
+	}
+}
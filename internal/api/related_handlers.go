@@ -0,0 +1,218 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/similarity"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// Related articles settings. RELATED_ARTICLES_LIMIT and RELATED_ARTICLES_METHOD
+// override the defaults; the limit and method query parameters override both.
+const (
+	defaultRelatedLimit  = 5
+	maxRelatedLimit      = 20
+	defaultRelatedMethod = similarity.MethodTFIDF
+
+	// relatedCandidatePool is how many recent articles are compared against the target
+	relatedCandidatePool = 500
+	// relatedCacheTTL is how long related results are cached per article
+	relatedCacheTTL = 10 * time.Minute
+
+	// relatedSimilarBiasMax is the largest score difference for bias=similar
+	relatedSimilarBiasMax = 0.25
+	// relatedContrastingBiasMin is the smallest score difference for bias=contrasting
+	relatedContrastingBiasMin = 0.5
+)
+
+// Values of the bias query parameter
+const (
+	RelatedBiasAny         = "any"
+	RelatedBiasSimilar     = "similar"
+	RelatedBiasContrasting = "contrasting"
+)
+
+// RelatedArticle is an article similar in content to the requested one
+type RelatedArticle struct {
+	ID             int64     `json:"id"`
+	Title          string    `json:"title"`
+	Source         string    `json:"source"`
+	URL            string    `json:"url"`
+	PubDate        time.Time `json:"pub_date"`
+	CompositeScore *float64  `json:"composite_score,omitempty"`
+	Similarity     float64   `json:"similarity"`
+	// BiasDistance is the absolute difference in composite score, when both are scored
+	BiasDistance *float64 `json:"bias_distance,omitempty"`
+}
+
+// RelatedArticlesResponse lists the related articles and the settings used to find them
+type RelatedArticlesResponse struct {
+	ArticleID int64            `json:"article_id"`
+	Method    string           `json:"method"`
+	Bias      string           `json:"bias"`
+	Related   []RelatedArticle `json:"related"`
+}
+
+// relatedDefaults returns the configured default limit and method
+func relatedDefaults() (int, string) {
+	limit := defaultRelatedLimit
+	if v := os.Getenv("RELATED_ARTICLES_LIMIT"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxRelatedLimit {
+			log.Printf("[WARN] Invalid RELATED_ARTICLES_LIMIT %q, using default %d", v, defaultRelatedLimit)
+		} else {
+			limit = parsed
+		}
+	}
+	method := defaultRelatedMethod
+	if v := os.Getenv("RELATED_ARTICLES_METHOD"); v != "" {
+		if similarity.IsValidMethod(v) {
+			method = v
+		} else {
+			log.Printf("[WARN] Invalid RELATED_ARTICLES_METHOD %q, using default %s", v, defaultRelatedMethod)
+		}
+	}
+	return limit, method
+}
+
+// relatedArticlesHandler handles GET /api/articles/:id/related
+// @Summary Get related articles
+// @Description Returns recent articles with similar content, optionally restricted to similar or contrasting bias scores.
+// @Description Bias filtering only considers scored articles.
+// @Tags Articles
+// @Produce json
+// @Param id path integer true "Article ID"
+// @Param limit query integer false "Number of results (default: 5, max: 20)"
+// @Param method query string false "Similarity method: tfidf or bow (default: tfidf)"
+// @Param bias query string false "Bias filter: any, similar or contrasting (default: any)"
+// @Success 200 {object} StandardResponse{data=RelatedArticlesResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/articles/{id}/related [get]
+// @ID getRelatedArticles
+func relatedArticlesHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+
+		limit, method := relatedDefaults()
+		if v := c.Query("limit"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 1 || parsed > maxRelatedLimit {
+				RespondError(c, NewAppError(ErrValidation, fmt.Sprintf("Invalid 'limit' parameter, must be between 1 and %d", maxRelatedLimit)))
+				return
+			}
+			limit = parsed
+		}
+		if v := c.Query("method"); v != "" {
+			if !similarity.IsValidMethod(v) {
+				RespondError(c, NewAppError(ErrValidation, "Invalid 'method' parameter, must be 'tfidf' or 'bow'"))
+				return
+			}
+			method = v
+		}
+		bias := c.DefaultQuery("bias", RelatedBiasAny)
+		if bias != RelatedBiasAny && bias != RelatedBiasSimilar && bias != RelatedBiasContrasting {
+			RespondError(c, NewAppError(ErrValidation, "Invalid 'bias' parameter, must be 'any', 'similar' or 'contrasting'"))
+			return
+		}
+
+		cacheKey := fmt.Sprintf("related:%d:%s:%s:%d", id, method, bias, limit)
+		articlesCacheLock.RLock()
+		if cached, found := articlesCache.Get(cacheKey); found {
+			articlesCacheLock.RUnlock()
+			RespondSuccess(c, json.RawMessage(cached))
+			return
+		}
+		articlesCacheLock.RUnlock()
+
+		article, err := db.FetchArticleByIDContext(c.Request.Context(), dbConn, id)
+		if err != nil {
+			if errors.Is(err, db.ErrArticleNotFound) {
+				RespondError(c, ErrArticleNotFound)
+				return
+			}
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch article"))
+			return
+		}
+
+		candidates, err := db.FetchArticles(dbConn, "", "", relatedCandidatePool, 0)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch candidate articles"))
+			return
+		}
+
+		resp := RelatedArticlesResponse{
+			ArticleID: id,
+			Method:    method,
+			Bias:      bias,
+			Related:   findRelatedArticles(article, candidates, method, bias, limit),
+		}
+
+		articlesCacheLock.Lock()
+		cacheSetJSON(articlesCache, cacheKey, resp, relatedCacheTTL)
+		articlesCacheLock.Unlock()
+
+		RespondSuccess(c, resp)
+	}
+}
+
+// findRelatedArticles ranks candidates by content similarity to the article and keeps
+// the top matches passing the bias filter
+func findRelatedArticles(article *db.Article, candidates []db.Article, method, bias string, limit int) []RelatedArticle {
+	docs := make([]similarity.Document, len(candidates))
+	byID := make(map[int64]*db.Article, len(candidates))
+	for i := range candidates {
+		docs[i] = similarity.Document{ID: candidates[i].ID, Text: candidates[i].Title + "\n" + candidates[i].Content}
+		byID[candidates[i].ID] = &candidates[i]
+	}
+	target := similarity.Document{ID: article.ID, Text: article.Title + "\n" + article.Content}
+
+	related := make([]RelatedArticle, 0, limit)
+	for _, match := range similarity.Rank(target, docs, method) {
+		if len(related) == limit {
+			break
+		}
+		cand := byID[match.ID]
+
+		var distance *float64
+		if article.CompositeScore != nil && cand.CompositeScore != nil {
+			d := math.Abs(*article.CompositeScore - *cand.CompositeScore)
+			distance = &d
+		}
+		switch bias {
+		case RelatedBiasSimilar:
+			if distance == nil || *distance > relatedSimilarBiasMax {
+				continue
+			}
+		case RelatedBiasContrasting:
+			if distance == nil || *distance < relatedContrastingBiasMin {
+				continue
+			}
+		}
+
+		related = append(related, RelatedArticle{
+			ID:             cand.ID,
+			Title:          cand.Title,
+			Source:         cand.Source,
+			URL:            cand.URL,
+			PubDate:        cand.PubDate,
+			CompositeScore: cand.CompositeScore,
+			Similarity:     math.Round(match.Similarity*1000) / 1000,
+			BiasDistance:   distance,
+		})
+	}
+	return related
+}
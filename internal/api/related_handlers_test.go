@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelatedArticlesHandler(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "related.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	insert := func(url, title, content string, score interface{}) int64 {
+		res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score)
+			VALUES ('src', CURRENT_TIMESTAMP, ?, ?, ?, ?)`, url, title, content, score)
+		require.NoError(t, err)
+		id, err := res.LastInsertId()
+		require.NoError(t, err)
+		return id
+	}
+	target := insert("https://example.com/related-1", "Senate climate bill", "Senate votes on climate bill with carbon tax", 0.1)
+	sameSide := insert("https://example.com/related-2", "Climate bill passes", "Climate bill with carbon tax clears Senate", 0.2)
+	otherSide := insert("https://example.com/related-3", "Carbon tax criticised", "Critics attack carbon tax in climate bill", -0.7)
+	insert("https://example.com/related-4", "Football", "Football season opens with record crowds", nil)
+
+	router := gin.New()
+	router.GET("/api/articles/:id/related", relatedArticlesHandler(dbConn))
+
+	get := func(query string) (int, []RelatedArticle) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/articles/%d/related%s", target, query), nil)
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		var resp struct {
+			Data RelatedArticlesResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp.Data.Related
+	}
+	ids := func(related []RelatedArticle) []int64 {
+		out := make([]int64, len(related))
+		for i, r := range related {
+			out[i] = r.ID
+		}
+		return out
+	}
+
+	code, related := get("?method=bow")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []int64{sameSide, otherSide}, ids(related), "unrelated and target articles are excluded")
+	require.NotNil(t, related[0].BiasDistance)
+	assert.InDelta(t, 0.1, *related[0].BiasDistance, 1e-9)
+
+	_, related = get("?bias=similar")
+	assert.Equal(t, []int64{sameSide}, ids(related))
+
+	_, related = get("?bias=contrasting")
+	assert.Equal(t, []int64{otherSide}, ids(related))
+
+	_, related = get("?limit=1&method=tfidf")
+	assert.Len(t, related, 1)
+
+	for _, query := range []string{"?limit=0", "?limit=21", "?method=embedding", "?bias=opposite"} {
+		code, _ := get(query)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles/9999/related", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
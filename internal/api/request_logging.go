@@ -0,0 +1,261 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request logging defaults. REQUEST_LOG_ENABLED turns the logger on,
+// REQUEST_LOG_SAMPLE_RATE logs one request in N and REQUEST_LOG_MAX_BODY caps each
+// logged body snapshot in bytes.
+const (
+	defaultRequestLogSampleRate = 1
+	defaultRequestLogMaxBody    = 2048
+	// requestLogCaptureLimit is how much of a body is buffered for redaction; larger
+	// bodies are logged by size only since truncated JSON cannot be redacted by field
+	requestLogCaptureLimit = 64 * 1024
+	redactedValue          = "[REDACTED]"
+)
+
+// keyLikeFields match field and query parameter names whose values are secrets
+var keyLikeFields = []string{"key", "token", "secret", "password", "passwd", "authorization", "credential", "signature"}
+
+// contentFields hold article text, which is logged by length only
+var contentFields = map[string]struct{}{
+	"content": {}, "body": {}, "text": {}, "description": {}, "summary": {},
+}
+
+// keyLikeValue matches values that look like credentials wherever they appear
+var keyLikeValue = regexp.MustCompile(`(?i)^(bearer\s+\S+|(sk|pk|rk)-[a-z0-9_-]{16,}|[a-f0-9]{32,}|[a-z0-9_-]{40,})$`)
+
+// RequestLogger logs a sample of API requests with redacted body snapshots. It is
+// separate from Gin's access log.
+type RequestLogger struct {
+	sampleRate uint64
+	maxBody    int
+	counter    atomic.Uint64
+	logf       func(format string, args ...interface{})
+}
+
+// NewRequestLogger logs one request in sampleRate with bodies capped at maxBody bytes
+func NewRequestLogger(sampleRate, maxBody int) *RequestLogger {
+	if sampleRate < 1 {
+		sampleRate = 1
+	}
+	return &RequestLogger{sampleRate: uint64(sampleRate), maxBody: maxBody, logf: log.Printf}
+}
+
+// NewRequestLoggerFromEnv builds the request logger, returning nil unless
+// REQUEST_LOG_ENABLED is true
+func NewRequestLoggerFromEnv() *RequestLogger {
+	enabled, _ := strconv.ParseBool(os.Getenv("REQUEST_LOG_ENABLED"))
+	if !enabled {
+		return nil
+	}
+
+	sampleRate := defaultRequestLogSampleRate
+	if v := os.Getenv("REQUEST_LOG_SAMPLE_RATE"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			log.Printf("[WARN] Invalid REQUEST_LOG_SAMPLE_RATE %q, using default %d", v, defaultRequestLogSampleRate)
+		} else {
+			sampleRate = parsed
+		}
+	}
+
+	maxBody := defaultRequestLogMaxBody
+	if v := os.Getenv("REQUEST_LOG_MAX_BODY"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Printf("[WARN] Invalid REQUEST_LOG_MAX_BODY %q, using default %d", v, defaultRequestLogMaxBody)
+		} else {
+			maxBody = parsed
+		}
+	}
+
+	return NewRequestLogger(sampleRate, maxBody)
+}
+
+// bodyCaptureWriter copies the start of the response body while writing it through
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyCaptureWriter) capture(b []byte) {
+	room := requestLogCaptureLimit - w.body.Len()
+	if len(b) > room {
+		b = b[:room]
+		w.truncated = true
+	}
+	w.body.Write(b)
+}
+
+// Middleware logs sampled requests once the handler has run. A nil logger is a no-op.
+func (rl *RequestLogger) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rl == nil || (rl.counter.Add(1)-1)%rl.sampleRate != 0 {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		reqBody, reqTruncated := peekRequestBody(c)
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		rl.logf("[REQUEST] Method=%s Path=%q Status=%d Latency=%v RequestBody=%q ResponseBody=%q",
+			c.Request.Method,
+			redactURL(c.Request.URL),
+			writer.Status(),
+			time.Since(start),
+			rl.snapshot(reqBody, reqTruncated, c.ContentType()),
+			rl.snapshot(writer.body.Bytes(), writer.truncated, writer.Header().Get("Content-Type")),
+		)
+	}
+}
+
+// peekRequestBody reads the start of the request body and restores it for the handler
+func peekRequestBody(c *gin.Context) ([]byte, bool) {
+	if c.Request.Body == nil {
+		return nil, false
+	}
+	head, err := io.ReadAll(io.LimitReader(c.Request.Body, requestLogCaptureLimit+1))
+	c.Request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), c.Request.Body), Closer: c.Request.Body}
+	if err != nil {
+		return nil, false
+	}
+	if len(head) > requestLogCaptureLimit {
+		return head[:requestLogCaptureLimit], true
+	}
+	return head, false
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// snapshot returns the redacted, size-capped form of a body. Only complete JSON bodies
+// are logged; anything else is summarized by content type and size.
+func (rl *RequestLogger) snapshot(body []byte, truncated bool, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var value interface{}
+	if truncated || !strings.Contains(contentType, "json") || json.Unmarshal(body, &value) != nil {
+		size := strconv.Itoa(len(body))
+		if truncated {
+			size = "over " + size
+		}
+		return fmt.Sprintf("[%s body, %s bytes]", mediaTypeOrUnknown(contentType), size)
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return "[unencodable body]"
+	}
+	if len(redacted) > rl.maxBody {
+		return string(redacted[:rl.maxBody]) + "...(truncated)"
+	}
+	return string(redacted)
+}
+
+func mediaTypeOrUnknown(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	if mediaType = strings.TrimSpace(mediaType); mediaType == "" {
+		return "unknown"
+	}
+	return mediaType
+}
+
+// redactValue replaces secrets and article text in a decoded JSON value
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for field, fieldValue := range v {
+			switch {
+			case isKeyLikeField(field):
+				v[field] = redactedValue
+			case isContentField(field):
+				if s, ok := fieldValue.(string); ok {
+					v[field] = fmt.Sprintf("[REDACTED %d chars]", len([]rune(s)))
+				} else {
+					v[field] = redactValue(fieldValue)
+				}
+			default:
+				v[field] = redactValue(fieldValue)
+			}
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+		return v
+	case string:
+		if keyLikeValue.MatchString(v) {
+			return redactedValue
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isKeyLikeField(field string) bool {
+	field = strings.ToLower(field)
+	for _, marker := range keyLikeFields {
+		if strings.Contains(field, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func isContentField(field string) bool {
+	_, ok := contentFields[strings.ToLower(field)]
+	return ok
+}
+
+// redactURL returns the request path and query with key-like query values redacted
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+	query := u.Query()
+	for name, values := range query {
+		for i, value := range values {
+			if isKeyLikeField(name) || keyLikeValue.MatchString(value) {
+				values[i] = redactedValue
+			}
+		}
+		query[name] = values
+	}
+	return u.Path + "?" + query.Encode()
+}
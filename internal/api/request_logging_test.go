@@ -0,0 +1,109 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLoggedRouter(rl *RequestLogger, lines *[]string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	rl.logf = func(format string, args ...interface{}) {
+		*lines = append(*lines, fmt.Sprintf(format, args...))
+	}
+	router := gin.New()
+	router.Use(rl.Middleware())
+	router.POST("/api/articles", func(c *gin.Context) {
+		var req map[string]interface{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondError(c, NewAppError(ErrValidation, "bad body"))
+			return
+		}
+		RespondSuccess(c, map[string]interface{}{"title": req["title"], "content": req["content"], "api_key": "sk-abcdefghijklmnopqrstuvwxyz"})
+	})
+	router.GET("/api/articles.csv", func(c *gin.Context) {
+		c.Data(http.StatusOK, MediaTypeCSV, []byte("article_id,content\n1,secret article text\n"))
+	})
+	return router
+}
+
+func TestRequestLoggerRedactsBodies(t *testing.T) {
+	var lines []string
+	router := newLoggedRouter(NewRequestLogger(1, 2048), &lines)
+
+	body := `{"title":"Budget vote","content":"Full article text","password":"hunter2","meta":{"token":"abc"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/articles?api_key=supersecret&limit=5", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, "the handler still receives the full request body")
+	assert.Contains(t, w.Body.String(), "Full article text", "the response is not altered")
+
+	require.Len(t, lines, 1)
+	line := lines[0]
+	assert.Contains(t, line, "[REQUEST] Method=POST")
+	assert.Contains(t, line, "Status=200")
+	assert.Contains(t, line, "Budget vote")
+	assert.Contains(t, line, "limit=5")
+	assert.Contains(t, line, "[REDACTED 17 chars]")
+	for _, secret := range []string{"Full article text", "hunter2", "supersecret", `\"abc\"`, "sk-abcdefghijklmnopqrstuvwxyz"} {
+		assert.NotContains(t, line, secret)
+	}
+}
+
+func TestRequestLoggerNonJSONBodies(t *testing.T) {
+	var lines []string
+	router := newLoggedRouter(NewRequestLogger(1, 2048), &lines)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles.csv", nil))
+
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "[text/csv body, 41 bytes]")
+	assert.NotContains(t, lines[0], "secret article text")
+}
+
+func TestRequestLoggerSamplingAndCap(t *testing.T) {
+	var lines []string
+	router := newLoggedRouter(NewRequestLogger(3, 20), &lines)
+
+	for i := 0; i < 6; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader(`{"title":"`+strings.Repeat("x", 50)+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.Len(t, lines, 2, "one request in three is logged")
+	assert.Contains(t, lines[0], "...(truncated)")
+	assert.NotContains(t, lines[0], strings.Repeat("x", 50))
+}
+
+func TestRequestLoggerFromEnv(t *testing.T) {
+	restoreLogOutput()
+	t.Setenv("REQUEST_LOG_ENABLED", "")
+	assert.Nil(t, NewRequestLoggerFromEnv())
+
+	t.Setenv("REQUEST_LOG_ENABLED", "true")
+	t.Setenv("REQUEST_LOG_SAMPLE_RATE", "10")
+	t.Setenv("REQUEST_LOG_MAX_BODY", "bogus")
+	rl := NewRequestLoggerFromEnv()
+	require.NotNil(t, rl)
+	assert.Equal(t, uint64(10), rl.sampleRate)
+	assert.Equal(t, defaultRequestLogMaxBody, rl.maxBody)
+
+	// A nil logger passes requests through
+	var nilLogger *RequestLogger
+	router := gin.New()
+	router.Use(nilLogger.Middleware())
+	router.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, "pong", w.Body.String())
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// RescoreFailedResponse lists the models queued for rescoring
+type RescoreFailedResponse struct {
+	ArticleID int64    `json:"article_id" example:"42"`
+	Status    string   `json:"status" example:"rescore queued"`
+	Models    []string `json:"models"`
+}
+
+// rescoreFailedHandler handles POST /api/articles/:id/rescore-failed
+// @Summary Rescore failed models
+// @Description Re-runs only the configured models without a valid score from the last max_age
+// @Description (default 168h), then recomputes the composite from the new and existing scores.
+// @Description Progress streams from /api/llm/score-progress/{id}.
+// @Tags LLM
+// @Produce json
+// @Param id path integer true "Article ID"
+// @Param max_age query string false "Oldest score still counted as valid, as a Go duration (0 = any age)"
+// @Success 200 {object} StandardResponse{data=RescoreFailedResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "A reanalysis of the article is already queued or running"
+// @Failure 503 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/articles/{id}/rescore-failed [post]
+// @ID rescoreFailedModels
+func rescoreFailedHandler(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *llm.ScoreManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		articleID, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+		if llmClient == nil || scoreManager == nil {
+			RespondError(c, NewAppError(ErrLLMService, "Scoring is not available"))
+			return
+		}
+
+		maxAge := llm.DefaultRescoreMaxAge
+		if v := c.Query("max_age"); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil || parsed < 0 {
+				RespondError(c, NewAppError(ErrValidation, "Invalid 'max_age' parameter, expected a duration such as 24h"))
+				return
+			}
+			maxAge = parsed
+		}
+
+		if _, err := db.FetchArticleByIDContext(c.Request.Context(), dbConn, articleID); err != nil {
+			if errors.Is(err, db.ErrArticleNotFound) {
+				RespondError(c, ErrArticleNotFound)
+				return
+			}
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch article"))
+			return
+		}
+
+		cfg := llmClient.GetConfig()
+		if cfg == nil {
+			var err error
+			if cfg, err = llm.LoadCompositeScoreConfig(); err != nil {
+				RespondError(c, WrapError(err, ErrLLMService, "Failed to load LLM configuration"))
+				return
+			}
+		}
+		scores, err := db.FetchLLMScoresContext(c.Request.Context(), dbConn, articleID)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch scores"))
+			return
+		}
+		failed := llm.FailedModels(scores, cfg, maxAge, time.Now())
+		if len(failed) == 0 {
+			RespondSuccess(c, RescoreFailedResponse{ArticleID: articleID, Status: "nothing to rescore", Models: []string{}})
+			return
+		}
+
+		if scoreManager.ShuttingDown() {
+			RespondError(c, ErrShuttingDown)
+			return
+		}
+		if err := startRescoreJob(llmClient, scoreManager, articleID, maxAge); err != nil {
+			RespondError(c, reanalysisInProgressError(articleID))
+			return
+		}
+		RespondSuccess(c, RescoreFailedResponse{ArticleID: articleID, Status: "rescore queued", Models: failed})
+	}
+}
+
+// startRescoreJob runs RescoreFailedModels in the background as a reanalysis job, so it
+// shares the job queue, progress stream, cancellation and per-article lock of a full
+// reanalysis. It fails with llm.ErrJobInProgress while the article has a job.
+func startRescoreJob(llmClient *llm.LLMClient, scoreManager *llm.ScoreManager, articleID int64, maxAge time.Duration) error {
+	if os.Getenv("NO_AUTO_ANALYZE") == "true" {
+		log.Printf("[rescore %d] NO_AUTO_ANALYZE is set, skipping background rescore.", articleID)
+		scoreManager.SetProgress(articleID, &models.ProgressState{
+			Status:      "Skipped",
+			Step:        "Skipped",
+			Message:     "Automatic rescore skipped by test configuration.",
+			Percent:     100,
+			LastUpdated: time.Now().Unix(),
+		})
+		return nil
+	}
+
+	jobCtx, jobDone, err := scoreManager.TryStartJob(context.Background(), articleID)
+	if err != nil {
+		return err
+	}
+	scoreManager.SetProgress(articleID, &models.ProgressState{
+		Status:  "Queued",
+		Step:    "Pending",
+		Message: "Rescore queued for failed models",
+	})
+	go func() {
+		defer jobDone()
+		releaseSlot, err := scoreManager.AcquireJobSlot(jobCtx, articleID)
+		if err != nil {
+			scoreManager.MarkCancelled(articleID)
+			return
+		}
+		defer releaseSlot()
+
+		rescored, err := llmClient.RescoreFailedModels(jobCtx, articleID, scoreManager, maxAge)
+		switch {
+		case errors.Is(err, context.Canceled):
+			log.Printf("[rescore %d] Rescore cancelled", articleID)
+			scoreManager.MarkCancelled(articleID)
+		case err != nil:
+			// UpdateArticleScore reports its own failures; only report the ones before it
+			if state := scoreManager.GetProgress(articleID); state == nil || state.Status != "Error" {
+				userMessage, step := translateReanalysisError(err)
+				scoreManager.SetProgress(articleID, &models.ProgressState{
+					Status:  "Error",
+					Step:    step,
+					Message: userMessage,
+					Error:   err.Error(),
+				})
+			}
+			log.Printf("[rescore %d] Rescore failed: %v", articleID, err)
+		case len(rescored) == 0:
+			// Another job filled in the missing scores while this one was queued
+			scoreManager.SetProgress(articleID, &models.ProgressState{
+				Status:      "Complete",
+				Step:        "Done",
+				Message:     "No failed models left to rescore",
+				Percent:     100,
+				LastUpdated: time.Now().Unix(),
+			})
+		default:
+			log.Printf("[rescore %d] Rescored %d model(s): %v", articleID, len(rescored), rescored)
+		}
+	}()
+	return nil
+}
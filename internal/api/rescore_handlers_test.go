@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRescoreFailedHandler(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+	// The background job records a skipped state instead of calling the LLM
+	t.Setenv("NO_AUTO_ANALYZE", "true")
+
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "rescore.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	cfg := &llm.CompositeScoreConfig{
+		MinScore: -1,
+		MaxScore: 1,
+		Models:   []llm.ModelConfig{{ModelName: "left-model"}, {ModelName: "right-model"}},
+	}
+	client := llm.NewLLMClientWithService(dbConn, llm.NewFixtureLLMService(llm.Fixture{}), cfg)
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	scoreManager := llm.NewScoreManager(dbConn, llm.NewCache(), &llm.DefaultScoreCalculator{}, pm)
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('src', CURRENT_TIMESTAMP, 'https://example.com/rescore', 'title', 'content')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	insertScore := func(model string) {
+		_, err := db.InsertLLMScore(dbConn, &db.LLMScore{
+			ArticleID: articleID, Model: model, Score: 0.2, Metadata: `{"confidence": 0.8}`, Version: 1, CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	insertScore("left-model")
+
+	router := gin.New()
+	router.POST("/api/articles/:id/rescore-failed", rescoreFailedHandler(client, dbConn, scoreManager))
+	post := func(path string) (int, RescoreFailedResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		var resp struct {
+			Data RescoreFailedResponse `json:"data"`
+		}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp.Data
+	}
+
+	code, resp := post(fmt.Sprintf("/api/articles/%d/rescore-failed", articleID))
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "rescore queued", resp.Status)
+	assert.Equal(t, []string{"right-model"}, resp.Models)
+	progress := scoreManager.GetProgress(articleID)
+	require.NotNil(t, progress)
+	assert.Equal(t, "Skipped", progress.Status)
+
+	insertScore("right-model")
+	code, resp = post(fmt.Sprintf("/api/articles/%d/rescore-failed", articleID))
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "nothing to rescore", resp.Status)
+	assert.Empty(t, resp.Models)
+
+	code, _ = post(fmt.Sprintf("/api/articles/%d/rescore-failed?max_age=soon", articleID))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = post("/api/articles/999999/rescore-failed")
+	assert.Equal(t, http.StatusNotFound, code)
+}
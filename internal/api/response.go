@@ -74,32 +74,32 @@ func RespondError(c *gin.Context, err error) {
 
 		log.Printf("[ERROR] LLM error (%s): %s", llmErr.ErrorType, llmErr.Message)
 
-		// We need to use gin.H to add details which is not part of ErrorDetail
-		c.JSON(statusCode, gin.H{
-			"success": false,
-			"error": gin.H{
-				"code":    errorDetail.Code,
-				"message": errorDetail.Message,
-				"details": map[string]interface{}{
-					"provider":        "openrouter",
-					"model":           c.Request.URL.Query().Get("model"),
-					"llm_status_code": llmErr.StatusCode,
-					"llm_message":     llmErr.Message,
-					"error_type":      string(llmErr.ErrorType),
-					"retry_after":     llmErr.RetryAfter,
-					"correlation_id":  c.Request.Header.Get("X-Request-ID"),
-				},
-				"recommended_action": getRecommendedAction(llmErr),
-			},
+		errorDetail.Details = map[string]interface{}{
+			"provider":           "openrouter",
+			"model":              c.Request.URL.Query().Get("model"),
+			"llm_status_code":    llmErr.StatusCode,
+			"llm_message":        llmErr.Message,
+			"error_type":         string(llmErr.ErrorType),
+			"retry_after":        llmErr.RetryAfter,
+			"correlation_id":     c.Request.Header.Get("X-Request-ID"),
+			"recommended_action": getRecommendedAction(llmErr),
+		}
+		c.JSON(statusCode, ErrorResponse{
+			Success: false,
+			Error:   errorDetail,
 		})
 		return
 	}
 
-	// Handle regular AppError
+	// Handle regular AppError, then errors from lower layers with a known API mapping
 	var appError *apperrors.AppError
-	if errors.As(err, &appError) {
+	if !errors.As(err, &appError) {
+		appError = domainError(err)
+	}
+	if appError != nil {
 		errorDetail.Code = appError.Code
 		errorDetail.Message = appError.Message
+		errorDetail.Details = appError.Details
 		statusCode = getHTTPStatus(appError.Code)
 	} else if err != nil {
 		errorDetail.Message = err.Error()
@@ -170,6 +170,18 @@ func getHTTPStatus(code string) int {
 		return http.StatusServiceUnavailable
 	case ErrConflict:
 		return http.StatusConflict
+	case ErrAuth:
+		return http.StatusUnauthorized
+	case ErrNotAcceptable:
+		return http.StatusNotAcceptable
+	case ErrTimeout:
+		return http.StatusGatewayTimeout
+	case ErrDuplicate:
+		return http.StatusConflict
+	case ErrTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case ErrNoValidScores, ErrZeroConfidence, ErrInsufficientScore:
+		return http.StatusUnprocessableEntity
 	default:
 		return http.StatusInternalServerError
 	}
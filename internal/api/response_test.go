@@ -2,14 +2,17 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/apperrors"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -199,6 +202,59 @@ func TestRespondError_AppErrors(t *testing.T) {
 	}
 }
 
+func TestRespondError_DomainErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"zero confidence wrapped in invalid perspectives",
+			fmt.Errorf("%w: %w", llm.ErrAllPerspectivesInvalid, llm.ErrAllScoresZeroConfidence),
+			http.StatusUnprocessableEntity, ErrZeroConfidence},
+		{"no valid scores", fmt.Errorf("scoring failed: %w", llm.ErrAllPerspectivesInvalid),
+			http.StatusUnprocessableEntity, ErrNoValidScores},
+		{"insufficient models", fmt.Errorf("%w: 1 of 2 required", llm.ErrInsufficientModels),
+			http.StatusUnprocessableEntity, ErrInsufficientScore},
+		{"article not found", db.ErrArticleNotFound, http.StatusNotFound, ErrNotFound},
+		{"duplicate url", db.ErrDuplicateURL, http.StatusConflict, ErrDuplicate},
+		{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout, ErrTimeout},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			RespondError(c, tc.err)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			var resp ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.False(t, resp.Success)
+			assert.Equal(t, tc.expectedCode, resp.Error.Code)
+			assert.NotEmpty(t, resp.Error.Message)
+			assert.Equal(t, map[string]interface{}{"reason": tc.err.Error()}, resp.Error.Details)
+		})
+	}
+
+	// An explicit AppError keeps its code and carries no details unless given some
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	RespondError(c, ErrArticleNotFound)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NotContains(t, w.Body.String(), "details")
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	RespondError(c, WithDetails(ErrInvalidPayload, map[string]string{"field": "title"}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"success":false,"error":{"code":"validation_error","message":"Invalid request payload","details":{"field":"title"}}}`, w.Body.String())
+	assert.Nil(t, ErrInvalidPayload.Details, "WithDetails must not change the shared error")
+}
+
 func TestLogError(t *testing.T) {
 	// Set up logger to prevent nil pointer dereference
 	var logBuffer bytes.Buffer
@@ -0,0 +1,399 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// defaultBackfillInterval is how often the backfill looks for unscored articles
+	defaultBackfillInterval = 5 * time.Minute
+	// defaultBackfillBatchSize caps how many backfill jobs are queued or running at once
+	defaultBackfillBatchSize = 10
+	// maxBackfillAttempts is how often one article is queued before the backfill gives
+	// up on it until the server restarts
+	maxBackfillAttempts = 3
+	// backfillRateLimitPause is how long the backfill waits after a job was rate limited
+	backfillRateLimitPause = 10 * time.Minute
+	// maxScoringRetries is how often an article left pending_retry by a shutdown is
+	// requeued at startup, across restarts, before it is marked failed
+	maxScoringRetries = 3
+)
+
+// backfillCondition selects the articles the backfill scores: those never scored.
+// Articles left pending_retry by a shutdown are requeued at startup instead.
+const backfillCondition = `composite_score IS NULL`
+
+// errBackfillUnavailable is returned by Start when scoring is not configured
+var errBackfillUnavailable = errors.New("scoring is unavailable: no LLM client or score manager")
+
+// ScoreBackfillStatus reports the backfill state for GET /api/admin/score-backfill
+type ScoreBackfillStatus struct {
+	Running   bool   `json:"running"`
+	Interval  string `json:"interval"`
+	BatchSize int    `json:"batch_size"`
+	// Unscored is the number of articles without a composite score at the last run
+	Unscored int        `json:"unscored"`
+	InFlight int        `json:"in_flight"`
+	LastRun  *time.Time `json:"last_run,omitempty"`
+	// Queued, Scored and Failed count backfill jobs since the server started
+	Queued int `json:"queued"`
+	Scored int `json:"scored"`
+	Failed int `json:"failed"`
+	// GaveUp is the number of articles skipped after maxBackfillAttempts attempts
+	GaveUp int `json:"gave_up"`
+	// PausedUntil is set while the backfill waits out an LLM rate limit or an open
+	// provider circuit breaker
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+}
+
+// ScoreBackfiller periodically queues articles that have no composite score for
+// scoring. Each article runs as a regular reanalysis job, so it waits for a free job
+// slot like any other reanalysis and shows up in the score progress stream. At most
+// batchSize backfill jobs are outstanding at a time, and a rate-limited job pauses the
+// backfill for backfillRateLimitPause. No jobs are queued while the LLM circuit breaker
+// is open.
+type ScoreBackfiller struct {
+	dbConn       *sqlx.DB
+	scoreManager *llm.ScoreManager
+	interval     time.Duration
+	batchSize    int
+	// enqueue starts scoring an article and calls done with the outcome; nil when
+	// scoring is unavailable
+	enqueue func(articleID int64, done func(error))
+	// providerUnavailableUntil reports an open LLM circuit breaker, which pauses the
+	// backfill until it lets calls through again
+	providerUnavailableUntil func() (time.Time, bool)
+
+	mu          sync.Mutex
+	stopChan    chan struct{} // nil while stopped
+	inFlight    map[int64]bool
+	attempts    map[int64]int
+	lastRun     *time.Time
+	unscored    int
+	queued      int
+	scored      int
+	failed      int
+	pausedUntil time.Time
+	lastError   string
+}
+
+// NewScoreBackfiller creates a stopped backfiller. A non-positive interval or batch
+// size uses the default.
+func NewScoreBackfiller(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *llm.ScoreManager,
+	interval time.Duration, batchSize int) *ScoreBackfiller {
+	if interval <= 0 {
+		interval = defaultBackfillInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBackfillBatchSize
+	}
+	b := &ScoreBackfiller{
+		dbConn:       dbConn,
+		scoreManager: scoreManager,
+		interval:     interval,
+		batchSize:    batchSize,
+		inFlight:     make(map[int64]bool),
+		attempts:     make(map[int64]int),
+	}
+	if llmClient != nil && scoreManager != nil {
+		b.enqueue = func(articleID int64, done func(error)) {
+			startReanalysisJobThen(llmClient, dbConn, scoreManager, articleID, false, done)
+		}
+		b.providerUnavailableUntil = llmClient.ProviderUnavailableUntil
+	}
+	return b
+}
+
+// NewScoreBackfillerFromEnv creates the backfiller tuned by SCORE_BACKFILL_INTERVAL and
+// SCORE_BACKFILL_BATCH_SIZE, and starts it when SCORE_BACKFILL_ENABLED is true
+func NewScoreBackfillerFromEnv(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *llm.ScoreManager) *ScoreBackfiller {
+	b := NewScoreBackfiller(llmClient, dbConn, scoreManager, backfillIntervalFromEnv(), backfillBatchSizeFromEnv())
+	if enabled, _ := strconv.ParseBool(os.Getenv("SCORE_BACKFILL_ENABLED")); enabled {
+		if err := b.Start(); err != nil {
+			log.Printf("[WARN] Score backfill not started: %v", err)
+		} else {
+			log.Printf("Score backfill started (every %v, up to %d articles at a time)", b.interval, b.batchSize)
+		}
+	}
+	return b
+}
+
+// RequeuePendingRetries queues the articles a server shutdown left pending_retry for
+// scoring again, whether or not the score backfill runs, and returns how many it
+// queued. An article already requeued maxScoringRetries times is marked failed_error
+// instead, so an article that keeps being interrupted is not retried forever.
+func RequeuePendingRetries(llmClient *llm.LLMClient, dbConn *sqlx.DB, scoreManager *llm.ScoreManager) int {
+	if llmClient == nil || scoreManager == nil {
+		return 0
+	}
+	return requeuePendingRetries(dbConn, func(articleID int64, done func(error)) {
+		startReanalysisJobThen(llmClient, dbConn, scoreManager, articleID, false, done)
+	})
+}
+
+// requeuePendingRetries is RequeuePendingRetries with the job start injected
+func requeuePendingRetries(dbConn *sqlx.DB, enqueue func(articleID int64, done func(error))) int {
+	ids, err := db.FetchPendingRetryArticleIDs(dbConn)
+	if err != nil {
+		log.Printf("[WARN] Could not requeue interrupted articles: %v", err)
+		return 0
+	}
+	queued := 0
+	for _, id := range ids {
+		articleID := id
+		attempts, err := db.IncrementScoringRetries(dbConn, articleID)
+		if err != nil {
+			log.Printf("[WARN] Could not requeue interrupted article %d: %v", articleID, err)
+			continue
+		}
+		if attempts > maxScoringRetries {
+			log.Printf("[WARN] Article %d was interrupted %d times; marking it failed instead of retrying", articleID, maxScoringRetries)
+			if err := db.UpdateArticleStatus(dbConn, articleID, models.ArticleStatusFailedError); err != nil {
+				log.Printf("[WARN] Could not mark article %d failed: %v", articleID, err)
+				continue
+			}
+			if err := db.ClearScoringRetries(dbConn, articleID); err != nil {
+				log.Printf("[WARN] Could not clear the retries of article %d: %v", articleID, err)
+			}
+			continue
+		}
+		enqueue(articleID, func(err error) {
+			if err != nil {
+				return
+			}
+			if err := db.ClearScoringRetries(dbConn, articleID); err != nil {
+				log.Printf("[WARN] Could not clear the retries of article %d: %v", articleID, err)
+			}
+		})
+		queued++
+	}
+	if queued > 0 {
+		log.Printf("Requeued %d articles whose scoring was interrupted by the last shutdown", queued)
+	}
+	return queued
+}
+
+// backfillIntervalFromEnv reads SCORE_BACKFILL_INTERVAL, falling back to the default
+func backfillIntervalFromEnv() time.Duration {
+	v := os.Getenv("SCORE_BACKFILL_INTERVAL")
+	if v == "" {
+		return defaultBackfillInterval
+	}
+	interval, err := time.ParseDuration(v)
+	if err != nil || interval <= 0 {
+		log.Printf("[WARN] Invalid SCORE_BACKFILL_INTERVAL %q, using default %v", v, defaultBackfillInterval)
+		return defaultBackfillInterval
+	}
+	return interval
+}
+
+// backfillBatchSizeFromEnv reads SCORE_BACKFILL_BATCH_SIZE, falling back to the default
+func backfillBatchSizeFromEnv() int {
+	v := os.Getenv("SCORE_BACKFILL_BATCH_SIZE")
+	if v == "" {
+		return defaultBackfillBatchSize
+	}
+	size, err := strconv.Atoi(v)
+	if err != nil || size <= 0 {
+		log.Printf("[WARN] Invalid SCORE_BACKFILL_BATCH_SIZE %q, using default %d", v, defaultBackfillBatchSize)
+		return defaultBackfillBatchSize
+	}
+	return size
+}
+
+// Start runs the backfill straight away and then every interval until Stop is called.
+// Starting a running backfill does nothing.
+func (b *ScoreBackfiller) Start() error {
+	if b.enqueue == nil {
+		return errBackfillUnavailable
+	}
+	if os.Getenv("NO_AUTO_ANALYZE") == "true" {
+		return errAutoAnalyzeDisabled
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopChan != nil {
+		return nil
+	}
+	stop := make(chan struct{})
+	b.stopChan = stop
+	go func() {
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+		for {
+			b.RunOnce()
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop ends the periodic runs. Jobs already queued carry on; they can be cancelled one
+// by one with DELETE /api/llm/reanalyze/{id}.
+func (b *ScoreBackfiller) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopChan != nil {
+		close(b.stopChan)
+		b.stopChan = nil
+	}
+}
+
+// RunOnce queues unscored articles, newest first, until batchSize backfill jobs are
+// outstanding, and returns how many it queued. Articles already being scored and
+// those that used up their attempts are skipped.
+func (b *ScoreBackfiller) RunOnce() int {
+	if b.enqueue == nil {
+		return 0
+	}
+	var breakerUntil time.Time
+	breakerOpen := false
+	if b.providerUnavailableUntil != nil {
+		breakerUntil, breakerOpen = b.providerUnavailableUntil()
+	}
+
+	b.mu.Lock()
+	now := time.Now().UTC()
+	b.lastRun = &now
+	capacity := b.batchSize - len(b.inFlight)
+	if breakerOpen && breakerUntil.After(b.pausedUntil) {
+		b.pausedUntil = breakerUntil.UTC()
+		log.Printf("[ScoreBackfill] LLM provider unavailable, pausing until %s", b.pausedUntil.Format(time.RFC3339))
+	}
+	paused := now.Before(b.pausedUntil)
+	b.mu.Unlock()
+
+	var unscored int
+	if err := b.dbConn.Get(&unscored, `SELECT COUNT(*) FROM articles WHERE `+backfillCondition); err != nil {
+		b.recordError(err)
+		return 0
+	}
+	b.mu.Lock()
+	b.unscored = unscored
+	b.mu.Unlock()
+	if paused || capacity <= 0 || unscored == 0 {
+		return 0
+	}
+
+	// Articles picked before an error are already marked in flight, so they are queued
+	ids, err := b.pickArticles(capacity)
+	if err != nil {
+		b.recordError(err)
+	}
+	for _, id := range ids {
+		articleID := id
+		b.enqueue(articleID, func(err error) { b.jobDone(articleID, err) })
+	}
+	if len(ids) > 0 {
+		log.Printf("[ScoreBackfill] Queued %d of %d unscored articles for scoring", len(ids), unscored)
+	}
+	return len(ids)
+}
+
+// pickArticles selects up to limit unscored articles and marks them in flight. On
+// error it returns the articles picked so far with it.
+func (b *ScoreBackfiller) pickArticles(limit int) ([]int64, error) {
+	rows, err := b.dbConn.Queryx(`SELECT id FROM articles WHERE ` + backfillCondition + ` ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var ids []int64
+	for len(ids) < limit && rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return ids, err
+		}
+		if b.inFlight[id] || b.attempts[id] >= maxBackfillAttempts ||
+			(b.scoreManager != nil && b.scoreManager.HasJob(id)) {
+			continue
+		}
+		b.inFlight[id] = true
+		b.attempts[id]++
+		b.queued++
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// jobDone records the outcome of a backfill job
+func (b *ScoreBackfiller) jobDone(articleID int64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.inFlight, articleID)
+	if err == nil {
+		b.scored++
+		delete(b.attempts, articleID)
+		return
+	}
+	b.failed++
+	b.lastError = err.Error()
+	if isRateLimitError(err) {
+		// Waiting out the limit is not the article's fault, so the attempt is not counted
+		b.attempts[articleID]--
+		b.pausedUntil = time.Now().UTC().Add(backfillRateLimitPause)
+		log.Printf("[ScoreBackfill] Rate limited, pausing until %s", b.pausedUntil.Format(time.RFC3339))
+	}
+}
+
+func (b *ScoreBackfiller) recordError(err error) {
+	log.Printf("[ScoreBackfill] Failed to find unscored articles: %v", err)
+	b.mu.Lock()
+	b.lastError = err.Error()
+	b.mu.Unlock()
+}
+
+// isRateLimitError reports whether a job failed because the LLM provider rate limited it
+func isRateLimitError(err error) bool {
+	if errors.Is(err, llm.ErrBothLLMKeysRateLimited) {
+		return true
+	}
+	_, step := translateReanalysisError(err)
+	return step == "Rate Limited"
+}
+
+// Status returns a snapshot of the backfill state
+func (b *ScoreBackfiller) Status() ScoreBackfillStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := ScoreBackfillStatus{
+		Running:   b.stopChan != nil,
+		Interval:  b.interval.String(),
+		BatchSize: b.batchSize,
+		Unscored:  b.unscored,
+		InFlight:  len(b.inFlight),
+		LastRun:   b.lastRun,
+		Queued:    b.queued,
+		Scored:    b.scored,
+		Failed:    b.failed,
+		LastError: b.lastError,
+	}
+	for id, n := range b.attempts {
+		if n >= maxBackfillAttempts && !b.inFlight[id] {
+			status.GaveUp++
+		}
+	}
+	if time.Now().Before(b.pausedUntil) {
+		until := b.pausedUntil
+		status.PausedUntil = &until
+	}
+	return status
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreBackfillerRunOnce(t *testing.T) {
+	restoreLogOutput()
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "backfill.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	for i := 0; i < 4; i++ {
+		var score interface{}
+		if i == 0 {
+			score = 0.2
+		}
+		_, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score)
+			VALUES ('bbc', CURRENT_TIMESTAMP, ?, 'Title', 'Body', ?)`, fmt.Sprintf("https://example.com/b/%d", i), score)
+		require.NoError(t, err)
+	}
+
+	b := NewScoreBackfiller(nil, dbConn, nil, 0, 2)
+	assert.Equal(t, 0, b.RunOnce(), "without scoring configured nothing is queued")
+	assert.ErrorIs(t, b.Start(), errBackfillUnavailable)
+
+	// Jobs finish when the test says so
+	pending := map[int64]func(error){}
+	b.enqueue = func(articleID int64, done func(error)) { pending[articleID] = done }
+
+	assert.Equal(t, 2, b.RunOnce(), "the batch size caps outstanding jobs")
+	assert.Equal(t, 0, b.RunOnce())
+	status := b.Status()
+	assert.Equal(t, 3, status.Unscored)
+	assert.Equal(t, 2, status.InFlight)
+	assert.NotContains(t, pending, int64(1), "scored articles are never queued")
+
+	_, err = dbConn.Exec(`UPDATE articles SET composite_score = 0.1 WHERE id = 4`)
+	require.NoError(t, err)
+	pending[4](nil)
+	pending[3](errors.New("insufficient valid models"))
+	assert.Equal(t, 2, b.RunOnce(), "finished jobs free their places")
+	assert.Contains(t, pending, int64(2))
+
+	pending[2](fmt.Errorf("scoring: %w", llm.ErrBothLLMKeysRateLimited))
+	status = b.Status()
+	require.NotNil(t, status.PausedUntil, "a rate-limited job pauses the backfill")
+	assert.Equal(t, 1, status.Scored)
+	assert.Equal(t, 2, status.Failed)
+	assert.Equal(t, 4, status.Queued)
+	assert.Equal(t, 0, b.RunOnce())
+	assert.Equal(t, 0, b.attempts[2], "the rate-limited attempt is not counted")
+
+	// An article that keeps failing is given up on
+	b.pausedUntil = b.pausedUntil.AddDate(-1, 0, 0)
+	pending[3](errors.New("insufficient valid models"))
+	for b.attempts[3] < maxBackfillAttempts {
+		delete(pending, 3)
+		b.RunOnce()
+		require.Contains(t, pending, int64(3))
+		pending[3](errors.New("insufficient valid models"))
+	}
+	delete(pending, 3)
+	b.RunOnce()
+	assert.NotContains(t, pending, int64(3))
+	assert.Equal(t, 1, b.Status().GaveUp)
+}
+
+func TestRequeuePendingRetries(t *testing.T) {
+	restoreLogOutput()
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "backfill.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	for i, status := range []string{models.ArticleStatusScored, models.ArticleStatusPendingRetry, models.ArticleStatusPendingRetry} {
+		_, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score, status)
+			VALUES ('bbc', CURRENT_TIMESTAMP, ?, 'Title', 'Body', 0.2, ?)`, fmt.Sprintf("https://example.com/r/%d", i), status)
+		require.NoError(t, err)
+	}
+
+	b := NewScoreBackfiller(nil, dbConn, nil, 0, 5)
+	b.enqueue = func(articleID int64, done func(error)) { t.Errorf("backfill queued article %d", articleID) }
+	assert.Equal(t, 0, b.RunOnce(), "the backfill leaves interrupted articles to the startup requeue")
+
+	var queued []int64
+	var done []func(error)
+	enqueue := func(articleID int64, onDone func(error)) {
+		queued = append(queued, articleID)
+		done = append(done, onDone)
+	}
+	assert.Equal(t, 2, requeuePendingRetries(dbConn, enqueue))
+	assert.Equal(t, []int64{2, 3}, queued)
+
+	// Article 2 is scored; article 3 keeps being interrupted
+	done[0](nil)
+	_, err = dbConn.Exec(`UPDATE articles SET status = 'processed' WHERE id = 2`)
+	require.NoError(t, err)
+	for restart := 2; restart <= maxScoringRetries; restart++ {
+		queued = nil
+		assert.Equal(t, 1, requeuePendingRetries(dbConn, enqueue))
+		assert.Equal(t, []int64{3}, queued)
+	}
+	queued = nil
+	assert.Equal(t, 0, requeuePendingRetries(dbConn, enqueue), "retries are capped across restarts")
+	assert.Empty(t, queued)
+	var status string
+	require.NoError(t, dbConn.Get(&status, `SELECT status FROM articles WHERE id = 3`))
+	assert.Equal(t, models.ArticleStatusFailedError, status)
+
+	var retries int
+	require.NoError(t, dbConn.Get(&retries, `SELECT COUNT(*) FROM scoring_retries`))
+	assert.Zero(t, retries, "scored and given up articles leave no retry count behind")
+}
+
+func TestScoreBackfillerPausesWhileProviderUnavailable(t *testing.T) {
+	restoreLogOutput()
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "backfill.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	_, err = dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('bbc', CURRENT_TIMESTAMP, 'https://example.com/p/1', 'Title', 'Body')`)
+	require.NoError(t, err)
+
+	b := NewScoreBackfiller(nil, dbConn, nil, 0, 2)
+	var queued []int64
+	b.enqueue = func(articleID int64, done func(error)) { queued = append(queued, articleID) }
+	reopens := time.Now().Add(time.Minute)
+	b.providerUnavailableUntil = func() (time.Time, bool) { return reopens, true }
+
+	assert.Equal(t, 0, b.RunOnce(), "nothing is queued while the circuit breaker is open")
+	assert.Empty(t, queued)
+	status := b.Status()
+	require.NotNil(t, status.PausedUntil)
+	assert.True(t, status.PausedUntil.Equal(reopens))
+
+	b.providerUnavailableUntil = func() (time.Time, bool) { return time.Time{}, false }
+	b.pausedUntil = time.Time{}
+	assert.Equal(t, 1, b.RunOnce())
+}
+
+func TestScoreBackfillAdminRoutes(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "backfill_routes.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	b := NewScoreBackfiller(nil, dbConn, nil, 0, 0)
+	router := gin.New()
+	router.GET("/api/admin/score-backfill", adminScoreBackfillStatusHandler(b))
+	router.POST("/api/admin/score-backfill/start", adminScoreBackfillStartHandler(b))
+	router.POST("/api/admin/score-backfill/stop", adminScoreBackfillStopHandler(b))
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := do(http.MethodPost, "/api/admin/score-backfill/start")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+
+	t.Setenv("NO_AUTO_ANALYZE", "")
+	b.enqueue = func(int64, func(error)) {}
+	w = do(http.MethodPost, "/api/admin/score-backfill/start")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"running":true`)
+	assert.Contains(t, do(http.MethodGet, "/api/admin/score-backfill").Body.String(), `"running":true`)
+
+	w = do(http.MethodPost, "/api/admin/score-backfill/stop")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"running":false`)
+}
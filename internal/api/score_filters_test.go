@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticlesScoreAndConfidenceFilters(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "filters.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	seed := []struct {
+		title      string
+		score      interface{}
+		confidence interface{}
+	}{
+		{"far left", -0.8, 0.9},
+		{"left unsure", -0.7, 0.3},
+		{"center", 0.0, 0.9},
+		{"far right", 0.9, 0.95},
+		{"unscored", nil, nil},
+	}
+	for i, a := range seed {
+		_, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score, confidence)
+			VALUES ('bbc', CURRENT_TIMESTAMP, ?, ?, 'Body', ?, ?)`, fmt.Sprintf("https://example.com/f/%d", i), a.title, a.score, a.confidence)
+		require.NoError(t, err)
+	}
+
+	router := gin.New()
+	router.GET("/api/articles", getArticlesHandler(dbConn))
+	titles := func(query string) []string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles?envelope=true&"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data       []ArticleResponse `json:"data"`
+			Pagination Pagination        `json:"pagination"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, len(resp.Data), resp.Pagination.Total, "the total honours the filters")
+		var out []string
+		for _, a := range resp.Data {
+			out = append(out, a.Title)
+		}
+		return out
+	}
+
+	assert.ElementsMatch(t, []string{"far left", "left unsure"}, titles("max_score=-0.5"))
+	assert.ElementsMatch(t, []string{"far right"}, titles("min_score=0.5"))
+	assert.ElementsMatch(t, []string{"far left", "center", "far right"}, titles("min_confidence=0.8"))
+	assert.ElementsMatch(t, []string{"far left"}, titles("max_score=-0.5&min_confidence=0.8"))
+
+	for _, query := range []string{"min_score=0.5&max_score=-0.5", "min_score=-2", "max_score=abc", "min_confidence=1.5"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
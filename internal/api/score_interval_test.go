@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBiasHandlerScoreInterval(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "interval.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	insertArticle := func(url string, scores map[string]float64) int64 {
+		res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content) VALUES ('bbc', CURRENT_TIMESTAMP, ?, 't', 'c')`, url)
+		require.NoError(t, err)
+		id, err := res.LastInsertId()
+		require.NoError(t, err)
+		for model, score := range scores {
+			_, err := dbConn.Exec(`INSERT INTO llm_scores (article_id, model, score, metadata) VALUES (?, ?, ?, '{}')`, id, model, score)
+			require.NoError(t, err)
+		}
+		invalidateArticleScoreCache(id)
+		return id
+	}
+
+	router := gin.New()
+	router.GET("/api/articles/:id/bias", biasHandler(dbConn))
+	bias := func(id int64) map[string]interface{} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/articles/%d/bias", id), nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp StandardResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.(map[string]interface{})
+	}
+
+	spread := bias(insertArticle("https://example.com/i/1", map[string]float64{
+		"ensemble": 0.1, "left-model": -0.3, "center-model": 0.1, "right-model": 0.5,
+	}))
+	assert.Less(t, spread["score_low"], 0.1)
+	assert.Greater(t, spread["score_high"], 0.1)
+	assert.Equal(t, true, spread["interval_reliable"])
+	assert.EqualValues(t, 3, spread["interval_models"])
+
+	single := bias(insertArticle("https://example.com/i/2", map[string]float64{"ensemble": 0.4, "left-model": 0.4}))
+	assert.InDelta(t, 0.4, single["score_low"], 1e-9)
+	assert.InDelta(t, 0.4, single["score_high"], 1e-9)
+	assert.Equal(t, false, single["interval_reliable"])
+
+	unscored := bias(insertArticle("https://example.com/i/3", nil))
+	assert.NotContains(t, unscored, "score_low")
+
+	// The neutral zone is reported whether or not the article is scored, and its edges
+	// are where the label mapping switches away from center
+	for _, resp := range []map[string]interface{}{spread, unscored} {
+		zone, ok := resp["neutral_zone"].(map[string]interface{})
+		require.True(t, ok, "neutral_zone is an object")
+		low, high := zone["low"].(float64), zone["high"].(float64)
+		assert.Equal(t, models.BiasLabelCenter, models.BiasLabel(low))
+		assert.Equal(t, models.BiasLabelCenter, models.BiasLabel(high))
+		assert.Equal(t, models.BiasLabelLeft, models.BiasLabel(low-0.01))
+		assert.Equal(t, models.BiasLabelRight, models.BiasLabel(high+0.01))
+	}
+}
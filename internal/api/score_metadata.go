@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// ScoreRow describes one stored score of an article without its metadata
+type ScoreRow struct {
+	ID        int64     `json:"id"`
+	Model     string    `json:"model"`
+	Score     float64   `json:"score"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ScoreMetadataResponse is one stored score with its metadata parsed. Metadata that is
+// not valid JSON is returned as stored in MetadataRaw.
+type ScoreMetadataResponse struct {
+	ScoreRow
+	ArticleID   int64       `json:"article_id"`
+	Metadata    interface{} `json:"metadata"`
+	MetadataRaw string      `json:"metadata_raw,omitempty"`
+}
+
+// getScoreID reads the :scoreID path parameter, responding with 400 when it is invalid
+func getScoreID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("scoreID"), 10, 64)
+	if err != nil || id < 1 {
+		RespondError(c, NewAppError(ErrValidation, "Invalid score ID (must be a positive integer)"))
+		return 0, false
+	}
+	return id, true
+}
+
+// listArticleScoresHandler handles GET /api/articles/:id/scores
+// @Summary List an article's stored scores
+// @Description Lists the score rows stored for an article, newest first: each model's score,
+// @Description the ensemble and any manual score, with their version and timestamp
+// @Tags Analysis
+// @Produce json
+// @Param id path integer true "Article ID"
+// @Success 200 {object} StandardResponse{data=[]ScoreRow}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/articles/{id}/scores [get]
+// @ID listArticleScores
+func listArticleScoresHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+		if _, err := db.FetchArticleByIDContext(c.Request.Context(), dbConn, id); err != nil {
+			if errors.Is(err, db.ErrArticleNotFound) {
+				RespondError(c, ErrArticleNotFound)
+				return
+			}
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch article"))
+			return
+		}
+
+		scores, err := db.FetchLLMScoresContext(c.Request.Context(), dbConn, id)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch scores"))
+			return
+		}
+		rows := make([]ScoreRow, 0, len(scores))
+		for _, s := range scores {
+			rows = append(rows, newScoreRow(s))
+		}
+		RespondSuccess(c, rows)
+	}
+}
+
+// scoreMetadataHandler handles GET /api/articles/:id/scores/:scoreID/metadata
+// @Summary Get a score's metadata
+// @Description Returns the parsed metadata stored with one of an article's scores, such as
+// @Description the raw model responses and ensemble sub-results, for remote debugging
+// @Tags Analysis
+// @Produce json
+// @Param id path integer true "Article ID"
+// @Param scoreID path integer true "Score ID from the article's score list"
+// @Success 200 {object} StandardResponse{data=ScoreMetadataResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/articles/{id}/scores/{scoreID}/metadata [get]
+// @ID getScoreMetadata
+func scoreMetadataHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+		scoreID, ok := getScoreID(c)
+		if !ok {
+			return
+		}
+
+		score, err := db.FetchLLMScore(dbConn, id, scoreID)
+		if err != nil {
+			if errors.Is(err, db.ErrScoreNotFound) {
+				RespondError(c, NewAppError(ErrNotFound, "Score not found for this article"))
+				return
+			}
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch score"))
+			return
+		}
+
+		resp := ScoreMetadataResponse{ScoreRow: newScoreRow(*score), ArticleID: score.ArticleID}
+		if raw := strings.TrimSpace(score.Metadata); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &resp.Metadata); err != nil {
+				resp.MetadataRaw = score.Metadata
+			}
+		}
+		RespondSuccess(c, resp)
+	}
+}
+
+func newScoreRow(s db.LLMScore) ScoreRow {
+	return ScoreRow{ID: s.ID, Model: s.Model, Score: s.Score, Version: s.Version, CreatedAt: s.CreatedAt}
+}
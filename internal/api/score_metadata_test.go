@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreMetadataHandlers(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "scores.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	insertArticle := func(url string) int64 {
+		res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+			VALUES ('src', CURRENT_TIMESTAMP, ?, 'title', 'content')`, url)
+		require.NoError(t, err)
+		id, err := res.LastInsertId()
+		require.NoError(t, err)
+		return id
+	}
+	articleID := insertArticle("https://example.com/scores-1")
+	otherID := insertArticle("https://example.com/scores-2")
+
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	scoreID, err := db.InsertLLMScore(dbConn, &db.LLMScore{
+		ArticleID: articleID, Model: "left-model", Score: -0.3, Version: 2, CreatedAt: base,
+		Metadata: `{"confidence": 0.8, "raw_response": "{\"score\": -0.3}"}`,
+	})
+	require.NoError(t, err)
+	_, err = db.InsertLLMScore(dbConn, &db.LLMScore{
+		ArticleID: articleID, Model: "ensemble", Score: -0.1, Version: 2, CreatedAt: base.Add(time.Minute), Metadata: `{}`,
+	})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/api/articles/:id/scores", listArticleScoresHandler(dbConn))
+	router.GET("/api/articles/:id/scores/:scoreID/metadata", scoreMetadataHandler(dbConn))
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get(fmt.Sprintf("/api/articles/%d/scores", articleID))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list struct {
+		Data []ScoreRow `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 2)
+	assert.Equal(t, "ensemble", list.Data[0].Model, "newest first")
+	assert.Equal(t, scoreID, list.Data[1].ID)
+	assert.Equal(t, 2, list.Data[1].Version)
+	assert.NotContains(t, w.Body.String(), "raw_response", "the list leaves out metadata")
+
+	w = get(fmt.Sprintf("/api/articles/%d/scores/%d/metadata", articleID, scoreID))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var meta struct {
+		Data struct {
+			ScoreMetadataResponse
+			Metadata map[string]interface{} `json:"metadata"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &meta))
+	assert.Equal(t, "left-model", meta.Data.Model)
+	assert.Equal(t, articleID, meta.Data.ArticleID)
+	assert.Equal(t, 0.8, meta.Data.Metadata["confidence"])
+	assert.Equal(t, `{"score": -0.3}`, meta.Data.Metadata["raw_response"])
+
+	// A score is only found under its own article
+	assert.Equal(t, http.StatusNotFound, get(fmt.Sprintf("/api/articles/%d/scores/%d/metadata", otherID, scoreID)).Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/articles/999/scores").Code)
+	assert.Equal(t, http.StatusBadRequest, get(fmt.Sprintf("/api/articles/%d/scores/abc/metadata", articleID)).Code)
+
+	w = get(fmt.Sprintf("/api/articles/%d/scores", otherID))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Empty(t, list.Data)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/gin-gonic/gin"
+)
+
+// maxScoreTextChars caps pasted content; longer text should be ingested as an article
+const maxScoreTextChars = 100000
+
+// ScoreTextRequest is text to score without storing it as an article
+// @Description Request body for scoring pasted text
+type ScoreTextRequest struct {
+	Content string `json:"content" example:"Full text of a press release..."` // Text to score
+	Title   string `json:"title,omitempty" example:"Press release"`           // Optional title, scored with the content
+}
+
+// scoreTextHandler handles POST /api/score-text
+// @Summary Score pasted text
+// @Description Runs the model ensemble on arbitrary text and returns the composite score, confidence and per-model results.
+// @Description Nothing is stored. Content longer than the configured limit is truncated as for articles.
+// @Tags LLM
+// @Accept json
+// @Produce json
+// @Param request body ScoreTextRequest true "Text to score"
+// @Success 200 {object} StandardResponse{data=llm.TextScoreResult}
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse "Request body too large"
+// @Failure 503 {object} ErrorResponse
+// @Router /api/score-text [post]
+// @ID scoreText
+func scoreTextHandler(llmClient *llm.LLMClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ScoreTextRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondError(c, NewAppError(ErrValidation, "Invalid JSON body"))
+			return
+		}
+		if strings.TrimSpace(req.Content) == "" {
+			RespondError(c, NewAppError(ErrValidation, "'content' is required"))
+			return
+		}
+		if n := len([]rune(req.Content)); n > maxScoreTextChars {
+			RespondError(c, NewAppError(ErrValidation, fmt.Sprintf("'content' is %d characters, the limit is %d", n, maxScoreTextChars)))
+			return
+		}
+		if llmClient == nil {
+			RespondError(c, NewAppError(ErrLLMService, "LLM client is not configured"))
+			return
+		}
+
+		result, err := llmClient.ScoreText(c.Request.Context(), req.Title, req.Content)
+		if err != nil {
+			RespondError(c, NewAppError(ErrLLMService, fmt.Sprintf("Scoring failed: %v", err)))
+			return
+		}
+		RespondSuccess(c, result)
+	}
+}
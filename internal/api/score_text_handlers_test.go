@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreTextHandler(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+	svc := llm.NewFixtureLLMService(llm.Fixture{
+		Default: llm.FixtureResponse{Score: 0.0, Confidence: 0.9},
+		Rules: []llm.FixtureRule{
+			{Model: "left-model", ContentContains: "Budget cuts", Responses: []llm.FixtureResponse{{Score: -0.6, Confidence: 0.8}}},
+			{Model: "right-model", Responses: []llm.FixtureResponse{{Error: "upstream down", StatusCode: 503}}},
+		},
+	})
+	client := llm.NewLLMClientWithService(nil, svc, &llm.CompositeScoreConfig{
+		Formula:          "average",
+		ConfidenceMethod: "count_valid",
+		MinScore:         -1.0,
+		MaxScore:         1.0,
+		HandleInvalid:    "ignore",
+		Models: []llm.ModelConfig{
+			{ModelName: "left-model", Perspective: "left", Weight: 1.0},
+			{ModelName: "center-model", Perspective: "center", Weight: 1.0},
+			{ModelName: "right-model", Perspective: "right", Weight: 1.0},
+		},
+	})
+
+	router := gin.New()
+	router.POST("/api/score-text", scoreTextHandler(client))
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/score-text", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"title": "Budget cuts announced", "content": "The ministry announced spending reductions."}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data llm.TextScoreResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.InDelta(t, -0.3, resp.Data.CompositeScore, 1e-9, "the title is scored with the content")
+	assert.Equal(t, "left", resp.Data.Label)
+	require.Len(t, resp.Data.Models, 3)
+	assert.Equal(t, llm.SelfCheckOK, resp.Data.Models[0].Status)
+	assert.Equal(t, "left", resp.Data.Models[0].Label)
+	assert.Equal(t, llm.SelfCheckError, resp.Data.Models[2].Status)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"title": "only a title"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post(`{"content": "`+strings.Repeat("x", maxScoreTextChars+1)+`"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post(`not json`).Code)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminSelfCheckHandler(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+	svc := llm.NewFixtureLLMService(llm.Fixture{Default: llm.FixtureResponse{Score: 0.2, Confidence: 0.9}})
+	client := llm.NewLLMClientWithService(nil, svc, &llm.CompositeScoreConfig{
+		Formula:          "average",
+		ConfidenceMethod: "count_valid",
+		MinScore:         -1.0,
+		MaxScore:         1.0,
+		HandleInvalid:    "ignore",
+		Models:           []llm.ModelConfig{{ModelName: "center-model", Perspective: "center", Weight: 1.0}},
+	})
+
+	router := gin.New()
+	router.POST("/api/admin/self-check", adminSelfCheckHandler(client))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/self-check", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Success bool                `json:"success"`
+		Data    llm.SelfCheckResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Data.Healthy)
+	assert.Equal(t, llm.KeyStatusValid, resp.Data.KeyStatus)
+	require.Len(t, resp.Data.Models, 1)
+	assert.Equal(t, llm.SelfCheckOK, resp.Data.Models[0].Status)
+
+	router = gin.New()
+	router.POST("/api/admin/self-check", adminSelfCheckHandler(nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/self-check", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
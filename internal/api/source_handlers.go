@@ -176,6 +176,8 @@ func createSourceHandler(dbConn *sqlx.DB) gin.HandlerFunc {
 			RespondError(c, NewAppError(ErrInternal, "Failed to create source"))
 			return
 		}
+		setAuditDetails(c, "source_id", id)
+		setAuditDetails(c, "source_name", source.Name)
 
 		// Fetch the created source to return complete data
 		createdSource, err := db.FetchSourceByID(dbConn, id)
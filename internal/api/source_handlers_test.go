@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"html/template"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -1277,6 +1278,11 @@ func TestParseSourceID(t *testing.T) {
 	}
 }
 
+// templateStubFuncs stands in for the server's template functions so the templates parse
+var templateStubFuncs = template.FuncMap{
+	"biasBar": func(score, confidence float64) template.HTML { return "" },
+}
+
 func TestAdminSourceFormHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -1284,6 +1290,7 @@ func TestAdminSourceFormHandler(t *testing.T) {
 	router := gin.New()
 
 	// Mock template loading - in real tests we'd need proper templates
+	router.SetFuncMap(templateStubFuncs)
 	router.LoadHTMLGlob("../../templates/**/*")
 
 	// We can't easily test the actual handler without a database connection
@@ -1314,6 +1321,7 @@ func TestAdminSourceFormHandlerWithID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	router := gin.New()
+	router.SetFuncMap(templateStubFuncs)
 	router.LoadHTMLGlob("../../templates/**/*")
 
 	router.GET("/htmx/sources/:id/edit", func(c *gin.Context) {
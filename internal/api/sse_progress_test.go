@@ -0,0 +1,252 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSSETestRouter(pm *llm.ProgressManager) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/llm/score-progress/:id", scoreProgressSSEHandler(llm.NewScoreManager(nil, nil, nil, pm)))
+	return router
+}
+
+// serveWithTimeout runs the request and fails the test if the handler hangs
+func serveWithTimeout(t *testing.T, router http.Handler, path string, timeout time.Duration) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatalf("SSE handler for %s did not return within %v", path, timeout)
+	}
+	return w
+}
+
+func TestSSEProgressUnknownJobExpiresImmediately(t *testing.T) {
+	restoreLogOutput()
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	router := newSSETestRouter(pm)
+
+	w := serveWithTimeout(t, router, "/api/llm/score-progress/42", time.Second)
+
+	assert.Contains(t, w.Body.String(), "event: error")
+	assert.Contains(t, w.Body.String(), sseJobExpiredMessage)
+}
+
+func TestSSEProgressFinishedJobClosesStream(t *testing.T) {
+	restoreLogOutput()
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	pm.SetProgress(7, &models.ProgressState{Status: llm.ProgressStatusError, Step: "Error", Percent: 100})
+	router := newSSETestRouter(pm)
+
+	w := serveWithTimeout(t, router, "/api/llm/score-progress/7", time.Second)
+
+	assert.Contains(t, w.Body.String(), `"status":"Error"`)
+	assert.NotContains(t, w.Body.String(), sseJobExpiredMessage)
+}
+
+// TestSSEProgressConnectDuringCleanup races SSE clients against the cleanup routine
+// for a job whose retention has lapsed. Every client must get a definitive answer,
+// either the terminal state or the expired event, without hanging.
+func TestSSEProgressConnectDuringCleanup(t *testing.T) {
+	restoreLogOutput()
+	pm := llm.NewProgressManager(time.Millisecond)
+	defer pm.Stop()
+	pm.SetProgress(9, &models.ProgressState{
+		Status:      llm.ProgressStatusSuccess,
+		Step:        "Complete",
+		Percent:     100,
+		LastUpdated: time.Now().Add(-time.Hour).Unix(),
+	})
+	router := newSSETestRouter(pm)
+
+	bodies := make(chan string, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/llm/score-progress/9", nil))
+			bodies <- w.Body.String()
+		}()
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("SSE clients hung while the job was being cleaned up")
+	}
+	close(bodies)
+
+	for body := range bodies {
+		gotTerminal := strings.Contains(body, `"status":"Success"`)
+		gotExpired := strings.Contains(body, "event: error") && strings.Contains(body, sseJobExpiredMessage)
+		assert.True(t, gotTerminal || gotExpired, "unexpected SSE body: %s", body)
+	}
+}
+
+func TestSSEProgressHeartbeatStopsAtTerminalState(t *testing.T) {
+	restoreLogOutput()
+	t.Setenv("SSE_HEARTBEAT_INTERVAL", "20ms")
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	pm.SetProgress(5, &models.ProgressState{Status: llm.ProgressStatusInProgress, Step: "Scoring", Percent: 10})
+	router := newSSETestRouter(pm)
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		pm.SetProgress(5, &models.ProgressState{Status: llm.ProgressStatusSuccess, Step: "Complete", Percent: 100})
+	}()
+
+	w := serveWithTimeout(t, router, "/api/llm/score-progress/5", 2*time.Second)
+	body := w.Body.String()
+
+	final := strings.Index(body, `"status":"Success"`)
+	assert.Greater(t, final, 0, "final event should be sent")
+	assert.Contains(t, body[:final], ":keepalive\n\n", "keepalive comments expected while in progress")
+	assert.NotContains(t, body[final:], ":keepalive", "no keepalive after the final event")
+}
+
+func TestSSEProgressResumeWithLastEventID(t *testing.T) {
+	restoreLogOutput()
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	router := newSSETestRouter(pm)
+
+	pm.SetProgress(3, &models.ProgressState{Status: llm.ProgressStatusInProgress, Step: "Scoring", Percent: 40})
+	_, seenID := pm.GetProgressWithEventID(3)
+
+	// The job completes while the client is disconnected
+	pm.SetProgress(3, &models.ProgressState{Status: llm.ProgressStatusSuccess, Step: "Complete", Percent: 100})
+	_, finalID := pm.GetProgressWithEventID(3)
+	assert.Greater(t, finalID, seenID)
+
+	t.Run("MissedTerminalEventIsReplayed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/llm/score-progress/3", nil)
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(seenID, 10))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		body := w.Body.String()
+		assert.Contains(t, body, fmt.Sprintf("id: %d\n", finalID))
+		assert.Contains(t, body, `"status":"Success"`)
+	})
+
+	t.Run("UpToDateClientGetsNothingNew", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/llm/score-progress/3", nil)
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(finalID, 10))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.NotContains(t, w.Body.String(), "event: progress")
+	})
+}
+
+func TestSSEProgressResumeInProgressJob(t *testing.T) {
+	restoreLogOutput()
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	router := newSSETestRouter(pm)
+
+	pm.SetProgress(4, &models.ProgressState{Status: llm.ProgressStatusInProgress, Step: "Scoring", Percent: 40})
+	_, seenID := pm.GetProgressWithEventID(4)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		pm.SetProgress(4, &models.ProgressState{Status: llm.ProgressStatusError, Step: "Error", Percent: 100})
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/llm/score-progress/4", nil)
+	req.Header.Set("Last-Event-ID", strconv.FormatInt(seenID, 10))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	assert.NotContains(t, body, `"percent":40`, "already delivered state should not be resent")
+	assert.Contains(t, body, `"status":"Error"`)
+	assert.Contains(t, body, fmt.Sprintf("id: %d\n", seenID+1))
+}
+
+func TestCancelReanalysisReportsCancelledOverSSE(t *testing.T) {
+	restoreLogOutput()
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := llm.NewScoreManager(nil, nil, nil, pm)
+	router := gin.New()
+	router.DELETE("/api/llm/reanalyze/:id", cancelReanalysisHandler(sm))
+	router.GET("/api/llm/score-progress/:id", scoreProgressSSEHandler(sm))
+
+	cancel := func() int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/llm/reanalyze/11", nil))
+		return w.Code
+	}
+	assert.Equal(t, http.StatusNotFound, cancel(), "no job in flight")
+
+	// Simulate the reanalysis goroutine started by reanalyzeHandler
+	ctx, done, err := sm.TryStartJob(context.Background(), 11)
+	assert.NoError(t, err)
+	pm.SetProgress(11, &models.ProgressState{Status: llm.ProgressStatusInProgress, Step: "Analyzing", Percent: 40})
+	go func() {
+		defer done()
+		<-ctx.Done()
+		sm.MarkCancelled(11)
+	}()
+
+	assert.Equal(t, http.StatusOK, cancel())
+
+	w := serveWithTimeout(t, router, "/api/llm/score-progress/11", 2*time.Second)
+	assert.Contains(t, w.Body.String(), `"status":"Cancelled"`)
+}
+
+func TestScoreProgressPollingMode(t *testing.T) {
+	pm := llm.NewProgressManager(time.Hour)
+	defer pm.Stop()
+	pm.SetProgress(7, &models.ProgressState{Status: "InProgress", Step: "Scoring", Percent: 40})
+	router := newSSETestRouter(pm)
+
+	w := serveWithTimeout(t, router, "/api/llm/score-progress/7?mode=poll", time.Second)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.Contains(t, w.Body.String(), `"status":"InProgress"`)
+	assert.Contains(t, w.Body.String(), `"event_id":`)
+
+	// Clients that only accept JSON get the snapshot without the query parameter
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/llm/score-progress/7", nil)
+	req.Header.Set("Accept", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"percent":40`)
+
+	w = serveWithTimeout(t, router, "/api/llm/score-progress/42?mode=poll", time.Second)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
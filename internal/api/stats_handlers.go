@@ -0,0 +1,39 @@
+package api
+
+import (
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// biasTrendHandler handles GET /api/stats/bias-trend
+// @Summary Get bias trend
+// @Description Returns the average composite score per day or week, optionally for a single source.
+// @Description Articles are bucketed by published date (falling back to creation date); empty periods are omitted.
+// @Tags Stats
+// @Produce json
+// @Param source query string false "Source name to filter by"
+// @Param interval query string false "Bucket size: day or week (default: day)"
+// @Success 200 {object} StandardResponse{data=[]db.BiasTrendPoint}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/stats/bias-trend [get]
+// @ID getBiasTrend
+func biasTrendHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		source := c.Query("source")
+		interval := c.DefaultQuery("interval", db.TrendIntervalDay)
+		if interval != db.TrendIntervalDay && interval != db.TrendIntervalWeek {
+			RespondError(c, NewAppError(ErrValidation, "Invalid 'interval' parameter, must be 'day' or 'week'"))
+			return
+		}
+
+		points, err := db.FetchBiasTrend(dbConn, source, interval)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to compute bias trend"))
+			return
+		}
+
+		RespondSuccess(c, points)
+	}
+}
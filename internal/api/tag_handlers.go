@@ -0,0 +1,194 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxTagsPerRequest caps how many tags one request can add to an article
+const maxTagsPerRequest = 20
+
+// ArticleTagsRequest lists editorial tags to add to an article
+// @Description Request body for tagging an article
+type ArticleTagsRequest struct {
+	Tags []string `json:"tags" example:"election coverage,fact check"` // Tag names; case and repeated spaces are ignored
+}
+
+// ArticleTagsResponse is an article's editorial tags in name order
+type ArticleTagsResponse struct {
+	ArticleID int64    `json:"article_id"`
+	Tags      []string `json:"tags"`
+}
+
+// normalizeTagParam normalizes a tag name from a request, answering 400 when it is not
+// a valid tag
+func normalizeTagParam(c *gin.Context, name string) (string, bool) {
+	tag, ok := db.NormalizeTag(name)
+	if !ok {
+		RespondError(c, NewAppError(ErrValidation, fmt.Sprintf(
+			"Invalid tag %q: use 1-%d letters, digits, spaces, hyphens or underscores", name, db.MaxTagLength)))
+		return "", false
+	}
+	return tag, true
+}
+
+// requireArticle answers 404 and false when the article does not exist
+func requireArticle(c *gin.Context, dbConn *sqlx.DB, articleID int64) bool {
+	if _, err := db.FetchArticleByIDContext(c.Request.Context(), dbConn, articleID); err != nil {
+		if errors.Is(err, db.ErrArticleNotFound) {
+			RespondError(c, ErrArticleNotFound)
+			return false
+		}
+		RespondError(c, WrapError(err, ErrInternal, "Failed to fetch article"))
+		return false
+	}
+	return true
+}
+
+// respondArticleTags writes an article's current tags
+func respondArticleTags(c *gin.Context, dbConn *sqlx.DB, articleID int64) {
+	tags, err := db.FetchArticleTags(dbConn, articleID)
+	if err != nil {
+		RespondError(c, WrapError(err, ErrInternal, "Failed to fetch article tags"))
+		return
+	}
+	RespondSuccess(c, ArticleTagsResponse{ArticleID: articleID, Tags: tags})
+}
+
+// listTagsHandler handles GET /api/tags
+// @Summary List tags
+// @Description Returns every editorial tag with its number of articles, most used first
+// @Tags Articles
+// @Produce json
+// @Success 200 {object} StandardResponse{data=[]db.TagCount}
+// @Failure 500 {object} ErrorResponse
+// @Router /api/tags [get]
+// @ID listTags
+func listTagsHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		counts, err := db.FetchTagCounts(dbConn)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to fetch tags"))
+			return
+		}
+		RespondSuccess(c, counts)
+	}
+}
+
+// getArticleTagsHandler handles GET /api/articles/:id/tags
+// @Summary Get article tags
+// @Description Returns an article's editorial tags in name order
+// @Tags Articles
+// @Produce json
+// @Param id path integer true "Article ID"
+// @Success 200 {object} StandardResponse{data=ArticleTagsResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/articles/{id}/tags [get]
+// @ID getArticleTags
+func getArticleTagsHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		articleID, ok := getValidArticleID(c)
+		if !ok || !requireArticle(c, dbConn, articleID) {
+			return
+		}
+		respondArticleTags(c, dbConn, articleID)
+	}
+}
+
+// addArticleTagsHandler handles POST /api/articles/:id/tags
+// @Summary Tag article
+// @Description Adds editorial tags to an article, creating new tags as needed. Tags are lowercased; ones the article already has are ignored.
+// @Tags Articles
+// @Accept json
+// @Produce json
+// @Param id path integer true "Article ID"
+// @Param request body ArticleTagsRequest true "Tags to add"
+// @Success 200 {object} StandardResponse{data=ArticleTagsResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/articles/{id}/tags [post]
+// @ID addArticleTags
+func addArticleTagsHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		articleID, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+
+		var req ArticleTagsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			RespondError(c, NewAppError(ErrValidation, "Invalid JSON body"))
+			return
+		}
+		if len(req.Tags) == 0 || len(req.Tags) > maxTagsPerRequest {
+			RespondError(c, NewAppError(ErrValidation, fmt.Sprintf("'tags' must list 1 to %d tags", maxTagsPerRequest)))
+			return
+		}
+		tags := make([]string, 0, len(req.Tags))
+		for _, name := range req.Tags {
+			tag, ok := normalizeTagParam(c, name)
+			if !ok {
+				return
+			}
+			tags = append(tags, tag)
+		}
+		if !requireArticle(c, dbConn, articleID) {
+			return
+		}
+
+		setAuditDetails(c, "tags", tags)
+		if err := db.AddArticleTags(dbConn, articleID, tags); err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to tag article"))
+			LogError(c, err, "addArticleTagsHandler: failed to tag article")
+			return
+		}
+		respondArticleTags(c, dbConn, articleID)
+	}
+}
+
+// removeArticleTagHandler handles DELETE /api/articles/:id/tags/:tag
+// @Summary Untag article
+// @Description Removes an editorial tag from an article
+// @Tags Articles
+// @Produce json
+// @Param id path integer true "Article ID"
+// @Param tag path string true "Tag name"
+// @Success 200 {object} StandardResponse{data=ArticleTagsResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse "Article not found or not tagged"
+// @Security ApiKeyAuth
+// @Router /api/articles/{id}/tags/{tag} [delete]
+// @ID removeArticleTag
+func removeArticleTagHandler(dbConn *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		articleID, ok := getValidArticleID(c)
+		if !ok {
+			return
+		}
+		tag, ok := normalizeTagParam(c, c.Param("tag"))
+		if !ok || !requireArticle(c, dbConn, articleID) {
+			return
+		}
+
+		setAuditDetails(c, "tag", tag)
+		removed, err := db.RemoveArticleTag(dbConn, articleID, tag)
+		if err != nil {
+			RespondError(c, WrapError(err, ErrInternal, "Failed to remove tag"))
+			LogError(c, err, "removeArticleTagHandler: failed to remove tag")
+			return
+		}
+		if !removed {
+			RespondError(c, NewAppError(ErrNotFound, fmt.Sprintf("Article %d is not tagged %q", articleID, tag)))
+			return
+		}
+		respondArticleTags(c, dbConn, articleID)
+	}
+}
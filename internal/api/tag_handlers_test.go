@@ -0,0 +1,93 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleTagEndpoints(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "tags.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	var ids []int64
+	for _, url := range []string{"https://example.com/a", "https://example.com/b"} {
+		res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+			VALUES ('cnn', CURRENT_TIMESTAMP, ?, 'title', 'content')`, url)
+		require.NoError(t, err)
+		id, err := res.LastInsertId()
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	router := gin.New()
+	router.GET("/api/tags", listTagsHandler(dbConn))
+	router.GET("/api/articles", getArticlesHandler(dbConn))
+	router.GET("/api/articles/:id/tags", getArticleTagsHandler(dbConn))
+	router.POST("/api/articles/:id/tags", addArticleTagsHandler(dbConn))
+	router.DELETE("/api/articles/:id/tags/:tag", removeArticleTagHandler(dbConn))
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	tagsPath := func(id int64) string { return fmt.Sprintf("/api/articles/%d/tags", id) }
+
+	t.Run("Validation", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, tagsPath(ids[0]), `{"tags":[]}`).Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, tagsPath(ids[0]), `{"tags":["<script>"]}`).Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodPost, tagsPath(999), `{"tags":["ok"]}`).Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/api/articles?tag=a%2Fb", "").Code)
+	})
+
+	t.Run("AddListRemove", func(t *testing.T) {
+		w := do(http.MethodPost, tagsPath(ids[0]), `{"tags":["Election  Coverage","fact-check"]}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data ArticleTagsResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []string{"election coverage", "fact-check"}, resp.Data.Tags)
+		require.Equal(t, http.StatusOK, do(http.MethodPost, tagsPath(ids[1]), `{"tags":["election coverage"]}`).Code)
+
+		w = do(http.MethodGet, "/api/articles?tag=fact-check", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+		var list struct {
+			Data []ArticleResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.Len(t, list.Data, 1)
+		assert.Equal(t, ids[0], list.Data[0].ArticleID)
+
+		w = do(http.MethodGet, "/api/tags", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var counts struct {
+			Data []db.TagCount `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &counts))
+		assert.Equal(t, []db.TagCount{{Name: "election coverage", Articles: 2}, {Name: "fact-check", Articles: 1}}, counts.Data)
+
+		assert.Equal(t, http.StatusOK, do(http.MethodDelete, tagsPath(ids[0])+"/Fact-Check", "").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, tagsPath(ids[0])+"/fact-check", "").Code)
+		w = do(http.MethodGet, tagsPath(ids[0]), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []string{"election coverage"}, resp.Data.Tags)
+	})
+}
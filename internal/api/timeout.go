@@ -0,0 +1,225 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRequestTimeout stays below the server's 60s write timeout so a slow request
+// still gets a response instead of a dropped connection
+const defaultRequestTimeout = 30 * time.Second
+
+// maxBufferedResponse is how much of a response is held back before it is written
+// through, so large bodies such as CSV lists are not kept in memory whole
+const maxBufferedResponse = 256 << 10
+
+// streamingRoutes are the server-sent event routes, which stay open for as long as
+// the client listens and are never timed out
+var streamingRoutes = map[string]bool{
+	"/api/llm/score-progress/:id":         true,
+	"/htmx/article/:id/reanalysis/stream": true,
+}
+
+// untimedRoutePrefix covers the admin routes. Maintenance, export and batch operations
+// there run for longer than a request timeout under their own deadlines, and their work
+// is done by the time they answer.
+const untimedRoutePrefix = "/api/admin/"
+
+// RequestTimeout cancels a request's context once it has run for too long and answers
+// with a 504 instead of whatever the handler wrote. Handlers pass the request context
+// to LLM calls, so cancelling it stops the outstanding work.
+type RequestTimeout struct {
+	timeout time.Duration
+}
+
+// NewRequestTimeout times requests out after timeout
+func NewRequestTimeout(timeout time.Duration) *RequestTimeout {
+	return &RequestTimeout{timeout: timeout}
+}
+
+// NewRequestTimeoutFromEnv builds the timeout from REQUEST_TIMEOUT, a Go duration;
+// "0" turns it off and returns nil
+func NewRequestTimeoutFromEnv() *RequestTimeout {
+	timeout := defaultRequestTimeout
+	if v := os.Getenv("REQUEST_TIMEOUT"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed < 0 {
+			log.Printf("[WARN] Invalid REQUEST_TIMEOUT %q, using default %v", v, defaultRequestTimeout)
+		} else if parsed == 0 {
+			return nil
+		} else {
+			timeout = parsed
+		}
+	}
+	return NewRequestTimeout(timeout)
+}
+
+// Middleware runs the rest of the chain under a deadline. The response is held back
+// until the handler returns, so a request the deadline cut short gets a clean 504 JSON
+// error rather than a partial body or the handler's cancellation error. A handler that
+// still finished with a successful response keeps it. Server-sent event streams and
+// admin routes are skipped, and a handler that flushes or writes more than
+// maxBufferedResponse is treated as streaming and written through. A nil timeout is a
+// no-op.
+func (rt *RequestTimeout) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rt == nil || streamingRoutes[c.FullPath()] || strings.HasPrefix(c.FullPath(), untimedRoutePrefix) ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), rt.timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		writer := &timeoutWriter{ResponseWriter: c.Writer, header: c.Writer.Header().Clone(), status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.streaming {
+			return
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !writer.succeeded() {
+			log.Printf("[WARN] %s %s timed out after %v", c.Request.Method, c.Request.URL.Path, rt.timeout)
+			RespondError(c, ErrRequestTimeout)
+			return
+		}
+		writer.commit()
+	}
+}
+
+// timeoutWriter buffers the status, headers and body until the handler returns, so
+// the middleware can still replace them with a timeout error
+type timeoutWriter struct {
+	gin.ResponseWriter
+	header    http.Header
+	status    int
+	buf       bytes.Buffer
+	statusSet bool
+	written   bool
+	streaming bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	if w.streaming {
+		return w.ResponseWriter.Header()
+	}
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if w.streaming {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 && !w.written {
+		w.status = code
+		w.statusSet = true
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.streaming {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	w.written = true
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.buf.Len()+len(b) > maxBufferedResponse {
+		w.stream()
+		return w.ResponseWriter.Write(b)
+	}
+	w.written = true
+	return w.buf.Write(b)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Status() int {
+	if w.streaming {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	if w.streaming {
+		return w.ResponseWriter.Size()
+	}
+	if !w.written {
+		return -1
+	}
+	return w.buf.Len()
+}
+
+func (w *timeoutWriter) Written() bool {
+	if w.streaming {
+		return w.ResponseWriter.Written()
+	}
+	return w.written
+}
+
+// succeeded reports whether the handler wrote a response that is not an error
+func (w *timeoutWriter) succeeded() bool {
+	return (w.written || w.statusSet) && w.status < http.StatusBadRequest
+}
+
+// Flush writes out what is buffered and passes everything after it straight through
+func (w *timeoutWriter) Flush() {
+	w.stream()
+	w.ResponseWriter.Flush()
+}
+
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.stream()
+	return w.ResponseWriter.Hijack()
+}
+
+func (w *timeoutWriter) stream() {
+	if !w.streaming {
+		w.commit()
+		w.streaming = true
+	}
+}
+
+// commit sends the buffered response
+func (w *timeoutWriter) commit() {
+	header := w.ResponseWriter.Header()
+	for key := range header {
+		if _, ok := w.header[key]; !ok {
+			header.Del(key)
+		}
+	}
+	for key, values := range w.header {
+		header[key] = values
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.written {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if w.buf.Len() > 0 {
+		if _, err := w.ResponseWriter.Write(w.buf.Bytes()); err != nil {
+			log.Printf("[WARN] Failed to write response: %v", err)
+		}
+	}
+	w.buf.Reset()
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(NewRequestTimeout(20 * time.Millisecond).Middleware())
+	router.GET("/fast", func(c *gin.Context) {
+		c.Header("X-Handler", "fast")
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+	router.GET("/slow", func(c *gin.Context) {
+		c.Header("X-Handler", "slow")
+		// Stands in for an LLM call that honours the request context
+		<-c.Request.Context().Done()
+		RespondError(c, WrapError(c.Request.Context().Err(), ErrLLMService, "LLM call failed"))
+	})
+	router.GET("/api/llm/score-progress/:id", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			c.String(http.StatusInternalServerError, "cancelled")
+		case <-time.After(50 * time.Millisecond):
+			c.String(http.StatusOK, "data: done\n\n")
+		}
+	})
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/fast")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "fast", w.Header().Get("X-Handler"))
+	assert.JSONEq(t, `{"ok": true}`, w.Body.String())
+
+	w = get("/slow")
+	require.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Empty(t, w.Header().Get("X-Handler"), "headers from the timed-out handler are dropped")
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Success)
+	assert.Equal(t, ErrTimeout, resp.Error.Code)
+
+	w = get("/api/llm/score-progress/1")
+	assert.Equal(t, http.StatusOK, w.Code, "SSE routes are not timed out")
+}
+
+func TestRequestTimeoutStreamsAfterFlush(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(NewRequestTimeout(time.Second).Middleware())
+	router.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain")
+		_, _ = c.Writer.Write([]byte("first "))
+		c.Writer.Flush()
+		_, _ = c.Writer.Write([]byte("second"))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "first second", w.Body.String())
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+}
+
+func TestRequestTimeoutKeepsFinishedWork(t *testing.T) {
+	restoreLogOutput()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(NewRequestTimeout(20 * time.Millisecond).Middleware())
+	finishLate := func(c *gin.Context) {
+		// Stands in for work that ignores the request context, like a VACUUM
+		time.Sleep(40 * time.Millisecond)
+		c.JSON(http.StatusOK, gin.H{"done": true})
+	}
+	router.POST("/late", finishLate)
+	router.POST("/api/admin/optimize-db", func(c *gin.Context) {
+		_, hasDeadline := c.Request.Context().Deadline()
+		assert.False(t, hasDeadline, "admin routes run without the request timeout")
+		finishLate(c)
+	})
+
+	for _, path := range []string{"/late", "/api/admin/optimize-db"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.JSONEq(t, `{"done": true}`, w.Body.String(), path)
+	}
+}
+
+func TestRequestTimeoutWritesLargeBodiesThrough(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := strings.Repeat("a,b,c\n", maxBufferedResponse/6+1)
+	w := httptest.NewRecorder()
+	router := gin.New()
+	router.Use(NewRequestTimeout(time.Second).Middleware())
+	router.GET("/export", func(c *gin.Context) {
+		c.Header("Content-Type", "text/csv")
+		c.Status(http.StatusOK)
+		for i := 0; i < len(body); i += 1024 {
+			_, _ = c.Writer.Write([]byte(body[i:min(i+1024, len(body))]))
+		}
+		assert.NotZero(t, w.Body.Len(), "the body is written before the handler returns")
+	})
+
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, body, w.Body.String())
+}
+
+func TestNewRequestTimeoutFromEnv(t *testing.T) {
+	restoreLogOutput()
+	t.Setenv("REQUEST_TIMEOUT", "0")
+	assert.Nil(t, NewRequestTimeoutFromEnv())
+
+	t.Setenv("REQUEST_TIMEOUT", "5s")
+	assert.Equal(t, 5*time.Second, NewRequestTimeoutFromEnv().timeout)
+
+	t.Setenv("REQUEST_TIMEOUT", "soon")
+	assert.Equal(t, defaultRequestTimeout, NewRequestTimeoutFromEnv().timeout)
+
+	var rt *RequestTimeout
+	router := gin.New()
+	router.Use(rt.Middleware())
+	router.GET("/", func(c *gin.Context) {
+		_, hasDeadline := c.Request.Context().Deadline()
+		assert.False(t, hasDeadline)
+		c.Status(http.StatusNoContent)
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(context.Background()))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetArticlesPageUnderstandsBothShapes(t *testing.T) {
+	envelope := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("envelope"))
+		w.Header().Set("Content-Type", "application/json")
+		if envelope {
+			fmt.Fprint(w, `{"success":true,"data":[{"article_id":1},{"article_id":2}],"pagination":{"total":7,"limit":2,"offset":2,"has_more":true}}`)
+			return
+		}
+		// An older server ignores envelope=true and returns the bare list
+		fmt.Fprint(w, `{"success":true,"data":[{"article_id":1},{"article_id":2}]}`)
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, WithRetryConfig(0, time.Millisecond))
+
+	page, err := client.GetArticlesPage(context.Background(), ArticlesParams{Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.Len(t, page.Articles, 2)
+	assert.Equal(t, Pagination{Total: 7, Limit: 2, Offset: 2, HasMore: true}, page.Pagination)
+
+	envelope = false
+	page, err = client.GetArticlesPage(context.Background(), ArticlesParams{Limit: 2, Offset: 4})
+	require.NoError(t, err)
+	assert.Len(t, page.Articles, 2)
+	assert.Equal(t, Pagination{Total: 7, Limit: 2, Offset: 4, HasMore: true, Estimated: true}, page.Pagination)
+}
+
+func TestGetArticlesSendsScoreFilters(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"data":[]}`)
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, WithRetryConfig(0, time.Millisecond))
+	minScore, minConfidence := 0.5, 0.8
+	_, err := client.GetArticles(context.Background(), ArticlesParams{MinScore: &minScore, MinConfidence: &minConfidence})
+	require.NoError(t, err)
+	assert.Contains(t, query, "min_score=0.5")
+	assert.Contains(t, query, "min_confidence=0.8")
+	assert.NotContains(t, query, "max_score")
+}
+
+func TestGetArticlesSendsDateFilters(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"data":[]}`)
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, WithRetryConfig(0, time.Millisecond))
+	after := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	_, err := client.GetArticles(context.Background(), ArticlesParams{PublishedAfter: &after, FallbackToIngested: true})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-01T00:00:00Z", query.Get("published_after"))
+	assert.Equal(t, "true", query.Get("fallback_to_ingested"))
+	assert.False(t, query.Has("ingested_before"))
+}
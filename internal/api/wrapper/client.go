@@ -2,6 +2,7 @@ package client
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -44,18 +45,28 @@ type Config struct {
 	MaxRetries int
 	RetryDelay time.Duration
 	UserAgent  string
+	// Transport replaces the HTTP transport, e.g. for connection pool tuning; nil keeps
+	// the default
+	Transport http.RoundTripper
+	// ProgressPolling makes WatchProgress poll for progress instead of streaming it, for
+	// networks whose proxies break server-sent events. Streaming falls back to polling
+	// on its own when the stream cannot be opened or resumed. PollInterval is the time
+	// between polls.
+	ProgressPolling bool
+	PollInterval    time.Duration
 }
 
 // NewAPIClient creates a new wrapped API client
 func NewAPIClient(baseURL string, opts ...ConfigOption) *APIClient {
 	// Default configuration
 	cfg := &Config{
-		BaseURL:    baseURL,
-		Timeout:    30 * time.Second,
-		CacheTTL:   30 * time.Second,
-		MaxRetries: 3,
-		RetryDelay: time.Second,
-		UserAgent:  "NewsBalancer-APIClient/1.0.0",
+		BaseURL:      baseURL,
+		Timeout:      30 * time.Second,
+		CacheTTL:     30 * time.Second,
+		MaxRetries:   3,
+		RetryDelay:   time.Second,
+		UserAgent:    "NewsBalancer-APIClient/1.0.0",
+		PollInterval: time.Second,
 	}
 
 	// Apply options
@@ -95,6 +106,7 @@ func NewAPIClient(baseURL string, opts ...ConfigOption) *APIClient {
 
 	rawCfg.HTTPClient.Timeout = cfg.Timeout
 	rawCfg.UserAgent = cfg.UserAgent
+	rawCfg.Transport = cfg.Transport
 
 	// Create raw client
 	rawClient := rawclient.NewAPIClient(rawCfg)
@@ -109,6 +121,13 @@ func NewAPIClient(baseURL string, opts ...ConfigOption) *APIClient {
 // ConfigOption is a function that modifies the Config
 type ConfigOption func(*Config)
 
+// WithTransport sets the HTTP transport, e.g. rawclient.TransportConfig{...}.NewTransport()
+func WithTransport(transport http.RoundTripper) ConfigOption {
+	return func(c *Config) {
+		c.Transport = transport
+	}
+}
+
 // WithTimeout sets the request timeout
 func WithTimeout(timeout time.Duration) ConfigOption {
 	return func(c *Config) {
@@ -131,6 +150,25 @@ func WithRetryConfig(maxRetries int, delay time.Duration) ConfigOption {
 	}
 }
 
+// WithProgressPolling makes WatchProgress poll for progress every interval instead of
+// streaming it; a non-positive interval keeps the default of one second
+func WithProgressPolling(interval time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.ProgressPolling = true
+		if interval > 0 {
+			c.PollInterval = interval
+		}
+	}
+}
+
+// WithPollInterval sets the time between progress polls, whether chosen with
+// WithProgressPolling or used as a fallback from streaming
+func WithPollInterval(interval time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.PollInterval = interval
+	}
+}
+
 // WithUserAgent sets the user agent string
 func WithUserAgent(userAgent string) ConfigOption {
 	return func(c *Config) {
@@ -253,3 +253,27 @@ func BenchmarkAPIClient_HTTPPerformance(b *testing.B) {
 		}
 	})
 }
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// TestAPIClient_CustomTransport verifies requests go through the configured transport
+func TestAPIClient_CustomTransport(t *testing.T) {
+	var calls []string
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls = append(calls, r.URL.Path)
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", "application/json")
+		_, _ = rec.WriteString(`{"success": true, "data": [{"article_id": 7, "Title": "Via transport"}]}`)
+		return rec.Result(), nil
+	})
+
+	client := NewAPIClient("http://news.invalid", WithTransport(transport))
+	articles, err := client.GetArticles(context.Background(), ArticlesParams{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, "Via transport", articles[0].Title)
+	assert.Equal(t, []string{"/api/articles"}, calls)
+}
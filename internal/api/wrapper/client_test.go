@@ -218,6 +218,29 @@ func TestGetArticle(t *testing.T) {
 		apiErr, ok := err.(APIError)
 		assert.True(t, ok)
 		assert.Equal(t, 404, apiErr.StatusCode)
+		assert.Equal(t, "not_found", apiErr.Code)
+		assert.Equal(t, "Article not found", apiErr.Message)
+	})
+
+	t.Run("Error envelope with details", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"success": false, "error": {"code": "no_valid_scores",
+				"message": "No valid model scores to build a composite from",
+				"details": {"reason": "no valid scores found", "correlation_id": "req-7"}}}`))
+		})
+
+		client, server := newTestClientWithMockServer(handler)
+		defer server.Close()
+
+		_, err := client.GetArticle(context.Background(), 5)
+		apiErr, ok := err.(APIError)
+		require.True(t, ok, "got %T: %v", err, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+		assert.Equal(t, "no_valid_scores", apiErr.Code)
+		assert.Equal(t, "req-7", apiErr.RequestID)
+		assert.Equal(t, map[string]interface{}{"reason": "no valid scores found", "correlation_id": "req-7"}, apiErr.Details)
 	})
 }
 
@@ -11,7 +11,7 @@ import (
 // GetArticles retrieves articles with caching
 func (c *APIClient) GetArticles(ctx context.Context, params ArticlesParams) ([]Article, error) {
 	// Build cache key from parameters
-	cacheKey := buildCacheKey("articles", params.Source, params.Leaning, params.Limit, params.Offset)
+	cacheKey := params.cacheKey("articles")
 
 	// Check cache first
 	if cached, found := c.getCached(cacheKey); found {
@@ -28,14 +28,7 @@ func (c *APIClient) GetArticles(ctx context.Context, params ArticlesParams) ([]A
 			time.Sleep(delay)
 		}
 
-		rawParams := rawclient.ArticlesParams{
-			Source:  params.Source,
-			Leaning: params.Leaning,
-			Limit:   params.Limit,
-			Offset:  params.Offset,
-		}
-
-		rawArticles, err := c.raw.ArticlesAPI.GetArticles(ctx, rawParams)
+		rawArticles, err := c.raw.ArticlesAPI.GetArticles(ctx, params.rawParams())
 		if err != nil {
 			lastErr = c.translateError(err)
 			continue
@@ -56,6 +49,43 @@ func (c *APIClient) GetArticles(ctx context.Context, params ArticlesParams) ([]A
 	return articles, nil
 }
 
+// GetArticlesPage retrieves a page of articles with pagination metadata, with caching
+func (c *APIClient) GetArticlesPage(ctx context.Context, params ArticlesParams) (*ArticlesPage, error) {
+	cacheKey := params.cacheKey("articles_page")
+
+	// Check cache first
+	if cached, found := c.getCached(cacheKey); found {
+		if page, ok := cached.(*ArticlesPage); ok {
+			return page, nil
+		}
+	}
+
+	rawParams := params.rawParams()
+
+	var lastErr error
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := calculateWrapperRetryDelay(attempt - 1)
+			time.Sleep(delay)
+		}
+
+		rawArticles, rawPagination, err := c.raw.ArticlesAPI.GetArticlesPage(ctx, rawParams)
+		if err != nil {
+			lastErr = c.translateError(err)
+			continue
+		}
+
+		page := &ArticlesPage{
+			Articles:   convertArticles(rawArticles),
+			Pagination: convertPagination(rawPagination, params, len(rawArticles)),
+		}
+		c.setCached(cacheKey, page)
+		return page, nil
+	}
+
+	return nil, lastErr
+}
+
 // GetArticle retrieves a single article with caching
 func (c *APIClient) GetArticle(ctx context.Context, id int64) (*Article, error) {
 	cacheKey := buildCacheKey("article", id)
@@ -280,6 +310,32 @@ func (c *APIClient) ReanalyzeArticle(ctx context.Context, id int64, req *ManualS
 	return "", lastErr
 }
 
+// Reanalyze queues reanalysis of an article. Follow it with WatchProgress(ctx, job.JobID).
+func (c *APIClient) Reanalyze(ctx context.Context, articleID int64) (*ReanalyzeJob, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := calculateWrapperRetryDelay(attempt - 1)
+			time.Sleep(delay)
+		}
+		result, err := c.raw.LLMApi.StartReanalysis(ctx, articleID)
+		if err != nil {
+			lastErr = c.translateError(err)
+			continue
+		}
+
+		// Invalidate related caches
+		c.invalidateArticleCache(articleID)
+		return &ReanalyzeJob{
+			JobID:     articleID,
+			ArticleID: articleID,
+			Status:    result.Status,
+		}, nil
+	}
+
+	return nil, lastErr
+}
+
 // GetFeedHealth retrieves feed health status with caching
 func (c *APIClient) GetFeedHealth(ctx context.Context) (FeedHealth, error) {
 	cacheKey := "feed_health"
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// GetValidationMetrics retrieves daily label counts and confidence with caching
+func (c *APIClient) GetValidationMetrics(ctx context.Context) ([]ValidationMetric, error) {
+	return getMetrics(ctx, c, "metrics:validation", c.raw.MetricsApi.GetValidationMetrics)
+}
+
+// GetFeedbackSummary retrieves daily feedback counts per category with caching
+func (c *APIClient) GetFeedbackSummary(ctx context.Context) ([]FeedbackSummary, error) {
+	return getMetrics(ctx, c, "metrics:feedback", c.raw.MetricsApi.GetFeedbackSummary)
+}
+
+// GetUncertaintyRates retrieves the daily share of low-confidence scores with caching
+func (c *APIClient) GetUncertaintyRates(ctx context.Context) ([]UncertaintyRate, error) {
+	return getMetrics(ctx, c, "metrics:uncertainty", c.raw.MetricsApi.GetUncertaintyRates)
+}
+
+// GetDisagreements retrieves articles whose feedback disagrees with caching
+func (c *APIClient) GetDisagreements(ctx context.Context) ([]Disagreement, error) {
+	return getMetrics(ctx, c, "metrics:disagreements", c.raw.MetricsApi.GetDisagreements)
+}
+
+// GetOutliers retrieves articles whose model scores spread widely with caching
+func (c *APIClient) GetOutliers(ctx context.Context) ([]OutlierScore, error) {
+	return getMetrics(ctx, c, "metrics:outliers", c.raw.MetricsApi.GetOutliers)
+}
+
+// getMetrics calls fetch with the client's retries and caches the result under key
+func getMetrics[T any](ctx context.Context, c *APIClient, key string, fetch func(context.Context) ([]T, error)) ([]T, error) {
+	if cached, found := c.getCached(key); found {
+		if rows, ok := cached.([]T); ok {
+			return rows, nil
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(calculateWrapperRetryDelay(attempt - 1))
+		}
+		rows, err := fetch(ctx)
+		if err != nil {
+			lastErr = c.translateError(err)
+			continue
+		}
+		if rows == nil {
+			rows = []T{}
+		}
+		c.setCached(key, rows)
+		return rows, nil
+	}
+	return nil, lastErr
+}
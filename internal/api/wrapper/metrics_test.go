@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMethods(t *testing.T) {
+	var uncertaintyCalls, outlierCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/metrics/validation":
+			_, _ = w.Write([]byte(`[{"day":"2024-05-01","label":"left","label_count":3,"avg_confidence":0.8}]`))
+		case "/metrics/feedback":
+			_, _ = w.Write([]byte(`[{"day":"2024-05-01","category":"agree","feedback_count":2}]`))
+		case "/metrics/uncertainty":
+			atomic.AddInt32(&uncertaintyCalls, 1)
+			_, _ = w.Write([]byte(`[{"day":"2024-05-01","low_confidence_ratio":0.25}]`))
+		case "/metrics/disagreements":
+			_, _ = w.Write([]byte(`null`))
+		case "/metrics/outliers":
+			if atomic.AddInt32(&outlierCalls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"error":"busy"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"article_id":7,"max_score":0.9,"min_score":-0.8,"score_range":1.7,"score_count":3}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, WithRetryConfig(2, time.Millisecond))
+	ctx := context.Background()
+
+	validation, err := client.GetValidationMetrics(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []ValidationMetric{{Day: "2024-05-01", Label: "left", LabelCount: 3, AvgConfidence: 0.8}}, validation)
+
+	feedback, err := client.GetFeedbackSummary(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, feedback[0].FeedbackCount)
+
+	for i := 0; i < 2; i++ {
+		rates, err := client.GetUncertaintyRates(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0.25, rates[0].LowConfidenceRate)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&uncertaintyCalls), "the second call is served from the cache")
+
+	disagreements, err := client.GetDisagreements(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, disagreements)
+	assert.Empty(t, disagreements)
+
+	outliers, err := client.GetOutliers(ctx)
+	require.NoError(t, err, "a failed attempt is retried")
+	assert.Equal(t, int64(7), outliers[0].ArticleID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&outlierCalls))
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -22,6 +23,9 @@ type Article struct {
 	CompositeScore float64   `json:"composite_score,omitempty"`
 	Confidence     float64   `json:"confidence,omitempty"`
 	ScoreSource    string    `json:"score_source,omitempty"`
+	ImageURL       string    `json:"image_url,omitempty"`
+	Authors        []string  `json:"authors,omitempty"`
+	Categories     []string  `json:"categories,omitempty"`
 	// BiasLabel field removed - not present in database
 }
 
@@ -30,6 +34,93 @@ type ArticlesParams struct {
 	Leaning string `json:"leaning,omitempty"`
 	Limit   int    `json:"limit,omitempty"`
 	Offset  int    `json:"offset,omitempty"`
+	// Optional author and feed category, matched ignoring case
+	Author   string `json:"author,omitempty"`
+	Category string `json:"category,omitempty"`
+	// Optional bounds on the composite score ([-1, 1]) and its confidence ([0, 1])
+	MinScore      *float64 `json:"min_score,omitempty"`
+	MaxScore      *float64 `json:"max_score,omitempty"`
+	MinConfidence *float64 `json:"min_confidence,omitempty"`
+	// Optional date bounds: After is inclusive, Before exclusive. FallbackToIngested
+	// applies the published bounds to the ingestion date of articles without one.
+	PublishedAfter     *time.Time `json:"published_after,omitempty"`
+	PublishedBefore    *time.Time `json:"published_before,omitempty"`
+	IngestedAfter      *time.Time `json:"ingested_after,omitempty"`
+	IngestedBefore     *time.Time `json:"ingested_before,omitempty"`
+	FallbackToIngested bool       `json:"fallback_to_ingested,omitempty"`
+}
+
+// rawParams converts the parameters for the generated client
+func (p ArticlesParams) rawParams() rawclient.ArticlesParams {
+	return rawclient.ArticlesParams{
+		Source:        p.Source,
+		Leaning:       p.Leaning,
+		Author:        p.Author,
+		Category:      p.Category,
+		Limit:         p.Limit,
+		Offset:        p.Offset,
+		MinScore:      p.MinScore,
+		MaxScore:      p.MaxScore,
+		MinConfidence: p.MinConfidence,
+
+		PublishedAfter:     p.PublishedAfter,
+		PublishedBefore:    p.PublishedBefore,
+		IngestedAfter:      p.IngestedAfter,
+		IngestedBefore:     p.IngestedBefore,
+		FallbackToIngested: p.FallbackToIngested,
+	}
+}
+
+// cacheKey identifies the parameters in the response cache. Optional filters are
+// appended only when set so unfiltered queries keep their original key.
+func (p ArticlesParams) cacheKey(prefix string) string {
+	key := buildCacheKey(prefix, p.Source, p.Leaning, p.Limit, p.Offset)
+	filters := []string{
+		formatOptionalFloat(p.MinScore), formatOptionalFloat(p.MaxScore), formatOptionalFloat(p.MinConfidence),
+		formatOptionalTime(p.PublishedAfter), formatOptionalTime(p.PublishedBefore),
+		formatOptionalTime(p.IngestedAfter), formatOptionalTime(p.IngestedBefore),
+		p.Author, p.Category,
+	}
+	if p.FallbackToIngested {
+		filters = append(filters, "fallback")
+	}
+	for _, f := range filters {
+		if f != "" {
+			return key + ":" + strings.Join(filters, ":")
+		}
+	}
+	return key
+}
+
+func formatOptionalTime(v *time.Time) string {
+	if v == nil {
+		return ""
+	}
+	return v.Format(time.RFC3339)
+}
+
+func formatOptionalFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// Pagination describes a page of a list response
+type Pagination struct {
+	Total   int  `json:"total"`
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"has_more"`
+	// Estimated is set when the server returned a bare list; Total and HasMore are then
+	// inferred from whether a full page came back
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// ArticlesPage is a page of articles with its pagination metadata
+type ArticlesPage struct {
+	Articles   []Article  `json:"articles"`
+	Pagination Pagination `json:"pagination"`
 }
 
 type CreateArticleRequest struct {
@@ -66,6 +157,73 @@ type ManualScoreRequest struct {
 
 type FeedHealth map[string]bool
 
+// Metrics rows, as served by the /metrics endpoints
+type (
+	ValidationMetric = rawclient.ValidationMetric
+	FeedbackSummary  = rawclient.FeedbackSummary
+	UncertaintyRate  = rawclient.UncertaintyRate
+	Disagreement     = rawclient.Disagreement
+	OutlierScore     = rawclient.OutlierScore
+)
+
+// ReanalyzeJob identifies a queued reanalysis. Progress is tracked per article, so
+// JobID equals ArticleID.
+type ReanalyzeJob struct {
+	JobID     int64  `json:"job_id"`
+	ArticleID int64  `json:"article_id"`
+	Status    string `json:"status,omitempty"`
+}
+
+// Progress is one progress event of a reanalysis job
+type Progress struct {
+	EventID      int64    `json:"-"`
+	Status       string   `json:"status,omitempty"`
+	Step         string   `json:"step,omitempty"`
+	Percent      int      `json:"percent,omitempty"`
+	Message      string   `json:"message,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	ErrorDetails string   `json:"error_details,omitempty"`
+	FinalScore   *float64 `json:"final_score,omitempty"`
+	LastUpdated  int64    `json:"last_updated,omitempty"`
+	// QueuePosition is the place in the reanalysis queue while the job waits for a slot
+	QueuePosition int `json:"queue_position,omitempty"`
+	// Change is set on success and compares the new score with the previous one
+	Change *ScoreChange `json:"change,omitempty"`
+}
+
+// ScoreSnapshot is a composite score with its confidence and bias label
+type ScoreSnapshot struct {
+	Score      float64 `json:"score"`
+	Confidence float64 `json:"confidence"`
+	Label      string  `json:"label"`
+}
+
+// ScoreChange describes how a reanalysis moved an article's score. Previous is nil
+// when the article had no score before.
+type ScoreChange struct {
+	Previous *ScoreSnapshot `json:"previous"`
+	Current  ScoreSnapshot  `json:"current"`
+	Delta    *float64       `json:"delta,omitempty"`
+}
+
+// Terminal progress statuses
+const (
+	ProgressStatusSuccess   = "Success"
+	ProgressStatusError     = "Error"
+	ProgressStatusCancelled = "Cancelled"
+	// ProgressStatusInterrupted ends a job stopped by a server shutdown; it is retried later
+	ProgressStatusInterrupted = "Interrupted"
+)
+
+// Done reports whether the event is the last one of its job
+func (p Progress) Done() bool {
+	switch p.Status {
+	case ProgressStatusSuccess, ProgressStatusError, ProgressStatusCancelled, ProgressStatusInterrupted, "Complete", "Skipped":
+		return true
+	}
+	return false
+}
+
 // convertArticles converts raw client articles to wrapper articles
 func convertArticles(rawArticles []rawclient.Article) []Article {
 	articles := make([]Article, len(rawArticles))
@@ -89,11 +247,40 @@ func convertArticle(raw *rawclient.Article) *Article {
 		PubDate:        raw.PubDate,
 		CreatedAt:      raw.CreatedAt,
 		CompositeScore: raw.CompositeScore,
-		Confidence:     raw.Confidence, ScoreSource: raw.ScoreSource,
+		Confidence:     raw.Confidence,
+		ScoreSource:    raw.ScoreSource,
+		ImageURL:       raw.ImageURL,
+		Authors:        raw.Authors,
+		Categories:     raw.Categories,
 		// BiasLabel removed - not present in database
 	}
 }
 
+// convertPagination converts raw pagination, estimating it for servers that return
+// a bare list
+func convertPagination(raw *rawclient.Pagination, params ArticlesParams, returned int) Pagination {
+	if raw != nil {
+		return Pagination{
+			Total:   raw.Total,
+			Limit:   raw.Limit,
+			Offset:  raw.Offset,
+			HasMore: raw.HasMore,
+		}
+	}
+	hasMore := params.Limit > 0 && returned == params.Limit
+	total := params.Offset + returned
+	if hasMore {
+		total++
+	}
+	return Pagination{
+		Total:     total,
+		Limit:     params.Limit,
+		Offset:    params.Offset,
+		HasMore:   hasMore,
+		Estimated: true,
+	}
+}
+
 // convertScoreResponse converts raw score response to wrapper score response
 func convertScoreResponse(raw *rawclient.ScoreResponse) *ScoreResponse {
 	if raw == nil {
@@ -123,15 +310,26 @@ func (c *APIClient) translateError(err error) error {
 		return nil
 	}
 
-	// Check for specific API errors defined in the schema first.
-	// These are errors that the server returned in a structured format and were successfully decoded.
-	if specificAPIErr, ok := err.(*rawclient.APIError); ok {
-		return APIError{
-			StatusCode: determineStatusCode(specificAPIErr.Code),
-			Code:       specificAPIErr.Code,
-			Message:    specificAPIErr.Message,
-			Details:    fmt.Sprintf("%v", specificAPIErr.Details), // Ensure details are stringified
+	// Check for the server's error envelope first: these errors carry a stable code,
+	// the response status and optional structured details
+	var envelope rawclient.APIError
+	if errors.As(err, &envelope) {
+		statusCode := envelope.StatusCode
+		if statusCode == 0 {
+			statusCode = determineStatusCode(envelope.Code)
+		}
+		apiErr := APIError{
+			StatusCode: statusCode,
+			Code:       envelope.Code,
+			Message:    envelope.Message,
+			Details:    envelope.Details,
+		}
+		if details, ok := envelope.Details.(map[string]interface{}); ok {
+			if id, ok := details["correlation_id"].(string); ok {
+				apiErr.RequestID = id
+			}
 		}
+		return apiErr
 	}
 
 	// Check for JSON unmarshalling errors, which might indicate a mismatch
@@ -224,12 +422,16 @@ func determineStatusCode(code string) int {
 		return http.StatusForbidden
 	case "rate_limit", "too_many_requests":
 		return http.StatusTooManyRequests
-	case "llm_service", "service_unavailable":
+	case "llm_service", "llm_service_error", "service_unavailable":
 		return http.StatusServiceUnavailable
 	case "timeout":
 		return http.StatusRequestTimeout
-	case "conflict", "duplicate_url":
+	case "conflict", "conflict_error", "duplicate_url":
 		return http.StatusConflict
+	case "not_acceptable":
+		return http.StatusNotAcceptable
+	case "no_valid_scores", "zero_confidence", "insufficient_models":
+		return http.StatusUnprocessableEntity
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSSELineSize bounds a single line of the progress stream
+const maxSSELineSize = 1024 * 1024
+
+// WatchProgress streams the progress events of a reanalysis job. The channel is closed
+// after a terminal event (see Progress.Done) or when ctx is done. When the stream cannot
+// be opened, or a dropped stream cannot be resumed, the job is followed by polling
+// instead; with Config.ProgressPolling it is only polled. An Error event is sent before
+// closing when polling fails too. Connecting, resuming and polling use the client's
+// retry configuration.
+func (c *APIClient) WatchProgress(ctx context.Context, jobID int64) (<-chan Progress, error) {
+	if c.cfg.ProgressPolling {
+		return c.pollProgressEvents(ctx, jobID)
+	}
+
+	var body io.ReadCloser
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 && !sleepContext(ctx, calculateWrapperRetryDelay(attempt-1)) {
+			return nil, c.translateError(ctx.Err())
+		}
+		var err error
+		if body, err = c.openProgressStream(ctx, jobID, 0); err == nil {
+			break
+		}
+	}
+	if body == nil {
+		if ctx.Err() != nil {
+			return nil, c.translateError(ctx.Err())
+		}
+		return c.pollProgressEvents(ctx, jobID)
+	}
+
+	events := make(chan Progress)
+	go c.watchProgress(ctx, jobID, body, events)
+	return events, nil
+}
+
+// openProgressStream opens the job's event stream. A response that is not an event
+// stream, as sent by proxies that do not pass SSE through, is an error.
+func (c *APIClient) openProgressStream(ctx context.Context, jobID, lastEventID int64) (io.ReadCloser, error) {
+	resp, err := c.raw.LLMApi.StreamScoreProgress(ctx, jobID, lastEventID)
+	if err != nil {
+		return nil, c.translateError(err)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/event-stream") {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("progress stream unavailable: got content type %q", contentType)
+	}
+	return resp.Body, nil
+}
+
+// watchProgress forwards events from the stream, resuming it from the last event ID
+// when the connection drops before the job finishes and polling once it cannot be
+func (c *APIClient) watchProgress(ctx context.Context, jobID int64, body io.ReadCloser, events chan<- Progress) {
+	defer close(events)
+
+	var lastEventID int64
+	retries := 0
+	for {
+		received, done, _ := readProgressEvents(ctx, body, events, &lastEventID)
+		_ = body.Close()
+		if done || ctx.Err() != nil {
+			return
+		}
+		if received {
+			retries = 0
+		}
+
+		// The stream ended before a terminal event
+		body = nil
+		for body == nil {
+			if retries >= c.cfg.MaxRetries {
+				c.pollProgress(ctx, jobID, events, lastEventID)
+				return
+			}
+			if !sleepContext(ctx, calculateWrapperRetryDelay(retries)) {
+				return
+			}
+			retries++
+			body, _ = c.openProgressStream(ctx, jobID, lastEventID)
+		}
+	}
+}
+
+// pollProgressEvents follows a job by polling its progress. The first poll is made
+// before returning, so a job that cannot be followed is reported as an error.
+func (c *APIClient) pollProgressEvents(ctx context.Context, jobID int64) (<-chan Progress, error) {
+	var first Progress
+	var lastErr error
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 && !sleepContext(ctx, calculateWrapperRetryDelay(attempt-1)) {
+			return nil, c.translateError(ctx.Err())
+		}
+		if first, lastErr = c.fetchProgress(ctx, jobID); lastErr == nil {
+			break
+		}
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+
+	events := make(chan Progress)
+	go func() {
+		defer close(events)
+		if !sendProgress(ctx, events, first) || first.Done() {
+			return
+		}
+		if sleepContext(ctx, c.cfg.PollInterval) {
+			c.pollProgress(ctx, jobID, events, first.EventID)
+		}
+	}()
+	return events, nil
+}
+
+// pollProgress sends the job's progress every PollInterval until a terminal event,
+// skipping snapshots no newer than lastEventID. After more consecutive failed polls
+// than MaxRetries it sends an Error event and gives up.
+func (c *APIClient) pollProgress(ctx context.Context, jobID int64, events chan<- Progress, lastEventID int64) {
+	failures := 0
+	for {
+		progress, err := c.fetchProgress(ctx, jobID)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			if failures >= c.cfg.MaxRetries {
+				sendProgress(ctx, events, Progress{
+					Status: ProgressStatusError,
+					Error:  fmt.Sprintf("progress polling failed: %v", err),
+				})
+				return
+			}
+			failures++
+		case progress.EventID == 0 || progress.EventID > lastEventID:
+			failures = 0
+			lastEventID = progress.EventID
+			if !sendProgress(ctx, events, progress) || progress.Done() {
+				return
+			}
+		default:
+			failures = 0
+		}
+		if !sleepContext(ctx, c.cfg.PollInterval) {
+			return
+		}
+	}
+}
+
+// fetchProgress polls the job's current progress once. A job the server does not know,
+// never started or already cleaned up, is reported as an Error event like the stream's.
+func (c *APIClient) fetchProgress(ctx context.Context, jobID int64) (Progress, error) {
+	data, err := c.raw.LLMApi.PollScoreProgress(ctx, jobID)
+	if err != nil {
+		err = c.translateError(err)
+		var apiErr APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return Progress{Status: ProgressStatusError, Error: apiErr.Message}, nil
+		}
+		return Progress{}, err
+	}
+	var snapshot struct {
+		Progress
+		EventID int64 `json:"event_id"`
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Progress{}, fmt.Errorf("invalid progress snapshot: %w", err)
+	}
+	snapshot.Progress.EventID = snapshot.EventID
+	return snapshot.Progress, nil
+}
+
+// readProgressEvents parses server-sent events from r and sends them on events. It
+// reports whether any event was received and whether a terminal event was reached.
+// Comments such as keepalives are skipped; "error" events become Error progress events.
+func readProgressEvents(ctx context.Context, r io.Reader, events chan<- Progress, lastEventID *int64) (received, done bool, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineSize)
+
+	var eventType, eventID string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			if strings.HasPrefix(line, ":") {
+				continue
+			}
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				eventType = value
+			case "data":
+				data = append(data, value)
+			case "id":
+				eventID = value
+			}
+			continue
+		}
+
+		// A blank line dispatches the event
+		if len(data) > 0 {
+			progress, parseErr := parseProgressEvent(eventType, strings.Join(data, "\n"))
+			if parseErr != nil {
+				return received, false, parseErr
+			}
+			if id, convErr := strconv.ParseInt(eventID, 10, 64); convErr == nil && id > 0 {
+				progress.EventID = id
+				*lastEventID = id
+			}
+			if !sendProgress(ctx, events, progress) {
+				return received, false, ctx.Err()
+			}
+			received = true
+			if progress.Done() {
+				return received, true, nil
+			}
+		}
+		eventType, eventID, data = "", "", nil
+	}
+	return received, false, scanner.Err()
+}
+
+// parseProgressEvent decodes the data of a progress or error event
+func parseProgressEvent(eventType, data string) (Progress, error) {
+	if eventType == "error" {
+		var payload struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &payload); err != nil || payload.Error == "" {
+			payload.Error = data
+		}
+		return Progress{Status: ProgressStatusError, Error: payload.Error}, nil
+	}
+
+	var progress Progress
+	if err := json.Unmarshal([]byte(data), &progress); err != nil {
+		return Progress{}, fmt.Errorf("invalid progress event: %w", err)
+	}
+	return progress, nil
+}
+
+// sendProgress sends an event unless ctx is done first
+func sendProgress(ctx context.Context, events chan<- Progress, progress Progress) bool {
+	select {
+	case events <- progress:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sleepContext waits for d, returning false if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
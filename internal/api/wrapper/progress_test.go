@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collectProgress(t *testing.T, events <-chan Progress) []Progress {
+	t.Helper()
+	var got []Progress
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case p, ok := <-events:
+			if !ok {
+				return got
+			}
+			got = append(got, p)
+		case <-timeout:
+			t.Fatal("progress channel was not closed")
+		}
+	}
+}
+
+func TestReanalyzeAndWatchProgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/llm/reanalyze/7":
+			assert.Equal(t, http.MethodPost, r.Method)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"success":true,"data":{"status":"reanalyze queued","article_id":7}}`)
+		case "/api/llm/score-progress/7":
+			assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "id: 1\nevent: progress\ndata: {\"status\":\"InProgress\",\"step\":\"Scoring\",\"percent\":40}\n\n")
+			fmt.Fprint(w, ":keepalive\n\n")
+			fmt.Fprint(w, "id: 2\nevent: progress\ndata: {\"status\":\"Success\",\"step\":\"Complete\",\"percent\":100,\"final_score\":0.25,"+
+				"\"change\":{\"previous\":{\"score\":-0.2,\"confidence\":0.6,\"label\":\"left\"},\"current\":{\"score\":0.25,\"confidence\":0.8,\"label\":\"right\"},\"delta\":0.45}}\n\n")
+			fmt.Fprint(w, "id: 3\nevent: progress\ndata: {\"status\":\"Ignored\"}\n\n")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, WithRetryConfig(0, time.Millisecond))
+
+	job, err := client.Reanalyze(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), job.JobID)
+	assert.Equal(t, "reanalyze queued", job.Status)
+
+	events, err := client.WatchProgress(context.Background(), job.JobID)
+	require.NoError(t, err)
+	got := collectProgress(t, events)
+
+	require.Len(t, got, 2, "keepalives are skipped and the stream ends at the terminal event")
+	assert.Equal(t, "Scoring", got[0].Step)
+	assert.Equal(t, 40, got[0].Percent)
+	assert.Equal(t, int64(1), got[0].EventID)
+	assert.True(t, got[1].Done())
+	require.NotNil(t, got[1].FinalScore)
+	assert.Equal(t, 0.25, *got[1].FinalScore)
+	require.NotNil(t, got[1].Change)
+	assert.Equal(t, &ScoreSnapshot{Score: -0.2, Confidence: 0.6, Label: "left"}, got[1].Change.Previous)
+	assert.Equal(t, "right", got[1].Change.Current.Label)
+	assert.InDelta(t, 0.45, *got[1].Change.Delta, 1e-9)
+}
+
+func TestWatchProgressErrorEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: error\ndata: {\"error\":\"job not found or expired\"}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, WithRetryConfig(0, time.Millisecond))
+	events, err := client.WatchProgress(context.Background(), 99)
+	require.NoError(t, err)
+	got := collectProgress(t, events)
+
+	require.Len(t, got, 1)
+	assert.Equal(t, ProgressStatusError, got[0].Status)
+	assert.Equal(t, "job not found or expired", got[0].Error)
+}
+
+func TestWatchProgressResumesDroppedStream(t *testing.T) {
+	var lastEventIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		if len(lastEventIDs) == 1 {
+			fmt.Fprint(w, "id: 4\nevent: progress\ndata: {\"status\":\"InProgress\",\"percent\":50}\n\n")
+			return // drop the connection mid-job
+		}
+		fmt.Fprint(w, "id: 5\nevent: progress\ndata: {\"status\":\"Success\",\"percent\":100}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, WithRetryConfig(1, time.Millisecond))
+	events, err := client.WatchProgress(context.Background(), 3)
+	require.NoError(t, err)
+	got := collectProgress(t, events)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, ProgressStatusSuccess, got[1].Status)
+	assert.Equal(t, []string{"", "4"}, lastEventIDs, "the stream resumes after the last event received")
+}
+
+func TestWatchProgressConnectFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"success":false,"error":{"code":"validation_error","message":"Invalid article ID"}}`)
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, WithRetryConfig(0, time.Millisecond))
+	_, err := client.WatchProgress(context.Background(), 1)
+	require.Error(t, err)
+	var apiErr APIError
+	require.ErrorAs(t, err, &apiErr)
+}
+
+// pollingServer answers progress polls with the given snapshots in turn and, unless
+// sse is set, answers stream requests like a proxy that does not pass SSE through
+func pollingServer(t *testing.T, snapshots []string, sse bool) (*httptest.Server, *int) {
+	t.Helper()
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("mode") != "poll" {
+			if sse {
+				t.Errorf("unexpected stream request")
+			}
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html>buffered by proxy</html>")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, snapshots[min(polls, len(snapshots)-1)])
+		polls++
+	}))
+	t.Cleanup(server.Close)
+	return server, &polls
+}
+
+func TestWatchProgressPolling(t *testing.T) {
+	server, polls := pollingServer(t, []string{
+		`{"success":true,"data":{"status":"InProgress","percent":20,"event_id":3}}`,
+		`{"success":true,"data":{"status":"InProgress","percent":20,"event_id":3}}`,
+		`{"success":true,"data":{"status":"Success","percent":100,"final_score":0.3,"event_id":5}}`,
+	}, true)
+
+	client := NewAPIClient(server.URL, WithRetryConfig(0, time.Millisecond), WithProgressPolling(time.Millisecond))
+	events, err := client.WatchProgress(context.Background(), 4)
+	require.NoError(t, err)
+	got := collectProgress(t, events)
+
+	require.Len(t, got, 2, "unchanged snapshots are skipped")
+	assert.Equal(t, int64(3), got[0].EventID)
+	assert.Equal(t, ProgressStatusSuccess, got[1].Status)
+	require.NotNil(t, got[1].FinalScore)
+	assert.Equal(t, 0.3, *got[1].FinalScore)
+	assert.Equal(t, 3, *polls)
+}
+
+func TestWatchProgressFallsBackToPolling(t *testing.T) {
+	server, _ := pollingServer(t, []string{
+		`{"success":true,"data":{"status":"Complete","percent":100,"event_id":9}}`,
+	}, false)
+
+	client := NewAPIClient(server.URL, WithRetryConfig(0, time.Millisecond), WithPollInterval(time.Millisecond))
+	events, err := client.WatchProgress(context.Background(), 4)
+	require.NoError(t, err)
+	got := collectProgress(t, events)
+
+	require.Len(t, got, 1)
+	assert.True(t, got[0].Done())
+}
+
+func TestWatchProgressPollingUnknownJob(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"success":false,"error":{"code":"not_found","message":"Scoring job not found or expired"}}`)
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, WithRetryConfig(0, time.Millisecond), WithProgressPolling(time.Millisecond))
+	events, err := client.WatchProgress(context.Background(), 4)
+	require.NoError(t, err)
+	got := collectProgress(t, events)
+
+	require.Len(t, got, 1)
+	assert.Equal(t, ProgressStatusError, got[0].Status)
+	assert.Equal(t, "Scoring job not found or expired", got[0].Error)
+}
@@ -6,9 +6,10 @@ import (
 
 // AppError represents an application error with a code and message
 type AppError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Cause   error  `json:"-"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"` // Optional structured context for clients
+	Cause   error       `json:"-"`
 }
 
 // Error implements the error interface
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "audit.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, action := range []string{"source.create", "source.update", "article.reanalyze"} {
+		id, err := InsertAuditEntry(db, &AuditEntry{
+			Actor:     "api-key",
+			Action:    action,
+			Target:    "7",
+			Details:   `{"status":200}`,
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		})
+		require.NoError(t, err)
+		assert.Greater(t, id, int64(0))
+	}
+
+	entries, total, err := FetchAuditEntries(db, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "article.reanalyze", entries[0].Action, "newest first")
+	assert.Equal(t, "source.update", entries[1].Action)
+
+	entries, _, err = FetchAuditEntries(db, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "source.create", entries[0].Action)
+
+	t.Run("AppendOnly", func(t *testing.T) {
+		_, err := db.Exec("UPDATE audit_log SET actor = 'someone-else'")
+		assert.Error(t, err)
+		_, err = db.Exec("DELETE FROM audit_log")
+		assert.Error(t, err)
+
+		_, total, err := FetchAuditEntries(db, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+	})
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchBiasTrend(t *testing.T) {
+	db, err := InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	insert := func(source, url string, pubDate, createdAt time.Time, score *float64) {
+		_, err := db.Exec(`INSERT INTO articles (source, pub_date, url, title, content, created_at, composite_score)
+			VALUES (?, ?, ?, 'title', 'content', ?, ?)`, source, pubDate, url, createdAt, score)
+		require.NoError(t, err)
+	}
+	f := func(v float64) *float64 { return &v }
+
+	mon := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC) // Monday
+	wed := mon.AddDate(0, 0, 2)
+	nextMon := mon.AddDate(0, 0, 7)
+
+	insert("cnn", "u1", mon, mon, f(-0.4))
+	insert("cnn", "u2", mon, mon, f(-0.2))
+	insert("cnn", "u3", wed, wed, f(0.3))
+	insert("cnn", "u4", time.Time{}, nextMon, f(0.5)) // no published date, falls back to created_at
+	insert("cnn", "u5", mon, mon, nil)                // unscored, ignored
+	insert("fox", "u6", mon, mon, f(0.9))
+
+	t.Run("Day", func(t *testing.T) {
+		points, err := FetchBiasTrend(db, "cnn", TrendIntervalDay)
+		require.NoError(t, err)
+		require.Len(t, points, 3)
+		assert.Equal(t, "2024-03-04", points[0].Period)
+		assert.Equal(t, 2, points[0].Count)
+		assert.InDelta(t, -0.3, points[0].AvgScore, 1e-9)
+		assert.Equal(t, "2024-03-06", points[1].Period)
+		assert.Equal(t, "2024-03-11", points[2].Period)
+		assert.InDelta(t, 0.5, points[2].AvgScore, 1e-9)
+	})
+
+	t.Run("Week", func(t *testing.T) {
+		points, err := FetchBiasTrend(db, "cnn", TrendIntervalWeek)
+		require.NoError(t, err)
+		require.Len(t, points, 2)
+		assert.Equal(t, "2024-03-04", points[0].Period)
+		assert.Equal(t, 3, points[0].Count)
+		assert.InDelta(t, -0.1, points[0].AvgScore, 1e-9)
+		assert.Equal(t, "2024-03-11", points[1].Period)
+	})
+
+	t.Run("AllSources", func(t *testing.T) {
+		points, err := FetchBiasTrend(db, "", TrendIntervalWeek)
+		require.NoError(t, err)
+		require.Len(t, points, 2)
+		assert.Equal(t, 4, points[0].Count)
+	})
+
+	t.Run("InvalidInterval", func(t *testing.T) {
+		_, err := FetchBiasTrend(db, "", "month")
+		assert.Error(t, err)
+	})
+}
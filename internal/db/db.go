@@ -3,17 +3,25 @@ package db
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/apperrors"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/tracing"
 	"github.com/jmoiron/sqlx"
 	_ "modernc.org/sqlite"
 )
@@ -46,6 +54,7 @@ func secureFloat64() float64 {
 var (
 	ErrArticleNotFound  = errors.New("article not found")
 	ErrFeedbackNotFound = errors.New("feedback not found")
+	ErrScoreNotFound    = errors.New("score not found")
 	ErrDuplicateURL     = errors.New("article with this URL already exists")
 )
 
@@ -66,9 +75,56 @@ type Article struct {
 	Confidence     *float64   `db:"confidence" json:"confidence,omitempty"`
 	ScoreSource    *string    `db:"score_source" json:"score_source,omitempty"`
 	BiasLabel      *string    `db:"bias_label" json:"bias_label,omitempty"`
+	ImageURL       *string    `db:"image_url" json:"image_url,omitempty"`
+	Authors        StringList `db:"authors" json:"authors,omitempty"`
+	Categories     StringList `db:"categories" json:"categories,omitempty"`
 	Bias           string     `db:"-" json:"bias,omitempty"` // Calculated field, not stored in DB
 }
 
+// StringList is a list of strings stored as a JSON array in a TEXT column. NULL and
+// empty strings scan as an empty list, and an empty list is stored as NULL. Text that
+// is not a JSON array scans as a single entry so one bad row cannot break a listing.
+type StringList []string
+
+// Scan implements sql.Scanner
+func (l *StringList) Scan(src interface{}) error {
+	var raw []byte
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", src)
+	}
+	if len(strings.TrimSpace(string(raw))) == 0 {
+		*l = nil
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		*l = StringList{string(raw)}
+		return nil
+	}
+	*l = list
+	return nil
+}
+
+// Value implements driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	raw, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
 // LLMScore represents a political bias score from an LLM model
 type LLMScore struct {
 	ID        int64     `db:"id" json:"id"`
@@ -80,6 +136,23 @@ type LLMScore struct {
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
 
+// Feedback moderation statuses. New feedback awaits review; spam is left out of the
+// feedback summary on request and never adjusts article confidence.
+const (
+	FeedbackStatusNew      = "new"
+	FeedbackStatusReviewed = "reviewed"
+	FeedbackStatusSpam     = "spam"
+)
+
+// ValidFeedbackStatus reports whether status is a feedback moderation status
+func ValidFeedbackStatus(status string) bool {
+	switch status {
+	case FeedbackStatusNew, FeedbackStatusReviewed, FeedbackStatusSpam:
+		return true
+	}
+	return false
+}
+
 // Feedback represents user feedback on an article
 type Feedback struct {
 	ID               int64     `db:"id" json:"id"`
@@ -89,6 +162,8 @@ type Feedback struct {
 	Category         string    `db:"category" json:"category"`
 	EnsembleOutputID *int64    `db:"ensemble_output_id" json:"ensemble_output_id,omitempty"`
 	Source           string    `db:"source" json:"source,omitempty"`
+	IdempotencyKey   *string   `db:"idempotency_key" json:"idempotency_key,omitempty"` // unique when set
+	Status           string    `db:"status" json:"status"`                             // new, reviewed or spam
 	CreatedAt        time.Time `db:"created_at" json:"created_at"`
 }
 
@@ -120,6 +195,31 @@ type Source struct {
 	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
 }
 
+// AutoScore reports whether the source is flagged for auto-scoring with
+// {"auto_score": true} in its metadata
+func (s *Source) AutoScore() bool {
+	if s.Metadata == nil || *s.Metadata == "" {
+		return false
+	}
+	var meta struct {
+		AutoScore bool `json:"auto_score"`
+	}
+	if err := json.Unmarshal([]byte(*s.Metadata), &meta); err != nil {
+		return false
+	}
+	return meta.AutoScore
+}
+
+// TrustWeight is the source's trust weight from its metadata, or the default when the
+// metadata sets none or an invalid one
+func (s *Source) TrustWeight() float64 {
+	weight, err := models.SourceTrustWeight(s.Metadata)
+	if err != nil {
+		return models.DefaultSourceTrustWeight
+	}
+	return weight
+}
+
 // SourceStats represents aggregated statistics for a source
 type SourceStats struct {
 	SourceID      int64      `db:"source_id" json:"source_id"`
@@ -133,8 +233,60 @@ type SourceStats struct {
 type ArticleFilter struct {
 	Source  string
 	Leaning string
-	Limit   int
-	Offset  int
+	// Author and Category match one of the article's authors or categories, ignoring case
+	Author   string
+	Category string
+	// Tag matches articles carrying the editorial tag, which must already be normalized
+	Tag    string
+	Limit  int
+	Offset int
+	// Optional bounds on the latest composite score and its confidence; articles
+	// without a score never match a score or confidence bound
+	MinScore      *float64
+	MaxScore      *float64
+	MinConfidence *float64
+	// Optional publication and ingestion (created_at) date bounds; After bounds are
+	// inclusive and Before bounds exclusive
+	PublishedAfter  *time.Time
+	PublishedBefore *time.Time
+	IngestedAfter   *time.Time
+	IngestedBefore  *time.Time
+	// PublishedFallbackToIngested applies the publication bounds to the ingestion
+	// date of articles whose feed gave no publication date
+	PublishedFallbackToIngested bool
+	// Sort is one of the ArticleSort values; empty sorts newest first
+	Sort string
+	// OmitContent leaves Content empty instead of reading article bodies, for listings
+	// that do not show them
+	OmitContent bool
+}
+
+// articleColumnsWithoutContent selects every articles column except content. It must
+// list every column of the table; Article.BiasLabel has no column and stays empty either way.
+const articleColumnsWithoutContent = `id, source, pub_date, url, title, created_at, status, fail_count, last_attempt,
+	escalated, composite_score, confidence, score_source, image_url, authors, categories`
+
+// Article sort orders for ArticleFilter.Sort. Score and confidence orders put articles
+// without a score last and break ties newest first.
+const (
+	ArticleSortNewest     = "newest"
+	ArticleSortScoreAsc   = "score_asc"
+	ArticleSortScoreDesc  = "score_desc"
+	ArticleSortConfidence = "confidence"
+)
+
+// articleSortOrders maps each sort to its ORDER BY clause
+var articleSortOrders = map[string]string{
+	ArticleSortNewest:     "created_at DESC",
+	ArticleSortScoreAsc:   "composite_score IS NULL, composite_score ASC, created_at DESC",
+	ArticleSortScoreDesc:  "composite_score IS NULL, composite_score DESC, created_at DESC",
+	ArticleSortConfidence: "confidence IS NULL, confidence DESC, created_at DESC",
+}
+
+// IsArticleSort reports whether sort is a known ArticleFilter.Sort value
+func IsArticleSort(sort string) bool {
+	_, ok := articleSortOrders[sort]
+	return ok
 }
 
 // ArticleScore represents a score update for an article
@@ -185,17 +337,17 @@ type DBInstance struct {
 
 // GetArticleByID retrieves an article by ID
 func (d *DBInstance) GetArticleByID(ctx context.Context, id int64) (*Article, error) {
-	return FetchArticleByID(d.DB, id)
+	return FetchArticleByIDContext(ctx, d.DB, id)
 }
 
 // FetchArticleByID is an alias for GetArticleByID
 func (d *DBInstance) FetchArticleByID(ctx context.Context, id int64) (*Article, error) {
-	return FetchArticleByID(d.DB, id)
+	return FetchArticleByIDContext(ctx, d.DB, id)
 }
 
 // GetArticles retrieves articles based on filter criteria
 func (d *DBInstance) GetArticles(ctx context.Context, filter ArticleFilter) ([]*Article, error) {
-	articles, err := FetchArticles(d.DB, filter.Source, filter.Leaning, filter.Limit, filter.Offset)
+	articles, err := FetchArticlesFiltered(d.DB, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -270,14 +422,14 @@ func (d *DBInstance) InsertFeedback(ctx context.Context, feedback *Feedback) err
 
 // FetchLLMScores retrieves LLM scores for an article
 func (d *DBInstance) FetchLLMScores(ctx context.Context, articleID int64) ([]LLMScore, error) {
-	return FetchLLMScores(d.DB, articleID)
+	return FetchLLMScoresContext(ctx, d.DB, articleID)
 }
 
 // UpdateArticleScoreLLM updates an article's score by an LLM, typically as part of a transaction
 func (d *DBInstance) UpdateArticleScoreLLM(ctx context.Context, articleID int64, score float64, confidence float64) error {
 	// The actual db.UpdateArticleScoreLLM takes sqlx.ExtContext.
 	// For DBInstance, d.DB is *sqlx.DB which implements sqlx.ExtContext.
-	return UpdateArticleScoreLLM(d.DB, articleID, score, confidence)
+	return UpdateArticleScoreLLMContext(ctx, d.DB, articleID, score, confidence)
 }
 
 // New creates a new database connection
@@ -313,6 +465,85 @@ func handleError(err error, msg string) error {
 	}
 }
 
+// addedColumns are columns added to existing tables after they were first created;
+// CREATE TABLE IF NOT EXISTS leaves older databases without them
+var addedColumns = []struct{ table, column, definition string }{
+	{"articles", "image_url", "TEXT"},
+	{"articles", "authors", "TEXT"},
+	{"articles", "categories", "TEXT"},
+	{"score_history", "content_hash", "TEXT"},
+	{"feedback", "idempotency_key", "TEXT"},
+	{"feedback", "status", "TEXT NOT NULL DEFAULT 'new'"},
+}
+
+// addedIndexes are indexes on addedColumns, created once the columns exist
+var addedIndexes = []string{
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_feedback_idempotency_key ON feedback(idempotency_key)`,
+}
+
+// addMissingColumns adds any of addedColumns an older database lacks, and their indexes
+func addMissingColumns(db *sqlx.DB) error {
+	for _, c := range addedColumns {
+		var count int
+		if err := db.Get(&count, `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, c.table, c.column); err != nil {
+			return fmt.Errorf("failed to inspect table %s: %w", c.table, err)
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", c.table, c.column, err)
+		}
+		log.Printf("Added column %s.%s", c.table, c.column)
+	}
+	for _, index := range addedIndexes {
+		if _, err := db.Exec(index); err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+	return nil
+}
+
+// nonUTCDate matches dates the driver stored with a UTC offset other than zero
+const nonUTCDate = "(%[1]s GLOB '* [+-][0-9][0-9][0-9][0-9]*' AND %[1]s NOT GLOB '* +0000*')"
+
+// normalizeArticleDates rewrites article dates stored with a non-zero UTC offset in
+// UTC. The driver stores times as text with their offset, which only sorts correctly
+// when every row uses the same one.
+func normalizeArticleDates(db *sqlx.DB) error {
+	normalized := 0
+	for _, column := range []string{"pub_date", "created_at"} {
+		var rows []struct {
+			ID    int64  `db:"id"`
+			Value string `db:"value"`
+		}
+		query := fmt.Sprintf("SELECT id, CAST(%[1]s AS TEXT) AS value FROM articles WHERE "+nonUTCDate, column)
+		if err := db.Select(&rows, query); err != nil {
+			return fmt.Errorf("failed to find articles with non-UTC %s: %w", column, err)
+		}
+		for _, r := range rows {
+			// The value is "2006-01-02 15:04:05.999999999 -0700 MST"; the zone name adds nothing
+			fields := strings.Fields(r.Value)
+			if len(fields) < 3 {
+				continue
+			}
+			parsed, err := time.Parse("2006-01-02 15:04:05.999999999 -0700", strings.Join(fields[:3], " "))
+			if err != nil {
+				log.Printf("[WARN] Cannot normalize %s %q of article %d: %v", column, r.Value, r.ID, err)
+				continue
+			}
+			if _, err := db.Exec(fmt.Sprintf("UPDATE articles SET %s = ? WHERE id = ?", column), parsed.UTC(), r.ID); err != nil {
+				return fmt.Errorf("failed to normalize %s of article %d: %w", column, r.ID, err)
+			}
+			normalized++
+		}
+	}
+	if normalized > 0 {
+		log.Printf("Normalized %d article dates to UTC", normalized)
+	}
+	return nil
+}
+
 // validateDBSchema ensures critical tables exist. It returns an error if any
 // required table is missing, providing clearer diagnostics for test failures.
 func validateDBSchema(db *sqlx.DB) error {
@@ -364,11 +595,217 @@ func InsertLabel(db *sqlx.DB, label *Label) error {
 	return nil
 }
 
-// InsertFeedback stores user feedback for an article
-func InsertFeedback(db *sqlx.DB, feedback *Feedback) error {
+// InsertLabels inserts labels in a single transaction and sets their IDs. If any
+// insert fails, none of the labels are stored.
+func InsertLabels(db *sqlx.DB, labels []*Label) (err error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return handleError(err, "failed to begin label batch")
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("[WARN] Failed to roll back label batch: %v", rbErr)
+			}
+		}
+	}()
+
+	stmt, err := tx.PrepareNamed(`
+        INSERT INTO labels (data, label, source, date_labeled, labeler, confidence, created_at)
+        VALUES (:data, :label, :source, :date_labeled, :labeler, :confidence, :created_at)`)
+	if err != nil {
+		return handleError(err, "failed to prepare label batch")
+	}
+	defer stmt.Close()
+
+	for _, label := range labels {
+		result, execErr := stmt.Exec(label)
+		if execErr != nil {
+			return handleError(execErr, "failed to insert label")
+		}
+		if label.ID, err = result.LastInsertId(); err != nil {
+			return handleError(err, "failed to get inserted label ID")
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return handleError(err, "failed to commit label batch")
+	}
+	return nil
+}
+
+// NormalizeLabel maps a label or one of its synonyms to left, right or neutral,
+// reporting false when the label is not one of them
+func NormalizeLabel(label string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(label)) {
+	case "left", "-1", "-1.0":
+		return "left", true
+	case "right", "1", "1.0":
+		return "right", true
+	case "neutral", "0", "0.0":
+		return "neutral", true
+	default:
+		return "", false
+	}
+}
+
+// LabelDataHash identifies labelled data for upserts, ignoring surrounding whitespace
+func LabelDataHash(data string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(data)))
+	return hex.EncodeToString(sum[:])
+}
+
+// labelLookupChunk bounds how many values one label lookup query binds
+const labelLookupChunk = 500
+
+// trimmedLabelData is the SQL counterpart of the strings.TrimSpace in LabelDataHash.
+// idx_labels_labeler_data indexes the same expression, so lookups use it.
+const trimmedLabelData = "trim(data, char(32, 9, 10, 11, 12, 13))"
+
+// FetchLabelIDsByData returns the IDs of a labeler's labels whose data matches any of
+// data once surrounding whitespace is trimmed, keyed by LabelDataHash. With duplicates
+// the newest label wins.
+func FetchLabelIDsByData(db *sqlx.DB, labeler string, data []string) (map[string]int64, error) {
+	ids := make(map[string]int64)
+	for start := 0; start < len(data); start += labelLookupChunk {
+		chunk := data[start:min(start+labelLookupChunk, len(data))]
+		trimmed := make([]string, len(chunk))
+		for i, d := range chunk {
+			trimmed[i] = strings.TrimSpace(d)
+		}
+		query, args, err := sqlx.In(`SELECT id, data FROM labels WHERE labeler = ? AND `+trimmedLabelData+` IN (?) ORDER BY id`,
+			labeler, trimmed)
+		if err != nil {
+			return nil, handleError(err, "failed to build label query")
+		}
+		var rows []struct {
+			ID   int64  `db:"id"`
+			Data string `db:"data"`
+		}
+		if err := db.Select(&rows, db.Rebind(query), args...); err != nil {
+			return nil, handleError(err, "failed to fetch labels")
+		}
+		for _, r := range rows {
+			ids[LabelDataHash(r.Data)] = r.ID
+		}
+	}
+	return ids, nil
+}
+
+// UpdateLabel overwrites the label with label.ID, keeping its created_at
+func UpdateLabel(db *sqlx.DB, label *Label) error {
 	result, err := db.NamedExec(`
-        INSERT INTO feedback (article_id, user_id, feedback_text, category, ensemble_output_id, source, created_at)
-        VALUES (:article_id, :user_id, :feedback_text, :category, :ensemble_output_id, :source, :created_at)`,
+        UPDATE labels SET data = :data, label = :label, source = :source, date_labeled = :date_labeled,
+            labeler = :labeler, confidence = :confidence
+        WHERE id = :id`,
+		label)
+	if err != nil {
+		return handleError(err, "failed to update label")
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return apperrors.New("not_found", fmt.Sprintf("label %d not found", label.ID))
+	}
+	return nil
+}
+
+// Error categories a reviewer can give a flagged label
+const (
+	ErrorCategoryPromptIssue  = "prompt_issue"
+	ErrorCategoryModelFailure = "model_failure"
+	ErrorCategoryDataNoise    = "data_noise"
+)
+
+// ValidErrorCategory reports whether category is empty or one of the error categories
+func ValidErrorCategory(category string) bool {
+	switch category {
+	case "", ErrorCategoryPromptIssue, ErrorCategoryModelFailure, ErrorCategoryDataNoise:
+		return true
+	default:
+		return false
+	}
+}
+
+// LabelCorrection is a reviewer's verdict on a label
+type LabelCorrection struct {
+	LabelID int64
+	// CorrectedLabel is a normalized label; empty keeps the current one
+	CorrectedLabel string
+	ErrorCategory  string
+}
+
+// FetchLabelsByIDs returns the labels with the given IDs keyed by ID; missing IDs are
+// absent from the map
+func FetchLabelsByIDs(db *sqlx.DB, ids []int64) (map[int64]Label, error) {
+	labels := make(map[int64]Label, len(ids))
+	if len(ids) == 0 {
+		return labels, nil
+	}
+	query, args, err := sqlx.In(`SELECT * FROM labels WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, handleError(err, "failed to build label query")
+	}
+	var rows []Label
+	if err := db.Select(&rows, db.Rebind(query), args...); err != nil {
+		return nil, handleError(err, "failed to fetch labels")
+	}
+	for _, l := range rows {
+		labels[l.ID] = l
+	}
+	return labels, nil
+}
+
+// ApplyLabelCorrections updates each corrected label and records every review in
+// label_reviews, all in one transaction. It returns how many labels changed.
+func ApplyLabelCorrections(db *sqlx.DB, corrections []LabelCorrection) (changed int, err error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return 0, handleError(err, "failed to begin label corrections")
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("[WARN] Failed to roll back label corrections: %v", rbErr)
+			}
+		}
+	}()
+
+	for _, c := range corrections {
+		var previous string
+		if err = tx.Get(&previous, `SELECT label FROM labels WHERE id = ?`, c.LabelID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = apperrors.New("not_found", fmt.Sprintf("label %d not found", c.LabelID))
+				return 0, err
+			}
+			return 0, handleError(err, "failed to fetch label")
+		}
+		corrected := previous
+		if c.CorrectedLabel != "" {
+			corrected = c.CorrectedLabel
+		}
+		if corrected != previous {
+			if _, err = tx.Exec(`UPDATE labels SET label = ?, date_labeled = ? WHERE id = ?`, corrected, time.Now(), c.LabelID); err != nil {
+				return 0, handleError(err, "failed to update label")
+			}
+			changed++
+		}
+		if _, err = tx.Exec(`INSERT INTO label_reviews (label_id, previous_label, corrected_label, error_category, reviewed_at)
+			VALUES (?, ?, ?, ?, ?)`, c.LabelID, previous, corrected, sql.NullString{String: c.ErrorCategory, Valid: c.ErrorCategory != ""}, time.Now()); err != nil {
+			return 0, handleError(err, "failed to record label review")
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, handleError(err, "failed to commit label corrections")
+	}
+	return changed, nil
+}
+
+// InsertFeedback stores user feedback for an article, with status new unless it has one
+func InsertFeedback(exec sqlx.ExtContext, feedback *Feedback) error {
+	if feedback.Status == "" {
+		feedback.Status = FeedbackStatusNew
+	}
+	result, err := sqlx.NamedExecContext(context.Background(), exec, `
+        INSERT INTO feedback (article_id, user_id, feedback_text, category, ensemble_output_id, source, idempotency_key, status, created_at)
+        VALUES (:article_id, :user_id, :feedback_text, :category, :ensemble_output_id, :source, :idempotency_key, :status, :created_at)`,
 		feedback)
 	if err != nil {
 		return handleError(err, "failed to insert feedback")
@@ -382,6 +819,127 @@ func InsertFeedback(db *sqlx.DB, feedback *Feedback) error {
 	return nil
 }
 
+// FeedbackBatchResult is the outcome of one item of a feedback batch. Duplicate is set
+// when the item's idempotency key was already stored; ID is then the stored feedback's.
+type FeedbackBatchResult struct {
+	ID        int64
+	Duplicate bool
+	Err       error
+}
+
+// InsertFeedbackBatch stores feedback items in a single transaction, returning one
+// result per item. An item whose idempotency key is already stored, or used earlier in
+// the batch, is not inserted again. A failed item is reported in its result and the
+// others are still stored; the error is only set when the batch itself fails.
+func InsertFeedbackBatch(db *sqlx.DB, items []*Feedback) (results []FeedbackBatchResult, err error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, handleError(err, "failed to begin feedback batch")
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("[WARN] Failed to roll back feedback batch: %v", rbErr)
+			}
+		}
+	}()
+
+	results = make([]FeedbackBatchResult, len(items))
+	for i, item := range items {
+		if item.IdempotencyKey != nil {
+			var existing int64
+			lookupErr := tx.Get(&existing, `SELECT id FROM feedback WHERE idempotency_key = ?`, *item.IdempotencyKey)
+			if lookupErr == nil {
+				results[i] = FeedbackBatchResult{ID: existing, Duplicate: true}
+				continue
+			}
+			if !errors.Is(lookupErr, sql.ErrNoRows) {
+				results[i].Err = handleError(lookupErr, "failed to look up feedback idempotency key")
+				continue
+			}
+		}
+		if insertErr := InsertFeedback(tx, item); insertErr != nil {
+			results[i].Err = insertErr
+			continue
+		}
+		results[i].ID = item.ID
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, handleError(err, "failed to commit feedback batch")
+	}
+	return results, nil
+}
+
+// feedbackColumns are the feedback columns read into Feedback
+const feedbackColumns = `id, article_id, COALESCE(user_id, '') AS user_id, COALESCE(feedback_text, '') AS feedback_text,
+	COALESCE(category, '') AS category, ensemble_output_id, COALESCE(source, '') AS source, idempotency_key, status, created_at`
+
+// FetchFeedbackByID returns one feedback item, or ErrFeedbackNotFound
+func FetchFeedbackByID(db *sqlx.DB, id int64) (*Feedback, error) {
+	var feedback Feedback
+	err := db.Get(&feedback, `SELECT `+feedbackColumns+` FROM feedback WHERE id = ?`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrFeedbackNotFound
+	}
+	if err != nil {
+		return nil, handleError(err, "failed to fetch feedback")
+	}
+	return &feedback, nil
+}
+
+// FetchFeedback returns feedback newest first, only that with the given status unless
+// status is empty, along with the total count matching
+func FetchFeedback(db *sqlx.DB, status string, limit, offset int) ([]Feedback, int, error) {
+	where, args := "", []interface{}{}
+	if status != "" {
+		where, args = " WHERE status = ?", append(args, status)
+	}
+	var total int
+	if err := db.Get(&total, `SELECT COUNT(*) FROM feedback`+where, args...); err != nil {
+		return nil, 0, handleError(err, "failed to count feedback")
+	}
+	feedback := []Feedback{}
+	err := db.Select(&feedback, `SELECT `+feedbackColumns+` FROM feedback`+where+`
+		ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, handleError(err, "failed to fetch feedback")
+	}
+	return feedback, total, nil
+}
+
+// UpdateFeedbackStatus sets a feedback item's moderation status, returning
+// ErrFeedbackNotFound when there is no such item
+func UpdateFeedbackStatus(db *sqlx.DB, id int64, status string) error {
+	if !ValidFeedbackStatus(status) {
+		return fmt.Errorf("invalid feedback status %q", status)
+	}
+	result, err := db.Exec(`UPDATE feedback SET status = ? WHERE id = ?`, status, id)
+	if err != nil {
+		return handleError(err, "failed to update feedback status")
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrFeedbackNotFound
+	}
+	return nil
+}
+
+// FetchLatestFeedbackCategory returns the category of an article's most recent
+// feedback that is not spam, or "" when there is none
+func FetchLatestFeedbackCategory(db *sqlx.DB, articleID int64) (string, error) {
+	var category string
+	err := db.Get(&category, `
+		SELECT COALESCE(category, '') FROM feedback
+		WHERE article_id = ? AND status != ?
+		ORDER BY created_at DESC, id DESC LIMIT 1`, articleID, FeedbackStatusSpam)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", handleError(err, "failed to fetch latest feedback category")
+	}
+	return category, nil
+}
+
 // FetchLatestEnsembleScore gets the most recent ensemble score for an article
 func FetchLatestEnsembleScore(db *sqlx.DB, articleID int64) (float64, error) {
 	var score float64
@@ -445,6 +1003,10 @@ func InsertArticle(db *sqlx.DB, article *Article) (int64, error) {
 	if article.CreatedAt.IsZero() {
 		article.CreatedAt = time.Now()
 	}
+	// Dates are stored in UTC so the publication and ingestion filters can compare
+	// them as text
+	article.PubDate = article.PubDate.UTC()
+	article.CreatedAt = article.CreatedAt.UTC()
 	if article.Status == nil {
 		defaultStatus := "pending"
 		article.Status = &defaultStatus
@@ -508,9 +1070,9 @@ func insertArticleTransaction(db *sqlx.DB, article *Article, resultID *int64) er
 	// Insert the article if it doesn't exist
 	result, err := tx.NamedExec(`
         INSERT INTO articles (source, pub_date, url, title, content, created_at, composite_score, confidence, score_source,
-                              status, fail_count, last_attempt, escalated)
+                              status, fail_count, last_attempt, escalated, image_url, authors, categories)
         VALUES (:source, :pub_date, :url, :title, :content, :created_at, :composite_score, :confidence, :score_source,
-                :status, :fail_count, :last_attempt, :escalated)`,
+                :status, :fail_count, :last_attempt, :escalated, :image_url, :authors, :categories)`,
 		article)
 	if err != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
@@ -541,6 +1103,18 @@ func insertArticleTransaction(db *sqlx.DB, article *Article, resultID *int64) er
 
 // InsertLLMScore creates a new LLM score record with retry logic for SQLite concurrency
 func InsertLLMScore(exec sqlx.ExtContext, score *LLMScore) (int64, error) {
+	return InsertLLMScoreContext(context.Background(), exec, score)
+}
+
+// InsertLLMScoreContext is InsertLLMScore with its span and query bound to ctx
+func InsertLLMScoreContext(ctx context.Context, exec sqlx.ExtContext, score *LLMScore) (id int64, err error) {
+	ctx, span := tracing.Start(ctx, "db.InsertLLMScore",
+		tracing.ArticleID(score.ArticleID), tracing.Model(score.Model))
+	defer func() { tracing.End(span, err) }()
+	return insertLLMScore(ctx, exec, score)
+}
+
+func insertLLMScore(ctx context.Context, exec sqlx.ExtContext, score *LLMScore) (int64, error) {
 	if err := validateLLMMetadata(score.Metadata); err != nil {
 		log.Printf("[ERROR] Invalid metadata for article %d model %s: %v", score.ArticleID, score.Model, err)
 		return 0, handleError(err, "invalid metadata for llm score")
@@ -558,7 +1132,7 @@ func InsertLLMScore(exec sqlx.ExtContext, score *LLMScore) (int64, error) {
 				version = excluded.version,
 				created_at = excluded.created_at;`
 
-		result, err := sqlx.NamedExecContext(context.Background(), exec, query, score)
+		result, err := sqlx.NamedExecContext(ctx, exec, query, score)
 		if err != nil {
 			if IsSQLiteBusyError(err) {
 				log.Printf("[RETRY] InsertLLMScore (upsert) for article %d model %s: %v", score.ArticleID, score.Model, err)
@@ -601,28 +1175,127 @@ func InsertLLMScore(exec sqlx.ExtContext, score *LLMScore) (int64, error) {
 	return id, nil
 }
 
-// FetchArticles retrieves articles with optional filters
-func FetchArticles(db *sqlx.DB, source string, leaning string, limit int, offset int) ([]Article, error) {
-	query := `SELECT * FROM articles WHERE 1=1`
+// articleFilterClause builds the WHERE conditions shared by FetchArticlesFiltered and
+// CountArticlesFiltered
+func articleFilterClause(filter ArticleFilter) (string, []interface{}) {
+	clause := " WHERE 1=1"
 	var args []interface{}
 
-	if source != "" {
-		query += " AND source = ?"
-		args = append(args, source)
+	if filter.Source != "" {
+		clause += " AND source = ?"
+		args = append(args, filter.Source)
 	}
-	if leaning != "" {
+	if leaning := filter.Leaning; leaning != "" {
 		switch leaning {
 		case "left":
-			query += " AND composite_score < -0.1"
+			clause += " AND composite_score < -0.1"
 		case "right":
-			query += " AND composite_score > 0.1"
+			clause += " AND composite_score > 0.1"
 		case "center":
-			query += " AND composite_score BETWEEN -0.1 AND 0.1"
+			clause += " AND composite_score BETWEEN -0.1 AND 0.1"
 		}
 	}
+	if filter.Author != "" {
+		clause += " AND " + jsonListContains("authors")
+		args = append(args, strings.TrimSpace(filter.Author))
+	}
+	if filter.Category != "" {
+		clause += " AND " + jsonListContains("categories")
+		args = append(args, strings.TrimSpace(filter.Category))
+	}
+	if filter.Tag != "" {
+		clause += " AND id IN (SELECT at.article_id FROM article_tags at JOIN tags t ON t.id = at.tag_id WHERE t.name = ?)"
+		args = append(args, filter.Tag)
+	}
+	if filter.MinScore != nil {
+		clause += " AND composite_score >= ?"
+		args = append(args, *filter.MinScore)
+	}
+	if filter.MaxScore != nil {
+		clause += " AND composite_score <= ?"
+		args = append(args, *filter.MaxScore)
+	}
+	if filter.MinConfidence != nil {
+		clause += " AND confidence >= ?"
+		args = append(args, *filter.MinConfidence)
+	}
+	clause, args = publishedBound(clause, args, ">=", filter.PublishedAfter, filter.PublishedFallbackToIngested)
+	clause, args = publishedBound(clause, args, "<", filter.PublishedBefore, filter.PublishedFallbackToIngested)
+	if filter.IngestedAfter != nil {
+		clause += " AND created_at >= ?"
+		args = append(args, filter.IngestedAfter.UTC())
+	}
+	if filter.IngestedBefore != nil {
+		clause += " AND created_at < ?"
+		args = append(args, filter.IngestedBefore.UTC())
+	}
+	return clause, args
+}
+
+// jsonListContains matches rows whose StringList column holds the bound value, ignoring
+// case. Rows with NULL or malformed lists never match.
+func jsonListContains(column string) string {
+	return "EXISTS (SELECT 1 FROM json_each(CASE WHEN json_valid(" + column + ") THEN " + column +
+		" END) WHERE LOWER(json_each.value) = LOWER(?))"
+}
+
+// missingPubDateCondition matches articles stored without a usable publication date.
+// They never match publication bounds unless the ingestion date fallback is enabled.
+const missingPubDateCondition = "(pub_date IS NULL OR pub_date < '1900-01-01')"
+
+// publishedBound adds a publication date bound. The columns are compared directly
+// against UTC timestamps, in the format the driver stores them, so the date indexes
+// can be used.
+func publishedBound(clause string, args []interface{}, op string, bound *time.Time, fallback bool) (string, []interface{}) {
+	if bound == nil {
+		return clause, args
+	}
+	ts := bound.UTC()
+	if !fallback {
+		return clause + " AND pub_date " + op + " ? AND NOT " + missingPubDateCondition, append(args, ts)
+	}
+	clause += " AND (pub_date " + op + " ? AND NOT " + missingPubDateCondition +
+		" OR " + missingPubDateCondition + " AND created_at " + op + " ?)"
+	return clause, append(args, ts, ts)
+}
 
-	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
-	args = append(args, limit, offset)
+// CountArticles returns the number of articles matching the FetchArticles filters
+func CountArticles(db *sqlx.DB, source string, leaning string) (int, error) {
+	return CountArticlesFiltered(db, ArticleFilter{Source: source, Leaning: leaning})
+}
+
+// CountArticlesFiltered returns the number of articles matching the filter, ignoring
+// its limit and offset
+func CountArticlesFiltered(db *sqlx.DB, filter ArticleFilter) (int, error) {
+	clause, args := articleFilterClause(filter)
+	var total int
+	if err := db.Get(&total, "SELECT COUNT(*) FROM articles"+clause, args...); err != nil {
+		return 0, handleError(err, "failed to count articles")
+	}
+	return total, nil
+}
+
+// FetchArticles retrieves articles with optional filters
+func FetchArticles(db *sqlx.DB, source string, leaning string, limit int, offset int) ([]Article, error) {
+	return FetchArticlesFiltered(db, ArticleFilter{Source: source, Leaning: leaning, Limit: limit, Offset: offset})
+}
+
+// FetchArticlesFiltered retrieves a page of articles matching the filter in its sort
+// order, newest first by default
+func FetchArticlesFiltered(db *sqlx.DB, filter ArticleFilter) ([]Article, error) {
+	clause, args := articleFilterClause(filter)
+	columns := "*"
+	if filter.OmitContent {
+		columns = articleColumnsWithoutContent
+	}
+	query := `SELECT ` + columns + ` FROM articles` + clause
+
+	order, ok := articleSortOrders[filter.Sort]
+	if !ok {
+		order = articleSortOrders[ArticleSortNewest]
+	}
+	query += " ORDER BY " + order + " LIMIT ? OFFSET ?"
+	args = append(args, filter.Limit, filter.Offset)
 
 	// Add debug logging
 	log.Printf("[DEBUG] FetchArticles query: %s with args: %v", query, args)
@@ -665,6 +1338,17 @@ func (a *Article) CalculateBias() {
 
 // FetchArticleByID retrieves a single article by ID
 func FetchArticleByID(db *sqlx.DB, id int64) (*Article, error) {
+	return FetchArticleByIDContext(context.Background(), db, id)
+}
+
+// FetchArticleByIDContext is FetchArticleByID with its span and query bound to ctx
+func FetchArticleByIDContext(ctx context.Context, db *sqlx.DB, id int64) (article *Article, err error) {
+	ctx, span := tracing.Start(ctx, "db.FetchArticleByID", tracing.ArticleID(id))
+	defer func() { tracing.End(span, err) }()
+	return fetchArticleByID(ctx, db, id)
+}
+
+func fetchArticleByID(ctx context.Context, db *sqlx.DB, id int64) (*Article, error) {
 	log.Printf("[DEBUG] FetchArticleByID called with id: %d", id)
 	if db == nil {
 		log.Printf("[ERROR] Database connection is nil")
@@ -680,7 +1364,7 @@ func FetchArticleByID(db *sqlx.DB, id int64) (*Article, error) {
 	var err error
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		log.Printf("[DEBUG] Attempt %d to fetch article with id: %d", attempt+1, id)
-		err = db.Get(&article, "SELECT * FROM articles WHERE id = ?", id)
+		err = db.GetContext(ctx, &article, "SELECT * FROM articles WHERE id = ?", id)
 		if err == nil {
 			// Article found, return it
 			log.Printf("[INFO] Article fetched successfully: %+v", article)
@@ -713,21 +1397,70 @@ func FetchArticleByID(db *sqlx.DB, id int64) (*Article, error) {
 
 // FetchLLMScores retrieves all LLM scores for an article
 func FetchLLMScores(db *sqlx.DB, articleID int64) ([]LLMScore, error) {
+	return FetchLLMScoresContext(context.Background(), db, articleID)
+}
+
+// FetchLLMScoresContext is FetchLLMScores with its span and query bound to ctx
+func FetchLLMScoresContext(ctx context.Context, db *sqlx.DB, articleID int64) ([]LLMScore, error) {
+	ctx, span := tracing.Start(ctx, "db.FetchLLMScores", tracing.ArticleID(articleID))
 	var scores []LLMScore
-	err := db.Select(&scores, "SELECT * FROM llm_scores WHERE article_id = ? ORDER BY created_at DESC", articleID)
+	err := db.SelectContext(ctx, &scores, "SELECT * FROM llm_scores WHERE article_id = ? ORDER BY created_at DESC", articleID)
+	tracing.End(span, err)
 	if err != nil {
 		return nil, handleError(err, "failed to fetch LLM scores")
 	}
 	return scores, nil
 }
 
-// UpdateArticleScore updates the composite score for an article with retry logic
+// FetchLLMScore returns one score row of an article, or ErrScoreNotFound when the
+// article has no score with that ID
+func FetchLLMScore(db *sqlx.DB, articleID, scoreID int64) (*LLMScore, error) {
+	var score LLMScore
+	err := db.Get(&score, "SELECT * FROM llm_scores WHERE id = ? AND article_id = ?", scoreID, articleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrScoreNotFound
+	}
+	if err != nil {
+		return nil, handleError(err, "failed to fetch LLM score")
+	}
+	return &score, nil
+}
+
+// FetchModelScoredArticleIDs returns up to limit IDs, in ascending order and greater
+// than afterID, of articles with at least one per-model score (ensemble and manual
+// scores do not count)
+func FetchModelScoredArticleIDs(db *sqlx.DB, afterID int64, limit int) ([]int64, error) {
+	var ids []int64
+	err := db.Select(&ids, `SELECT DISTINCT article_id FROM llm_scores
+		WHERE article_id > ? AND model NOT IN ('ensemble', ?)
+		ORDER BY article_id LIMIT ?`, afterID, ManualScoreModel, limit)
+	if err != nil {
+		return nil, handleError(err, "failed to fetch scored article IDs")
+	}
+	return ids, nil
+}
+
+// FetchRecentModelScoredArticleIDs returns the IDs of the newest limit articles with at
+// least one per-model score, newest first
+func FetchRecentModelScoredArticleIDs(db *sqlx.DB, limit int) ([]int64, error) {
+	var ids []int64
+	err := db.Select(&ids, `SELECT DISTINCT article_id FROM llm_scores
+		WHERE model NOT IN ('ensemble', ?)
+		ORDER BY article_id DESC LIMIT ?`, ManualScoreModel, limit)
+	if err != nil {
+		return nil, handleError(err, "failed to fetch recent scored article IDs")
+	}
+	return ids, nil
+}
+
+// UpdateArticleScore updates the composite score for an article with retry logic.
+// Articles with a manual score override are left unchanged.
 func UpdateArticleScore(db *sqlx.DB, articleID int64, score float64, confidence float64) error {
 	err := WithRetry(DefaultRetryConfig(), func() error {
 		_, err := db.Exec(`
 			UPDATE articles
 			SET composite_score = ?, confidence = ?, score_source = 'llm'
-			WHERE id = ?`,
+			WHERE id = ? AND COALESCE(score_source, '') != 'manual'`,
 			score, confidence, articleID)
 		if err != nil {
 			if IsSQLiteBusyError(err) {
@@ -745,21 +1478,111 @@ func UpdateArticleScore(db *sqlx.DB, articleID int64, score float64, confidence
 	return nil
 }
 
-// UpdateArticleScoreLLM updates the composite score for an article, specifically from LLM rescoring with retry logic
-func UpdateArticleScoreLLM(exec sqlx.ExtContext, articleID int64, score float64, confidence float64) error {
-	log.Printf("[DEBUG][CONFIDENCE] UpdateArticleScoreLLM called with articleID=%d, score=%.4f, confidence=%.4f",
-		articleID, score, confidence)
+// FetchArticleTrustWeight returns the trust weight of the source an article came from,
+// matched by source name. Articles whose source is not configured get the default.
+func FetchArticleTrustWeight(exec sqlx.QueryerContext, articleID int64) (float64, error) {
+	source := Source{}
+	err := sqlx.GetContext(context.Background(), exec, &source.Metadata, `
+		SELECT s.metadata FROM articles a JOIN sources s ON s.name = a.source WHERE a.id = ?`, articleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DefaultSourceTrustWeight, nil
+		}
+		return models.DefaultSourceTrustWeight, handleError(err, "failed to fetch source trust weight")
+	}
+	return source.TrustWeight(), nil
+}
 
-	err := WithRetry(DefaultRetryConfig(), func() error {
-		result, err := exec.ExecContext(context.Background(), `
-			UPDATE articles
-			SET composite_score = ?, confidence = ?, score_source = 'llm'
-			WHERE id = ?`,
-			score, confidence, articleID)
+// Score history kinds: a composite from scoring the article with the LLMs, or one
+// recomputed from stored model scores
+const (
+	ScoreVersionAnalysis  = "analysis"
+	ScoreVersionRecompute = "recompute"
+)
 
-		if err != nil {
-			if IsSQLiteBusyError(err) {
-				log.Printf("[RETRY] UpdateArticleScoreLLM for article %d: %v", articleID, err)
+// RecordScoreVersion appends a composite score to the article's score history and
+// returns its version, one more than the article's latest
+func RecordScoreVersion(exec sqlx.ExtContext, articleID int64, score, confidence float64, kind string) (int, error) {
+	return RecordAnalyzedScoreVersion(exec, articleID, score, confidence, kind, "")
+}
+
+// RecordAnalyzedScoreVersion is RecordScoreVersion also storing the hash of the content
+// that was scored; an empty hash is stored as NULL
+func RecordAnalyzedScoreVersion(exec sqlx.ExtContext, articleID int64, score, confidence float64, kind, contentHash string) (int, error) {
+	var hash *string
+	if contentHash != "" {
+		hash = &contentHash
+	}
+	var version int
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		return sqlx.GetContext(context.Background(), exec, &version, `
+			INSERT INTO score_history (article_id, version, score, confidence, kind, content_hash)
+			SELECT ?, COALESCE(MAX(version), 0) + 1, ?, ?, ?, ? FROM score_history WHERE article_id = ?
+			RETURNING version`, articleID, score, confidence, kind, hash, articleID)
+	})
+	if err != nil {
+		return 0, handleError(err, "failed to record score version")
+	}
+	return version, nil
+}
+
+// FetchLastContentHash returns the content hash stored with the article's latest score
+// version that has one, or "" when none does
+func FetchLastContentHash(exec sqlx.QueryerContext, articleID int64) (string, error) {
+	var hash string
+	err := sqlx.GetContext(context.Background(), exec, &hash, `
+		SELECT content_hash FROM score_history
+		WHERE article_id = ? AND content_hash IS NOT NULL
+		ORDER BY version DESC LIMIT 1`, articleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", handleError(err, "failed to fetch content hash")
+	}
+	return hash, nil
+}
+
+// FetchArticleScore returns an article's stored composite score and confidence, either
+// of which is nil when the article has not been scored
+func FetchArticleScore(exec sqlx.QueryerContext, articleID int64) (score *float64, confidence *float64, err error) {
+	row := exec.QueryRowxContext(context.Background(), "SELECT composite_score, confidence FROM articles WHERE id = ?", articleID)
+	if err := row.Scan(&score, &confidence); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrArticleNotFound
+		}
+		return nil, nil, err
+	}
+	return score, confidence, nil
+}
+
+// UpdateArticleScoreLLM updates the composite score for an article, specifically from LLM rescoring with retry logic.
+// Articles with a manual score override are left unchanged.
+func UpdateArticleScoreLLM(exec sqlx.ExtContext, articleID int64, score float64, confidence float64) error {
+	return UpdateArticleScoreLLMContext(context.Background(), exec, articleID, score, confidence)
+}
+
+// UpdateArticleScoreLLMContext is UpdateArticleScoreLLM with its span and query bound to ctx
+func UpdateArticleScoreLLMContext(ctx context.Context, exec sqlx.ExtContext, articleID int64, score float64, confidence float64) (err error) {
+	ctx, span := tracing.Start(ctx, "db.UpdateArticleScoreLLM", tracing.ArticleID(articleID))
+	defer func() { tracing.End(span, err) }()
+	return updateArticleScoreLLM(ctx, exec, articleID, score, confidence)
+}
+
+func updateArticleScoreLLM(ctx context.Context, exec sqlx.ExtContext, articleID int64, score float64, confidence float64) error {
+	log.Printf("[DEBUG][CONFIDENCE] UpdateArticleScoreLLM called with articleID=%d, score=%.4f, confidence=%.4f",
+		articleID, score, confidence)
+
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		result, err := exec.ExecContext(ctx, `
+			UPDATE articles
+			SET composite_score = ?, confidence = ?, score_source = 'llm'
+			WHERE id = ? AND COALESCE(score_source, '') != 'manual'`,
+			score, confidence, articleID)
+
+		if err != nil {
+			if IsSQLiteBusyError(err) {
+				log.Printf("[RETRY] UpdateArticleScoreLLM for article %d: %v", articleID, err)
 				return err // Will trigger retry
 			}
 			log.Printf("[ERROR][CONFIDENCE] Failed to update article score in database: %v", err)
@@ -774,7 +1597,7 @@ func UpdateArticleScoreLLM(exec sqlx.ExtContext, articleID int64, score float64,
 				rowsAffected, articleID)
 
 			if rowsAffected == 0 {
-				log.Printf("[WARN][CONFIDENCE] No rows updated for articleID=%d - article may not exist", articleID)
+				log.Printf("[WARN][CONFIDENCE] No rows updated for articleID=%d - article may not exist or has a manual score override", articleID)
 			}
 		}
 
@@ -787,6 +1610,255 @@ func UpdateArticleScoreLLM(exec sqlx.ExtContext, articleID int64, score float64,
 	return nil
 }
 
+// Manual score overrides are stored as an llm_scores row under ManualScoreModel
+// and mark the article's score_source as ScoreSourceManual. While an override is
+// in place, LLM rescoring keeps updating model scores but leaves the article's
+// composite score alone.
+const (
+	ManualScoreModel  = "manual"
+	ScoreSourceManual = "manual"
+)
+
+// ManualScoreMetadata is stored in the metadata column of a manual score record
+type ManualScoreMetadata struct {
+	Manual bool   `json:"manual"`
+	Reason string `json:"reason"`
+	// PriorConfidence is the article's confidence before it was first pinned,
+	// restored when the override is cleared
+	PriorConfidence *float64 `json:"prior_confidence,omitempty"`
+}
+
+// fetchManualScoreMetadata returns the metadata of an article's manual override,
+// or nil when there is none
+func fetchManualScoreMetadata(tx *sqlx.Tx, articleID int64) (*ManualScoreMetadata, error) {
+	var raw string
+	err := tx.Get(&raw, `SELECT metadata FROM llm_scores WHERE article_id = ? AND model = ?`, articleID, ManualScoreModel)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var meta ManualScoreMetadata
+	if err := json.Unmarshal([]byte(raw), &meta); err != nil {
+		log.Printf("Ignoring malformed manual score metadata for article %d: %v", articleID, err)
+	}
+	return &meta, nil
+}
+
+// SetManualScore pins an article's composite score to an editor-provided value
+func SetManualScore(db *sqlx.DB, articleID int64, score float64, reason string) error {
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		tx, err := db.Beginx()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		// Re-pinning keeps the confidence from before the first override
+		existing, err := fetchManualScoreMetadata(tx, articleID)
+		if err != nil {
+			return err
+		}
+		var prior *float64
+		if existing != nil {
+			prior = existing.PriorConfidence
+		} else if err := tx.Get(&prior, `SELECT confidence FROM articles WHERE id = ?`, articleID); err != nil &&
+			!errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		meta, err := json.Marshal(ManualScoreMetadata{Manual: true, Reason: reason, PriorConfidence: prior})
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`
+			INSERT INTO llm_scores (article_id, model, score, metadata, version, created_at)
+			VALUES (?, ?, ?, ?, 1, ?)
+			ON CONFLICT (article_id, model) DO UPDATE SET
+				score = excluded.score,
+				metadata = excluded.metadata,
+				created_at = excluded.created_at`,
+			articleID, ManualScoreModel, score, string(meta), time.Now()); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+			UPDATE articles
+			SET composite_score = ?, confidence = 1.0, score_source = ?
+			WHERE id = ?`,
+			score, ScoreSourceManual, articleID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return handleError(err, "failed to set manual score")
+	}
+	return nil
+}
+
+// ClearManualScore removes an article's manual override and restores the latest
+// ensemble score, if any, and the confidence from before the override. It returns
+// false when no override existed.
+func ClearManualScore(db *sqlx.DB, articleID int64) (bool, error) {
+	var cleared bool
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		tx, err := db.Beginx()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		meta, err := fetchManualScoreMetadata(tx, articleID)
+		if err != nil {
+			return err
+		}
+		cleared = meta != nil
+		if !cleared {
+			return nil
+		}
+
+		if _, err := tx.Exec(`DELETE FROM llm_scores WHERE article_id = ? AND model = ?`, articleID, ManualScoreModel); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+			UPDATE articles
+			SET composite_score = (SELECT score FROM llm_scores WHERE article_id = ? AND model = 'ensemble'),
+				confidence = ?,
+				score_source = 'llm'
+			WHERE id = ?`,
+			articleID, meta.PriorConfidence, articleID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	if err != nil {
+		return false, handleError(err, "failed to clear manual score")
+	}
+	return cleared, nil
+}
+
+// FetchManualScore returns an article's manual override, or nil when there is none
+func FetchManualScore(db *sqlx.DB, articleID int64) (*LLMScore, error) {
+	var score LLMScore
+	err := db.Get(&score, "SELECT * FROM llm_scores WHERE article_id = ? AND model = ?", articleID, ManualScoreModel)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, handleError(err, "failed to fetch manual score")
+	}
+	return &score, nil
+}
+
+// AuditEntry records a single administrative action. The audit_log table is
+// append-only: triggers reject updates and deletes.
+type AuditEntry struct {
+	ID        int64     `db:"id" json:"id"`
+	Actor     string    `db:"actor" json:"actor"`
+	Action    string    `db:"action" json:"action"`
+	Target    string    `db:"target" json:"target"`
+	Details   string    `db:"details" json:"details"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// InsertAuditEntry appends an entry to the audit log
+func InsertAuditEntry(db *sqlx.DB, entry *AuditEntry) (int64, error) {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	var id int64
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		result, err := db.NamedExec(`
+			INSERT INTO audit_log (actor, action, target, details, created_at)
+			VALUES (:actor, :action, :target, :details, :created_at)`, entry)
+		if err != nil {
+			return err
+		}
+		id, err = result.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return 0, handleError(err, "failed to insert audit entry")
+	}
+	entry.ID = id
+	return id, nil
+}
+
+// FetchAuditEntries returns audit entries newest first along with the total count
+func FetchAuditEntries(db *sqlx.DB, limit int, offset int) ([]AuditEntry, int, error) {
+	var total int
+	if err := db.Get(&total, "SELECT COUNT(*) FROM audit_log"); err != nil {
+		return nil, 0, handleError(err, "failed to count audit entries")
+	}
+
+	entries := []AuditEntry{}
+	err := db.Select(&entries, `
+		SELECT id, actor, action, COALESCE(target, '') AS target, COALESCE(details, '') AS details, created_at
+		FROM audit_log
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?`, limit, offset)
+	if err != nil {
+		return nil, 0, handleError(err, "failed to fetch audit entries")
+	}
+	return entries, total, nil
+}
+
+// FetchPendingRetryArticleIDs returns the articles whose scoring was interrupted and
+// left in the pending_retry status
+func FetchPendingRetryArticleIDs(db *sqlx.DB) ([]int64, error) {
+	var ids []int64
+	if err := db.Select(&ids, `SELECT id FROM articles WHERE status = ? ORDER BY id`, models.ArticleStatusPendingRetry); err != nil {
+		return nil, handleError(err, "failed to fetch articles pending retry")
+	}
+	return ids, nil
+}
+
+// IncrementScoringRetries counts one more retry of an interrupted article's scoring and
+// returns how many retries it has had, across server restarts
+func IncrementScoringRetries(db *sqlx.DB, articleID int64) (int, error) {
+	_, err := db.Exec(`INSERT INTO scoring_retries (article_id, attempts) VALUES (?, 1)
+		ON CONFLICT(article_id) DO UPDATE SET attempts = attempts + 1, updated_at = CURRENT_TIMESTAMP`, articleID)
+	if err != nil {
+		return 0, handleError(err, "failed to count scoring retry")
+	}
+	var attempts int
+	if err := db.Get(&attempts, `SELECT attempts FROM scoring_retries WHERE article_id = ?`, articleID); err != nil {
+		return 0, handleError(err, "failed to count scoring retry")
+	}
+	return attempts, nil
+}
+
+// ClearScoringRetries forgets the retries of an article once it no longer needs them
+func ClearScoringRetries(db *sqlx.DB, articleID int64) error {
+	if _, err := db.Exec(`DELETE FROM scoring_retries WHERE article_id = ?`, articleID); err != nil {
+		return handleError(err, "failed to clear scoring retries")
+	}
+	return nil
+}
+
+// TokenCounts is the token usage a provider reported for one LLM call
+type TokenCounts struct {
+	Prompt     int
+	Completion int
+	Total      int
+}
+
+// InsertLLMUsage records one LLM call made to model for cost reporting. tokens is nil
+// when the provider reported no usage; the call is still counted.
+func InsertLLMUsage(db *sqlx.DB, model string, tokens *TokenCounts) error {
+	var prompt, completion, total interface{}
+	if tokens != nil {
+		prompt, completion, total = tokens.Prompt, tokens.Completion, tokens.Total
+	}
+	_, err := db.Exec(`INSERT INTO llm_usage (model, prompt_tokens, completion_tokens, total_tokens)
+		VALUES (?, ?, ?, ?)`, model, prompt, completion, total)
+	if err != nil {
+		return handleError(err, "failed to record LLM usage")
+	}
+	return nil
+}
+
 // ArticleExistsByURL checks if an article exists with the given URL
 func ArticleExistsByURL(db *sqlx.DB, url string) (bool, error) {
 	var exists bool
@@ -1007,6 +2079,19 @@ func FetchEnabledSources(db *sqlx.DB) ([]Source, error) {
 	return FetchSources(db, &enabled, "", "", 0, 0)
 }
 
+// FetchSourceByName retrieves a single source by name
+func FetchSourceByName(db *sqlx.DB, name string) (*Source, error) {
+	var source Source
+	err := db.Get(&source, "SELECT * FROM sources WHERE name = ?", name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("source not found")
+		}
+		return nil, handleError(err, "failed to fetch source")
+	}
+	return &source, nil
+}
+
 // SourceExistsByName checks if a source exists with the given name
 func SourceExistsByName(db *sqlx.DB, name string) (bool, error) {
 	var exists bool
@@ -1017,6 +2102,179 @@ func SourceExistsByName(db *sqlx.DB, name string) (bool, error) {
 	return exists, nil
 }
 
+// MaxTagLength is the longest tag name, in characters
+const MaxTagLength = 50
+
+// TagCount is an editorial tag and the number of articles carrying it
+type TagCount struct {
+	Name     string `db:"name" json:"name"`
+	Articles int    `db:"articles" json:"articles"`
+}
+
+// NormalizeTag lowercases a tag name and collapses its whitespace, reporting false
+// unless it is 1 to MaxTagLength letters, digits, spaces, hyphens or underscores
+func NormalizeTag(name string) (string, bool) {
+	tag := strings.ToLower(strings.Join(strings.Fields(name), " "))
+	if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+		return "", false
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' && r != '_' {
+			return "", false
+		}
+	}
+	return tag, true
+}
+
+// AddArticleTags tags an article, creating tags that do not exist yet. Names must
+// already be normalized; tags the article already has are left as they are.
+func AddArticleTags(db *sqlx.DB, articleID int64, tags []string) error {
+	return WithRetry(DefaultRetryConfig(), func() (err error) {
+		tx, err := db.Beginx()
+		if err != nil {
+			return handleError(err, "failed to begin transaction for article tags")
+		}
+		defer func() {
+			if err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					log.Printf("[ERROR] Failed to rollback article tags: %v", rollbackErr)
+				}
+			}
+		}()
+
+		for _, tag := range tags {
+			if _, err = tx.Exec(`INSERT OR IGNORE INTO tags (name) VALUES (?)`, tag); err != nil {
+				return handleError(err, "failed to create tag")
+			}
+			if _, err = tx.Exec(`INSERT OR IGNORE INTO article_tags (article_id, tag_id)
+				SELECT ?, id FROM tags WHERE name = ?`, articleID, tag); err != nil {
+				return handleError(err, "failed to tag article")
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// RemoveArticleTag removes a tag from an article, reporting whether it had the tag.
+// The tag itself is kept for other articles and later use.
+func RemoveArticleTag(db *sqlx.DB, articleID int64, tag string) (bool, error) {
+	result, err := db.Exec(`DELETE FROM article_tags WHERE article_id = ?
+		AND tag_id = (SELECT id FROM tags WHERE name = ?)`, articleID, tag)
+	if err != nil {
+		return false, handleError(err, "failed to remove article tag")
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return removed > 0, nil
+}
+
+// FetchArticleTags returns an article's tags in name order
+func FetchArticleTags(db *sqlx.DB, articleID int64) ([]string, error) {
+	tags := []string{}
+	err := db.Select(&tags, `SELECT t.name FROM tags t JOIN article_tags at ON at.tag_id = t.id
+		WHERE at.article_id = ? ORDER BY t.name`, articleID)
+	if err != nil {
+		return nil, handleError(err, "failed to fetch article tags")
+	}
+	return tags, nil
+}
+
+// FetchTagCounts returns every tag with its number of articles, most used first, then
+// by name. Tags no article carries any more are listed with a zero count.
+func FetchTagCounts(db *sqlx.DB) ([]TagCount, error) {
+	counts := []TagCount{}
+	err := db.Select(&counts, `SELECT t.name, COUNT(at.article_id) AS articles
+		FROM tags t LEFT JOIN article_tags at ON at.tag_id = t.id
+		GROUP BY t.id ORDER BY articles DESC, t.name`)
+	if err != nil {
+		return nil, handleError(err, "failed to fetch tags")
+	}
+	return counts, nil
+}
+
+// BiasTrendPoint represents the average composite score of a source over one period
+type BiasTrendPoint struct {
+	Period   string  `json:"period"`
+	AvgScore float64 `json:"avg_score"`
+	Count    int     `json:"count"`
+}
+
+// Supported bias trend intervals
+const (
+	TrendIntervalDay  = "day"
+	TrendIntervalWeek = "week"
+)
+
+// FetchBiasTrend aggregates composite scores into day or week buckets, optionally
+// restricted to a single source. Articles are bucketed by pub_date, falling back to
+// created_at when no published date is recorded. Periods without scored articles are
+// omitted; weeks start on Monday (UTC) and are labelled with that Monday's date.
+func FetchBiasTrend(db *sqlx.DB, source string, interval string) ([]BiasTrendPoint, error) {
+	if interval != TrendIntervalDay && interval != TrendIntervalWeek {
+		return nil, fmt.Errorf("unsupported trend interval: %s", interval)
+	}
+
+	query := `SELECT pub_date, created_at, composite_score FROM articles WHERE composite_score IS NOT NULL`
+	var args []interface{}
+	if source != "" {
+		query += " AND source = ?"
+		args = append(args, source)
+	}
+
+	var rows []struct {
+		PubDate        *time.Time `db:"pub_date"`
+		CreatedAt      time.Time  `db:"created_at"`
+		CompositeScore float64    `db:"composite_score"`
+	}
+	if err := db.Select(&rows, query, args...); err != nil {
+		return nil, handleError(err, "failed to fetch bias trend")
+	}
+
+	type bucket struct {
+		sum   float64
+		count int
+	}
+	buckets := make(map[string]*bucket)
+	for _, r := range rows {
+		ts := r.CreatedAt
+		if r.PubDate != nil && !r.PubDate.IsZero() {
+			ts = *r.PubDate
+		}
+		period := trendPeriod(ts, interval)
+		b, ok := buckets[period]
+		if !ok {
+			b = &bucket{}
+			buckets[period] = b
+		}
+		b.sum += r.CompositeScore
+		b.count++
+	}
+
+	points := make([]BiasTrendPoint, 0, len(buckets))
+	for period, b := range buckets {
+		points = append(points, BiasTrendPoint{
+			Period:   period,
+			AvgScore: b.sum / float64(b.count),
+			Count:    b.count,
+		})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Period < points[j].Period })
+	return points, nil
+}
+
+// trendPeriod returns the bucket label for a timestamp
+func trendPeriod(ts time.Time, interval string) string {
+	ts = ts.UTC()
+	day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == TrendIntervalWeek {
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day.Format("2006-01-02")
+}
+
 // InitDB initializes and returns a database connection to the specified SQLite database file
 func InitDB(dbPath string) (*sqlx.DB, error) {
 	// Open SQLite database connection
@@ -1092,9 +2350,15 @@ func InitDB(dbPath string) (*sqlx.DB, error) {
 		escalated BOOLEAN DEFAULT 0,
 		composite_score REAL,
 		confidence REAL,
-		score_source TEXT
+		score_source TEXT,
+		image_url TEXT,
+		authors TEXT,
+		categories TEXT
 	);
 
+	CREATE INDEX IF NOT EXISTS idx_articles_pub_date ON articles(pub_date);
+	CREATE INDEX IF NOT EXISTS idx_articles_created_at ON articles(created_at);
+
 	CREATE TABLE IF NOT EXISTS llm_scores (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		article_id INTEGER NOT NULL,
@@ -1109,6 +2373,19 @@ func InitDB(dbPath string) (*sqlx.DB, error) {
 
 	CREATE INDEX IF NOT EXISTS idx_llm_scores_article_version ON llm_scores(article_id, version);
 
+	CREATE TABLE IF NOT EXISTS score_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		article_id INTEGER NOT NULL,
+		version INTEGER NOT NULL,
+		score REAL NOT NULL,
+		confidence REAL,
+		kind TEXT NOT NULL DEFAULT 'analysis',
+		content_hash TEXT,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (article_id) REFERENCES articles (id),
+		UNIQUE(article_id, version)
+	);
+
 	CREATE TABLE IF NOT EXISTS feedback (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		article_id INTEGER NOT NULL,
@@ -1117,6 +2394,8 @@ func InitDB(dbPath string) (*sqlx.DB, error) {
 		category TEXT,
 		ensemble_output_id INTEGER,
 		source TEXT,
+		idempotency_key TEXT,
+		status TEXT NOT NULL DEFAULT 'new',
 		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (article_id) REFERENCES articles (id)
 	);
@@ -1132,6 +2411,53 @@ func InitDB(dbPath string) (*sqlx.DB, error) {
 		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 	);
 
+	CREATE INDEX IF NOT EXISTS idx_labels_labeler_data ON labels(labeler, trim(data, char(32, 9, 10, 11, 12, 13)));
+
+	CREATE TABLE IF NOT EXISTS label_reviews (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		label_id INTEGER NOT NULL,
+		previous_label TEXT NOT NULL,
+		corrected_label TEXT NOT NULL,
+		error_category TEXT,
+		reviewed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (label_id) REFERENCES labels(id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_label_reviews_label_id ON label_reviews(label_id);
+
+	CREATE TABLE IF NOT EXISTS validation_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		total INTEGER NOT NULL,
+		correct INTEGER NOT NULL,
+		incorrect INTEGER NOT NULL,
+		uncertain INTEGER NOT NULL,
+		disagreements INTEGER NOT NULL,
+		accuracy REAL NOT NULL,
+		precision REAL NOT NULL,
+		recall REAL NOT NULL,
+		f1 REAL NOT NULL,
+		confusion_matrix TEXT NOT NULL,
+		per_class TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS scoring_retries (
+		article_id INTEGER PRIMARY KEY,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS llm_usage (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		model TEXT NOT NULL,
+		prompt_tokens INTEGER,
+		completion_tokens INTEGER,
+		total_tokens INTEGER,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_llm_usage_created_at ON llm_usage(created_at);
+
 	CREATE TABLE IF NOT EXISTS sources (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		name TEXT NOT NULL UNIQUE,
@@ -1159,6 +2485,44 @@ func InitDB(dbPath string) (*sqlx.DB, error) {
 		computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (source_id) REFERENCES sources (id)
 	);
+
+	CREATE TABLE IF NOT EXISTS tags (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS article_tags (
+		article_id INTEGER NOT NULL,
+		tag_id INTEGER NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (article_id, tag_id),
+		FOREIGN KEY (article_id) REFERENCES articles (id),
+		FOREIGN KEY (tag_id) REFERENCES tags (id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_article_tags_tag_id ON article_tags(tag_id);
+
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		actor TEXT NOT NULL,
+		action TEXT NOT NULL,
+		target TEXT,
+		details TEXT,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+
+	CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+	BEGIN
+		SELECT RAISE(ABORT, 'audit_log is append-only');
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+	BEGIN
+		SELECT RAISE(ABORT, 'audit_log is append-only');
+	END;
 	`
 
 	// Initialize database schema
@@ -1171,6 +2535,20 @@ func InitDB(dbPath string) (*sqlx.DB, error) {
 		return nil, err
 	}
 
+	if err := addMissingColumns(db); err != nil {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Printf("Error closing DB after column migration failure: %v", closeErr)
+		}
+		return nil, err
+	}
+
+	if err := normalizeArticleDates(db); err != nil {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Printf("Error closing DB after date migration failure: %v", closeErr)
+		}
+		return nil, err
+	}
+
 	if err := validateDBSchema(db); err != nil {
 		if closeErr := db.Close(); closeErr != nil {
 			log.Printf("Error closing DB after schema validation failure: %v", closeErr)
@@ -1182,6 +2560,25 @@ func InitDB(dbPath string) (*sqlx.DB, error) {
 	return db, nil
 }
 
+// OpenReadOnly opens an existing SQLite database without creating or migrating it,
+// for tools that only read
+func OpenReadOnly(dbPath string) (*sqlx.DB, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db, err := sqlx.Open("sqlite", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err = db.Ping(); err != nil {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Printf("Error closing DB after ping failure: %v", closeErr)
+		}
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return db, nil
+}
+
 // UpdateArticleStatus updates the status of a specific article.
 func UpdateArticleStatus(exec sqlx.ExtContext, articleID int64, status string) error {
 	query := `UPDATE articles SET status = ? WHERE id = ?`
@@ -1206,6 +2603,219 @@ func UpdateArticleStatus(exec sqlx.ExtContext, articleID int64, status string) e
 	return nil
 }
 
+// RetentionPolicy decides which articles a cleanup removes: those ingested more than
+// MaxAge ago and those beyond the newest MaxCount. A zero field disables that limit.
+type RetentionPolicy struct {
+	MaxAge   time.Duration
+	MaxCount int
+}
+
+// Enabled reports whether the policy limits anything
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxCount > 0
+}
+
+// ArticleCleanup counts the rows an article cleanup removed, or would remove in a dry run
+type ArticleCleanup struct {
+	Articles     int64 `json:"articles"`
+	LLMScores    int64 `json:"llm_scores"`
+	ScoreHistory int64 `json:"score_history"`
+	Feedback     int64 `json:"feedback"`
+	ArticleTags  int64 `json:"article_tags"`
+}
+
+// add sums the counts of another cleanup into c
+func (c *ArticleCleanup) add(other ArticleCleanup) {
+	c.Articles += other.Articles
+	c.LLMScores += other.LLMScores
+	c.ScoreHistory += other.ScoreHistory
+	c.Feedback += other.Feedback
+	c.ArticleTags += other.ArticleTags
+}
+
+// articleDependentTables are the tables whose rows belong to an article through
+// article_id; they are cleaned up together with it
+var articleDependentTables = []string{"llm_scores", "score_history", "feedback", "article_tags"}
+
+// FetchExpiredArticleIDs returns the IDs of up to limit articles outside the policy at
+// now, oldest first. A non-positive limit returns all of them.
+func FetchExpiredArticleIDs(db *sqlx.DB, policy RetentionPolicy, now time.Time, limit int) ([]int64, error) {
+	var conditions []string
+	var args []interface{}
+	if policy.MaxAge > 0 {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, now.Add(-policy.MaxAge).UTC())
+	}
+	if policy.MaxCount > 0 {
+		conditions = append(conditions, "id NOT IN (SELECT id FROM articles ORDER BY created_at DESC, id DESC LIMIT ?)")
+		args = append(args, policy.MaxCount)
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = -1 // SQLite for no limit
+	}
+	args = append(args, limit)
+
+	var ids []int64
+	query := "SELECT id FROM articles WHERE " + strings.Join(conditions, " OR ") + " ORDER BY created_at ASC, id ASC LIMIT ?"
+	if err := db.Select(&ids, query, args...); err != nil {
+		return nil, handleError(err, "failed to fetch expired articles")
+	}
+	return ids, nil
+}
+
+// CountArticleRows counts the given articles and the rows that belong to them, which
+// is what DeleteArticles would remove
+func CountArticleRows(db *sqlx.DB, ids []int64) (ArticleCleanup, error) {
+	var counts ArticleCleanup
+	if len(ids) == 0 {
+		return counts, nil
+	}
+	targets := map[string]*int64{
+		"articles":      &counts.Articles,
+		"llm_scores":    &counts.LLMScores,
+		"score_history": &counts.ScoreHistory,
+		"feedback":      &counts.Feedback,
+		"article_tags":  &counts.ArticleTags,
+	}
+	for table, count := range targets {
+		column := "article_id"
+		if table == "articles" {
+			column = "id"
+		}
+		query, args, err := sqlx.In(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s IN (?)", table, column), ids)
+		if err != nil {
+			return ArticleCleanup{}, err
+		}
+		if err := db.Get(count, db.Rebind(query), args...); err != nil {
+			return ArticleCleanup{}, handleError(err, "failed to count "+table)
+		}
+	}
+	return counts, nil
+}
+
+// DeleteArticles deletes the given articles together with their scores, score history,
+// feedback and tag assignments in one transaction, so a failure leaves them all in place
+func DeleteArticles(db *sqlx.DB, ids []int64) (ArticleCleanup, error) {
+	var deleted ArticleCleanup
+	if len(ids) == 0 {
+		return deleted, nil
+	}
+	err := WithRetry(DefaultRetryConfig(), func() (err error) {
+		deleted = ArticleCleanup{}
+		tx, err := db.Beginx()
+		if err != nil {
+			return handleError(err, "failed to begin transaction for article cleanup")
+		}
+		defer func() {
+			if err != nil {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil {
+					log.Printf("[ERROR] Failed to rollback article cleanup: %v", rollbackErr)
+				}
+			}
+		}()
+
+		targets := []struct {
+			table, column string
+			count         *int64
+		}{
+			{"llm_scores", "article_id", &deleted.LLMScores},
+			{"score_history", "article_id", &deleted.ScoreHistory},
+			{"feedback", "article_id", &deleted.Feedback},
+			{"article_tags", "article_id", &deleted.ArticleTags},
+			{"articles", "id", &deleted.Articles},
+		}
+		for _, target := range targets {
+			query, args, err := sqlx.In(fmt.Sprintf("DELETE FROM %s WHERE %s IN (?)", target.table, target.column), ids)
+			if err != nil {
+				return err
+			}
+			result, err := tx.Exec(tx.Rebind(query), args...)
+			if err != nil {
+				return handleError(err, "failed to delete from "+target.table)
+			}
+			if *target.count, err = result.RowsAffected(); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+	return deleted, err
+}
+
+// DeleteOrphanedArticleRows removes scores, score history, feedback and tag assignments
+// whose article no longer exists, such as those left by clearing the articles table by
+// hand. With dryRun it only counts them.
+func DeleteOrphanedArticleRows(db *sqlx.DB, dryRun bool) (ArticleCleanup, error) {
+	var orphans ArticleCleanup
+	counts := []*int64{&orphans.LLMScores, &orphans.ScoreHistory, &orphans.Feedback, &orphans.ArticleTags}
+	for i, table := range articleDependentTables {
+		condition := fmt.Sprintf(" FROM %s WHERE article_id NOT IN (SELECT id FROM articles)", table)
+		if dryRun {
+			if err := db.Get(counts[i], "SELECT COUNT(*)"+condition); err != nil {
+				return orphans, handleError(err, "failed to count orphaned "+table)
+			}
+			continue
+		}
+		result, err := db.Exec("DELETE" + condition)
+		if err != nil {
+			return orphans, handleError(err, "failed to delete orphaned "+table)
+		}
+		if *counts[i], err = result.RowsAffected(); err != nil {
+			return orphans, err
+		}
+	}
+	return orphans, nil
+}
+
+// CleanupArticles applies a retention policy: it deletes the articles outside it in
+// batches of batchSize, each batch with its dependent rows in one transaction, and
+// then any dependent rows already orphaned. With dryRun it only counts what would be
+// removed. Batches deleted before an error stay deleted and are counted.
+func CleanupArticles(db *sqlx.DB, policy RetentionPolicy, now time.Time, batchSize int, dryRun bool) (ArticleCleanup, error) {
+	var total ArticleCleanup
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	if policy.Enabled() {
+		if dryRun {
+			// Nothing is deleted, so take every expired article at once
+			ids, err := FetchExpiredArticleIDs(db, policy, now, 0)
+			if err != nil {
+				return total, err
+			}
+			for start := 0; start < len(ids); start += batchSize {
+				counts, err := CountArticleRows(db, ids[start:min(start+batchSize, len(ids))])
+				if err != nil {
+					return total, err
+				}
+				total.add(counts)
+			}
+		} else {
+			for {
+				ids, err := FetchExpiredArticleIDs(db, policy, now, batchSize)
+				if err != nil {
+					return total, err
+				}
+				if len(ids) == 0 {
+					break
+				}
+				deleted, err := DeleteArticles(db, ids)
+				if err != nil {
+					return total, err
+				}
+				total.add(deleted)
+			}
+		}
+	}
+
+	orphans, err := DeleteOrphanedArticleRows(db, dryRun)
+	total.add(orphans)
+	return total, err
+}
+
 // RetryConfig holds configuration for database retry operations
 type RetryConfig struct {
 	MaxAttempts   int
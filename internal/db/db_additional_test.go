@@ -30,7 +30,10 @@ func openTestDB(t *testing.T) *sqlx.DB {
 			status TEXT, -- Added missing column
 			fail_count INTEGER,
 			last_attempt TIMESTAMP,
-			escalated BOOLEAN
+			escalated BOOLEAN,
+			image_url TEXT,
+			authors TEXT,
+			categories TEXT
 		);
 
 		CREATE TABLE IF NOT EXISTS llm_scores (
@@ -52,6 +55,8 @@ func openTestDB(t *testing.T) *sqlx.DB {
 			category TEXT,
 			ensemble_output_id INTEGER,
 			source TEXT,
+			idempotency_key TEXT,
+			status TEXT NOT NULL DEFAULT 'new',
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (article_id) REFERENCES articles (id)
 		);
@@ -151,6 +156,69 @@ func TestInsertLabelAndFeedback(t *testing.T) {
 	assert.Greater(t, feedback.ID, int64(0))
 }
 
+func TestNormalizeLabel(t *testing.T) {
+	for in, want := range map[string]string{"Left": "left", " -1.0 ": "left", "RIGHT": "right", "1": "right", "neutral": "neutral", "0.0": "neutral"} {
+		got, ok := db.NormalizeLabel(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "lbl", "center-left", "0.5"} {
+		_, ok := db.NormalizeLabel(in)
+		assert.False(t, ok, in)
+	}
+}
+
+func TestLabelUpsertHelpers(t *testing.T) {
+	dbConn := openTestDB(t)
+	insert := func(data, labeler string) *db.Label {
+		label := &db.Label{Data: data, Label: "left", Source: "src", DateLabeled: time.Now(), Labeler: labeler, Confidence: 1, CreatedAt: time.Now()}
+		assert.NoError(t, db.InsertLabel(dbConn, label))
+		return label
+	}
+	first := insert("some text", "alice")
+	insert("other text", "alice")
+	insert("some text", "bob")
+
+	ids, err := db.FetchLabelIDsByData(dbConn, "alice", []string{"  some text\n", "other text", "unknown text"})
+	assert.NoError(t, err)
+	assert.Len(t, ids, 2)
+	assert.Equal(t, first.ID, ids[db.LabelDataHash("  some text\n")], "surrounding whitespace does not change the hash")
+
+	first.Label = "right"
+	first.Confidence = 0.5
+	assert.NoError(t, db.UpdateLabel(dbConn, first))
+	var stored db.Label
+	assert.NoError(t, dbConn.Get(&stored, "SELECT * FROM labels WHERE id = ?", first.ID))
+	assert.Equal(t, "right", stored.Label)
+	assert.Equal(t, 0.5, stored.Confidence)
+
+	first.ID = 9999
+	assert.Error(t, db.UpdateLabel(dbConn, first))
+}
+
+func TestInsertLabels(t *testing.T) {
+	dbConn := openTestDB(t)
+	batch := []*db.Label{
+		{Data: "a", Label: "left", Source: "src", DateLabeled: time.Now(), Labeler: "u1", CreatedAt: time.Now()},
+		{Data: "b", Label: "right", Source: "src", DateLabeled: time.Now(), Labeler: "u1", CreatedAt: time.Now()},
+	}
+	assert.NoError(t, db.InsertLabels(dbConn, batch))
+	assert.Greater(t, batch[0].ID, int64(0))
+	assert.Equal(t, batch[0].ID+1, batch[1].ID)
+
+	// A failing row rolls back the whole batch
+	bad := []*db.Label{
+		{Data: "c", Label: "left", Source: "src", DateLabeled: time.Now(), Labeler: "u1", CreatedAt: time.Now()},
+		{Data: "d", Label: "left", Source: "src", DateLabeled: time.Now(), Labeler: "u1", CreatedAt: time.Now()},
+	}
+	_, err := dbConn.Exec("CREATE TRIGGER reject_d BEFORE INSERT ON labels WHEN NEW.data = 'd' BEGIN SELECT RAISE(ABORT, 'rejected'); END")
+	assert.NoError(t, err)
+	assert.Error(t, db.InsertLabels(dbConn, bad))
+	var count int
+	assert.NoError(t, dbConn.Get(&count, "SELECT COUNT(*) FROM labels"))
+	assert.Equal(t, 2, count)
+}
+
 func TestUpdateArticleScoreLLM(t *testing.T) {
 	dbConn := openTestDB(t)
 
@@ -31,7 +31,10 @@ func openFilterTestDB(t *testing.T) *sqlx.DB {
 			status TEXT, -- Added missing column
 			fail_count INTEGER,
 			last_attempt TIMESTAMP,
-			escalated BOOLEAN
+			escalated BOOLEAN,
+			image_url TEXT,
+			authors TEXT,
+			categories TEXT
 		);
 
 		CREATE TABLE IF NOT EXISTS llm_scores (
@@ -53,6 +56,8 @@ func openFilterTestDB(t *testing.T) *sqlx.DB {
 			category TEXT,
 			ensemble_output_id INTEGER,
 			source TEXT,
+			idempotency_key TEXT,
+			status TEXT NOT NULL DEFAULT 'new',
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (article_id) REFERENCES articles (id)
 		);
@@ -121,6 +126,101 @@ func TestFetchArticlesFiltering(t *testing.T) {
 	assert.Len(t, right, 1)
 }
 
+func TestFetchArticlesByAuthorAndCategory(t *testing.T) {
+	dbConn := openFilterTestDB(t)
+	articles := []*db.Article{
+		{Authors: db.StringList{"Jane Doe", "John Roe"}, Categories: db.StringList{"Politics"}},
+		{Authors: db.StringList{"john roe"}, Categories: db.StringList{"Economy", "Politics"}},
+		{}, // no authors or categories
+	}
+	for i, a := range articles {
+		a.Source, a.PubDate, a.URL, a.Title, a.Content = "A", time.Now(), "url"+strconv.Itoa(i), "t", "c"
+		_, err := db.InsertArticle(dbConn, a)
+		assert.NoError(t, err)
+	}
+	// A malformed list from an older writer must not break the query
+	_, err := dbConn.Exec(`UPDATE articles SET categories = 'Politics' WHERE url = 'url2'`)
+	assert.NoError(t, err)
+
+	byAuthor, err := db.FetchArticlesFiltered(dbConn, db.ArticleFilter{Author: "JOHN ROE", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, byAuthor, 2)
+
+	byCategory, err := db.FetchArticlesFiltered(dbConn, db.ArticleFilter{Category: "politics", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, byCategory, 2)
+
+	both := db.ArticleFilter{Author: "Jane Doe", Category: "Politics"}
+	count, err := db.CountArticlesFiltered(dbConn, both)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	none, err := db.FetchArticlesFiltered(dbConn, db.ArticleFilter{Author: "Jane", Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, none, "author matches are exact, not substrings")
+
+	stored, err := db.FetchArticleByID(dbConn, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, db.StringList{"Economy", "Politics"}, stored.Categories)
+
+	malformed, err := db.FetchArticleByID(dbConn, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, db.StringList{"Politics"}, malformed.Categories)
+}
+
+func TestFetchArticlesSorted(t *testing.T) {
+	dbConn := openFilterTestDB(t)
+	scores := []struct {
+		score, confidence float64
+		scored            bool
+	}{{0.5, 0.4, true}, {-0.7, 0.9, true}, {0, 0, false}, {0.1, 0.6, true}}
+	base := time.Now().Add(-time.Hour)
+	for i, s := range scores {
+		id, err := db.InsertArticle(dbConn, &db.Article{
+			Source: "A", PubDate: base, URL: "url" + strconv.Itoa(i), Title: "t", Content: "c",
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		})
+		assert.NoError(t, err)
+		if s.scored {
+			assert.NoError(t, db.UpdateArticleScore(dbConn, id, s.score, s.confidence))
+		}
+	}
+	ids := func(sort string) []int64 {
+		articles, err := db.FetchArticlesFiltered(dbConn, db.ArticleFilter{Sort: sort, Limit: 10})
+		assert.NoError(t, err)
+		out := make([]int64, len(articles))
+		for i, a := range articles {
+			out[i] = a.ID
+		}
+		return out
+	}
+
+	assert.Equal(t, []int64{4, 3, 2, 1}, ids(""))
+	assert.Equal(t, []int64{4, 3, 2, 1}, ids(db.ArticleSortNewest))
+	assert.Equal(t, []int64{2, 4, 1, 3}, ids(db.ArticleSortScoreAsc), "unscored articles come last")
+	assert.Equal(t, []int64{1, 4, 2, 3}, ids(db.ArticleSortScoreDesc))
+	assert.Equal(t, []int64{2, 4, 1, 3}, ids(db.ArticleSortConfidence))
+	assert.Equal(t, []int64{4, 3, 2, 1}, ids("bogus"), "unknown sorts fall back to newest")
+	assert.True(t, db.IsArticleSort(db.ArticleSortConfidence))
+	assert.False(t, db.IsArticleSort("bogus"))
+}
+
+func TestFetchArticlesOmitContent(t *testing.T) {
+	dbConn := openFilterTestDB(t)
+	_, err := db.InsertArticle(dbConn, &db.Article{
+		Source: "A", PubDate: time.Now(), URL: "url", Title: "t", Content: "long body", Authors: db.StringList{"Ann"},
+	})
+	assert.NoError(t, err)
+
+	articles, err := db.FetchArticlesFiltered(dbConn, db.ArticleFilter{Limit: 10, OmitContent: true})
+	assert.NoError(t, err)
+	if assert.Len(t, articles, 1) {
+		assert.Empty(t, articles[0].Content)
+		assert.Equal(t, "t", articles[0].Title)
+		assert.Equal(t, db.StringList{"Ann"}, articles[0].Authors)
+	}
+}
+
 func TestMigrateSchemaIdempotent(t *testing.T) {
 	// calling migrateSchema multiple times should not error
 	_, err := db.New(":memory:")
@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -423,3 +424,41 @@ func TestArticleWithNewFields(t *testing.T) {
 	assert.NotNil(t, updated.LastAttempt)
 	assert.Equal(t, true, *updated.Escalated)
 }
+
+func TestArticleColumnsWithoutContentMatchesSchema(t *testing.T) {
+	db, err := InitDB(":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var columns []string
+	assert.NoError(t, db.Select(&columns, `SELECT name FROM pragma_table_info('articles') WHERE name != 'content'`))
+	listed := strings.Split(articleColumnsWithoutContent, ",")
+	for i := range listed {
+		listed[i] = strings.TrimSpace(listed[i])
+	}
+	sort.Strings(columns)
+	sort.Strings(listed)
+	assert.Equal(t, columns, listed, "OmitContent must select every articles column but content")
+}
+
+func TestInitDBNormalizesArticleDates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dates.db")
+	db, err := InitDB(path)
+	assert.NoError(t, err)
+	local := time.Date(2025, time.March, 25, 1, 0, 0, 0, time.FixedZone("", 5*3600))
+	_, err = db.Exec(`INSERT INTO articles (source, pub_date, created_at, url, title, content)
+		VALUES ('src', ?, ?, 'https://example.com/offset', 'title', 'content')`, local, local)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close())
+
+	db, err = InitDB(path)
+	assert.NoError(t, err)
+	defer db.Close()
+	var stored struct {
+		PubDate   string `db:"pub_date"`
+		CreatedAt string `db:"created_at"`
+	}
+	assert.NoError(t, db.Get(&stored, `SELECT CAST(pub_date AS TEXT) AS pub_date, CAST(created_at AS TEXT) AS created_at FROM articles`))
+	assert.Equal(t, "2025-03-24 20:00:00 +0000 UTC", stored.PubDate)
+	assert.Equal(t, "2025-03-24 20:00:00 +0000 UTC", stored.CreatedAt)
+}
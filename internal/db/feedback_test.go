@@ -0,0 +1,102 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertFeedbackBatch(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "feedback.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Feedback reading "boom" fails to insert, standing in for any per-item database error
+	_, err = db.Exec(`CREATE TRIGGER feedback_boom BEFORE INSERT ON feedback WHEN NEW.feedback_text = 'boom'
+		BEGIN SELECT RAISE(ABORT, 'boom'); END`)
+	require.NoError(t, err)
+
+	key := func(k string) *string { return &k }
+	item := func(text string, idempotencyKey *string) *Feedback {
+		return &Feedback{ArticleID: 1, UserID: "u", FeedbackText: text, Category: "agree", IdempotencyKey: idempotencyKey, CreatedAt: time.Now()}
+	}
+
+	results, err := InsertFeedbackBatch(db, []*Feedback{
+		item("first", key("k1")),
+		item("boom", key("k2")),
+		item("again", key("k1")),
+		item("no key", nil),
+		item("no key either", nil),
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	assert.NoError(t, results[0].Err)
+	assert.False(t, results[0].Duplicate)
+	assert.Error(t, results[1].Err, "a failed item is reported")
+	assert.True(t, results[2].Duplicate, "a key used earlier in the batch is not stored twice")
+	assert.Equal(t, results[0].ID, results[2].ID)
+	assert.NoError(t, results[3].Err)
+	assert.NoError(t, results[4].Err)
+	assert.NotEqual(t, results[3].ID, results[4].ID, "items without a key are never duplicates")
+
+	var count int
+	require.NoError(t, db.Get(&count, `SELECT COUNT(*) FROM feedback`))
+	assert.Equal(t, 3, count, "the failed item does not abort the rest of the batch")
+
+	// Sending the batch again stores nothing new for the keyed items
+	results, err = InsertFeedbackBatch(db, []*Feedback{item("first", key("k1")), item("retry", key("k2"))})
+	require.NoError(t, err)
+	assert.True(t, results[0].Duplicate)
+	assert.False(t, results[1].Duplicate, "the failed item was never stored")
+	assert.NoError(t, results[1].Err)
+}
+
+func TestFeedbackStatus(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "feedback.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	var ids []int64
+	for i, category := range []string{"disagree", "agree"} {
+		f := &Feedback{ArticleID: 7, UserID: "u", FeedbackText: "text", Category: category, CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, InsertFeedback(db, f))
+		assert.Equal(t, FeedbackStatusNew, f.Status)
+		ids = append(ids, f.ID)
+	}
+
+	category, err := FetchLatestFeedbackCategory(db, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "agree", category)
+
+	require.NoError(t, UpdateFeedbackStatus(db, ids[1], FeedbackStatusSpam))
+	category, err = FetchLatestFeedbackCategory(db, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "disagree", category, "spam is skipped")
+	category, err = FetchLatestFeedbackCategory(db, 8)
+	require.NoError(t, err)
+	assert.Empty(t, category)
+
+	spam, total, err := FetchFeedback(db, FeedbackStatusSpam, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, spam, 1)
+	assert.Equal(t, ids[1], spam[0].ID)
+	all, total, err := FetchFeedback(db, "", 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, all, 1)
+	assert.Equal(t, ids[1], all[0].ID, "newest first")
+
+	fetched, err := FetchFeedbackByID(db, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, FeedbackStatusNew, fetched.Status)
+
+	assert.ErrorIs(t, UpdateFeedbackStatus(db, 999, FeedbackStatusReviewed), ErrFeedbackNotFound)
+	assert.Error(t, UpdateFeedbackStatus(db, ids[0], "deleted"))
+	_, err = FetchFeedbackByID(db, 999)
+	assert.ErrorIs(t, err, ErrFeedbackNotFound)
+}
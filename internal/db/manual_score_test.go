@@ -0,0 +1,212 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManualScoreOverride(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "manual.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	res, err := db.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score, confidence, score_source)
+		VALUES ('cnn', CURRENT_TIMESTAMP, 'u1', 'title', 'content', 0.6, 0.8, 'llm')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO llm_scores (article_id, model, score, metadata) VALUES (?, 'ensemble', 0.6, '{}')`, articleID)
+	require.NoError(t, err)
+
+	require.NoError(t, SetManualScore(db, articleID, -0.2, "satire misread"))
+
+	article, err := FetchArticleByID(db, articleID)
+	require.NoError(t, err)
+	assert.InDelta(t, -0.2, *article.CompositeScore, 1e-9)
+	assert.Equal(t, ScoreSourceManual, *article.ScoreSource)
+
+	manual, err := FetchManualScore(db, articleID)
+	require.NoError(t, err)
+	require.NotNil(t, manual)
+	assert.Contains(t, manual.Metadata, "satire misread")
+
+	// Re-pinning keeps the confidence from before the first override
+	require.NoError(t, SetManualScore(db, articleID, -0.3, "satire misread, again"))
+	article, err = FetchArticleByID(db, articleID)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, *article.Confidence, 1e-9)
+
+	// LLM rescoring must not overwrite the override
+	require.NoError(t, UpdateArticleScoreLLM(db, articleID, 0.9, 0.7))
+	require.NoError(t, UpdateArticleScore(db, articleID, 0.9, 0.7))
+	article, err = FetchArticleByID(db, articleID)
+	require.NoError(t, err)
+	assert.InDelta(t, -0.3, *article.CompositeScore, 1e-9)
+
+	cleared, err := ClearManualScore(db, articleID)
+	require.NoError(t, err)
+	assert.True(t, cleared)
+
+	article, err = FetchArticleByID(db, articleID)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.6, *article.CompositeScore, 1e-9, "ensemble score is restored")
+	require.NotNil(t, article.Confidence)
+	assert.InDelta(t, 0.8, *article.Confidence, 1e-9, "confidence from before the override is restored")
+	assert.Equal(t, "llm", *article.ScoreSource)
+
+	manual, err = FetchManualScore(db, articleID)
+	require.NoError(t, err)
+	assert.Nil(t, manual)
+
+	cleared, err = ClearManualScore(db, articleID)
+	require.NoError(t, err)
+	assert.False(t, cleared, "nothing left to clear")
+
+	// Once cleared, rescoring updates the article again
+	require.NoError(t, UpdateArticleScoreLLM(db, articleID, 0.9, 0.7))
+	article, err = FetchArticleByID(db, articleID)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.9, *article.CompositeScore, 1e-9)
+}
+
+func TestFetchModelScoredArticleIDs(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "scored.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var ids []int64
+	for i, model := range []string{"left-model", "ensemble", ManualScoreModel, "right-model"} {
+		res, err := db.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+			VALUES ('cnn', CURRENT_TIMESTAMP, ?, 'title', 'content')`, "u-"+model)
+		require.NoError(t, err)
+		id, err := res.LastInsertId()
+		require.NoError(t, err)
+		ids = append(ids, id)
+		_, err = db.Exec(`INSERT INTO llm_scores (article_id, model, score, metadata) VALUES (?, ?, 0.1, '{}')`, id, model)
+		require.NoError(t, err, "row %d", i)
+	}
+	// A second per-model score must not list the article twice
+	_, err = db.Exec(`INSERT INTO llm_scores (article_id, model, score, metadata) VALUES (?, 'center-model', 0.1, '{}')`, ids[0])
+	require.NoError(t, err)
+
+	got, err := FetchModelScoredArticleIDs(db, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{ids[0], ids[3]}, got, "ensemble and manual scores do not count")
+
+	got, err = FetchModelScoredArticleIDs(db, ids[0], 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{ids[3]}, got)
+}
+
+func TestRecordScoreVersion(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "history.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	for want := 1; want <= 3; want++ {
+		version, err := RecordScoreVersion(db, 1, 0.1*float64(want), 0.8, ScoreVersionAnalysis)
+		require.NoError(t, err)
+		assert.Equal(t, want, version)
+	}
+	version, err := RecordScoreVersion(db, 2, 0.5, 0.8, ScoreVersionRecompute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version, "versions are numbered per article")
+}
+
+func TestFetchLastContentHash(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "hash.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	hash, err := FetchLastContentHash(db, 1)
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+
+	_, err = RecordAnalyzedScoreVersion(db, 1, 0.1, 0.8, ScoreVersionAnalysis, "first")
+	require.NoError(t, err)
+	_, err = RecordAnalyzedScoreVersion(db, 1, 0.2, 0.8, ScoreVersionAnalysis, "second")
+	require.NoError(t, err)
+	_, err = RecordScoreVersion(db, 1, 0.3, 0.8, ScoreVersionRecompute)
+	require.NoError(t, err)
+
+	hash, err = FetchLastContentHash(db, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "second", hash, "versions without a hash are passed over")
+}
+
+func TestFetchLabelIDsByDataUsesIndex(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "labels.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var plan []struct {
+		ID      int    `db:"id"`
+		Parent  int    `db:"parent"`
+		NotUsed int    `db:"notused"`
+		Detail  string `db:"detail"`
+	}
+	require.NoError(t, db.Select(&plan, `EXPLAIN QUERY PLAN SELECT id, data FROM labels WHERE labeler = ? AND `+
+		trimmedLabelData+` IN (?, ?)`, "alice", "a", "b"))
+	require.NotEmpty(t, plan)
+	assert.Contains(t, plan[0].Detail, "idx_labels_labeler_data")
+}
+
+func TestApplyLabelCorrections(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "reviews.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	insert := func(data, label string) int64 {
+		l := &Label{Data: data, Label: label, Source: "src", DateLabeled: time.Now(), Labeler: "u1", CreatedAt: time.Now()}
+		require.NoError(t, InsertLabel(db, l))
+		return l.ID
+	}
+	wrong := insert("a", "left")
+	right := insert("b", "neutral")
+
+	found, err := FetchLabelsByIDs(db, []int64{wrong, right, 999})
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	changed, err := ApplyLabelCorrections(db, []LabelCorrection{
+		{LabelID: wrong, CorrectedLabel: "right", ErrorCategory: ErrorCategoryDataNoise},
+		{LabelID: right, ErrorCategory: ErrorCategoryModelFailure},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	found, err = FetchLabelsByIDs(db, []int64{wrong, right})
+	require.NoError(t, err)
+	assert.Equal(t, "right", found[wrong].Label)
+	assert.Equal(t, "neutral", found[right].Label)
+
+	var reviews []struct {
+		LabelID   int64          `db:"label_id"`
+		Previous  string         `db:"previous_label"`
+		Corrected string         `db:"corrected_label"`
+		Category  sql.NullString `db:"error_category"`
+	}
+	require.NoError(t, db.Select(&reviews, "SELECT label_id, previous_label, corrected_label, error_category FROM label_reviews ORDER BY id"))
+	require.Len(t, reviews, 2)
+	assert.Equal(t, "left", reviews[0].Previous)
+	assert.Equal(t, "right", reviews[0].Corrected)
+	assert.Equal(t, ErrorCategoryDataNoise, reviews[0].Category.String)
+	assert.Equal(t, "neutral", reviews[1].Corrected)
+
+	_, err = ApplyLabelCorrections(db, []LabelCorrection{
+		{LabelID: right, CorrectedLabel: "left"},
+		{LabelID: 999, CorrectedLabel: "left"},
+	})
+	assert.Error(t, err)
+	found, err = FetchLabelsByIDs(db, []int64{right})
+	require.NoError(t, err)
+	assert.Equal(t, "neutral", found[right].Label, "a missing label rolls back the whole set")
+
+	assert.True(t, ValidErrorCategory(""))
+	assert.False(t, ValidErrorCategory("typo"))
+}
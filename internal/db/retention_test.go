@@ -0,0 +1,71 @@
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanupArticles(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "retention.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	// Articles 1-5 were ingested 50, 40, 30, 20 and 10 days ago; each has a score,
+	// a history entry and a feedback item
+	for i := 1; i <= 5; i++ {
+		createdAt := now.AddDate(0, 0, -10*(6-i))
+		_, err := db.Exec(`INSERT INTO articles (source, pub_date, url, title, content, created_at)
+			VALUES ('bbc', ?, ?, 'Title', 'Body', ?)`, createdAt, fmt.Sprintf("https://example.com/r/%d", i), createdAt)
+		require.NoError(t, err)
+		_, err = db.Exec(`INSERT INTO llm_scores (article_id, model, score, metadata) VALUES (?, 'm', 0.1, '{}')`, i)
+		require.NoError(t, err)
+		_, err = db.Exec(`INSERT INTO score_history (article_id, version, score) VALUES (?, 1, 0.1)`, i)
+		require.NoError(t, err)
+		_, err = db.Exec(`INSERT INTO feedback (article_id, feedback_text) VALUES (?, 'ok')`, i)
+		require.NoError(t, err)
+	}
+	// Feedback left behind by an article deleted earlier
+	_, err = db.Exec(`INSERT INTO feedback (article_id, feedback_text) VALUES (99, 'orphan')`)
+	require.NoError(t, err)
+
+	ids, err := FetchExpiredArticleIDs(db, RetentionPolicy{MaxAge: 25 * 24 * time.Hour}, now, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ids, "oldest first")
+	ids, err = FetchExpiredArticleIDs(db, RetentionPolicy{MaxCount: 4}, now, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, ids)
+	ids, err = FetchExpiredArticleIDs(db, RetentionPolicy{}, now, 0)
+	require.NoError(t, err)
+	assert.Empty(t, ids, "an empty policy keeps everything")
+
+	policy := RetentionPolicy{MaxAge: 35 * 24 * time.Hour, MaxCount: 3}
+	preview, err := CleanupArticles(db, policy, now, 1, true)
+	require.NoError(t, err)
+	assert.Equal(t, ArticleCleanup{Articles: 2, LLMScores: 2, ScoreHistory: 2, Feedback: 3}, preview)
+	var articles int
+	require.NoError(t, db.Get(&articles, `SELECT COUNT(*) FROM articles`))
+	assert.Equal(t, 5, articles, "a dry run deletes nothing")
+
+	deleted, err := CleanupArticles(db, policy, now, 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, preview, deleted, "the dry run predicts the cleanup")
+
+	var remaining []int64
+	require.NoError(t, db.Select(&remaining, `SELECT id FROM articles ORDER BY id`))
+	assert.Equal(t, []int64{3, 4, 5}, remaining)
+	for _, table := range []string{"llm_scores", "score_history", "feedback"} {
+		var orphans int
+		require.NoError(t, db.Get(&orphans, `SELECT COUNT(*) FROM `+table+` WHERE article_id NOT IN (SELECT id FROM articles)`))
+		assert.Zero(t, orphans, table)
+	}
+
+	again, err := CleanupArticles(db, policy, now, 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, ArticleCleanup{}, again)
+}
@@ -196,3 +196,32 @@ func TestSourceExistsByName(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, exists)
 }
+
+func TestFetchSourceByNameAutoScore(t *testing.T) {
+	db, err := InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	meta := `{"auto_score": true}`
+	_, err = InsertSource(db, &Source{
+		Name:          "Wire",
+		ChannelType:   "rss",
+		FeedURL:       "https://example.com/wire.xml",
+		Category:      "center",
+		Enabled:       true,
+		DefaultWeight: 1.0,
+		Metadata:      &meta,
+	})
+	require.NoError(t, err)
+
+	source, err := FetchSourceByName(db, "Wire")
+	require.NoError(t, err)
+	assert.True(t, source.AutoScore())
+
+	_, err = FetchSourceByName(db, "Missing")
+	assert.Error(t, err)
+
+	invalid := "not json"
+	assert.False(t, (&Source{Metadata: &invalid}).AutoScore())
+	assert.False(t, (&Source{}).AutoScore())
+}
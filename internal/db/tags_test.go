@@ -0,0 +1,76 @@
+package db
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	for name, want := range map[string]string{
+		"Election Coverage":                 "election coverage",
+		"  fact   check ":                   "fact check",
+		"climate_2024":                      "climate_2024",
+		"über-wahl":                         "über-wahl",
+		"":                                  "",
+		"   ":                               "",
+		"drop;table":                        "",
+		"a/b":                               "",
+		strings.Repeat("a", MaxTagLength+1): "",
+	} {
+		got, ok := NormalizeTag(name)
+		assert.Equal(t, want, got, "NormalizeTag(%q)", name)
+		assert.Equal(t, want != "", ok, "NormalizeTag(%q)", name)
+	}
+}
+
+func TestArticleTags(t *testing.T) {
+	db, err := InitDB(filepath.Join(t.TempDir(), "tags.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var ids []int64
+	for _, url := range []string{"https://example.com/1", "https://example.com/2"} {
+		id, err := InsertArticle(db, &Article{Source: "bbc", PubDate: time.Now(), URL: url, Title: url, Content: "body"})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	require.NoError(t, AddArticleTags(db, ids[0], []string{"election coverage", "fact check"}))
+	require.NoError(t, AddArticleTags(db, ids[0], []string{"fact check"}), "re-adding a tag is a no-op")
+	require.NoError(t, AddArticleTags(db, ids[1], []string{"election coverage"}))
+
+	tags, err := FetchArticleTags(db, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"election coverage", "fact check"}, tags)
+
+	articles, err := FetchArticlesFiltered(db, ArticleFilter{Tag: "fact check", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, ids[0], articles[0].ID)
+	total, err := CountArticlesFiltered(db, ArticleFilter{Tag: "election coverage"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	removed, err := RemoveArticleTag(db, ids[0], "fact check")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = RemoveArticleTag(db, ids[0], "fact check")
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	counts, err := FetchTagCounts(db)
+	require.NoError(t, err)
+	assert.Equal(t, []TagCount{{Name: "election coverage", Articles: 2}, {Name: "fact check", Articles: 0}}, counts)
+
+	deleted, err := DeleteArticles(db, []int64{ids[1]})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted.ArticleTags)
+	tags, err = FetchArticleTags(db, ids[1])
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/tracing"
+)
+
+func TestScoreSpansNestUnderCaller(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	db, err := InitDB(filepath.Join(t.TempDir(), "tracing.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	res, err := db.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('cnn', CURRENT_TIMESTAMP, 'u1', 'title', 'content')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+
+	ctx, parent := tracing.Start(context.Background(), "request")
+	_, err = InsertLLMScoreContext(ctx, db, &LLMScore{ArticleID: articleID, Model: "model-a", Score: 0.2, Metadata: "{}"})
+	require.NoError(t, err)
+	_, err = FetchArticleByIDContext(ctx, db, articleID)
+	require.NoError(t, err)
+	_, err = FetchLLMScoresContext(ctx, db, articleID)
+	require.NoError(t, err)
+	require.NoError(t, UpdateArticleScoreLLMContext(ctx, db, articleID, 0.2, 0.9))
+	tracing.End(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 5)
+	for _, span := range spans[:4] {
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID(), span.Name())
+	}
+}
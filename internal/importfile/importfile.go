@@ -0,0 +1,100 @@
+// Package importfile streams the JSON and JSONL files read by the import tools.
+package importfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"unicode"
+)
+
+// Item is one decoded JSON object
+type Item = map[string]interface{}
+
+// ReadJSON streams objects from either a JSON array or JSONL, one object per line,
+// detected from the first character. Each object is passed to add with its 1-based
+// position, and add returns false if it skipped the object. It returns how many
+// objects were skipped, including malformed JSONL lines.
+func ReadJSON(r io.Reader, add func(n int, item Item) bool) (int, error) {
+	br := bufio.NewReader(r)
+	first, err := firstNonSpace(br)
+	if err == io.EOF {
+		return 0, fmt.Errorf("empty JSON file")
+	}
+	if err != nil {
+		return 0, err
+	}
+	if first == '[' {
+		return readJSONArray(br, add)
+	}
+	return readJSONLines(br, add)
+}
+
+// firstNonSpace returns the first byte that is not whitespace or a byte order mark,
+// leaving it unread
+func firstNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		r, _, err := br.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		if r == '\ufeff' || unicode.IsSpace(r) {
+			continue
+		}
+		if err := br.UnreadRune(); err != nil {
+			return 0, err
+		}
+		return byte(r), nil
+	}
+}
+
+// readJSONArray decodes the elements of a JSON array one at a time
+func readJSONArray(r io.Reader, add func(int, Item) bool) (int, error) {
+	decoder := json.NewDecoder(r)
+	if _, err := decoder.Token(); err != nil {
+		return 0, err
+	}
+
+	skipped := 0
+	for n := 1; decoder.More(); n++ {
+		var item Item
+		if err := decoder.Decode(&item); err != nil {
+			// The decoder cannot resynchronise inside an array, so stop here
+			return skipped, fmt.Errorf("item %d: %w", n, err)
+		}
+		if !add(n, item) {
+			skipped++
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return skipped, err
+	}
+	return skipped, nil
+}
+
+// readJSONLines decodes one object per line, skipping blank lines and reporting
+// malformed ones
+func readJSONLines(br *bufio.Reader, add func(int, Item) bool) (int, error) {
+	skipped := 0
+	for n := 1; ; n++ {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var item Item
+			if jsonErr := json.Unmarshal(line, &item); jsonErr != nil {
+				log.Printf("Skipping line %d: invalid JSON: %v", n, jsonErr)
+				skipped++
+			} else if !add(n, item) {
+				skipped++
+			}
+		}
+		if err == io.EOF {
+			return skipped, nil
+		}
+		if err != nil {
+			return skipped, err
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package importfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collect keeps the "name" of every item that has one and skips the rest
+func collect(names *[]string) func(int, Item) bool {
+	return func(n int, item Item) bool {
+		name, ok := item["name"].(string)
+		if ok {
+			*names = append(*names, name)
+		}
+		return ok
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	var names []string
+	skipped, err := ReadJSON(strings.NewReader("\ufeff  [{\"name\": \"a\"}, {\"name\": 1}, {\"name\": \"b\"}]"), collect(&names))
+	require.NoError(t, err)
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	names = nil
+	skipped, err = ReadJSON(strings.NewReader("{\"name\": \"a\"}\n\n{not json}\n{\"name\": \"b\"}"), collect(&names))
+	require.NoError(t, err)
+	assert.Equal(t, 1, skipped, "a malformed line is skipped and the rest are read")
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	names = nil
+	_, err = ReadJSON(strings.NewReader(`[{"name": "a"}, {"name": `), collect(&names))
+	assert.EqualError(t, err, "item 2: unexpected EOF")
+	assert.Equal(t, []string{"a"}, names)
+
+	_, err = ReadJSON(strings.NewReader(" \n"), collect(&names))
+	assert.EqualError(t, err, "empty JSON file")
+}
@@ -0,0 +1,136 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+)
+
+// AggregationProfile is a named variant of the aggregation config to compare against
+// others on the same stored per-model scores
+type AggregationProfile struct {
+	Name   string
+	Config *CompositeScoreConfig
+}
+
+// profileFile is one entry of a profiles file. Config holds only the settings the
+// profile changes, in composite score config JSON.
+type profileFile struct {
+	Name   string          `json:"name"`
+	Config json.RawMessage `json:"config"`
+}
+
+// LoadAggregationProfiles reads a JSON array of {"name", "config"} profiles from path
+// and applies each one's config over base. Settings a profile leaves out keep the base
+// value; "weights" entries are merged into the base weights, while "models" replaces
+// the model list. Names must be present and unique.
+func LoadAggregationProfiles(path string, base *CompositeScoreConfig) ([]AggregationProfile, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is an operator-supplied CLI argument
+	if err != nil {
+		return nil, err
+	}
+	var entries []profileFile
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid profiles file %s: %w", path, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("profiles file %s has no profiles", path)
+	}
+
+	profiles := make([]AggregationProfile, 0, len(entries))
+	seen := make(map[string]bool)
+	for i, entry := range entries {
+		name := strings.TrimSpace(entry.Name)
+		if name == "" {
+			return nil, fmt.Errorf("profile %d has no name", i+1)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate profile name %q", name)
+		}
+		seen[name] = true
+		cfg, err := applyProfileConfig(base, entry.Config)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		profiles = append(profiles, AggregationProfile{Name: name, Config: cfg})
+	}
+	return profiles, nil
+}
+
+// applyProfileConfig returns a copy of base with the settings in overlay applied
+func applyProfileConfig(base *CompositeScoreConfig, overlay json.RawMessage) (*CompositeScoreConfig, error) {
+	if base == nil {
+		return nil, fmt.Errorf("base config is required")
+	}
+	// A JSON round trip gives a deep copy, so profiles never share maps or slices
+	baseJSON, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+	var cfg CompositeScoreConfig
+	if err := json.Unmarshal(baseJSON, &cfg); err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(overlay)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(overlay))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+	switch cfg.Formula {
+	case "", "average", "weighted":
+	default:
+		return nil, fmt.Errorf("unknown formula %q", cfg.Formula)
+	}
+	return &cfg, nil
+}
+
+// ProfileScore is the composite one aggregation profile gives an article. Err is set
+// when the profile cannot produce a composite, such as too few valid models.
+type ProfileScore struct {
+	Profile    string
+	Score      float64
+	Confidence float64
+	Err        error
+}
+
+// ProfileComparison holds an article's composites under several profiles, in the
+// order the profiles were given
+type ProfileComparison struct {
+	ArticleID     int64
+	PreviousScore *float64
+	Models        int
+	Scores        []ProfileScore
+}
+
+// CompareProfiles computes the composite each profile gives an article from its stored
+// per-model scores, applying the same checks as PreviewRecompute, without writing
+// anything. The error is only set when the article's scores cannot be loaded.
+func (sm *ScoreManager) CompareProfiles(articleID int64, profiles []AggregationProfile) (*ProfileComparison, error) {
+	_, perModel, previous, err := sm.recomputeInputs(context.Background(), articleID)
+	if err != nil {
+		return nil, err
+	}
+	comparison := &ProfileComparison{
+		ArticleID:     articleID,
+		PreviousScore: previous,
+		Models:        len(perModel),
+		Scores:        make([]ProfileScore, len(profiles)),
+	}
+	trustWeight, err := db.FetchArticleTrustWeight(sm.db, articleID)
+	if err != nil {
+		return nil, err
+	}
+	for i, profile := range profiles {
+		score, confidence, err := sm.previewComposite(perModel, profile.Config)
+		confidence = ScaleConfidence(confidence, trustWeight)
+		comparison.Scores[i] = ProfileScore{Profile: profile.Name, Score: score, Confidence: confidence, Err: err}
+	}
+	return comparison, nil
+}
@@ -0,0 +1,102 @@
+package llm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProfiles(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadAggregationProfiles(t *testing.T) {
+	base := &CompositeScoreConfig{
+		Formula:  "average",
+		MinScore: -1,
+		MaxScore: 1,
+		Weights:  map[string]float64{"left": 1, "center": 1, "right": 1},
+		Models:   []ModelConfig{{ModelName: "left-model", Perspective: "left"}},
+	}
+
+	profiles, err := LoadAggregationProfiles(writeProfiles(t, `[
+		{"name": "baseline"},
+		{"name": "left-heavy", "config": {"formula": "weighted", "weights": {"left": 3}}}
+	]`), base)
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "average", profiles[0].Config.Formula)
+	assert.Equal(t, "weighted", profiles[1].Config.Formula)
+	assert.Equal(t, map[string]float64{"left": 3, "center": 1, "right": 1}, profiles[1].Config.Weights)
+	assert.Equal(t, 1.0, base.Weights["left"], "profiles must not change the base config")
+	assert.Equal(t, base.Models, profiles[1].Config.Models)
+
+	for name, content := range map[string]string{
+		"empty":           `[]`,
+		"missing name":    `[{"config": {}}]`,
+		"duplicate name":  `[{"name": "a"}, {"name": "a"}]`,
+		"unknown field":   `[{"name": "a", "config": {"formla": "weighted"}}]`,
+		"unknown formula": `[{"name": "a", "config": {"formula": "median"}}]`,
+	} {
+		_, err := LoadAggregationProfiles(writeProfiles(t, content), base)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestCompareProfiles(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "profiles.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(dbConn, NewCache(), &DefaultScoreCalculator{}, pm)
+	base := &CompositeScoreConfig{
+		Formula:  "average",
+		MinScore: -1,
+		MaxScore: 1,
+		Models: []ModelConfig{
+			{ModelName: "left-model", Perspective: "left"},
+			{ModelName: "center-model", Perspective: "center"},
+			{ModelName: "right-model", Perspective: "right"},
+		},
+	}
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('src', CURRENT_TIMESTAMP, 'https://example.com/profiles', 'title', 'content')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	for model, score := range map[string]float64{"left-model": -0.6, "center-model": 0, "right-model": 0.3} {
+		_, err = db.InsertLLMScore(dbConn, &db.LLMScore{
+			ArticleID: articleID, Model: model, Score: score, Metadata: `{"confidence": 0.8}`, Version: 1, CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	profiles, err := LoadAggregationProfiles(writeProfiles(t, `[
+		{"name": "baseline"},
+		{"name": "strict", "config": {"min_models_for_composite": 4}}
+	]`), base)
+	require.NoError(t, err)
+
+	comparison, err := sm.CompareProfiles(articleID, profiles)
+	require.NoError(t, err)
+	assert.Equal(t, 3, comparison.Models)
+	assert.Nil(t, comparison.PreviousScore)
+	require.Len(t, comparison.Scores, 2)
+	require.NoError(t, comparison.Scores[0].Err)
+	assert.InDelta(t, -0.1, comparison.Scores[0].Score, 1e-6)
+	assert.ErrorIs(t, comparison.Scores[1].Err, ErrInsufficientModels)
+
+	scores, err := db.FetchLLMScores(dbConn, articleID)
+	require.NoError(t, err)
+	assert.Len(t, scores, 3, "comparing profiles must not store an ensemble score")
+}
@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/jmoiron/sqlx"
+)
+
+// How the built-in calculators combine model scores and confidences
+const (
+	AggregationFormulaMean                = "mean"
+	AggregationFormulaMedian              = "median"
+	AggregationFormulaPerspectiveMean     = "mean of the perspectives' mean scores"
+	AggregationFormulaPerspectiveWeighted = "weighted mean of the perspectives' mean scores"
+	AggregationConfidenceMean             = "mean of the included models' confidences"
+	AggregationConfidencePerspectiveMean  = "mean of the perspectives' mean confidences"
+)
+
+// AggregationTrace is the intermediate math of a composite as a calculator computes it,
+// for debugging: each model's part, the aggregate before it is clamped to the score
+// range and how the confidence was derived
+type AggregationTrace struct {
+	// Calculator names the calculator traced
+	Calculator    string            `json:"calculator,omitempty"`
+	Formula       string            `json:"formula"`
+	Models        []AggregationStep `json:"models"`
+	WeightTotal   float64           `json:"weight_total"`
+	WeightedSum   float64           `json:"weighted_sum"`
+	PreClampScore float64           `json:"pre_clamp_score"`
+	MinScore      float64           `json:"min_score"`
+	MaxScore      float64           `json:"max_score"`
+	Score         float64           `json:"score"`
+	// Confidence is RawConfidence scaled by the source trust weight
+	ConfidenceMethod  string  `json:"confidence_method"`
+	RawConfidence     float64 `json:"raw_confidence"`
+	SourceTrustWeight float64 `json:"source_trust_weight"`
+	Confidence        float64 `json:"confidence"`
+	// Error says why no composite could be computed
+	Error string `json:"error,omitempty"`
+}
+
+// AggregationStep is one model score's part in a composite. Excluded scores carry the
+// reason and a weight of 0.
+type AggregationStep struct {
+	Model       string  `json:"model"`
+	Perspective string  `json:"perspective,omitempty"`
+	Score       float64 `json:"score"`
+	Confidence  float64 `json:"confidence"`
+	Weight      float64 `json:"weight"`
+	Included    bool    `json:"included"`
+	Excluded    string  `json:"excluded,omitempty"`
+}
+
+// isModelScore reports whether s is a model's own score rather than the ensemble or a
+// manual override
+func isModelScore(s db.LLMScore) bool {
+	return !strings.EqualFold(s.Model, "ensemble") && s.Model != db.ManualScoreModel
+}
+
+// scoreTracer is a calculator that can report each step of its calculation
+type scoreTracer interface {
+	TraceScore(scores []db.LLMScore, cfg *CompositeScoreConfig) (*AggregationTrace, error)
+}
+
+// TraceArticleComposite traces the composite of an article's stored model scores under
+// cfg, scaling the confidence by the article's source trust weight as scoring does. It
+// replays the calculator the latest ensemble score in stored records, or the formula
+// calculator reanalysis uses by default when none is recorded or it is not built in.
+// Ensemble and manual scores are not traced. It returns nil without a config.
+func TraceArticleComposite(exec sqlx.QueryerContext, articleID int64, stored []db.LLMScore, cfg *CompositeScoreConfig) *AggregationTrace {
+	perModel := make([]db.LLMScore, 0, len(stored))
+	for _, s := range stored {
+		if isModelScore(s) {
+			perModel = append(perModel, s)
+		}
+	}
+	name := storedCalculator(stored)
+	calc, err := NewScoreCalculator(name)
+	if err != nil {
+		name = CalculatorFormula
+		calc = &FormulaScoreCalculator{}
+	}
+	trace, err := calc.(scoreTracer).TraceScore(perModel, cfg)
+	if trace != nil {
+		trace.Calculator = name
+	}
+	if trace == nil || err != nil {
+		return trace
+	}
+	var trust sourceTrust
+	trace.Confidence, trust = applySourceTrust(exec, articleID, trace.RawConfidence)
+	trace.SourceTrustWeight = trust.Weight
+	return trace
+}
+
+// storedCalculator returns the calculator recorded in the final aggregation of the
+// latest ensemble score in stored, empty when there is none
+func storedCalculator(stored []db.LLMScore) string {
+	var latest *db.LLMScore
+	for i := range stored {
+		if strings.EqualFold(stored[i].Model, "ensemble") && (latest == nil || stored[i].CreatedAt.After(latest.CreatedAt)) {
+			latest = &stored[i]
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	var meta struct {
+		FinalAggregation struct {
+			Calculator string `json:"calculator"`
+		} `json:"final_aggregation"`
+	}
+	if err := json.Unmarshal([]byte(latest.Metadata), &meta); err != nil {
+		return ""
+	}
+	return meta.FinalAggregation.Calculator
+}
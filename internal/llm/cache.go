@@ -1,21 +1,109 @@
 package llm
 
 import (
+	"container/list"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"log"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
 )
 
-// Cache provides a thread-safe in-memory cache
+// DefaultCacheMaxSize is the number of entries NewCache keeps before evicting
+const DefaultCacheMaxSize = 10000
+
+// DefaultCacheMaxAge is how long NewCache reuses a result unless LLM_CACHE_MAX_AGE says
+// otherwise. Prompts and models change, so old results should be scored again.
+const DefaultCacheMaxAge = 24 * time.Hour
+
+// CacheStats reports cache effectiveness
+type CacheStats struct {
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	Evictions int64   `json:"evictions"`
+	HitRate   float64 `json:"hit_rate"`
+	Size      int     `json:"size"`
+	MaxSize   int     `json:"max_size"`
+	// MaxAgeSeconds is how long entries are reused, 0 when they never expire
+	MaxAgeSeconds int64 `json:"max_age_seconds"`
+	// Models breaks the counts down by model, the key suffix after the content hash
+	Models map[string]CacheCounts `json:"models"`
+}
+
+// CacheCounts are the hit, miss and eviction counts for one model
+type CacheCounts struct {
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	Evictions int64   `json:"evictions"`
+	HitRate   float64 `json:"hit_rate"`
+}
+
+// cacheEntry is a single cached value, stored as JSON so callers never share state
+type cacheEntry struct {
+	key      string
+	model    string
+	value    string
+	storedAt time.Time
+}
+
+// Cache provides a thread-safe in-memory LRU cache for LLM results
 type Cache struct {
-	m sync.Map
+	mu        sync.Mutex
+	items     map[string]*list.Element
+	order     *list.List // front is most recently used
+	maxSize   int
+	maxAge    time.Duration
+	hits      int64
+	misses    int64
+	evictions int64
+	models    map[string]*CacheCounts
+	now       func() time.Time
 }
 
-// NewCache creates a new empty cache instance
+// NewCache creates a new empty cache instance holding up to DefaultCacheMaxSize entries,
+// each reused for LLM_CACHE_MAX_AGE (a Go duration, default 24h; "0" never expires)
 func NewCache() *Cache {
-	return &Cache{}
+	c := NewCacheWithSize(DefaultCacheMaxSize)
+	c.SetMaxAge(cacheMaxAgeFromEnv())
+	return c
+}
+
+// cacheMaxAgeFromEnv reads LLM_CACHE_MAX_AGE, falling back to DefaultCacheMaxAge
+func cacheMaxAgeFromEnv() time.Duration {
+	v := os.Getenv("LLM_CACHE_MAX_AGE")
+	if v == "" {
+		return DefaultCacheMaxAge
+	}
+	maxAge, err := time.ParseDuration(v)
+	if err != nil || maxAge < 0 {
+		log.Printf("[WARN] Invalid LLM_CACHE_MAX_AGE %q, using default %v", v, DefaultCacheMaxAge)
+		return DefaultCacheMaxAge
+	}
+	return maxAge
+}
+
+// NewCacheWithSize creates a cache that evicts the least recently used entry
+// once it holds maxSize entries. A maxSize of 0 or less means unbounded.
+func NewCacheWithSize(maxSize int) *Cache {
+	return &Cache{
+		items:   make(map[string]*list.Element),
+		order:   list.New(),
+		maxSize: maxSize,
+		models:  make(map[string]*CacheCounts),
+		now:     time.Now,
+	}
+}
+
+// SetMaxAge makes reads treat entries stored more than maxAge ago as misses. A maxAge
+// of 0 or less means entries never expire.
+func (c *Cache) SetMaxAge(maxAge time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxAge = maxAge
 }
 
 // makeKey creates a composite key from content hash and model
@@ -23,43 +111,144 @@ func makeKey(contentHash, model string) string {
 	return fmt.Sprintf("%s:%s", contentHash, model)
 }
 
+// hashPromptContent hashes everything besides the model that determines an LLM
+// result: the prompt variant and the article content. Identical content scored
+// with the same prompt shares a cache entry regardless of article ID, while
+// edited content or a changed prompt yields a new key.
+func hashPromptContent(pv PromptVariant, content string) string {
+	h := sha256.New()
+	for _, part := range []string{pv.ID, pv.SystemPrompt, pv.Template, content} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	for _, example := range pv.Examples {
+		h.Write([]byte(example))
+		h.Write([]byte{0})
+	}
+	if sampling, err := json.Marshal(pv.Sampling); err == nil {
+		h.Write(sampling)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
 // Get retrieves a value from the cache
 func (c *Cache) Get(contentHash, model string) (*db.LLMScore, bool) {
-	v, ok := c.m.Load(makeKey(contentHash, model))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[makeKey(contentHash, model)]
 	if !ok {
+		c.misses++
+		c.modelCounts(model).Misses++
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if c.maxAge > 0 && c.now().Sub(entry.storedAt) > c.maxAge {
+		// Stale entries are dropped so the fresh result replaces them
+		c.order.Remove(el)
+		delete(c.items, entry.key)
+		c.misses++
+		c.modelCounts(model).Misses++
 		return nil, false
 	}
 
 	// Convert stored JSON string back to LLMScore
 	var score db.LLMScore
-	s, okAssert := v.(string)
-	if !okAssert {
-		// Optionally log an error here, e.g., log.Printf("Cache item for key %s was not a string", makeKey(contentHash, model))
-		return nil, false
-	}
-	if err := json.Unmarshal([]byte(s), &score); err != nil {
-		// Optionally log an error here, e.g., log.Printf("Failed to unmarshal cache item for key %s: %v", makeKey(contentHash, model), err)
+	if err := json.Unmarshal([]byte(entry.value), &score); err != nil {
+		c.misses++
+		c.modelCounts(model).Misses++
 		return nil, false
 	}
+	c.order.MoveToFront(el)
+	c.hits++
+	c.modelCounts(model).Hits++
 	return &score, true
 }
 
-// Set stores a value in the cache
+// Set stores a value in the cache, evicting the least recently used entry when full
 func (c *Cache) Set(contentHash, model string, score *db.LLMScore) {
 	// Convert LLMScore to JSON string for storage
 	data, err := json.Marshal(score)
 	if err != nil {
 		return
 	}
-	c.m.Store(makeKey(contentHash, model), string(data))
+	key := makeKey(contentHash, model)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.value = string(data)
+		entry.storedAt = c.now()
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&cacheEntry{key: key, model: model, value: string(data), storedAt: c.now()})
+	for c.maxSize > 0 && c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*cacheEntry)
+		c.order.Remove(oldest)
+		delete(c.items, entry.key)
+		c.evictions++
+		c.modelCounts(entry.model).Evictions++
+	}
+}
+
+// modelCounts returns the counters for model, creating them on first use.
+// Callers must hold mu.
+func (c *Cache) modelCounts(model string) *CacheCounts {
+	counts, ok := c.models[model]
+	if !ok {
+		counts = &CacheCounts{}
+		c.models[model] = counts
+	}
+	return counts
 }
 
 // Delete removes a value from the cache
 func (c *Cache) Delete(key string) {
-	c.m.Delete(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.order.Remove(el)
+		delete(c.items, key)
+	}
 }
 
 // Remove removes a value from the cache by content hash and model
 func (c *Cache) Remove(contentHash, model string) {
-	c.m.Delete(makeKey(contentHash, model))
+	c.Delete(makeKey(contentHash, model))
+}
+
+// Stats returns hit, miss and eviction counters, overall and per model, along with
+// the current size
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	models := make(map[string]CacheCounts, len(c.models))
+	for model, counts := range c.models {
+		snapshot := *counts
+		snapshot.HitRate = cacheHitRate(counts.Hits, counts.Misses)
+		models[model] = snapshot
+	}
+	return CacheStats{
+		Hits:          c.hits,
+		Misses:        c.misses,
+		Evictions:     c.evictions,
+		HitRate:       cacheHitRate(c.hits, c.misses),
+		Size:          c.order.Len(),
+		MaxSize:       c.maxSize,
+		MaxAgeSeconds: int64(c.maxAge / time.Second),
+		Models:        models,
+	}
+}
+
+// cacheHitRate is the fraction of lookups that were hits, 0 when there were none
+func cacheHitRate(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
 }
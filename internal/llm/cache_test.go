@@ -2,6 +2,7 @@ package llm
 
 import (
 	"testing"
+	"time"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
 	"github.com/stretchr/testify/assert"
@@ -155,3 +156,145 @@ func TestCacheRemove(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, score2.Score, val.Score)
 }
+
+func TestHashPromptContent(t *testing.T) {
+	pv := PromptVariant{ID: "default", Template: "Rate the bias", Model: "gpt-4"}
+	base := hashPromptContent(pv, "Article body")
+
+	// Same prompt and content share a key regardless of which article it came from
+	assert.Equal(t, base, hashPromptContent(pv, "Article body"))
+
+	// Edited content forces a new key
+	assert.NotEqual(t, base, hashPromptContent(pv, "Article body, edited"))
+
+	// A different prompt variant or template yields a new key
+	other := pv
+	other.ID = "short"
+	assert.NotEqual(t, base, hashPromptContent(other, "Article body"))
+	other = pv
+	other.Template = "Rate the political bias"
+	assert.NotEqual(t, base, hashPromptContent(other, "Article body"))
+	other = pv
+	other.Examples = []string{`{"score": 0}`}
+	assert.NotEqual(t, base, hashPromptContent(other, "Article body"))
+
+	// Field boundaries are unambiguous
+	a := hashPromptContent(PromptVariant{ID: "ab", Template: "c"}, "")
+	b := hashPromptContent(PromptVariant{ID: "a", Template: "bc"}, "")
+	assert.NotEqual(t, a, b)
+}
+
+func TestCacheSharedAcrossArticlesByContent(t *testing.T) {
+	cache := NewCache()
+	pv := PromptVariant{ID: "default", Template: "Rate the bias"}
+
+	cache.Set(hashPromptContent(pv, "Same wire story"), "gpt-4", &db.LLMScore{ArticleID: 1, Model: "gpt-4", Score: 0.4})
+
+	val, ok := cache.Get(hashPromptContent(pv, "Same wire story"), "gpt-4")
+	assert.True(t, ok, "identical content from another article should hit")
+	assert.Equal(t, 0.4, val.Score)
+
+	_, ok = cache.Get(hashPromptContent(pv, "Same wire story, updated"), "gpt-4")
+	assert.False(t, ok, "edited content should miss")
+
+	_, ok = cache.Get(hashPromptContent(pv, "Same wire story"), "llama")
+	assert.False(t, ok, "other models should miss")
+}
+
+func TestCacheStats(t *testing.T) {
+	cache := NewCacheWithSize(5)
+
+	cache.Set("hash1", "gpt-4", &db.LLMScore{Score: 0.1})
+	cache.Get("hash1", "gpt-4")
+	cache.Get("hash1", "gpt-4")
+	cache.Get("hash2", "gpt-4")
+
+	stats := cache.Stats()
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, int64(0), stats.Evictions)
+	assert.Equal(t, 1, stats.Size)
+	assert.Equal(t, 5, stats.MaxSize)
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCacheWithSize(2)
+
+	cache.Set("hash1", "gpt-4", &db.LLMScore{Score: 0.1})
+	cache.Set("hash2", "gpt-4", &db.LLMScore{Score: 0.2})
+
+	// Touch hash1 so hash2 becomes the eviction candidate
+	_, ok := cache.Get("hash1", "gpt-4")
+	assert.True(t, ok)
+
+	cache.Set("hash3", "gpt-4", &db.LLMScore{Score: 0.3})
+
+	_, ok = cache.Get("hash2", "gpt-4")
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, ok = cache.Get("hash1", "gpt-4")
+	assert.True(t, ok)
+	_, ok = cache.Get("hash3", "gpt-4")
+	assert.True(t, ok)
+
+	stats := cache.Stats()
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, int64(1), stats.Evictions)
+}
+
+func TestCacheStatsByModel(t *testing.T) {
+	cache := NewCacheWithSize(1)
+
+	cache.Set("hash1", "gpt-4", &db.LLMScore{Score: 0.1})
+	cache.Get("hash1", "gpt-4")
+	cache.Get("hash1", "llama")
+	cache.Set("hash2", "llama", &db.LLMScore{Score: 0.2})
+
+	stats := cache.Stats()
+	assert.InDelta(t, 0.5, stats.HitRate, 1e-9)
+	assert.Equal(t, CacheCounts{Hits: 1, Evictions: 1, HitRate: 1}, stats.Models["gpt-4"])
+	assert.Equal(t, CacheCounts{Misses: 1}, stats.Models["llama"])
+}
+
+func TestCacheMaxAge(t *testing.T) {
+	cache := NewCacheWithSize(5)
+	cache.SetMaxAge(time.Hour)
+	now := time.Unix(1_700_000_000, 0)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("hash1", "gpt-4", &db.LLMScore{Score: 0.1})
+	now = now.Add(time.Hour)
+	_, ok := cache.Get("hash1", "gpt-4")
+	assert.True(t, ok, "entries are reused up to the max age")
+
+	now = now.Add(time.Second)
+	_, ok = cache.Get("hash1", "gpt-4")
+	assert.False(t, ok, "older entries are misses")
+	assert.Equal(t, 0, cache.Stats().Size, "expired entries are dropped")
+
+	// Storing again restarts the clock
+	cache.Set("hash1", "gpt-4", &db.LLMScore{Score: 0.2})
+	now = now.Add(30 * time.Minute)
+	score, ok := cache.Get("hash1", "gpt-4")
+	assert.True(t, ok)
+	assert.Equal(t, 0.2, score.Score)
+
+	// Without a max age entries never expire
+	cache.SetMaxAge(0)
+	now = now.Add(365 * 24 * time.Hour)
+	_, ok = cache.Get("hash1", "gpt-4")
+	assert.True(t, ok)
+}
+
+func TestCacheMaxAgeFromEnv(t *testing.T) {
+	t.Setenv("LLM_CACHE_MAX_AGE", "")
+	assert.Equal(t, int64(DefaultCacheMaxAge/time.Second), NewCache().Stats().MaxAgeSeconds)
+
+	t.Setenv("LLM_CACHE_MAX_AGE", "15m")
+	assert.Equal(t, int64(900), NewCache().Stats().MaxAgeSeconds)
+
+	t.Setenv("LLM_CACHE_MAX_AGE", "0")
+	assert.Equal(t, int64(0), NewCache().Stats().MaxAgeSeconds)
+
+	t.Setenv("LLM_CACHE_MAX_AGE", "a week")
+	assert.Equal(t, DefaultCacheMaxAge, cacheMaxAgeFromEnv())
+}
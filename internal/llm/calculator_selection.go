@@ -0,0 +1,342 @@
+package llm
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+)
+
+// Names of the built-in score calculators, as used in SCORE_CALCULATORS and recorded in
+// ensemble metadata
+const (
+	CalculatorDefault = "default"
+	CalculatorFormula = "formula"
+	CalculatorMedian  = "median"
+)
+
+// Policies for choosing between the results of several calculators
+const (
+	// PolicyHighestConfidence keeps the result with the highest confidence; ties go to
+	// the calculator listed first. The built-in calculators all report a mean model
+	// confidence, so their confidences share a scale: default and median always tie,
+	// and formula, which averages per perspective and keeps zero-confidence models,
+	// only differs when some model answered with zero confidence.
+	PolicyHighestConfidence = "highest_confidence"
+	// PolicyFirstValid keeps the result of the first calculator that produces one
+	PolicyFirstValid = "first_valid"
+)
+
+// scoreCalculators builds the built-in calculators by name
+var scoreCalculators = map[string]func() ScoreCalculator{
+	CalculatorDefault: func() ScoreCalculator { return &DefaultScoreCalculator{} },
+	CalculatorFormula: func() ScoreCalculator { return &FormulaScoreCalculator{} },
+	CalculatorMedian:  func() ScoreCalculator { return &MedianScoreCalculator{} },
+}
+
+// NewScoreCalculator returns the built-in calculator with the given name
+func NewScoreCalculator(name string) (ScoreCalculator, error) {
+	build, ok := scoreCalculators[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("unknown score calculator %q", name)
+	}
+	return build(), nil
+}
+
+// FormulaScoreCalculator computes the composite as the config describes it: the
+// formula and perspective weights of the composite score config. Its confidence is the
+// mean of the per-perspective mean model confidences.
+type FormulaScoreCalculator struct{}
+
+// CalculateScore applies ComputeCompositeScoreWithConfidenceFixed
+func (c *FormulaScoreCalculator) CalculateScore(scores []db.LLMScore, cfg *CompositeScoreConfig) (float64, float64, error) {
+	return ComputeCompositeScoreWithConfidenceFixed(scores, cfg)
+}
+
+// TraceScore is CalculateScore reporting each step of the calculation. A model's weight
+// is its perspective's weight shared among the perspective's models.
+func (c *FormulaScoreCalculator) TraceScore(scores []db.LLMScore, cfg *CompositeScoreConfig) (*AggregationTrace, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("FormulaScoreCalculator: Config must not be nil: %w", ErrAllPerspectivesInvalid)
+	}
+	trace := &AggregationTrace{
+		Formula:           AggregationFormulaPerspectiveMean,
+		ConfidenceMethod:  AggregationConfidencePerspectiveMean,
+		MinScore:          cfg.MinScore,
+		MaxScore:          cfg.MaxScore,
+		SourceTrustWeight: models.DefaultSourceTrustWeight,
+		Models:            make([]AggregationStep, 0, len(scores)),
+	}
+
+	perModel := make(map[string]int)
+	for _, score := range scores {
+		step := AggregationStep{Model: score.Model, Score: score.Score, Perspective: MapModelToPerspective(score.Model, cfg)}
+		switch {
+		case math.IsNaN(score.Score) || math.IsInf(score.Score, 0):
+			step.Score = 0 // not representable in JSON
+			step.Excluded = "score is not a finite number"
+		case score.Score < cfg.MinScore || score.Score > cfg.MaxScore:
+			step.Excluded = fmt.Sprintf("score is outside [%g, %g]", cfg.MinScore, cfg.MaxScore)
+		case step.Perspective == "":
+			step.Excluded = "model is not in the composite score configuration"
+		default:
+			step.Included = true
+			step.Confidence = (&DefaultScoreCalculator{}).extractConfidence(score.Metadata)
+			perModel[step.Perspective]++
+		}
+		trace.Models = append(trace.Models, step)
+	}
+
+	// Perspectives count equally unless the weighted formula has usable weights
+	perspectiveWeight := func(string) float64 { return 1.0 }
+	if cfg.Formula == "weighted" {
+		total := 0.0
+		for p := range perModel {
+			total += cfg.Weights[p]
+		}
+		if total > 0 {
+			trace.Formula = AggregationFormulaPerspectiveWeighted
+			perspectiveWeight = func(p string) float64 { return cfg.Weights[p] }
+		}
+	}
+	for p := range perModel {
+		trace.WeightTotal += perspectiveWeight(p)
+	}
+	for i := range trace.Models {
+		step := &trace.Models[i]
+		if step.Included {
+			step.Weight = perspectiveWeight(step.Perspective) / float64(perModel[step.Perspective])
+			trace.WeightedSum += step.Score * step.Weight
+		}
+	}
+	if trace.WeightTotal > 0 {
+		trace.PreClampScore = trace.WeightedSum / trace.WeightTotal
+	}
+
+	score, confidence, err := ComputeCompositeScoreWithConfidenceFixed(scores, cfg)
+	if err != nil {
+		trace.Error = err.Error()
+		return trace, err
+	}
+	trace.Score = score
+	trace.RawConfidence = confidence
+	trace.Confidence = confidence
+	return trace, nil
+}
+
+// MedianScoreCalculator takes the median of the scores DefaultScoreCalculator would
+// average, so a single outlying model cannot pull the composite. The confidence is
+// their mean confidence.
+type MedianScoreCalculator struct{}
+
+// CalculateScore returns the median of the valid model scores
+func (c *MedianScoreCalculator) CalculateScore(scores []db.LLMScore, cfg *CompositeScoreConfig) (float64, float64, error) {
+	trace, err := c.TraceScore(scores, cfg)
+	if err != nil {
+		return 0.0, 0.0, err
+	}
+	return trace.Score, trace.RawConfidence, nil
+}
+
+// TraceScore is CalculateScore reporting each step of the calculation. The middle
+// score, or the two middle scores, carry all the weight; the other valid scores only
+// count towards the confidence.
+func (c *MedianScoreCalculator) TraceScore(scores []db.LLMScore, cfg *CompositeScoreConfig) (*AggregationTrace, error) {
+	trace, err := (&DefaultScoreCalculator{}).TraceScore(scores, cfg)
+	if trace != nil {
+		trace.Formula = AggregationFormulaMedian
+	}
+	if err != nil {
+		return trace, err
+	}
+	valid := make([]int, 0, len(trace.Models))
+	for i, step := range trace.Models {
+		trace.Models[i].Weight = 0
+		if step.Included {
+			valid = append(valid, i)
+		}
+	}
+	sort.SliceStable(valid, func(a, b int) bool { return trace.Models[valid[a]].Score < trace.Models[valid[b]].Score })
+	middle := valid[len(valid)/2 : len(valid)/2+1]
+	if len(valid)%2 == 0 {
+		middle = valid[len(valid)/2-1 : len(valid)/2+1]
+	}
+	trace.WeightTotal, trace.WeightedSum = 1, 0
+	for _, i := range middle {
+		trace.Models[i].Weight = 1 / float64(len(middle))
+		trace.WeightedSum += trace.Models[i].Score * trace.Models[i].Weight
+	}
+	trace.PreClampScore = trace.WeightedSum
+	trace.Score = math.Max(cfg.MinScore, math.Min(cfg.MaxScore, trace.PreClampScore))
+	return trace, nil
+}
+
+// NamedCalculator is a calculator with the name recorded when its result is chosen
+type NamedCalculator struct {
+	Name       string
+	Calculator ScoreCalculator
+}
+
+// CalculatorChoice is the result a MultiScoreCalculator kept
+type CalculatorChoice struct {
+	Calculator string
+	Score      float64
+	Confidence float64
+}
+
+// MultiScoreCalculator runs several calculators on the same scores and keeps one
+// result according to its policy. It fails only when every calculator fails.
+type MultiScoreCalculator struct {
+	calculators []NamedCalculator
+	policy      string
+}
+
+// NewMultiScoreCalculator chooses between calculators by policy, PolicyHighestConfidence
+// when empty
+func NewMultiScoreCalculator(policy string, calculators ...NamedCalculator) (*MultiScoreCalculator, error) {
+	if policy == "" {
+		policy = PolicyHighestConfidence
+	}
+	if policy != PolicyHighestConfidence && policy != PolicyFirstValid {
+		return nil, fmt.Errorf("unknown calculator policy %q", policy)
+	}
+	if len(calculators) == 0 {
+		return nil, fmt.Errorf("at least one score calculator is required")
+	}
+	return &MultiScoreCalculator{calculators: calculators, policy: policy}, nil
+}
+
+// Choose runs the calculators and returns the result the policy keeps. Without any
+// result it returns the first calculator's error.
+func (m *MultiScoreCalculator) Choose(scores []db.LLMScore, cfg *CompositeScoreConfig) (*CalculatorChoice, error) {
+	var best *CalculatorChoice
+	var firstErr error
+	for _, nc := range m.calculators {
+		score, confidence, err := nc.Calculator.CalculateScore(scores, cfg)
+		if err != nil {
+			log.Printf("[DEBUG] Score calculator %s produced no result: %v", nc.Name, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if best == nil || confidence > best.Confidence {
+			best = &CalculatorChoice{Calculator: nc.Name, Score: score, Confidence: confidence}
+		}
+		if m.policy == PolicyFirstValid {
+			break
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no score calculator produced a result: %w", firstErr)
+	}
+	return best, nil
+}
+
+// TraceScore traces the calculation of the calculator Choose keeps, when it can be
+// traced, recording its name in the trace
+func (m *MultiScoreCalculator) TraceScore(scores []db.LLMScore, cfg *CompositeScoreConfig) (*AggregationTrace, error) {
+	choice, err := m.Choose(scores, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, nc := range m.calculators {
+		if nc.Name != choice.Calculator {
+			continue
+		}
+		tracer, ok := nc.Calculator.(scoreTracer)
+		if !ok {
+			break
+		}
+		trace, err := tracer.TraceScore(scores, cfg)
+		if trace != nil {
+			trace.Calculator = nc.Name
+		}
+		return trace, err
+	}
+	return nil, fmt.Errorf("score calculator %s cannot be traced", choice.Calculator)
+}
+
+// CalculateScore returns the chosen calculator's score and confidence
+func (m *MultiScoreCalculator) CalculateScore(scores []db.LLMScore, cfg *CompositeScoreConfig) (float64, float64, error) {
+	choice, err := m.Choose(scores, cfg)
+	if err != nil {
+		return 0.0, 0.0, err
+	}
+	return choice.Score, choice.Confidence, nil
+}
+
+// calculatorName is the name of a built-in calculator, empty for any other
+func calculatorName(calc ScoreCalculator) string {
+	switch calc.(type) {
+	case *DefaultScoreCalculator:
+		return CalculatorDefault
+	case *FormulaScoreCalculator:
+		return CalculatorFormula
+	case *MedianScoreCalculator:
+		return CalculatorMedian
+	}
+	return ""
+}
+
+// calculateComposite runs calc, also returning the name of the calculator whose result
+// was kept: the one chosen when calc chooses between several, otherwise calc's own
+func calculateComposite(calc ScoreCalculator, scores []db.LLMScore, cfg *CompositeScoreConfig) (score, confidence float64, chosen string, err error) {
+	if multi, ok := calc.(*MultiScoreCalculator); ok {
+		choice, err := multi.Choose(scores, cfg)
+		if err != nil {
+			return 0.0, 0.0, "", err
+		}
+		return choice.Score, choice.Confidence, choice.Calculator, nil
+	}
+	score, confidence, err = calc.CalculateScore(scores, cfg)
+	return score, confidence, calculatorName(calc), err
+}
+
+// analysisCalculator returns the calculator ReanalyzeArticle computes composites with:
+// the ScoreManager's once SCORE_CALCULATORS selects calculators, otherwise the config
+// formula reanalysis has always used
+func analysisCalculator(sm *ScoreManager) ScoreCalculator {
+	if sm != nil && sm.calculator != nil && strings.TrimSpace(os.Getenv("SCORE_CALCULATORS")) != "" {
+		return sm.calculator
+	}
+	return &FormulaScoreCalculator{}
+}
+
+// ScoreCalculatorFromEnv builds the calculator named by SCORE_CALCULATORS, a comma
+// separated list of built-in calculators. Several names give a MultiScoreCalculator
+// choosing by SCORE_CALCULATOR_POLICY. Unset, it is DefaultScoreCalculator.
+func ScoreCalculatorFromEnv() ScoreCalculator {
+	v := os.Getenv("SCORE_CALCULATORS")
+	if strings.TrimSpace(v) == "" {
+		return &DefaultScoreCalculator{}
+	}
+	var calculators []NamedCalculator
+	for _, name := range strings.Split(v, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		calc, err := NewScoreCalculator(name)
+		if err != nil {
+			log.Printf("[WARN] SCORE_CALCULATORS: %v, skipping it", err)
+			continue
+		}
+		calculators = append(calculators, NamedCalculator{Name: name, Calculator: calc})
+	}
+	switch len(calculators) {
+	case 0:
+		log.Printf("[WARN] No valid calculator in SCORE_CALCULATORS %q, using the default", v)
+		return &DefaultScoreCalculator{}
+	case 1:
+		return calculators[0].Calculator
+	}
+	multi, err := NewMultiScoreCalculator(os.Getenv("SCORE_CALCULATOR_POLICY"), calculators...)
+	if err != nil {
+		log.Printf("[WARN] Invalid SCORE_CALCULATOR_POLICY: %v, using %s", err, PolicyHighestConfidence)
+		multi, _ = NewMultiScoreCalculator(PolicyHighestConfidence, calculators...)
+	}
+	return multi
+}
@@ -0,0 +1,356 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubCalculator returns a fixed result
+type stubCalculator struct {
+	score, confidence float64
+	err               error
+	calls             int
+}
+
+func (s *stubCalculator) CalculateScore([]db.LLMScore, *CompositeScoreConfig) (float64, float64, error) {
+	s.calls++
+	return s.score, s.confidence, s.err
+}
+
+func TestMedianScoreCalculator(t *testing.T) {
+	cfg := &CompositeScoreConfig{MinScore: -1, MaxScore: 1}
+	calc := &MedianScoreCalculator{}
+
+	score, conf, err := calc.CalculateScore([]db.LLMScore{
+		{Model: "left", Score: -0.9, Metadata: `{"confidence": 0.6}`},
+		{Model: "center", Score: 0.1, Metadata: `{"confidence": 0.8}`},
+		{Model: "right", Score: 0.2, Metadata: `{"confidence": 1.0}`},
+	}, cfg)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.1, score, 1e-9, "the outlying left score does not pull the median")
+	assert.InDelta(t, 0.8, conf, 1e-9)
+
+	score, _, err = calc.CalculateScore([]db.LLMScore{
+		{Model: "left", Score: -0.4, Metadata: `{"confidence": 0.8}`},
+		{Model: "right", Score: 0.2, Metadata: `{"confidence": 0.8}`},
+		{Model: "center", Score: 0.5, Metadata: `{}`},
+	}, cfg)
+	require.NoError(t, err)
+	assert.InDelta(t, -0.1, score, 1e-9, "an even count averages the middle two")
+
+	_, _, err = calc.CalculateScore(nil, cfg)
+	assert.ErrorIs(t, err, ErrAllPerspectivesInvalid)
+}
+
+func TestMultiScoreCalculator(t *testing.T) {
+	cfg := &CompositeScoreConfig{MinScore: -1, MaxScore: 1}
+	failing := &stubCalculator{err: ErrAllPerspectivesInvalid}
+	low := &stubCalculator{score: -0.2, confidence: 0.5}
+	high := &stubCalculator{score: 0.3, confidence: 0.9}
+	calculators := []NamedCalculator{{"failing", failing}, {"low", low}, {"high", high}}
+
+	multi, err := NewMultiScoreCalculator("", calculators...)
+	require.NoError(t, err)
+	choice, err := multi.Choose(nil, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "high", choice.Calculator)
+	score, conf, err := multi.CalculateScore(nil, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 0.3, score)
+	assert.Equal(t, 0.9, conf)
+
+	tied, err := NewMultiScoreCalculator(PolicyHighestConfidence,
+		NamedCalculator{"first", &stubCalculator{score: 0.1, confidence: 0.7}},
+		NamedCalculator{"second", &stubCalculator{score: 0.2, confidence: 0.7}})
+	require.NoError(t, err)
+	choice, err = tied.Choose(nil, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "first", choice.Calculator, "ties go to the calculator listed first")
+
+	high.calls = 0
+	firstValid, err := NewMultiScoreCalculator(PolicyFirstValid, calculators...)
+	require.NoError(t, err)
+	choice, err = firstValid.Choose(nil, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "low", choice.Calculator)
+	assert.Zero(t, high.calls, "calculators after the first result are not run")
+
+	none, err := NewMultiScoreCalculator(PolicyHighestConfidence,
+		NamedCalculator{"a", failing}, NamedCalculator{"b", &stubCalculator{err: errors.New("boom")}})
+	require.NoError(t, err)
+	_, _, err = none.CalculateScore(nil, cfg)
+	assert.ErrorIs(t, err, ErrAllPerspectivesInvalid, "the first calculator's error is kept")
+
+	_, err = NewMultiScoreCalculator("lowest_score", calculators...)
+	assert.Error(t, err)
+	_, err = NewMultiScoreCalculator(PolicyFirstValid)
+	assert.Error(t, err)
+}
+
+func TestScoreCalculatorFromEnv(t *testing.T) {
+	t.Setenv("SCORE_CALCULATORS", "")
+	assert.IsType(t, &DefaultScoreCalculator{}, ScoreCalculatorFromEnv())
+
+	t.Setenv("SCORE_CALCULATORS", "median")
+	assert.IsType(t, &MedianScoreCalculator{}, ScoreCalculatorFromEnv())
+
+	t.Setenv("SCORE_CALCULATORS", "bogus")
+	assert.IsType(t, &DefaultScoreCalculator{}, ScoreCalculatorFromEnv())
+
+	t.Setenv("SCORE_CALCULATORS", "default, Formula, bogus, median")
+	t.Setenv("SCORE_CALCULATOR_POLICY", PolicyFirstValid)
+	multi, ok := ScoreCalculatorFromEnv().(*MultiScoreCalculator)
+	require.True(t, ok)
+	assert.Equal(t, PolicyFirstValid, multi.policy)
+	require.Len(t, multi.calculators, 3)
+	assert.Equal(t, CalculatorFormula, multi.calculators[1].Name)
+
+	t.Setenv("SCORE_CALCULATOR_POLICY", "bogus")
+	multi, ok = ScoreCalculatorFromEnv().(*MultiScoreCalculator)
+	require.True(t, ok)
+	assert.Equal(t, PolicyHighestConfidence, multi.policy)
+}
+
+func TestRecomputeRecordsChosenCalculator(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "calculators.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	multi, err := NewMultiScoreCalculator(PolicyHighestConfidence,
+		NamedCalculator{CalculatorDefault, &DefaultScoreCalculator{}},
+		NamedCalculator{"confident", &stubCalculator{score: 0.25, confidence: 0.95}})
+	require.NoError(t, err)
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(dbConn, NewCache(), multi, pm)
+	cfg := &CompositeScoreConfig{
+		Formula:  "average",
+		MinScore: -1,
+		MaxScore: 1,
+		Models: []ModelConfig{
+			{ModelName: "left-model", Perspective: "left"},
+			{ModelName: "right-model", Perspective: "right"},
+		},
+	}
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('src', CURRENT_TIMESTAMP, 'https://example.com/calculators', 'title', 'content')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	for model, score := range map[string]float64{"left-model": -0.6, "right-model": 0.2} {
+		_, err = db.InsertLLMScore(dbConn, &db.LLMScore{
+			ArticleID: articleID, Model: model, Score: score, Metadata: `{"confidence": 0.8}`, Version: 1, CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	result, err := sm.RecomputeArticleScore(articleID, cfg)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.25, result.Score, 1e-9)
+
+	var metadata string
+	require.NoError(t, dbConn.Get(&metadata,
+		`SELECT metadata FROM llm_scores WHERE article_id = ? AND model = 'ensemble'`, articleID))
+	var meta struct {
+		FinalAggregation map[string]any `json:"final_aggregation"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(metadata), &meta))
+	assert.Equal(t, "confident", meta.FinalAggregation["calculator"])
+}
+
+func TestHighestConfidenceWithBuiltInCalculators(t *testing.T) {
+	cfg := &CompositeScoreConfig{
+		Formula:       "average",
+		MinScore:      -1,
+		MaxScore:      1,
+		HandleInvalid: "default",
+		Models: []ModelConfig{
+			{ModelName: "left-model", Perspective: "left"},
+			{ModelName: "center-model", Perspective: "center"},
+			{ModelName: "right-model", Perspective: "right"},
+		},
+	}
+	choose := func(scores []db.LLMScore, names ...string) *CalculatorChoice {
+		var calculators []NamedCalculator
+		for _, name := range names {
+			calc, err := NewScoreCalculator(name)
+			require.NoError(t, err)
+			calculators = append(calculators, NamedCalculator{name, calc})
+		}
+		multi, err := NewMultiScoreCalculator(PolicyHighestConfidence, calculators...)
+		require.NoError(t, err)
+		choice, err := multi.Choose(scores, cfg)
+		require.NoError(t, err)
+		return choice
+	}
+
+	confident := []db.LLMScore{
+		{Model: "left-model", Score: -0.6, Metadata: `{"confidence": 0.8}`},
+		{Model: "center-model", Score: 0.0, Metadata: `{"confidence": 0.8}`},
+		{Model: "right-model", Score: 0.3, Metadata: `{"confidence": 0.8}`},
+	}
+	for _, names := range [][]string{
+		{CalculatorFormula, CalculatorDefault, CalculatorMedian},
+		{CalculatorMedian, CalculatorFormula, CalculatorDefault},
+	} {
+		choice := choose(confident, names...)
+		assert.Equal(t, names[0], choice.Calculator, "equal model confidences tie on the same scale")
+		assert.InDelta(t, 0.8, choice.Confidence, 1e-9)
+	}
+
+	withZero := append([]db.LLMScore{}, confident...)
+	withZero[1].Metadata = `{"confidence": 0}`
+	choice := choose(withZero, CalculatorFormula, CalculatorDefault)
+	assert.Equal(t, CalculatorDefault, choice.Calculator, "formula averages in the zero-confidence model")
+	assert.InDelta(t, 0.8, choice.Confidence, 1e-9)
+}
+
+func TestReanalysisUsesConfiguredCalculator(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "reanalysis.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	svc := NewFixtureLLMService(Fixture{Default: FixtureResponse{Score: 0.3, Confidence: 0.8, Explanation: "ok"}})
+	cfg, err := LoadCompositeScoreConfig()
+	require.NoError(t, err)
+	client := NewLLMClientWithService(dbConn, svc, cfg)
+	multi, err := NewMultiScoreCalculator(PolicyHighestConfidence,
+		NamedCalculator{CalculatorDefault, &DefaultScoreCalculator{}},
+		NamedCalculator{"confident", &stubCalculator{score: -0.25, confidence: 0.95}})
+	require.NoError(t, err)
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(dbConn, NewCache(), multi, pm)
+
+	reanalyze := func(url string) (float64, map[string]any) {
+		res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+			VALUES ('src', CURRENT_TIMESTAMP, ?, 'title', 'content')`, url)
+		require.NoError(t, err)
+		articleID, err := res.LastInsertId()
+		require.NoError(t, err)
+		require.NoError(t, client.ReanalyzeArticle(context.Background(), articleID, sm))
+
+		var composite float64
+		require.NoError(t, dbConn.Get(&composite, `SELECT composite_score FROM articles WHERE id = ?`, articleID))
+		var metadata string
+		require.NoError(t, dbConn.Get(&metadata,
+			`SELECT metadata FROM llm_scores WHERE article_id = ? AND model = 'ensemble'`, articleID))
+		var meta struct {
+			FinalAggregation map[string]any `json:"final_aggregation"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(metadata), &meta))
+		return composite, meta.FinalAggregation
+	}
+
+	t.Setenv("SCORE_CALCULATORS", "")
+	composite, aggregation := reanalyze("https://example.com/formula")
+	assert.InDelta(t, 0.3, composite, 1e-9, "without SCORE_CALCULATORS reanalysis keeps the config formula")
+	assert.Equal(t, CalculatorFormula, aggregation["calculator"])
+
+	t.Setenv("SCORE_CALCULATORS", "default,median")
+	composite, aggregation = reanalyze("https://example.com/configured")
+	assert.InDelta(t, -0.25, composite, 1e-9)
+	assert.Equal(t, "confident", aggregation["calculator"])
+}
+
+func TestFormulaAndMedianTraceScore(t *testing.T) {
+	cfg := &CompositeScoreConfig{
+		Formula:       "weighted",
+		MinScore:      -1,
+		MaxScore:      1,
+		HandleInvalid: "ignore",
+		Weights:       map[string]float64{"left": 1, "center": 2, "right": 1},
+		Models: []ModelConfig{
+			{ModelName: "left-a", Perspective: "left"},
+			{ModelName: "left-b", Perspective: "left"},
+			{ModelName: "center-model", Perspective: "center"},
+			{ModelName: "right-model", Perspective: "right"},
+		},
+	}
+	scores := []db.LLMScore{
+		{Model: "left-a", Score: -0.8, Metadata: `{"confidence": 0.9}`},
+		{Model: "left-b", Score: -0.4, Metadata: `{"confidence": 0.7}`},
+		{Model: "center-model", Score: 0.1, Metadata: `{"confidence": 0.6}`},
+		{Model: "right-model", Score: 0.5, Metadata: `{"confidence": 0.8}`},
+		{Model: "right-model", Score: 1.5, Metadata: `{"confidence": 0.8}`},
+	}
+
+	formula := &FormulaScoreCalculator{}
+	trace, err := formula.TraceScore(scores, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, AggregationFormulaPerspectiveWeighted, trace.Formula)
+	assert.InDelta(t, 0.5, trace.Models[0].Weight, 1e-9, "left's weight is shared by its two models")
+	assert.InDelta(t, 2.0, trace.Models[2].Weight, 1e-9)
+	assert.False(t, trace.Models[4].Included)
+	assert.InDelta(t, 4.0, trace.WeightTotal, 1e-9)
+	assert.InDelta(t, trace.WeightedSum/trace.WeightTotal, trace.PreClampScore, 1e-9)
+	score, confidence, err := formula.CalculateScore(scores, cfg)
+	require.NoError(t, err)
+	assert.InDelta(t, score, trace.Score, 1e-9)
+	assert.InDelta(t, score, trace.PreClampScore, 1e-9)
+	assert.InDelta(t, confidence, trace.RawConfidence, 1e-9)
+
+	median := &MedianScoreCalculator{}
+	trace, err = median.TraceScore(scores, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, AggregationFormulaMedian, trace.Formula)
+	assert.InDelta(t, 0.5, trace.Models[1].Weight, 1e-9, "the two middle scores share the weight")
+	assert.InDelta(t, 0.5, trace.Models[2].Weight, 1e-9)
+	assert.Zero(t, trace.Models[0].Weight)
+	assert.True(t, trace.Models[0].Included)
+	score, _, err = median.CalculateScore(scores, cfg)
+	require.NoError(t, err)
+	assert.InDelta(t, -0.15, score, 1e-9)
+	assert.InDelta(t, score, trace.Score, 1e-9)
+}
+
+func TestTraceArticleCompositeReplaysStoredCalculator(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "trace.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	cfg := &CompositeScoreConfig{
+		MinScore: -1,
+		MaxScore: 1,
+		Models: []ModelConfig{
+			{ModelName: "left-a", Perspective: "left"},
+			{ModelName: "left-b", Perspective: "left"},
+			{ModelName: "right-model", Perspective: "right"},
+		},
+	}
+	stored := []db.LLMScore{
+		{Model: "left-a", Score: -0.9, Metadata: `{"confidence": 0.8}`},
+		{Model: "left-b", Score: -0.3, Metadata: `{"confidence": 0.8}`},
+		{Model: "right-model", Score: 0.6, Metadata: `{"confidence": 0.8}`},
+	}
+	withEnsemble := func(calculator string) []db.LLMScore {
+		return append([]db.LLMScore{
+			{Model: "ensemble", Metadata: `{"final_aggregation": {"calculator": "formula"}}`, CreatedAt: time.Now().Add(-time.Hour)},
+			{Model: "ensemble", Metadata: fmt.Sprintf(`{"final_aggregation": {"calculator": %q}}`, calculator), CreatedAt: time.Now()},
+		}, stored...)
+	}
+
+	trace := TraceArticleComposite(dbConn, 1, stored, cfg)
+	require.NotNil(t, trace)
+	assert.Equal(t, CalculatorFormula, trace.Calculator, "without a recorded calculator the formula is traced")
+	assert.InDelta(t, 0.0, trace.Score, 1e-9)
+
+	for name, want := range map[string]float64{CalculatorDefault: -0.2, CalculatorMedian: -0.3, "custom": 0.0} {
+		trace = TraceArticleComposite(dbConn, 1, withEnsemble(name), cfg)
+		require.NotNil(t, trace, name)
+		assert.InDelta(t, want, trace.Score, 1e-9, name)
+		if name != "custom" {
+			assert.Equal(t, name, trace.Calculator)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+// Long content modes, selected with long_content_mode in the composite score config
+const (
+	// LongContentTruncate shortens overlong content with the configured truncation strategy
+	LongContentTruncate = "truncate"
+	// LongContentChunk scores overlapping chunks separately and aggregates the results
+	LongContentChunk = "chunk"
+)
+
+// defaultChunkOverlapChars is the overlap between consecutive chunks when none is configured
+const defaultChunkOverlapChars = 200
+
+// chunkScore is the ensemble result for one chunk of an article
+type chunkScore struct {
+	Index      int     `json:"index"`
+	Length     int     `json:"length"`
+	Score      float64 `json:"score"`
+	Confidence float64 `json:"confidence"`
+}
+
+// SplitIntoChunks splits content into chunks of at most chunkSize characters, each
+// starting overlap characters before the end of the previous one
+func SplitIntoChunks(content string, chunkSize int, overlap int) []string {
+	runes := []rune(content)
+	if chunkSize <= 0 || len(runes) <= chunkSize {
+		return []string{content}
+	}
+	if overlap < 0 || overlap >= chunkSize {
+		overlap = 0
+	}
+
+	step := chunkSize - overlap
+	var chunks []string
+	for start := 0; start < len(runes); start += step {
+		end := start + chunkSize
+		if end >= len(runes) {
+			chunks = append(chunks, string(runes[start:]))
+			break
+		}
+		chunks = append(chunks, string(runes[start:end]))
+	}
+	return chunks
+}
+
+// chunkContent returns the chunks to score separately. It returns a single chunk
+// unless chunking is enabled and the content exceeds the configured limit.
+func (cfg *CompositeScoreConfig) chunkContent(content string) []string {
+	if cfg == nil || cfg.LongContentMode != LongContentChunk {
+		return []string{content}
+	}
+	overlap := cfg.ChunkOverlapChars
+	if overlap == 0 {
+		overlap = defaultChunkOverlapChars
+	}
+	return SplitIntoChunks(content, cfg.MaxContentLength(), overlap)
+}
+
+// ensembleAnalyzeChunks scores each chunk and combines the results into a mean weighted
+// by chunk length. Chunks that fail to score are skipped; an error is returned only
+// when no chunk could be scored or ctx is cancelled.
+func (c *LLMClient) ensembleAnalyzeChunks(ctx context.Context, articleID int64, chunks []string, models []string) (float64, float64, map[string]interface{}, error) {
+	results := make([]chunkScore, 0, len(chunks))
+	var weightedScore, weightedConfidence, totalLength float64
+	for i, chunk := range chunks {
+		score, confidence, _, err := c.ensembleAnalyzeContent(ctx, articleID, chunk, models)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return 0, 0, nil, ctxErr
+		}
+		if err != nil {
+			log.Printf("[Ensemble] ArticleID %d | Chunk %d/%d failed: %v", articleID, i+1, len(chunks), err)
+			continue
+		}
+		length := len([]rune(chunk))
+		results = append(results, chunkScore{Index: i, Length: length, Score: score, Confidence: confidence})
+		weightedScore += score * float64(length)
+		weightedConfidence += confidence * float64(length)
+		totalLength += float64(length)
+	}
+
+	if len(results) == 0 {
+		return 0, 0, nil, fmt.Errorf("no chunk of article %d could be scored", articleID)
+	}
+
+	finalScore := weightedScore / math.Max(totalLength, 1)
+	confidence := weightedConfidence / math.Max(totalLength, 1)
+	log.Printf("[Ensemble] ArticleID %d | Chunked score %.4f from %d/%d chunks", articleID, finalScore, len(results), len(chunks))
+
+	meta := map[string]interface{}{
+		"confidence":    confidence,
+		"chunked":       true,
+		"chunk_count":   len(chunks),
+		"chunks_scored": len(results),
+		"chunk_scores":  results,
+		"final_aggregation": map[string]interface{}{
+			"weighted_mean": finalScore,
+			"weighting":     "chunk_length",
+		},
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+	return finalScore, confidence, meta, nil
+}
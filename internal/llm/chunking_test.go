@@ -0,0 +1,86 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitIntoChunks(t *testing.T) {
+	assert.Equal(t, []string{"short"}, SplitIntoChunks("short", 10, 2))
+	assert.Equal(t, []string{"abcdef"}, SplitIntoChunks("abcdef", 0, 2), "no limit means one chunk")
+
+	chunks := SplitIntoChunks("abcdefghij", 4, 1)
+	assert.Equal(t, []string{"abcd", "defg", "ghij"}, chunks)
+
+	chunks = SplitIntoChunks("abcdefghij", 4, 0)
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, chunks)
+
+	// An overlap that would never advance is ignored
+	chunks = SplitIntoChunks("abcdefgh", 4, 4)
+	assert.Equal(t, []string{"abcd", "efgh"}, chunks)
+}
+
+func TestChunkContentIsOptIn(t *testing.T) {
+	long := strings.Repeat("x", 500)
+
+	cfg := &CompositeScoreConfig{MaxContentChars: 100}
+	assert.Len(t, cfg.chunkContent(long), 1, "truncate mode never chunks")
+
+	cfg.LongContentMode = LongContentChunk
+	assert.Len(t, cfg.chunkContent("short article"), 1, "normal-length articles are scored in one pass")
+	assert.Greater(t, len(cfg.chunkContent(long)), 1)
+}
+
+func TestEnsembleAnalyzeChunked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		score := 0.4
+		if strings.Contains(string(body), "AAAA") {
+			score = -0.5
+		}
+		content, _ := json.Marshal(map[string]float64{"score": score, "confidence": 0.9})
+		resp, _ := json.Marshal(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"content": string(content)}}},
+		})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(resp)
+	}))
+	defer server.Close()
+
+	client := &LLMClient{
+		llmService: NewHTTPLLMService(resty.New(), "test-key", "", server.URL),
+		config: &CompositeScoreConfig{
+			Models:            []ModelConfig{{ModelName: "model1", Perspective: "center", Weight: 1.0}},
+			MaxContentChars:   200,
+			LongContentMode:   LongContentChunk,
+			ChunkOverlapChars: -1, // no overlap keeps the expected weights simple
+		},
+	}
+
+	score, err := client.EnsembleAnalyze(context.Background(), 1, strings.Repeat("A", 200)+strings.Repeat("B", 100))
+	require.NoError(t, err)
+	assert.InDelta(t, (-0.5*200+0.4*100)/300, score.Score, 1e-9, "chunks are weighted by length")
+
+	var meta map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(score.Metadata), &meta))
+	assert.Equal(t, true, meta["chunked"])
+	assert.Equal(t, float64(2), meta["chunk_count"])
+	assert.Len(t, meta["chunk_scores"], 2)
+
+	// Short content takes the single-pass path
+	score, err = client.EnsembleAnalyze(context.Background(), 2, "BBBB")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.4, score.Score, 1e-9)
+	meta = nil
+	require.NoError(t, json.Unmarshal([]byte(score.Metadata), &meta))
+	assert.NotContains(t, meta, "chunked")
+}
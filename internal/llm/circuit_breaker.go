@@ -0,0 +1,195 @@
+package llm
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrProviderUnavailable is returned without calling the provider while the circuit
+// breaker is open
+var ErrProviderUnavailable = fmt.Errorf("%w: circuit breaker open", ErrLLMServiceUnavailable)
+
+// Circuit breaker defaults: 5 consecutive failures within a minute open the breaker
+// for 30 seconds
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerWindow    = time.Minute
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// BreakerStats reports the state of the provider circuit breaker
+type BreakerStats struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenUntil           *time.Time `json:"open_until,omitempty"`
+	Opens               int        `json:"opens"` // times the breaker has opened
+	Threshold           int        `json:"threshold"`
+	Window              string     `json:"window"`
+	Cooldown            string     `json:"cooldown"`
+}
+
+// circuitBreaker stops calls to a failing provider. threshold consecutive failures,
+// each within window of the first, open it; calls then fail fast for cooldown, after
+// which a single probe call is let through. The probe's success closes the breaker
+// and its failure opens it again. A nil breaker lets every call through.
+type circuitBreaker struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu           sync.Mutex
+	state        string
+	failures     int
+	firstFailure time.Time
+	openUntil    time.Time
+	opens        int
+}
+
+func newCircuitBreaker(threshold int, window, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, window: window, cooldown: cooldown, now: time.Now, state: BreakerClosed}
+}
+
+// newCircuitBreakerFromEnv creates the breaker tuned by LLM_BREAKER_THRESHOLD,
+// LLM_BREAKER_WINDOW and LLM_BREAKER_COOLDOWN. A threshold of 0 disables it.
+func newCircuitBreakerFromEnv() *circuitBreaker {
+	threshold := defaultBreakerThreshold
+	if v := os.Getenv("LLM_BREAKER_THRESHOLD"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			threshold = n
+		} else {
+			log.Printf("[WARN] Invalid LLM_BREAKER_THRESHOLD %q, using default %d", v, defaultBreakerThreshold)
+		}
+	}
+	if threshold == 0 {
+		return nil
+	}
+	duration := func(name string, def time.Duration) time.Duration {
+		v := os.Getenv(name)
+		if v == "" {
+			return def
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Printf("[WARN] Invalid %s %q, using default %v", name, v, def)
+			return def
+		}
+		return d
+	}
+	return newCircuitBreaker(threshold, duration("LLM_BREAKER_WINDOW", defaultBreakerWindow),
+		duration("LLM_BREAKER_COOLDOWN", defaultBreakerCooldown))
+}
+
+// allow returns ErrProviderUnavailable when a call must not reach the provider. Once
+// the cooldown is over it lets one probe call through and holds back the rest until
+// the probe reports back.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Before(b.openUntil) {
+			return ErrProviderUnavailable
+		}
+		b.state = BreakerHalfOpen
+		log.Printf("[INFO] LLM circuit breaker half-open, probing the provider")
+		return nil
+	case BreakerHalfOpen:
+		return ErrProviderUnavailable // The probe has not reported back yet
+	}
+	return nil
+}
+
+// recordSuccess closes the breaker
+func (b *circuitBreaker) recordSuccess() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != BreakerClosed {
+		log.Printf("[INFO] LLM circuit breaker closed, the provider recovered")
+	}
+	b.state = BreakerClosed
+	b.failures = 0
+}
+
+// recordFailure counts a failed call, opening the breaker at the threshold or when a
+// probe fails
+func (b *circuitBreaker) recordFailure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if b.state == BreakerHalfOpen {
+		b.failures++
+		b.open(now)
+		return
+	}
+	if b.failures == 0 || now.Sub(b.firstFailure) > b.window {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+	if b.state == BreakerClosed && b.failures >= b.threshold {
+		b.open(now)
+	}
+}
+
+// open starts a cooldown. Callers must hold the lock.
+func (b *circuitBreaker) open(now time.Time) {
+	b.state = BreakerOpen
+	b.openUntil = now.Add(b.cooldown)
+	b.opens++
+	log.Printf("[WARN] LLM circuit breaker open after %d consecutive failures, failing calls fast until %s",
+		b.failures, b.openUntil.Format(time.RFC3339))
+}
+
+// openedUntil returns when an open breaker lets a probe through, and false when it is
+// not open
+func (b *circuitBreaker) openedUntil() (time.Time, bool) {
+	if b == nil {
+		return time.Time{}, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != BreakerOpen || !b.now().Before(b.openUntil) {
+		return time.Time{}, false
+	}
+	return b.openUntil, true
+}
+
+// stats returns a snapshot of the breaker state
+func (b *circuitBreaker) stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := BreakerStats{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Opens:               b.opens,
+		Threshold:           b.threshold,
+		Window:              b.window.String(),
+		Cooldown:            b.cooldown.String(),
+	}
+	if b.state == BreakerOpen {
+		until := b.openUntil
+		s.OpenUntil = &until
+	}
+	return s
+}
@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	b := newCircuitBreaker(3, time.Minute, 30*time.Second)
+	now := time.Unix(1_700_000_000, 0)
+	b.now = func() time.Time { return now }
+
+	// Failures spread beyond the window never add up to the threshold
+	b.recordFailure()
+	b.recordFailure()
+	now = now.Add(2 * time.Minute)
+	b.recordFailure()
+	assert.NoError(t, b.allow())
+	assert.Equal(t, 1, b.stats().ConsecutiveFailures)
+
+	// A success resets the count
+	b.recordSuccess()
+	b.recordFailure()
+	b.recordFailure()
+	assert.NoError(t, b.allow())
+	b.recordFailure()
+	assert.ErrorIs(t, b.allow(), ErrProviderUnavailable)
+	assert.ErrorIs(t, b.allow(), ErrLLMServiceUnavailable)
+	until, open := b.openedUntil()
+	assert.True(t, open)
+	assert.Equal(t, now.Add(30*time.Second), until)
+	stats := b.stats()
+	assert.Equal(t, BreakerOpen, stats.State)
+	assert.Equal(t, 1, stats.Opens)
+	require.NotNil(t, stats.OpenUntil)
+
+	// After the cooldown a single probe goes through
+	now = now.Add(31 * time.Second)
+	_, open = b.openedUntil()
+	assert.False(t, open)
+	assert.NoError(t, b.allow())
+	assert.Equal(t, BreakerHalfOpen, b.stats().State)
+	assert.ErrorIs(t, b.allow(), ErrProviderUnavailable, "calls wait for the probe")
+
+	// A failed probe opens the breaker again, a successful one closes it
+	b.recordFailure()
+	assert.ErrorIs(t, b.allow(), ErrProviderUnavailable)
+	assert.Equal(t, 2, b.stats().Opens)
+	now = now.Add(31 * time.Second)
+	assert.NoError(t, b.allow())
+	b.recordSuccess()
+	assert.Equal(t, BreakerClosed, b.stats().State)
+	assert.NoError(t, b.allow())
+	assert.NoError(t, b.allow())
+
+	var disabled *circuitBreaker
+	assert.NoError(t, disabled.allow())
+	disabled.recordFailure()
+	_, open = disabled.openedUntil()
+	assert.False(t, open)
+}
+
+func TestCircuitBreakerFromEnv(t *testing.T) {
+	t.Setenv("LLM_BREAKER_THRESHOLD", "0")
+	assert.Nil(t, newCircuitBreakerFromEnv(), "a zero threshold disables the breaker")
+
+	t.Setenv("LLM_BREAKER_THRESHOLD", "7")
+	t.Setenv("LLM_BREAKER_WINDOW", "2m")
+	t.Setenv("LLM_BREAKER_COOLDOWN", "soon")
+	b := newCircuitBreakerFromEnv()
+	require.NotNil(t, b)
+	assert.Equal(t, 7, b.threshold)
+	assert.Equal(t, 2*time.Minute, b.window)
+	assert.Equal(t, defaultBreakerCooldown, b.cooldown)
+}
+
+func TestHTTPLLMServiceCircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	service := NewHTTPLLMServiceWithKeys(resty.New(), []string{"key-a"}, server.URL)
+	service.breaker = newCircuitBreaker(2, time.Minute, time.Minute)
+	client := NewLLMClientWithService(nil, service, nil)
+
+	for i := 0; i < 2; i++ {
+		resp, err := service.callLLMAPIWithKey(testModelName, "prompt", "key-a")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode())
+	}
+	_, err := service.callLLMAPIWithKey(testModelName, "prompt", "key-a")
+	assert.ErrorIs(t, err, ErrProviderUnavailable)
+	assert.Equal(t, int32(2), requests.Load(), "an open breaker fails fast without calling the provider")
+
+	stats := client.BreakerStats()
+	require.NotNil(t, stats)
+	assert.Equal(t, BreakerOpen, stats.State)
+	_, unavailable := client.ProviderUnavailableUntil()
+	assert.True(t, unavailable)
+
+	service.breaker = nil
+	assert.Nil(t, client.BreakerStats())
+	_, unavailable = client.ProviderUnavailableUntil()
+	assert.False(t, unavailable)
+}
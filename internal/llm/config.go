@@ -1,11 +1,14 @@
 package llm
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // Label constants for perspectives
@@ -18,17 +21,56 @@ const (
 
 // CompositeScoreConfig defines the structure for composite score calculation configuration
 type CompositeScoreConfig struct {
-	Models            []ModelConfig      `json:"models"`
-	Formula           string             `json:"formula"` // "average" or "weighted"
-	ConfidenceMethod  string             `json:"confidence_method"`
-	MinScore          float64            `json:"min_score"`
-	MaxScore          float64            `json:"max_score"`
-	DefaultMissing    float64            `json:"default_missing"`
-	MinConfidence     float64            `json:"min_confidence"`
-	MaxConfidence     float64            `json:"max_confidence"`
-	HandleInvalid     string             `json:"handle_invalid"` // "default" or "ignore"
-	Weights           map[string]float64 `json:"weights"`        // Optional: Perspective weights for "weighted" formula
-	ArticleIDForDebug int64              `json:"-"`              // Temporary field for debugging logs, ignored by JSON
+	Models           []ModelConfig `json:"models"`
+	Formula          string        `json:"formula"` // "average" or "weighted"
+	ConfidenceMethod string        `json:"confidence_method"`
+	MinScore         float64       `json:"min_score"`
+	MaxScore         float64       `json:"max_score"`
+	DefaultMissing   float64       `json:"default_missing"`
+	MinConfidence    float64       `json:"min_confidence"`
+	MaxConfidence    float64       `json:"max_confidence"`
+	HandleInvalid    string        `json:"handle_invalid"` // "default" or "ignore"
+	// MinModelsForComposite is how many models must return a valid score before a
+	// composite is stored; zero is treated as 1
+	MinModelsForComposite int                `json:"min_models_for_composite,omitempty"`
+	Weights               map[string]float64 `json:"weights"` // Optional: Perspective weights for "weighted" formula
+	ArticleIDForDebug     int64              `json:"-"`       // Temporary field for debugging logs, ignored by JSON
+
+	// Content length limits applied before submission; zero means unlimited.
+	// max_content_tokens is approximated as 4 characters per token.
+	MaxContentChars    int    `json:"max_content_chars,omitempty"`
+	MaxContentTokens   int    `json:"max_content_tokens,omitempty"`
+	TruncationStrategy string `json:"truncation_strategy,omitempty"` // "head", "head_tail" (default) or "middle_out"
+
+	// Long content handling: "truncate" (default) or "chunk" to score overlapping
+	// chunks of max content length and average them weighted by chunk length.
+	// chunk_overlap_chars defaults to 200; a negative value disables overlap.
+	LongContentMode   string `json:"long_content_mode,omitempty"`
+	ChunkOverlapChars int    `json:"chunk_overlap_chars,omitempty"`
+
+	// Few-shot example rotation: include example_sample_size examples drawn from each
+	// prompt variant per call instead of all of them. example_seed makes the draw
+	// reproducible for a given article.
+	ExampleSampleSize int    `json:"example_sample_size,omitempty"`
+	ExampleSeed       *int64 `json:"example_seed,omitempty"`
+
+	// Sampling parameters sent with every scoring request (temperature, top_p, seed).
+	// Temperature defaults to 0 for reproducible runs.
+	SamplingParams
+
+	// ModelConcurrency is how many models an ensemble analysis calls at once; zero means
+	// the default of 1, which calls them one after another
+	ModelConcurrency int `json:"model_concurrency,omitempty"`
+
+	// Price rates per model name for estimating LLM spend; models without a rate are
+	// reported with their token counts but no cost
+	ModelPrices map[string]ModelPrice `json:"model_prices,omitempty"`
+
+	// Optional embedding-based perspective added to ensemble analysis; disabled by default
+	Embedding *EmbeddingConfig `json:"embedding,omitempty"`
+
+	// When a result is flagged uncertain; see UncertaintyConfig for the exact conditions
+	Uncertainty *UncertaintyConfig `json:"uncertainty,omitempty"`
 }
 
 // ModelConfig defines configuration for a single model within the composite score
@@ -37,13 +79,30 @@ type ModelConfig struct {
 	Perspective string  `json:"perspective"`
 	Weight      float64 `json:"weight"`
 	URL         string  `json:"url"`
+	// Fallback is the model to score with when this one fails; see FallbackChain
+	Fallback string `json:"fallback,omitempty"`
+	// Latency budget: with exclude_when_slow, a model whose rolling average latency is
+	// above max_latency_ms is still called but left out of ensemble aggregation
+	MaxLatencyMs    int  `json:"max_latency_ms,omitempty"`
+	ExcludeWhenSlow bool `json:"exclude_when_slow,omitempty"`
+	// ExtraParams are model-specific fields added to each scoring request body for this
+	// model. They take precedence over the sampling parameters; see applyExtraParams.
+	ExtraParams map[string]interface{} `json:"extra_params,omitempty"`
 }
 
 // LoadCompositeScoreConfig loads the configuration from a JSON file
 func LoadCompositeScoreConfig() (*CompositeScoreConfig, error) {
+	configPath, err := CompositeScoreConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	return loadConfigFromPath(configPath)
+}
+
+// CompositeScoreConfigPath returns the path of the composite score config file
+func CompositeScoreConfigPath() (string, error) {
 	// Try multiple possible locations for the config file
 	var configPath string
-	var err error
 
 	// First try: relative to current working directory
 	wd, err := os.Getwd()
@@ -53,7 +112,7 @@ func LoadCompositeScoreConfig() (*CompositeScoreConfig, error) {
 		configPath = filepath.Join(wd, "configs", "composite_score_config.json")
 		if _, err := os.Stat(configPath); err == nil {
 			log.Printf("Found composite score config at: %s", configPath)
-			return loadConfigFromPath(configPath)
+			return configPath, nil
 		}
 	}
 
@@ -61,23 +120,88 @@ func LoadCompositeScoreConfig() (*CompositeScoreConfig, error) {
 	configPath = "/configs/composite_score_config.json"
 	if _, err := os.Stat(configPath); err == nil {
 		log.Printf("Found composite score config at: %s", configPath)
-		return loadConfigFromPath(configPath)
+		return configPath, nil
 	}
 
 	// Third try: relative to executable
 	configPath = "configs/composite_score_config.json"
 	if _, err := os.Stat(configPath); err == nil {
 		log.Printf("Found composite score config at: %s", configPath)
-		return loadConfigFromPath(configPath)
+		return configPath, nil
 	}
 
 	log.Printf("Could not find composite score config file in any of the expected locations")
-	return nil, fmt.Errorf("composite score config file not found")
+	return "", fmt.Errorf("composite score config file not found")
 }
 
-func loadConfigFromPath(configPath string) (*CompositeScoreConfig, error) {
-	log.Printf("Attempting to load composite score config from: %s", configPath)
+// ReadCompositeScoreConfigFile returns the config file as stored, without the
+// LLM_MODELS override applied
+func ReadCompositeScoreConfigFile() (*CompositeScoreConfig, error) {
+	configPath, err := CompositeScoreConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	return readConfigFile(configPath)
+}
 
+// SaveCompositeScoreConfig validates cfg and replaces the config file with it. The
+// file is written to a temporary file first and renamed, so a reader never sees a
+// partial config.
+func SaveCompositeScoreConfig(cfg *CompositeScoreConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	configPath, err := CompositeScoreConfigPath()
+	if err != nil {
+		return err
+	}
+	return writeConfigFile(configPath, cfg)
+}
+
+// writeConfigFile replaces the config file at configPath with cfg, keeping the file's
+// permissions and its CRLF line endings when it has them
+func writeConfigFile(configPath string, cfg *CompositeScoreConfig) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return err
+	}
+	existing, err := os.ReadFile(configPath) // #nosec G304 - configPath is from application configuration, controlled input
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(existing, []byte("\r\n")) {
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(configPath), ".composite_score_config-*.json")
+	if err != nil {
+		return err
+	}
+	// Removing fails harmlessly once the file has been renamed into place
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), configPath); err != nil {
+		return err
+	}
+	log.Printf("Saved composite score config to: %s", configPath)
+	return nil
+}
+
+func readConfigFile(configPath string) (*CompositeScoreConfig, error) {
 	bytes, err := os.ReadFile(configPath) // #nosec G304 - configPath is from application configuration, controlled input
 	if err != nil {
 		log.Printf("Error reading config file %s: %v", configPath, err)
@@ -89,7 +213,143 @@ func loadConfigFromPath(configPath string) (*CompositeScoreConfig, error) {
 		log.Printf("Error parsing config file %s: %v", configPath, err)
 		return nil, err
 	}
+	return &config, nil
+}
+
+func loadConfigFromPath(configPath string) (*CompositeScoreConfig, error) {
+	log.Printf("Attempting to load composite score config from: %s", configPath)
+
+	config, err := readConfigFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if override := os.Getenv("LLM_MODELS"); override != "" {
+		if err := applyModelOverride(config, override); err != nil {
+			log.Printf("Error applying LLM_MODELS override: %v", err)
+			return nil, err
+		}
+		log.Printf("[WARN] LLM_MODELS override active, using models: %s", strings.Join(modelNames(config.Models), ", "))
+	}
 
 	log.Printf("Successfully loaded and parsed composite score config from: %s", configPath)
-	return &config, nil
+	return config, nil
+}
+
+// Validate reports the first problem that would keep the config from scoring: no
+// models, a model without a name or perspective, a negative or non-finite weight,
+// invalid extra_params, or an unknown formula
+func (cfg *CompositeScoreConfig) Validate() error {
+	if cfg == nil || len(cfg.Models) == 0 {
+		return fmt.Errorf("config has no models")
+	}
+	for i, m := range cfg.Models {
+		if strings.TrimSpace(m.ModelName) == "" {
+			return fmt.Errorf("model %d has no modelName", i+1)
+		}
+		if strings.TrimSpace(m.Perspective) == "" {
+			return fmt.Errorf("model %s has no perspective", m.ModelName)
+		}
+		if !validWeight(m.Weight) {
+			return fmt.Errorf("model %s has invalid weight %v", m.ModelName, m.Weight)
+		}
+		if m.MaxLatencyMs < 0 {
+			return fmt.Errorf("model %s has negative max_latency_ms %d", m.ModelName, m.MaxLatencyMs)
+		}
+		if m.ExcludeWhenSlow && m.MaxLatencyMs == 0 {
+			return fmt.Errorf("model %s sets exclude_when_slow without max_latency_ms", m.ModelName)
+		}
+		if err := validateExtraParams(m.ExtraParams); err != nil {
+			return fmt.Errorf("model %s has invalid extra_params: %w", m.ModelName, err)
+		}
+	}
+	for perspective, w := range cfg.Weights {
+		if !validWeight(w) {
+			return fmt.Errorf("perspective %s has invalid weight %v", perspective, w)
+		}
+	}
+	for model, price := range cfg.ModelPrices {
+		if !validWeight(price.PromptPerMillion) || !validWeight(price.CompletionPerMillion) {
+			return fmt.Errorf("model %s has an invalid price", model)
+		}
+	}
+	switch cfg.Formula {
+	case "", "average", "weighted":
+	default:
+		return fmt.Errorf("unknown formula %q", cfg.Formula)
+	}
+	if cfg.MinScore > cfg.MaxScore {
+		return fmt.Errorf("min_score %v is above max_score %v", cfg.MinScore, cfg.MaxScore)
+	}
+	return nil
+}
+
+func validWeight(w float64) bool {
+	return w >= 0 && !math.IsInf(w, 0) && !math.IsNaN(w)
+}
+
+// applyModelOverride replaces the configured models with the comma-separated list in
+// value. Each entry is a model name, optionally prefixed with its perspective as
+// "perspective=model". Entries without a perspective, and all entries' URL and weight,
+// take the settings of the configured model at the same position, falling back to the
+// first configured model and the center perspective.
+func applyModelOverride(config *CompositeScoreConfig, value string) error {
+	entries := strings.Split(value, ",")
+	models := make([]ModelConfig, 0, len(entries))
+	for i, entry := range entries {
+		perspective, name, hasPerspective := strings.Cut(strings.TrimSpace(entry), "=")
+		if !hasPerspective {
+			name, perspective = perspective, ""
+		}
+		name, perspective = strings.TrimSpace(name), strings.ToLower(strings.TrimSpace(perspective))
+		if name == "" {
+			return fmt.Errorf("LLM_MODELS entry %d has an empty model name", i+1)
+		}
+		if hasPerspective && perspective == "" {
+			return fmt.Errorf("LLM_MODELS entry %d has an empty perspective", i+1)
+		}
+
+		model := ModelConfig{Perspective: LabelCenter, Weight: 1.0}
+		if i < len(config.Models) {
+			model = config.Models[i]
+		} else if len(config.Models) > 0 {
+			model.URL = config.Models[0].URL
+		}
+		model.ModelName = name
+		if hasPerspective {
+			model.Perspective = perspective
+		}
+		models = append(models, model)
+	}
+	config.Models = models
+	return nil
+}
+
+// DefaultModelConcurrency is how many models an ensemble analysis calls at once unless
+// model_concurrency says otherwise. Models are called one after another by default, so
+// parallel provider calls are opt-in.
+const DefaultModelConcurrency = 1
+
+// modelConcurrency returns how many models an ensemble analysis may call at once
+func (cfg *CompositeScoreConfig) modelConcurrency() int {
+	if cfg == nil || cfg.ModelConcurrency < 1 {
+		return DefaultModelConcurrency
+	}
+	return cfg.ModelConcurrency
+}
+
+// minModelsForComposite returns the configured minimum number of valid model scores
+func (cfg *CompositeScoreConfig) minModelsForComposite() int {
+	if cfg == nil || cfg.MinModelsForComposite < 1 {
+		return 1
+	}
+	return cfg.MinModelsForComposite
+}
+
+func modelNames(models []ModelConfig) []string {
+	names := make([]string, len(models))
+	for i, m := range models {
+		names[i] = m.ModelName
+	}
+	return names
 }
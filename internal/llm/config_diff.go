@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"math"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+)
+
+// configChangeEpsilon is the smallest composite difference counted as a change
+const configChangeEpsilon = 1e-6
+
+// ConfigDiff summarizes how a proposed config would change composites compared with
+// the current one, recomputed from the same stored per-model scores. The change
+// figures cover the articles both configs produce a composite for.
+type ConfigDiff struct {
+	Articles       int               `json:"articles"`
+	Compared       int               `json:"compared"`
+	Changed        int               `json:"changed"`
+	MeanAbsChange  float64           `json:"mean_abs_change"`
+	MaxAbsChange   float64           `json:"max_abs_change"`
+	LabelFlips     int               `json:"label_flips"`
+	ProposedFailed int               `json:"proposed_failed"` // scored under the current config but not the proposed one
+	Skipped        int               `json:"skipped"`         // no usable scores or no composite under the current config
+	Flips          []ConfigLabelFlip `json:"flips"`
+}
+
+// ConfigLabelFlip is an article whose bias label differs between the two configs
+type ConfigLabelFlip struct {
+	ArticleID     int64   `json:"article_id"`
+	CurrentScore  float64 `json:"current_score"`
+	ProposedScore float64 `json:"proposed_score"`
+	CurrentLabel  string  `json:"current_label"`
+	ProposedLabel string  `json:"proposed_label"`
+}
+
+// CompareConfigs recomputes the given articles' composites under current and proposed
+// without calling an LLM or writing anything, and summarizes the differences
+func (sm *ScoreManager) CompareConfigs(articleIDs []int64, current, proposed *CompositeScoreConfig) *ConfigDiff {
+	profiles := []AggregationProfile{{Name: "current", Config: current}, {Name: "proposed", Config: proposed}}
+	diff := &ConfigDiff{Articles: len(articleIDs), Flips: []ConfigLabelFlip{}}
+	var sumAbsChange float64
+	for _, id := range articleIDs {
+		comparison, err := sm.CompareProfiles(id, profiles)
+		if err != nil || comparison.Scores[0].Err != nil {
+			diff.Skipped++
+			continue
+		}
+		before, after := comparison.Scores[0], comparison.Scores[1]
+		if after.Err != nil {
+			diff.ProposedFailed++
+			continue
+		}
+		diff.Compared++
+		change := math.Abs(after.Score - before.Score)
+		sumAbsChange += change
+		if change > configChangeEpsilon {
+			diff.Changed++
+		}
+		diff.MaxAbsChange = math.Max(diff.MaxAbsChange, change)
+		if beforeLabel, afterLabel := models.BiasLabel(before.Score), models.BiasLabel(after.Score); beforeLabel != afterLabel {
+			diff.LabelFlips++
+			diff.Flips = append(diff.Flips, ConfigLabelFlip{
+				ArticleID:     id,
+				CurrentScore:  before.Score,
+				ProposedScore: after.Score,
+				CurrentLabel:  beforeLabel,
+				ProposedLabel: afterLabel,
+			})
+		}
+	}
+	if diff.Compared > 0 {
+		diff.MeanAbsChange = sumAbsChange / float64(diff.Compared)
+	}
+	return diff
+}
@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLLMModelsOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "composite_score_config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"formula": "average", "models": [
+		{"modelName": "left-model", "perspective": "left", "weight": 2.0, "url": "https://router.example/v1"},
+		{"modelName": "center-model", "perspective": "center", "weight": 1.0, "url": "https://router.example/v1"}
+	]}`), 0o600))
+
+	t.Run("NoOverride", func(t *testing.T) {
+		t.Setenv("LLM_MODELS", "")
+		cfg, err := loadConfigFromPath(path)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"left-model", "center-model"}, modelNames(cfg.Models))
+	})
+
+	t.Run("ReplacesModels", func(t *testing.T) {
+		t.Setenv("LLM_MODELS", " vendor/new-left:free , right=vendor/new-right, vendor/extra ")
+		cfg, err := loadConfigFromPath(path)
+		require.NoError(t, err)
+		assert.Equal(t, []ModelConfig{
+			{ModelName: "vendor/new-left:free", Perspective: "left", Weight: 2.0, URL: "https://router.example/v1"},
+			{ModelName: "vendor/new-right", Perspective: "right", Weight: 1.0, URL: "https://router.example/v1"},
+			{ModelName: "vendor/extra", Perspective: LabelCenter, Weight: 1.0, URL: "https://router.example/v1"},
+		}, cfg.Models)
+		assert.Equal(t, "average", cfg.Formula, "the rest of the file still applies")
+	})
+
+	t.Run("RejectsEmptyNames", func(t *testing.T) {
+		for _, value := range []string{"a,,b", "a, ", "left=", "=model"} {
+			t.Setenv("LLM_MODELS", value)
+			_, err := loadConfigFromPath(path)
+			assert.Error(t, err, value)
+		}
+	})
+}
+
+func TestCompositeScoreConfigValidate(t *testing.T) {
+	valid := func() *CompositeScoreConfig {
+		return &CompositeScoreConfig{
+			Formula: "weighted",
+			Models:  []ModelConfig{{ModelName: "m", Perspective: LabelCenter, Weight: 1}},
+			Weights: map[string]float64{LabelCenter: 1},
+		}
+	}
+	require.NoError(t, valid().Validate())
+
+	for name, change := range map[string]func(cfg *CompositeScoreConfig){
+		"NoModels":        func(cfg *CompositeScoreConfig) { cfg.Models = nil },
+		"NoModelName":     func(cfg *CompositeScoreConfig) { cfg.Models[0].ModelName = " " },
+		"NoPerspective":   func(cfg *CompositeScoreConfig) { cfg.Models[0].Perspective = "" },
+		"NegativeWeight":  func(cfg *CompositeScoreConfig) { cfg.Models[0].Weight = -1 },
+		"NegativeWeights": func(cfg *CompositeScoreConfig) { cfg.Weights[LabelCenter] = -0.5 },
+		"UnknownFormula":  func(cfg *CompositeScoreConfig) { cfg.Formula = "median" },
+		"ScoreRange":      func(cfg *CompositeScoreConfig) { cfg.MinScore, cfg.MaxScore = 1, -1 },
+		"NegativePrice": func(cfg *CompositeScoreConfig) {
+			cfg.ModelPrices = map[string]ModelPrice{"m": {PromptPerMillion: -1}}
+		},
+		"NegativeLatency": func(cfg *CompositeScoreConfig) { cfg.Models[0].MaxLatencyMs = -1 },
+		"ExcludeNoBudget": func(cfg *CompositeScoreConfig) { cfg.Models[0].ExcludeWhenSlow = true },
+		"ExtraMessages": func(cfg *CompositeScoreConfig) {
+			cfg.Models[0].ExtraParams = map[string]interface{}{"messages": "x"}
+		},
+		"ExtraNested": func(cfg *CompositeScoreConfig) {
+			cfg.Models[0].ExtraParams = map[string]interface{}{"provider": map[string]interface{}{"order": "a"}}
+		},
+	} {
+		cfg := valid()
+		change(cfg)
+		assert.Error(t, cfg.Validate(), name)
+	}
+	var empty *CompositeScoreConfig
+	assert.Error(t, empty.Validate())
+}
+
+func TestWriteConfigFileKeepsLineEndings(t *testing.T) {
+	cfg := &CompositeScoreConfig{
+		Formula: "weighted",
+		Models:  []ModelConfig{{ModelName: "m", Perspective: LabelCenter, Weight: 1}},
+	}
+	for name, existing := range map[string]string{"CRLF": "{\r\n}\r\n", "LF": "{\n}\n"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "composite_score_config.json")
+			require.NoError(t, os.WriteFile(path, []byte(existing), 0o600))
+			require.NoError(t, writeConfigFile(path, cfg))
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			content := string(data)
+			if name == "CRLF" {
+				assert.NotContains(t, strings.ReplaceAll(content, "\r\n", ""), "\n", "every line ends with CRLF")
+			} else {
+				assert.NotContains(t, content, "\r")
+			}
+
+			saved, err := readConfigFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, "m", saved.Models[0].ModelName)
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		})
+	}
+}
+
+func TestLLMClientSetConfigConcurrently(t *testing.T) {
+	client := &LLMClient{config: &CompositeScoreConfig{Models: []ModelConfig{{ModelName: "a", Perspective: LabelLeft}}}}
+	replacement := &CompositeScoreConfig{Models: []ModelConfig{{ModelName: "b", Perspective: LabelRight}}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.SetConfig(replacement)
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = client.modelBudget("a")
+		}()
+	}
+	wg.Wait()
+	assert.Same(t, replacement, client.GetConfig())
+}
@@ -0,0 +1,325 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/go-resty/resty/v2"
+)
+
+// EmbeddingPromptVariant labels ensemble sub-results produced by the embedding pipeline
+const EmbeddingPromptVariant = "embedding"
+
+// Embedding classifier types
+const (
+	EmbeddingClassifierAnchor = "anchor"
+	EmbeddingClassifierLinear = "linear"
+)
+
+// defaultEmbeddingConfidence is reported by classifiers without a configured confidence
+const defaultEmbeddingConfidence = 0.6
+
+// EmbeddingConfig enables an embedding-derived score as one more perspective in the
+// ensemble. It is disabled unless enabled is true.
+type EmbeddingConfig struct {
+	Enabled bool   `json:"enabled"`
+	Model   string `json:"model"`
+	// URL is the API base URL; the /embeddings path is appended. Defaults to LLM_BASE_URL.
+	URL string `json:"url,omitempty"`
+	// Weight scales the embedding score's confidence when aggregated with the chat
+	// models; defaults to 1
+	Weight     float64                   `json:"weight,omitempty"`
+	Classifier EmbeddingClassifierConfig `json:"classifier"`
+}
+
+// EmbeddingClassifierConfig selects and configures the classifier turning an
+// embedding into a bias score.
+//
+// The "anchor" classifier (default) embeds a left and a right anchor text and scores
+// an article by how much closer it is to one than the other. The "linear" classifier
+// applies tanh(weights·vector + bias), e.g. with weights from a logistic regression
+// trained offline on labelled articles.
+type EmbeddingClassifierConfig struct {
+	Type        string    `json:"type,omitempty"`
+	LeftAnchor  string    `json:"left_anchor,omitempty"`
+	RightAnchor string    `json:"right_anchor,omitempty"`
+	Weights     []float64 `json:"weights,omitempty"`
+	Bias        float64   `json:"bias,omitempty"`
+	Confidence  float64   `json:"confidence,omitempty"`
+}
+
+// Embedder turns text into an embedding vector
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float64, error)
+}
+
+// EmbeddingClassifier derives a bias score in [-1, 1] and a confidence from an embedding
+type EmbeddingClassifier interface {
+	Classify(ctx context.Context, vector []float64) (score float64, confidence float64, err error)
+}
+
+// LinearClassifier scores an embedding as tanh(weights·vector + bias)
+type LinearClassifier struct {
+	Weights    []float64
+	Bias       float64
+	Confidence float64
+}
+
+// Classify implements EmbeddingClassifier
+func (l *LinearClassifier) Classify(_ context.Context, vector []float64) (float64, float64, error) {
+	if len(vector) != len(l.Weights) {
+		return 0, 0, fmt.Errorf("linear classifier expects %d dimensions, got %d", len(l.Weights), len(vector))
+	}
+	var dot float64
+	for i, w := range l.Weights {
+		dot += w * vector[i]
+	}
+	return math.Tanh(dot + l.Bias), confidenceOrDefault(l.Confidence), nil
+}
+
+// AnchorClassifier scores an embedding by its cosine similarity to a right anchor
+// minus its similarity to a left anchor. The anchors are embedded on first use.
+type AnchorClassifier struct {
+	embedder    Embedder
+	leftAnchor  string
+	rightAnchor string
+	confidence  float64
+
+	mu          sync.Mutex
+	left, right []float64
+}
+
+// NewAnchorClassifier creates an anchor classifier embedding its anchors with embedder
+func NewAnchorClassifier(embedder Embedder, leftAnchor, rightAnchor string, confidence float64) *AnchorClassifier {
+	return &AnchorClassifier{embedder: embedder, leftAnchor: leftAnchor, rightAnchor: rightAnchor, confidence: confidence}
+}
+
+// Classify implements EmbeddingClassifier
+func (a *AnchorClassifier) Classify(ctx context.Context, vector []float64) (float64, float64, error) {
+	left, right, err := a.anchors(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	score := cosineSimilarity(vector, right) - cosineSimilarity(vector, left)
+	return math.Max(-1, math.Min(1, score)), confidenceOrDefault(a.confidence), nil
+}
+
+// anchors returns the anchor embeddings, fetching them until a fetch succeeds
+func (a *AnchorClassifier) anchors(ctx context.Context) ([]float64, []float64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.left != nil && a.right != nil {
+		return a.left, a.right, nil
+	}
+	left, err := a.embedder.Embed(ctx, a.leftAnchor)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to embed left anchor: %w", err)
+	}
+	right, err := a.embedder.Embed(ctx, a.rightAnchor)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to embed right anchor: %w", err)
+	}
+	a.left, a.right = left, right
+	return left, right, nil
+}
+
+// EmbeddingScore is the result of scoring content through the embedding pipeline.
+// VectorRef identifies the embedding (model and content hash) without storing it.
+type EmbeddingScore struct {
+	Score      float64 `json:"score"`
+	Confidence float64 `json:"confidence"`
+	Model      string  `json:"model"`
+	VectorRef  string  `json:"vector_ref"`
+	Dimensions int     `json:"dimensions"`
+}
+
+// EmbeddingLLMService scores content by embedding it through an OpenAI-compatible
+// embeddings endpoint and classifying the vector
+type EmbeddingLLMService struct {
+	client     *resty.Client
+	url        string
+	apiKey     string
+	model      string
+	classifier EmbeddingClassifier
+}
+
+// NewEmbeddingLLMService creates an embedding service. A nil classifier must be set
+// with SetClassifier before scoring.
+func NewEmbeddingLLMService(client *resty.Client, baseURL, apiKey, model string, classifier EmbeddingClassifier) *EmbeddingLLMService {
+	if baseURL == "" {
+		baseURL = "https://openrouter.ai/api/v1"
+	}
+	baseURL = strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/chat/completions")
+	if !strings.HasSuffix(baseURL, "/embeddings") {
+		baseURL += "/embeddings"
+	}
+	return &EmbeddingLLMService{
+		client:     client,
+		url:        baseURL,
+		apiKey:     apiKey,
+		model:      model,
+		classifier: classifier,
+	}
+}
+
+// SetClassifier replaces the classifier used to score embeddings
+func (s *EmbeddingLLMService) SetClassifier(classifier EmbeddingClassifier) {
+	s.classifier = classifier
+}
+
+// Model returns the embedding model name
+func (s *EmbeddingLLMService) Model() string {
+	return s.model
+}
+
+// Embed implements Embedder by calling the embeddings endpoint
+func (s *EmbeddingLLMService) Embed(ctx context.Context, text string) ([]float64, error) {
+	var result struct {
+		Data []struct {
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+	}
+	resp, err := s.client.R().
+		SetContext(ctx).
+		SetAuthToken(s.apiKey).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]interface{}{"model": s.model, "input": text}).
+		Post(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	if resp.IsError() {
+		return nil, formatHTTPError(resp)
+	}
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse embedding response: %w", err)
+	}
+	if len(result.Data) == 0 || len(result.Data[0].Embedding) == 0 {
+		return nil, errors.New("embedding response contained no vector")
+	}
+	return result.Data[0].Embedding, nil
+}
+
+// Score embeds the content and classifies the vector
+func (s *EmbeddingLLMService) Score(ctx context.Context, content string) (*EmbeddingScore, error) {
+	if s.classifier == nil {
+		return nil, errors.New("embedding classifier not configured")
+	}
+	vector, err := s.Embed(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	score, confidence, err := s.classifier.Classify(ctx, vector)
+	if err != nil {
+		return nil, fmt.Errorf("embedding classification failed: %w", err)
+	}
+	return &EmbeddingScore{
+		Score:      score,
+		Confidence: confidence,
+		Model:      s.model,
+		VectorRef:  embeddingVectorRef(s.model, content),
+		Dimensions: len(vector),
+	}, nil
+}
+
+// ScoreContent implements LLMService, scoring the article content; the prompt variant
+// is not used
+func (s *EmbeddingLLMService) ScoreContent(ctx context.Context, _ PromptVariant, art *db.Article) (float64, float64, error) {
+	result, err := s.Score(ctx, art.Content)
+	if err != nil {
+		return 0, 0, err
+	}
+	return result.Score, result.Confidence, nil
+}
+
+// newEmbeddingServiceFromConfig builds the embedding service when the config enables it
+func newEmbeddingServiceFromConfig(cfg *EmbeddingConfig, client *resty.Client, apiKey, baseURL string) (*EmbeddingLLMService, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+	if cfg.Model == "" {
+		return nil, errors.New("embedding is enabled but no model is configured")
+	}
+	if key := os.Getenv("EMBEDDING_API_KEY"); key != "" {
+		apiKey = key
+	}
+	if cfg.URL != "" {
+		baseURL = cfg.URL
+	}
+
+	service := NewEmbeddingLLMService(client, baseURL, apiKey, cfg.Model, nil)
+	switch cfg.Classifier.Type {
+	case EmbeddingClassifierLinear:
+		if len(cfg.Classifier.Weights) == 0 {
+			return nil, errors.New("linear embedding classifier requires weights")
+		}
+		service.SetClassifier(&LinearClassifier{
+			Weights:    cfg.Classifier.Weights,
+			Bias:       cfg.Classifier.Bias,
+			Confidence: cfg.Classifier.Confidence,
+		})
+	case "", EmbeddingClassifierAnchor:
+		if cfg.Classifier.LeftAnchor == "" || cfg.Classifier.RightAnchor == "" {
+			return nil, errors.New("anchor embedding classifier requires left_anchor and right_anchor")
+		}
+		service.SetClassifier(NewAnchorClassifier(service, cfg.Classifier.LeftAnchor, cfg.Classifier.RightAnchor, cfg.Classifier.Confidence))
+	default:
+		return nil, fmt.Errorf("unknown embedding classifier type %q", cfg.Classifier.Type)
+	}
+	log.Printf("[INFO] Embedding perspective enabled with model %s", cfg.Model)
+	return service, nil
+}
+
+// embeddingWeight returns the configured weight of the embedding perspective
+func (cfg *CompositeScoreConfig) embeddingWeight() float64 {
+	if cfg == nil || cfg.Embedding == nil || cfg.Embedding.Weight <= 0 {
+		return 1
+	}
+	return cfg.Embedding.Weight
+}
+
+// embeddingModelKey names the embedding perspective in ensemble results
+func embeddingModelKey(model string) string {
+	return EmbeddingPromptVariant + ":" + model
+}
+
+// embeddingVectorRef identifies an embedding by model and content hash, so it can be
+// recomputed or looked up without storing the vector
+func embeddingVectorRef(model, content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return fmt.Sprintf("%s@sha256:%x", model, sum[:16])
+}
+
+func confidenceOrDefault(c float64) float64 {
+	if c <= 0 {
+		return defaultEmbeddingConfidence
+	}
+	return c
+}
+
+// cosineSimilarity returns the cosine similarity of two dense vectors, or 0 when their
+// lengths differ or either is zero
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,124 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEmbeddingServer serves 2-dimensional embeddings: text mentioning "left" points
+// along the first axis, text mentioning "right" along the second
+func newEmbeddingServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+		var req struct {
+			Model string `json:"model"`
+			Input string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model == "" {
+			http.Error(w, `{"error":{"message":"bad request"}}`, http.StatusBadRequest)
+			return
+		}
+		vec := []float64{0.5, 0.5}
+		switch {
+		case strings.Contains(req.Input, "left"):
+			vec = []float64{1, 0}
+		case strings.Contains(req.Input, "right"):
+			vec = []float64{0, 1}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []map[string]interface{}{{"embedding": vec}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEmbeddingLLMServiceScore(t *testing.T) {
+	server := newEmbeddingServer(t)
+	svc := NewEmbeddingLLMService(resty.New(), server.URL+"/chat/completions", "key", "embed-small", nil)
+	svc.SetClassifier(NewAnchorClassifier(svc, "left anchor", "right anchor", 0.7))
+
+	result, err := svc.Score(context.Background(), "a right-leaning article")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, result.Score, 1e-9)
+	assert.Equal(t, 0.7, result.Confidence)
+	assert.Equal(t, 2, result.Dimensions)
+	assert.True(t, strings.HasPrefix(result.VectorRef, "embed-small@sha256:"), result.VectorRef)
+
+	score, confidence, err := svc.ScoreContent(context.Background(), PromptVariant{}, &db.Article{Content: "a neutral article"})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.0, score, 1e-9)
+	assert.Equal(t, 0.7, confidence)
+}
+
+func TestLinearClassifier(t *testing.T) {
+	c := &LinearClassifier{Weights: []float64{-1, 1}}
+	score, confidence, err := c.Classify(context.Background(), []float64{0, 2})
+	require.NoError(t, err)
+	assert.Greater(t, score, 0.9)
+	assert.Equal(t, defaultEmbeddingConfidence, confidence)
+
+	_, _, err = c.Classify(context.Background(), []float64{1})
+	assert.Error(t, err, "dimension mismatch")
+}
+
+func TestEmbeddingServiceFromConfig(t *testing.T) {
+	svc, err := newEmbeddingServiceFromConfig(nil, resty.New(), "key", "")
+	require.NoError(t, err)
+	assert.Nil(t, svc, "disabled by default")
+
+	svc, err = newEmbeddingServiceFromConfig(&EmbeddingConfig{Model: "embed-small"}, resty.New(), "key", "")
+	require.NoError(t, err)
+	assert.Nil(t, svc, "enabled must be set")
+
+	_, err = newEmbeddingServiceFromConfig(&EmbeddingConfig{Enabled: true, Model: "embed-small"}, resty.New(), "key", "")
+	assert.Error(t, err, "anchor classifier needs anchors")
+
+	_, err = newEmbeddingServiceFromConfig(&EmbeddingConfig{Enabled: true, Model: "m", Classifier: EmbeddingClassifierConfig{Type: "svm"}}, resty.New(), "key", "")
+	assert.Error(t, err)
+
+	svc, err = newEmbeddingServiceFromConfig(&EmbeddingConfig{
+		Enabled: true, Model: "embed-small", URL: "http://localhost:1234/v1/",
+		Classifier: EmbeddingClassifierConfig{Type: EmbeddingClassifierLinear, Weights: []float64{1}},
+	}, resty.New(), "key", "")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:1234/v1/embeddings", svc.url)
+}
+
+func TestEnsembleIncludesEmbeddingPerspective(t *testing.T) {
+	server := newEmbeddingServer(t)
+	embedding := NewEmbeddingLLMService(resty.New(), server.URL, "key", "embed-small", nil)
+	embedding.SetClassifier(NewAnchorClassifier(embedding, "left anchor", "right anchor", 0.9))
+
+	client := NewLLMClientWithService(nil, NewFixtureLLMService(Fixture{
+		Default: FixtureResponse{Score: 0, Confidence: 0.9},
+	}), &CompositeScoreConfig{
+		Models:    []ModelConfig{{ModelName: "center-model", Perspective: "center", Weight: 1}},
+		Embedding: &EmbeddingConfig{Enabled: true, Model: "embed-small"},
+	})
+	client.SetEmbeddingService(embedding)
+
+	result, err := client.EnsembleAnalyze(context.Background(), 1, "an article leaning right")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, result.Score, 1e-9, "embedding and chat model are weighted equally")
+
+	var meta map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.Metadata), &meta))
+	emb, ok := meta["embedding"].(map[string]interface{})
+	require.True(t, ok, "metadata records the embedding perspective")
+	assert.Contains(t, emb["vector_ref"], "embed-small@sha256:")
+	assert.NotContains(t, result.Metadata, `"embedding":[`, "the vector itself is not stored")
+	assert.Contains(t, meta["per_model_aggregation"], "embedding:embed-small")
+}
@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Startup model endpoint check modes, set with MODEL_ENDPOINT_CHECK
+const (
+	EndpointCheckOff  = "off"
+	EndpointCheckWarn = "warn"
+	EndpointCheckFail = "fail"
+)
+
+// EndpointCheckResult is the outcome of probing one configured model endpoint
+type EndpointCheckResult struct {
+	Model      string `json:"model"`
+	URL        string `json:"url"`
+	Reachable  bool   `json:"reachable"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+// EndpointCheckModeFromEnv reads MODEL_ENDPOINT_CHECK, falling back to off
+func EndpointCheckModeFromEnv() string {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv("MODEL_ENDPOINT_CHECK")))
+	switch v {
+	case "":
+		return EndpointCheckOff
+	case EndpointCheckOff, EndpointCheckWarn, EndpointCheckFail:
+		return v
+	default:
+		log.Printf("[WARN] Invalid MODEL_ENDPOINT_CHECK %q, using default %q", v, EndpointCheckOff)
+		return EndpointCheckOff
+	}
+}
+
+// CheckModelEndpoints sends each configured model a one-token completion request at
+// its configured URL (or the client's base URL when it has none), like the API key
+// validation does, and reports which endpoints answered. Responses rejecting the key
+// or rate limiting still count as reachable: they prove the URL and model are right.
+func (c *LLMClient) CheckModelEndpoints(ctx context.Context) ([]EndpointCheckResult, error) {
+	cfg := c.GetConfig()
+	httpService, ok := c.llmService.(*HTTPLLMService)
+	if !ok {
+		return nil, fmt.Errorf("endpoint check not supported for this LLM service type")
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("no composite score config loaded")
+	}
+
+	results := make([]EndpointCheckResult, 0, len(cfg.Models))
+	for _, m := range cfg.Models {
+		url := httpService.baseURL
+		if m.URL != "" {
+			url = chatCompletionsURL(m.URL)
+		}
+		results = append(results, checkModelEndpoint(ctx, httpService, m.ModelName, url))
+	}
+	return results, nil
+}
+
+// checkModelEndpoint probes one model and classifies the response
+func checkModelEndpoint(ctx context.Context, s *HTTPLLMService, model, url string) EndpointCheckResult {
+	result := EndpointCheckResult{Model: model, URL: url}
+	resp, err := s.client.R().
+		SetContext(ctx).
+		SetAuthToken(s.apiKey).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("HTTP-Referer", "https://github.com/alexandru-savinov/BalancedNewsGo").
+		SetHeader("X-Title", "NewsBalancer").
+		SetBody(map[string]interface{}{
+			"model":      model,
+			"messages":   userMessages("Test"),
+			"max_tokens": 1,
+		}).
+		Post(url)
+	if err != nil {
+		result.Reason = fmt.Sprintf("network error: %v", err)
+		return result
+	}
+
+	result.StatusCode = resp.StatusCode()
+	switch resp.StatusCode() {
+	case 200, 201:
+		result.Reachable, result.Reason = true, "ok"
+	case 401:
+		result.Reachable, result.Reason = true, "api key rejected"
+	case 402:
+		result.Reachable, result.Reason = true, "payment required"
+	case 429:
+		result.Reachable, result.Reason = true, "rate limited"
+	case 400:
+		result.Reason = "request rejected, check the model name"
+	case 404, 405:
+		result.Reason = "endpoint not found, check the URL"
+	case 500, 502, 503, 504:
+		result.Reason = "service unavailable"
+	default:
+		result.Reason = "unexpected response"
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckModelEndpoints(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/chat/completions" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body struct {
+			Model     string `json:"model"`
+			MaxTokens int    `json:"max_tokens"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, 1, body.MaxTokens)
+		if body.Model == "typo-model" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	defer ts.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	cfg := &CompositeScoreConfig{Models: []ModelConfig{
+		{ModelName: "good-model", URL: ts.URL + "/api/v1"},
+		{ModelName: "default-url-model"},
+		{ModelName: "typo-model", URL: ts.URL + "/api/v1"},
+		{ModelName: "good-model", URL: ts.URL + "/api/v2"},
+		{ModelName: "good-model", URL: closed.URL},
+	}}
+	client := NewLLMClientWithService(nil, NewHTTPLLMService(resty.New(), "key", "", ts.URL+"/api/v1"), cfg)
+
+	results, err := client.CheckModelEndpoints(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	assert.True(t, results[0].Reachable)
+	assert.True(t, results[1].Reachable, "models without a URL use the client's base URL")
+	assert.Equal(t, ts.URL+"/api/v1/chat/completions", results[1].URL)
+	assert.False(t, results[2].Reachable)
+	assert.Equal(t, http.StatusBadRequest, results[2].StatusCode)
+	assert.False(t, results[3].Reachable)
+	assert.Equal(t, "endpoint not found, check the URL", results[3].Reason)
+	assert.False(t, results[4].Reachable)
+	assert.Contains(t, results[4].Reason, "network error")
+
+	_, err = NewLLMClientWithService(nil, NewFixtureLLMService(Fixture{}), cfg).CheckModelEndpoints(context.Background())
+	assert.Error(t, err)
+}
+
+func TestEndpointCheckModeFromEnv(t *testing.T) {
+	t.Setenv("MODEL_ENDPOINT_CHECK", "")
+	assert.Equal(t, EndpointCheckOff, EndpointCheckModeFromEnv())
+	t.Setenv("MODEL_ENDPOINT_CHECK", "Fail")
+	assert.Equal(t, EndpointCheckFail, EndpointCheckModeFromEnv())
+	t.Setenv("MODEL_ENDPOINT_CHECK", "sometimes")
+	assert.Equal(t, EndpointCheckOff, EndpointCheckModeFromEnv())
+}
@@ -1,17 +1,22 @@
 package llm
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"log"
 	"math"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
 )
 
 // calculateRetryDelay calculates exponential backoff delay for retry attempts
@@ -34,15 +39,25 @@ func calculateRetryDelay(attempt int) time.Duration {
 }
 
 // callLLM queries a specific LLM with a prompt variant
-func (c *LLMClient) callLLM(articleID int64, modelName string, promptVariant PromptVariant, content string) (float64, string, float64, string, error) {
+func (c *LLMClient) callLLM(ctx context.Context, articleID int64, modelName string, promptVariant PromptVariant, content string) (score float64, explanation string, confidence float64, rawResp string, err error) {
+	ctx, span := tracing.Start(ctx, "llm.callLLM",
+		tracing.ArticleID(articleID), tracing.Model(modelName), attribute.String("prompt_variant", promptVariant.ID))
+	defer func() { tracing.End(span, err) }()
+	return c.callLLMWithRetries(ctx, articleID, modelName, promptVariant, content)
+}
+
+// callLLMWithRetries queries the model, retrying failed and zero-confidence responses
+func (c *LLMClient) callLLMWithRetries(ctx context.Context, articleID int64, modelName string, promptVariant PromptVariant, content string) (float64, string, float64, string, error) {
+	cfg := c.GetConfig()
 	maxRetries := 2
 	var lastErr error
 	var rawResp string
 	var score, confidence float64
 	var explanation string
 
+	data := c.promptData(articleID, promptVariant, content)
 	for attempt := 0; attempt <= maxRetries; attempt++ {
-		prompt := promptVariant.FormatPrompt(content)
+		prompt := promptVariant.Render(data)
 
 		// Compute prompt hash for logging
 		h := sha256.Sum256([]byte(prompt))
@@ -60,6 +75,23 @@ func (c *LLMClient) callLLM(articleID int64, modelName string, promptVariant Pro
 			return 0, "", 0, "", fmt.Errorf("LLM service not initialized")
 		}
 
+		// Services that return explanations directly need no response parsing
+		if explainer, ok := c.llmService.(ExplainingLLMService); ok {
+			pv := promptVariant
+			pv.Model = modelName
+			art := &db.Article{ID: articleID, Title: data.Title, Source: data.Source, PubDate: data.Published, Content: content}
+			score, confidence, explanation, rawResp, err = explainer.ScoreWithExplanation(ctx, pv, art)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if confidence == 0 {
+				lastErr = fmt.Errorf("invalid zero confidence")
+				continue
+			}
+			return score, explanation, confidence, rawResp, nil
+		}
+
 		// We need the raw response string and the parsed score/confidence/explanation.
 		// The current HTTPLLMService.AnalyzeWithPrompt returns a *db.LLMScore which contains metadata,
 		// but not necessarily the raw response string needed for parseLLMResponse here.
@@ -71,8 +103,8 @@ func (c *LLMClient) callLLM(articleID int64, modelName string, promptVariant Pro
 			return 0, "", 0, "", fmt.Errorf("LLM service is not *HTTPLLMService")
 		}
 
-		// Call the underlying API method
-		apiResp, err := httpService.callLLMAPIWithKey(modelName, prompt, httpService.apiKey) // Use renamed function and pass primary key
+		// Call the underlying API method, rotating past rejected keys
+		apiResp, err := httpService.callWithKeyRotation(modelName, promptVariant.ChatMessages(prompt), promptVariant.Sampling.resolved(), cfg.extraParams(modelName))
 		if err != nil {
 			// Enhanced error handling for SSE/streaming errors
 			if strings.Contains(err.Error(), "SSE") ||
@@ -259,16 +291,27 @@ func parseNestedLLMJSONResponse(rawResp string) (float64, string, float64, error
 	return score, explanation, confidence, nil
 }
 
-// EnsembleAnalyze performs multi-model, multi-prompt ensemble analysis
-func (c *LLMClient) EnsembleAnalyze(articleID int64, content string) (*db.LLMScore, error) {
+// EnsembleAnalyze performs multi-model, multi-prompt ensemble analysis.
+// Content longer than the configured limit is truncated, or, when long_content_mode
+// is "chunk", split into overlapping chunks that are scored separately and aggregated.
+// Cancelling ctx stops further model calls and retries and returns its error.
+func (c *LLMClient) EnsembleAnalyze(ctx context.Context, articleID int64, content string) (result *db.LLMScore, err error) {
+	ctx, span := tracing.Start(ctx, "llm.EnsembleAnalyze", tracing.ArticleID(articleID))
+	defer func() { tracing.End(span, err) }()
+	return c.ensembleAnalyze(ctx, articleID, content)
+}
+
+// ensembleAnalyze runs the ensemble analysis traced by EnsembleAnalyze
+func (c *LLMClient) ensembleAnalyze(ctx context.Context, articleID int64, content string) (*db.LLMScore, error) {
+	cfg := c.GetConfig()
 	// Use models defined in the loaded configuration
-	if c.config == nil || len(c.config.Models) == 0 {
+	if cfg == nil || len(cfg.Models) == 0 {
 		log.Printf("[Ensemble] ArticleID %d | Error: LLMClient config is nil or has no models defined.", articleID)
 		return nil, fmt.Errorf("LLMClient config is nil or has no models defined")
 	}
 	// Extract model names from the config
-	models := make([]string, 0, len(c.config.Models))
-	for _, modelCfg := range c.config.Models {
+	models := make([]string, 0, len(cfg.Models))
+	for _, modelCfg := range cfg.Models {
 		if modelCfg.ModelName == "" {
 			log.Printf("[Ensemble] Warning: Skipping model config with empty name (Perspective: %s)", modelCfg.Perspective)
 			continue
@@ -280,73 +323,99 @@ func (c *LLMClient) EnsembleAnalyze(articleID int64, content string) (*db.LLMSco
 		return nil, fmt.Errorf("no valid models found in configuration")
 	}
 	log.Printf("[Ensemble] ArticleID %d | Using %d models from config: %v", articleID, len(models), models)
-	promptVariants := loadPromptVariants()
 
-	type SubResult struct {
-		Model         string  `json:"model"`
-		PromptVariant string  `json:"prompt_variant"`
-		Score         float64 `json:"score"`
-		Explanation   string  `json:"explanation"`
-		Confidence    float64 `json:"confidence"`
-		RawResponse   string  `json:"raw_response"`
+	var score float64
+	var meta map[string]interface{}
+	var err error
+	if chunks := cfg.chunkContent(content); len(chunks) > 1 {
+		log.Printf("[Ensemble] ArticleID %d | Scoring %d chunks of up to %d characters", articleID, len(chunks), cfg.MaxContentLength())
+		score, _, meta, err = c.ensembleAnalyzeChunks(ctx, articleID, chunks, models)
+	} else {
+		var truncated bool
+		content, truncated = prepareContent(cfg, articleID, content)
+		score, _, meta, err = c.ensembleAnalyzeContent(ctx, articleID, content, models)
+		if err == nil && truncated {
+			meta["content_truncated"] = true
+		}
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	allSubResults := make([]SubResult, 0)
-	perModelResults := make(map[string][]SubResult)
-	perModelAgg := make(map[string]map[string]float64)
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		log.Printf("[Ensemble] ArticleID %d | Error marshaling metadata: %v", articleID, err)
+		// Proceed but log it clearly
+		metaBytes = []byte(fmt.Sprintf(`{"error": "failed to marshal metadata: %v"}`, err))
+	}
 
-	const minValid = 1
-	const maxAttempts = 6
-	const confidenceThreshold = 0.5
+	return &db.LLMScore{
+		Model:     "ensemble",
+		Score:     score,
+		Metadata:  string(metaBytes),
+		CreatedAt: time.Now(),
+	}, nil
+}
 
-	// Collect all valid responses that pass the threshold
-	allValidResponses := make([]SubResult, 0)
-
-	for _, model := range models {
-		validResponses := make([]SubResult, 0, minValid)
-		attempts := 0
-	outer:
-		for attempts < maxAttempts && len(validResponses) < minValid {
-			for _, pv := range promptVariants {
-				for retry := 0; retry < 2 && attempts < maxAttempts && len(validResponses) < minValid; retry++ {
-					// Add exponential backoff delay for retries (not on first attempt)
-					if retry > 0 {
-						delay := calculateRetryDelay(retry - 1)
-						log.Printf("[Ensemble] ArticleID %d | Model %s | Prompt %s | Retry %d: waiting %v before retry",
-							articleID, model, pv.ID, retry, delay)
-						time.Sleep(delay)
-					}
+// ensembleAnalyzeContent scores content in a single pass across all models and prompt
+// variants, returning the aggregated score, its variance-based confidence and metadata
+func (c *LLMClient) ensembleAnalyzeContent(ctx context.Context, articleID int64, content string, models []string) (float64, float64, map[string]interface{}, error) {
+	cfg := c.GetConfig()
+	promptVariants := loadPromptVariants()
+	for i := range promptVariants {
+		promptVariants[i] = cfg.withSampling(cfg.withExampleSampling(promptVariants[i]))
+	}
 
-					attempts++
-					score, explanation, confidence, rawResp, err := c.callLLM(articleID, model, pv, content)
-					if err != nil {
-						// Log error from callLLM but continue trying other prompts/models
-						log.Printf("[Ensemble] ArticleID %d | Model %s | Prompt %s | callLLM Error: %v", articleID, model, pv.ID, err)
-						continue // Don't count this as a valid response
-					}
-					sub := SubResult{
-						Model: model, PromptVariant: pv.ID,
-						Score: score, Explanation: explanation,
-						Confidence: confidence, RawResponse: rawResp,
-					}
-					allSubResults = append(allSubResults, sub)
-					if confidence >= confidenceThreshold {
-						validResponses = append(validResponses, sub)
-						// Also add to the overall list
-						allValidResponses = append(allValidResponses, sub)
-					}
-					if len(validResponses) >= minValid || attempts >= maxAttempts {
-						break outer // Break model loop once minValid is reached or maxAttempts
-					}
-				}
+	allSubResults := make([]ensembleSubResult, 0)
+	perModelResults := make(map[string][]ensembleSubResult)
+	perModelAgg := make(map[string]map[string]float64)
+
+	// Collect all valid responses that pass the threshold
+	allValidResponses := make([]ensembleSubResult, 0)
+
+	// Models are called concurrently, up to the configured limit; their results are
+	// gathered in model order so the metadata matches a sequential run
+	runs := make([]modelRun, len(models))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cfg.modelConcurrency())
+	for i, model := range models {
+		g.Go(func() error {
+			// Failed models are skipped, so only a cancellation stops the group
+			if err := gctx.Err(); err != nil {
+				return err
 			}
-		}
+			runs[i] = c.ensembleScoreModel(gctx, articleID, model, promptVariants, content)
+			return gctx.Err()
+		})
+	}
+	if err := g.Wait(); err != nil {
+		log.Printf("[Ensemble] ArticleID %d | Cancelled: %v", articleID, err)
+		return 0, 0, nil, err
+	}
+
+	answered := make([]bool, len(models))
+	for i := range models {
+		answered[i] = len(runs[i].valid) > 0
+	}
+	budgets, excluded := c.budgetExclusions(models, answered)
+
+	for i, model := range models {
+		run := runs[i]
+		allSubResults = append(allSubResults, run.subResults...)
+		validResponses := run.valid
 
 		if len(validResponses) == 0 {
-			log.Printf("[Ensemble] Model %s: no valid high-confidence responses after %d attempts. Skipping model.", model, attempts)
+			log.Printf("[Ensemble] Model %s: no valid high-confidence responses after %d attempts. Skipping model.", model, run.attempts)
 			// Don't fail the whole ensemble here, just skip this model's contribution
 			continue
 		}
+		if excluded[model] {
+			b := budgets[model]
+			log.Printf("[Ensemble] Model %s: average latency %dms over %d calls is above its %dms budget. Leaving it out of the aggregation.",
+				model, b.AverageLatencyMs, b.Samples, b.MaxLatencyMs)
+			continue
+		}
+		allValidResponses = append(allValidResponses, validResponses...)
 
 		var sum, weightedSum, sumWeights float64
 		for _, r := range validResponses {
@@ -377,14 +446,50 @@ func (c *LLMClient) EnsembleAnalyze(articleID int64, content string) (*db.LLMSco
 			model, len(validResponses), weightedMean, variance, sumWeights)
 	}
 
+	// The embedding perspective joins the aggregation as one more model
+	var embeddingMeta *EmbeddingScore
+	if c.embedding != nil {
+		result, err := c.embedding.Score(ctx, content)
+		if err != nil {
+			log.Printf("[Ensemble] ArticleID %d | Embedding perspective failed, continuing without it: %v", articleID, err)
+		} else {
+			name := embeddingModelKey(result.Model)
+			sub := ensembleSubResult{
+				Model: name, PromptVariant: EmbeddingPromptVariant,
+				Score: result.Score, Confidence: result.Confidence,
+				VectorRef: result.VectorRef,
+			}
+			allSubResults = append(allSubResults, sub)
+			allValidResponses = append(allValidResponses, sub)
+			perModelResults[name] = []ensembleSubResult{sub}
+			perModelAgg[name] = map[string]float64{
+				"mean":           result.Score,
+				"weighted_mean":  result.Score,
+				"variance":       0,
+				"count":          1,
+				"sum_confidence": result.Confidence * cfg.embeddingWeight(),
+			}
+			embeddingMeta = result
+			log.Printf("[Ensemble] Embedding %s: score=%.3f, confidence=%.3f", result.Model, result.Score, result.Confidence)
+		}
+	}
+
 	if len(perModelAgg) == 0 {
 		log.Printf("[Ensemble] ArticleID %d | No valid high-confidence LLM responses from any model after all attempts.", articleID)
-		return nil, fmt.Errorf("no valid high-confidence LLM responses from any model")
+		return 0, 0, nil, fmt.Errorf("no valid high-confidence LLM responses from any model")
+	}
+
+	// Aggregate across models that provided valid responses, in a fixed order so the
+	// floating point sums do not depend on map iteration
+	aggModels := make([]string, 0, len(perModelAgg))
+	for name := range perModelAgg {
+		aggModels = append(aggModels, name)
 	}
+	sort.Strings(aggModels)
 
-	// Aggregate across models that provided valid responses
 	var totalWeightedSum, totalSumWeights float64
-	for _, agg := range perModelAgg {
+	for _, name := range aggModels {
+		agg := perModelAgg[name]
 		// Use the sum of confidence from this model's valid responses as its weight
 		// This gives more weight to models that were more confident more often
 		weight := agg["sum_confidence"]
@@ -396,7 +501,8 @@ func (c *LLMClient) EnsembleAnalyze(articleID int64, content string) (*db.LLMSco
 
 	// Compute overall variance (average of per-model variances weighted by sum_confidence)
 	var totalVarianceSum float64
-	for _, agg := range perModelAgg {
+	for _, name := range aggModels {
+		agg := perModelAgg[name]
 		totalVarianceSum += agg["variance"] * agg["sum_confidence"]
 	}
 	// Avoid division by zero
@@ -406,38 +512,162 @@ func (c *LLMClient) EnsembleAnalyze(articleID int64, content string) (*db.LLMSco
 	// Assumes variance is somewhat normalized (e.g., scores -1 to 1 mean variance likely 0 to ~1)
 	ensembleConfidence := math.Max(0.0, 1.0-totalVariance)
 
-	// Determine uncertainty based on variance
-	uncertaintyFlag := totalVariance > 0.1 // Keep variance check for now
+	modelScores := make([]float64, 0, len(perModelAgg))
+	modelConfidences := make([]float64, 0, len(perModelAgg))
+	for _, name := range aggModels {
+		agg := perModelAgg[name]
+		modelScores = append(modelScores, agg["weighted_mean"])
+		if embeddingMeta != nil && name == embeddingModelKey(embeddingMeta.Model) {
+			modelConfidences = append(modelConfidences, embeddingMeta.Confidence)
+		} else {
+			modelConfidences = append(modelConfidences, agg["sum_confidence"]/math.Max(agg["count"], 1))
+		}
+	}
+	uncertainty := cfg.assessUncertainty(totalVariance, modelScores, modelConfidences)
 
 	log.Printf("[Ensemble] Final Score: %.4f | Variance-Based Confidence: %.4f | Variance: %.4f | Total Valid Sub-Results: %d",
 		finalScore, ensembleConfidence, totalVariance, len(allValidResponses))
 
+	finalAggregation := map[string]interface{}{
+		"weighted_mean": finalScore,
+		"variance":      totalVariance,
+		"total_weight":  totalSumWeights, // Include total weight used
+	}
+	uncertainty.addTo(finalAggregation)
+
 	meta := map[string]interface{}{
 		"confidence":            ensembleConfidence, // Store variance-based confidence
 		"all_sub_results":       allSubResults,
 		"per_model_results":     perModelResults,
 		"per_model_aggregation": perModelAgg,
-		"final_aggregation": map[string]interface{}{
-			"weighted_mean":    finalScore,
-			"variance":         totalVariance,
-			"uncertainty_flag": uncertaintyFlag,
-			"total_weight":     totalSumWeights, // Include total weight used
-		},
-		"timestamp": time.Now().Format(time.RFC3339),
+		"final_aggregation":     finalAggregation,
+		"timestamp":             time.Now().Format(time.RFC3339),
 	}
-	metaBytes, err := json.Marshal(meta)
-	if err != nil {
-		log.Printf("[Ensemble] ArticleID %d | Error marshaling metadata: %v", articleID, err)
-		// Proceed but log it clearly
-		metaBytes = []byte(fmt.Sprintf(`{"error": "failed to marshal metadata: %v"}`, err))
+	if embeddingMeta != nil {
+		meta["embedding"] = embeddingMeta
+	}
+	if len(budgets) > 0 {
+		meta["model_budgets"] = budgets
 	}
+	return finalScore, ensembleConfidence, meta, nil
+}
 
-	return &db.LLMScore{
-		Model:     "ensemble",
-		Score:     finalScore,
-		Metadata:  string(metaBytes),
-		CreatedAt: time.Now(),
-	}, nil
+// budgetExclusions returns the budget state of the models with a latency budget and
+// which of them to leave out of the aggregation, given which models answered validly.
+// A model over budget is only left out while enough other models answered to meet
+// min_models_for_composite; otherwise it is kept.
+func (c *LLMClient) budgetExclusions(models []string, answered []bool) (map[string]ModelBudget, map[string]bool) {
+	cfg := c.GetConfig()
+	budgets := make(map[string]ModelBudget)
+	excluded := make(map[string]bool)
+	valid := 0
+	for i := range models {
+		if answered[i] {
+			valid++
+		}
+	}
+	for i, model := range models {
+		budget, ok := c.modelBudget(model)
+		if !ok {
+			continue
+		}
+		if budget.Excluded && answered[i] {
+			if valid-1 >= cfg.minModelsForComposite() {
+				excluded[model] = true
+				valid--
+			} else {
+				log.Printf("[Ensemble] Model %s is over its latency budget but kept to meet min_models_for_composite", model)
+			}
+		}
+		budget.Excluded = excluded[model]
+		budgets[model] = budget
+	}
+	return budgets, excluded
+}
+
+// ensembleSubResult is one scoring call made during ensemble analysis
+type ensembleSubResult struct {
+	Model         string      `json:"model"`
+	ScoredBy      string      `json:"scored_by,omitempty"` // The model that answered, the primary or a fallback
+	PromptVariant string      `json:"prompt_variant"`
+	Score         float64     `json:"score"`
+	Explanation   string      `json:"explanation"`
+	Confidence    float64     `json:"confidence"`
+	RawResponse   string      `json:"raw_response"`
+	ExampleIDs    []int       `json:"example_ids,omitempty"`
+	VectorRef     string      `json:"vector_ref,omitempty"`
+	Usage         *TokenUsage `json:"usage,omitempty"`
+
+	Sampling SamplingParams `json:"sampling"`
+}
+
+// modelRun is what one model contributed to an ensemble analysis: every response it
+// gave and those confident enough to count
+type modelRun struct {
+	subResults []ensembleSubResult
+	valid      []ensembleSubResult
+	attempts   int
+}
+
+// ensembleScoreModel calls one model, and its fallbacks, across the prompt variants
+// until it gives a confident response, runs out of attempts or ctx is cancelled
+func (c *LLMClient) ensembleScoreModel(ctx context.Context, articleID int64, model string, promptVariants []PromptVariant, content string) modelRun {
+	cfg := c.GetConfig()
+	const minValid = 1
+	const maxAttempts = 6
+	const confidenceThreshold = 0.5
+
+	chain := cfg.FallbackChain(model)
+	run := modelRun{valid: make([]ensembleSubResult, 0, minValid)}
+outer:
+	for run.attempts < maxAttempts && len(run.valid) < minValid {
+		for _, pv := range promptVariants {
+			for retry := 0; retry < 2 && run.attempts < maxAttempts && len(run.valid) < minValid; retry++ {
+				// Add exponential backoff delay for retries (not on first attempt)
+				if retry > 0 {
+					delay := calculateRetryDelay(retry - 1)
+					log.Printf("[Ensemble] ArticleID %d | Model %s | Prompt %s | Retry %d: waiting %v before retry",
+						articleID, model, pv.ID, retry, delay)
+					select {
+					case <-ctx.Done():
+						break outer
+					case <-time.After(delay):
+					}
+				}
+				if ctx.Err() != nil {
+					break outer
+				}
+
+				run.attempts++
+				sampled, exampleIDs := pv.SampleExamples(articleID)
+				started := time.Now()
+				score, explanation, confidence, rawResp, scoredBy, err := c.callLLMWithFallback(ctx, articleID, chain, sampled, content)
+				c.latencies.record(model, time.Since(started))
+				if err != nil {
+					// Log error from callLLM but continue trying other prompts/models
+					log.Printf("[Ensemble] ArticleID %d | Model %s | Prompt %s | callLLM Error: %v", articleID, model, pv.ID, err)
+					continue // Don't count this as a valid response
+				}
+				sub := ensembleSubResult{
+					Model: model, ScoredBy: scoredBy, PromptVariant: pv.ID,
+					Score: score, Explanation: explanation,
+					Confidence: confidence, RawResponse: rawResp,
+					Sampling: pv.Sampling, Usage: parseTokenUsage(rawResp),
+				}
+				if pv.SampleSize > 0 {
+					sub.ExampleIDs = exampleIDs
+				}
+				run.subResults = append(run.subResults, sub)
+				if confidence >= confidenceThreshold {
+					run.valid = append(run.valid, sub)
+				}
+				if len(run.valid) >= minValid || run.attempts >= maxAttempts {
+					break outer // Stop once minValid is reached or maxAttempts
+				}
+			}
+		}
+	}
+	return run
 }
 
 const promptScaleFragment = "on a scale from -1.0 (strongly left) to 1.0 (strongly right). Respond with a JSON object containing 'score', "
@@ -0,0 +1,125 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelConcurrencyDefault(t *testing.T) {
+	assert.Equal(t, 1, DefaultModelConcurrency, "models are scored one after another unless configured")
+	var cfg *CompositeScoreConfig
+	assert.Equal(t, DefaultModelConcurrency, cfg.modelConcurrency())
+	assert.Equal(t, DefaultModelConcurrency, (&CompositeScoreConfig{}).modelConcurrency())
+	assert.Equal(t, DefaultModelConcurrency, (&CompositeScoreConfig{ModelConcurrency: -1}).modelConcurrency())
+	assert.Equal(t, 4, (&CompositeScoreConfig{ModelConcurrency: 4}).modelConcurrency())
+}
+
+func TestEnsembleAnalyzeConcurrentMatchesSequential(t *testing.T) {
+	// The first model answers slowest, so concurrent runs finish out of model order
+	fixture := Fixture{
+		Default: FixtureResponse{Score: 0.1, Confidence: 0.9, Explanation: "center"},
+		Rules: []FixtureRule{
+			{Model: "model-a", Responses: []FixtureResponse{{Score: -0.6, Confidence: 0.8, Explanation: "left", DelayMs: 30}}},
+			{Model: "model-b", Responses: []FixtureResponse{{Score: 0.4, Confidence: 0.7, Explanation: "right", DelayMs: 10}}},
+		},
+	}
+
+	analyze := func(concurrency int) (float64, map[string]interface{}) {
+		cfg := &CompositeScoreConfig{
+			Models: []ModelConfig{
+				{ModelName: "model-a", Perspective: "left", Weight: 1.0},
+				{ModelName: "model-b", Perspective: "right", Weight: 1.0},
+				{ModelName: "model-c", Perspective: "center", Weight: 1.0},
+			},
+			Formula:          "average",
+			MinScore:         -1.0,
+			MaxScore:         1.0,
+			HandleInvalid:    "default",
+			ConfidenceMethod: "count_valid",
+			ModelConcurrency: concurrency,
+		}
+		client := NewLLMClientWithService(nil, NewFixtureLLMService(fixture), cfg)
+		result, err := client.EnsembleAnalyze(context.Background(), 1, "Article body about policy")
+		require.NoError(t, err)
+		var meta map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Metadata), &meta))
+		delete(meta, "timestamp")
+		return result.Score, meta
+	}
+
+	sequentialScore, sequentialMeta := analyze(1)
+	concurrentScore, concurrentMeta := analyze(4)
+
+	assert.Equal(t, sequentialScore, concurrentScore)
+	assert.Equal(t, sequentialMeta, concurrentMeta)
+	assert.NotEmpty(t, concurrentMeta["all_sub_results"])
+}
+
+func TestEnsembleAnalyzeStopsWhenCancelled(t *testing.T) {
+	// Low confidence answers make every model retry with a backoff of seconds
+	svc := NewFixtureLLMService(Fixture{Default: FixtureResponse{Score: 0.1, Confidence: 0.2, Explanation: "unsure"}})
+	cfg := &CompositeScoreConfig{
+		Models: []ModelConfig{
+			{ModelName: "model-a", Perspective: "left", Weight: 1.0},
+			{ModelName: "model-b", Perspective: "right", Weight: 1.0},
+		},
+		Formula:          "average",
+		MinScore:         -1.0,
+		MaxScore:         1.0,
+		HandleInvalid:    "default",
+		ConfidenceMethod: "count_valid",
+	}
+	client := NewLLMClientWithService(nil, svc, cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := client.EnsembleAnalyze(ctx, 1, "Article body about policy")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), time.Second, "the retry backoff stops at the cancellation")
+
+	calls := svc.Calls()
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	_, err = client.EnsembleAnalyze(cancelled, 1, "Article body about policy")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, calls, svc.Calls(), "no model is called once cancelled")
+}
+
+func TestReanalyzeArticleScoresModelsConcurrently(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "concurrent.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('cnn', CURRENT_TIMESTAMP, 'https://example.com/c', 'title', 'content')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+
+	// Each model is looked up in the config file while scoring, so use its models
+	fileCfg, err := LoadCompositeScoreConfig()
+	require.NoError(t, err)
+	require.Greater(t, len(fileCfg.Models), 1)
+	cfg := *fileCfg
+	cfg.ModelConcurrency = len(cfg.Models)
+	const delay = 150 * time.Millisecond
+	svc := NewFixtureLLMService(Fixture{Default: FixtureResponse{
+		Score: 0.2, Confidence: 0.8, Explanation: "ok", DelayMs: int(delay.Milliseconds())}})
+	client := NewLLMClientWithService(dbConn, svc, &cfg)
+
+	started := time.Now()
+	require.NoError(t, client.ReanalyzeArticle(context.Background(), articleID, nil))
+	assert.Less(t, time.Since(started), time.Duration(len(cfg.Models))*delay, "models are not called one after another")
+	assert.Equal(t, len(cfg.Models), svc.Calls())
+
+	var stored []string
+	require.NoError(t, dbConn.Select(&stored, `SELECT model FROM llm_scores WHERE article_id = ? AND model != 'ensemble'`, articleID))
+	assert.ElementsMatch(t, modelNames(cfg.Models), stored)
+}
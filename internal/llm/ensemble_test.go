@@ -412,7 +412,7 @@ func TestEnsembleRetryLogicWithFailures(t *testing.T) {
 		config:     nil, // This will trigger early return
 	}
 
-	score, err := client.EnsembleAnalyze(123, "test content")
+	score, err := client.EnsembleAnalyze(context.Background(), 123, "test content")
 	assert.Error(t, err, "Should fail with nil config")
 	assert.Nil(t, score, "Score should be nil on failure")
 	assert.Contains(t, err.Error(), "config is nil", "Error should mention nil config")
@@ -423,7 +423,7 @@ func TestEnsembleRetryLogicWithFailures(t *testing.T) {
 	}
 
 	client.config = emptyConfig
-	score, err = client.EnsembleAnalyze(123, "test content")
+	score, err = client.EnsembleAnalyze(context.Background(), 123, "test content")
 	assert.Error(t, err, "Should fail with empty models")
 	assert.Nil(t, score, "Score should be nil on failure")
 	assert.Contains(t, err.Error(), "config is nil", "Error should mention config is nil")
@@ -7,6 +7,9 @@ var (
 	ErrLLMServiceUnavailable   = errors.New("LLM service unavailable")
 	ErrRateLimited             = ErrBothLLMKeysRateLimited // Alias for compatibility with old code
 	ErrAllScoresZeroConfidence = errors.New("all LLMs returned empty or zero-confidence responses")
+	// ErrInsufficientModels means fewer models returned a valid score than
+	// min_models_for_composite requires
+	ErrInsufficientModels = errors.New("too few models returned a valid score")
 )
 
 // ErrAllPerspectivesInvalid indicates that despite attempting analysis across
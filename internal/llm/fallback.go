@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// MaxFallbackDepth caps how many fallback models are tried after a model fails, so a
+// long or circular chain cannot multiply the calls made for one score
+const MaxFallbackDepth = 3
+
+// FallbackChain returns the models to try for model, in order: the model itself, its
+// fallback, then that model's fallback when it is configured as well, and so on. The
+// chain ends after MaxFallbackDepth fallbacks or at a model already in it.
+func (cfg *CompositeScoreConfig) FallbackChain(model string) []string {
+	chain := []string{model}
+	if cfg == nil {
+		return chain
+	}
+	seen := map[string]bool{model: true}
+	current := model
+	for len(chain) <= MaxFallbackDepth {
+		fallback := ""
+		for _, m := range cfg.Models {
+			if m.ModelName == current {
+				fallback = m.Fallback
+				break
+			}
+		}
+		if fallback == "" {
+			break
+		}
+		if seen[fallback] {
+			log.Printf("[WARN] Fallback chain for model %s loops back to %s; stopping there", model, fallback)
+			break
+		}
+		seen[fallback] = true
+		chain = append(chain, fallback)
+		current = fallback
+	}
+	return chain
+}
+
+// callLLMWithFallback is callLLM trying each model of chain in turn until one scores
+// the content. It also returns the model that produced the score.
+func (c *LLMClient) callLLMWithFallback(ctx context.Context, articleID int64, chain []string, promptVariant PromptVariant, content string) (score float64, explanation string, confidence float64, rawResp string, scoredBy string, err error) {
+	errs := make([]error, 0, len(chain))
+	for i, model := range chain {
+		if i > 0 {
+			log.Printf("[LLM] ArticleID %d | Model %s failed, trying fallback %s", articleID, chain[i-1], model)
+		}
+		score, explanation, confidence, rawResp, err = c.callLLM(ctx, articleID, model, promptVariant, content)
+		if err == nil {
+			return score, explanation, confidence, rawResp, model, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 1 {
+		return 0, "", 0, "", "", errs[0]
+	}
+	return 0, "", 0, "", "", fmt.Errorf("model %s and its fallbacks failed: %w", chain[0], errors.Join(errs...))
+}
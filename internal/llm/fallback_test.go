@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallbackChain(t *testing.T) {
+	cfg := &CompositeScoreConfig{Models: []ModelConfig{
+		{ModelName: "a", Fallback: "b"},
+		{ModelName: "b", Fallback: "c"},
+		{ModelName: "c", Fallback: "a"},
+		{ModelName: "d", Fallback: "e"},
+		{ModelName: "e"},
+		{ModelName: "f"},
+		{ModelName: "g", Fallback: "h"},
+		{ModelName: "h", Fallback: "i"},
+		{ModelName: "i", Fallback: "j"},
+		{ModelName: "j", Fallback: "k"},
+	}}
+
+	assert.Equal(t, []string{"f"}, cfg.FallbackChain("f"))
+	assert.Equal(t, []string{"d", "e"}, cfg.FallbackChain("d"))
+	assert.Equal(t, []string{"a", "b", "c"}, cfg.FallbackChain("a"), "loop stops at a model already tried")
+	assert.Equal(t, []string{"g", "h", "i", "j"}, cfg.FallbackChain("g"), "chain is capped at MaxFallbackDepth")
+	assert.Equal(t, []string{"unknown"}, cfg.FallbackChain("unknown"))
+
+	var empty *CompositeScoreConfig
+	assert.Equal(t, []string{"a"}, empty.FallbackChain("a"))
+}
+
+// modelFailingService fails for the models in failing and scores everything else
+type modelFailingService struct {
+	failing map[string]bool
+	calls   []string
+}
+
+func (s *modelFailingService) ScoreContent(_ context.Context, pv PromptVariant, _ *db.Article) (float64, float64, error) {
+	s.calls = append(s.calls, pv.Model)
+	if s.failing[pv.Model] {
+		return 0, 0, errors.New("model unavailable")
+	}
+	return 0.4, 0.8, nil
+}
+
+func TestScoreWithModelFallback(t *testing.T) {
+	cfg := &CompositeScoreConfig{Models: []ModelConfig{
+		{ModelName: "primary", Fallback: "backup"},
+		{ModelName: "backup", Fallback: "last"},
+		{ModelName: "last"},
+	}}
+	article := &db.Article{ID: 1, Content: "Body"}
+
+	t.Run("FallbackScores", func(t *testing.T) {
+		svc := &modelFailingService{failing: map[string]bool{"primary": true}}
+		client := &LLMClient{config: cfg, llmService: svc}
+		score, err := client.ScoreWithModel(article, "primary")
+		require.NoError(t, err)
+		assert.Equal(t, 0.4, score)
+		assert.Equal(t, []string{"primary", "backup"}, svc.calls)
+	})
+
+	t.Run("AllFail", func(t *testing.T) {
+		svc := &modelFailingService{failing: map[string]bool{"primary": true, "backup": true, "last": true}}
+		client := &LLMClient{config: cfg, llmService: svc}
+		_, err := client.ScoreWithModel(article, "primary")
+		require.Error(t, err)
+		assert.Equal(t, []string{"primary", "backup", "last"}, svc.calls)
+	})
+}
@@ -0,0 +1,131 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+)
+
+// ErrShuttingDown is the cancellation cause of reanalysis jobs stopped by Shutdown
+var ErrShuttingDown = errors.New("score manager is shutting down")
+
+// DefaultShutdownTimeout bounds how long Shutdown waits for running jobs unless
+// SCORING_SHUTDOWN_TIMEOUT overrides it
+const DefaultShutdownTimeout = 20 * time.Second
+
+// ShutdownTimeoutFromEnv reads SCORING_SHUTDOWN_TIMEOUT, falling back to the default
+func ShutdownTimeoutFromEnv() time.Duration {
+	v := os.Getenv("SCORING_SHUTDOWN_TIMEOUT")
+	if v == "" {
+		return DefaultShutdownTimeout
+	}
+	timeout, err := time.ParseDuration(v)
+	if err != nil || timeout < 0 {
+		log.Printf("[WARN] Invalid SCORING_SHUTDOWN_TIMEOUT %q, using default %v", v, DefaultShutdownTimeout)
+		return DefaultShutdownTimeout
+	}
+	return timeout
+}
+
+// ShuttingDown reports whether Shutdown has begun. Handlers should refuse new jobs
+// once it has; jobs started anyway are interrupted straight away.
+func (sm *ScoreManager) ShuttingDown() bool {
+	sm.jobsMu.Lock()
+	defer sm.jobsMu.Unlock()
+	return sm.closing
+}
+
+// Shutdown stops the manager accepting reanalysis jobs and waits for the jobs in
+// flight to finish until ctx ends. Jobs still waiting for a slot are interrupted at
+// once, as are jobs still scoring when ctx ends: they are cancelled, which discards
+// their partial scores, and their articles are set to pending_retry so they are
+// requeued when the server next starts. It returns the IDs of the interrupted articles.
+func (sm *ScoreManager) Shutdown(ctx context.Context) []int64 {
+	sm.jobsMu.Lock()
+	sm.closing = true
+	if sm.interrupted == nil {
+		sm.interrupted = make(map[int64]bool)
+	}
+	idle := make(chan struct{})
+	if len(sm.jobs) == 0 {
+		close(idle)
+	} else {
+		sm.idle = idle
+	}
+	sm.jobsMu.Unlock()
+
+	// Queued jobs would not get a slot before the deadline anyway
+	sm.slotsMu.Lock()
+	queued := make([]int64, 0, len(sm.queue))
+	for _, waiter := range sm.queue {
+		queued = append(queued, waiter.articleID)
+	}
+	sm.slotsMu.Unlock()
+	interrupted := sm.interruptJobs(queued)
+
+	select {
+	case <-idle:
+		return interrupted
+	case <-ctx.Done():
+	}
+
+	sm.jobsMu.Lock()
+	remaining := make([]int64, 0, len(sm.jobs))
+	for articleID := range sm.jobs {
+		remaining = append(remaining, articleID)
+	}
+	sm.jobsMu.Unlock()
+	return append(interrupted, sm.interruptJobs(remaining)...)
+}
+
+// interruptJobs cancels the jobs of the given articles with ErrShuttingDown and leaves
+// the articles in the pending_retry state, returning the articles that had a job
+func (sm *ScoreManager) interruptJobs(articleIDs []int64) []int64 {
+	var interrupted []int64
+	for _, articleID := range articleIDs {
+		sm.jobsMu.Lock()
+		job, ok := sm.jobs[articleID]
+		// Queued jobs interrupted earlier may not have unregistered yet
+		ok = ok && !sm.interrupted[articleID]
+		if ok {
+			sm.interrupted[articleID] = true
+		}
+		sm.jobsMu.Unlock()
+		if !ok {
+			continue
+		}
+		job.cancel(ErrShuttingDown)
+		interrupted = append(interrupted, articleID)
+
+		if sm.db != nil {
+			if err := db.UpdateArticleStatus(sm.db, articleID, models.ArticleStatusPendingRetry); err != nil {
+				log.Printf("[ERROR] ScoreManager: ArticleID %d: Failed to mark interrupted job for retry: %v", articleID, err)
+			}
+		}
+		sm.markInterrupted(articleID)
+	}
+	return interrupted
+}
+
+// wasInterrupted reports whether Shutdown stopped the article's job
+func (sm *ScoreManager) wasInterrupted(articleID int64) bool {
+	sm.jobsMu.Lock()
+	defer sm.jobsMu.Unlock()
+	return sm.interrupted[articleID]
+}
+
+// markInterrupted records the interrupted terminal state for an article's reanalysis
+func (sm *ScoreManager) markInterrupted(articleID int64) {
+	sm.SetProgress(articleID, &models.ProgressState{
+		Status:      ProgressStatusInterrupted,
+		Step:        "Interrupted",
+		Message:     "Reanalysis interrupted by a server shutdown; it will be retried",
+		Percent:     100,
+		LastUpdated: time.Now().Unix(),
+	})
+}
@@ -0,0 +1,98 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreManagerShutdownWithoutJobs(t *testing.T) {
+	sm := NewScoreManager(nil, nil, nil, nil)
+	assert.False(t, sm.ShuttingDown())
+	assert.Empty(t, sm.Shutdown(context.Background()), "an idle manager stops at once")
+	assert.True(t, sm.ShuttingDown())
+}
+
+func TestScoreManagerShutdown(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "shutdown.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+	for i := 1; i <= 3; i++ {
+		_, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, status)
+			VALUES ('bbc', CURRENT_TIMESTAMP, ?, 'Title', 'Body', 'pending')`, fmt.Sprintf("https://example.com/s/%d", i))
+		require.NoError(t, err)
+	}
+
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(dbConn, nil, nil, pm)
+	sm.SetMaxConcurrentJobs(2)
+
+	// Articles 1 and 2 are scoring; 3 waits for a slot
+	_, done1, err := sm.TryStartJob(context.Background(), 1)
+	require.NoError(t, err)
+	release1, err := sm.AcquireJobSlot(context.Background(), 1)
+	require.NoError(t, err)
+	ctx2, done2, err := sm.TryStartJob(context.Background(), 2)
+	require.NoError(t, err)
+	release2, err := sm.AcquireJobSlot(ctx2, 2)
+	require.NoError(t, err)
+	ctx3, done3, err := sm.TryStartJob(context.Background(), 3)
+	require.NoError(t, err)
+	queuedErr := make(chan error, 1)
+	go func() {
+		defer done3()
+		_, err := sm.AcquireJobSlot(ctx3, 3)
+		if err != nil {
+			sm.MarkCancelled(3)
+		}
+		queuedErr <- err
+	}()
+	require.Eventually(t, func() bool {
+		_, queued := sm.JobCounts()
+		return queued == 1
+	}, time.Second, 5*time.Millisecond)
+
+	// Article 1 finishes while shutdown waits; article 2 never does
+	go func() {
+		<-ctx3.Done()
+		release1()
+		done1()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	interrupted := sm.Shutdown(ctx)
+	assert.Equal(t, []int64{3, 2}, interrupted, "queued jobs are interrupted at once, running ones at the deadline")
+
+	assert.ErrorIs(t, <-queuedErr, context.Canceled)
+	assert.ErrorIs(t, context.Cause(ctx2), ErrShuttingDown)
+	release2()
+	done2()
+
+	for id, want := range map[int64]string{1: "pending", 2: models.ArticleStatusPendingRetry, 3: models.ArticleStatusPendingRetry} {
+		var status string
+		require.NoError(t, dbConn.Get(&status, `SELECT status FROM articles WHERE id = ?`, id))
+		assert.Equal(t, want, status, "article %d", id)
+	}
+	for _, id := range interrupted {
+		state := sm.GetProgress(id)
+		require.NotNil(t, state)
+		assert.Equal(t, ProgressStatusInterrupted, state.Status, "article %d", id)
+		assert.True(t, IsTerminalProgressStatus(state.Status))
+	}
+
+	// Jobs started after shutdown began never run
+	lateCtx, lateDone, err := sm.TryStartJob(context.Background(), 4)
+	require.NoError(t, err)
+	defer lateDone()
+	assert.ErrorIs(t, context.Cause(lateCtx), ErrShuttingDown)
+	sm.MarkCancelled(4)
+	assert.Equal(t, ProgressStatusInterrupted, sm.GetProgress(4).Status)
+}
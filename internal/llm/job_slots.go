@@ -0,0 +1,154 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/metrics"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+)
+
+// DefaultMaxConcurrentJobs caps simultaneous reanalysis jobs unless
+// REANALYSIS_MAX_CONCURRENT overrides it; 0 removes the cap
+const DefaultMaxConcurrentJobs = 4
+
+// ProgressStatusQueued marks a reanalysis waiting for a free job slot
+const ProgressStatusQueued = "Queued"
+
+// jobWaiter is a reanalysis waiting in the job queue
+type jobWaiter struct {
+	articleID int64
+	ready     chan struct{} // closed when the waiter is handed a slot
+	granted   bool
+}
+
+// MaxConcurrentJobsFromEnv reads REANALYSIS_MAX_CONCURRENT, falling back to the default
+func MaxConcurrentJobsFromEnv() int {
+	v := os.Getenv("REANALYSIS_MAX_CONCURRENT")
+	if v == "" {
+		return DefaultMaxConcurrentJobs
+	}
+	parsed, err := strconv.Atoi(v)
+	if err != nil || parsed < 0 {
+		log.Printf("[WARN] Invalid REANALYSIS_MAX_CONCURRENT %q, using default %d", v, DefaultMaxConcurrentJobs)
+		return DefaultMaxConcurrentJobs
+	}
+	return parsed
+}
+
+// SetMaxConcurrentJobs caps how many reanalysis jobs may score at once; 0 means no cap.
+// Raising the cap admits queued jobs straight away.
+func (sm *ScoreManager) SetMaxConcurrentJobs(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	sm.slotsMu.Lock()
+	defer sm.slotsMu.Unlock()
+	sm.maxConcurrent = limit
+	sm.admitWaiting()
+	sm.publishQueueLocked()
+}
+
+// JobCounts returns the number of reanalysis jobs scoring and waiting for a slot
+func (sm *ScoreManager) JobCounts() (active, queued int) {
+	sm.slotsMu.Lock()
+	defer sm.slotsMu.Unlock()
+	return sm.active, len(sm.queue)
+}
+
+// AcquireJobSlot blocks until the article's reanalysis may start scoring and returns
+// the function that frees the slot again. While it waits the article's progress shows
+// its position in the queue. It fails only when ctx ends first.
+func (sm *ScoreManager) AcquireJobSlot(ctx context.Context, articleID int64) (func(), error) {
+	sm.slotsMu.Lock()
+	if len(sm.queue) == 0 && sm.hasFreeSlot() {
+		sm.active++
+		sm.publishQueueLocked()
+		sm.slotsMu.Unlock()
+		return sm.slotReleaser(), nil
+	}
+	waiter := &jobWaiter{articleID: articleID, ready: make(chan struct{})}
+	sm.queue = append(sm.queue, waiter)
+	sm.publishQueueLocked()
+	sm.slotsMu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return sm.slotReleaser(), nil
+	case <-ctx.Done():
+	}
+
+	sm.slotsMu.Lock()
+	defer sm.slotsMu.Unlock()
+	if waiter.granted {
+		// The slot arrived together with the cancellation; pass it on
+		sm.active--
+		sm.admitWaiting()
+	} else {
+		for i, w := range sm.queue {
+			if w == waiter {
+				sm.queue = append(sm.queue[:i], sm.queue[i+1:]...)
+				break
+			}
+		}
+	}
+	sm.publishQueueLocked()
+	return nil, ctx.Err()
+}
+
+// slotReleaser returns the function that frees a held slot, handing it to the
+// longest waiting job. Calls after the first are no-ops.
+func (sm *ScoreManager) slotReleaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			sm.slotsMu.Lock()
+			defer sm.slotsMu.Unlock()
+			sm.active--
+			sm.admitWaiting()
+			sm.publishQueueLocked()
+		})
+	}
+}
+
+// admitWaiting starts queued jobs while slots are free. Callers must hold slotsMu.
+func (sm *ScoreManager) admitWaiting() {
+	for len(sm.queue) > 0 && sm.hasFreeSlot() {
+		sm.admitNext()
+	}
+}
+
+// admitNext moves the head of the queue into a slot. Callers must hold slotsMu.
+func (sm *ScoreManager) admitNext() {
+	waiter := sm.queue[0]
+	sm.queue = sm.queue[1:]
+	sm.active++
+	waiter.granted = true
+	close(waiter.ready)
+}
+
+// hasFreeSlot reports whether another job may start. Callers must hold slotsMu.
+func (sm *ScoreManager) hasFreeSlot() bool {
+	return sm.maxConcurrent <= 0 || sm.active < sm.maxConcurrent
+}
+
+// publishQueueLocked refreshes the job gauges and the queue position shown in each
+// waiting job's progress. Callers must hold slotsMu.
+func (sm *ScoreManager) publishQueueLocked() {
+	metrics.SetReanalysisJobs(sm.active, len(sm.queue))
+	now := time.Now().Unix()
+	for i, waiter := range sm.queue {
+		sm.SetProgress(waiter.articleID, &models.ProgressState{
+			Status:        ProgressStatusQueued,
+			Step:          "Waiting",
+			Message:       fmt.Sprintf("Waiting for a free analysis slot (position %d of %d)", i+1, len(sm.queue)),
+			QueuePosition: i + 1,
+			LastUpdated:   now,
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package llm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireJobSlotQueuesOverLimit(t *testing.T) {
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(nil, nil, nil, pm)
+	sm.SetMaxConcurrentJobs(1)
+
+	releaseFirst, err := sm.AcquireJobSlot(context.Background(), 1)
+	require.NoError(t, err)
+
+	acquired := make(chan int64, 2)
+	for _, id := range []int64{2, 3} {
+		go func(id int64) {
+			release, err := sm.AcquireJobSlot(context.Background(), id)
+			if assert.NoError(t, err) {
+				acquired <- id
+				release()
+			}
+		}(id)
+		require.Eventually(t, func() bool {
+			state := sm.GetProgress(id)
+			return state != nil && state.Status == ProgressStatusQueued
+		}, time.Second, 5*time.Millisecond)
+	}
+
+	active, queued := sm.JobCounts()
+	assert.Equal(t, 1, active)
+	assert.Equal(t, 2, queued)
+	assert.Equal(t, 1, sm.GetProgress(2).QueuePosition)
+	assert.Equal(t, 2, sm.GetProgress(3).QueuePosition)
+	assert.Contains(t, sm.GetProgress(3).Message, "position 2 of 2")
+
+	releaseFirst()
+	releaseFirst() // releasing twice must not free a second slot
+	assert.Equal(t, int64(2), <-acquired, "jobs start in arrival order")
+	assert.Equal(t, int64(3), <-acquired)
+
+	require.Eventually(t, func() bool {
+		active, queued := sm.JobCounts()
+		return active == 0 && queued == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestAcquireJobSlotCancelledWhileQueued(t *testing.T) {
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(nil, nil, nil, pm)
+	sm.SetMaxConcurrentJobs(1)
+
+	release, err := sm.AcquireJobSlot(context.Background(), 1)
+	require.NoError(t, err)
+	defer release()
+
+	ctx, done, err := sm.TryStartJob(context.Background(), 2)
+	require.NoError(t, err)
+	defer done()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := sm.AcquireJobSlot(ctx, 2)
+		errCh <- err
+	}()
+	require.Eventually(t, func() bool {
+		_, queued := sm.JobCounts()
+		return queued == 1
+	}, time.Second, 5*time.Millisecond)
+
+	assert.True(t, sm.CancelJob(2))
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+	active, queued := sm.JobCounts()
+	assert.Equal(t, 1, active)
+	assert.Equal(t, 0, queued, "a cancelled job leaves the queue")
+}
+
+func TestSetMaxConcurrentJobsAdmitsWaiting(t *testing.T) {
+	sm := NewScoreManager(nil, nil, nil, nil)
+	sm.SetMaxConcurrentJobs(1)
+
+	release, err := sm.AcquireJobSlot(context.Background(), 1)
+	require.NoError(t, err)
+	defer release()
+
+	admitted := make(chan struct{})
+	go func() {
+		if _, err := sm.AcquireJobSlot(context.Background(), 2); err == nil {
+			close(admitted)
+		}
+	}()
+	require.Eventually(t, func() bool {
+		_, queued := sm.JobCounts()
+		return queued == 1
+	}, time.Second, 5*time.Millisecond)
+
+	sm.SetMaxConcurrentJobs(0)
+	select {
+	case <-admitted:
+	case <-time.After(time.Second):
+		t.Fatal("removing the cap should admit the queued job")
+	}
+}
+
+func TestMaxConcurrentJobsFromEnv(t *testing.T) {
+	t.Setenv("REANALYSIS_MAX_CONCURRENT", "")
+	assert.Equal(t, DefaultMaxConcurrentJobs, MaxConcurrentJobsFromEnv())
+	t.Setenv("REANALYSIS_MAX_CONCURRENT", "0")
+	assert.Equal(t, 0, MaxConcurrentJobsFromEnv())
+	t.Setenv("REANALYSIS_MAX_CONCURRENT", "-2")
+	assert.Equal(t, DefaultMaxConcurrentJobs, MaxConcurrentJobsFromEnv())
+}
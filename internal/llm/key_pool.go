@@ -0,0 +1,152 @@
+package llm
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultKeyCooldown is how long a key is skipped after an auth, credits or rate limit failure
+const defaultKeyCooldown = 60 * time.Second
+
+// KeyStats reports the health of a single API key
+type KeyStats struct {
+	Key            string     `json:"key"` // masked
+	Healthy        bool       `json:"healthy"`
+	UnhealthyUntil *time.Time `json:"unhealthy_until,omitempty"`
+	LastStatus     int        `json:"last_status,omitempty"`
+	Requests       int        `json:"requests"`
+	Failures       int        `json:"failures"`
+}
+
+// keyState tracks usage and health of one key
+type keyState struct {
+	key            string
+	unhealthyUntil time.Time
+	lastStatus     int
+	requests       int
+	failures       int
+}
+
+// keyPool hands out API keys round-robin, skipping keys that are cooling down
+type keyPool struct {
+	keys     []*keyState
+	next     int
+	cooldown time.Duration
+	mu       sync.Mutex
+	now      func() time.Time
+}
+
+// newKeyPool creates a pool from the given keys, ignoring empty and duplicate entries
+func newKeyPool(keys []string, cooldown time.Duration) *keyPool {
+	p := &keyPool{cooldown: cooldown, now: time.Now}
+	seen := make(map[string]bool)
+	for _, k := range keys {
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		p.keys = append(p.keys, &keyState{key: k})
+	}
+	return p
+}
+
+// size returns the number of keys in the pool
+func (p *keyPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.keys)
+}
+
+// pick returns the next healthy key in round-robin order. When every key is
+// cooling down it returns the one that recovers first rather than failing outright.
+func (p *keyPool) pick() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.keys) == 0 {
+		return ""
+	}
+
+	now := p.now()
+	for i := 0; i < len(p.keys); i++ {
+		idx := (p.next + i) % len(p.keys)
+		if !now.Before(p.keys[idx].unhealthyUntil) {
+			p.next = idx + 1
+			p.keys[idx].requests++
+			return p.keys[idx].key
+		}
+	}
+
+	soonest := p.keys[0]
+	for _, k := range p.keys[1:] {
+		if k.unhealthyUntil.Before(soonest.unhealthyUntil) {
+			soonest = k
+		}
+	}
+	soonest.requests++
+	return soonest.key
+}
+
+// reportSuccess marks a key healthy again
+func (p *keyPool) reportSuccess(key string, statusCode int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k := p.find(key); k != nil {
+		k.lastStatus = statusCode
+		k.unhealthyUntil = time.Time{}
+	}
+}
+
+// reportFailure puts a key on cooldown. A positive retryAfter overrides the default cooldown.
+func (p *keyPool) reportFailure(key string, statusCode int, retryAfter time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	k := p.find(key)
+	if k == nil {
+		return
+	}
+	cooldown := p.cooldown
+	if retryAfter > 0 {
+		cooldown = retryAfter
+	}
+	k.lastStatus = statusCode
+	k.failures++
+	k.unhealthyUntil = p.now().Add(cooldown)
+}
+
+// stats returns a snapshot of every key's health with the keys masked
+func (p *keyPool) stats() []KeyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	out := make([]KeyStats, 0, len(p.keys))
+	for _, k := range p.keys {
+		s := KeyStats{
+			Key:        maskKey(k.key),
+			Healthy:    !now.Before(k.unhealthyUntil),
+			LastStatus: k.lastStatus,
+			Requests:   k.requests,
+			Failures:   k.failures,
+		}
+		if !s.Healthy {
+			until := k.unhealthyUntil
+			s.UnhealthyUntil = &until
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// find returns the state for key. Callers must hold the lock.
+func (p *keyPool) find(key string) *keyState {
+	for _, k := range p.keys {
+		if k.key == key {
+			return k
+		}
+	}
+	return nil
+}
+
+// isKeyFailureStatus reports whether a status code means the key itself is unusable for now
+func isKeyFailureStatus(statusCode int) bool {
+	return statusCode == 401 || statusCode == 402 || statusCode == 429
+}
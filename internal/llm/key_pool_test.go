@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyPoolRoundRobin(t *testing.T) {
+	p := newKeyPool([]string{"key-a", "", "key-b", "key-a", "key-c"}, time.Minute)
+
+	assert.Equal(t, 3, p.size(), "empty and duplicate keys are dropped")
+	assert.Equal(t, "key-a", p.pick())
+	assert.Equal(t, "key-b", p.pick())
+	assert.Equal(t, "key-c", p.pick())
+	assert.Equal(t, "key-a", p.pick())
+}
+
+func TestKeyPoolCooldown(t *testing.T) {
+	p := newKeyPool([]string{"key-a", "key-b"}, time.Minute)
+	now := time.Unix(1_700_000_000, 0)
+	p.now = func() time.Time { return now }
+
+	p.reportFailure("key-a", 429, 0)
+	assert.Equal(t, "key-b", p.pick())
+	assert.Equal(t, "key-b", p.pick(), "unhealthy key is skipped")
+
+	// With every key cooling down the one recovering first is used
+	p.reportFailure("key-b", 401, 2*time.Minute)
+	assert.Equal(t, "key-a", p.pick())
+
+	now = now.Add(61 * time.Second)
+	stats := p.stats()
+	assert.True(t, stats[0].Healthy, "key-a recovers after the cooldown")
+	assert.False(t, stats[1].Healthy)
+	assert.Equal(t, 401, stats[1].LastStatus)
+	assert.Equal(t, 1, stats[1].Failures)
+	assert.NotNil(t, stats[1].UnhealthyUntil)
+	assert.NotContains(t, stats[1].Key, "key-b", "keys are masked")
+
+	p.reportSuccess("key-b", 200)
+	assert.True(t, p.stats()[1].Healthy)
+}
+
+func TestHTTPLLMServiceRotatesAcrossKeys(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		mu.Lock()
+		calls[key]++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch key {
+		case "key-credits":
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"error":{"message":"Insufficient credits"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"{\"score\":0.2,\"confidence\":0.7}"}}]}`))
+		}
+	}))
+	defer server.Close()
+
+	service := NewHTTPLLMServiceWithKeys(resty.New(), []string{"key-a", "key-credits", "key-c"}, server.URL)
+	pv := PromptVariant{ID: "default", Template: testPromptTemplate, Model: testModelName}
+	article := &db.Article{ID: 1, Content: testArticleContent}
+
+	for i := 0; i < 6; i++ {
+		score, _, err := service.ScoreContent(context.Background(), pv, article)
+		assert.NoError(t, err)
+		assert.Equal(t, 0.2, score)
+	}
+
+	assert.Equal(t, 1, calls["key-credits"], "key on cooldown after a 402 is not retried")
+	assert.Equal(t, 3, calls["key-a"])
+	assert.Equal(t, 3, calls["key-c"])
+
+	stats := service.KeyStats()
+	assert.Len(t, stats, 3)
+	assert.False(t, stats[1].Healthy)
+	assert.Equal(t, 402, stats[1].LastStatus)
+}
+
+func TestEnsembleCallsRotateAcrossKeys(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		mu.Lock()
+		calls[key]++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if key == "key-limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"message":"Rate limit exceeded"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"{\"score\":0.4,\"explanation\":\"ok\",\"confidence\":0.9}"}}]}`))
+	}))
+	defer server.Close()
+
+	service := NewHTTPLLMServiceWithKeys(resty.New(), []string{"key-limited", "key-b"}, server.URL)
+	client := &LLMClient{llmService: service, config: &CompositeScoreConfig{}}
+	pv := PromptVariant{ID: "default", Template: testPromptTemplate, Model: testModelName}
+
+	score, _, confidence, _, err := client.callLLMWithRetries(context.Background(), 1, testModelName, pv, testArticleContent)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.4, score)
+	assert.Equal(t, 0.9, confidence)
+	assert.Equal(t, 1, calls["key-limited"])
+	assert.Equal(t, 1, calls["key-b"], "the rate limited key is skipped within the same call")
+	assert.False(t, service.KeyStats()[0].Healthy)
+}
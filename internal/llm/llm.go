@@ -12,6 +12,8 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/apperrors"
@@ -19,6 +21,7 @@ import (
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
 	"github.com/go-resty/resty/v2"
 	"github.com/jmoiron/sqlx"
+	"golang.org/x/sync/errgroup"
 )
 
 // ErrInvalidLLMResponse represents an invalid response from LLM service
@@ -40,17 +43,29 @@ func maskKey(key string) string {
 
 // PromptVariant defines a prompt template with few-shot examples
 type PromptVariant struct {
-	ID       string
-	Template string
-	Examples []string
-	Model    string // Model name for this variant
-	URL      string // API endpoint URL
+	ID         string
+	Template   string
+	Examples   []string
+	Model      string // Model name for this variant
+	URL        string // API endpoint URL
+	SampleSize int    // Examples drawn per call; zero includes every example
+	Seed       *int64 // Makes example sampling deterministic per article when set
+
+	// SystemPrompt, when set, is sent as a separate system message ahead of the
+	// rendered template; otherwise everything goes in a single user message
+	SystemPrompt string
+
+	// Sampling overrides the sampling parameters from the composite score config
+	Sampling SamplingParams
+
+	// ExtraParams are the model's extra_params, added to the request after Sampling
+	ExtraParams map[string]interface{}
 }
 
-// GeneratePrompt formats the prompt template with content
+// FormatPrompt formats the prompt template with content only; see Render for
+// the other template variables
 func (pv *PromptVariant) FormatPrompt(content string) string {
-	examplesText := strings.Join(pv.Examples, "\n")
-	return fmt.Sprintf("%s\n%s\nArticle:\n%s", pv.Template, examplesText, content)
+	return pv.Render(PromptData{Content: content})
 }
 
 // DefaultPromptVariant is the standard prompt template for analyzing articles
@@ -141,7 +156,10 @@ type LLMClient struct {
 	cache      *Cache
 	db         *sqlx.DB
 	llmService LLMService
-	config     *CompositeScoreConfig
+	config     *CompositeScoreConfig // guarded by configMu; read it with GetConfig
+	configMu   sync.RWMutex
+	embedding  *EmbeddingLLMService // nil unless the embedding perspective is enabled
+	latencies  *modelLatencies      // rolling latency per model, for latency budgets
 }
 
 // ArticleAnalysis represents the full analysis results for an article
@@ -176,18 +194,83 @@ func (c *LLMClient) GetHTTPLLMTimeout() time.Duration {
 	return defaultLLMTimeout
 }
 
+// KeyStats returns per-key health for the HTTP LLM service, or nil for other services
+func (c *LLMClient) KeyStats() []KeyStats {
+	httpService, ok := c.llmService.(*HTTPLLMService)
+	if !ok || httpService == nil {
+		return nil
+	}
+	return httpService.KeyStats()
+}
+
+// BreakerStats returns the provider circuit breaker state for the HTTP LLM service, or
+// nil for other services and when the breaker is disabled
+func (c *LLMClient) BreakerStats() *BreakerStats {
+	httpService, ok := c.llmService.(*HTTPLLMService)
+	if !ok || httpService == nil {
+		return nil
+	}
+	return httpService.BreakerStats()
+}
+
+// ProviderUnavailableUntil reports whether the provider circuit breaker is open and, if
+// so, when it will let a call through again. Batch scoring waits this out rather than
+// failing every article.
+func (c *LLMClient) ProviderUnavailableUntil() (time.Time, bool) {
+	httpService, ok := c.llmService.(*HTTPLLMService)
+	if !ok || httpService == nil {
+		return time.Time{}, false
+	}
+	return httpService.breaker.openedUntil()
+}
+
+// CacheStats returns the hit, miss and eviction counters of the LLM result cache
+func (c *LLMClient) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{Models: map[string]CacheCounts{}}
+	}
+	return c.cache.Stats()
+}
+
+// splitKeyList parses a comma-separated list of API keys, dropping blanks
+func splitKeyList(value string) []string {
+	var keys []string
+	for _, k := range strings.Split(value, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
 func NewLLMClient(dbConn *sqlx.DB) (*LLMClient, error) {
+	// Offline mode: answer from a fixture file instead of calling a provider
+	if fixturePath := os.Getenv("LLM_FIXTURE_FILE"); fixturePath != "" {
+		service, err := LoadFixtureLLMService(fixturePath)
+		if err != nil {
+			return nil, err
+		}
+		config, err := LoadCompositeScoreConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load composite score config: %w", err)
+		}
+		log.Printf("[INFO][NewLLMClient] Using LLM fixture %s; no provider requests will be made", fixturePath)
+		return NewLLMClientWithService(dbConn, service, config), nil
+	}
+
 	cache := NewCache()
 
 	// Get OpenRouter configuration
 	primaryKey := os.Getenv("LLM_API_KEY")
 	backupKey := os.Getenv("LLM_API_KEY_SECONDARY")
+	extraKeys := splitKeyList(os.Getenv("LLM_API_KEYS"))
 	baseURL := os.Getenv("LLM_BASE_URL")
 
 	// Debug logging for configuration
 	log.Printf("[DEBUG][NewLLMClient] Environment configuration:")
 	log.Printf("[DEBUG][NewLLMClient] LLM_API_KEY: %s", maskKey(primaryKey))
 	log.Printf("[DEBUG][NewLLMClient] LLM_API_KEY_SECONDARY: %s", maskKey(backupKey))
+	log.Printf("[DEBUG][NewLLMClient] LLM_API_KEYS: %d additional key(s)", len(extraKeys))
 	log.Printf("[DEBUG][NewLLMClient] LLM_BASE_URL: %s", baseURL)
 
 	// Return error for missing primary key instead of panicking
@@ -215,7 +298,9 @@ func NewLLMClient(dbConn *sqlx.DB) (*LLMClient, error) {
 	}
 
 	// Initialize service with OpenRouter configuration
-	service := NewHTTPLLMService(restyClient, primaryKey, backupKey, baseURL)
+	service := NewHTTPLLMServiceWithKeys(restyClient, append([]string{primaryKey, backupKey}, extraKeys...), baseURL)
+	service.breaker = newCircuitBreakerFromEnv()
+	service.usageDB = dbConn
 
 	client := &LLMClient{
 		client:     &http.Client{},
@@ -223,8 +308,15 @@ func NewLLMClient(dbConn *sqlx.DB) (*LLMClient, error) {
 		db:         dbConn,
 		llmService: service,
 		config:     config,
+		latencies:  newModelLatencies(),
 	}
 
+	embedding, err := newEmbeddingServiceFromConfig(config.Embedding, restyClient, primaryKey, baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid embedding config: %w", err)
+	}
+	client.embedding = embedding
+
 	// Validate API key during initialization if not in test mode
 	if os.Getenv("TEST_MODE") != "true" && os.Getenv("SKIP_API_VALIDATION") != "true" {
 		log.Printf("[INFO] Validating API key during startup...")
@@ -238,13 +330,58 @@ func NewLLMClient(dbConn *sqlx.DB) (*LLMClient, error) {
 	return client, nil // Return nil error on success
 }
 
+// NewLLMClientWithService creates a client backed by the given service and config
+// without reading the environment, e.g. with a FixtureLLMService in tests
+func NewLLMClientWithService(dbConn *sqlx.DB, service LLMService, config *CompositeScoreConfig) *LLMClient {
+	return &LLMClient{
+		client:     &http.Client{},
+		cache:      NewCache(),
+		db:         dbConn,
+		llmService: service,
+		config:     config,
+		latencies:  newModelLatencies(),
+	}
+}
+
+// SetEmbeddingService enables the embedding perspective in ensemble analysis with the
+// given service, or disables it when nil
+func (c *LLMClient) SetEmbeddingService(service *EmbeddingLLMService) {
+	c.embedding = service
+}
+
 // GetConfig returns the loaded configuration for the client.
 func (c *LLMClient) GetConfig() *CompositeScoreConfig {
-	// Maybe add logic here to load config if nil?
-	// For now, just return the stored config.
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
 	return c.config
 }
 
+// SetConfig replaces the client's configuration, e.g. after the config file changed.
+// Analyses already running keep the configuration they started with.
+func (c *LLMClient) SetConfig(config *CompositeScoreConfig) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+	c.config = config
+}
+
+// loadedConfig returns the client's configuration, loading it from the config file
+// when none is set yet
+func (c *LLMClient) loadedConfig() (*CompositeScoreConfig, error) {
+	if config := c.GetConfig(); config != nil {
+		return config, nil
+	}
+	config, err := LoadCompositeScoreConfig()
+	if err != nil {
+		return nil, err
+	}
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+	if c.config == nil {
+		c.config = config
+	}
+	return c.config, nil
+}
+
 // ValidateAPIKey validates the API key by making a test request to the LLM service
 func (c *LLMClient) ValidateAPIKey() error {
 	// Cast to HTTPLLMService to access the callLLMAPIWithKey method
@@ -286,16 +423,28 @@ func (c *LLMClient) ValidateAPIKey() error {
 	}
 }
 
-func (c *LLMClient) analyzeContent(articleID int64, content string, model string) (*db.LLMScore, error) {
-	log.Printf("[analyzeContent] Entry: articleID=%d, model=%s", articleID, model)
-	contentHash := hashContent(content)
-
-	if cached, ok := c.cache.Get(contentHash, model); ok {
-		return cached, nil
+// defaultPromptVariant returns the prompt used for single-pass scoring with a model
+func defaultPromptVariant(modelConfig *ModelConfig) PromptVariant {
+	return PromptVariant{
+		ID: "default",
+		Template: "Please analyze the political bias of the following article on a scale from -1.0 (strongly left) " +
+			"to 1.0 (strongly right). Respond ONLY with a valid JSON object containing 'score', 'explanation', " +
+			"and 'confidence'. Do not include any other text or formatting.",
+		Examples: []string{
+			`{"score": -1.0, "explanation": "Strongly left-leaning language", "confidence": 0.9}`,
+			`{"score": 0.0, "explanation": "Neutral reporting", "confidence": 0.95}`,
+			`{"score": 1.0, "explanation": "Strongly right-leaning language", "confidence": 0.9}`,
+		},
+		Model:       modelConfig.ModelName,
+		URL:         modelConfig.URL,
+		ExtraParams: modelConfig.ExtraParams,
 	}
+}
 
-	// Load composite score config to get the model configuration
-	cfg, err := LoadCompositeScoreConfig()
+func (c *LLMClient) analyzeContent(ctx context.Context, articleID int64, content string, model string) (*db.LLMScore, error) {
+	log.Printf("[analyzeContent] Entry: articleID=%d, model=%s", articleID, model)
+	// Use the client's config, as the ensemble and reanalysis paths do
+	cfg, err := c.loadedConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load composite score config: %w", err)
 	}
@@ -313,27 +462,31 @@ func (c *LLMClient) analyzeContent(articleID int64, content string, model string
 		return nil, fmt.Errorf("model %s not found in configuration", model)
 	}
 
-	generalPrompt := PromptVariant{
-		ID: "default",
-		Template: "Please analyze the political bias of the following article on a scale from -1.0 (strongly left) " +
-			"to 1.0 (strongly right). Respond ONLY with a valid JSON object containing 'score', 'explanation', " +
-			"and 'confidence'. Do not include any other text or formatting.",
-		Examples: []string{
-			`{"score": -1.0, "explanation": "Strongly left-leaning language", "confidence": 0.9}`,
-			`{"score": 0.0, "explanation": "Neutral reporting", "confidence": 0.95}`,
-			`{"score": 1.0, "explanation": "Strongly right-leaning language", "confidence": 0.9}`,
-		},
-		Model: modelConfig.ModelName,
-		URL:   modelConfig.URL,
+	generalPrompt, exampleIDs := cfg.withSampling(cfg.withExampleSampling(defaultPromptVariant(modelConfig))).SampleExamples(articleID)
+
+	content, truncated := prepareContent(cfg, articleID, content)
+
+	// The cache is keyed on the prompt and content rather than the article ID, so
+	// identical content shares results and edited content is always re-scored
+	contentHash := hashPromptContent(generalPrompt, content)
+	if cached, ok := c.cache.Get(contentHash, model); ok {
+		cached.ID = 0
+		cached.ArticleID = articleID
+		cached.Metadata = withoutUsage(cached.Metadata)
+		return cached, nil
 	}
 
-	scoreVal, explanation, confidence, _, err := c.callLLM(articleID, model, generalPrompt, content)
+	started := time.Now()
+	scoreVal, explanation, confidence, rawResp, scoredBy, err := c.callLLMWithFallback(ctx, articleID, cfg.FallbackChain(model), generalPrompt, content)
+	c.latencies.record(model, time.Since(started))
 	if err != nil {
 		return nil, err
 	}
 
-	meta := fmt.Sprintf(`{"explanation": %q, "confidence": %.3f, "perspective": %q}`,
-		explanation, confidence, modelConfig.Perspective)
+	meta := fmt.Sprintf(`{"explanation": %q, "confidence": %.3f, "perspective": %q, "scored_by": %q%s%s%s%s}`,
+		explanation, confidence, modelConfig.Perspective, scoredBy, truncatedMetaField(truncated),
+		exampleIDsMetaField(generalPrompt, exampleIDs), samplingMetaField(generalPrompt.Sampling),
+		usageMetaField(parseTokenUsage(rawResp)))
 
 	score := &db.LLMScore{
 		ArticleID: articleID,
@@ -372,17 +525,18 @@ func (c *LLMClient) ProcessUnscoredArticles() error {
 }
 
 func (c *LLMClient) AnalyzeAndStore(article *db.Article) error {
-	if c.config == nil || len(c.config.Models) == 0 {
+	cfg := c.GetConfig()
+	if cfg == nil || len(cfg.Models) == 0 {
 		log.Printf("[ERROR] LLMClient config is nil or has no models defined")
 		return fmt.Errorf("LLMClient config is nil or has no models defined")
 	}
 
 	var lastErr error
 
-	for _, m := range c.config.Models {
+	for _, m := range cfg.Models {
 		log.Printf("[DEBUG][AnalyzeAndStore] Article %d | Perspective: %s | ModelName passed: %s | URL: %s",
 			article.ID, m.Perspective, m.ModelName, m.URL)
-		score, err := c.analyzeContent(article.ID, article.Content, m.ModelName)
+		score, err := c.analyzeContent(context.Background(), article.ID, article.Content, m.ModelName)
 		if err != nil {
 			log.Printf("Error analyzing article %d with model %s: %v", article.ID, m.ModelName, err)
 			lastErr = fmt.Errorf("error analyzing article %d with model %s: %w", article.ID, m.ModelName, err)
@@ -400,9 +554,25 @@ func (c *LLMClient) AnalyzeAndStore(article *db.Article) error {
 	return lastErr // Return the last error encountered
 }
 
-// ReanalyzeArticle performs a complete reanalysis of an article using all configured models
+// ReanalyzeOptions adjusts how ReanalyzeArticleWithOptions runs
+type ReanalyzeOptions struct {
+	// Force calls the models even when the content is unchanged since the last analysis
+	Force bool
+}
+
+// ReanalyzeArticle performs a complete reanalysis of an article using all configured
+// models. An article whose content and LLM configuration are unchanged since its last
+// analysis keeps its existing composite without calling any model.
 func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, scoreManager *ScoreManager) error {
-	log.Printf("[ReanalyzeArticle %d] Starting reanalysis", articleID)
+	return c.ReanalyzeArticleWithOptions(ctx, articleID, scoreManager, ReanalyzeOptions{})
+}
+
+// ReanalyzeArticleWithOptions is ReanalyzeArticle with options
+func (c *LLMClient) ReanalyzeArticleWithOptions(ctx context.Context, articleID int64, scoreManager *ScoreManager, opts ReanalyzeOptions) error {
+	log.Printf("[ReanalyzeArticle %d] Starting reanalysis (force=%t)", articleID, opts.Force)
+	if !opts.Force && c.contentUnchanged(ctx, articleID, scoreManager) {
+		return nil
+	}
 	if scoreManager != nil {
 		scoreManager.SetProgress(articleID, &models.ProgressState{
 			Status:  "InProgress",
@@ -456,7 +626,7 @@ func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, score
 			Percent: 10,
 		})
 	}
-	_, delErr := tx.ExecContext(ctx, "DELETE FROM llm_scores WHERE article_id = ? AND model != 'ensemble'", articleID)
+	_, delErr := tx.ExecContext(ctx, "DELETE FROM llm_scores WHERE article_id = ? AND model NOT IN ('ensemble', ?)", articleID, db.ManualScoreModel)
 	if delErr != nil {
 		err = fmt.Errorf("failed to delete existing non-ensemble scores for article %d: %w", articleID, delErr)
 		if scoreManager != nil {
@@ -485,37 +655,60 @@ func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, score
 	}
 
 	log.Printf("[ReanalyzeArticle %d] Fetched article: Title='%.50s'", articleID, article.Title)
-	if c.config == nil {
+	if c.GetConfig() == nil {
 		log.Printf("[ReanalyzeArticle %d] Error: LLMClient config is not loaded.", articleID)
-		var loadErr error
-		c.config, loadErr = LoadCompositeScoreConfig()
-		if loadErr != nil {
-			err = fmt.Errorf("LLMClient config is not loaded and fallback failed for article %d: %w", articleID, loadErr)
-			if scoreManager != nil {
-				scoreManager.SetProgress(articleID, &models.ProgressState{Status: "Error", Step: "Load Config", Message: "Failed to load LLM configuration", Error: loadErr.Error()})
-			}
-			return err // Defer will handle rollback
-		}
-		log.Printf("[ReanalyzeArticle %d] Loaded config via fallback.", articleID)
 	}
-	cfg := c.config
-	totalModels := len(cfg.Models)
-	currentModelNum := 0
-
-	for _, modelConfig := range cfg.Models {
-		currentModelNum++
-		modelProgressPercent := 15 + int(float64(currentModelNum)/float64(totalModels)*50.0)
-		log.Printf("[ReanalyzeArticle %d] Calling analyzeContent for model: %s", articleID, modelConfig.ModelName)
+	cfg, loadErr := c.loadedConfig()
+	if loadErr != nil {
+		err = fmt.Errorf("LLMClient config is not loaded and fallback failed for article %d: %w", articleID, loadErr)
 		if scoreManager != nil {
-			scoreManager.SetProgress(articleID, &models.ProgressState{
-				Status:  "InProgress",
-				Step:    fmt.Sprintf("Analyzing with %s", modelConfig.ModelName),
-				Message: fmt.Sprintf("Processing with model %d of %d.", currentModelNum, totalModels),
-				Percent: modelProgressPercent,
-			})
+			scoreManager.SetProgress(articleID, &models.ProgressState{Status: "Error", Step: "Load Config", Message: "Failed to load LLM configuration", Error: loadErr.Error()})
 		}
+		return err // Defer will handle rollback
+	}
+	totalModels := len(cfg.Models)
+	// answered marks the models whose score was stored, for the latency budgets
+	answered := make([]bool, totalModels)
+
+	// Models are called concurrently, up to model_concurrency at once, and their scores
+	// stored in model order once all have answered
+	type modelResult struct {
+		score *db.LLMScore
+		err   error
+	}
+	results := make([]modelResult, totalModels)
+	var started atomic.Int32
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cfg.modelConcurrency())
+	for i, modelConfig := range cfg.Models {
+		g.Go(func() error {
+			if ctxErr := gctx.Err(); ctxErr != nil {
+				log.Printf("[ReanalyzeArticle %d] Cancelled before scoring with %s", articleID, modelConfig.ModelName)
+				return ctxErr
+			}
+			modelNum := int(started.Add(1))
+			log.Printf("[ReanalyzeArticle %d] Calling analyzeContent for model: %s", articleID, modelConfig.ModelName)
+			if scoreManager != nil {
+				scoreManager.SetProgress(articleID, &models.ProgressState{
+					Status:  "InProgress",
+					Step:    fmt.Sprintf("Analyzing with %s", modelConfig.ModelName),
+					Message: fmt.Sprintf("Processing with model %d of %d.", modelNum, totalModels),
+					Percent: 15 + int(float64(modelNum)/float64(totalModels)*50.0),
+				})
+			}
+			results[i].score, results[i].err = c.analyzeContent(gctx, article.ID, article.Content, modelConfig.ModelName)
+			return gctx.Err()
+		})
+	}
+	if waitErr := g.Wait(); waitErr != nil {
+		log.Printf("[ReanalyzeArticle %d] Cancelled; discarding model scores", articleID)
+		err = waitErr
+		return err // Defer will handle rollback
+	}
 
-		scoreDataStruct, analyzeErr := c.analyzeContent(article.ID, article.Content, modelConfig.ModelName)
+	for i, modelConfig := range cfg.Models {
+		modelProgressPercent := 15 + int(float64(i+1)/float64(totalModels)*50.0)
+		scoreDataStruct, analyzeErr := results[i].score, results[i].err
 		if analyzeErr != nil {
 			log.Printf("[ReanalyzeArticle %d] Error from analyzeContent for %s: %v", articleID, modelConfig.ModelName, analyzeErr)
 			if scoreManager != nil {
@@ -552,7 +745,7 @@ func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, score
 		}
 
 		log.Printf("[ReanalyzeArticle %d] Attempting to insert/update score for model %s using db.InsertLLMScore (transactional)", articleID, modelConfig.ModelName)
-		_, insertErr := db.InsertLLMScore(tx, scoreDataStruct) // Use tx and *db.LLMScore
+		_, insertErr := db.InsertLLMScoreContext(ctx, tx, scoreDataStruct) // Use tx and *db.LLMScore
 		if insertErr != nil {
 			err = apperrors.Wrap(insertErr, fmt.Sprintf("failed to insert/update score for model %s for article %d", modelConfig.ModelName, articleID), "db_insert_error")
 			log.Printf("[ReanalyzeArticle %d] %v", articleID, err)
@@ -562,6 +755,7 @@ func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, score
 			return err // Defer will handle rollback
 		}
 		log.Printf("[ReanalyzeArticle %d] Successfully inserted/updated score for article %d, model %s", articleID, articleID, modelConfig.ModelName)
+		answered[i] = true
 	}
 
 	// If loop completed, err might still be set by a previous non-fatal error or a commit failure in defer.
@@ -581,7 +775,7 @@ func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, score
 	}
 
 	var currentScores []db.LLMScore
-	fetchScoresErr := tx.SelectContext(ctx, &currentScores, "SELECT * FROM llm_scores WHERE article_id = ? AND model != 'ensemble'", articleID)
+	fetchScoresErr := tx.SelectContext(ctx, &currentScores, "SELECT * FROM llm_scores WHERE article_id = ? AND model NOT IN ('ensemble', ?)", articleID, db.ManualScoreModel)
 	if fetchScoresErr != nil {
 		err = fmt.Errorf("failed to fetch scores from transaction for composite calculation for article %d: %w", articleID, fetchScoresErr)
 		if scoreManager != nil {
@@ -591,7 +785,24 @@ func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, score
 	}
 	log.Printf("[ReanalyzeArticle %d] Found %d non-ensemble scores in transaction for composite calculation.", articleID, len(currentScores)) // Corrected log
 
-	finalScore, confidence, calcErr := ComputeCompositeScoreWithConfidenceFixed(currentScores, cfg)
+	// Models over their latency budget are left out of the composite, but their scores
+	// are kept and still reported
+	budgets, excluded := c.budgetExclusions(modelNames(cfg.Models), answered)
+	compositeScores := currentScores
+	if len(excluded) > 0 {
+		compositeScores = make([]db.LLMScore, 0, len(currentScores))
+		for _, score := range currentScores {
+			if excluded[score.Model] {
+				b := budgets[score.Model]
+				log.Printf("[ReanalyzeArticle %d] Model %s: average latency %dms over %d calls is above its %dms budget. Leaving it out of the composite.",
+					articleID, score.Model, b.AverageLatencyMs, b.Samples, b.MaxLatencyMs)
+				continue
+			}
+			compositeScores = append(compositeScores, score)
+		}
+	}
+
+	finalScore, confidence, chosen, calcErr := calculateComposite(analysisCalculator(scoreManager), compositeScores, cfg)
 	if calcErr != nil {
 		log.Printf("[ReanalyzeArticle %d] Error calculating composite score: %v. Proceeding with zero values.", articleID, calcErr)
 		finalScore = 0
@@ -607,44 +818,29 @@ func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, score
 		}
 	}
 
-	subResults := make([]map[string]interface{}, 0, len(currentScores))
-	for _, s := range currentScores {
-		var currentSubConfidence float64 = 0.0
-		var explanation string = ""
-		var metaOut map[string]interface{}
-		if s.Metadata != "" {
-			if unmarshalErr := json.Unmarshal([]byte(s.Metadata), &metaOut); unmarshalErr == nil {
-				if confVal, ok := metaOut["confidence"].(float64); ok {
-					currentSubConfidence = confVal
-				}
-				if explVal, ok := metaOut["explanation"].(string); ok {
-					explanation = explVal
-				}
-			} else {
-				log.Printf("[ReanalyzeArticle %d] Error unmarshalling metadata for model %s score ID %d: %v", articleID, s.Model, s.ID, unmarshalErr)
-			}
-		}
-		perspective := MapModelToPerspective(s.Model, cfg)
-		if perspective == "" {
-			perspective = "unknown"
-		}
-		subResults = append(subResults, map[string]interface{}{
-			"model":       s.Model,
-			"score":       s.Score,
-			"confidence":  currentSubConfidence,
-			"explanation": explanation,
-			"perspective": perspective,
-		})
+	// The source's trust weight changes how confident the composite is, never the score
+	details := compositeDetails{Calculator: chosen}
+	confidence, details.sourceTrust = applySourceTrust(tx, articleID, confidence)
+
+	subResults, contentTruncated := ensembleSubResults(currentScores, cfg)
+	finalAggregation := map[string]any{
+		"weighted_mean": finalScore,
+		"variance":      1.0 - details.RawConfidence,
+		"confidence":    confidence,
 	}
+	cfg.assessSubResults(subResults).addTo(finalAggregation)
+	details.addTo(finalAggregation)
 
 	ensembleMetaMap := map[string]any{
-		"timestamp":   time.Now().UTC().Format(time.RFC3339),
-		"sub_results": subResults,
-		"final_aggregation": map[string]any{
-			"weighted_mean": finalScore,
-			"variance":      1.0 - confidence,
-			"confidence":    confidence,
-		},
+		"timestamp":         time.Now().UTC().Format(time.RFC3339),
+		"sub_results":       subResults,
+		"final_aggregation": finalAggregation,
+	}
+	if contentTruncated {
+		ensembleMetaMap["content_truncated"] = true
+	}
+	if len(budgets) > 0 {
+		ensembleMetaMap["model_budgets"] = budgets
 	}
 	metaBytes, marshalErr := json.Marshal(ensembleMetaMap)
 	if marshalErr != nil {
@@ -655,17 +851,30 @@ func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, score
 		return err // Defer will rollback
 	}
 
+	// Each reanalysis is a new score version, which score stability is measured across.
+	// The content hash lets an unchanged article skip its next reanalysis, so it is only
+	// kept when the composite was actually computed.
+	contentHash := ""
+	if calcErr == nil {
+		contentHash = hashContent(article.Content, cfg)
+	}
+	version, historyErr := db.RecordAnalyzedScoreVersion(tx, articleID, finalScore, confidence, db.ScoreVersionAnalysis, contentHash)
+	if historyErr != nil {
+		log.Printf("[ReanalyzeArticle %d] Failed to record score history: %v", articleID, historyErr)
+		version = 1
+	}
+
 	ensembleLLMScore := &db.LLMScore{
 		ArticleID: articleID,
 		Model:     "ensemble",
 		Score:     finalScore,
 		Metadata:  string(metaBytes),
-		Version:   1,
+		Version:   version,
 		CreatedAt: time.Now().UTC(),
 	}
 
 	log.Printf("[ReanalyzeArticle %d] Attempting to insert/update ensemble score using db.InsertLLMScore (transactional)", articleID)
-	_, ensembleInsertErr := db.InsertLLMScore(tx, ensembleLLMScore)
+	_, ensembleInsertErr := db.InsertLLMScoreContext(ctx, tx, ensembleLLMScore)
 	if ensembleInsertErr != nil {
 		err = fmt.Errorf("failed to insert/update ensemble score for article %d: %w", articleID, ensembleInsertErr)
 		if scoreManager != nil {
@@ -685,8 +894,13 @@ func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, score
 		})
 	}
 	_, updateErr := tx.ExecContext(ctx,
-		"UPDATE articles SET composite_score = ?, confidence = ?, score_source = 'llm', status = 'processed' WHERE id = ?",
-		finalScore, confidence, articleID)
+		`UPDATE articles SET
+			composite_score = CASE WHEN score_source = ? THEN composite_score ELSE ? END,
+			confidence = CASE WHEN score_source = ? THEN confidence ELSE ? END,
+			score_source = CASE WHEN score_source = ? THEN score_source ELSE 'llm' END,
+			status = 'processed'
+		WHERE id = ?`,
+		db.ScoreSourceManual, finalScore, db.ScoreSourceManual, confidence, db.ScoreSourceManual, articleID)
 	if updateErr != nil {
 		err = fmt.Errorf("failed to update article score and status for article %d: %w", articleID, updateErr)
 		if scoreManager != nil {
@@ -696,6 +910,13 @@ func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, score
 	}
 	log.Printf("[ReanalyzeArticle %d] Successfully updated article table in transaction for article %d.", articleID, articleID)
 
+	// Last chance to honour a cancellation before anything is persisted
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		log.Printf("[ReanalyzeArticle %d] Cancelled before commit; rolling back.", articleID)
+		err = ctxErr
+		return err // Defer will handle rollback
+	}
+
 	log.Printf("[ReanalyzeArticle %d] Reanalysis operations within transaction complete. Preparing to commit.", articleID)
 	if scoreManager != nil {
 		scoreManager.SetProgress(articleID, &models.ProgressState{
@@ -709,8 +930,58 @@ func (c *LLMClient) ReanalyzeArticle(ctx context.Context, articleID int64, score
 	return err // err will be nil if all ops succeeded, or will contain commitErr if commit failed in defer
 }
 
+// contentUnchanged reports whether the article already has a composite scored from its
+// current content with the current configuration, in which case the article is marked
+// processed and progress is left at the finalizing step with SkippedUnchanged set.
+// Lookup failures are logged and treated as changed.
+func (c *LLMClient) contentUnchanged(ctx context.Context, articleID int64, scoreManager *ScoreManager) bool {
+	var article struct {
+		Content        string   `db:"content"`
+		CompositeScore *float64 `db:"composite_score"`
+	}
+	if err := c.db.GetContext(ctx, &article, "SELECT content, composite_score FROM articles WHERE id = ?", articleID); err != nil {
+		log.Printf("[ReanalyzeArticle %d] Could not check for unchanged content: %v", articleID, err)
+		return false
+	}
+	if article.CompositeScore == nil {
+		return false
+	}
+	cfg, err := c.loadedConfig()
+	if err != nil {
+		log.Printf("[ReanalyzeArticle %d] Could not check for unchanged content: %v", articleID, err)
+		return false
+	}
+	lastHash, err := db.FetchLastContentHash(c.db, articleID)
+	if err != nil {
+		log.Printf("[ReanalyzeArticle %d] Could not check for unchanged content: %v", articleID, err)
+		return false
+	}
+	if lastHash == "" || lastHash != hashContent(article.Content, cfg) {
+		return false
+	}
+	// The skipped analysis still counts as done, so an interrupted article is not
+	// picked up again
+	if _, err := c.db.ExecContext(ctx, `UPDATE articles SET status = 'processed' WHERE id = ?`, articleID); err != nil {
+		log.Printf("[ReanalyzeArticle %d] Could not mark unchanged article processed: %v", articleID, err)
+		return false
+	}
+
+	log.Printf("[ReanalyzeArticle %d] Content unchanged since the last analysis; keeping composite %.4f", articleID, *article.CompositeScore)
+	if scoreManager != nil {
+		scoreManager.SetProgress(articleID, &models.ProgressState{
+			Status:           "InProgress",
+			Step:             "Content unchanged",
+			Message:          "Content unchanged since the last analysis; keeping the existing score.",
+			Percent:          99,
+			FinalScore:       article.CompositeScore,
+			SkippedUnchanged: true,
+		})
+	}
+	return true
+}
+
 func (c *LLMClient) AnalyzeContent(articleID int64, content string, model string, url string, scoreManager *ScoreManager) (*db.LLMScore, error) { // Add scoreManager
-	return c.analyzeContent(articleID, content, model)
+	return c.analyzeContent(context.Background(), articleID, content, model)
 }
 
 func (c *LLMClient) GetArticle(articleID int64) (db.Article, error) {
@@ -730,25 +1001,41 @@ func (c *LLMClient) FetchScores(articleID int64) ([]db.LLMScore, error) {
 
 // ScoreWithModel uses a single model to score content
 func (c *LLMClient) ScoreWithModel(article *db.Article, modelName string) (float64, error) {
+	cfg := c.GetConfig()
 	log.Printf("[DEBUG][CONFIDENCE] ScoreWithModel called for article %d with model %s", article.ID, modelName)
 
-	// Create a prompt variant with the specified model
-	promptVariant := DefaultPromptVariant
-	promptVariant.Model = modelName
-
-	// Find the model in the configuration to get its URL
-	if c.config != nil {
-		for _, m := range c.config.Models {
-			if m.ModelName == modelName {
-				promptVariant.URL = m.URL
-				break
+	// Apply the content limit on a copy so the caller's article is left untouched
+	submitted := *article
+	var truncated bool
+	submitted.Content, truncated = prepareContent(cfg, article.ID, article.Content)
+
+	// Score with the model, then its fallbacks in turn while they fail
+	var score, confidence float64
+	var err error
+	scoredBy := modelName
+	for i, model := range cfg.FallbackChain(modelName) {
+		if i > 0 {
+			log.Printf("[ScoreWithModel] Model %s failed for article %d (%v), trying fallback %s", scoredBy, article.ID, err, model)
+		}
+		scoredBy = model
+		promptVariant := DefaultPromptVariant
+		promptVariant.Model = model
+		// Find the model in the configuration to get its URL
+		if cfg != nil {
+			for _, m := range cfg.Models {
+				if m.ModelName == model {
+					promptVariant.URL = m.URL
+					break
+				}
 			}
 		}
+		// Use the LLM service directly to handle rate limiting properly
+		score, confidence, err = c.llmService.ScoreContent(context.Background(), promptVariant, &submitted)
+		if err == nil {
+			break
+		}
 	}
 
-	// Use the LLM service directly to handle rate limiting properly
-	score, confidence, err := c.llmService.ScoreContent(context.Background(), promptVariant, article)
-
 	if err != nil {
 		// Specifically check for rate limit errors first
 		if errors.Is(err, ErrBothLLMKeysRateLimited) {
@@ -773,7 +1060,7 @@ func (c *LLMClient) ScoreWithModel(article *db.Article, modelName string) (float
 
 	// Create and store the score in the database
 	explanation := "Generated by model " + modelName // We don't have explanation from the interface
-	meta := fmt.Sprintf(`{"explanation": %q, "confidence": %.3f}`, explanation, confidence)
+	meta := fmt.Sprintf(`{"explanation": %q, "confidence": %.3f, "scored_by": %q%s}`, explanation, confidence, scoredBy, truncatedMetaField(truncated))
 	llmScore := &db.LLMScore{
 		ArticleID: article.ID,
 		Model:     modelName,
@@ -801,24 +1088,23 @@ func (c *LLMClient) StoreEnsembleScore(article *db.Article) (float64, error) {
 		return 0, fmt.Errorf("fetching scores for article %d: %w", article.ID, err)
 	}
 
-	if c.config == nil {
+	if c.GetConfig() == nil {
 		log.Printf("[StoreEnsembleScore %d] Error: LLMClient config is nil.", article.ID)
-		// Attempt fallback load
-		var loadErr error
-		c.config, loadErr = LoadCompositeScoreConfig()
-		if loadErr != nil {
-			log.Printf("[StoreEnsembleScore %d] Error loading config fallback: %v", article.ID, loadErr)
-			return 0, fmt.Errorf("LLMClient config is nil and fallback failed: %w", loadErr)
-		}
+	}
+	cfg, loadErr := c.loadedConfig()
+	if loadErr != nil {
+		log.Printf("[StoreEnsembleScore %d] Error loading config fallback: %v", article.ID, loadErr)
+		return 0, fmt.Errorf("LLMClient config is nil and fallback failed: %w", loadErr)
 	}
 
-	score, confidence, err := ComputeCompositeScoreWithConfidenceFixed(scores, c.config)
+	score, confidence, err := ComputeCompositeScoreWithConfidenceFixed(scores, cfg)
 	if err != nil {
 		log.Printf("Error calculating composite score for article %d: %v", article.ID, err)
 		// Proceed with zero score in case of error
 		score = 0
 		confidence = 0
 	}
+	confidence, trust := applySourceTrust(c.db, article.ID, confidence)
 
 	// Prepare metadata for ensemble score
 	subResults := make([]map[string]interface{}, 0, len(scores))
@@ -845,7 +1131,7 @@ func (c *LLMClient) StoreEnsembleScore(article *db.Article) (float64, error) {
 		}
 
 		// Map model to perspective
-		perspective := MapModelToPerspective(s.Model, c.config)
+		perspective := MapModelToPerspective(s.Model, cfg)
 		if perspective == "" {
 			perspective = "unknown"
 		}
@@ -859,14 +1145,18 @@ func (c *LLMClient) StoreEnsembleScore(article *db.Article) (float64, error) {
 		})
 	}
 
+	finalAggregation := map[string]interface{}{
+		"weighted_mean": score,
+		"variance":      1.0 - trust.RawConfidence,
+		"confidence":    confidence,
+	}
+	cfg.assessSubResults(subResults).addTo(finalAggregation)
+	trust.addTo(finalAggregation)
+
 	meta := map[string]interface{}{
-		"timestamp":   time.Now().Format(time.RFC3339),
-		"sub_results": subResults,
-		"final_aggregation": map[string]interface{}{
-			"weighted_mean": score,
-			"variance":      1.0 - confidence,
-			"confidence":    confidence,
-		},
+		"timestamp":         time.Now().Format(time.RFC3339),
+		"sub_results":       subResults,
+		"final_aggregation": finalAggregation,
 	}
 	metaBytes, err := json.Marshal(meta)
 	if err != nil {
@@ -901,9 +1191,18 @@ func (c *LLMClient) StoreEnsembleScore(article *db.Article) (float64, error) {
 	return score, nil
 }
 
-func hashContent(content string) string {
-	hash := sha256.Sum256([]byte(content))
-	return fmt.Sprintf("%x", hash)
+// hashContent fingerprints content together with the configuration it is scored with,
+// so a change of models, prompts or scoring settings also counts as changed content
+func hashContent(content string, cfg *CompositeScoreConfig) string {
+	h := sha256.New()
+	h.Write([]byte(content))
+	if cfg != nil {
+		h.Write([]byte{0})
+		if err := json.NewEncoder(h).Encode(cfg); err != nil {
+			log.Printf("[WARN] Could not fingerprint the LLM config: %v", err)
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
 func min(a, b int) int {
@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"sync"
+	"time"
+)
+
+// latencyWindow is how many recent calls the rolling latency of a model covers, and
+// minBudgetSamples how many it needs before a model can be judged over budget
+const (
+	latencyWindow    = 20
+	minBudgetSamples = 3
+)
+
+// modelLatencies tracks the rolling latency of the calls made to each model. A nil
+// tracker records nothing.
+type modelLatencies struct {
+	mu      sync.Mutex
+	samples map[string][]time.Duration
+}
+
+func newModelLatencies() *modelLatencies {
+	return &modelLatencies{samples: make(map[string][]time.Duration)}
+}
+
+// record adds a call's latency, dropping the oldest once the window is full
+func (l *modelLatencies) record(model string, latency time.Duration) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := append(l.samples[model], latency)
+	if len(s) > latencyWindow {
+		s = s[len(s)-latencyWindow:]
+	}
+	l.samples[model] = s
+}
+
+// average returns the mean latency over the window and how many calls it covers
+func (l *modelLatencies) average(model string) (time.Duration, int) {
+	if l == nil {
+		return 0, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := l.samples[model]
+	if len(s) == 0 {
+		return 0, 0
+	}
+	var total time.Duration
+	for _, d := range s {
+		total += d
+	}
+	return total / time.Duration(len(s)), len(s)
+}
+
+// ModelBudget reports a model's rolling latency against its configured budget
+type ModelBudget struct {
+	MaxLatencyMs     int64 `json:"max_latency_ms"`
+	AverageLatencyMs int64 `json:"average_latency_ms"`
+	Samples          int   `json:"samples"`
+	OverBudget       bool  `json:"over_budget"`
+	Excluded         bool  `json:"excluded"`
+}
+
+// modelBudget returns the model's budget state, and false when it has no max_latency_ms.
+// A model is over budget once it has minBudgetSamples calls and their rolling average
+// is above the limit.
+func (c *LLMClient) modelBudget(model string) (ModelBudget, bool) {
+	cfg := c.GetConfig()
+	if cfg == nil {
+		return ModelBudget{}, false
+	}
+	for _, m := range cfg.Models {
+		if m.ModelName != model || m.MaxLatencyMs <= 0 {
+			continue
+		}
+		avg, samples := c.latencies.average(model)
+		budget := ModelBudget{
+			MaxLatencyMs:     int64(m.MaxLatencyMs),
+			AverageLatencyMs: avg.Milliseconds(),
+			Samples:          samples,
+		}
+		budget.OverBudget = samples >= minBudgetSamples && avg > time.Duration(m.MaxLatencyMs)*time.Millisecond
+		budget.Excluded = budget.OverBudget && m.ExcludeWhenSlow
+		return budget, true
+	}
+	return ModelBudget{}, false
+}
@@ -0,0 +1,136 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelLatenciesRollingWindow(t *testing.T) {
+	l := newModelLatencies()
+	for i := 0; i < latencyWindow; i++ {
+		l.record("m", time.Second)
+	}
+	avg, samples := l.average("m")
+	assert.Equal(t, time.Second, avg)
+	assert.Equal(t, latencyWindow, samples)
+
+	// Fast calls push the slow ones out of the window
+	for i := 0; i < latencyWindow; i++ {
+		l.record("m", 10*time.Millisecond)
+	}
+	avg, samples = l.average("m")
+	assert.Equal(t, 10*time.Millisecond, avg)
+	assert.Equal(t, latencyWindow, samples)
+
+	var disabled *modelLatencies
+	disabled.record("m", time.Second)
+	_, samples = disabled.average("m")
+	assert.Zero(t, samples)
+}
+
+func TestEnsembleAnalyzeExcludesSlowModel(t *testing.T) {
+	fixture := Fixture{
+		Default: FixtureResponse{Score: 0.0, Confidence: 0.9, Explanation: "center"},
+		Rules: []FixtureRule{
+			{Model: "model-slow", Responses: []FixtureResponse{{Score: 0.9, Confidence: 0.9, Explanation: "right", DelayMs: 20}}},
+			{Model: "model-fast", Responses: []FixtureResponse{{Score: -0.4, Confidence: 0.9, Explanation: "left"}}},
+		},
+	}
+
+	analyze := func(minModels int) (*LLMClient, float64, map[string]ModelBudget) {
+		cfg := &CompositeScoreConfig{
+			Models: []ModelConfig{
+				{ModelName: "model-slow", Perspective: "right", Weight: 1.0, MaxLatencyMs: 5, ExcludeWhenSlow: true},
+				{ModelName: "model-fast", Perspective: "left", Weight: 1.0, MaxLatencyMs: 1000, ExcludeWhenSlow: true},
+			},
+			Formula:               "average",
+			MinScore:              -1.0,
+			MaxScore:              1.0,
+			HandleInvalid:         "default",
+			ConfidenceMethod:      "count_valid",
+			MinModelsForComposite: minModels,
+		}
+		client := NewLLMClientWithService(nil, NewFixtureLLMService(fixture), cfg)
+		// Earlier slow calls put the model over its budget
+		client.latencies.record("model-slow", 50*time.Millisecond)
+		client.latencies.record("model-slow", 50*time.Millisecond)
+
+		result, err := client.EnsembleAnalyze(context.Background(), 1, "Article body about policy")
+		require.NoError(t, err)
+		var meta struct {
+			Budgets       map[string]ModelBudget `json:"model_budgets"`
+			AllSubResults []ensembleSubResult    `json:"all_sub_results"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Metadata), &meta))
+		assert.Len(t, meta.AllSubResults, 2, "the excluded model's attempt is still recorded")
+		return client, result.Score, meta.Budgets
+	}
+
+	client, score, budgets := analyze(1)
+	assert.InDelta(t, -0.4, score, 1e-9, "the slow model's vote is left out")
+	require.Contains(t, budgets, "model-slow")
+	assert.True(t, budgets["model-slow"].OverBudget)
+	assert.True(t, budgets["model-slow"].Excluded)
+	assert.Equal(t, 3, budgets["model-slow"].Samples)
+	assert.False(t, budgets["model-fast"].Excluded)
+	_, samples := client.latencies.average("model-fast")
+	assert.Equal(t, 1, samples)
+
+	// Leaving the slow model out would fall short of min_models_for_composite
+	_, score, budgets = analyze(2)
+	assert.InDelta(t, 0.25, score, 1e-9)
+	assert.True(t, budgets["model-slow"].OverBudget)
+	assert.False(t, budgets["model-slow"].Excluded)
+}
+
+func TestReanalyzeArticleExcludesSlowModel(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "budget.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('cnn', CURRENT_TIMESTAMP, 'https://example.com/slow', 'title', 'content')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+
+	// Each model is looked up in the config file while scoring, so budget one of its models
+	fileCfg, err := LoadCompositeScoreConfig()
+	require.NoError(t, err)
+	cfg := *fileCfg
+	cfg.Models = append([]ModelConfig(nil), fileCfg.Models...)
+	slow := cfg.Models[0].ModelName
+	cfg.Models[0].MaxLatencyMs = 5
+	cfg.Models[0].ExcludeWhenSlow = true
+	svc := NewFixtureLLMService(Fixture{
+		Default: FixtureResponse{Score: -0.4, Confidence: 0.9, Explanation: "ok"},
+		Rules:   []FixtureRule{{Model: slow, Responses: []FixtureResponse{{Score: 0.9, Confidence: 0.9, Explanation: "slow", DelayMs: 20}}}},
+	})
+	client := NewLLMClientWithService(dbConn, svc, &cfg)
+	client.latencies.record(slow, 50*time.Millisecond)
+	client.latencies.record(slow, 50*time.Millisecond)
+
+	require.NoError(t, client.ReanalyzeArticle(context.Background(), articleID, nil))
+	_, samples := client.latencies.average(slow)
+	assert.Equal(t, 3, samples, "reanalysis records each call's latency")
+
+	var composite float64
+	require.NoError(t, dbConn.Get(&composite, `SELECT composite_score FROM articles WHERE id = ?`, articleID))
+	assert.InDelta(t, -0.4, composite, 1e-9, "the slow model's vote is left out")
+	var metadata string
+	require.NoError(t, dbConn.Get(&metadata, `SELECT metadata FROM llm_scores WHERE article_id = ? AND model = 'ensemble'`, articleID))
+	var meta struct {
+		Budgets    map[string]ModelBudget `json:"model_budgets"`
+		SubResults []ensembleSubResult    `json:"sub_results"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(metadata), &meta))
+	require.Contains(t, meta.Budgets, slow)
+	assert.True(t, meta.Budgets[slow].Excluded)
+	assert.Len(t, meta.SubResults, len(cfg.Models), "the excluded model's score is still reported")
+}
@@ -15,6 +15,9 @@ const (
 	ProgressStatusInProgress = "InProgress"
 	ProgressStatusSuccess    = "Success"
 	ProgressStatusError      = "Error"
+	ProgressStatusCancelled  = "Cancelled"
+	// ProgressStatusInterrupted marks a reanalysis stopped by a server shutdown
+	ProgressStatusInterrupted = "Interrupted"
 
 	ProgressStepStart       = "Start"
 	ProgressStepCalculating = "Calculating"
@@ -24,11 +27,37 @@ const (
 	ProgressStepError       = "Error" // Also a step
 )
 
+// Retention windows for progress entries
+const (
+	// progressCompletedGrace is the minimum time a finished job is kept so that
+	// clients connecting right after completion still see its terminal state
+	progressCompletedGrace = 30 * time.Second
+	// progressCompletedTTL is how long a finished job is kept if nobody observes it
+	progressCompletedTTL = 5 * time.Minute
+	// progressStaleTTL is how long an unfinished job is kept without updates
+	progressStaleTTL = 30 * time.Minute
+)
+
+// IsTerminalProgressStatus reports whether a progress status marks the end of a job
+func IsTerminalProgressStatus(status string) bool {
+	switch status {
+	case ProgressStatusSuccess, ProgressStatusError, ProgressStatusCancelled, ProgressStatusInterrupted, "Complete", "Skipped":
+		return true
+	}
+	return false
+}
+
 // ProgressManager tracks scoring progress with cleanup
 type ProgressManager struct {
 	progressMap     map[int64]*models.ProgressState
+	observed        map[int64]bool  // terminal states already delivered to a client
+	eventIDs        map[int64]int64 // ID of the latest progress event per job
+	lastEventID     int64           // monotonic across jobs so IDs never repeat
 	progressMapLock sync.RWMutex
 	cleanupInterval time.Duration
+	completedGrace  time.Duration
+	completedTTL    time.Duration
+	staleTTL        time.Duration
 	stopChan        chan struct{}
 	stopped         bool
 }
@@ -37,7 +66,12 @@ type ProgressManager struct {
 func NewProgressManager(cleanupInterval time.Duration) *ProgressManager {
 	pm := &ProgressManager{
 		progressMap:     make(map[int64]*models.ProgressState),
+		observed:        make(map[int64]bool),
+		eventIDs:        make(map[int64]int64),
 		cleanupInterval: cleanupInterval,
+		completedGrace:  progressCompletedGrace,
+		completedTTL:    progressCompletedTTL,
+		staleTTL:        progressStaleTTL,
 		stopChan:        make(chan struct{}),
 		stopped:         false,
 	}
@@ -54,7 +88,29 @@ func NewProgressManager(cleanupInterval time.Duration) *ProgressManager {
 func (pm *ProgressManager) SetProgress(articleID int64, state *models.ProgressState) {
 	pm.progressMapLock.Lock()
 	defer pm.progressMapLock.Unlock()
+	if state != nil && state.LastUpdated == 0 {
+		// Unstamped states would otherwise look infinitely old to cleanup
+		state.LastUpdated = time.Now().Unix()
+	}
 	pm.progressMap[articleID] = state
+	pm.nextEventID(articleID)
+	delete(pm.observed, articleID)
+}
+
+// nextEventID assigns a new event ID to the latest state of a job. Callers must hold the lock.
+func (pm *ProgressManager) nextEventID(articleID int64) {
+	pm.lastEventID++
+	pm.eventIDs[articleID] = pm.lastEventID
+}
+
+// MarkObserved records that the terminal state of a job has been delivered to a
+// client, allowing cleanup to drop it once the grace window has passed
+func (pm *ProgressManager) MarkObserved(articleID int64) {
+	pm.progressMapLock.Lock()
+	defer pm.progressMapLock.Unlock()
+	if state, exists := pm.progressMap[articleID]; exists && state != nil && IsTerminalProgressStatus(state.Status) {
+		pm.observed[articleID] = true
+	}
 }
 
 // UpdateProgress updates progress state with error handling
@@ -74,6 +130,8 @@ func (pm *ProgressManager) UpdateProgress(articleID int64, step string, percent
 	state.Percent = percent
 	state.Status = status
 	state.LastUpdated = time.Now().Unix()
+	pm.nextEventID(articleID)
+	delete(pm.observed, articleID)
 
 	// Enhanced error handling for LLM errors
 	if err != nil {
@@ -111,6 +169,14 @@ func (pm *ProgressManager) GetProgress(articleID int64) *models.ProgressState {
 	return pm.progressMap[articleID]
 }
 
+// GetProgressWithEventID retrieves the progress state for an article together with
+// the ID of the event that produced it, for SSE resumption via Last-Event-ID
+func (pm *ProgressManager) GetProgressWithEventID(articleID int64) (*models.ProgressState, int64) {
+	pm.progressMapLock.RLock()
+	defer pm.progressMapLock.RUnlock()
+	return pm.progressMap[articleID], pm.eventIDs[articleID]
+}
+
 // Stop gracefully shuts down the progress manager
 func (pm *ProgressManager) Stop() {
 	pm.progressMapLock.Lock()
@@ -137,18 +203,36 @@ func (pm *ProgressManager) startCleanupRoutine() {
 	}
 }
 
-// cleanup removes completed or stale progress entries
+// cleanup removes completed or stale progress entries. Finished jobs are kept for
+// at least completedGrace, then dropped once observed or after completedTTL.
 func (pm *ProgressManager) cleanup() {
 	pm.progressMapLock.Lock()
 	defer pm.progressMapLock.Unlock()
 	now := time.Now().Unix()
 	for id, progress := range pm.progressMap {
-		if (progress.Status == ProgressStatusSuccess || progress.Status == ProgressStatusError) && now-progress.LastUpdated > 300 {
-			delete(pm.progressMap, id)
+		if progress == nil {
+			pm.forget(id)
 			continue
 		}
-		if progress.Status == ProgressStatusInProgress && now-progress.LastUpdated > 1800 {
-			delete(pm.progressMap, id)
+		age := time.Duration(now-progress.LastUpdated) * time.Second
+		if IsTerminalProgressStatus(progress.Status) {
+			if age < pm.completedGrace {
+				continue
+			}
+			if pm.observed[id] || age > pm.completedTTL {
+				pm.forget(id)
+			}
+			continue
+		}
+		if age > pm.staleTTL {
+			pm.forget(id)
 		}
 	}
 }
+
+// forget drops everything tracked for a job. Callers must hold the lock.
+func (pm *ProgressManager) forget(articleID int64) {
+	delete(pm.progressMap, articleID)
+	delete(pm.observed, articleID)
+	delete(pm.eventIDs, articleID)
+}
@@ -361,3 +361,75 @@ func TestProgressManager_ExportStateWithErrors(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, string(ErrTypeStreaming), details["type"])
 }
+
+// TestCleanupGraceWindow verifies finished jobs survive until observed or expired
+func TestCleanupGraceWindow(t *testing.T) {
+	pm := NewProgressManager(time.Hour)
+	now := time.Now()
+
+	// Just finished: kept even though observed, so late subscribers still see it
+	pm.SetProgress(1, &models.ProgressState{Status: "Complete", LastUpdated: now.Unix()})
+	pm.MarkObserved(1)
+
+	// Finished past the grace window but never observed: kept until TTL
+	pm.SetProgress(2, &models.ProgressState{Status: ProgressStatusSuccess, LastUpdated: now.Add(-time.Minute).Unix()})
+
+	// Finished past the grace window and observed: dropped
+	pm.SetProgress(3, &models.ProgressState{Status: ProgressStatusError, LastUpdated: now.Add(-time.Minute).Unix()})
+	pm.MarkObserved(3)
+
+	// Queued jobs are not terminal, observing them has no effect
+	pm.SetProgress(4, &models.ProgressState{Status: "Queued", LastUpdated: now.Add(-time.Minute).Unix()})
+	pm.MarkObserved(4)
+
+	pm.cleanup()
+
+	assert.NotNil(t, pm.GetProgress(1), "recently finished job should be kept during grace window")
+	assert.NotNil(t, pm.GetProgress(2), "unobserved finished job should be kept until TTL")
+	assert.Nil(t, pm.GetProgress(3), "observed finished job should be dropped after grace window")
+	assert.NotNil(t, pm.GetProgress(4), "queued job should be kept")
+}
+
+// TestSetProgressResetsObservation verifies a new state for a job clears the observed flag
+func TestSetProgressResetsObservation(t *testing.T) {
+	pm := NewProgressManager(time.Hour)
+	old := time.Now().Add(-time.Minute).Unix()
+
+	pm.SetProgress(1, &models.ProgressState{Status: ProgressStatusSuccess, LastUpdated: old})
+	pm.MarkObserved(1)
+	pm.SetProgress(1, &models.ProgressState{Status: ProgressStatusSuccess, LastUpdated: old})
+	pm.cleanup()
+
+	assert.NotNil(t, pm.GetProgress(1))
+}
+
+// TestSetProgressStampsLastUpdated verifies unstamped states are not treated as ancient
+func TestSetProgressStampsLastUpdated(t *testing.T) {
+	pm := NewProgressManager(time.Hour)
+	pm.SetProgress(1, &models.ProgressState{Status: "Complete"})
+	pm.cleanup()
+
+	progress := pm.GetProgress(1)
+	assert.NotNil(t, progress)
+	assert.NotZero(t, progress.LastUpdated)
+}
+
+// TestProgressEventIDs verifies each state change gets a new, increasing event ID
+func TestProgressEventIDs(t *testing.T) {
+	pm := NewProgressManager(time.Hour)
+
+	_, id := pm.GetProgressWithEventID(1)
+	assert.Zero(t, id, "unknown jobs have no event ID")
+
+	pm.SetProgress(1, &models.ProgressState{Status: ProgressStatusInProgress})
+	_, first := pm.GetProgressWithEventID(1)
+	pm.UpdateProgress(1, ProgressStepCalculating, 50, ProgressStatusInProgress, nil)
+	_, second := pm.GetProgressWithEventID(1)
+	pm.SetProgress(2, &models.ProgressState{Status: ProgressStatusInProgress})
+	pm.SetProgress(1, &models.ProgressState{Status: ProgressStatusSuccess})
+	_, third := pm.GetProgressWithEventID(1)
+
+	assert.Greater(t, first, int64(0))
+	assert.Greater(t, second, first)
+	assert.Greater(t, third, second)
+}
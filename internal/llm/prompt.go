@@ -0,0 +1,263 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+)
+
+// Template variables available in PromptVariant.Template:
+//
+//	{{title}}     the article title
+//	{{source}}    the feed the article came from
+//	{{published}} the publication date, formatted as YYYY-MM-DD
+//
+// The article text is not a variable: it is always appended after the examples, as
+// FormatPrompt always did. Unknown placeholders, {{content}} included, are left intact
+// and logged.
+const (
+	PromptVarTitle     = "title"
+	PromptVarSource    = "source"
+	PromptVarPublished = "published"
+)
+
+// promptPlaceholder matches {{name}} placeholders, allowing surrounding spaces
+var promptPlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z_]+)\s*\}\}`)
+
+// PromptData holds the values substituted into prompt templates
+type PromptData struct {
+	Content   string
+	Title     string
+	Source    string
+	Published time.Time
+}
+
+// PromptDataFromArticle builds prompt data from an article, using content in place
+// of the article text so callers can pass truncated or chunked content
+func PromptDataFromArticle(art *db.Article, content string) PromptData {
+	if art == nil {
+		return PromptData{Content: content}
+	}
+	return PromptData{
+		Content:   content,
+		Title:     art.Title,
+		Source:    art.Source,
+		Published: art.PubDate,
+	}
+}
+
+// vars returns the value of each known template variable
+func (d PromptData) vars() map[string]string {
+	published := ""
+	if !d.Published.IsZero() {
+		published = d.Published.Format("2006-01-02")
+	}
+	return map[string]string{
+		PromptVarTitle:     d.Title,
+		PromptVarSource:    d.Source,
+		PromptVarPublished: published,
+	}
+}
+
+// Render substitutes the template variables and joins the template, the examples and
+// the article, exactly as FormatPrompt did before templates had variables
+func (pv *PromptVariant) Render(data PromptData) string {
+	vars := data.vars()
+	template := promptPlaceholder.ReplaceAllStringFunc(pv.Template, func(match string) string {
+		name := promptPlaceholder.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok {
+			log.Printf("[WARN] Prompt variant %q uses unknown placeholder %s; leaving it intact", pv.ID, match)
+			return match
+		}
+		return value
+	})
+
+	examplesText := strings.Join(pv.Examples, "\n")
+	return template + "\n" + examplesText + "\nArticle:\n" + data.Content
+}
+
+// userMessages wraps a prompt in a single user chat message
+func userMessages(prompt string) []map[string]string {
+	return []map[string]string{
+		{"role": "user", "content": prompt},
+	}
+}
+
+// ChatMessages builds the chat completion messages for a rendered prompt, sending the
+// system prompt as its own message when the variant has one
+func (pv *PromptVariant) ChatMessages(prompt string) []map[string]string {
+	if strings.TrimSpace(pv.SystemPrompt) == "" {
+		return userMessages(prompt)
+	}
+	return []map[string]string{
+		{"role": "system", "content": pv.SystemPrompt},
+		{"role": "user", "content": prompt},
+	}
+}
+
+// usesArticleVariables reports whether the template references article metadata
+// beyond its content
+func (pv *PromptVariant) usesArticleVariables() bool {
+	for _, m := range promptPlaceholder.FindAllStringSubmatch(pv.Template, -1) {
+		switch m[1] {
+		case PromptVarTitle, PromptVarSource, PromptVarPublished:
+			return true
+		}
+	}
+	return false
+}
+
+// promptData builds the template data for an article. The article is only looked up
+// when the template needs its metadata; lookup failures leave those variables empty.
+func (c *LLMClient) promptData(articleID int64, pv PromptVariant, content string) PromptData {
+	if c.db == nil || !pv.usesArticleVariables() {
+		return PromptData{Content: content}
+	}
+	article, err := db.FetchArticleByID(c.db, articleID)
+	if err != nil {
+		log.Printf("[WARN] ArticleID %d | Could not load article for prompt variables: %v", articleID, err)
+		return PromptData{Content: content}
+	}
+	return PromptDataFromArticle(article, content)
+}
+
+// withExampleSampling copies the configured example sampling onto a prompt variant
+func (cfg *CompositeScoreConfig) withExampleSampling(pv PromptVariant) PromptVariant {
+	if cfg == nil {
+		return pv
+	}
+	pv.SampleSize = cfg.ExampleSampleSize
+	pv.Seed = cfg.ExampleSeed
+	return pv
+}
+
+// SampleExamples returns a copy of the variant holding only the examples drawn for
+// this call, along with their indices in the full example list. Without a seed the
+// draw is random; with one it depends only on the seed and article ID.
+func (pv PromptVariant) SampleExamples(articleID int64) (PromptVariant, []int) {
+	if pv.SampleSize <= 0 || pv.SampleSize >= len(pv.Examples) {
+		ids := make([]int, len(pv.Examples))
+		for i := range ids {
+			ids[i] = i
+		}
+		return pv, ids
+	}
+
+	var perm []int
+	if pv.Seed != nil {
+		perm = rand.New(rand.NewSource(*pv.Seed + articleID)).Perm(len(pv.Examples)) // #nosec G404 - reproducibility, not security
+	} else {
+		perm = rand.Perm(len(pv.Examples)) // #nosec G404 - example rotation, not security
+	}
+	ids := perm[:pv.SampleSize]
+	sort.Ints(ids) // keep examples in their original order
+
+	examples := make([]string, len(ids))
+	for i, id := range ids {
+		examples[i] = pv.Examples[id]
+	}
+	pv.Examples = examples
+	return pv, ids
+}
+
+// exampleIDsMetaField returns the metadata JSON fragment recording sampled example IDs
+func exampleIDsMetaField(pv PromptVariant, ids []int) string {
+	if pv.SampleSize <= 0 {
+		return ""
+	}
+	encoded, err := json.Marshal(ids)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf(`, "example_ids": %s`, encoded)
+}
+
+// Errors returned by PreviewPrompt for invalid selections
+var (
+	ErrPromptModelNotConfigured = errors.New("model is not configured")
+	ErrUnknownPromptVariant     = errors.New("unknown prompt variant")
+)
+
+// PromptPreview is the exact prompt that would be sent to a model for an article
+type PromptPreview struct {
+	ArticleID        int64          `json:"article_id"`
+	Model            string         `json:"model"`
+	Variant          string         `json:"variant"`
+	SystemPrompt     string         `json:"system_prompt,omitempty"`
+	Prompt           string         `json:"prompt"`
+	Sampling         SamplingParams `json:"sampling"`
+	PromptChars      int            `json:"prompt_chars"`
+	ContentTruncated bool           `json:"content_truncated"`
+	ExampleIDs       []int          `json:"example_ids"`
+}
+
+// PreviewPrompt renders the prompt for an article without calling the LLM. An empty
+// model selects the first configured model and an empty variant the single-pass
+// "default" prompt; other variants are the ensemble prompts.
+func (c *LLMClient) PreviewPrompt(article *db.Article, model string, variantID string) (*PromptPreview, error) {
+	cfg := c.GetConfig()
+	if cfg == nil {
+		loaded, err := LoadCompositeScoreConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load composite score config: %w", err)
+		}
+		cfg = loaded
+	}
+	if len(cfg.Models) == 0 {
+		return nil, ErrPromptModelNotConfigured
+	}
+
+	modelConfig := &cfg.Models[0]
+	if model != "" {
+		modelConfig = nil
+		for i := range cfg.Models {
+			if cfg.Models[i].ModelName == model {
+				modelConfig = &cfg.Models[i]
+				break
+			}
+		}
+		if modelConfig == nil {
+			return nil, fmt.Errorf("%w: %s", ErrPromptModelNotConfigured, model)
+		}
+	}
+
+	pv := defaultPromptVariant(modelConfig)
+	if variantID != "" && variantID != pv.ID {
+		found := false
+		for _, v := range loadPromptVariants() {
+			if v.ID == variantID {
+				v.Model, v.URL = modelConfig.ModelName, modelConfig.URL
+				pv, found = v, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPromptVariant, variantID)
+		}
+	}
+
+	pv, exampleIDs := cfg.withSampling(cfg.withExampleSampling(pv)).SampleExamples(article.ID)
+	content, truncated := prepareContent(cfg, article.ID, article.Content)
+	prompt := pv.Render(PromptDataFromArticle(article, content))
+
+	return &PromptPreview{
+		ArticleID:        article.ID,
+		Model:            modelConfig.ModelName,
+		Variant:          pv.ID,
+		SystemPrompt:     pv.SystemPrompt,
+		Prompt:           prompt,
+		Sampling:         pv.Sampling,
+		PromptChars:      len([]rune(prompt)),
+		ContentTruncated: truncated,
+		ExampleIDs:       exampleIDs,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromptVariantRender(t *testing.T) {
+	article := &db.Article{
+		Title:   "Budget passes",
+		Source:  "bbc",
+		PubDate: time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC),
+		Content: "full text",
+	}
+
+	t.Run("AllVariables", func(t *testing.T) {
+		pv := PromptVariant{
+			ID:       "vars",
+			Template: "Title: {{title}}\nSource: {{ source }}\nDate: {{published}}",
+			Examples: []string{"ex1", "ex2"},
+		}
+		out := pv.Render(PromptDataFromArticle(article, "short text"))
+		assert.Equal(t, "Title: Budget passes\nSource: bbc\nDate: 2025-03-14\nex1\nex2\nArticle:\nshort text", out)
+	})
+
+	t.Run("ContentAlwaysAppended", func(t *testing.T) {
+		pv := PromptVariant{Template: "Analyze {{title}}", Examples: []string{"ex"}}
+		assert.Equal(t, "Analyze Budget passes\nex\nArticle:\nfull text", pv.Render(PromptDataFromArticle(article, article.Content)))
+		assert.Equal(t, pv.FormatPrompt("x"), "Analyze \nex\nArticle:\nx")
+	})
+
+	t.Run("UnknownPlaceholderKept", func(t *testing.T) {
+		pv := PromptVariant{Template: "{{author}} wrote {{content}}"}
+		assert.Equal(t, "{{author}} wrote {{content}}\n\nArticle:\ntext", pv.Render(PromptData{Content: "text"}),
+			"{{content}} is not a variable, so older templates render as they always did")
+	})
+
+	t.Run("UsesArticleVariables", func(t *testing.T) {
+		assert.False(t, (&PromptVariant{Template: "only {{content}}"}).usesArticleVariables())
+		assert.True(t, (&PromptVariant{Template: "by {{source}}"}).usesArticleVariables())
+	})
+}
+
+func TestSampleExamples(t *testing.T) {
+	bank := []string{"e0", "e1", "e2", "e3", "e4", "e5"}
+
+	t.Run("AllWhenUnset", func(t *testing.T) {
+		pv, ids := PromptVariant{Examples: bank}.SampleExamples(1)
+		assert.Equal(t, bank, pv.Examples)
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, ids)
+		assert.Empty(t, exampleIDsMetaField(pv, ids))
+	})
+
+	t.Run("SampleSize", func(t *testing.T) {
+		pv, ids := PromptVariant{Examples: bank, SampleSize: 2}.SampleExamples(1)
+		assert.Len(t, pv.Examples, 2)
+		assert.Len(t, ids, 2)
+		assert.Less(t, ids[0], ids[1], "examples keep their original order")
+		for i, id := range ids {
+			assert.Equal(t, bank[id], pv.Examples[i])
+		}
+		assert.Len(t, bank, 6, "the example bank is not modified")
+	})
+
+	t.Run("Seeded", func(t *testing.T) {
+		seed := int64(42)
+		variant := PromptVariant{Examples: bank, SampleSize: 3, Seed: &seed}
+		_, first := variant.SampleExamples(7)
+		_, second := variant.SampleExamples(7)
+		assert.Equal(t, first, second, "same seed and article give the same examples")
+
+		pv, ids := variant.SampleExamples(7)
+		assert.JSONEq(t, `{"x": 1`+exampleIDsMetaField(pv, ids)+`}`,
+			fmt.Sprintf(`{"x": 1, "example_ids": [%d,%d,%d]}`, ids[0], ids[1], ids[2]))
+	})
+
+	t.Run("FromConfig", func(t *testing.T) {
+		seed := int64(3)
+		cfg := &CompositeScoreConfig{ExampleSampleSize: 2, ExampleSeed: &seed}
+		pv := cfg.withExampleSampling(PromptVariant{Examples: bank})
+		assert.Equal(t, 2, pv.SampleSize)
+		assert.Equal(t, &seed, pv.Seed)
+	})
+}
+
+func TestPreviewPrompt(t *testing.T) {
+	client := &LLMClient{config: &CompositeScoreConfig{
+		Models: []ModelConfig{
+			{ModelName: "model-left", Perspective: "left", Weight: 1.0},
+			{ModelName: "model-right", Perspective: "right", Weight: 1.0},
+		},
+		MaxContentChars:    20,
+		TruncationStrategy: TruncateHead,
+	}}
+	article := &db.Article{ID: 5, Title: "T", Content: strings.Repeat("word ", 10)}
+
+	preview, err := client.PreviewPrompt(article, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "model-left", preview.Model)
+	assert.Equal(t, "default", preview.Variant)
+	assert.True(t, preview.ContentTruncated)
+	assert.True(t, strings.HasSuffix(preview.Prompt, "Article:\n"+strings.Repeat("word ", 4)))
+	assert.Equal(t, []int{0, 1, 2}, preview.ExampleIDs)
+	assert.Equal(t, len(preview.Prompt), preview.PromptChars)
+
+	preview, err = client.PreviewPrompt(article, "model-right", "right_focus")
+	assert.NoError(t, err)
+	assert.Equal(t, "model-right", preview.Model)
+	assert.Contains(t, preview.Prompt, "conservative")
+
+	_, err = client.PreviewPrompt(article, "missing", "")
+	assert.ErrorIs(t, err, ErrPromptModelNotConfigured)
+	_, err = client.PreviewPrompt(article, "", "missing")
+	assert.ErrorIs(t, err, ErrUnknownPromptVariant)
+}
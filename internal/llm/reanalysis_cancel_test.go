@@ -0,0 +1,120 @@
+package llm
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreManagerCancelJob(t *testing.T) {
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(nil, nil, nil, pm)
+
+	assert.False(t, sm.CancelJob(1), "no job registered yet")
+
+	ctx, done, err := sm.TryStartJob(context.Background(), 1)
+	require.NoError(t, err)
+	assert.NoError(t, ctx.Err())
+	assert.True(t, sm.CancelJob(1))
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	done()
+	assert.False(t, sm.CancelJob(1), "finished jobs are unregistered")
+
+	// A finished job must not unregister a newer job for the same article
+	_, oldDone, err := sm.TryStartJob(context.Background(), 2)
+	require.NoError(t, err)
+	oldDone()
+	newCtx, newDone, err := sm.TryStartJob(context.Background(), 2)
+	require.NoError(t, err)
+	oldDone()
+	assert.NoError(t, newCtx.Err())
+	assert.True(t, sm.CancelJob(2))
+	newDone()
+
+	sm.MarkCancelled(1)
+	state := sm.GetProgress(1)
+	require.NotNil(t, state)
+	assert.Equal(t, ProgressStatusCancelled, state.Status)
+	assert.True(t, IsTerminalProgressStatus(state.Status))
+}
+
+func TestTryStartJobAllowsOneJobPerArticle(t *testing.T) {
+	sm := NewScoreManager(nil, nil, nil, nil)
+
+	const racers = 20
+	var started atomic.Int32
+	dones := make(chan func(), racers)
+	var wg sync.WaitGroup
+	for i := 0; i < racers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, done, err := sm.TryStartJob(context.Background(), 5)
+			if err != nil {
+				assert.ErrorIs(t, err, ErrJobInProgress)
+				return
+			}
+			started.Add(1)
+			dones <- done
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), started.Load(), "exactly one reanalysis of the article may run")
+
+	// Other articles are not held up
+	_, otherDone, err := sm.TryStartJob(context.Background(), 6)
+	require.NoError(t, err)
+	otherDone()
+
+	// Finishing the job releases the lock
+	(<-dones)()
+	ctx, done, err := sm.TryStartJob(context.Background(), 5)
+	require.NoError(t, err)
+	assert.True(t, sm.CancelJob(5), "the new job is the one registered")
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	done()
+	assert.False(t, sm.HasJob(5))
+}
+
+func TestReanalyzeArticleCancelledDiscardsScores(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "cancel.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score, confidence)
+		VALUES ('cnn', CURRENT_TIMESTAMP, 'https://example.com/c', 'title', 'content', 0.4, 0.7)`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	_, err = dbConn.Exec(`INSERT INTO llm_scores (article_id, model, score, metadata) VALUES (?, 'model1', 0.4, '{}')`, articleID)
+	require.NoError(t, err)
+
+	client := &LLMClient{
+		db:     dbConn,
+		config: &CompositeScoreConfig{Models: []ModelConfig{{ModelName: "model1", Perspective: "left", Weight: 1.0}}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = client.ReanalyzeArticle(ctx, articleID, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	scores, err := db.FetchLLMScores(dbConn, articleID)
+	require.NoError(t, err)
+	require.Len(t, scores, 1, "existing scores survive a cancelled reanalysis")
+	assert.InDelta(t, 0.4, scores[0].Score, 1e-9)
+
+	article, err := db.FetchArticleByID(dbConn, articleID)
+	require.NoError(t, err)
+	require.NotNil(t, article.CompositeScore)
+	assert.InDelta(t, 0.4, *article.CompositeScore, 1e-9)
+}
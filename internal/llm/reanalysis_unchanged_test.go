@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReanalyzeArticleSkipsUnchangedContent(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "unchanged.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('cnn', CURRENT_TIMESTAMP, 'https://example.com/u', 'title', 'content')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+
+	svc := NewFixtureLLMService(Fixture{Default: FixtureResponse{Score: 0.3, Confidence: 0.8, Explanation: "ok"}})
+	// Each model is looked up in the config file while scoring, so use its models
+	cfg, err := LoadCompositeScoreConfig()
+	require.NoError(t, err)
+	client := NewLLMClientWithService(dbConn, svc, cfg)
+	calls := len(cfg.Models)
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(dbConn, NewCache(), &DefaultScoreCalculator{}, pm)
+
+	require.NoError(t, client.ReanalyzeArticle(context.Background(), articleID, sm))
+	require.Equal(t, calls, svc.Calls())
+	hash, err := db.FetchLastContentHash(dbConn, articleID)
+	require.NoError(t, err)
+	assert.Equal(t, hashContent("content", cfg), hash, "the analyzed content's hash is stored with the version")
+	assert.False(t, sm.GetProgress(articleID).SkippedUnchanged)
+
+	_, err = dbConn.Exec(`UPDATE articles SET status = ? WHERE id = ?`, models.ArticleStatusPendingRetry, articleID)
+	require.NoError(t, err)
+	require.NoError(t, client.ReanalyzeArticle(context.Background(), articleID, sm))
+	assert.Equal(t, calls, svc.Calls(), "unchanged content is not sent to the LLM again")
+	state := sm.GetProgress(articleID)
+	require.NotNil(t, state)
+	assert.True(t, state.SkippedUnchanged)
+	require.NotNil(t, state.FinalScore)
+	var status string
+	require.NoError(t, dbConn.Get(&status, `SELECT status FROM articles WHERE id = ?`, articleID))
+	assert.Equal(t, "processed", status, "a skipped article no longer waits for a retry")
+
+	require.NoError(t, client.ReanalyzeArticleWithOptions(context.Background(), articleID, sm, ReanalyzeOptions{Force: true}))
+	var versions int
+	require.NoError(t, dbConn.Get(&versions, `SELECT COUNT(*) FROM score_history WHERE article_id = ?`, articleID))
+	assert.Equal(t, 2, versions, "force reanalyzes unchanged content")
+
+	_, err = dbConn.Exec(`UPDATE articles SET content = 'edited content' WHERE id = ?`, articleID)
+	require.NoError(t, err)
+	require.NoError(t, client.ReanalyzeArticle(context.Background(), articleID, sm))
+	assert.Equal(t, 2*calls, svc.Calls(), "changed content is rescored")
+
+	changed := *cfg
+	changed.MinConfidence = cfg.MinConfidence + 0.01
+	client.SetConfig(&changed)
+	require.NoError(t, client.ReanalyzeArticle(context.Background(), articleID, sm))
+	require.NoError(t, dbConn.Get(&versions, `SELECT COUNT(*) FROM score_history WHERE article_id = ?`, articleID))
+	assert.Equal(t, 4, versions, "unchanged content is reanalyzed after a config change")
+	assert.False(t, sm.GetProgress(articleID).SkippedUnchanged)
+}
@@ -0,0 +1,202 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+)
+
+// ErrNoModelScores is returned when an article has no stored per-model scores to
+// recompute its composite from
+var ErrNoModelScores = errors.New("article has no per-model scores")
+
+// RecomputeResult describes a composite recomputed from stored scores
+type RecomputeResult struct {
+	ArticleID     int64
+	PreviousScore *float64
+	Score         float64
+	Confidence    float64
+	Version       int
+	Models        int
+}
+
+// RecomputeArticleScore recomputes an article's composite from its stored per-model
+// scores and the given config, without calling any LLM. The composite is recorded as a
+// new score version and the ensemble score's metadata marks it as a recompute.
+func (sm *ScoreManager) RecomputeArticleScore(articleID int64, cfg *CompositeScoreConfig) (*RecomputeResult, error) {
+	return sm.RecomputeArticleScoreContext(context.Background(), articleID, cfg)
+}
+
+// RecomputeArticleScoreContext is RecomputeArticleScore traced as part of ctx
+func (sm *ScoreManager) RecomputeArticleScoreContext(ctx context.Context, articleID int64, cfg *CompositeScoreConfig) (*RecomputeResult, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("composite score config is required to recompute article %d", articleID)
+	}
+	stored, perModel, previous, err := sm.recomputeInputs(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	score, confidence, version, details, err := sm.updateArticleScore(ctx, articleID, perModel, cfg, db.ScoreVersionRecompute)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		// The history could not be recorded; still store a newer ensemble version
+		version = 1
+		for _, s := range stored {
+			if strings.EqualFold(s.Model, "ensemble") && s.Version >= version {
+				version = s.Version + 1
+			}
+		}
+	}
+
+	subResults, contentTruncated := ensembleSubResults(perModel, cfg)
+	finalAggregation := map[string]any{
+		"weighted_mean": score,
+		"variance":      1.0 - details.RawConfidence,
+		"confidence":    confidence,
+	}
+	cfg.assessSubResults(subResults).addTo(finalAggregation)
+	details.addTo(finalAggregation)
+	meta := map[string]any{
+		"timestamp":         time.Now().UTC().Format(time.RFC3339),
+		"recompute":         true,
+		"sub_results":       subResults,
+		"final_aggregation": finalAggregation,
+	}
+	if contentTruncated {
+		meta["content_truncated"] = true
+	}
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ensemble metadata for article %d: %w", articleID, err)
+	}
+	if _, err := db.InsertLLMScoreContext(ctx, sm.db, &db.LLMScore{
+		ArticleID: articleID,
+		Model:     "ensemble",
+		Score:     score,
+		Metadata:  string(metaBytes),
+		Version:   version,
+		CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to store recomputed ensemble score for article %d: %w", articleID, err)
+	}
+	log.Printf("[RecomputeArticleScore %d] Recomputed composite %.3f from %d model score(s), version %d",
+		articleID, score, len(perModel), version)
+
+	return &RecomputeResult{
+		ArticleID:     articleID,
+		PreviousScore: previous,
+		Score:         score,
+		Confidence:    confidence,
+		Version:       version,
+		Models:        len(perModel),
+	}, nil
+}
+
+// PreviewRecompute computes the composite RecomputeArticleScore would store, applying
+// the same checks, without writing anything. Version is left at 0.
+func (sm *ScoreManager) PreviewRecompute(articleID int64, cfg *CompositeScoreConfig) (*RecomputeResult, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("composite score config is required to recompute article %d", articleID)
+	}
+	_, perModel, previous, err := sm.recomputeInputs(context.Background(), articleID)
+	if err != nil {
+		return nil, err
+	}
+	score, confidence, err := sm.previewComposite(perModel, cfg)
+	if err != nil {
+		return nil, err
+	}
+	confidence, _ = applySourceTrust(sm.db, articleID, confidence)
+	return &RecomputeResult{
+		ArticleID:     articleID,
+		PreviousScore: previous,
+		Score:         score,
+		Confidence:    confidence,
+		Models:        len(perModel),
+	}, nil
+}
+
+// previewComposite computes the composite of per-model scores under cfg with the
+// checks a stored recompute applies, without writing anything
+func (sm *ScoreManager) previewComposite(perModel []db.LLMScore, cfg *CompositeScoreConfig) (float64, float64, error) {
+	if allZeros, zeroErr := checkForAllZeroResponses(perModel); allZeros {
+		return 0, 0, fmt.Errorf("all LLMs returned zero confidence: %w", zeroErr)
+	}
+	if required := cfg.minModelsForComposite(); required > 1 {
+		if valid := countValidModelScores(perModel, cfg); valid < required {
+			return 0, 0, fmt.Errorf("%w: %d of %d required", ErrInsufficientModels, valid, required)
+		}
+	}
+	return sm.calculator.CalculateScore(perModel, cfg)
+}
+
+// recomputeInputs loads an article's stored scores, the per-model subset a composite
+// is computed from and the currently stored composite
+func (sm *ScoreManager) recomputeInputs(ctx context.Context, articleID int64) (stored, perModel []db.LLMScore, previous *float64, err error) {
+	stored, err = db.FetchLLMScoresContext(ctx, sm.db, articleID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	perModel = sm.MergeModelScores(stored, nil)
+	if len(perModel) == 0 {
+		return nil, nil, nil, ErrNoModelScores
+	}
+	previous, _, err = db.FetchArticleScore(sm.db, articleID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return stored, perModel, previous, nil
+}
+
+// ensembleSubResults builds the per-model sub_results stored in ensemble metadata and
+// reports whether any model scored truncated content
+func ensembleSubResults(scores []db.LLMScore, cfg *CompositeScoreConfig) ([]map[string]interface{}, bool) {
+	subResults := make([]map[string]interface{}, 0, len(scores))
+	contentTruncated := false
+	for _, s := range scores {
+		var currentSubConfidence float64 = 0.0
+		var explanation string = ""
+		var metaOut map[string]interface{}
+		if s.Metadata != "" {
+			if unmarshalErr := json.Unmarshal([]byte(s.Metadata), &metaOut); unmarshalErr == nil {
+				if confVal, ok := metaOut["confidence"].(float64); ok {
+					currentSubConfidence = confVal
+				}
+				if explVal, ok := metaOut["explanation"].(string); ok {
+					explanation = explVal
+				}
+				if flag, ok := metaOut["content_truncated"].(bool); ok && flag {
+					contentTruncated = true
+				}
+			} else {
+				log.Printf("[ensembleSubResults %d] Error unmarshalling metadata for model %s score ID %d: %v", s.ArticleID, s.Model, s.ID, unmarshalErr)
+			}
+		}
+		perspective := MapModelToPerspective(s.Model, cfg)
+		if perspective == "" {
+			perspective = "unknown"
+		}
+		sub := map[string]interface{}{
+			"model":       s.Model,
+			"score":       s.Score,
+			"confidence":  currentSubConfidence,
+			"explanation": explanation,
+			"perspective": perspective,
+		}
+		// Keep which model answered when a fallback stood in for the configured one
+		if scoredBy, ok := metaOut["scored_by"].(string); ok && scoredBy != "" {
+			sub["scored_by"] = scoredBy
+		}
+		subResults = append(subResults, sub)
+	}
+	return subResults, contentTruncated
+}
@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecomputeArticleScore(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "recompute.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(dbConn, NewCache(), &DefaultScoreCalculator{}, pm)
+	cfg := &CompositeScoreConfig{
+		Formula:  "average",
+		MinScore: -1,
+		MaxScore: 1,
+		Models: []ModelConfig{
+			{ModelName: "left-model", Perspective: "left"},
+			{ModelName: "center-model", Perspective: "center"},
+			{ModelName: "right-model", Perspective: "right"},
+		},
+	}
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('src', CURRENT_TIMESTAMP, 'https://example.com/recompute', 'title', 'content')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+
+	_, err = sm.RecomputeArticleScore(articleID, cfg)
+	assert.ErrorIs(t, err, ErrNoModelScores)
+
+	for model, score := range map[string]float64{"left-model": -0.6, "center-model": 0, "right-model": 0.3} {
+		_, err = db.InsertLLMScore(dbConn, &db.LLMScore{
+			ArticleID: articleID, Model: model, Score: score, Metadata: `{"confidence": 0.8}`, Version: 1, CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	first, err := sm.RecomputeArticleScore(articleID, cfg)
+	require.NoError(t, err)
+	assert.Nil(t, first.PreviousScore)
+	assert.Equal(t, 1, first.Version)
+	assert.Equal(t, 3, first.Models)
+	assert.InDelta(t, -0.1, first.Score, 1e-6)
+
+	second, err := sm.RecomputeArticleScore(articleID, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.Version, "each recompute stores a new ensemble version")
+	require.NotNil(t, second.PreviousScore)
+	assert.InDelta(t, first.Score, *second.PreviousScore, 1e-6)
+
+	scores, err := db.FetchLLMScores(dbConn, articleID)
+	require.NoError(t, err)
+	var ensemble *db.LLMScore
+	for i := range scores {
+		if scores[i].Model == "ensemble" {
+			ensemble = &scores[i]
+		}
+	}
+	require.NotNil(t, ensemble)
+	assert.Equal(t, 2, ensemble.Version)
+	var meta map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(ensemble.Metadata), &meta))
+	assert.Equal(t, true, meta["recompute"])
+	assert.Len(t, meta["sub_results"], 3)
+}
+
+func TestPreviewRecomputeDoesNotStore(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "preview.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	sm := NewScoreManager(dbConn, NewCache(), &DefaultScoreCalculator{}, nil)
+	cfg := &CompositeScoreConfig{
+		Formula:  "average",
+		MinScore: -1,
+		MaxScore: 1,
+		Models:   []ModelConfig{{ModelName: "left-model", Perspective: "left"}, {ModelName: "right-model", Perspective: "right"}},
+	}
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score, confidence)
+		VALUES ('src', CURRENT_TIMESTAMP, 'https://example.com/preview', 'title', 'content', 0.5, 0.7)`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	for model, score := range map[string]float64{"left-model": -0.2, "right-model": 0.4} {
+		_, err = db.InsertLLMScore(dbConn, &db.LLMScore{
+			ArticleID: articleID, Model: model, Score: score, Metadata: `{"confidence": 0.8}`, Version: 1, CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	preview, err := sm.PreviewRecompute(articleID, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, preview.PreviousScore)
+	assert.InDelta(t, 0.5, *preview.PreviousScore, 1e-9)
+	assert.InDelta(t, 0.1, preview.Score, 1e-6)
+
+	score, _, err := db.FetchArticleScore(dbConn, articleID)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, *score, 1e-9, "a preview leaves the stored composite alone")
+	scores, err := db.FetchLLMScores(dbConn, articleID)
+	require.NoError(t, err)
+	assert.Len(t, scores, 2, "no ensemble score is written")
+}
@@ -0,0 +1,156 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+)
+
+// DefaultRescoreMaxAge is how old a model's valid score may be before rescore-failed
+// treats the model as failed
+const DefaultRescoreMaxAge = 7 * 24 * time.Hour
+
+// ErrNoModelRescored is returned when every model chosen for rescoring failed again
+var ErrNoModelRescored = errors.New("no failed model could be rescored")
+
+// FailedModels returns the configured models, in configuration order, that have no
+// valid score created within maxAge of now. A maxAge of 0 accepts scores of any age.
+func FailedModels(scores []db.LLMScore, cfg *CompositeScoreConfig, maxAge time.Duration, now time.Time) []string {
+	if cfg == nil {
+		return nil
+	}
+	healthy := make(map[string]bool)
+	for _, s := range scores {
+		if !isValidModelScore(s, cfg) {
+			continue
+		}
+		if maxAge > 0 && now.Sub(s.CreatedAt) > maxAge {
+			continue
+		}
+		healthy[strings.ToLower(s.Model)] = true
+	}
+
+	var failed []string
+	for _, m := range cfg.Models {
+		if !healthy[strings.ToLower(m.ModelName)] {
+			failed = append(failed, m.ModelName)
+		}
+	}
+	return failed
+}
+
+// MergeModelScores combines an article's existing per-model scores with freshly
+// computed ones, keeping the fresh score where a model has both. Ensemble and manual
+// scores are dropped so the result can go straight to UpdateArticleScore.
+func (sm *ScoreManager) MergeModelScores(existing, fresh []db.LLMScore) []db.LLMScore {
+	replaced := make(map[string]bool, len(fresh))
+	for _, s := range fresh {
+		replaced[strings.ToLower(s.Model)] = true
+	}
+
+	merged := make([]db.LLMScore, 0, len(existing)+len(fresh))
+	for _, s := range existing {
+		if !isModelScore(s) || replaced[strings.ToLower(s.Model)] {
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return append(merged, fresh...)
+}
+
+// RescoreFailedModels re-runs only the models returned by FailedModels for the article,
+// stores their new scores and recomputes the composite from the merged set. It returns
+// the models that were rescored successfully. Nothing is stored when ctx is cancelled.
+func (c *LLMClient) RescoreFailedModels(ctx context.Context, articleID int64, scoreManager *ScoreManager, maxAge time.Duration) ([]string, error) {
+	cfg := c.GetConfig()
+	if cfg == nil {
+		var err error
+		if cfg, err = LoadCompositeScoreConfig(); err != nil {
+			return nil, fmt.Errorf("failed to load composite score config: %w", err)
+		}
+	}
+
+	article, err := db.FetchArticleByIDContext(ctx, c.db, articleID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := db.FetchLLMScoresContext(ctx, c.db, articleID)
+	if err != nil {
+		return nil, err
+	}
+	failed := FailedModels(existing, cfg, maxAge, time.Now())
+	if len(failed) == 0 {
+		return nil, nil
+	}
+	log.Printf("[RescoreFailedModels %d] Rescoring %d model(s): %s", articleID, len(failed), strings.Join(failed, ", "))
+
+	var fresh []db.LLMScore
+	var rescored []string
+	for i, model := range failed {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		percent := 10 + int(float64(i+1)/float64(len(failed))*70.0)
+		if scoreManager != nil {
+			scoreManager.SetProgress(articleID, &models.ProgressState{
+				Status:  "InProgress",
+				Step:    fmt.Sprintf("Analyzing with %s", model),
+				Message: fmt.Sprintf("Rescoring failed model %d of %d.", i+1, len(failed)),
+				Percent: percent,
+			})
+		}
+
+		score, analyzeErr := c.analyzeContent(ctx, articleID, article.Content, model)
+		if analyzeErr != nil {
+			log.Printf("[RescoreFailedModels %d] Model %s failed again: %v", articleID, model, analyzeErr)
+			continue
+		}
+		if score.CreatedAt.IsZero() {
+			score.CreatedAt = time.Now().UTC()
+		}
+		if score.Version == 0 {
+			score.Version = 1
+		}
+		fresh = append(fresh, *score)
+		rescored = append(rescored, model)
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if len(fresh) == 0 {
+		return nil, ErrNoModelRescored
+	}
+
+	tx, err := c.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction for article %d: %w", articleID, err)
+	}
+	for i := range fresh {
+		if _, err := db.InsertLLMScoreContext(ctx, tx, &fresh[i]); err != nil {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("failed to store score for model %s: %w", fresh[i].Model, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit rescored models for article %d: %w", articleID, err)
+	}
+
+	if scoreManager != nil {
+		scoreManager.SetProgress(articleID, &models.ProgressState{
+			Status:  "InProgress",
+			Step:    "Calculating composite score",
+			Message: "Merging new scores with the existing ones.",
+			Percent: 90,
+		})
+		if _, _, err := scoreManager.UpdateArticleScoreContext(ctx, articleID, scoreManager.MergeModelScores(existing, fresh), cfg); err != nil {
+			return rescored, err
+		}
+	}
+	return rescored, nil
+}
@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailedModels(t *testing.T) {
+	cfg := &CompositeScoreConfig{
+		MinScore: -1,
+		MaxScore: 1,
+		Models: []ModelConfig{
+			{ModelName: "left-model"}, {ModelName: "center-model"}, {ModelName: "right-model"}, {ModelName: "extra-model"},
+		},
+	}
+	now := time.Now()
+	scores := []db.LLMScore{
+		{Model: "left-model", Score: -0.4, Metadata: `{"confidence": 0.8}`, CreatedAt: now.Add(-time.Hour)},
+		{Model: "center-model", Score: 0, Metadata: `{"confidence": 0}`, CreatedAt: now},
+		{Model: "right-model", Score: 0.5, Metadata: `{"confidence": 0.9}`, CreatedAt: now.Add(-48 * time.Hour)},
+		{Model: "ensemble", Score: 0.1, Metadata: `{"confidence": 0.9}`, CreatedAt: now},
+	}
+
+	assert.Equal(t, []string{"center-model", "right-model", "extra-model"}, FailedModels(scores, cfg, 24*time.Hour, now))
+	assert.Equal(t, []string{"center-model", "extra-model"}, FailedModels(scores, cfg, 0, now), "0 accepts scores of any age")
+	assert.Nil(t, FailedModels(scores, nil, 0, now))
+}
+
+func TestMergeModelScores(t *testing.T) {
+	sm := NewScoreManager(nil, nil, nil, nil)
+	existing := []db.LLMScore{
+		{Model: "left-model", Score: -0.4},
+		{Model: "center-model", Score: 0},
+		{Model: "ensemble", Score: -0.2},
+		{Model: db.ManualScoreModel, Score: 0.9},
+	}
+	fresh := []db.LLMScore{{Model: "center-model", Score: 0.1}, {Model: "right-model", Score: 0.3}}
+
+	merged := sm.MergeModelScores(existing, fresh)
+	byModel := make(map[string]float64)
+	for _, s := range merged {
+		byModel[s.Model] = s.Score
+	}
+	assert.Equal(t, map[string]float64{"left-model": -0.4, "center-model": 0.1, "right-model": 0.3}, byModel)
+}
+
+func TestRescoreFailedModelsOnlyCallsFailedModels(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "rescore.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	cfg, err := LoadCompositeScoreConfig()
+	require.NoError(t, err)
+	require.Len(t, cfg.Models, 3)
+
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('src', CURRENT_TIMESTAMP, 'https://example.com/rescore', 'title', 'content to rescore')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	for _, m := range cfg.Models[:2] {
+		_, err = db.InsertLLMScore(dbConn, &db.LLMScore{
+			ArticleID: articleID, Model: m.ModelName, Score: -0.5, Metadata: `{"confidence": 0.8}`, Version: 1, CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+	}
+
+	svc := NewFixtureLLMService(Fixture{Default: FixtureResponse{Score: 0.4, Confidence: 0.9}})
+	client := NewLLMClientWithService(dbConn, svc, cfg)
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(dbConn, NewCache(), &DefaultScoreCalculator{}, pm)
+
+	rescored, err := client.RescoreFailedModels(context.Background(), articleID, sm, DefaultRescoreMaxAge)
+	require.NoError(t, err)
+	assert.Equal(t, []string{cfg.Models[2].ModelName}, rescored)
+	assert.Equal(t, 1, svc.Calls(), "models with valid scores are not called again")
+
+	scores, err := db.FetchLLMScores(dbConn, articleID)
+	require.NoError(t, err)
+	assert.Empty(t, FailedModels(scores, cfg, DefaultRescoreMaxAge, time.Now()))
+
+	article, err := db.FetchArticleByID(dbConn, articleID)
+	require.NoError(t, err)
+	require.NotNil(t, article.CompositeScore)
+	assert.InDelta(t, (-0.5-0.5+0.4)/3, *article.CompositeScore, 1e-6, "composite merges new and existing scores")
+
+	rescored, err = client.RescoreFailedModels(context.Background(), articleID, sm, DefaultRescoreMaxAge)
+	require.NoError(t, err)
+	assert.Empty(t, rescored)
+}
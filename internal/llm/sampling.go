@@ -0,0 +1,121 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// DefaultTemperature is used when neither the config nor the prompt variant sets one,
+// keeping scoring as deterministic as the provider allows
+const DefaultTemperature = 0.0
+
+// SamplingParams controls model sampling. Unset fields are omitted from requests so
+// the provider default applies.
+type SamplingParams struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	Seed        *int64   `json:"seed,omitempty"`
+}
+
+// merge returns p with any unset fields taken from fallback
+func (p SamplingParams) merge(fallback SamplingParams) SamplingParams {
+	if p.Temperature == nil {
+		p.Temperature = fallback.Temperature
+	}
+	if p.TopP == nil {
+		p.TopP = fallback.TopP
+	}
+	if p.Seed == nil {
+		p.Seed = fallback.Seed
+	}
+	return p
+}
+
+// resolved fills in the default temperature when none is set
+func (p SamplingParams) resolved() SamplingParams {
+	if p.Temperature == nil {
+		t := DefaultTemperature
+		p.Temperature = &t
+	}
+	return p
+}
+
+// apply adds the sampling parameters to a chat completions request body
+func (p SamplingParams) apply(body map[string]interface{}) {
+	if p.Temperature != nil {
+		body["temperature"] = *p.Temperature
+	}
+	if p.TopP != nil {
+		body["top_p"] = *p.TopP
+	}
+	if p.Seed != nil {
+		body["seed"] = *p.Seed
+	}
+}
+
+// protectedRequestFields are request body fields that extra_params may not set
+var protectedRequestFields = map[string]bool{"model": true, "messages": true, "stream": true}
+
+// validateExtraParams checks that extra params are a flat object of scalar values that
+// leaves the protected request fields alone
+func validateExtraParams(params map[string]interface{}) error {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if protectedRequestFields[key] {
+			return fmt.Errorf("%q cannot be overridden", key)
+		}
+		switch params[key].(type) {
+		case map[string]interface{}, []interface{}:
+			return fmt.Errorf("%q must be a string, number, boolean or null", key)
+		}
+	}
+	return nil
+}
+
+// applyExtraParams adds a model's extra params to a chat completions request body.
+// It runs after the sampling parameters are applied, so an extra param such as
+// temperature wins over the config and prompt variant values.
+func applyExtraParams(body map[string]interface{}, params map[string]interface{}) {
+	for key, value := range params {
+		if !protectedRequestFields[key] {
+			body[key] = value
+		}
+	}
+}
+
+// extraParams returns the extra_params configured for a model, or nil
+func (cfg *CompositeScoreConfig) extraParams(model string) map[string]interface{} {
+	if cfg == nil {
+		return nil
+	}
+	for _, m := range cfg.Models {
+		if m.ModelName == model {
+			return m.ExtraParams
+		}
+	}
+	return nil
+}
+
+// withSampling resolves the sampling parameters for a prompt variant; values set on
+// the variant override the config
+func (cfg *CompositeScoreConfig) withSampling(pv PromptVariant) PromptVariant {
+	if cfg != nil {
+		pv.Sampling = pv.Sampling.merge(cfg.SamplingParams)
+	}
+	pv.Sampling = pv.Sampling.resolved()
+	return pv
+}
+
+// samplingMetaField returns the metadata JSON fragment recording the sampling parameters
+func samplingMetaField(p SamplingParams) string {
+	encoded, err := json.Marshal(p)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf(`, "sampling": %s`, encoded)
+}
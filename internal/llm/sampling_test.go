@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingParamsFromConfig(t *testing.T) {
+	var cfg CompositeScoreConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"temperature": 0.2, "top_p": 0.9, "seed": 7}`), &cfg))
+	require.NotNil(t, cfg.Temperature)
+	assert.Equal(t, 0.2, *cfg.Temperature)
+	assert.Equal(t, 0.9, *cfg.TopP)
+	assert.Equal(t, int64(7), *cfg.Seed)
+
+	t.Run("VariantOverrides", func(t *testing.T) {
+		temp := 0.5
+		pv := cfg.withSampling(PromptVariant{Sampling: SamplingParams{Temperature: &temp}})
+		assert.Equal(t, 0.5, *pv.Sampling.Temperature)
+		assert.Equal(t, 0.9, *pv.Sampling.TopP)
+		assert.Equal(t, int64(7), *pv.Sampling.Seed)
+	})
+
+	t.Run("DefaultTemperature", func(t *testing.T) {
+		var empty *CompositeScoreConfig
+		pv := empty.withSampling(PromptVariant{})
+		require.NotNil(t, pv.Sampling.Temperature)
+		assert.Equal(t, DefaultTemperature, *pv.Sampling.Temperature)
+		assert.Nil(t, pv.Sampling.TopP)
+		assert.Nil(t, pv.Sampling.Seed)
+		assert.Equal(t, `, "sampling": {"temperature":0}`, samplingMetaField(pv.Sampling))
+	})
+}
+
+func TestScoreContentSendsSamplingParams(t *testing.T) {
+	var body map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"{\"score\":0.1,\"explanation\":\"ok\",\"confidence\":0.8}"}}]}`))
+	}))
+	defer ts.Close()
+
+	svc := NewHTTPLLMService(resty.New(), "dummy-key", "", ts.URL)
+	topP, seed := 0.8, int64(3)
+	pv := PromptVariant{Model: "m", Sampling: SamplingParams{TopP: &topP, Seed: &seed}}
+
+	_, _, err := svc.ScoreContent(context.Background(), pv, &db.Article{ID: 1, Content: "Body"})
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, body["temperature"])
+	assert.Equal(t, 0.8, body["top_p"])
+	assert.Equal(t, 3.0, body["seed"])
+}
+
+func TestScoreContentSendsExtraParams(t *testing.T) {
+	var cfg CompositeScoreConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"models": [{"modelName": "m", "perspective": "center", "weight": 1,
+		"extra_params": {"max_tokens": 256, "temperature": 0.7, "transforms": null}}]}`), &cfg))
+	require.NoError(t, cfg.Validate())
+
+	var body map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"{\"score\":0.1,\"explanation\":\"ok\",\"confidence\":0.8}"}}]}`))
+	}))
+	defer ts.Close()
+
+	svc := NewHTTPLLMService(resty.New(), "dummy-key", "", ts.URL)
+	seed := int64(3)
+	pv := defaultPromptVariant(&cfg.Models[0])
+	pv.Sampling = SamplingParams{Seed: &seed}
+
+	_, _, err := svc.ScoreContent(context.Background(), pv, &db.Article{ID: 1, Content: "Body"})
+	require.NoError(t, err)
+	assert.Equal(t, "m", body["model"])
+	assert.Equal(t, 256.0, body["max_tokens"])
+	assert.Equal(t, 0.7, body["temperature"], "extra_params take precedence over sampling")
+	assert.Equal(t, 3.0, body["seed"])
+	assert.Contains(t, body, "transforms")
+}
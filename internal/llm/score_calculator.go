@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
 )
 
 var perspectives = []string{LabelLeft, LabelCenter, LabelRight}
@@ -109,64 +110,92 @@ func (c *DefaultScoreCalculator) extractConfidence(metadata string) float64 {
 	}
 }
 
+// CalculateScore averages the valid per-model scores and their confidences
 func (c *DefaultScoreCalculator) CalculateScore(scores []db.LLMScore, cfg *CompositeScoreConfig) (float64, float64, error) {
+	trace, err := c.TraceScore(scores, cfg)
+	if err != nil {
+		return 0.0, 0.0, err
+	}
+	return trace.Score, trace.RawConfidence, nil
+}
+
+// TraceScore is CalculateScore reporting each step of the calculation. The trace is
+// returned with its Error set when the scores do not give a composite, and is nil only
+// without a config.
+func (c *DefaultScoreCalculator) TraceScore(scores []db.LLMScore, cfg *CompositeScoreConfig) (*AggregationTrace, error) {
 	if cfg == nil {
-		return 0.0, 0.0, fmt.Errorf("DefaultScoreCalculator: Config must not be nil: %w", ErrAllPerspectivesInvalid)
+		return nil, fmt.Errorf("DefaultScoreCalculator: Config must not be nil: %w", ErrAllPerspectivesInvalid)
+	}
+	trace := &AggregationTrace{
+		Formula:           AggregationFormulaMean,
+		ConfidenceMethod:  AggregationConfidenceMean,
+		MinScore:          cfg.MinScore,
+		MaxScore:          cfg.MaxScore,
+		SourceTrustWeight: models.DefaultSourceTrustWeight,
+		Models:            make([]AggregationStep, 0, len(scores)),
+	}
+	fail := func(err error) (*AggregationTrace, error) {
+		trace.Error = err.Error()
+		return trace, err
 	}
 
 	if len(scores) == 0 {
-		return 0.0, 0.0, ErrAllPerspectivesInvalid
+		return fail(ErrAllPerspectivesInvalid)
 	}
 
-	// Initialize maps for scores and confidences
-	scoreMap, confMap := c.initializeMaps()
-
 	// Process each score
 	validCount := 0
 	var sumScore float64
 	var sumConf float64
 
 	for _, score := range scores {
+		step := AggregationStep{Model: score.Model, Score: score.Score}
 		perspective := c.getPerspective(score.Model, cfg)
-		if perspective == "" {
+		step.Perspective = perspective
+		switch {
+		case perspective == "":
 			log.Printf("Warning: Model '%s' not found in composite score configuration", score.Model)
-			continue
-		}
-
-		// Check if score is valid
-		if math.IsNaN(score.Score) || math.IsInf(score.Score, 0) || score.Score < cfg.MinScore || score.Score > cfg.MaxScore {
+			step.Excluded = "model is not in the composite score configuration"
+		case math.IsNaN(score.Score) || math.IsInf(score.Score, 0):
 			log.Printf("[DEBUG][CONFIDENCE] Ignoring invalid score %.2f for model %s", score.Score, score.Model)
-			continue
-		}
-
-		// Extract confidence from metadata
-		confidence := c.extractConfidence(score.Metadata)
-		if confidence == 0.0 {
-			log.Printf("[DEBUG][CONFIDENCE] No confidence field in metadata, defaulting to 0.0")
-			continue
+			step.Score = 0 // not representable in JSON
+			step.Excluded = "score is not a finite number"
+		case score.Score < cfg.MinScore || score.Score > cfg.MaxScore:
+			log.Printf("[DEBUG][CONFIDENCE] Ignoring invalid score %.2f for model %s", score.Score, score.Model)
+			step.Excluded = fmt.Sprintf("score is outside [%g, %g]", cfg.MinScore, cfg.MaxScore)
+		default:
+			// Extract confidence from metadata
+			step.Confidence = c.extractConfidence(score.Metadata)
+			if step.Confidence == 0.0 {
+				log.Printf("[DEBUG][CONFIDENCE] No confidence field in metadata, defaulting to 0.0")
+				step.Excluded = "confidence is zero or missing"
+				break
+			}
+			step.Included = true
+			step.Weight = 1.0 // every valid model counts equally
+			validCount++
+			sumScore += score.Score
+			sumConf += step.Confidence
 		}
-
-		// Store the score and confidence
-		scoreMap[perspective] = &score.Score
-		confMap[perspective] = &confidence
-
-		validCount++
-		sumScore += score.Score
-		sumConf += confidence
+		trace.Models = append(trace.Models, step)
 	}
 
 	if validCount == 0 {
-		return 0.0, 0.0, ErrAllPerspectivesInvalid
+		return fail(ErrAllPerspectivesInvalid)
 	}
 
 	// Calculate average score and confidence
-	avgScore := sumScore / float64(validCount)
-	avgConf := sumConf / float64(validCount)
-
-	if avgConf == 0.0 {
-		return 0.0, 0.0, ErrAllPerspectivesInvalid
+	trace.WeightTotal = float64(validCount)
+	trace.WeightedSum = sumScore
+	trace.PreClampScore = sumScore / float64(validCount)
+	trace.Score = math.Max(cfg.MinScore, math.Min(cfg.MaxScore, trace.PreClampScore))
+	trace.RawConfidence = sumConf / float64(validCount)
+	trace.Confidence = trace.RawConfidence
+
+	if trace.RawConfidence == 0.0 {
+		return fail(ErrAllPerspectivesInvalid)
 	}
 
-	log.Printf("[DEBUG][CONFIDENCE] Calculated composite score: %.4f with confidence %.4f from %d valid scores", avgScore, avgConf, validCount)
-	return avgScore, avgConf, nil
+	log.Printf("[DEBUG][CONFIDENCE] Calculated composite score: %.4f with confidence %.4f from %d valid scores", trace.Score, trace.RawConfidence, validCount)
+	return trace, nil
 }
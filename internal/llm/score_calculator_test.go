@@ -312,3 +312,49 @@ func TestDefaultScoreCalculator_InitializeMaps(t *testing.T) {
 	assert.Len(t, scoreMap, 3)
 	assert.Len(t, confMap, 3)
 }
+
+func TestDefaultScoreCalculator_TraceScore(t *testing.T) {
+	cfg := &CompositeScoreConfig{MinScore: -1.0, MaxScore: 1.0}
+	calc := &DefaultScoreCalculator{}
+	scores := []db.LLMScore{
+		{Model: "left", Score: -0.6, Metadata: `{"confidence": 0.9}`},
+		{Model: "right", Score: 0.4, Metadata: `{"confidence": 0.7}`},
+		{Model: "center", Score: 1.5, Metadata: `{"confidence": 0.8}`},
+		{Model: "center", Score: math.NaN(), Metadata: `{"confidence": 0.8}`},
+		{Model: "center", Score: 0.1, Metadata: `{}`},
+		{Model: "unknown", Score: 0.2, Metadata: `{"confidence": 0.8}`},
+	}
+
+	trace, err := calc.TraceScore(scores, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, AggregationFormulaMean, trace.Formula)
+	assert.Len(t, trace.Models, len(scores))
+	for i, step := range trace.Models {
+		if i < 2 {
+			assert.True(t, step.Included, step.Model)
+			assert.Equal(t, 1.0, step.Weight)
+			assert.Empty(t, step.Excluded)
+		} else {
+			assert.False(t, step.Included, step.Model)
+			assert.Zero(t, step.Weight)
+			assert.NotEmpty(t, step.Excluded)
+		}
+	}
+	assert.Contains(t, trace.Models[2].Excluded, "outside")
+	assert.Zero(t, trace.Models[3].Score, "NaN is reported as 0")
+	assert.Contains(t, trace.Models[4].Excluded, "confidence")
+	assert.Contains(t, trace.Models[5].Excluded, "configuration")
+	assert.InDelta(t, 2.0, trace.WeightTotal, 1e-9)
+	assert.InDelta(t, -0.2, trace.WeightedSum, 1e-9)
+	assert.InDelta(t, -0.1, trace.PreClampScore, 1e-9)
+	assert.InDelta(t, 0.8, trace.RawConfidence, 1e-9)
+
+	score, conf, err := calc.CalculateScore(scores, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, trace.Score, score)
+	assert.Equal(t, trace.RawConfidence, conf)
+
+	trace, err = calc.TraceScore(scores[4:], cfg)
+	assert.ErrorIs(t, err, ErrAllPerspectivesInvalid)
+	assert.NotEmpty(t, trace.Error)
+}
@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateArticleScoreReportsScoreChange(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "change.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	calculator := &MockRealCalculator{CalculatedScore: 0.1, CalculatedConfidence: 0.8}
+	sm := NewScoreManager(dbConn, NewCache(), calculator, NewProgressManager(time.Minute))
+	scores := []db.LLMScore{{Model: "center", Score: 0.1, Metadata: `{"confidence": 0.8}`}}
+	cfg := &CompositeScoreConfig{Models: []ModelConfig{{ModelName: "center", Perspective: "center"}}}
+
+	insert := func(url string, score, confidence interface{}) int64 {
+		res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, composite_score, confidence)
+			VALUES ('bbc', CURRENT_TIMESTAMP, ?, 't', 'c', ?, ?)`, url, score, confidence)
+		require.NoError(t, err)
+		id, err := res.LastInsertId()
+		require.NoError(t, err)
+		return id
+	}
+
+	t.Run("Rescored", func(t *testing.T) {
+		id := insert("https://example.com/c/1", -0.2, 0.6)
+		_, _, err := sm.UpdateArticleScore(id, scores, cfg)
+		require.NoError(t, err)
+
+		change := sm.GetProgress(id).Change
+		require.NotNil(t, change)
+		assert.Equal(t, &models.ScoreSnapshot{Score: -0.2, Confidence: 0.6, Label: "left"}, change.Previous)
+		assert.Equal(t, models.ScoreSnapshot{Score: 0.1, Confidence: 0.8, Label: "center"}, change.Current)
+		assert.InDelta(t, 0.3, *change.Delta, 1e-9)
+	})
+
+	t.Run("FirstScore", func(t *testing.T) {
+		id := insert("https://example.com/c/2", nil, nil)
+		_, _, err := sm.UpdateArticleScore(id, scores, cfg)
+		require.NoError(t, err)
+
+		change := sm.GetProgress(id).Change
+		require.NotNil(t, change)
+		assert.Nil(t, change.Previous)
+		assert.Nil(t, change.Delta)
+
+		encoded, err := json.Marshal(change)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"previous": null, "current": {"score": 0.1, "confidence": 0.8, "label": "center"}}`, string(encoded))
+	})
+}
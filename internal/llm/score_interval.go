@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"math"
+	"strings"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+)
+
+// scoreIntervalZ is the normal quantile used for the composite score interval (95%)
+const scoreIntervalZ = 1.96
+
+// ScoreInterval is a confidence interval for a composite score
+type ScoreInterval struct {
+	Low  float64 `json:"score_low"`
+	High float64 `json:"score_high"`
+	// Models is the number of per-model scores the interval was derived from
+	Models int `json:"interval_models"`
+	// Reliable is false when fewer than two models contributed, in which case the
+	// interval collapses to the composite score
+	Reliable bool `json:"interval_reliable"`
+}
+
+// CompositeScoreInterval returns composite ± z·s/√n, where s is the sample standard
+// deviation of the latest score of each model and n the number of models, clamped to
+// [-1, 1]. Ensemble and manual scores and scores outside [-1, 1] are ignored.
+func CompositeScoreInterval(composite float64, scores []db.LLMScore) ScoreInterval {
+	latest := make(map[string]db.LLMScore)
+	for _, s := range scores {
+		model := strings.ToLower(s.Model)
+		if model == "ensemble" || s.Model == db.ManualScoreModel {
+			continue
+		}
+		if math.IsNaN(s.Score) || s.Score < -1 || s.Score > 1 {
+			continue
+		}
+		if prev, ok := latest[model]; !ok || s.CreatedAt.After(prev.CreatedAt) {
+			latest[model] = s
+		}
+	}
+
+	interval := ScoreInterval{Low: composite, High: composite, Models: len(latest)}
+	if len(latest) < 2 {
+		return interval
+	}
+
+	var sum float64
+	for _, s := range latest {
+		sum += s.Score
+	}
+	mean := sum / float64(len(latest))
+	var squares float64
+	for _, s := range latest {
+		squares += (s.Score - mean) * (s.Score - mean)
+	}
+	stddev := math.Sqrt(squares / float64(len(latest)-1))
+	margin := scoreIntervalZ * stddev / math.Sqrt(float64(len(latest)))
+
+	interval.Low = math.Max(-1, composite-margin)
+	interval.High = math.Min(1, composite+margin)
+	interval.Reliable = true
+	return interval
+}
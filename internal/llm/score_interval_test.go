@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompositeScoreInterval(t *testing.T) {
+	now := time.Now()
+	scores := []db.LLMScore{
+		{Model: "left", Score: 0.9, CreatedAt: now.Add(-time.Hour)}, // superseded
+		{Model: "left", Score: -0.2, CreatedAt: now},
+		{Model: "center", Score: 0.0, CreatedAt: now},
+		{Model: "right", Score: 0.2, CreatedAt: now},
+		{Model: "ensemble", Score: 0.0, CreatedAt: now},
+		{Model: db.ManualScoreModel, Score: 1.0, CreatedAt: now},
+	}
+
+	interval := CompositeScoreInterval(0.0, scores)
+	margin := scoreIntervalZ * 0.2 / math.Sqrt(3) // sample stddev of -0.2, 0, 0.2 is 0.2
+	assert.Equal(t, 3, interval.Models)
+	assert.True(t, interval.Reliable)
+	assert.InDelta(t, -margin, interval.Low, 1e-9)
+	assert.InDelta(t, margin, interval.High, 1e-9)
+
+	t.Run("ClampedToScale", func(t *testing.T) {
+		interval := CompositeScoreInterval(0.95, []db.LLMScore{{Model: "a", Score: 0.5}, {Model: "b", Score: 1.0}})
+		assert.Equal(t, 1.0, interval.High)
+		assert.Less(t, interval.Low, 0.95)
+	})
+
+	t.Run("SingleModelIsUnreliable", func(t *testing.T) {
+		interval := CompositeScoreInterval(0.4, []db.LLMScore{{Model: "a", Score: 0.4}, {Model: "ensemble", Score: 0.4}})
+		assert.Equal(t, ScoreInterval{Low: 0.4, High: 0.4, Models: 1}, interval)
+	})
+}
@@ -1,13 +1,19 @@
 package llm
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"math"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/tracing"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -18,20 +24,72 @@ type ScoreManager struct {
 	cache       *Cache
 	calculator  ScoreCalculator
 	progressMgr *ProgressManager
+
+	jobsMu sync.Mutex
+	jobs   map[int64]*reanalysisJob // in-flight reanalysis jobs by article ID
+	// closing is set by Shutdown; jobs started afterwards are interrupted straight away
+	closing bool
+	// idle is closed once no jobs are left after Shutdown began
+	idle chan struct{}
+	// interrupted holds the articles whose jobs Shutdown stopped
+	interrupted map[int64]bool
+
+	slotsMu       sync.Mutex
+	maxConcurrent int          // cap on jobs scoring at once; 0 means no cap
+	active        int          // jobs holding a slot
+	queue         []*jobWaiter // jobs waiting for a slot, oldest first
+}
+
+// reanalysisJob is a running reanalysis that can be cancelled
+type reanalysisJob struct {
+	cancel context.CancelCauseFunc
 }
 
 // NewScoreManager creates a new score manager with dependencies
 func NewScoreManager(db *sqlx.DB, cache *Cache, calculator ScoreCalculator, progressMgr *ProgressManager) *ScoreManager {
 	return &ScoreManager{
-		db:          db,
-		cache:       cache,
-		calculator:  calculator,
-		progressMgr: progressMgr,
+		db:            db,
+		cache:         cache,
+		calculator:    calculator,
+		progressMgr:   progressMgr,
+		jobs:          make(map[int64]*reanalysisJob),
+		maxConcurrent: DefaultMaxConcurrentJobs,
 	}
 }
 
 // UpdateArticleScore computes and stores a composite score for an article based on LLM scores
-func (sm *ScoreManager) UpdateArticleScore(articleID int64, scores []db.LLMScore, cfg *CompositeScoreConfig) (float64, float64, error) {
+func (sm *ScoreManager) UpdateArticleScore(articleID int64, scores []db.LLMScore, cfg *CompositeScoreConfig) (score float64, confidence float64, err error) {
+	return sm.UpdateArticleScoreContext(context.Background(), articleID, scores, cfg)
+}
+
+// UpdateArticleScoreContext is UpdateArticleScore traced as part of ctx
+func (sm *ScoreManager) UpdateArticleScoreContext(ctx context.Context, articleID int64, scores []db.LLMScore, cfg *CompositeScoreConfig) (score float64, confidence float64, err error) {
+	score, confidence, _, _, err = sm.updateArticleScore(ctx, articleID, scores, cfg, db.ScoreVersionAnalysis)
+	return score, confidence, err
+}
+
+// compositeDetails is how a stored composite was derived, for its ensemble metadata
+type compositeDetails struct {
+	sourceTrust
+	// Calculator names the calculator whose result was kept when several ran
+	Calculator string
+}
+
+// addTo records the details in an ensemble score's final aggregation
+func (d compositeDetails) addTo(finalAggregation map[string]any) {
+	d.sourceTrust.addTo(finalAggregation)
+	if d.Calculator != "" {
+		finalAggregation["calculator"] = d.Calculator
+	}
+}
+
+// updateArticleScore is UpdateArticleScore recording the composite in the score history
+// as the given kind. It returns the composite's history version, 0 if it was not recorded,
+// how the source trust weight scaled the confidence and which calculator was chosen.
+func (sm *ScoreManager) updateArticleScore(ctx context.Context, articleID int64, scores []db.LLMScore, cfg *CompositeScoreConfig, kind string) (score float64, confidence float64, version int, details compositeDetails, err error) {
+	ctx, span := tracing.Start(ctx, "ScoreManager.UpdateArticleScore", tracing.ArticleID(articleID))
+	defer func() { tracing.End(span, err) }()
+
 	// First, check if all responses have zero confidence
 	if allZeros, errZeroConf := checkForAllZeroResponses(scores); allZeros {
 		log.Printf("[ERROR] ArticleID %d: All LLMs returned zero confidence - this is a serious error: %v", articleID, errZeroConf)
@@ -49,12 +107,20 @@ func (sm *ScoreManager) UpdateArticleScore(articleID int64, scores []db.LLMScore
 				"after zero confidence error: %v", articleID, models.ArticleStatusFailedZeroConf, dbErr)
 		}
 		// Return the error without modifying the score
-		return 0, 0, fmt.Errorf("all LLMs returned zero confidence - this indicates a serious issue with the LLM responses: %w", errZeroConf)
+		return 0, 0, 0, compositeDetails{}, fmt.Errorf("all LLMs returned zero confidence - this indicates a serious issue with the LLM responses: %w", errZeroConf)
+	}
+
+	// Refuse to produce a composite from fewer models than configured. The default of
+	// one model leaves the checks to the calculator.
+	if required := cfg.minModelsForComposite(); required > 1 {
+		if valid := countValidModelScores(scores, cfg); valid < required {
+			return 0, 0, 0, compositeDetails{}, sm.failInsufficientModels(articleID, valid, required)
+		}
 	}
 
 	// Use the score calculator to compute the score and confidence, passing the config
 	cfg.ArticleIDForDebug = articleID // Set the ID for logging within calculation
-	compositeScore, confidence, errCalc := sm.calculator.CalculateScore(scores, cfg)
+	compositeScore, confidence, chosen, errCalc := calculateComposite(sm.calculator, scores, cfg)
 	cfg.ArticleIDForDebug = 0 // Reset after use (optional, good practice)
 	if errCalc != nil {
 		// Check for the specific "all invalid" error
@@ -81,7 +147,7 @@ func (sm *ScoreManager) UpdateArticleScore(articleID int64, scores []db.LLMScore
 			}
 			// IMPORTANT: Do NOT proceed to update the DB score. Return the error.
 			log.Printf("[DEBUG] ScoreManager: ArticleID %d: Returning ErrAllPerspectivesInvalid error now.", articleID)
-			return 0, 0, errCalc
+			return 0, 0, 0, compositeDetails{}, errCalc
 		} else {
 			// Handle other, unexpected errors from CalculateScore
 			log.Printf("[ERROR] ScoreManager: ArticleID %d: Unexpected error calculating score: %v. Score will not be updated.", articleID, errCalc)
@@ -101,12 +167,22 @@ func (sm *ScoreManager) UpdateArticleScore(articleID int64, scores []db.LLMScore
 				log.Printf("[ERROR] ScoreManager: ArticleID %d: Failed to update article status to %s "+
 					"after calculation error: %v", articleID, models.ArticleStatusFailedError, dbErr)
 			}
-			return 0, 0, errCalc
+			return 0, 0, 0, compositeDetails{}, errCalc
 		}
 	}
 
+	// The source's trust weight changes how confident the composite is, never the score
+	confidence, details.sourceTrust = applySourceTrust(sm.db, articleID, confidence)
+	details.Calculator = chosen
+
+	interval := CompositeScoreInterval(compositeScore, scores)
+	log.Printf("[ScoreManager] ArticleID=%d Score=%.3f Interval=[%.3f, %.3f] Models=%d Reliable=%t Calculator=%q",
+		articleID, compositeScore, interval.Low, interval.High, interval.Models, interval.Reliable, chosen)
+
+	previous, previousKnown := sm.previousScore(articleID)
+
 	// Update the article score in the database
-	errDbUpdate := db.UpdateArticleScoreLLM(sm.db, articleID, compositeScore, confidence)
+	errDbUpdate := db.UpdateArticleScoreLLMContext(ctx, sm.db, articleID, compositeScore, confidence)
 	if errDbUpdate != nil {
 		log.Printf("[ERROR] Failed to update article score: %v", errDbUpdate)
 		sm.SetProgress(articleID, &models.ProgressState{
@@ -121,7 +197,7 @@ func (sm *ScoreManager) UpdateArticleScore(articleID int64, scores []db.LLMScore
 			log.Printf("[ERROR] ScoreManager: ArticleID %d: Failed to update article status to %s "+
 				"after DB score update error: %v", articleID, models.ArticleStatusFailedError, dbStatusErr)
 		}
-		return 0, 0, fmt.Errorf("failed to store score: %w", errDbUpdate)
+		return 0, 0, 0, compositeDetails{}, fmt.Errorf("failed to store score: %w", errDbUpdate)
 	}
 
 	// If score update was successful, also update status to Scored
@@ -131,6 +207,12 @@ func (sm *ScoreManager) UpdateArticleScore(articleID int64, scores []db.LLMScore
 		// Note: The main operation (score update) succeeded, so we don't return this error, just log it.
 	}
 
+	// Keep every composite so score stability across versions can be measured
+	version, errHistory := db.RecordScoreVersion(sm.db, articleID, compositeScore, confidence, kind)
+	if errHistory != nil {
+		log.Printf("[WARN] ScoreManager: ArticleID %d: Failed to record score history: %v", articleID, errHistory)
+	}
+
 	// Invalidate cache
 	sm.InvalidateScoreCache(articleID)
 
@@ -143,11 +225,14 @@ func (sm *ScoreManager) UpdateArticleScore(articleID int64, scores []db.LLMScore
 		FinalScore:  &compositeScore,
 		LastUpdated: time.Now().Unix(),
 	}
+	if previousKnown {
+		successState.Change = models.NewScoreChange(previous, models.NewScoreSnapshot(compositeScore, confidence))
+	}
 	if sm.progressMgr != nil {
 		sm.progressMgr.SetProgress(articleID, &successState)
 	}
 	log.Printf("[INFO] ScoreManager: ArticleID %d: Score updated successfully, status set to %s.", articleID, models.ArticleStatusScored)
-	return compositeScore, confidence, nil
+	return compositeScore, confidence, version, details, nil
 }
 
 // InvalidateScoreCache invalidates all score-related caches for an article
@@ -188,6 +273,21 @@ func (sm *ScoreManager) SetProgress(articleID int64, state *models.ProgressState
 	}
 }
 
+// GetProgressWithEventID proxies to ProgressManager
+func (sm *ScoreManager) GetProgressWithEventID(articleID int64) (*models.ProgressState, int64) {
+	if sm.progressMgr != nil {
+		return sm.progressMgr.GetProgressWithEventID(articleID)
+	}
+	return nil, 0
+}
+
+// MarkProgressObserved proxies to ProgressManager
+func (sm *ScoreManager) MarkProgressObserved(articleID int64) {
+	if sm.progressMgr != nil {
+		sm.progressMgr.MarkObserved(articleID)
+	}
+}
+
 // GetProgress proxies to ProgressManager
 func (sm *ScoreManager) GetProgress(articleID int64) *models.ProgressState {
 	if sm.progressMgr != nil {
@@ -195,3 +295,183 @@ func (sm *ScoreManager) GetProgress(articleID int64) *models.ProgressState {
 	}
 	return nil
 }
+
+// ErrJobInProgress is returned by TryStartJob when the article already has a queued or
+// running reanalysis
+var ErrJobInProgress = errors.New("a reanalysis of this article is already queued or running")
+
+// TryStartJob registers an in-flight reanalysis for an article and returns the context
+// it must run under, plus a function to call once the job has finished. It holds the
+// article's lock: it fails with ErrJobInProgress while another job for the article is
+// registered, so two reanalyses of one article never race on its score. The lock is
+// released by the returned function, which must be called however the job ends.
+func (sm *ScoreManager) TryStartJob(parent context.Context, articleID int64) (context.Context, func(), error) {
+	sm.jobsMu.Lock()
+	if sm.jobs == nil {
+		sm.jobs = make(map[int64]*reanalysisJob)
+	}
+	if _, running := sm.jobs[articleID]; running {
+		sm.jobsMu.Unlock()
+		return nil, nil, ErrJobInProgress
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	job := &reanalysisJob{cancel: cancel}
+	sm.jobs[articleID] = job
+	if sm.closing {
+		sm.interrupted[articleID] = true
+		cancel(ErrShuttingDown)
+	}
+	sm.jobsMu.Unlock()
+
+	done := func() {
+		sm.jobsMu.Lock()
+		if sm.jobs[articleID] == job {
+			delete(sm.jobs, articleID)
+		}
+		if sm.closing && len(sm.jobs) == 0 && sm.idle != nil {
+			close(sm.idle)
+			sm.idle = nil
+		}
+		sm.jobsMu.Unlock()
+		cancel(nil)
+	}
+	return ctx, done, nil
+}
+
+// CancelJob cancels the in-flight reanalysis for an article, reporting whether one was running.
+// The job stops at its next checkpoint and discards any scores computed so far.
+func (sm *ScoreManager) CancelJob(articleID int64) bool {
+	sm.jobsMu.Lock()
+	job, ok := sm.jobs[articleID]
+	sm.jobsMu.Unlock()
+	if !ok {
+		return false
+	}
+	job.cancel(nil)
+	return true
+}
+
+// HasJob reports whether a reanalysis of the article is queued or running
+func (sm *ScoreManager) HasJob(articleID int64) bool {
+	sm.jobsMu.Lock()
+	defer sm.jobsMu.Unlock()
+	_, ok := sm.jobs[articleID]
+	return ok
+}
+
+// MarkCancelled records the cancelled terminal state for an article's reanalysis. Jobs
+// stopped by Shutdown are recorded as interrupted instead.
+func (sm *ScoreManager) MarkCancelled(articleID int64) {
+	if sm.wasInterrupted(articleID) {
+		sm.markInterrupted(articleID)
+		return
+	}
+	sm.SetProgress(articleID, &models.ProgressState{
+		Status:      ProgressStatusCancelled,
+		Step:        "Cancelled",
+		Message:     "Reanalysis cancelled; no scores were saved",
+		Percent:     100,
+		LastUpdated: time.Now().Unix(),
+	})
+}
+
+// previousScore captures the stored score before it is overwritten. The snapshot is
+// nil when the article had no score; false means the score could not be read.
+func (sm *ScoreManager) previousScore(articleID int64) (*models.ScoreSnapshot, bool) {
+	if sm.db == nil {
+		return nil, false
+	}
+	score, confidence, err := db.FetchArticleScore(sm.db, articleID)
+	if err != nil {
+		log.Printf("[WARN] ScoreManager: ArticleID %d: Could not read previous score: %v", articleID, err)
+		return nil, false
+	}
+	if score == nil {
+		return nil, true
+	}
+	snapshot := models.NewScoreSnapshot(*score, 0)
+	if confidence != nil {
+		snapshot.Confidence = *confidence
+	}
+	return &snapshot, true
+}
+
+// failInsufficientModels records that too few models returned a valid score
+func (sm *ScoreManager) failInsufficientModels(articleID int64, valid, required int) error {
+	err := fmt.Errorf("%w: %d of %d required", ErrInsufficientModels, valid, required)
+	log.Printf("[ERROR] ScoreManager: ArticleID %d: %v. Score will not be updated.", articleID, err)
+	sm.SetProgress(articleID, &models.ProgressState{
+		Step:        "Error",
+		Message:     err.Error(),
+		Status:      "Error",
+		Error:       err.Error(),
+		Percent:     100,
+		LastUpdated: time.Now().Unix(),
+	})
+	if dbErr := db.UpdateArticleStatus(sm.db, articleID, models.ArticleStatusFailedTooFewModels); dbErr != nil {
+		log.Printf("[ERROR] ScoreManager: ArticleID %d: Failed to update article status to %s "+
+			"after insufficient models error: %v", articleID, models.ArticleStatusFailedTooFewModels, dbErr)
+	}
+	return err
+}
+
+// countValidModelScores counts the configured models whose latest score is valid. Models
+// the calculators would not map to a perspective do not count, nor do older scores of a
+// model that has a newer one.
+func countValidModelScores(scores []db.LLMScore, cfg *CompositeScoreConfig) int {
+	latest := make(map[string]db.LLMScore)
+	for _, s := range scores {
+		if !isModelScore(s) || MapModelToPerspective(s.Model, cfg) == "" {
+			continue
+		}
+		key := strings.ToLower(s.Model)
+		if prev, ok := latest[key]; ok && !newerModelScore(s, prev) {
+			continue
+		}
+		latest[key] = s
+	}
+	valid := 0
+	for _, s := range latest {
+		if isValidModelScore(s, cfg) {
+			valid++
+		}
+	}
+	return valid
+}
+
+// newerModelScore reports whether a is a later score than b: a higher version, then a
+// later creation time, then a higher ID
+func newerModelScore(a, b db.LLMScore) bool {
+	if a.Version != b.Version {
+		return a.Version > b.Version
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID > b.ID
+}
+
+// isValidModelScore reports whether s is a per-model score (not the ensemble or a
+// manual score) with a finite score inside the configured range and a positive
+// confidence of at least min_confidence
+func isValidModelScore(s db.LLMScore, cfg *CompositeScoreConfig) bool {
+	if !isModelScore(s) {
+		return false
+	}
+	if math.IsNaN(s.Score) || math.IsInf(s.Score, 0) {
+		return false
+	}
+	if cfg != nil && cfg.MaxScore > cfg.MinScore && (s.Score < cfg.MinScore || s.Score > cfg.MaxScore) {
+		return false
+	}
+	var meta struct {
+		Confidence float64 `json:"confidence"`
+	}
+	if err := json.Unmarshal([]byte(s.Metadata), &meta); err != nil || meta.Confidence <= 0 {
+		return false
+	}
+	if cfg != nil && meta.Confidence < cfg.MinConfidence {
+		return false
+	}
+	return true
+}
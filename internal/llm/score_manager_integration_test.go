@@ -314,3 +314,95 @@ func TestScoreManagerIntegrationCalculateScoreError(t *testing.T) {
 	err = sqlMock.ExpectationsWereMet()
 	assert.NoError(t, err, "DB expectations not met")
 }
+
+// TestIntegrationUpdateArticleScore_MinModelsForComposite tests the minimum model count at its threshold
+func TestIntegrationUpdateArticleScore_MinModelsForComposite(t *testing.T) {
+	config := &CompositeScoreConfig{
+		Models: []ModelConfig{
+			{ModelName: "left", Perspective: "left"},
+			{ModelName: "center", Perspective: "center"},
+			{ModelName: "right", Perspective: "right"},
+		},
+		MinScore:              -1.0,
+		MaxScore:              1.0,
+		MinModelsForComposite: 2,
+	}
+	const articleID = int64(789)
+	twoValid := []db.LLMScore{
+		{ArticleID: articleID, Model: "left", Score: -0.4, Metadata: `{"confidence": 0.9}`},
+		{ArticleID: articleID, Model: "center", Score: 0.2, Metadata: `{"confidence": 0.7}`},
+		{ArticleID: articleID, Model: "right", Score: 5, Metadata: `{"confidence": 0.8}`}, // out of range
+		{ArticleID: articleID, Model: "ensemble", Score: 0.1, Metadata: `{"confidence": 0.9}`},
+	}
+
+	newManager := func(t *testing.T) (*ScoreManager, sqlmock.Sqlmock) {
+		mockDB, sqlMock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = mockDB.Close() })
+		calculator := &MockRealCalculator{CalculatedScore: 0.1, CalculatedConfidence: 0.8}
+		return NewScoreManager(sqlx.NewDb(mockDB, "sqlmock"), NewCache(), calculator, NewProgressManager(time.Minute)), sqlMock
+	}
+
+	t.Run("AtThreshold", func(t *testing.T) {
+		sm, sqlMock := newManager(t)
+		sqlMock.ExpectExec("UPDATE articles SET composite_score = \\?, confidence = \\?, score_source = 'llm' WHERE id = \\?").
+			WithArgs(0.1, 0.8, articleID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		sqlMock.ExpectExec("UPDATE articles SET status = \\? WHERE id = \\?").
+			WithArgs(models.ArticleStatusScored, articleID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		score, _, err := sm.UpdateArticleScore(articleID, twoValid, config)
+		require.NoError(t, err)
+		assert.Equal(t, 0.1, score)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("BelowThreshold", func(t *testing.T) {
+		sm, sqlMock := newManager(t)
+		sqlMock.ExpectExec("UPDATE articles SET status = \\? WHERE id = \\?").
+			WithArgs(models.ArticleStatusFailedTooFewModels, articleID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		oneValid := append([]db.LLMScore{}, twoValid...)
+		oneValid[1].Metadata = `{"confidence": 0}`
+		_, _, err := sm.UpdateArticleScore(articleID, oneValid, config)
+		assert.ErrorIs(t, err, ErrInsufficientModels)
+		assert.Contains(t, err.Error(), "1 of 2 required")
+		assert.NoError(t, sqlMock.ExpectationsWereMet(), "no score is stored")
+		assert.Equal(t, "Error", sm.GetProgress(articleID).Status)
+	})
+
+	t.Run("OnlyLatestConfiguredScoresCount", func(t *testing.T) {
+		sm, sqlMock := newManager(t)
+		sqlMock.ExpectExec("UPDATE articles SET status = \\? WHERE id = \\?").
+			WithArgs(models.ArticleStatusFailedTooFewModels, articleID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		minConf := *config
+		minConf.MinConfidence = 0.1
+		scores := []db.LLMScore{
+			{ArticleID: articleID, Model: "left", Score: -0.4, Metadata: `{"confidence": 0.9}`},
+			// An older valid score does not count once the model's latest one failed
+			{ArticleID: articleID, Model: "center", Score: 0.2, Metadata: `{"confidence": 0.7}`, Version: 1},
+			{ArticleID: articleID, Model: "center", Score: 0.2, Metadata: `{"confidence": 0}`, Version: 2},
+			{ArticleID: articleID, Model: "right", Score: 0.3, Metadata: `{"confidence": 0.05}`}, // below min_confidence
+			{ArticleID: articleID, Model: "other-model", Score: 0.1, Metadata: `{"confidence": 0.9}`},
+		}
+		_, _, err := sm.UpdateArticleScore(articleID, scores, &minConf)
+		assert.ErrorIs(t, err, ErrInsufficientModels)
+		assert.Contains(t, err.Error(), "1 of 2 required")
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("DefaultAllowsSingleModel", func(t *testing.T) {
+		sm, sqlMock := newManager(t)
+		sqlMock.ExpectExec("UPDATE articles SET composite_score").WillReturnResult(sqlmock.NewResult(1, 1))
+		sqlMock.ExpectExec("UPDATE articles SET status").WillReturnResult(sqlmock.NewResult(1, 1))
+
+		defaults := *config
+		defaults.MinModelsForComposite = 0
+		_, _, err := sm.UpdateArticleScore(articleID, twoValid[:1], &defaults)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,169 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/tracing"
+)
+
+// selfCheckArticle is the built-in sample scored by SelfCheck. It is short to keep the
+// check cheap and is never stored.
+var selfCheckArticle = db.Article{
+	Title:  "City council approves budget for road repairs",
+	Source: "self-check",
+	Content: "The city council voted 7-2 on Tuesday to approve a budget that allocates " +
+		"$4 million to road repairs next year. Supporters said the spending was overdue, " +
+		"while two members argued the money should come from reserves instead of a fee increase.",
+}
+
+// Self-check statuses for models and for the API key
+const (
+	SelfCheckOK      = "ok"
+	SelfCheckError   = "error"
+	KeyStatusValid   = "valid"
+	KeyStatusInvalid = "invalid"
+	KeyStatusUnknown = "unknown"
+)
+
+// ModelScoreResult is the outcome of scoring content with one model
+type ModelScoreResult struct {
+	Model       string   `json:"model"`
+	Perspective string   `json:"perspective"`
+	Status      string   `json:"status"`
+	Score       *float64 `json:"score,omitempty"`
+	Confidence  *float64 `json:"confidence,omitempty"`
+	Label       string   `json:"label,omitempty"`
+	LatencyMs   int64    `json:"latency_ms"`
+	Error       string   `json:"error,omitempty"`
+	ErrorType   string   `json:"error_type,omitempty"`
+	StatusCode  int      `json:"status_code,omitempty"`
+}
+
+// SelfCheckResult reports whether the scoring pipeline works end to end
+type SelfCheckResult struct {
+	Healthy bool `json:"healthy"`
+	// KeyStatus is "invalid" when any model rejected the API key, "valid" when any
+	// model answered and "unknown" otherwise
+	KeyStatus      string             `json:"key_status"`
+	CompositeScore *float64           `json:"composite_score,omitempty"`
+	Confidence     *float64           `json:"confidence,omitempty"`
+	CompositeError string             `json:"composite_error,omitempty"`
+	Models         []ModelScoreResult `json:"models"`
+	DurationMs     int64              `json:"duration_ms"`
+}
+
+// SelfCheck scores a built-in sample article with every configured model in parallel
+// and combines the results into a composite score. Nothing is cached or persisted.
+func (c *LLMClient) SelfCheck(ctx context.Context) (result *SelfCheckResult, err error) {
+	cfg := c.GetConfig()
+	ctx, span := tracing.Start(ctx, "llm.SelfCheck")
+	defer func() { tracing.End(span, err) }()
+
+	if cfg == nil || len(cfg.Models) == 0 {
+		return nil, fmt.Errorf("LLMClient config is nil or has no models defined")
+	}
+
+	start := time.Now()
+	checks := c.scoreWithEachModel(ctx, selfCheckArticle.Content)
+
+	result = &SelfCheckResult{Models: checks, KeyStatus: KeyStatusUnknown}
+	for _, check := range checks {
+		switch {
+		case check.ErrorType == string(ErrTypeAuthentication) || check.StatusCode == http.StatusUnauthorized:
+			result.KeyStatus = KeyStatusInvalid
+		case check.Status == SelfCheckOK && result.KeyStatus == KeyStatusUnknown:
+			result.KeyStatus = KeyStatusValid
+		}
+	}
+
+	scored := 0
+	if score, confidence, n, err := c.compositeOfResults(checks); err != nil {
+		result.CompositeError = err.Error()
+	} else {
+		result.CompositeScore, result.Confidence, scored = &score, &confidence, n
+	}
+
+	result.Healthy = result.CompositeError == "" && scored == len(checks)
+	result.DurationMs = time.Since(start).Milliseconds()
+	log.Printf("[SelfCheck] Healthy=%t KeyStatus=%s Models=%d/%d Duration=%dms",
+		result.Healthy, result.KeyStatus, scored, len(checks), result.DurationMs)
+	return result, nil
+}
+
+// scoreWithEachModel scores content with every configured model in parallel,
+// bypassing the cache
+func (c *LLMClient) scoreWithEachModel(ctx context.Context, content string) []ModelScoreResult {
+	cfg := c.GetConfig()
+	results := make([]ModelScoreResult, len(cfg.Models))
+	var wg sync.WaitGroup
+	for i := range cfg.Models {
+		wg.Add(1)
+		go func(i int, modelCfg ModelConfig) {
+			defer wg.Done()
+			results[i] = c.scoreWithModel(ctx, &modelCfg, content)
+		}(i, cfg.Models[i])
+	}
+	wg.Wait()
+	return results
+}
+
+// scoreWithModel scores content with a single model
+func (c *LLMClient) scoreWithModel(ctx context.Context, modelCfg *ModelConfig, content string) ModelScoreResult {
+	cfg := c.GetConfig()
+	result := ModelScoreResult{Model: modelCfg.ModelName, Perspective: modelCfg.Perspective}
+	if modelCfg.ModelName == "" {
+		result.Status, result.Error = SelfCheckError, "model name is empty"
+		return result
+	}
+
+	prompt := cfg.withSampling(defaultPromptVariant(modelCfg))
+	start := time.Now()
+	score, _, confidence, _, err := c.callLLM(ctx, 0, modelCfg.ModelName, prompt, content)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Status, result.Error = SelfCheckError, err.Error()
+		var apiErr LLMAPIError
+		if errors.As(err, &apiErr) {
+			result.ErrorType, result.StatusCode = string(apiErr.ErrorType), apiErr.StatusCode
+		}
+		return result
+	}
+
+	result.Status = SelfCheckOK
+	result.Score, result.Confidence = &score, &confidence
+	result.Label = models.BiasLabel(score)
+	return result
+}
+
+// compositeOfResults combines the successful model results into a composite score,
+// returning it with its confidence and the number of models that contributed
+func (c *LLMClient) compositeOfResults(results []ModelScoreResult) (float64, float64, int, error) {
+	cfg := c.GetConfig()
+	scores := make([]db.LLMScore, 0, len(results))
+	for _, r := range results {
+		if r.Status != SelfCheckOK {
+			continue
+		}
+		scores = append(scores, db.LLMScore{
+			Model:    r.Model,
+			Score:    *r.Score,
+			Metadata: fmt.Sprintf(`{"confidence": %f}`, *r.Confidence),
+		})
+	}
+	if len(scores) == 0 {
+		return 0, 0, 0, fmt.Errorf("no model returned a score: %w", ErrAllPerspectivesInvalid)
+	}
+	score, confidence, err := ComputeCompositeScoreWithConfidence(scores, cfg)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return score, confidence, len(scores), nil
+}
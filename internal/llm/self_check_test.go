@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func selfCheckConfig() *CompositeScoreConfig {
+	return &CompositeScoreConfig{
+		Formula:          "average",
+		ConfidenceMethod: "count_valid",
+		MinScore:         -1.0,
+		MaxScore:         1.0,
+		HandleInvalid:    "ignore",
+		Models: []ModelConfig{
+			{ModelName: "left-model", Perspective: "left", Weight: 1.0},
+			{ModelName: "center-model", Perspective: "center", Weight: 1.0},
+			{ModelName: "right-model", Perspective: "right", Weight: 1.0},
+		},
+	}
+}
+
+func TestSelfCheck(t *testing.T) {
+	t.Run("Healthy", func(t *testing.T) {
+		svc := NewFixtureLLMService(Fixture{
+			Default: FixtureResponse{Score: 0.1, Confidence: 0.9},
+			Rules: []FixtureRule{
+				{Model: "left-model", Responses: []FixtureResponse{{Score: -0.4, Confidence: 0.8}}},
+				{Model: "right-model", Responses: []FixtureResponse{{Score: 0.6, Confidence: 0.8}}},
+			},
+		})
+		client := NewLLMClientWithService(nil, svc, selfCheckConfig())
+
+		result, err := client.SelfCheck(context.Background())
+		require.NoError(t, err)
+		assert.True(t, result.Healthy)
+		assert.Equal(t, KeyStatusValid, result.KeyStatus)
+		require.Len(t, result.Models, 3)
+		for _, m := range result.Models {
+			assert.Equal(t, SelfCheckOK, m.Status, m.Model)
+		}
+		assert.Equal(t, -0.4, *result.Models[0].Score)
+		require.NotNil(t, result.CompositeScore)
+		assert.InDelta(t, 0.1, *result.CompositeScore, 1e-9)
+		assert.Equal(t, 3, svc.Calls(), "each model is called once and nothing comes from the cache")
+	})
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		svc := NewFixtureLLMService(Fixture{
+			Default: FixtureResponse{Score: 0.1, Confidence: 0.9},
+			Rules: []FixtureRule{
+				{Model: "center-model", Responses: []FixtureResponse{{Error: "invalid key", StatusCode: 401}}},
+			},
+		})
+		client := NewLLMClientWithService(nil, svc, selfCheckConfig())
+
+		result, err := client.SelfCheck(context.Background())
+		require.NoError(t, err)
+		assert.False(t, result.Healthy)
+		assert.Equal(t, KeyStatusInvalid, result.KeyStatus)
+		center := result.Models[1]
+		assert.Equal(t, SelfCheckError, center.Status)
+		assert.Equal(t, string(ErrTypeAuthentication), center.ErrorType)
+		assert.Equal(t, 401, center.StatusCode)
+		assert.Nil(t, center.Score)
+		assert.NotNil(t, result.CompositeScore, "the reachable models still produce a composite")
+	})
+
+	t.Run("NoModels", func(t *testing.T) {
+		client := NewLLMClientWithService(nil, NewFixtureLLMService(Fixture{}), &CompositeScoreConfig{})
+		_, err := client.SelfCheck(context.Background())
+		assert.Error(t, err)
+	})
+}
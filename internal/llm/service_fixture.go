@@ -0,0 +1,180 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+)
+
+// FixtureResponse is one scripted reply from a FixtureLLMService
+type FixtureResponse struct {
+	Score       float64 `json:"score"`
+	Confidence  float64 `json:"confidence"`
+	Explanation string  `json:"explanation,omitempty"`
+	// Error makes the call fail with this message. With StatusCode set the error is an
+	// LLMAPIError, so 429 simulates a rate limit, 401 an invalid key and 402 no credits.
+	Error      string `json:"error,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	RetryAfter int    `json:"retry_after,omitempty"`
+	// DelayMs delays the reply, returning early if the context is cancelled
+	DelayMs int `json:"delay_ms,omitempty"`
+}
+
+// FixtureRule matches calls and replies with its responses in order, repeating the
+// last one once the sequence is exhausted. Unset criteria match every call.
+type FixtureRule struct {
+	ArticleID       int64             `json:"article_id,omitempty"`
+	Model           string            `json:"model,omitempty"`
+	ContentContains string            `json:"content_contains,omitempty"`
+	Responses       []FixtureResponse `json:"responses"`
+}
+
+// Fixture scripts a FixtureLLMService. Rules are checked in order; calls matching no
+// rule get the default response.
+type Fixture struct {
+	Default FixtureResponse `json:"default"`
+	Rules   []FixtureRule   `json:"rules"`
+}
+
+// FixtureLLMService is an in-process LLMService that replies from a fixture instead
+// of calling a model, for deterministic tests and offline development
+type FixtureLLMService struct {
+	fixture Fixture
+
+	mu    sync.Mutex
+	next  []int // next response index per rule
+	calls int
+}
+
+// NewFixtureLLMService creates a service replying from the given fixture
+func NewFixtureLLMService(fixture Fixture) *FixtureLLMService {
+	return &FixtureLLMService{
+		fixture: fixture,
+		next:    make([]int, len(fixture.Rules)),
+	}
+}
+
+// LoadFixtureLLMService creates a service from a JSON fixture file
+func LoadFixtureLLMService(path string) (*FixtureLLMService, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - fixture path is supplied by tests or developers
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LLM fixture %s: %w", path, err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM fixture %s: %w", path, err)
+	}
+	for i, rule := range fixture.Rules {
+		if len(rule.Responses) == 0 {
+			return nil, fmt.Errorf("LLM fixture %s: rule %d has no responses", path, i)
+		}
+	}
+	return NewFixtureLLMService(fixture), nil
+}
+
+// ScoreContent implements LLMService
+func (s *FixtureLLMService) ScoreContent(ctx context.Context, pv PromptVariant, art *db.Article) (float64, float64, error) {
+	score, confidence, _, _, err := s.ScoreWithExplanation(ctx, pv, art)
+	return score, confidence, err
+}
+
+// ScoreWithExplanation implements ExplainingLLMService, so the fixture can also drive
+// single-model and ensemble scoring
+func (s *FixtureLLMService) ScoreWithExplanation(ctx context.Context, pv PromptVariant, art *db.Article) (float64, float64, string, string, error) {
+	resp := s.respond(pv.Model, art)
+
+	if resp.DelayMs > 0 {
+		select {
+		case <-time.After(time.Duration(resp.DelayMs) * time.Millisecond):
+		case <-ctx.Done():
+			return 0, 0, "", "", ctx.Err()
+		}
+	}
+
+	if resp.Error != "" {
+		if resp.StatusCode == 0 {
+			return 0, 0, "", "", errors.New(resp.Error)
+		}
+		return 0, 0, "", "", LLMAPIError{
+			Message:    resp.Error,
+			StatusCode: resp.StatusCode,
+			ErrorType:  fixtureErrorType(resp.StatusCode),
+			RetryAfter: resp.RetryAfter,
+		}
+	}
+
+	raw, err := json.Marshal(map[string]interface{}{
+		"score":       resp.Score,
+		"explanation": resp.Explanation,
+		"confidence":  resp.Confidence,
+	})
+	if err != nil {
+		return 0, 0, "", "", err
+	}
+	return resp.Score, resp.Confidence, resp.Explanation, string(raw), nil
+}
+
+// Calls returns how many requests the service has answered
+func (s *FixtureLLMService) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// respond picks the scripted reply for a call and advances its rule's sequence
+func (s *FixtureLLMService) respond(model string, art *db.Article) FixtureResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+
+	for i, rule := range s.fixture.Rules {
+		if !rule.matches(model, art) || len(rule.Responses) == 0 {
+			continue
+		}
+		idx := s.next[i]
+		if idx < len(rule.Responses)-1 {
+			s.next[i]++
+		}
+		return rule.Responses[idx]
+	}
+	return s.fixture.Default
+}
+
+// matches reports whether every criterion set on the rule matches the call
+func (r FixtureRule) matches(model string, art *db.Article) bool {
+	if r.Model != "" && r.Model != model {
+		return false
+	}
+	if art == nil {
+		return r.ArticleID == 0 && r.ContentContains == ""
+	}
+	if r.ArticleID != 0 && r.ArticleID != art.ID {
+		return false
+	}
+	if r.ContentContains != "" && !strings.Contains(art.Content, r.ContentContains) {
+		return false
+	}
+	return true
+}
+
+// fixtureErrorType maps a simulated HTTP status to the matching OpenRouter error type
+func fixtureErrorType(status int) OpenRouterErrorType {
+	switch status {
+	case http.StatusTooManyRequests:
+		return ErrTypeRateLimit
+	case http.StatusUnauthorized:
+		return ErrTypeAuthentication
+	case http.StatusPaymentRequired:
+		return ErrTypeCredits
+	default:
+		return ErrTypeUnknown
+	}
+}
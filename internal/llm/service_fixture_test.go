@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixtureLLMServiceRules(t *testing.T) {
+	svc := NewFixtureLLMService(Fixture{
+		Default: FixtureResponse{Score: 0, Confidence: 0.9},
+		Rules: []FixtureRule{
+			{ArticleID: 7, Responses: []FixtureResponse{
+				{Error: "Rate limit exceeded", StatusCode: 429, RetryAfter: 3},
+				{Score: 0.4, Confidence: 0.8},
+			}},
+			{ContentContains: "offline", Responses: []FixtureResponse{{Error: "connection refused"}}},
+			{Model: "slow", Responses: []FixtureResponse{{Score: 1, Confidence: 1, DelayMs: 1000}}},
+		},
+	})
+	ctx := context.Background()
+
+	_, _, err := svc.ScoreContent(ctx, PromptVariant{}, &db.Article{ID: 7})
+	var apiErr LLMAPIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, ErrTypeRateLimit, apiErr.ErrorType)
+	assert.Equal(t, 3, apiErr.RetryAfter)
+
+	for i := 0; i < 2; i++ {
+		score, confidence, err := svc.ScoreContent(ctx, PromptVariant{}, &db.Article{ID: 7})
+		require.NoError(t, err)
+		assert.Equal(t, 0.4, score, "the last response repeats")
+		assert.Equal(t, 0.8, confidence)
+	}
+
+	_, _, err = svc.ScoreContent(ctx, PromptVariant{}, &db.Article{ID: 1, Content: "gone offline"})
+	assert.EqualError(t, err, "connection refused")
+
+	score, confidence, err := svc.ScoreContent(ctx, PromptVariant{}, &db.Article{ID: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, score)
+	assert.Equal(t, 0.9, confidence)
+
+	cancelled, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, _, err = svc.ScoreContent(cancelled, PromptVariant{Model: "slow"}, &db.Article{ID: 3})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.Equal(t, 6, svc.Calls())
+}
+
+func TestFixtureLLMServiceDrivesEnsemble(t *testing.T) {
+	// TestMain runs the tests from the project root
+	svc, err := LoadFixtureLLMService(filepath.Join("testdata", "llm_fixture.json"))
+	require.NoError(t, err)
+
+	client := NewLLMClientWithService(nil, svc, &CompositeScoreConfig{
+		Models: []ModelConfig{
+			{ModelName: "meta-llama/llama-4-maverick", Perspective: "left", Weight: 1.0},
+			{ModelName: "openai/gpt-4.1-nano", Perspective: "right", Weight: 1.0},
+		},
+	})
+
+	score, err := client.EnsembleAnalyze(context.Background(), 1, "Some article text")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.0, score.Score, 1e-9, "symmetric left and right fixtures cancel out")
+
+	single, err := client.ScoreWithModel(&db.Article{ID: 2, Content: "text"}, "openai/gpt-4.1-nano")
+	require.NoError(t, err)
+	assert.Equal(t, 0.6, single)
+}
@@ -8,11 +8,15 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/apperrors"
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/metrics"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/tracing"
 	"github.com/go-resty/resty/v2"
+	"github.com/jmoiron/sqlx"
 )
 
 // LLMService defines the interface for LLM analysis providers
@@ -20,6 +24,14 @@ type LLMService interface {
 	ScoreContent(ctx context.Context, pv PromptVariant, art *db.Article) (score float64, confidence float64, err error)
 }
 
+// ExplainingLLMService is an LLMService that also returns the explanation and raw
+// response. Services other than HTTPLLMService implement it to be usable for
+// single-model and ensemble scoring.
+type ExplainingLLMService interface {
+	LLMService
+	ScoreWithExplanation(ctx context.Context, pv PromptVariant, art *db.Article) (score float64, confidence float64, explanation string, raw string, err error)
+}
+
 // OpenRouterErrorType represents specific error types from OpenRouter
 type OpenRouterErrorType string
 
@@ -62,11 +74,47 @@ type HTTPLLMService struct {
 	client    *resty.Client
 	apiKey    string
 	backupKey string
+	extraKeys []string
 	baseURL   string
+
+	keys     *keyPool
+	keysOnce sync.Once
+
+	// breaker fails calls fast during a provider outage; nil disables it
+	breaker *circuitBreaker
+	// usageDB stores the token usage of each call for cost reporting; nil stores nothing
+	usageDB *sqlx.DB
 }
 
 // NewHTTPLLMService creates a new HTTP-based LLM service
 func NewHTTPLLMService(c *resty.Client, primaryKey string, backupKey string, baseURL string) *HTTPLLMService {
+	return NewHTTPLLMServiceWithKeys(c, []string{primaryKey, backupKey}, baseURL)
+}
+
+// NewHTTPLLMServiceWithKeys creates an HTTP-based LLM service that rotates
+// round-robin across the given API keys, skipping keys that recently failed
+// with an authentication, credits or rate limit error
+func NewHTTPLLMServiceWithKeys(c *resty.Client, keys []string, baseURL string) *HTTPLLMService {
+	s := &HTTPLLMService{
+		client:  c,
+		baseURL: chatCompletionsURL(baseURL),
+		breaker: newCircuitBreaker(defaultBreakerThreshold, defaultBreakerWindow, defaultBreakerCooldown),
+	}
+	if len(keys) > 0 {
+		s.apiKey = keys[0]
+	}
+	if len(keys) > 1 {
+		s.backupKey = keys[1]
+	}
+	if len(keys) > 2 {
+		s.extraKeys = keys[2:]
+	}
+	return s
+}
+
+// chatCompletionsURL returns the chat completions endpoint for a provider base URL,
+// defaulting to OpenRouter
+func chatCompletionsURL(baseURL string) string {
 	if baseURL == "" {
 		baseURL = "https://openrouter.ai/api/v1"
 	}
@@ -78,105 +126,151 @@ func NewHTTPLLMService(c *resty.Client, primaryKey string, backupKey string, bas
 			baseURL += "/chat/completions"
 		}
 	}
-	return &HTTPLLMService{
-		client:    c,
-		apiKey:    primaryKey,
-		backupKey: backupKey,
-		baseURL:   baseURL,
+	return baseURL
+}
+
+// keyPool returns the service's key pool, building it on first use so that
+// services constructed as struct literals get one too
+func (s *HTTPLLMService) keyPool() *keyPool {
+	s.keysOnce.Do(func() {
+		keys := append([]string{s.apiKey, s.backupKey}, s.extraKeys...)
+		s.keys = newKeyPool(keys, defaultKeyCooldown)
+	})
+	return s.keys
+}
+
+// KeyStats returns per-key health for every configured API key, with the keys masked
+func (s *HTTPLLMService) KeyStats() []KeyStats {
+	return s.keyPool().stats()
+}
+
+// BreakerStats returns the circuit breaker state, or nil when it is disabled
+func (s *HTTPLLMService) BreakerStats() *BreakerStats {
+	if s.breaker == nil {
+		return nil
 	}
+	stats := s.breaker.stats()
+	return &stats
 }
 
-// callLLMAPIWithKey makes a direct API call to the LLM service
+// callLLMAPIWithKey makes a direct API call to the LLM service with a single user message
 func (s *HTTPLLMService) callLLMAPIWithKey(modelName string, prompt string, apiKey string) (*resty.Response, error) {
-	return s.client.R().
+	return s.callLLMAPIWithMessages(modelName, userMessages(prompt), SamplingParams{}, nil, apiKey)
+}
+
+// callLLMAPIWithMessages makes a direct API call to the LLM service with the given chat
+// messages and the model's extra params, recording the token usage of successful calls. While the circuit breaker is
+// open it returns ErrProviderUnavailable without calling the provider; transport errors
+// and 5xx responses count as provider failures.
+func (s *HTTPLLMService) callLLMAPIWithMessages(modelName string, messages []map[string]string, sampling SamplingParams, extra map[string]interface{}, apiKey string) (*resty.Response, error) {
+	if err := s.breaker.allow(); err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{
+		"model":    modelName,
+		"messages": messages,
+	}
+	sampling.apply(body)
+	applyExtraParams(body, extra)
+	resp, err := s.client.R().
 		SetAuthToken(apiKey).
 		SetHeader("Content-Type", "application/json").
 		SetHeader("HTTP-Referer", "https://github.com/alexandru-savinov/BalancedNewsGo").
 		SetHeader("X-Title", "NewsBalancer").
-		SetBody(map[string]interface{}{
-			"model": modelName,
-			"messages": []map[string]string{
-				{"role": "user", "content": prompt},
-			},
-		}).
+		SetBody(body).
 		Post(s.baseURL)
+	if err != nil || resp.StatusCode() >= 500 {
+		s.breaker.recordFailure()
+		return resp, err
+	}
+	s.breaker.recordSuccess()
+	if resp.StatusCode() < 400 {
+		recordTokenUsage(s.usageDB, modelName, parseTokenUsage(resp.String()))
+	}
+	return resp, err
+}
+
+// callWithKeyRotation calls the LLM API with the next healthy key, moving on to the
+// following key when one is rejected with 401, 402 or 429. Each key is tried at most
+// once per call; the last response is returned when every key fails.
+func (s *HTTPLLMService) callWithKeyRotation(modelName string, messages []map[string]string, sampling SamplingParams, extra map[string]interface{}) (*resty.Response, error) {
+	pool := s.keyPool()
+	if pool.size() == 0 {
+		return s.callLLMAPIWithMessages(modelName, messages, sampling, extra, "")
+	}
+
+	tried := make(map[string]bool)
+	var resp *resty.Response
+	var err error
+	for i := 0; i < pool.size(); i++ {
+		key := pool.pick()
+		if tried[key] {
+			break
+		}
+		tried[key] = true
+
+		resp, err = s.callLLMAPIWithMessages(modelName, messages, sampling, extra, key)
+		if err != nil {
+			return resp, err
+		}
+		if !isKeyFailureStatus(resp.StatusCode()) {
+			pool.reportSuccess(key, resp.StatusCode())
+			return resp, nil
+		}
+
+		var retryAfter time.Duration
+		if secs, convErr := strconv.Atoi(resp.Header().Get("Retry-After")); convErr == nil && secs > 0 {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+		pool.reportFailure(key, resp.StatusCode(), retryAfter)
+		log.Printf("[WARN] LLM API key %s rejected with status %d for model %s, trying next key", maskKey(key), resp.StatusCode(), modelName)
+	}
+	return resp, err
+}
+
+// isRateLimited reports whether a call failed because of rate limiting
+func isRateLimited(resp *resty.Response, err error) bool {
+	return (err != nil && strings.Contains(err.Error(), "rate limit")) || (resp != nil && resp.StatusCode() == 429)
 }
 
 // ScoreContent implements LLMService by making HTTP requests to score content
 func (s *HTTPLLMService) ScoreContent(ctx context.Context, pv PromptVariant, art *db.Article) (score float64, confidence float64, err error) {
-	// Try primary key first
-	resp, err := s.callLLMAPIWithKey(pv.Model, pv.FormatPrompt(art.Content), s.apiKey)
-
-	// Handle rate limiting and try backup key if available
-	if (err != nil && strings.Contains(err.Error(), "rate limit")) || (resp != nil && resp.StatusCode() == 429) {
-		if s.backupKey != "" {
-			// Try backup key if rate limited and backup key exists
-			resp, err = s.callLLMAPIWithKey(pv.Model, pv.FormatPrompt(art.Content), s.backupKey)
-			if (err != nil && strings.Contains(err.Error(), "rate limit")) || (resp != nil && resp.StatusCode() == 429) {
-				// Both keys are rate limited for this model, try a different model
-				config, err := LoadCompositeScoreConfig()
-				if err != nil {
-					return 0, 0, fmt.Errorf("failed to load config: %w", err)
-				}
+	_, span := tracing.Start(ctx, "llm.ScoreContent", tracing.ArticleID(art.ID))
+	defer func() {
+		// Record the model that answered, which differs from the requested one after a rate-limit fallback
+		span.SetAttributes(tracing.Model(pv.Model))
+		tracing.End(span, err)
+	}()
 
-				// Find a different model to try
-				for _, model := range config.Models {
-					if model.ModelName != pv.Model {
-						log.Printf("[INFO] Rate limited on model %s, trying alternative model %s", pv.Model, model.ModelName)
-						// Try the alternative model with primary key
-						resp, err = s.callLLMAPIWithKey(model.ModelName, pv.FormatPrompt(art.Content), s.apiKey)
-						if err == nil && resp.StatusCode() < 400 {
-							pv.Model = model.ModelName // Update the model name in the prompt variant
-							break
-						}
-						// If still rate limited, try with backup key
-						if s.backupKey != "" {
-							resp, err = s.callLLMAPIWithKey(model.ModelName, pv.FormatPrompt(art.Content), s.backupKey)
-							if err == nil && resp.StatusCode() < 400 {
-								pv.Model = model.ModelName // Update the model name in the prompt variant
-								break
-							}
-						}
-					}
-				}
+	messages := pv.ChatMessages(pv.Render(PromptDataFromArticle(art, art.Content)))
+	sampling := pv.Sampling.resolved()
+	resp, err := s.callWithKeyRotation(pv.Model, messages, sampling, pv.ExtraParams)
 
-				// If we still have an error after trying all models
-				if err != nil || (resp != nil && resp.StatusCode() >= 400) {
-					return 0, 0, LLMAPIError{
-						Message:    "LLM rate limit exceeded: Rate limit exceeded",
-						StatusCode: 429,
-						ErrorType:  ErrTypeRateLimit,
-						RetryAfter: 30,
-					}
-				}
-			}
-		} else {
-			// No backup key, try a different model with primary key
-			config, err := LoadCompositeScoreConfig()
-			if err != nil {
-				return 0, 0, fmt.Errorf("failed to load config: %w", err)
-			}
+	// Every key is rate limited for this model, try a different model
+	if isRateLimited(resp, err) {
+		config, cfgErr := LoadCompositeScoreConfig()
+		if cfgErr != nil {
+			return 0, 0, fmt.Errorf("failed to load config: %w", cfgErr)
+		}
 
-			// Find a different model to try
-			for _, model := range config.Models {
-				if model.ModelName != pv.Model {
-					log.Printf("[INFO] Rate limited on model %s, trying alternative model %s", pv.Model, model.ModelName)
-					resp, err = s.callLLMAPIWithKey(model.ModelName, pv.FormatPrompt(art.Content), s.apiKey)
-					if err == nil && resp.StatusCode() < 400 {
-						pv.Model = model.ModelName // Update the model name in the prompt variant
-						break
-					}
+		for _, model := range config.Models {
+			if model.ModelName != pv.Model {
+				log.Printf("[INFO] Rate limited on model %s, trying alternative model %s", pv.Model, model.ModelName)
+				resp, err = s.callWithKeyRotation(model.ModelName, messages, sampling, model.ExtraParams)
+				if err == nil && resp.StatusCode() < 400 {
+					pv.Model = model.ModelName // Update the model name in the prompt variant
+					break
 				}
 			}
+		}
 
-			// If we still have an error after trying all models
-			if err != nil || (resp != nil && resp.StatusCode() >= 400) {
-				return 0, 0, LLMAPIError{
-					Message:    "LLM rate limit exceeded: Rate limit exceeded",
-					StatusCode: 429,
-					ErrorType:  ErrTypeRateLimit,
-					RetryAfter: 30,
-				}
+		// If we still have an error after trying all models
+		if err != nil || (resp != nil && resp.StatusCode() >= 400) {
+			return 0, 0, LLMAPIError{
+				Message:    "LLM rate limit exceeded: Rate limit exceeded",
+				StatusCode: 429,
+				ErrorType:  ErrTypeRateLimit,
+				RetryAfter: 30,
 			}
 		}
 	}
@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -44,6 +45,36 @@ func TestHTTPLLMServiceUsesOpenRouterURL(t *testing.T) {
 	}
 }
 
+// TestScoreContentMessageRoles verifies the system prompt is sent as its own message
+func TestScoreContentMessageRoles(t *testing.T) {
+	var received []map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []map[string]string `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received = body.Messages
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"{\"score\":0.1,\"explanation\":\"ok\",\"confidence\":0.8}"}}]}`))
+	}))
+	defer ts.Close()
+
+	svc := NewHTTPLLMService(resty.New(), "dummy-key", "", ts.URL)
+	article := &db.Article{ID: 1, Title: "Title", Content: "Body"}
+
+	_, _, err := svc.ScoreContent(context.Background(), PromptVariant{Model: "m", Template: "Rate {{content}}"}, article)
+	require.NoError(t, err)
+	require.Len(t, received, 1)
+	assert.Equal(t, "user", received[0]["role"])
+
+	pv := PromptVariant{Model: "m", SystemPrompt: "You are a bias analyst.", Template: "Rate {{title}}:"}
+	_, _, err = svc.ScoreContent(context.Background(), pv, article)
+	require.NoError(t, err)
+	require.Len(t, received, 2)
+	assert.Equal(t, map[string]string{"role": "system", "content": "You are a bias analyst."}, received[0])
+	assert.Equal(t, "user", received[1]["role"])
+	assert.Equal(t, "Rate Title:\n\nArticle:\nBody", received[1]["content"])
+}
+
 // TestLLMAPIError_Error tests the Error method of the LLMAPIError type
 func TestLLMAPIError_Error(t *testing.T) {
 	testCases := []struct {
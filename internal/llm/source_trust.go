@@ -0,0 +1,46 @@
+package llm
+
+import (
+	"log"
+	"math"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// sourceTrust records how the trust weight of an article's source changed its
+// composite confidence
+type sourceTrust struct {
+	Weight        float64
+	RawConfidence float64
+}
+
+// ScaleConfidence applies a source trust weight to a composite confidence, keeping the
+// result within [0, 1]
+func ScaleConfidence(confidence, trustWeight float64) float64 {
+	return math.Max(0, math.Min(1, confidence*trustWeight))
+}
+
+// applySourceTrust scales a composite confidence by the trust weight of the article's
+// source. When the weight cannot be read the confidence is left as it is.
+func applySourceTrust(exec sqlx.QueryerContext, articleID int64, confidence float64) (float64, sourceTrust) {
+	trust := sourceTrust{Weight: models.DefaultSourceTrustWeight, RawConfidence: confidence}
+	weight, err := db.FetchArticleTrustWeight(exec, articleID)
+	if err != nil {
+		log.Printf("[WARN] ArticleID %d: Could not read source trust weight, leaving confidence unscaled: %v", articleID, err)
+		return confidence, trust
+	}
+	trust.Weight = weight
+	return ScaleConfidence(confidence, weight), trust
+}
+
+// addTo records the unscaled confidence and the trust weight in an ensemble score's
+// final aggregation when the weight is not the default
+func (t sourceTrust) addTo(finalAggregation map[string]any) {
+	if t.Weight == models.DefaultSourceTrustWeight {
+		return
+	}
+	finalAggregation["raw_confidence"] = t.RawConfidence
+	finalAggregation["source_trust_weight"] = t.Weight
+}
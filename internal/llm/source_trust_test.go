@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaleConfidence(t *testing.T) {
+	assert.InDelta(t, 0.8, ScaleConfidence(0.8, 1), 1e-9, "the default weight keeps the confidence")
+	assert.InDelta(t, 0.4, ScaleConfidence(0.8, 0.5), 1e-9)
+	assert.InDelta(t, 0.9, ScaleConfidence(0.6, 1.5), 1e-9)
+	assert.Equal(t, 1.0, ScaleConfidence(0.8, 1.5), "capped at 1")
+	assert.Equal(t, 0.0, ScaleConfidence(0, 1.5))
+}
+
+func TestUpdateArticleScoreSourceTrust(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "trust.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	for name, metadata := range map[string]string{
+		"sceptical": `{"trust_weight": 0.5}`,
+		"trusted":   `{"trust_weight": 1.5}`,
+		"invalid":   `{"trust_weight": 3}`,
+	} {
+		meta := metadata
+		_, err := db.InsertSource(dbConn, &db.Source{
+			Name: name, ChannelType: "rss", FeedURL: "https://example.com/" + name, Category: "center",
+			Enabled: true, DefaultWeight: 1, Metadata: &meta,
+		})
+		require.NoError(t, err)
+	}
+
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(dbConn, NewCache(), &MockRealCalculator{CalculatedScore: 0.2, CalculatedConfidence: 0.8}, pm)
+	scores := []db.LLMScore{{Model: "center", Score: 0.2, Metadata: `{"confidence":0.8}`}}
+	cfg := &CompositeScoreConfig{Models: []ModelConfig{{ModelName: "center", Perspective: "center"}}}
+
+	for source, want := range map[string]float64{
+		"sceptical":    0.4,
+		"trusted":      1.0,
+		"invalid":      0.8,
+		"unconfigured": 0.8,
+	} {
+		res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+			VALUES (?, CURRENT_TIMESTAMP, ?, 'title', 'content')`, source, "https://example.com/a/"+source)
+		require.NoError(t, err)
+		articleID, err := res.LastInsertId()
+		require.NoError(t, err)
+
+		score, confidence, err := sm.UpdateArticleScore(articleID, scores, cfg)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.2, score, 1e-9, "%s: the score is never scaled", source)
+		assert.InDelta(t, want, confidence, 1e-9, source)
+		_, stored, err := db.FetchArticleScore(dbConn, articleID)
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		assert.InDelta(t, want, *stored, 1e-9, source)
+	}
+}
+
+func TestRecomputeRecordsRawConfidence(t *testing.T) {
+	dbConn, err := db.InitDB(filepath.Join(t.TempDir(), "trust_recompute.db"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	meta := `{"trust_weight": 0.5}`
+	_, err = db.InsertSource(dbConn, &db.Source{
+		Name: "sceptical", ChannelType: "rss", FeedURL: "https://example.com/feed", Category: "center",
+		Enabled: true, DefaultWeight: 1, Metadata: &meta,
+	})
+	require.NoError(t, err)
+	res, err := dbConn.Exec(`INSERT INTO articles (source, pub_date, url, title, content)
+		VALUES ('sceptical', CURRENT_TIMESTAMP, 'https://example.com/a', 'title', 'content')`)
+	require.NoError(t, err)
+	articleID, err := res.LastInsertId()
+	require.NoError(t, err)
+	_, err = db.InsertLLMScore(dbConn, &db.LLMScore{
+		ArticleID: articleID, Model: "center", Score: 0.2, Metadata: `{"confidence": 0.8}`, Version: 1, CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	pm := NewProgressManager(time.Hour)
+	defer pm.Stop()
+	sm := NewScoreManager(dbConn, NewCache(), &MockRealCalculator{CalculatedScore: 0.2, CalculatedConfidence: 0.8}, pm)
+	cfg := &CompositeScoreConfig{Models: []ModelConfig{{ModelName: "center", Perspective: "center"}}}
+
+	result, err := sm.RecomputeArticleScore(articleID, cfg)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.4, result.Confidence, 1e-9)
+
+	scores, err := db.FetchLLMScores(dbConn, articleID)
+	require.NoError(t, err)
+	var aggregation map[string]interface{}
+	for _, s := range scores {
+		if s.Model == "ensemble" {
+			var ensemble struct {
+				FinalAggregation map[string]interface{} `json:"final_aggregation"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(s.Metadata), &ensemble))
+			aggregation = ensemble.FinalAggregation
+		}
+	}
+	require.NotNil(t, aggregation)
+	assert.InDelta(t, 0.4, aggregation["confidence"], 1e-9)
+	assert.InDelta(t, 0.8, aggregation["raw_confidence"], 1e-9)
+	assert.InDelta(t, 0.5, aggregation["source_trust_weight"], 1e-9)
+}
@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/models"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/tracing"
+)
+
+// TextScoreResult is the ensemble score of text that is not stored as an article
+type TextScoreResult struct {
+	CompositeScore   float64            `json:"composite_score"`
+	Confidence       float64            `json:"confidence"`
+	Label            string             `json:"label"`
+	Models           []ModelScoreResult `json:"models"`
+	ContentTruncated bool               `json:"content_truncated,omitempty"`
+	DurationMs       int64              `json:"duration_ms"`
+}
+
+// ScoreText scores arbitrary text with every configured model and combines the
+// results into a composite score. The title, when given, is scored with the content.
+// Nothing is cached or persisted.
+func (c *LLMClient) ScoreText(ctx context.Context, title, content string) (result *TextScoreResult, err error) {
+	cfg := c.GetConfig()
+	ctx, span := tracing.Start(ctx, "llm.ScoreText")
+	defer func() { tracing.End(span, err) }()
+
+	if cfg == nil || len(cfg.Models) == 0 {
+		return nil, fmt.Errorf("LLMClient config is nil or has no models defined")
+	}
+	if title = strings.TrimSpace(title); title != "" {
+		content = title + "\n\n" + content
+	}
+
+	start := time.Now()
+	content, truncated := prepareContent(cfg, 0, content)
+	results := c.scoreWithEachModel(ctx, content)
+
+	score, confidence, scored, err := c.compositeOfResults(results)
+	if err != nil {
+		return nil, err
+	}
+	result = &TextScoreResult{
+		CompositeScore:   score,
+		Confidence:       confidence,
+		Label:            models.BiasLabel(score),
+		Models:           results,
+		ContentTruncated: truncated,
+		DurationMs:       time.Since(start).Milliseconds(),
+	}
+	log.Printf("[ScoreText] Score=%.3f Confidence=%.3f Models=%d/%d Duration=%dms",
+		score, confidence, scored, len(results), result.DurationMs)
+	return result, nil
+}
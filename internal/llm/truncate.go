@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"log"
+)
+
+// Content truncation strategies, selected with truncation_strategy in the composite score config
+const (
+	// TruncateHead keeps the beginning of the article
+	TruncateHead = "head"
+	// TruncateHeadTail keeps mostly the beginning plus the closing paragraphs
+	TruncateHeadTail = "head_tail"
+	// TruncateMiddleOut drops text from the centre outward, keeping equal head and tail
+	TruncateMiddleOut = "middle_out"
+)
+
+// charsPerToken approximates how many characters make up one model token
+const charsPerToken = 4
+
+// truncationMarker is inserted where text was dropped from the middle of an article
+const truncationMarker = "\n[...]\n"
+
+// MaxContentLength returns the configured content limit in characters, converting
+// max_content_tokens when max_content_chars is unset. Zero means unlimited.
+func (cfg *CompositeScoreConfig) MaxContentLength() int {
+	if cfg == nil {
+		return 0
+	}
+	if cfg.MaxContentChars > 0 {
+		return cfg.MaxContentChars
+	}
+	if cfg.MaxContentTokens > 0 {
+		return cfg.MaxContentTokens * charsPerToken
+	}
+	return 0
+}
+
+// TruncateContent shortens content to at most maxChars characters using the given
+// strategy, reporting whether anything was removed. Unknown strategies fall back to head.
+func TruncateContent(content string, maxChars int, strategy string) (string, bool) {
+	runes := []rune(content)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return content, false
+	}
+
+	marker := []rune(truncationMarker)
+	if strategy == TruncateHead || maxChars <= len(marker)*2 {
+		return string(runes[:maxChars]), true
+	}
+
+	keep := maxChars - len(marker)
+	var head int
+	switch strategy {
+	case TruncateHeadTail:
+		head = keep * 2 / 3
+	case TruncateMiddleOut:
+		head = keep / 2
+	default:
+		log.Printf("[WARN] Unknown truncation strategy %q, keeping the head of the content", strategy)
+		return string(runes[:maxChars]), true
+	}
+	tail := keep - head
+
+	out := make([]rune, 0, maxChars)
+	out = append(out, runes[:head]...)
+	out = append(out, marker...)
+	out = append(out, runes[len(runes)-tail:]...)
+	return string(out), true
+}
+
+// prepareContent applies the configured content limit before submission to a model
+func prepareContent(cfg *CompositeScoreConfig, articleID int64, content string) (string, bool) {
+	truncated, ok := TruncateContent(content, cfg.MaxContentLength(), cfg.truncationStrategy())
+	if ok {
+		log.Printf("[LLM] ArticleID %d | Content truncated from %d to %d characters (%s)",
+			articleID, len([]rune(content)), len([]rune(truncated)), cfg.truncationStrategy())
+	}
+	return truncated, ok
+}
+
+// truncationStrategy returns the configured strategy, defaulting to head_tail
+func (cfg *CompositeScoreConfig) truncationStrategy() string {
+	if cfg == nil || cfg.TruncationStrategy == "" {
+		return TruncateHeadTail
+	}
+	return cfg.TruncationStrategy
+}
+
+// truncatedMetaField returns the metadata JSON fragment flagging truncated content
+func truncatedMetaField(truncated bool) string {
+	if !truncated {
+		return ""
+	}
+	return `, "content_truncated": true`
+}
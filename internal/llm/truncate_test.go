@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateContent(t *testing.T) {
+	content := strings.Repeat("a", 50) + strings.Repeat("m", 100) + strings.Repeat("z", 50)
+
+	t.Run("UnderLimit", func(t *testing.T) {
+		out, truncated := TruncateContent("short", 100, TruncateHead)
+		assert.False(t, truncated)
+		assert.Equal(t, "short", out)
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		out, truncated := TruncateContent(content, 0, TruncateHead)
+		assert.False(t, truncated)
+		assert.Equal(t, content, out)
+	})
+
+	t.Run("Head", func(t *testing.T) {
+		out, truncated := TruncateContent(content, 60, TruncateHead)
+		assert.True(t, truncated)
+		assert.Equal(t, strings.Repeat("a", 50)+strings.Repeat("m", 10), out)
+	})
+
+	t.Run("HeadTail", func(t *testing.T) {
+		out, truncated := TruncateContent(content, 100, TruncateHeadTail)
+		assert.True(t, truncated)
+		assert.Len(t, []rune(out), 100)
+		assert.True(t, strings.HasPrefix(out, strings.Repeat("a", 50)))
+		assert.True(t, strings.HasSuffix(out, strings.Repeat("z", 30)))
+		assert.Contains(t, out, truncationMarker)
+	})
+
+	t.Run("MiddleOut", func(t *testing.T) {
+		out, truncated := TruncateContent(content, 100, TruncateMiddleOut)
+		assert.True(t, truncated)
+		assert.Len(t, []rune(out), 100)
+		parts := strings.Split(out, truncationMarker)
+		assert.Len(t, parts, 2)
+		assert.InDelta(t, len(parts[0]), len(parts[1]), 1, "head and tail are balanced")
+		assert.True(t, strings.HasSuffix(out, strings.Repeat("z", 46)))
+	})
+
+	t.Run("MultibyteSafe", func(t *testing.T) {
+		out, truncated := TruncateContent(strings.Repeat("é", 40), 30, TruncateHeadTail)
+		assert.True(t, truncated)
+		assert.Len(t, []rune(out), 30)
+		assert.True(t, strings.HasPrefix(out, "é"))
+	})
+}
+
+func TestMaxContentLength(t *testing.T) {
+	var nilCfg *CompositeScoreConfig
+	assert.Equal(t, 0, nilCfg.MaxContentLength())
+	assert.Equal(t, 0, (&CompositeScoreConfig{}).MaxContentLength())
+	assert.Equal(t, 400, (&CompositeScoreConfig{MaxContentTokens: 100}).MaxContentLength())
+	assert.Equal(t, 250, (&CompositeScoreConfig{MaxContentChars: 250, MaxContentTokens: 100}).MaxContentLength())
+}
+
+func TestScoreWithModelTruncatesContent(t *testing.T) {
+	var submitted string
+	client := &LLMClient{
+		llmService: &mockLLMServiceTestEnsemble{
+			scoreContentFunc: func(ctx context.Context, pv PromptVariant, art *db.Article) (float64, float64, error) {
+				submitted = art.Content
+				return 0.1, 0.9, nil
+			},
+		},
+		config: &CompositeScoreConfig{
+			Models:             []ModelConfig{{ModelName: "model1", Perspective: "left", Weight: 1.0}},
+			MaxContentChars:    40,
+			TruncationStrategy: TruncateHead,
+		},
+	}
+	article := &db.Article{ID: 1, Content: strings.Repeat("x", 100)}
+
+	_, err := client.ScoreWithModel(article, "model1")
+	assert.NoError(t, err)
+	assert.Len(t, submitted, 40)
+	assert.Len(t, article.Content, 100, "caller's article is not modified")
+}
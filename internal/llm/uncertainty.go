@@ -0,0 +1,111 @@
+package llm
+
+import "math"
+
+// Reasons recorded in final_aggregation.uncertainty_reasons when a result is flagged
+const (
+	UncertaintyHighVariance      = "high_variance"
+	UncertaintyModelDisagreement = "model_disagreement"
+	UncertaintyLowConfidence     = "low_confidence"
+)
+
+// defaultMaxUncertaintyVariance is the within-model variance above which a result is
+// flagged when no uncertainty config is given
+const defaultMaxUncertaintyVariance = 0.1
+
+// UncertaintyConfig decides when a composite result gets uncertainty_flag. The flag is
+// set when any enabled condition holds:
+//
+//   - variance > max_variance, where variance is the confidence-weighted mean of each
+//     model's variance across its own responses (default 0.1)
+//   - the population standard deviation of the per-model scores > max_score_stddev,
+//     i.e. the models disagree with each other; needs at least two models
+//   - the mean of the per-model confidences < min_mean_confidence
+//
+// A zero threshold disables its condition; only max_variance is enabled by default.
+type UncertaintyConfig struct {
+	MaxVariance       *float64 `json:"max_variance,omitempty"`
+	MaxScoreStdDev    float64  `json:"max_score_stddev,omitempty"`
+	MinMeanConfidence float64  `json:"min_mean_confidence,omitempty"`
+}
+
+// UncertaintyAssessment is the outcome of checking a result against UncertaintyConfig
+type UncertaintyAssessment struct {
+	Flag           bool
+	Reasons        []string
+	ScoreStdDev    float64
+	MeanConfidence float64
+}
+
+// assessUncertainty checks a result's within-model variance and its per-model scores
+// and confidences, given in the same order, against the configured conditions
+func (cfg *CompositeScoreConfig) assessUncertainty(variance float64, scores, confidences []float64) UncertaintyAssessment {
+	var u UncertaintyConfig
+	if cfg != nil && cfg.Uncertainty != nil {
+		u = *cfg.Uncertainty
+	}
+	maxVariance := defaultMaxUncertaintyVariance
+	if u.MaxVariance != nil {
+		maxVariance = *u.MaxVariance
+	}
+
+	var a UncertaintyAssessment
+	if len(scores) > 1 {
+		var sum float64
+		for _, s := range scores {
+			sum += s
+		}
+		mean := sum / float64(len(scores))
+		var sq float64
+		for _, s := range scores {
+			sq += (s - mean) * (s - mean)
+		}
+		a.ScoreStdDev = math.Sqrt(sq / float64(len(scores)))
+	}
+	if len(confidences) > 0 {
+		var sum float64
+		for _, c := range confidences {
+			sum += c
+		}
+		a.MeanConfidence = sum / float64(len(confidences))
+	}
+
+	if maxVariance > 0 && variance > maxVariance {
+		a.Reasons = append(a.Reasons, UncertaintyHighVariance)
+	}
+	if u.MaxScoreStdDev > 0 && len(scores) > 1 && a.ScoreStdDev > u.MaxScoreStdDev {
+		a.Reasons = append(a.Reasons, UncertaintyModelDisagreement)
+	}
+	if u.MinMeanConfidence > 0 && len(confidences) > 0 && a.MeanConfidence < u.MinMeanConfidence {
+		a.Reasons = append(a.Reasons, UncertaintyLowConfidence)
+	}
+	a.Flag = len(a.Reasons) > 0
+	return a
+}
+
+// addTo records the assessment in a final_aggregation metadata map
+func (a UncertaintyAssessment) addTo(aggregation map[string]interface{}) {
+	aggregation["uncertainty_flag"] = a.Flag
+	aggregation["score_stddev"] = a.ScoreStdDev
+	aggregation["mean_confidence"] = a.MeanConfidence
+	if a.Flag {
+		aggregation["uncertainty_reasons"] = a.Reasons
+	}
+}
+
+// assessSubResults checks stored per-model sub_results, which have a single response
+// per model and so no within-model variance
+func (cfg *CompositeScoreConfig) assessSubResults(subResults []map[string]interface{}) UncertaintyAssessment {
+	scores := make([]float64, 0, len(subResults))
+	confidences := make([]float64, 0, len(subResults))
+	for _, sr := range subResults {
+		score, ok := sr["score"].(float64)
+		if !ok {
+			continue
+		}
+		confidence, _ := sr["confidence"].(float64)
+		scores = append(scores, score)
+		confidences = append(confidences, confidence)
+	}
+	return cfg.assessUncertainty(0, scores, confidences)
+}
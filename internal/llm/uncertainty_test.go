@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssessUncertaintyDefaults(t *testing.T) {
+	var cfg *CompositeScoreConfig
+	a := cfg.assessUncertainty(0.05, []float64{-0.8, 0.8}, []float64{0.1, 0.1})
+	assert.False(t, a.Flag, "only the variance check is enabled by default")
+	assert.InDelta(t, 0.8, a.ScoreStdDev, 1e-9)
+	assert.InDelta(t, 0.1, a.MeanConfidence, 1e-9)
+
+	a = cfg.assessUncertainty(0.2, []float64{0.1}, []float64{0.9})
+	assert.True(t, a.Flag)
+	assert.Equal(t, []string{UncertaintyHighVariance}, a.Reasons)
+}
+
+func TestAssessUncertaintyConditions(t *testing.T) {
+	zero := 0.0
+	cfg := &CompositeScoreConfig{Uncertainty: &UncertaintyConfig{
+		MaxVariance:       &zero,
+		MaxScoreStdDev:    0.3,
+		MinMeanConfidence: 0.6,
+	}}
+
+	a := cfg.assessUncertainty(0.9, []float64{0.1, 0.2}, []float64{0.8, 0.9})
+	assert.False(t, a.Flag, "a zero max_variance disables the variance check")
+
+	a = cfg.assessUncertainty(0, []float64{-0.5, 0.5}, []float64{0.8, 0.9})
+	assert.True(t, a.Flag)
+	assert.Equal(t, []string{UncertaintyModelDisagreement}, a.Reasons)
+
+	a = cfg.assessUncertainty(0, []float64{0.1, 0.2}, []float64{0.5, 0.6})
+	assert.True(t, a.Flag)
+	assert.Equal(t, []string{UncertaintyLowConfidence}, a.Reasons)
+
+	a = cfg.assessUncertainty(0, []float64{0.9}, []float64{0.9})
+	assert.False(t, a.Flag, "a single model cannot disagree")
+
+	a = cfg.assessUncertainty(0, []float64{-0.5, 0.5}, []float64{0.2, 0.3})
+	assert.Equal(t, []string{UncertaintyModelDisagreement, UncertaintyLowConfidence}, a.Reasons)
+}
+
+func TestAssessSubResultsRecordsReasons(t *testing.T) {
+	var cfg CompositeScoreConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"uncertainty":{"max_score_stddev":0.25}}`), &cfg))
+	require.NotNil(t, cfg.Uncertainty)
+	assert.Nil(t, cfg.Uncertainty.MaxVariance)
+
+	aggregation := map[string]interface{}{}
+	cfg.assessSubResults([]map[string]interface{}{
+		{"model": "left", "score": -0.6, "confidence": 0.9},
+		{"model": "right", "score": 0.4, "confidence": 0.7},
+	}).addTo(aggregation)
+	assert.Equal(t, true, aggregation["uncertainty_flag"])
+	assert.Equal(t, []string{UncertaintyModelDisagreement}, aggregation["uncertainty_reasons"])
+	assert.InDelta(t, 0.5, aggregation["score_stddev"], 1e-9)
+	assert.InDelta(t, 0.8, aggregation["mean_confidence"], 1e-9)
+
+	aggregation = map[string]interface{}{}
+	cfg.assessSubResults([]map[string]interface{}{{"model": "left", "score": -0.6, "confidence": 0.9}}).addTo(aggregation)
+	assert.Equal(t, false, aggregation["uncertainty_flag"])
+	assert.NotContains(t, aggregation, "uncertainty_reasons")
+}
@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/metrics"
+	"github.com/jmoiron/sqlx"
+)
+
+// TokenUsage is the token count a provider reports for one call
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// ModelPrice is what a model costs, in dollars per million tokens
+type ModelPrice struct {
+	PromptPerMillion     float64 `json:"prompt_per_million"`
+	CompletionPerMillion float64 `json:"completion_per_million"`
+}
+
+// parseTokenUsage reads the usage field of a chat completion response. It returns nil
+// when the provider reported no usage. Providers using input_tokens and output_tokens
+// are read too.
+func parseTokenUsage(rawResponse string) *TokenUsage {
+	var resp struct {
+		Usage *struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			TotalTokens      int `json:"total_tokens"`
+			InputTokens      int `json:"input_tokens"`
+			OutputTokens     int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal([]byte(rawResponse), &resp); err != nil || resp.Usage == nil {
+		return nil
+	}
+	u := resp.Usage
+	usage := &TokenUsage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens, TotalTokens: u.TotalTokens}
+	if usage.PromptTokens == 0 && usage.CompletionTokens == 0 {
+		usage.PromptTokens, usage.CompletionTokens = u.InputTokens, u.OutputTokens
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	if usage.TotalTokens == 0 {
+		return nil
+	}
+	return usage
+}
+
+// recordTokenUsage counts a successful call's tokens, or the call itself when the
+// provider reported no usage, and stores the call in usageDB for cost reporting when
+// it is set
+func recordTokenUsage(usageDB *sqlx.DB, model string, usage *TokenUsage) {
+	var tokens *db.TokenCounts
+	if usage == nil {
+		metrics.IncLLMCallWithoutUsage(model)
+	} else {
+		metrics.RecordLLMTokens(model, usage.PromptTokens, usage.CompletionTokens)
+		tokens = &db.TokenCounts{Prompt: usage.PromptTokens, Completion: usage.CompletionTokens, Total: usage.TotalTokens}
+	}
+	if usageDB == nil {
+		return
+	}
+	if err := db.InsertLLMUsage(usageDB, model, tokens); err != nil {
+		log.Printf("[WARN] Failed to record token usage of %s: %v", model, err)
+	}
+}
+
+// EstimateCost returns the dollar cost of the given token counts at the model's price
+// rate, and false when no rate is configured for the model
+func (cfg *CompositeScoreConfig) EstimateCost(model string, promptTokens, completionTokens int) (float64, bool) {
+	if cfg == nil {
+		return 0, false
+	}
+	price, ok := cfg.ModelPrices[model]
+	if !ok {
+		return 0, false
+	}
+	cost := (float64(promptTokens)*price.PromptPerMillion + float64(completionTokens)*price.CompletionPerMillion) / 1e6
+	return cost, true
+}
+
+// usageMetaField returns the metadata JSON fragment recording a call's token usage
+func usageMetaField(usage *TokenUsage) string {
+	if usage == nil {
+		return ""
+	}
+	encoded, err := json.Marshal(usage)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf(`, "usage": %s`, encoded)
+}
+
+// withoutUsage drops the usage recorded in score metadata, for results served from the
+// cache that cost nothing
+func withoutUsage(metadata string) string {
+	var meta map[string]interface{}
+	if err := json.Unmarshal([]byte(metadata), &meta); err != nil {
+		return metadata
+	}
+	if _, ok := meta["usage"]; !ok {
+		return metadata
+	}
+	delete(meta, "usage")
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return metadata
+	}
+	return string(encoded)
+}
@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTokenUsage(t *testing.T) {
+	usage := parseTokenUsage(`{"choices":[],"usage":{"prompt_tokens":120,"completion_tokens":30,"total_tokens":150}}`)
+	require.NotNil(t, usage)
+	assert.Equal(t, TokenUsage{PromptTokens: 120, CompletionTokens: 30, TotalTokens: 150}, *usage)
+
+	usage = parseTokenUsage(`{"usage":{"input_tokens":80,"output_tokens":20}}`)
+	require.NotNil(t, usage)
+	assert.Equal(t, TokenUsage{PromptTokens: 80, CompletionTokens: 20, TotalTokens: 100}, *usage)
+
+	assert.Nil(t, parseTokenUsage(`{"choices":[]}`), "providers may not report usage")
+	assert.Nil(t, parseTokenUsage(`{"usage":{}}`))
+	assert.Nil(t, parseTokenUsage(`Score: 0.2`))
+}
+
+func TestEstimateCost(t *testing.T) {
+	cfg := &CompositeScoreConfig{ModelPrices: map[string]ModelPrice{
+		"priced": {PromptPerMillion: 2, CompletionPerMillion: 10},
+	}}
+	cost, ok := cfg.EstimateCost("priced", 500000, 100000)
+	assert.True(t, ok)
+	assert.InDelta(t, 2.0, cost, 1e-9)
+
+	_, ok = cfg.EstimateCost("unpriced", 1000, 1000)
+	assert.False(t, ok)
+	var empty *CompositeScoreConfig
+	_, ok = empty.EstimateCost("priced", 1000, 1000)
+	assert.False(t, ok)
+}
+
+func TestUsageMetadata(t *testing.T) {
+	meta := `{"confidence": 0.8` + usageMetaField(&TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}) + `}`
+	assert.JSONEq(t, `{"confidence": 0.8, "usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}}`, meta)
+	assert.Empty(t, usageMetaField(nil))
+
+	assert.JSONEq(t, `{"confidence": 0.8}`, withoutUsage(meta))
+	assert.Equal(t, `{"confidence": 0.8}`, withoutUsage(`{"confidence": 0.8}`))
+}
+
+func TestRecordTokenUsageStoresCalls(t *testing.T) {
+	conn, err := db.InitDB(filepath.Join(t.TempDir(), "usage.db"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	recordTokenUsage(conn, "model-a", &TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15})
+	recordTokenUsage(conn, "model-a", nil)
+	recordTokenUsage(nil, "model-b", nil) // Without a database nothing is stored
+
+	var rows []struct {
+		Model       string        `db:"model"`
+		TotalTokens sql.NullInt64 `db:"total_tokens"`
+	}
+	require.NoError(t, conn.Select(&rows, `SELECT model, total_tokens FROM llm_usage ORDER BY id`))
+	require.Len(t, rows, 2)
+	assert.Equal(t, "model-a", rows[0].Model)
+	assert.Equal(t, int64(15), rows[0].TotalTokens.Int64)
+	assert.False(t, rows[1].TotalTokens.Valid, "a call without reported usage is stored without tokens")
+}
@@ -105,8 +105,15 @@ func TestHashContent(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := hashContent(tc.content)
+			result := hashContent(tc.content, nil)
 			assert.Equal(t, tc.expected, result, "hashContent function returned incorrect hash")
 		})
 	}
+
+	cfg := &CompositeScoreConfig{Models: []ModelConfig{{Perspective: "left", ModelName: "a"}}}
+	withConfig := hashContent("hello world", cfg)
+	assert.NotEqual(t, hashContent("hello world", nil), withConfig, "the config is part of the fingerprint")
+	assert.Equal(t, withConfig, hashContent("hello world", &CompositeScoreConfig{Models: cfg.Models}))
+	cfg.Models[0].ModelName = "b"
+	assert.NotEqual(t, withConfig, hashContent("hello world", cfg), "a model change changes the fingerprint")
 }
@@ -1,6 +1,12 @@
 package metrics
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -44,9 +50,21 @@ func GetValidationMetrics(db *sqlx.DB) ([]ValidationMetric, error) {
 	return metrics, err
 }
 
-func GetFeedbackSummary(db *sqlx.DB) ([]FeedbackSummary, error) {
+// GetFeedbackSummary returns daily feedback counts per category, leaving out feedback
+// moderated as spam when excludeSpam is set
+func GetFeedbackSummary(db *sqlx.DB, excludeSpam bool) ([]FeedbackSummary, error) {
+	where := ""
+	if excludeSpam {
+		where = "WHERE status != 'spam'"
+	}
+	// created_at is stored both as SQLite timestamps and as Go time strings, which DATE()
+	// cannot read; both start with the date
 	var summaries []FeedbackSummary
-	err := db.Select(&summaries, "SELECT * FROM feedback_summary")
+	err := db.Select(&summaries, `
+		SELECT SUBSTR(created_at, 1, 10) AS day, COALESCE(category, '') AS category, COUNT(*) AS feedback_count
+		FROM feedback `+where+`
+		GROUP BY day, category
+		ORDER BY day DESC`)
 	return summaries, err
 }
 
@@ -67,3 +85,417 @@ func GetOutlierScores(db *sqlx.DB) ([]OutlierScore, error) {
 	err := db.Select(&outliers, "SELECT * FROM outlier_scores")
 	return outliers, err
 }
+
+// DefaultStabilityThreshold is the composite score variance across reanalysis versions
+// above which an article is flagged unstable (a standard deviation of 0.1)
+const DefaultStabilityThreshold = 0.01
+
+// ScoreStability is the spread of an article's composite score across its versions
+type ScoreStability struct {
+	ArticleID int64   `db:"article_id" json:"article_id"`
+	Versions  int     `db:"versions" json:"versions"`
+	MeanScore float64 `db:"mean_score" json:"mean_score"`
+	Variance  float64 `db:"variance" json:"variance"`
+	MinScore  float64 `db:"min_score" json:"min_score"`
+	MaxScore  float64 `db:"max_score" json:"max_score"`
+	Unstable  bool    `db:"-" json:"unstable"`
+}
+
+// ScoreStabilityReport lists articles scored more than once, most variable first
+type ScoreStabilityReport struct {
+	Threshold       float64          `json:"threshold"`
+	ArticlesChecked int              `json:"articles_checked"`
+	UnstableCount   int              `json:"unstable_count"`
+	Articles        []ScoreStability `json:"articles"`
+}
+
+// GetScoreStability computes the variance of the composite score across the analysis
+// versions in score_history of every article analysed more than once, flagging those
+// above threshold. Recomputes from stored model scores are left out since they change
+// the score on purpose.
+func GetScoreStability(db *sqlx.DB, threshold float64, unstableOnly bool) (*ScoreStabilityReport, error) {
+	var rows []ScoreStability
+	err := db.Select(&rows, `
+		SELECT
+			article_id,
+			COUNT(*) AS versions,
+			AVG(score) AS mean_score,
+			AVG(score * score) - AVG(score) * AVG(score) AS variance,
+			MIN(score) AS min_score,
+			MAX(score) AS max_score
+		FROM score_history
+		WHERE kind = 'analysis'
+		GROUP BY article_id
+		HAVING COUNT(*) > 1
+		ORDER BY variance DESC, article_id`)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ScoreStabilityReport{Threshold: threshold, ArticlesChecked: len(rows), Articles: []ScoreStability{}}
+	for _, row := range rows {
+		if row.Variance < 0 {
+			row.Variance = 0 // rounding in the single-pass formula
+		}
+		row.Unstable = row.Variance > threshold
+		if row.Unstable {
+			report.UnstableCount++
+		} else if unstableOnly {
+			continue
+		}
+		report.Articles = append(report.Articles, row)
+	}
+	return report, nil
+}
+
+// OutlierDeviation is how far a model's score must be from the consensus for the
+// article to count as an outlier for that model
+const OutlierDeviation = 0.5
+
+// OutlierModel summarises how far a model's scores fall from the consensus
+type OutlierModel struct {
+	Model    string `json:"model"`
+	Articles int    `json:"articles"`
+	// MeanDeviation is signed: positive means the model scores right of the consensus
+	MeanDeviation    float64 `json:"mean_deviation"`
+	MeanAbsDeviation float64 `json:"mean_abs_deviation"`
+	MaxAbsDeviation  float64 `json:"max_abs_deviation"`
+	// OutlierRate is the share of articles where the model deviates by more than OutlierDeviation
+	OutlierRate float64 `json:"outlier_rate"`
+	// FarthestRate is the share of articles scored by several models where this model
+	// was the farthest from the consensus
+	FarthestRate float64 `json:"farthest_rate"`
+}
+
+// modelConsensusRow is one model score next to its article's composite
+type modelConsensusRow struct {
+	ArticleID int64   `db:"article_id"`
+	Model     string  `db:"model"`
+	Score     float64 `db:"score"`
+	Consensus float64 `db:"consensus"`
+}
+
+// GetOutlierModels compares every model score with the article's stored LLM composite,
+// the ensemble consensus, and returns per-model deviation stats, the models most often
+// farthest from the consensus first. Articles with a manual score are left out.
+func GetOutlierModels(db *sqlx.DB) ([]OutlierModel, error) {
+	var rows []modelConsensusRow
+	err := db.Select(&rows, `
+		SELECT s.article_id, s.model, s.score, a.composite_score AS consensus
+		FROM llm_scores s
+		JOIN articles a ON a.id = s.article_id
+		WHERE s.model NOT IN ('ensemble', 'manual')
+			AND a.composite_score IS NOT NULL
+			AND COALESCE(a.score_source, '') != 'manual'
+		ORDER BY s.article_id, s.model`)
+	if err != nil {
+		return nil, err
+	}
+
+	type totals struct {
+		OutlierModel
+		sumDev, sumAbs   float64
+		outliers         int
+		farthest, shared int
+	}
+	byModel := make(map[string]*totals)
+	statsFor := func(model string) *totals {
+		t, ok := byModel[model]
+		if !ok {
+			t = &totals{OutlierModel: OutlierModel{Model: model}}
+			byModel[model] = t
+		}
+		return t
+	}
+
+	// Rows are ordered by article, so each article's scores are contiguous
+	for start := 0; start < len(rows); {
+		end := start
+		for end < len(rows) && rows[end].ArticleID == rows[start].ArticleID {
+			end++
+		}
+		article := rows[start:end]
+
+		farthest, farthestDev := "", -1.0
+		for _, r := range article {
+			dev := r.Score - r.Consensus
+			abs := math.Abs(dev)
+			t := statsFor(r.Model)
+			t.Articles++
+			t.sumDev += dev
+			t.sumAbs += abs
+			if abs > t.MaxAbsDeviation {
+				t.MaxAbsDeviation = abs
+			}
+			if abs > OutlierDeviation {
+				t.outliers++
+			}
+			if len(article) > 1 {
+				t.shared++
+				if abs > farthestDev {
+					farthest, farthestDev = r.Model, abs
+				}
+			}
+		}
+		if farthest != "" {
+			byModel[farthest].farthest++
+		}
+		start = end
+	}
+
+	result := make([]OutlierModel, 0, len(byModel))
+	for _, t := range byModel {
+		m := t.OutlierModel
+		m.MeanDeviation = t.sumDev / float64(m.Articles)
+		m.MeanAbsDeviation = t.sumAbs / float64(m.Articles)
+		m.OutlierRate = float64(t.outliers) / float64(m.Articles)
+		if t.shared > 0 {
+			m.FarthestRate = float64(t.farthest) / float64(t.shared)
+		}
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FarthestRate != result[j].FarthestRate {
+			return result[i].FarthestRate > result[j].FarthestRate
+		}
+		if result[i].MeanAbsDeviation != result[j].MeanAbsDeviation {
+			return result[i].MeanAbsDeviation > result[j].MeanAbsDeviation
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result, nil
+}
+
+// ClassScores are precision, recall and F1 for one label, with Support the number of
+// samples whose true label it is
+type ClassScores struct {
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Support   int     `json:"support"`
+}
+
+// ValidationRun holds the results of a cmd/validate_labels run
+type ValidationRun struct {
+	ID            int64     `json:"id"`
+	RunAt         time.Time `json:"run_at"`
+	Total         int       `json:"total"`
+	Correct       int       `json:"correct"`
+	Incorrect     int       `json:"incorrect"`
+	Uncertain     int       `json:"uncertain"`
+	Disagreements int       `json:"disagreements"`
+	Accuracy      float64   `json:"accuracy"`
+	Precision     float64   `json:"precision"`
+	Recall        float64   `json:"recall"`
+	F1            float64   `json:"f1"`
+	// ConfusionMatrix counts samples by true label, then predicted label
+	ConfusionMatrix map[string]map[string]int `json:"confusion_matrix"`
+	PerClass        map[string]ClassScores    `json:"per_class"`
+}
+
+// PerClassScores computes one-vs-rest precision, recall and F1 for every label in a
+// confusion matrix keyed by true label, then predicted label
+func PerClassScores(confusion map[string]map[string]int) map[string]ClassScores {
+	classes := make(map[string]bool)
+	for trueLabel, preds := range confusion {
+		classes[trueLabel] = true
+		for predLabel := range preds {
+			classes[predLabel] = true
+		}
+	}
+
+	scores := make(map[string]ClassScores, len(classes))
+	for class := range classes {
+		var tp, fp, fn int
+		for trueLabel, preds := range confusion {
+			for predLabel, count := range preds {
+				switch {
+				case trueLabel == class && predLabel == class:
+					tp += count
+				case predLabel == class:
+					fp += count
+				case trueLabel == class:
+					fn += count
+				}
+			}
+		}
+		s := ClassScores{Support: tp + fn}
+		if tp+fp > 0 {
+			s.Precision = float64(tp) / float64(tp+fp)
+		}
+		if tp+fn > 0 {
+			s.Recall = float64(tp) / float64(tp+fn)
+		}
+		if s.Precision+s.Recall > 0 {
+			s.F1 = 2 * s.Precision * s.Recall / (s.Precision + s.Recall)
+		}
+		scores[class] = s
+	}
+	return scores
+}
+
+// validationRunRow is how a ValidationRun is stored, with the maps as JSON
+type validationRunRow struct {
+	ID              int64     `db:"id"`
+	RunAt           time.Time `db:"run_at"`
+	Total           int       `db:"total"`
+	Correct         int       `db:"correct"`
+	Incorrect       int       `db:"incorrect"`
+	Uncertain       int       `db:"uncertain"`
+	Disagreements   int       `db:"disagreements"`
+	Accuracy        float64   `db:"accuracy"`
+	Precision       float64   `db:"precision"`
+	Recall          float64   `db:"recall"`
+	F1              float64   `db:"f1"`
+	ConfusionMatrix string    `db:"confusion_matrix"`
+	PerClass        string    `db:"per_class"`
+}
+
+// SaveValidationRun stores a validation run, filling in PerClass from the confusion
+// matrix when it is empty and RunAt when it is zero
+func SaveValidationRun(db *sqlx.DB, run *ValidationRun) error {
+	if run.PerClass == nil {
+		run.PerClass = PerClassScores(run.ConfusionMatrix)
+	}
+	if run.RunAt.IsZero() {
+		run.RunAt = time.Now().UTC()
+	}
+	confusion, err := json.Marshal(run.ConfusionMatrix)
+	if err != nil {
+		return fmt.Errorf("failed to marshal confusion matrix: %w", err)
+	}
+	perClass, err := json.Marshal(run.PerClass)
+	if err != nil {
+		return fmt.Errorf("failed to marshal per-class scores: %w", err)
+	}
+
+	result, err := db.NamedExec(`
+		INSERT INTO validation_runs (run_at, total, correct, incorrect, uncertain, disagreements,
+			accuracy, precision, recall, f1, confusion_matrix, per_class)
+		VALUES (:run_at, :total, :correct, :incorrect, :uncertain, :disagreements,
+			:accuracy, :precision, :recall, :f1, :confusion_matrix, :per_class)`,
+		validationRunRow{
+			RunAt: run.RunAt, Total: run.Total, Correct: run.Correct, Incorrect: run.Incorrect,
+			Uncertain: run.Uncertain, Disagreements: run.Disagreements,
+			Accuracy: run.Accuracy, Precision: run.Precision, Recall: run.Recall, F1: run.F1,
+			ConfusionMatrix: string(confusion), PerClass: string(perClass),
+		})
+	if err != nil {
+		return err
+	}
+	run.ID, err = result.LastInsertId()
+	return err
+}
+
+// GetLatestValidationRun returns the most recent validation run, or nil when none
+// has been recorded
+func GetLatestValidationRun(db *sqlx.DB) (*ValidationRun, error) {
+	var row validationRunRow
+	err := db.Get(&row, `SELECT * FROM validation_runs ORDER BY run_at DESC, id DESC LIMIT 1`)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	run := &ValidationRun{
+		ID: row.ID, RunAt: row.RunAt, Total: row.Total, Correct: row.Correct, Incorrect: row.Incorrect,
+		Uncertain: row.Uncertain, Disagreements: row.Disagreements,
+		Accuracy: row.Accuracy, Precision: row.Precision, Recall: row.Recall, F1: row.F1,
+	}
+	if err := json.Unmarshal([]byte(row.ConfusionMatrix), &run.ConfusionMatrix); err != nil {
+		return nil, fmt.Errorf("invalid confusion matrix for validation run %d: %w", row.ID, err)
+	}
+	if err := json.Unmarshal([]byte(row.PerClass), &run.PerClass); err != nil {
+		return nil, fmt.Errorf("invalid per-class scores for validation run %d: %w", row.ID, err)
+	}
+	return run, nil
+}
+
+// CostEstimator prices a model's token counts in dollars, returning false when the
+// model has no price rate
+type CostEstimator func(model string, promptTokens, completionTokens int) (float64, bool)
+
+// ModelCost is the token usage and estimated spend of one model
+type ModelCost struct {
+	Model            string  `json:"model" db:"model"`
+	Calls            int     `json:"calls" db:"calls"`
+	CallsWithUsage   int     `json:"calls_with_usage" db:"calls_with_usage"`
+	PromptTokens     int     `json:"prompt_tokens" db:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens" db:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens" db:"total_tokens"`
+	EstimatedCost    float64 `json:"estimated_cost"`
+	Priced           bool    `json:"priced"`
+}
+
+// LLMCostReport totals the token usage and estimated spend of the LLM calls made in a
+// date range, with a breakdown per model
+type LLMCostReport struct {
+	From             *time.Time  `json:"from,omitempty"`
+	To               *time.Time  `json:"to,omitempty"`
+	Calls            int         `json:"calls"`
+	CallsWithUsage   int         `json:"calls_with_usage"`
+	PromptTokens     int         `json:"prompt_tokens"`
+	CompletionTokens int         `json:"completion_tokens"`
+	TotalTokens      int         `json:"total_tokens"`
+	EstimatedCost    float64     `json:"estimated_cost"`
+	UnpricedModels   []string    `json:"unpriced_models"`
+	Models           []ModelCost `json:"models"`
+}
+
+// usageTimeLayout is how SQLite's CURRENT_TIMESTAMP stores llm_usage.created_at
+const usageTimeLayout = "2006-01-02 15:04:05"
+
+// GetLLMCost totals the token usage recorded for every LLM call made from from up to
+// but not including to; nil bounds are open. Usage is attributed to the model that was
+// called, a fallback when one was used, and covers retries and calls whose score was
+// later replaced. Calls whose provider reported no usage are counted but add no tokens.
+func GetLLMCost(db *sqlx.DB, from, to *time.Time, estimate CostEstimator) (*LLMCostReport, error) {
+	query := `SELECT model, COUNT(*) AS calls, COUNT(total_tokens) AS calls_with_usage,
+			COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens,
+			COALESCE(SUM(completion_tokens), 0) AS completion_tokens,
+			COALESCE(SUM(total_tokens), 0) AS total_tokens
+		FROM llm_usage WHERE 1 = 1`
+	var args []interface{}
+	if from != nil {
+		query += " AND created_at >= ?"
+		args = append(args, from.UTC().Format(usageTimeLayout))
+	}
+	if to != nil {
+		query += " AND created_at < ?"
+		args = append(args, to.UTC().Format(usageTimeLayout))
+	}
+	var byModel []ModelCost
+	if err := db.Select(&byModel, query+" GROUP BY model", args...); err != nil {
+		return nil, err
+	}
+
+	report := &LLMCostReport{From: from, To: to, UnpricedModels: []string{}}
+	report.Models = make([]ModelCost, 0, len(byModel))
+	for _, m := range byModel {
+		if estimate != nil {
+			m.EstimatedCost, m.Priced = estimate(m.Model, m.PromptTokens, m.CompletionTokens)
+		}
+		report.Models = append(report.Models, m)
+	}
+	sort.Slice(report.Models, func(i, j int) bool {
+		if report.Models[i].EstimatedCost != report.Models[j].EstimatedCost {
+			return report.Models[i].EstimatedCost > report.Models[j].EstimatedCost
+		}
+		return report.Models[i].Model < report.Models[j].Model
+	})
+	for _, m := range report.Models {
+		if !m.Priced && m.CallsWithUsage > 0 {
+			report.UnpricedModels = append(report.UnpricedModels, m.Model)
+		}
+		report.Calls += m.Calls
+		report.CallsWithUsage += m.CallsWithUsage
+		report.PromptTokens += m.PromptTokens
+		report.CompletionTokens += m.CompletionTokens
+		report.TotalTokens += m.TotalTokens
+		report.EstimatedCost += m.EstimatedCost
+	}
+	sort.Strings(report.UnpricedModels)
+	return report, nil
+}
@@ -0,0 +1,205 @@
+package metrics
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetScoreStability(t *testing.T) {
+	conn, err := db.InitDB(filepath.Join(t.TempDir(), "stability.db"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	record := func(articleID int64, kind string, scores ...float64) {
+		for _, s := range scores {
+			_, err := db.RecordScoreVersion(conn, articleID, s, 0.8, kind)
+			require.NoError(t, err)
+		}
+	}
+	record(1, db.ScoreVersionAnalysis, -0.6, 0.4, -0.5) // flip-flops
+	record(2, db.ScoreVersionAnalysis, 0.2, 0.22, 0.21)
+	record(3, db.ScoreVersionAnalysis, 0.3) // analysed once
+	record(2, db.ScoreVersionRecompute, -0.9)
+
+	report, err := GetScoreStability(conn, DefaultStabilityThreshold, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.ArticlesChecked)
+	assert.Equal(t, 1, report.UnstableCount)
+	require.Len(t, report.Articles, 2)
+	assert.Equal(t, int64(1), report.Articles[0].ArticleID, "most variable first")
+	assert.True(t, report.Articles[0].Unstable)
+	assert.Equal(t, 3, report.Articles[0].Versions)
+	assert.InDelta(t, 0.2022, report.Articles[0].Variance, 1e-3)
+	assert.False(t, report.Articles[1].Unstable, "recomputes do not count towards stability")
+	assert.Equal(t, 3, report.Articles[1].Versions)
+
+	report, err = GetScoreStability(conn, DefaultStabilityThreshold, true)
+	require.NoError(t, err)
+	assert.Len(t, report.Articles, 1)
+	assert.Equal(t, 2, report.ArticlesChecked)
+}
+
+func TestGetOutlierModels(t *testing.T) {
+	conn, err := db.InitDB(filepath.Join(t.TempDir(), "outliers.db"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	articles := 0
+	article := func(composite float64, source string, scores map[string]float64) {
+		articles++
+		res, err := conn.Exec(`INSERT INTO articles (source, pub_date, url, title, content, created_at, composite_score, score_source)
+			VALUES ('src', CURRENT_TIMESTAMP, ?, 't', 'c', CURRENT_TIMESTAMP, ?, ?)`, fmt.Sprintf("https://example.com/%d", articles), composite, source)
+		require.NoError(t, err)
+		id, _ := res.LastInsertId()
+		for model, score := range scores {
+			_, err := conn.Exec(`INSERT INTO llm_scores (article_id, model, score, metadata, created_at) VALUES (?, ?, ?, '{}', CURRENT_TIMESTAMP)`,
+				id, model, score)
+			require.NoError(t, err)
+		}
+	}
+	article(0.1, "llm", map[string]float64{"left": -0.1, "center": 0.1, "wild": 0.9, "ensemble": 0.1})
+	article(-0.2, "llm", map[string]float64{"left": -0.3, "center": -0.2, "wild": 0.5})
+	article(0.0, "llm", map[string]float64{"left": -0.5, "center": 0.0})
+	article(0.8, "manual", map[string]float64{"left": -1.0, "manual": 0.8})
+
+	models, err := GetOutlierModels(conn)
+	require.NoError(t, err)
+	require.Len(t, models, 3)
+
+	assert.Equal(t, "wild", models[0].Model, "the model most often farthest from consensus comes first")
+	assert.Equal(t, 2, models[0].Articles)
+	assert.InDelta(t, 0.75, models[0].MeanDeviation, 1e-9)
+	assert.InDelta(t, 0.8, models[0].MaxAbsDeviation, 1e-9)
+	assert.InDelta(t, 1.0, models[0].OutlierRate, 1e-9)
+	assert.InDelta(t, 1.0, models[0].FarthestRate, 1e-9)
+
+	assert.Equal(t, "left", models[1].Model)
+	assert.Equal(t, 3, models[1].Articles, "articles with a manual score are left out")
+	assert.InDelta(t, -0.8/3, models[1].MeanDeviation, 1e-9)
+	assert.InDelta(t, 0.0, models[1].OutlierRate, 1e-9)
+	assert.InDelta(t, 1.0/3, models[1].FarthestRate, 1e-9)
+
+	assert.Equal(t, "center", models[2].Model)
+	assert.Zero(t, models[2].MeanAbsDeviation)
+}
+
+func TestValidationRuns(t *testing.T) {
+	conn, err := db.InitDB(filepath.Join(t.TempDir(), "validation.db"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	run, err := GetLatestValidationRun(conn)
+	require.NoError(t, err)
+	assert.Nil(t, run)
+
+	confusion := map[string]map[string]int{
+		"left":    {"left": 8, "right": 0, "neutral": 2},
+		"right":   {"left": 1, "right": 6, "neutral": 3},
+		"neutral": {"left": 1, "right": 0, "neutral": 9},
+	}
+	older := &ValidationRun{RunAt: time.Now().Add(-time.Hour), Total: 1, ConfusionMatrix: map[string]map[string]int{}}
+	require.NoError(t, SaveValidationRun(conn, older))
+	require.NoError(t, SaveValidationRun(conn, &ValidationRun{Total: 30, Correct: 23, Accuracy: 23.0 / 30, ConfusionMatrix: confusion}))
+
+	run, err = GetLatestValidationRun(conn)
+	require.NoError(t, err)
+	require.NotNil(t, run)
+	assert.Equal(t, 30, run.Total)
+	assert.Equal(t, confusion, run.ConfusionMatrix)
+	require.Contains(t, run.PerClass, "left")
+	assert.InDelta(t, 0.8, run.PerClass["left"].Precision, 1e-9)
+	assert.InDelta(t, 0.8, run.PerClass["left"].Recall, 1e-9)
+	assert.InDelta(t, 1.0, run.PerClass["right"].Precision, 1e-9)
+	assert.InDelta(t, 0.6, run.PerClass["right"].Recall, 1e-9)
+	assert.InDelta(t, 0.75, run.PerClass["right"].F1, 1e-9)
+	assert.Equal(t, 10, run.PerClass["neutral"].Support)
+}
+
+func TestGetLLMCost(t *testing.T) {
+	conn, err := db.InitDB(filepath.Join(t.TempDir(), "cost.db"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	day := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	for _, call := range []struct {
+		model  string
+		tokens *db.TokenCounts
+		at     time.Time
+	}{
+		{"left", &db.TokenCounts{Prompt: 600, Completion: 100, Total: 700}, day},
+		{"left", &db.TokenCounts{Prompt: 400, Completion: 100, Total: 500}, day}, // a retry
+		{"backup", &db.TokenCounts{Prompt: 500, Completion: 100, Total: 600}, day},
+		{"right", nil, day}, // provider reported no usage
+		{"old", &db.TokenCounts{Prompt: 9000, Completion: 9000, Total: 18000}, day.AddDate(0, -1, 0)},
+	} {
+		require.NoError(t, db.InsertLLMUsage(conn, call.model, call.tokens))
+		_, err := conn.Exec(`UPDATE llm_usage SET created_at = ? WHERE id = (SELECT MAX(id) FROM llm_usage)`,
+			call.at.Format("2006-01-02 15:04:05"))
+		require.NoError(t, err)
+	}
+	prices := func(model string, promptTokens, completionTokens int) (float64, bool) {
+		if model != "left" {
+			return 0, false
+		}
+		return float64(promptTokens)*1e-6 + float64(completionTokens)*5e-6, true
+	}
+
+	from, to := day.AddDate(0, 0, -1), day.AddDate(0, 0, 1)
+	report, err := GetLLMCost(conn, &from, &to, prices)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Calls, "calls outside the range are left out")
+	assert.Equal(t, 3, report.CallsWithUsage)
+	assert.Equal(t, 1500, report.PromptTokens)
+	assert.Equal(t, 300, report.CompletionTokens)
+	assert.Equal(t, 1800, report.TotalTokens)
+	assert.InDelta(t, 0.002, report.EstimatedCost, 1e-12)
+	assert.Equal(t, []string{"backup"}, report.UnpricedModels)
+	require.Len(t, report.Models, 3)
+	assert.Equal(t, "left", report.Models[0].Model)
+	assert.Equal(t, 2, report.Models[0].Calls, "every call counts, not only the stored score")
+	assert.True(t, report.Models[0].Priced)
+	assert.Equal(t, "right", report.Models[2].Model)
+	assert.Equal(t, 1, report.Models[2].Calls)
+	assert.Zero(t, report.Models[2].CallsWithUsage)
+
+	report, err = GetLLMCost(conn, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5, report.Calls)
+	assert.Equal(t, 19800, report.TotalTokens)
+	assert.Zero(t, report.EstimatedCost)
+}
+
+func TestGetFeedbackSummary(t *testing.T) {
+	conn, err := db.InitDB(filepath.Join(t.TempDir(), "feedback.db"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	day := time.Date(2026, 4, 2, 9, 0, 0, 0, time.UTC)
+	for _, f := range []struct{ category, status string }{
+		{"agree", db.FeedbackStatusNew},
+		{"agree", db.FeedbackStatusReviewed},
+		{"agree", db.FeedbackStatusSpam},
+		{"disagree", db.FeedbackStatusSpam},
+	} {
+		require.NoError(t, db.InsertFeedback(conn, &db.Feedback{
+			ArticleID: 1, UserID: "u", FeedbackText: "t", Category: f.category, Status: f.status, CreatedAt: day,
+		}))
+	}
+
+	all, err := GetFeedbackSummary(conn, false)
+	require.NoError(t, err)
+	assert.Equal(t, []FeedbackSummary{
+		{Day: "2026-04-02", Category: "agree", FeedbackCount: 3},
+		{Day: "2026-04-02", Category: "disagree", FeedbackCount: 1},
+	}, all)
+
+	withoutSpam, err := GetFeedbackSummary(conn, true)
+	require.NoError(t, err)
+	assert.Equal(t, []FeedbackSummary{{Day: "2026-04-02", Category: "agree", FeedbackCount: 2}}, withoutSpam)
+}
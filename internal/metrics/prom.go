@@ -141,6 +141,14 @@ var (
 		},
 	)
 
+	ReanalysisJobs = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "newsbalancer_reanalysis_jobs",
+			Help: "Number of reanalysis jobs by state (active, queued)",
+		},
+		[]string{"state"},
+	)
+
 	LLMAnalysesTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "newsbalancer_llm_analyses_total",
@@ -208,6 +216,22 @@ var (
 		},
 		[]string{"type", "model"},
 	)
+
+	LLMTokensTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "newsbalancer_llm_tokens_total",
+			Help: "Total number of tokens used by LLM calls, by model and prompt or completion",
+		},
+		[]string{"model", "type"},
+	)
+
+	LLMCallsWithoutUsage = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "newsbalancer_llm_calls_without_usage_total",
+			Help: "Total number of successful LLM calls whose response reported no token usage",
+		},
+		[]string{"model"},
+	)
 )
 
 func InitLLMMetrics() {
@@ -236,6 +260,7 @@ func InitLLMMetrics() {
 	prometheus.MustRegister(ArticlesByStatus)
 	prometheus.MustRegister(LLMQueueSize)
 	prometheus.MustRegister(LLMQueueProcessing)
+	prometheus.MustRegister(ReanalysisJobs)
 	prometheus.MustRegister(LLMAnalysesTotal)
 	prometheus.MustRegister(LLMRequestDuration)
 	prometheus.MustRegister(LLMConfidenceScore)
@@ -244,6 +269,8 @@ func InitLLMMetrics() {
 	prometheus.MustRegister(HTTPRequestsTotal)
 	prometheus.MustRegister(HTTPRequestDuration)
 	prometheus.MustRegister(LLMErrorsTotal)
+	prometheus.MustRegister(LLMTokensTotal)
+	prometheus.MustRegister(LLMCallsWithoutUsage)
 }
 
 func IncLLMRequest(model, promptHash string) {
@@ -331,6 +358,12 @@ func SetLLMQueueProcessing(count int) {
 	LLMQueueProcessing.Set(float64(count))
 }
 
+// SetReanalysisJobs records how many reanalysis jobs are scoring and waiting for a slot
+func SetReanalysisJobs(active, queued int) {
+	ReanalysisJobs.WithLabelValues("active").Set(float64(active))
+	ReanalysisJobs.WithLabelValues("queued").Set(float64(queued))
+}
+
 func IncLLMAnalysis(status, model string) {
 	LLMAnalysesTotal.WithLabelValues(status, model).Inc()
 }
@@ -362,3 +395,14 @@ func RecordHTTPDuration(duration time.Duration, method, endpoint string) {
 func IncLLMError(errorType, model string) {
 	LLMErrorsTotal.WithLabelValues(errorType, model).Inc()
 }
+
+// RecordLLMTokens adds one call's prompt and completion tokens to the model's totals
+func RecordLLMTokens(model string, promptTokens, completionTokens int) {
+	LLMTokensTotal.WithLabelValues(model, "prompt").Add(float64(promptTokens))
+	LLMTokensTotal.WithLabelValues(model, "completion").Add(float64(completionTokens))
+}
+
+// IncLLMCallWithoutUsage counts a call whose provider reported no token usage
+func IncLLMCallWithoutUsage(model string) {
+	LLMCallsWithoutUsage.WithLabelValues(model).Inc()
+}
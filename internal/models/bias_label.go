@@ -0,0 +1,35 @@
+package models
+
+// BiasLabelThreshold separates center from left and right, matching the leaning filter
+const BiasLabelThreshold = 0.1
+
+// Bias labels derived from a score
+const (
+	BiasLabelLeft   = "left"
+	BiasLabelCenter = "center"
+	BiasLabelRight  = "right"
+)
+
+// BiasNeutralZone is the score range BiasLabel maps to center, for clients drawing
+// the neutral band of a bias bar
+type BiasNeutralZone struct {
+	Low  float64 `json:"low" example:"-0.1"`
+	High float64 `json:"high" example:"0.1"`
+}
+
+// NeutralZone returns the range of scores labelled center
+func NeutralZone() BiasNeutralZone {
+	return BiasNeutralZone{Low: -BiasLabelThreshold, High: BiasLabelThreshold}
+}
+
+// BiasLabel maps a bias score to left, center or right
+func BiasLabel(score float64) string {
+	switch {
+	case score < -BiasLabelThreshold:
+		return BiasLabelLeft
+	case score > BiasLabelThreshold:
+		return BiasLabelRight
+	default:
+		return BiasLabelCenter
+	}
+}
@@ -12,4 +12,45 @@ type ProgressState struct {
 	ErrorDetails string   `json:"error_details,omitempty"`              // Structured error details (JSON string)
 	FinalScore   *float64 `json:"final_score,omitempty" example:"0.25"` // Final score if completed
 	LastUpdated  int64    `json:"last_updated" example:"1609459200"`    // Timestamp
+
+	// QueuePosition is the 1-based place in the reanalysis queue while Status is Queued
+	QueuePosition int `json:"queue_position,omitempty" example:"2"`
+
+	// Change compares the new score with the one it replaced; set on completion
+	Change *ScoreChange `json:"change,omitempty"`
+
+	// SkippedUnchanged is set when a reanalysis kept the existing score because the
+	// article's content had not changed since it was last analyzed
+	SkippedUnchanged bool `json:"skipped_unchanged,omitempty"`
+}
+
+// ScoreSnapshot is a composite score with its confidence and bias label
+type ScoreSnapshot struct {
+	Score      float64 `json:"score" example:"0.1"`
+	Confidence float64 `json:"confidence" example:"0.8"`
+	Label      string  `json:"label" example:"center"`
+}
+
+// ScoreChange describes how a reanalysis moved an article's score
+type ScoreChange struct {
+	// Previous is null when the article had no score before
+	Previous *ScoreSnapshot `json:"previous"`
+	Current  ScoreSnapshot  `json:"current"`
+	// Delta is the current minus the previous score, when there was one
+	Delta *float64 `json:"delta,omitempty" example:"0.3"`
+}
+
+// NewScoreSnapshot builds a snapshot, labelling the score
+func NewScoreSnapshot(score, confidence float64) ScoreSnapshot {
+	return ScoreSnapshot{Score: score, Confidence: confidence, Label: BiasLabel(score)}
+}
+
+// NewScoreChange compares a new score with the previous one, which may be nil
+func NewScoreChange(previous *ScoreSnapshot, current ScoreSnapshot) *ScoreChange {
+	change := &ScoreChange{Previous: previous, Current: current}
+	if previous != nil {
+		delta := current.Score - previous.Score
+		change.Delta = &delta
+	}
+	return change
 }
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Source represents a news source with channel-specific configuration
 // @Description A news source configuration for content ingestion
@@ -131,6 +134,36 @@ func IsValidCategory(category string) bool {
 	return false
 }
 
+// Source trust weights scale the composite confidence of a source's articles, never
+// their scores. They are set with {"trust_weight": 0.8} in the source metadata.
+const (
+	DefaultSourceTrustWeight = 1.0
+	MinSourceTrustWeight     = 0.5
+	MaxSourceTrustWeight     = 1.5
+)
+
+// SourceTrustWeight reads the trust weight from source metadata. Metadata without one
+// gives DefaultSourceTrustWeight; a trust_weight that is not a number between
+// MinSourceTrustWeight and MaxSourceTrustWeight gives ErrSourceInvalidTrustWeight.
+func SourceTrustWeight(metadata *string) (float64, error) {
+	if metadata == nil || *metadata == "" {
+		return DefaultSourceTrustWeight, nil
+	}
+	var meta map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(*metadata), &meta); err != nil {
+		return DefaultSourceTrustWeight, nil
+	}
+	raw, ok := meta["trust_weight"]
+	if !ok || string(raw) == "null" {
+		return DefaultSourceTrustWeight, nil
+	}
+	var weight float64
+	if err := json.Unmarshal(raw, &weight); err != nil || weight < MinSourceTrustWeight || weight > MaxSourceTrustWeight {
+		return DefaultSourceTrustWeight, ErrSourceInvalidTrustWeight
+	}
+	return weight, nil
+}
+
 // ToUpdateMap converts UpdateSourceRequest to a map for database updates
 func (r *UpdateSourceRequest) ToUpdateMap() map[string]interface{} {
 	updates := make(map[string]interface{})
@@ -180,6 +213,9 @@ func (r *CreateSourceRequest) Validate() error {
 	if r.DefaultWeight < 0 {
 		return ErrSourceInvalidWeight
 	}
+	if _, err := SourceTrustWeight(r.Metadata); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -191,5 +227,8 @@ func (r *UpdateSourceRequest) Validate() error {
 	if r.DefaultWeight != nil && *r.DefaultWeight < 0 {
 		return ErrSourceInvalidWeight
 	}
+	if _, err := SourceTrustWeight(r.Metadata); err != nil {
+		return err
+	}
 	return nil
 }
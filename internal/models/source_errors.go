@@ -11,6 +11,7 @@ var (
 	ErrSourceCategoryRequired    = errors.New("source category is required")
 	ErrSourceInvalidCategory     = errors.New("invalid category")
 	ErrSourceInvalidWeight       = errors.New("default weight must be non-negative")
+	ErrSourceInvalidTrustWeight  = errors.New("trust_weight must be a number between 0.5 and 1.5")
 	ErrSourceNotFound            = errors.New("source not found")
 	ErrSourceNameExists          = errors.New("source with this name already exists")
 )
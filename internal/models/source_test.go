@@ -465,3 +465,43 @@ func boolPtr(b bool) *bool {
 func float64Ptr(f float64) *float64 {
 	return &f
 }
+
+func TestSourceTrustWeight(t *testing.T) {
+	tests := []struct {
+		metadata string
+		want     float64
+		wantErr  bool
+	}{
+		{metadata: "", want: DefaultSourceTrustWeight},
+		{metadata: `{}`, want: DefaultSourceTrustWeight},
+		{metadata: `{"auto_score": true}`, want: DefaultSourceTrustWeight},
+		{metadata: `{"trust_weight": null}`, want: DefaultSourceTrustWeight},
+		{metadata: `not json`, want: DefaultSourceTrustWeight},
+		{metadata: `{"trust_weight": 0.5}`, want: 0.5},
+		{metadata: `{"trust_weight": 1.5}`, want: 1.5},
+		{metadata: `{"trust_weight": 0.8, "auto_score": true}`, want: 0.8},
+		{metadata: `{"trust_weight": 0.49}`, want: DefaultSourceTrustWeight, wantErr: true},
+		{metadata: `{"trust_weight": 1.51}`, want: DefaultSourceTrustWeight, wantErr: true},
+		{metadata: `{"trust_weight": 0}`, want: DefaultSourceTrustWeight, wantErr: true},
+		{metadata: `{"trust_weight": "high"}`, want: DefaultSourceTrustWeight, wantErr: true},
+	}
+	for _, tt := range tests {
+		metadata := tt.metadata
+		got, err := SourceTrustWeight(&metadata)
+		assert.Equal(t, tt.want, got, tt.metadata)
+		if tt.wantErr {
+			assert.ErrorIs(t, err, ErrSourceInvalidTrustWeight, tt.metadata)
+		} else {
+			assert.NoError(t, err, tt.metadata)
+		}
+	}
+	got, err := SourceTrustWeight(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultSourceTrustWeight, got)
+
+	invalid := `{"trust_weight": 2}`
+	create := CreateSourceRequest{Name: testSourceName, ChannelType: "rss", FeedURL: testFeedURL, Category: "center", Metadata: &invalid}
+	assert.ErrorIs(t, create.Validate(), ErrSourceInvalidTrustWeight)
+	update := UpdateSourceRequest{Metadata: &invalid}
+	assert.ErrorIs(t, update.Validate(), ErrSourceInvalidTrustWeight)
+}
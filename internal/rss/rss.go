@@ -2,6 +2,8 @@ package rss
 
 import (
 	"log"
+	"net/url"
+	"path"
 	"strings"
 	"time"
 
@@ -9,6 +11,7 @@ import (
 	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
 	"github.com/jmoiron/sqlx"
 	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
 	"github.com/robfig/cron/v3"
 )
 
@@ -19,11 +22,21 @@ type CollectorInterface interface {
 	CheckFeedHealth() map[string]bool
 }
 
+// IngestHook is told about every article the collector stores so scoring can follow
+// the ingestion scoring policy. sourceAutoScore reports whether the feed's source is
+// flagged for auto-scoring.
+type IngestHook interface {
+	ArticleIngested(articleID int64, sourceAutoScore bool) string
+}
+
 type Collector struct {
 	DB        *sqlx.DB
 	FeedURLs  []string
 	Cron      *cron.Cron
 	LLMClient *llm.LLMClient
+
+	ingestHook IngestHook
+	autoScore  map[string]bool // feed URLs whose source is flagged for auto-scoring
 }
 
 // NewCollector creates a new RSS Collector with DB and feed URLs.
@@ -36,6 +49,11 @@ func NewCollector(dbConn *sqlx.DB, urls []string, llmClient *llm.LLMClient) *Col
 	}
 }
 
+// SetIngestHook registers the hook told about newly stored articles
+func (c *Collector) SetIngestHook(hook IngestHook) {
+	c.ingestHook = hook
+}
+
 // StartScheduler starts the cron job to fetch feeds every 30 minutes.
 func (c *Collector) StartScheduler() {
 	_, err := c.Cron.AddFunc("@every 30m", func() {
@@ -70,14 +88,19 @@ func (c *Collector) LoadSourcesFromDB() error {
 
 	// Convert sources to URL slice for backward compatibility
 	urls := make([]string, 0, len(sources))
+	autoScore := make(map[string]bool)
 	for _, source := range sources {
 		if source.ChannelType == "rss" && source.FeedURL != "" {
 			urls = append(urls, source.FeedURL)
+			if source.AutoScore() {
+				autoScore[source.FeedURL] = true
+			}
 			log.Printf("[RSS] Loaded source: %s (%s)", source.Name, source.FeedURL)
 		}
 	}
 
 	c.FeedURLs = urls
+	c.autoScore = autoScore
 	log.Printf("[RSS] Loaded %d RSS sources from database", len(urls))
 	return nil
 }
@@ -105,12 +128,12 @@ func (c *Collector) FetchAndStore() {
 		}
 
 		for _, item := range feed.Items {
-			c.processFeedItem(feed, item)
+			c.processFeedItem(feedURL, feed, item)
 		}
 	}
 }
 
-func (c *Collector) processFeedItem(feed *gofeed.Feed, item *gofeed.Item) {
+func (c *Collector) processFeedItem(feedURL string, feed *gofeed.Feed, item *gofeed.Item) {
 	if c.shouldSkipItem(item) {
 		return
 	}
@@ -137,10 +160,14 @@ func (c *Collector) processFeedItem(feed *gofeed.Feed, item *gofeed.Item) {
 
 	article := c.createArticle(feed, item)
 
-	if err := c.storeArticle(article); err != nil {
+	id, err := c.storeArticle(article)
+	if err != nil {
 		log.Printf("[RSS] Failed to store article: %v", err)
 		return
 	}
+	if c.ingestHook != nil {
+		c.ingestHook.ArticleIngested(id, c.autoScore[feedURL])
+	}
 }
 
 func (c *Collector) fetchFeed(parser *gofeed.Parser, feedURL string) *gofeed.Feed {
@@ -197,23 +224,142 @@ func (c *Collector) createArticle(feed *gofeed.Feed, item *gofeed.Item) *db.Arti
 		pubTime = *item.PublishedParsed
 	}
 
-	return &db.Article{
+	article := &db.Article{
 		Source:  feed.Title,
 		PubDate: pubTime,
 		URL:     item.Link,
 		Title:   item.Title,
 		Content: c.extractContent(item),
 	}
+	if image := extractImageURL(item); image != "" {
+		article.ImageURL = &image
+	}
+	article.Authors = extractAuthors(item)
+	article.Categories = cleanTerms(item.Categories)
+	return article
+}
+
+// maxTerms and maxTermLength bound the authors and categories kept per item, since
+// some feeds stuff them with keyword lists
+const (
+	maxTerms      = 20
+	maxTermLength = 200
+)
+
+// extractAuthors returns the names of the item's authors. Authors given only as an
+// email address are left out.
+func extractAuthors(item *gofeed.Item) db.StringList {
+	var names []string
+	for _, author := range item.Authors {
+		if author != nil {
+			names = append(names, author.Name)
+		}
+	}
+	return cleanTerms(names)
 }
 
-func (c *Collector) storeArticle(article *db.Article) error {
-	_, err := db.InsertArticle(c.DB, article)
+// cleanTerms trims and collapses whitespace, drops empty and overlong entries and
+// case-insensitive duplicates, and keeps at most maxTerms in feed order
+func cleanTerms(terms []string) db.StringList {
+	var out db.StringList
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		term = strings.Join(strings.Fields(term), " ")
+		key := strings.ToLower(term)
+		if term == "" || len(term) > maxTermLength || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, term)
+		if len(out) == maxTerms {
+			break
+		}
+	}
+	return out
+}
+
+// maxImageURLLength bounds stored image URLs; longer ones are usually data URIs or
+// tracking links
+const maxImageURLLength = 2048
+
+// extractImageURL picks the item's image from, in order, the parsed item image,
+// media:content, media:thumbnail and image enclosures. When a feed lists several
+// images the first valid one wins; items without one return "".
+func extractImageURL(item *gofeed.Item) string {
+	var candidates []string
+	if item.Image != nil {
+		candidates = append(candidates, item.Image.URL)
+	}
+	if media, ok := item.Extensions["media"]; ok {
+		for _, content := range mediaElements(media, "content") {
+			medium, typ := content.Attrs["medium"], content.Attrs["type"]
+			if medium == "image" || strings.HasPrefix(typ, "image/") || (medium == "" && typ == "" && looksLikeImage(content.Attrs["url"])) {
+				candidates = append(candidates, content.Attrs["url"])
+			}
+		}
+		for _, thumb := range mediaElements(media, "thumbnail") {
+			candidates = append(candidates, thumb.Attrs["url"])
+		}
+	}
+	for _, enc := range item.Enclosures {
+		if enc != nil && (strings.HasPrefix(enc.Type, "image/") || (enc.Type == "" && looksLikeImage(enc.URL))) {
+			candidates = append(candidates, enc.URL)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if valid, ok := validImageURL(candidate); ok {
+			return valid
+		}
+	}
+	return ""
+}
+
+// mediaElements returns the media elements with the given name, including those
+// nested in media:group
+func mediaElements(media map[string][]ext.Extension, name string) []ext.Extension {
+	out := append([]ext.Extension(nil), media[name]...)
+	for _, group := range media["group"] {
+		out = append(out, group.Children[name]...)
+	}
+	return out
+}
+
+// validImageURL accepts absolute http and https URLs of reasonable length
+func validImageURL(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > maxImageURLLength {
+		return "", false
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false
+	}
+	return u.String(), true
+}
+
+// looksLikeImage guesses from the file extension for media without a type
+func looksLikeImage(raw string) bool {
+	u, err := url.Parse(raw)
 	if err != nil {
-		return err
+		return false
+	}
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp", ".avif":
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Collector) storeArticle(article *db.Article) (int64, error) {
+	id, err := db.InsertArticle(c.DB, article)
+	if err != nil {
+		return 0, err
 	}
 
 	log.Printf("[RSS] Inserted new article: %s", article.URL)
-	return nil
+	return id, nil
 }
 
 // isValidItem performs basic validation on a feed item.
@@ -1,6 +1,7 @@
 package rss
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/mmcdole/gofeed"
@@ -32,3 +33,98 @@ func TestIsValidItem(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractImageURL(t *testing.T) {
+	const feedXML = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+<channel><title>Feed</title>
+<item><title>enclosure</title><link>http://example.com/1</link>
+  <enclosure url="http://example.com/audio.mp3" type="audio/mpeg" length="1"/>
+  <enclosure url="https://img.example.com/a.jpg" type="image/jpeg" length="1"/>
+</item>
+<item><title>media content</title><link>http://example.com/2</link>
+  <media:content url="https://img.example.com/video.mp4" medium="video"/>
+  <media:content url="https://img.example.com/b.png" medium="image"/>
+</item>
+<item><title>media group</title><link>http://example.com/3</link>
+  <media:group><media:thumbnail url="https://img.example.com/c.webp"/></media:group>
+</item>
+<item><title>invalid first</title><link>http://example.com/4</link>
+  <media:thumbnail url="javascript:alert(1)"/>
+  <media:thumbnail url="/relative.jpg"/>
+  <enclosure url="https://img.example.com/d.gif" type="" length="1"/>
+</item>
+<item><title>none</title><link>http://example.com/5</link>
+  <enclosure url="http://example.com/audio.mp3" type="audio/mpeg" length="1"/>
+</item>
+</channel></rss>`
+
+	feed, err := gofeed.NewParser().ParseString(feedXML)
+	if err != nil {
+		t.Fatalf("parse feed: %v", err)
+	}
+	want := []string{
+		"https://img.example.com/a.jpg",
+		"https://img.example.com/b.png",
+		"https://img.example.com/c.webp",
+		"https://img.example.com/d.gif",
+		"",
+	}
+	if len(feed.Items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(feed.Items), len(want))
+	}
+	for i, item := range feed.Items {
+		if got := extractImageURL(item); got != want[i] {
+			t.Errorf("extractImageURL(%q) = %q, want %q", item.Title, got, want[i])
+		}
+	}
+
+	article := (&Collector{}).createArticle(feed, feed.Items[0])
+	if article.ImageURL == nil || *article.ImageURL != want[0] {
+		t.Errorf("createArticle did not keep the image URL: %v", article.ImageURL)
+	}
+	if article := (&Collector{}).createArticle(feed, feed.Items[4]); article.ImageURL != nil {
+		t.Errorf("createArticle set an image for an item without one: %q", *article.ImageURL)
+	}
+}
+
+func TestExtractAuthorsAndCategories(t *testing.T) {
+	const feedXML = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel><title>Feed</title>
+<item><title>tagged</title><link>http://example.com/1</link>
+  <dc:creator>Jane  Doe</dc:creator>
+  <category>Politics</category>
+  <category> politics </category>
+  <category>Economy</category>
+  <category></category>
+</item>
+<item><title>email author</title><link>http://example.com/2</link>
+  <author>desk@example.com (Newsroom)</author>
+</item>
+<item><title>bare</title><link>http://example.com/3</link></item>
+</channel></rss>`
+
+	feed, err := gofeed.NewParser().ParseString(feedXML)
+	if err != nil {
+		t.Fatalf("parse feed: %v", err)
+	}
+	collector := &Collector{}
+
+	tagged := collector.createArticle(feed, feed.Items[0])
+	if !reflect.DeepEqual([]string(tagged.Authors), []string{"Jane Doe"}) {
+		t.Errorf("authors = %q, want [Jane Doe]", tagged.Authors)
+	}
+	if !reflect.DeepEqual([]string(tagged.Categories), []string{"Politics", "Economy"}) {
+		t.Errorf("categories = %q, want [Politics Economy]", tagged.Categories)
+	}
+
+	if got := collector.createArticle(feed, feed.Items[1]).Authors; !reflect.DeepEqual([]string(got), []string{"Newsroom"}) {
+		t.Errorf("authors = %q, want [Newsroom]", got)
+	}
+
+	bare := collector.createArticle(feed, feed.Items[2])
+	if bare.Authors != nil || bare.Categories != nil {
+		t.Errorf("item without authors or categories got %q and %q", bare.Authors, bare.Categories)
+	}
+}
@@ -0,0 +1,164 @@
+// Package similarity ranks documents by textual similarity using bag-of-words vectors.
+package similarity
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Supported similarity methods
+const (
+	// MethodTFIDF weights terms by inverse document frequency over the compared documents
+	MethodTFIDF = "tfidf"
+	// MethodBagOfWords compares raw term counts
+	MethodBagOfWords = "bow"
+)
+
+// IsValidMethod reports whether method is a supported similarity method
+func IsValidMethod(method string) bool {
+	return method == MethodTFIDF || method == MethodBagOfWords
+}
+
+// minTokenLength drops short tokens, which are mostly noise in news text
+const minTokenLength = 3
+
+// stopWords are common English words that carry no topical signal
+var stopWords = map[string]struct{}{
+	"the": {}, "and": {}, "for": {}, "are": {}, "but": {}, "not": {}, "you": {}, "all": {},
+	"any": {}, "can": {}, "had": {}, "her": {}, "was": {}, "one": {}, "our": {}, "out": {},
+	"has": {}, "have": {}, "his": {}, "how": {}, "its": {}, "may": {}, "new": {}, "now": {},
+	"who": {}, "did": {}, "get": {}, "she": {}, "him": {}, "they": {}, "this": {}, "that": {},
+	"with": {}, "from": {}, "their": {}, "there": {}, "which": {}, "would": {}, "will": {},
+	"been": {}, "were": {}, "what": {}, "when": {}, "where": {}, "said": {}, "says": {},
+	"about": {}, "into": {}, "than": {}, "then": {}, "them": {}, "these": {}, "those": {},
+	"also": {}, "more": {}, "most": {}, "some": {}, "such": {}, "only": {}, "over": {},
+	"after": {}, "before": {}, "could": {}, "should": {}, "other": {}, "just": {}, "like": {},
+}
+
+// Tokenize lowercases text and splits it into words, dropping stop words and short tokens
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) < minTokenLength {
+			continue
+		}
+		if _, stop := stopWords[f]; stop {
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
+
+// Document is a piece of text identified by ID
+type Document struct {
+	ID   int64
+	Text string
+}
+
+// Match is a candidate document and its similarity to the target, in [0, 1]
+type Match struct {
+	ID         int64
+	Similarity float64
+}
+
+// Rank scores each candidate against target and returns the matches with a positive
+// similarity, most similar first. Candidates sharing the target's ID are skipped.
+func Rank(target Document, candidates []Document, method string) []Match {
+	termCounts := make([]map[string]float64, len(candidates))
+	for i, doc := range candidates {
+		termCounts[i] = countTerms(doc.Text)
+	}
+	targetCounts := countTerms(target.Text)
+
+	var idf map[string]float64
+	if method == MethodTFIDF {
+		idf = inverseDocumentFrequency(append(termCounts, targetCounts))
+	}
+	targetVec := weigh(targetCounts, idf)
+
+	matches := make([]Match, 0, len(candidates))
+	for i, doc := range candidates {
+		if doc.ID == target.ID {
+			continue
+		}
+		sim := Cosine(targetVec, weigh(termCounts[i], idf))
+		if sim > 0 {
+			matches = append(matches, Match{ID: doc.ID, Similarity: sim})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Similarity > matches[j].Similarity
+	})
+	return matches
+}
+
+// Cosine returns the cosine similarity of two sparse vectors
+func Cosine(a, b map[string]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var dot float64
+	for term, wa := range a {
+		dot += wa * b[term]
+	}
+	normA, normB := norm(a), norm(b)
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (normA * normB)
+}
+
+func countTerms(text string) map[string]float64 {
+	counts := make(map[string]float64)
+	for _, tok := range Tokenize(text) {
+		counts[tok]++
+	}
+	return counts
+}
+
+// inverseDocumentFrequency uses smoothed IDF so terms present in every document
+// still carry a small weight
+func inverseDocumentFrequency(docs []map[string]float64) map[string]float64 {
+	df := make(map[string]float64)
+	for _, doc := range docs {
+		for term := range doc {
+			df[term]++
+		}
+	}
+	n := float64(len(docs))
+	idf := make(map[string]float64, len(df))
+	for term, count := range df {
+		idf[term] = math.Log((1+n)/(1+count)) + 1
+	}
+	return idf
+}
+
+// weigh applies IDF weights to term counts; nil idf returns the counts unchanged
+func weigh(counts map[string]float64, idf map[string]float64) map[string]float64 {
+	if idf == nil {
+		return counts
+	}
+	vec := make(map[string]float64, len(counts))
+	for term, count := range counts {
+		vec[term] = count * idf[term]
+	}
+	return vec
+}
+
+func norm(v map[string]float64) float64 {
+	var sum float64
+	for _, w := range v {
+		sum += w * w
+	}
+	return math.Sqrt(sum)
+}
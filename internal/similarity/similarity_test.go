@@ -0,0 +1,68 @@
+package similarity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"senate", "passes", "budget", "2025"},
+		Tokenize("The Senate passes a budget, for 2025!"))
+	assert.Empty(t, Tokenize("a an to of"))
+}
+
+func TestCosine(t *testing.T) {
+	a := map[string]float64{"tax": 1, "vote": 2}
+	assert.InDelta(t, 1.0, Cosine(a, a), 1e-9)
+	assert.Equal(t, 0.0, Cosine(a, map[string]float64{"weather": 1}))
+	assert.Equal(t, 0.0, Cosine(a, nil))
+}
+
+func TestRank(t *testing.T) {
+	target := Document{ID: 1, Text: "Senate votes on climate bill and carbon tax"}
+	candidates := []Document{
+		target,
+		{ID: 2, Text: "Football season opens with record crowds"},
+		{ID: 3, Text: "Climate bill with carbon tax clears Senate vote"},
+		{ID: 4, Text: "Senate schedule announced"},
+	}
+
+	for _, method := range []string{MethodTFIDF, MethodBagOfWords} {
+		t.Run(method, func(t *testing.T) {
+			matches := Rank(target, candidates, method)
+			require.Len(t, matches, 2, "target and unrelated article are excluded")
+			assert.Equal(t, int64(3), matches[0].ID)
+			assert.Equal(t, int64(4), matches[1].ID)
+			assert.Greater(t, matches[0].Similarity, matches[1].Similarity)
+			assert.LessOrEqual(t, matches[0].Similarity, 1.0+1e-9)
+		})
+	}
+}
+
+func TestRankTFIDFDownweightsCommonTerms(t *testing.T) {
+	target := Document{ID: 1, Text: "report report report economy"}
+	candidates := []Document{
+		{ID: 2, Text: "report report report weather"},
+		{ID: 3, Text: "economy"},
+		{ID: 4, Text: "report sports"},
+		{ID: 5, Text: "report politics"},
+	}
+
+	bow := Rank(target, candidates, MethodBagOfWords)
+	tfidf := Rank(target, candidates, MethodTFIDF)
+	require.NotEmpty(t, bow)
+	require.NotEmpty(t, tfidf)
+	assert.Equal(t, int64(2), bow[0].ID, "raw counts favour the repeated common term")
+
+	scores := map[int64]float64{}
+	for _, m := range tfidf {
+		scores[m.ID] = m.Similarity
+	}
+	bowScores := map[int64]float64{}
+	for _, m := range bow {
+		bowScores[m.ID] = m.Similarity
+	}
+	assert.Greater(t, scores[3]-scores[2], bowScores[3]-bowScores[2], "tf-idf narrows the gap towards the rare shared term")
+}
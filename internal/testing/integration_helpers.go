@@ -13,7 +13,24 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
-// DBReset clears test-specific data from the database for a given set of article IDs.
+// articleResetQueries clear an article and every row derived from it, children first.
+// Tags themselves are shared between articles and are kept; only the article's links
+// to them are removed.
+var articleResetQueries = []string{
+	"DELETE FROM llm_scores WHERE article_id = ?",
+	"DELETE FROM score_history WHERE article_id = ?",
+	"DELETE FROM feedback WHERE article_id = ?",
+	"DELETE FROM article_tags WHERE article_id = ?",
+	"DELETE FROM articles WHERE id = ?",
+}
+
+// DBReset deletes the given articles in one transaction along with their rows in
+// llm_scores, score_history, feedback and article_tags, so nothing derived from a
+// test article outlives it. Either all of it is deleted or none of it.
+//
+// Left untouched: tags, source_stats (recomputed from the remaining articles), the
+// append-only audit_log, and reanalysis progress and score caches, which live in the
+// ScoreManager's memory rather than the database.
 func DBReset(db *sqlx.DB, testArticleIDs []int64) error {
 	if len(testArticleIDs) == 0 {
 		return nil // Nothing to do
@@ -25,20 +42,11 @@ func DBReset(db *sqlx.DB, testArticleIDs []int64) error {
 	}
 	defer func() {
 		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone && err != sql.ErrConnDone {
-			log.Printf("Error rolling back transaction in DBResetForTestIDs: %v", err)
+			log.Printf("Error rolling back transaction in DBReset: %v", err)
 		}
 	}()
 
-	// Prepare queries - using IN clause for efficiency if supported and not too many IDs.
-	// For simplicity with variable number of IDs and to ensure order, loop and delete.
-
-	queries := []string{
-		"DELETE FROM llm_scores WHERE article_id = ?",
-		"DELETE FROM feedback WHERE article_id = ?",
-		"DELETE FROM articles WHERE id = ?",
-	}
-
-	for _, queryTemplate := range queries {
+	for _, queryTemplate := range articleResetQueries {
 		for _, articleID := range testArticleIDs {
 			_, err := tx.Exec(queryTemplate, articleID)
 			if err != nil {
@@ -53,6 +61,83 @@ func DBReset(db *sqlx.DB, testArticleIDs []int64) error {
 	return nil
 }
 
+// resetAllTables are the tables DBResetAll empties, children before their parents
+var resetAllTables = []string{
+	"label_reviews",
+	"labels",
+	"validation_runs",
+	"article_tags",
+	"tags",
+	"llm_scores",
+	"score_history",
+	"feedback",
+	"articles",
+	"source_stats",
+	"sources",
+}
+
+// resetAllQueries are the predefined statements for resetAllTables, so no SQL is built
+// from table names
+var resetAllQueries = map[string]string{
+	"label_reviews":   `DELETE FROM "label_reviews"`,
+	"labels":          `DELETE FROM "labels"`,
+	"validation_runs": `DELETE FROM "validation_runs"`,
+	"article_tags":    `DELETE FROM "article_tags"`,
+	"tags":            `DELETE FROM "tags"`,
+	"llm_scores":      `DELETE FROM "llm_scores"`,
+	"score_history":   `DELETE FROM "score_history"`,
+	"feedback":        `DELETE FROM "feedback"`,
+	"articles":        `DELETE FROM "articles"`,
+	"source_stats":    `DELETE FROM "source_stats"`,
+	"sources":         `DELETE FROM "sources"`,
+}
+
+// DBResetAll empties every table InitDB creates except the append-only audit_log, in
+// one transaction, and restarts their AUTOINCREMENT counters so IDs begin at 1 again.
+// That covers articles with everything derived from them as in DBReset, plus tags,
+// sources and source_stats, labels and label_reviews, and validation_runs. Tables
+// missing from the database are skipped.
+func DBResetAll(db *sqlx.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("DBResetAll: failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone && err != sql.ErrConnDone {
+			log.Printf("Error rolling back transaction in DBResetAll: %v", err)
+		}
+	}()
+
+	var existing []string
+	if err := tx.Select(&existing, "SELECT name FROM sqlite_master WHERE type = 'table'"); err != nil {
+		return fmt.Errorf("DBResetAll: failed to list tables: %w", err)
+	}
+	present := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		present[name] = true
+	}
+
+	for _, table := range resetAllTables {
+		if !present[table] {
+			continue
+		}
+		if _, err := tx.Exec(resetAllQueries[table]); err != nil {
+			return fmt.Errorf("DBResetAll: failed to clear %s: %w", table, err)
+		}
+		// sqlite_sequence only exists once an AUTOINCREMENT table has been written to
+		if present["sqlite_sequence"] {
+			if _, err := tx.Exec("DELETE FROM sqlite_sequence WHERE name = ?", table); err != nil {
+				return fmt.Errorf("DBResetAll: failed to reset the ID sequence of %s: %w", table, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("DBResetAll: failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // InsertTestArticle inserts a basic article for testing and returns its ID.
 func InsertTestArticle(db *sqlx.DB, title, content string) (int64, error) {
 	dummyURL := fmt.Sprintf("http://test.example.com/article/%d", time.Now().UnixNano())
@@ -163,3 +248,90 @@ func SeedLLMScoresForSuccessfulScore(db *sqlx.DB, articleID int64) error {
 	}
 	return nil
 }
+
+// seededScore is one model score inserted by a seed helper
+type seededScore struct {
+	model      string
+	score      float64
+	confidence float64
+}
+
+// seedScores inserts the given model scores for an article, naming the calling helper
+// in errors
+func seedScores(db *sqlx.DB, helper string, articleID int64, scores []seededScore) error {
+	for _, s := range scores {
+		scoreRecord := &appdb.LLMScore{
+			ArticleID: articleID,
+			Model:     s.model,
+			Score:     s.score,
+			Metadata:  fmt.Sprintf(`{"confidence": %.2f}`, s.confidence),
+			Version:   1,
+			CreatedAt: time.Now(),
+		}
+		if _, err := appdb.InsertLLMScore(db, scoreRecord); err != nil {
+			return fmt.Errorf("%s: failed to insert score for model %s: %w", helper, s.model, err)
+		}
+	}
+	return nil
+}
+
+// SeededComposite is the composite score and confidence a seed helper's scores lead to
+// under the default composite score config: the average of the valid model scores, with
+// the mean of their confidences
+type SeededComposite struct {
+	Score      float64
+	Confidence float64
+}
+
+// Composite outcomes of the seed helpers
+var (
+	SuccessfulScoreComposite = SeededComposite{Score: 0.2 / 3, Confidence: 0.89}
+	LowConfidenceComposite   = SeededComposite{Score: 0.1, Confidence: 0.25}
+	DegradedComposite        = SeededComposite{Score: 0.4, Confidence: 0.875}
+	DisagreementComposite    = SeededComposite{Score: 0.0, Confidence: 0.9}
+)
+
+// ManualOverrideScore is the score SeedLLMScoresForManualOverride pins an article to; the
+// model composite underneath it is SuccessfulScoreComposite
+const ManualOverrideScore = -0.35
+
+// SeedLLMScoresForLowConfidence seeds scores from all three perspectives whose models
+// each report low confidence (0.2 to 0.3), for testing low_confidence uncertainty
+func SeedLLMScoresForLowConfidence(db *sqlx.DB, articleID int64) error {
+	return seedScores(db, "SeedLLMScoresForLowConfidence", articleID, []seededScore{
+		{"meta-llama/llama-4-maverick", 0.0, 0.2},  // Left
+		{"google/gemini-2.0-flash-001", 0.1, 0.25}, // Center
+		{"openai/gpt-4.1-nano", 0.2, 0.3},          // Right
+	})
+}
+
+// SeedLLMScoresForDegraded seeds confident scores for the center and right models only,
+// as when the left model failed, for testing scoring with missing models
+func SeedLLMScoresForDegraded(db *sqlx.DB, articleID int64) error {
+	return seedScores(db, "SeedLLMScoresForDegraded", articleID, []seededScore{
+		{"google/gemini-2.0-flash-001", 0.2, 0.85}, // Center
+		{"openai/gpt-4.1-nano", 0.6, 0.9},          // Right
+	})
+}
+
+// SeedLLMScoresForDisagreement seeds confident scores that split left and right around
+// a neutral center, for testing model_disagreement uncertainty
+func SeedLLMScoresForDisagreement(db *sqlx.DB, articleID int64) error {
+	return seedScores(db, "SeedLLMScoresForDisagreement", articleID, []seededScore{
+		{"meta-llama/llama-4-maverick", -0.8, 0.9}, // Left
+		{"google/gemini-2.0-flash-001", 0.0, 0.9},  // Center
+		{"openai/gpt-4.1-nano", 0.8, 0.9},          // Right
+	})
+}
+
+// SeedLLMScoresForManualOverride seeds the scores of SeedLLMScoresForSuccessfulScore
+// and pins the article to a manual score, for testing that overrides win over models
+func SeedLLMScoresForManualOverride(db *sqlx.DB, articleID int64) error {
+	if err := SeedLLMScoresForSuccessfulScore(db, articleID); err != nil {
+		return fmt.Errorf("SeedLLMScoresForManualOverride: %w", err)
+	}
+	if err := appdb.SetManualScore(db, articleID, ManualOverrideScore, "seeded manual override"); err != nil {
+		return fmt.Errorf("SeedLLMScoresForManualOverride: failed to set manual score: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package testing
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	appdb "github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedArticleWithDerivedRows inserts an article with a score, score version, feedback
+// and tag
+func seedArticleWithDerivedRows(t *testing.T, db *sqlx.DB, title string) int64 {
+	t.Helper()
+	id, err := InsertTestArticle(db, title, "Body of "+title)
+	require.NoError(t, err)
+	_, err = appdb.InsertLLMScore(db, &appdb.LLMScore{ArticleID: id, Model: "m", Score: 0.2, Metadata: "{}", CreatedAt: time.Now()})
+	require.NoError(t, err)
+	_, err = appdb.RecordScoreVersion(db, id, 0.2, 0.8, "analysis")
+	require.NoError(t, err)
+	require.NoError(t, appdb.InsertFeedback(db, &appdb.Feedback{ArticleID: id, UserID: "u", FeedbackText: "ok", CreatedAt: time.Now()}))
+	require.NoError(t, appdb.AddArticleTags(db, id, []string{"politics"}))
+	return id
+}
+
+func countRows(t *testing.T, db *sqlx.DB, query string, args ...interface{}) int {
+	t.Helper()
+	var n int
+	require.NoError(t, db.Get(&n, query, args...))
+	return n
+}
+
+func TestDBReset(t *testing.T) {
+	db, err := appdb.InitDB(filepath.Join(t.TempDir(), "reset.db"))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	reset := seedArticleWithDerivedRows(t, db, "Reset me")
+	kept := seedArticleWithDerivedRows(t, db, "Keep me")
+
+	require.NoError(t, DBReset(db, []int64{reset}))
+	for _, table := range []string{"llm_scores", "score_history", "feedback", "article_tags"} {
+		assert.Zero(t, countRows(t, db, "SELECT COUNT(*) FROM "+table+" WHERE article_id = ?", reset), table)
+		assert.Equal(t, 1, countRows(t, db, "SELECT COUNT(*) FROM "+table+" WHERE article_id = ?", kept), table)
+	}
+	assert.Zero(t, countRows(t, db, "SELECT COUNT(*) FROM articles WHERE id = ?", reset))
+	assert.Equal(t, 1, countRows(t, db, "SELECT COUNT(*) FROM tags"), "shared tags are kept")
+
+	require.NoError(t, DBResetAll(db))
+	for _, table := range resetAllTables {
+		assert.Zero(t, countRows(t, db, "SELECT COUNT(*) FROM "+table), table)
+	}
+	id, err := InsertTestArticle(db, "After reset", "Body")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id, "IDs restart after DBResetAll")
+}
+
+func TestSeedLLMScoresOutcomes(t *testing.T) {
+	db, err := appdb.InitDB(filepath.Join(t.TempDir(), "seed.db"))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "configs", "composite_score_config.json"))
+	require.NoError(t, err)
+	var cfg llm.CompositeScoreConfig
+	require.NoError(t, json.Unmarshal(data, &cfg))
+
+	for name, tc := range map[string]struct {
+		seed func(*sqlx.DB, int64) error
+		want SeededComposite
+	}{
+		"Successful":     {SeedLLMScoresForSuccessfulScore, SuccessfulScoreComposite},
+		"LowConfidence":  {SeedLLMScoresForLowConfidence, LowConfidenceComposite},
+		"Degraded":       {SeedLLMScoresForDegraded, DegradedComposite},
+		"Disagreement":   {SeedLLMScoresForDisagreement, DisagreementComposite},
+		"ManualOverride": {SeedLLMScoresForManualOverride, SuccessfulScoreComposite},
+	} {
+		t.Run(name, func(t *testing.T) {
+			id, err := InsertTestArticle(db, name, "Body")
+			require.NoError(t, err)
+			require.NoError(t, tc.seed(db, id))
+
+			scores, err := appdb.FetchLLMScores(db, id)
+			require.NoError(t, err)
+			score, confidence, err := llm.ComputeCompositeScoreWithConfidence(scores, &cfg)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.want.Score, score, 1e-9)
+			assert.InDelta(t, tc.want.Confidence, confidence, 1e-9)
+		})
+	}
+
+	t.Run("ManualOverridePinsArticle", func(t *testing.T) {
+		id, err := InsertTestArticle(db, "Pinned", "Body")
+		require.NoError(t, err)
+		require.NoError(t, SeedLLMScoresForManualOverride(db, id))
+		article, err := appdb.FetchArticleByID(db, id)
+		require.NoError(t, err)
+		require.NotNil(t, article.CompositeScore)
+		assert.Equal(t, ManualOverrideScore, *article.CompositeScore)
+		require.NotNil(t, article.ScoreSource)
+		assert.Equal(t, appdb.ScoreSourceManual, *article.ScoreSource)
+	})
+}
@@ -0,0 +1,149 @@
+// Package testserver runs the full API server in-process against an in-memory SQLite
+// database and a fixture LLM service, so end-to-end tests of scoring, reanalysis and
+// progress streaming need no external processes or provider calls.
+//
+// Reanalysis loads configs/composite_score_config.json, so tests using the server
+// must run from the project root.
+package testserver
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/api"
+	client "github.com/alexandru-savinov/BalancedNewsGo/internal/api/wrapper"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/db"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	internaltesting "github.com/alexandru-savinov/BalancedNewsGo/internal/testing"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// shutdownTimeout bounds how long teardown waits for running scoring jobs
+const shutdownTimeout = 5 * time.Second
+
+// DefaultFixture answers every LLM call with a neutral, confident score
+var DefaultFixture = llm.Fixture{
+	Default: llm.FixtureResponse{Score: 0, Confidence: 0.9, Explanation: "fixture response"},
+}
+
+// dbCounter names each server's in-memory database so parallel servers do not share one
+var dbCounter atomic.Int64
+
+// Options configures a test server. The zero value uses DefaultFixture and the
+// composite score config from configs/.
+type Options struct {
+	Fixture *llm.Fixture
+	Config  *llm.CompositeScoreConfig
+	// ClientOptions are applied to the wrapper client after the test defaults of no
+	// retries and no caching
+	ClientOptions []client.ConfigOption
+}
+
+// Server is a running in-process API server
+type Server struct {
+	URL          string
+	DB           *sqlx.DB
+	Client       *client.APIClient
+	LLM          *llm.FixtureLLMService
+	LLMClient    *llm.LLMClient
+	ScoreManager *llm.ScoreManager
+
+	mu         sync.Mutex
+	articleIDs []int64
+}
+
+// nopCollector stands in for the RSS collector; the test server fetches no feeds
+type nopCollector struct{}
+
+func (nopCollector) ManualRefresh()                   {}
+func (nopCollector) CheckFeedHealth() map[string]bool { return map[string]bool{} }
+
+// Start starts a server and returns it with its teardown func, which waits for
+// running scoring jobs before closing the server and database. Setup failures end the
+// test.
+func Start(t testing.TB, opts Options) (*Server, func()) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	dsn := fmt.Sprintf("file:testserver%d?mode=memory&cache=shared", dbCounter.Add(1))
+	dbConn, err := db.InitDB(dsn)
+	if err != nil {
+		t.Fatalf("testserver: failed to initialize database: %v", err)
+	}
+
+	config := opts.Config
+	if config == nil {
+		if config, err = llm.LoadCompositeScoreConfig(); err != nil {
+			_ = dbConn.Close()
+			t.Fatalf("testserver: %v", err)
+		}
+	}
+	fixture := DefaultFixture
+	if opts.Fixture != nil {
+		fixture = *opts.Fixture
+	}
+	service := llm.NewFixtureLLMService(fixture)
+	llmClient := llm.NewLLMClientWithService(dbConn, service, config)
+
+	progressManager := llm.NewProgressManager(time.Minute)
+	scoreManager := llm.NewScoreManager(dbConn, llm.NewCache(), &llm.DefaultScoreCalculator{}, progressManager)
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	api.RegisterRoutes(router, dbConn, nopCollector{}, llmClient, scoreManager, progressManager, nil, nil, nil)
+	httpServer := httptest.NewServer(router)
+
+	clientOptions := append([]client.ConfigOption{
+		client.WithRetryConfig(0, time.Millisecond),
+		client.WithCacheTTL(0),
+	}, opts.ClientOptions...)
+
+	s := &Server{
+		URL:          httpServer.URL,
+		DB:           dbConn,
+		Client:       client.NewAPIClient(httpServer.URL, clientOptions...),
+		LLM:          service,
+		LLMClient:    llmClient,
+		ScoreManager: scoreManager,
+	}
+
+	teardown := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		scoreManager.Shutdown(ctx)
+		httpServer.Close()
+		progressManager.Stop()
+		if err := dbConn.Close(); err != nil {
+			t.Logf("testserver: failed to close database: %v", err)
+		}
+	}
+	return s, teardown
+}
+
+// InsertArticle stores a test article, remembering it for Reset
+func (s *Server) InsertArticle(title, content string) (int64, error) {
+	id, err := internaltesting.InsertTestArticle(s.DB, title, content)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	s.articleIDs = append(s.articleIDs, id)
+	s.mu.Unlock()
+	return id, nil
+}
+
+// Reset deletes the articles added with InsertArticle along with their scores and
+// feedback, so one server can be shared by several subtests
+func (s *Server) Reset() error {
+	s.mu.Lock()
+	ids := s.articleIDs
+	s.articleIDs = nil
+	s.mu.Unlock()
+	return internaltesting.DBReset(s.DB, ids)
+}
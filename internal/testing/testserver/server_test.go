@@ -0,0 +1,131 @@
+package testserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	client "github.com/alexandru-savinov/BalancedNewsGo/internal/api/wrapper"
+	"github.com/alexandru-savinov/BalancedNewsGo/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	// Reanalysis loads the composite score config relative to the project root
+	if err := os.Chdir(filepath.Join("..", "..", "..")); err != nil {
+		log.Fatalf("failed to change to project root: %v", err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestServerReanalysisEndToEnd(t *testing.T) {
+	fixture := llm.Fixture{Default: llm.FixtureResponse{Score: 0.4, Confidence: 0.8, Explanation: "leans right"}}
+	server, teardown := Start(t, Options{Fixture: &fixture})
+	defer teardown()
+
+	articleID, err := server.InsertArticle("Harness article", "Some article text for the fixture to score.")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	job, err := server.Client.Reanalyze(ctx, articleID)
+	require.NoError(t, err)
+
+	events, err := server.Client.WatchProgress(ctx, job.JobID)
+	require.NoError(t, err)
+	var last client.Progress
+	for event := range events {
+		last = event
+	}
+	require.True(t, last.Done(), "stream ended on %+v", last)
+	assert.Empty(t, last.Error)
+	require.NotNil(t, last.FinalScore)
+	assert.Positive(t, server.LLM.Calls())
+
+	assert.InDelta(t, 0.4, *last.FinalScore, 1e-9)
+	var stored float64
+	require.NoError(t, server.DB.Get(&stored, "SELECT composite_score FROM articles WHERE id = ?", articleID))
+	assert.InDelta(t, 0.4, stored, 1e-9)
+
+	require.NoError(t, server.Reset())
+	var count int
+	require.NoError(t, server.DB.Get(&count, "SELECT COUNT(*) FROM llm_scores WHERE article_id = ?", articleID))
+	assert.Zero(t, count)
+}
+
+func TestServersAreIsolated(t *testing.T) {
+	first, teardownFirst := Start(t, Options{})
+	defer teardownFirst()
+	second, teardownSecond := Start(t, Options{})
+	defer teardownSecond()
+
+	_, err := first.InsertArticle("Only in the first", "Body")
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, second.DB.Get(&count, "SELECT COUNT(*) FROM articles"))
+	assert.Zero(t, count)
+}
+
+func TestConcurrentReanalysisOfOneArticle(t *testing.T) {
+	server, teardown := Start(t, Options{})
+	defer teardown()
+
+	articleID, err := server.InsertArticle("Contended article", "Text that several clients ask to reanalyse at once.")
+	require.NoError(t, err)
+
+	// Hold the only job slot so the accepted reanalysis stays queued while the others arrive
+	server.ScoreManager.SetMaxConcurrentJobs(1)
+	releaseSlot, err := server.ScoreManager.AcquireJobSlot(context.Background(), 0)
+	require.NoError(t, err)
+
+	reanalyze := func() (int, map[string]interface{}) {
+		url := fmt.Sprintf("%s/api/llm/reanalyze/%d", server.URL, articleID)
+		resp, err := http.Post(url, "application/json", strings.NewReader("{}"))
+		if !assert.NoError(t, err) {
+			return 0, nil
+		}
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body
+	}
+
+	const clients = 8
+	codes := make(chan int, clients)
+	var wg sync.WaitGroup
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, body := reanalyze()
+			if code == http.StatusConflict {
+				details := body["error"].(map[string]interface{})["details"].(map[string]interface{})
+				assert.Equal(t, fmt.Sprintf("/api/llm/score-progress/%d", articleID), details["progress_url"])
+			}
+			codes <- code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+	counts := map[int]int{}
+	for code := range codes {
+		counts[code]++
+	}
+	assert.Equal(t, map[int]int{http.StatusOK: 1, http.StatusConflict: clients - 1}, counts)
+
+	// Once the accepted job has finished the article can be reanalysed again
+	releaseSlot()
+	require.Eventually(t, func() bool { return !server.ScoreManager.HasJob(articleID) }, 30*time.Second, 10*time.Millisecond)
+	code, _ := reanalyze()
+	assert.Equal(t, http.StatusOK, code)
+	require.Eventually(t, func() bool { return !server.ScoreManager.HasJob(articleID) }, 30*time.Second, 10*time.Millisecond)
+}
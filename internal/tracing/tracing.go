@@ -0,0 +1,114 @@
+// Package tracing sets up OpenTelemetry tracing. Tracing is a no-op unless an OTLP
+// endpoint is configured through the standard OTEL_EXPORTER_OTLP_* variables.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is reported as service.name unless OTEL_SERVICE_NAME is set
+const ServiceName = "newsbalancer"
+
+// instrumentationName identifies the spans created by this application
+const instrumentationName = "github.com/alexandru-savinov/BalancedNewsGo"
+
+// Span attribute keys shared across the application
+const (
+	AttrArticleID = attribute.Key("article_id")
+	AttrModel     = attribute.Key("model")
+	AttrStatus    = attribute.Key("status")
+)
+
+// Span status values recorded under AttrStatus
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Enabled reports whether an OTLP endpoint is configured
+func Enabled() bool {
+	if os.Getenv("OTEL_SDK_DISABLED") == "true" {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Init installs the global tracer provider. Without an OTLP endpoint it leaves the
+// no-op provider in place. The returned function flushes and stops the exporter.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// The exporter reads the endpoint, headers and protocol options from OTEL_EXPORTER_OTLP_*
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = ServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	log.Printf("[INFO] OpenTelemetry tracing enabled for service %s", serviceName)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the application tracer from the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span as a child of any span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the outcome of the operation on the span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(AttrStatus.String(StatusError))
+	} else {
+		span.SetAttributes(AttrStatus.String(StatusOK))
+	}
+	span.End()
+}
+
+// ArticleID returns the article_id span attribute
+func ArticleID(id int64) attribute.KeyValue {
+	return AttrArticleID.Int64(id)
+}
+
+// Model returns the model span attribute
+func Model(name string) attribute.KeyValue {
+	return AttrModel.String(name)
+}
@@ -0,0 +1,2 @@
+-- Feed image shown on article cards
+ALTER TABLE articles ADD COLUMN image_url TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE articles DROP COLUMN categories;
+ALTER TABLE articles DROP COLUMN authors;
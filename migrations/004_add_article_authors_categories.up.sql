@@ -0,0 +1,3 @@
+-- Feed authors and categories, each a JSON array of strings
+ALTER TABLE articles ADD COLUMN authors TEXT;
+ALTER TABLE articles ADD COLUMN categories TEXT;
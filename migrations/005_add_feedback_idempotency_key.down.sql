@@ -0,0 +1,2 @@
+DROP INDEX idx_feedback_idempotency_key;
+ALTER TABLE feedback DROP COLUMN idempotency_key;
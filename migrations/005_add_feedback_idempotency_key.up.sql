@@ -0,0 +1,3 @@
+-- Client-supplied key that keeps a resent feedback item from being stored twice
+ALTER TABLE feedback ADD COLUMN idempotency_key TEXT;
+CREATE UNIQUE INDEX idx_feedback_idempotency_key ON feedback(idempotency_key);
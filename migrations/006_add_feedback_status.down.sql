@@ -0,0 +1 @@
+ALTER TABLE feedback DROP COLUMN status;
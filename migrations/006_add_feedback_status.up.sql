@@ -0,0 +1,2 @@
+-- Moderation status of feedback: new, reviewed or spam
+ALTER TABLE feedback ADD COLUMN status TEXT NOT NULL DEFAULT 'new';
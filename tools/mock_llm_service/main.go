@@ -2,9 +2,14 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
+	"math"
+	"math/rand"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 )
 
@@ -14,49 +19,201 @@ const (
 )
 
 type AnalysisResult struct {
-	Score float64 `json:"score"`
-	Label string  `json:"label"`
+	Score      float64 `json:"score"`
+	Label      string  `json:"label"`
+	Confidence float64 `json:"confidence"`
 }
 
-func analyzeHandler(label string, score float64) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		result := AnalysisResult{
-			Score: score,
-			Label: label,
-		}
+// mockConfig controls the mock responses. It can be loaded from a JSON file with
+// -config; command-line flags override values from the file.
+type mockConfig struct {
+	Score       *float64 `json:"score,omitempty"` // defaults to the label's score
+	Confidence  float64  `json:"confidence"`      // confidence returned with every score
+	LatencyMs   int      `json:"latency_ms"`      // delay before each response
+	Jitter      float64  `json:"jitter"`          // scores vary uniformly by up to +/- jitter
+	ErrorRate   float64  `json:"error_rate"`      // fraction of requests that fail
+	ErrorStatus int      `json:"error_status"`    // HTTP status of injected failures
+	Seed        int64    `json:"seed,omitempty"`  // seeds jitter and error injection; 0 uses the clock
+	Explanation string   `json:"explanation"`     // explanation in chat completion responses
+	Model       string   `json:"model,omitempty"` // reported model name
+	Label       string   `json:"-"`               // set from the command line
+}
 
-		w.Header().Set("Content-Type", "application/json")
+// mockService answers requests according to its config
+type mockService struct {
+	cfg   mockConfig
+	score float64
 
-		if err := json.NewEncoder(w).Encode(result); err != nil {
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		}
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// next returns the score for this request, or an injected failure
+func (s *mockService) next() (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cfg.ErrorRate > 0 && s.rng.Float64() < s.cfg.ErrorRate {
+		return 0, false
 	}
+	score := s.score
+	if s.cfg.Jitter > 0 {
+		score += (s.rng.Float64()*2 - 1) * s.cfg.Jitter
+	}
+	return math.Max(-1, math.Min(1, score)), true
 }
 
-func main() {
-	if len(os.Args) < 3 {
-		log.Fatal("Usage: mock_llm_service <label> <port>")
+// respond applies latency and error injection, then writes the body built from the score
+func (s *mockService) respond(w http.ResponseWriter, body func(score float64) interface{}) {
+	if s.cfg.LatencyMs > 0 {
+		time.Sleep(time.Duration(s.cfg.LatencyMs) * time.Millisecond)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	score, ok := s.next()
+	if !ok {
+		if s.cfg.ErrorStatus == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		w.WriteHeader(s.cfg.ErrorStatus)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]interface{}{
+				"message": http.StatusText(s.cfg.ErrorStatus),
+				"code":    s.cfg.ErrorStatus,
+			},
+		})
+		return
 	}
 
-	label := os.Args[1]
-	port := os.Args[2]
+	if err := json.NewEncoder(w).Encode(body(score)); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// analyzeHandler serves the simple {score,label,confidence} format
+func (s *mockService) analyzeHandler(w http.ResponseWriter, r *http.Request) {
+	s.respond(w, func(score float64) interface{} {
+		return AnalysisResult{
+			Score:      score,
+			Label:      s.cfg.Label,
+			Confidence: s.cfg.Confidence,
+		}
+	})
+}
 
-	var score float64
+// chatCompletionsHandler serves the OpenAI/OpenRouter chat completions format, so the
+// server can use the mock via LLM_BASE_URL
+func (s *mockService) chatCompletionsHandler(w http.ResponseWriter, r *http.Request) {
+	s.respond(w, func(score float64) interface{} {
+		content, _ := json.Marshal(map[string]interface{}{
+			"score":       score,
+			"explanation": s.cfg.Explanation,
+			"confidence":  s.cfg.Confidence,
+		})
+		return map[string]interface{}{
+			"model": s.cfg.Model,
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": string(content)}},
+			},
+		}
+	})
+}
 
+func labelScore(label string) float64 {
 	switch label {
 	case LabelLeft:
-		score = -1.0
+		return -1.0
 	case "center":
-		score = 0.0
+		return 0.0
 	case LabelRight:
-		score = 1.0
+		return 1.0
 	default:
-		score = 0.0
+		return 0.0
+	}
+}
+
+func loadConfig(path string) (mockConfig, error) {
+	cfg := mockConfig{Confidence: 0.9, ErrorStatus: http.StatusInternalServerError, Explanation: "Mock analysis", Model: "mock"}
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 - path is supplied by the developer running the mock
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func main() {
+	configPath := flag.String("config", "", "JSON config file (flags override its values)")
+	score := flag.Float64("score", 0, "score to return (defaults to the label's score)")
+	confidence := flag.Float64("confidence", 0, "confidence to return (default 0.9)")
+	latency := flag.Duration("latency", 0, "delay before each response, e.g. 200ms")
+	jitter := flag.Float64("jitter", 0, "vary each score uniformly by up to +/- this amount")
+	errorRate := flag.Float64("error-rate", 0, "fraction of requests that fail (0-1)")
+	errorStatus := flag.Int("error-status", 0, "HTTP status for injected failures (default 500; 429 adds Retry-After)")
+	seed := flag.Int64("seed", 0, "random seed for jitter and error injection (default: clock)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: mock_llm_service [flags] <label> <port>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	label := flag.Arg(0)
+	port := flag.Arg(1)
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.Label = label
+
+	// Flags that were set explicitly override the config file
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "score":
+			cfg.Score = score
+		case "confidence":
+			cfg.Confidence = *confidence
+		case "latency":
+			cfg.LatencyMs = int(latency.Milliseconds())
+		case "jitter":
+			cfg.Jitter = *jitter
+		case "error-rate":
+			cfg.ErrorRate = *errorRate
+		case "error-status":
+			cfg.ErrorStatus = *errorStatus
+		case "seed":
+			cfg.Seed = *seed
+		}
+	})
+
+	if cfg.ErrorStatus < 400 {
+		cfg.ErrorStatus = http.StatusInternalServerError
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+
+	svc := &mockService{cfg: cfg, score: labelScore(label)}
+	if cfg.Score != nil {
+		svc.score = *cfg.Score
 	}
+	svc.rng = rand.New(rand.NewSource(cfg.Seed)) // #nosec G404 - test traffic variation, not security
 
-	http.HandleFunc("/analyze", analyzeHandler(label, score))
+	http.HandleFunc("/analyze", svc.analyzeHandler)
+	http.HandleFunc("/chat/completions", svc.chatCompletionsHandler)
+	http.HandleFunc("/api/v1/chat/completions", svc.chatCompletionsHandler)
 
-	log.Printf("Starting mock LLM service for %s on port %s...", label, port)
+	log.Printf("Starting mock LLM service for %s on port %s (score=%.2f confidence=%.2f jitter=%.2f latency=%dms error_rate=%.2f)...",
+		label, port, svc.score, cfg.Confidence, cfg.Jitter, cfg.LatencyMs, cfg.ErrorRate)
 
 	// Create HTTP server with security timeouts
 	srv := &http.Server{
@@ -68,7 +225,7 @@ func main() {
 		IdleTimeout:       120 * time.Second, // Maximum time for idle connections
 	}
 
-	err := srv.ListenAndServe()
+	err = srv.ListenAndServe()
 	if err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}